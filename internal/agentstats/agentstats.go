@@ -0,0 +1,225 @@
+// Package agentstats records finished subagent runs and aggregates them
+// into per-agent scorecards for `moai agents stats`.
+//
+// The SubagentStop hook appends one Run per finished subagent to
+// .moai/logs/agent-runs.jsonl. Task tool metrics written by the PostToolUse
+// hook to .moai/logs/task-metrics.jsonl are summarized alongside.
+package agentstats
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/defs"
+)
+
+// Claim outcomes.
+const (
+	ClaimVerified   = "verified"
+	ClaimFailed     = "failed"
+	ClaimUnverified = "unverified"
+)
+
+// Claim is an outcome the subagent reported, such as "tests pass", and
+// the result of re-running the check behind it.
+type Claim struct {
+	Kind   string `json:"kind"` // tests, build, lint
+	Check  string `json:"check,omitempty"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Run is one finished subagent run.
+type Run struct {
+	Timestamp       time.Time `json:"timestamp"`
+	SessionID       string    `json:"session_id,omitempty"`
+	AgentID         string    `json:"agent_id"`
+	Agent           string    `json:"agent"`
+	DurationSeconds float64   `json:"duration_seconds,omitempty"`
+	Tokens          int64     `json:"tokens,omitempty"`
+	ToolUses        int       `json:"tool_uses,omitempty"`
+	FilesChanged    []string  `json:"files_changed,omitempty"`
+	Claims          []Claim   `json:"claims,omitempty"`
+	// Success is false when a claimed check failed.
+	Success bool `json:"success"`
+	// Reworks counts how often the run was sent back because a claim
+	// failed verification.
+	Reworks int `json:"reworks,omitempty"`
+}
+
+// RunsPath is the log of finished subagent runs of a project.
+func RunsPath(projectRoot string) string {
+	return filepath.Join(projectRoot, defs.MoAIDir, defs.LogsSubdir, "agent-runs.jsonl")
+}
+
+// TaskMetricsPath is the Task tool metrics log of a project.
+func TaskMetricsPath(projectRoot string) string {
+	return filepath.Join(projectRoot, defs.MoAIDir, defs.LogsSubdir, "task-metrics.jsonl")
+}
+
+// Append adds a run to the project's run log.
+func Append(projectRoot string, run Run) error {
+	path := RunsPath(projectRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create log directory: %w", err)
+	}
+	line, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("marshal agent run: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open agent run log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write agent run: %w", err)
+	}
+	return nil
+}
+
+// ReadRuns returns the runs logged at or after since. A missing log holds
+// no runs; malformed lines are skipped.
+func ReadRuns(projectRoot string, since time.Time) ([]Run, error) {
+	var runs []Run
+	err := readJSONL(RunsPath(projectRoot), func(data []byte) {
+		var r Run
+		if json.Unmarshal(data, &r) == nil && !r.Timestamp.Before(since) {
+			runs = append(runs, r)
+		}
+	})
+	return runs, err
+}
+
+// Scorecard aggregates the runs of one agent type.
+type Scorecard struct {
+	Agent           string    `json:"agent"`
+	Runs            int       `json:"runs"`
+	Successes       int       `json:"successes"`
+	Reworks         int       `json:"reworks"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Tokens          int64     `json:"tokens"`
+	FilesChanged    int       `json:"files_changed"`
+	LastRun         time.Time `json:"last_run"`
+}
+
+// SuccessRate is the share of successful runs in [0, 1].
+func (s Scorecard) SuccessRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Successes) / float64(s.Runs)
+}
+
+// AvgDuration is the mean run duration.
+func (s Scorecard) AvgDuration() time.Duration {
+	if s.Runs == 0 {
+		return 0
+	}
+	return time.Duration(s.DurationSeconds / float64(s.Runs) * float64(time.Second))
+}
+
+// AvgTokens is the mean number of tokens per run.
+func (s Scorecard) AvgTokens() int64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return s.Tokens / int64(s.Runs)
+}
+
+// Scorecards groups runs by agent type, busiest agents first.
+func Scorecards(runs []Run) []Scorecard {
+	byAgent := map[string]*Scorecard{}
+	for _, r := range runs {
+		agent := cmp.Or(r.Agent, "unknown")
+		s := byAgent[agent]
+		if s == nil {
+			s = &Scorecard{Agent: agent}
+			byAgent[agent] = s
+		}
+		s.Runs++
+		if r.Success {
+			s.Successes++
+		}
+		s.Reworks += r.Reworks
+		s.DurationSeconds += r.DurationSeconds
+		s.Tokens += r.Tokens
+		s.FilesChanged += len(r.FilesChanged)
+		if r.Timestamp.After(s.LastRun) {
+			s.LastRun = r.Timestamp
+		}
+	}
+
+	cards := make([]Scorecard, 0, len(byAgent))
+	for _, s := range byAgent {
+		cards = append(cards, *s)
+	}
+	slices.SortFunc(cards, func(a, b Scorecard) int {
+		return cmp.Or(cmp.Compare(b.Runs, a.Runs), cmp.Compare(a.Agent, b.Agent))
+	})
+	return cards
+}
+
+// TaskTotals summarizes the Task tool metrics log.
+type TaskTotals struct {
+	Calls           int     `json:"calls"`
+	Tokens          int64   `json:"tokens"`
+	ToolUses        int     `json:"tool_uses"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// taskMetricsLine is a record of the Task tool metrics log.
+type taskMetricsLine struct {
+	Timestamp       string  `json:"timestamp"`
+	TokensUsed      int64   `json:"tokens_used"`
+	ToolUses        int     `json:"tool_uses"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// ReadTaskTotals sums the Task tool metrics recorded at or after since.
+func ReadTaskTotals(projectRoot string, since time.Time) (TaskTotals, error) {
+	var t TaskTotals
+	err := readJSONL(TaskMetricsPath(projectRoot), func(data []byte) {
+		var m taskMetricsLine
+		if json.Unmarshal(data, &m) != nil {
+			return
+		}
+		if ts, err := time.Parse(time.RFC3339, m.Timestamp); err != nil || ts.Before(since) {
+			return
+		}
+		t.Calls++
+		t.Tokens += m.TokensUsed
+		t.ToolUses += m.ToolUses
+		t.DurationSeconds += m.DurationSeconds
+	})
+	return t, err
+}
+
+// readJSONL calls fn for each line of a JSONL file. A missing file is not
+// an error.
+func readJSONL(path string, fn func([]byte)) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open %s: %w", filepath.Base(path), err)
+	}
+	defer func() { _ = f.Close() }()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		fn(sc.Bytes())
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("read %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
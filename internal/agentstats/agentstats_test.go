@@ -0,0 +1,75 @@
+package agentstats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendAndScorecards(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	t0 := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	runs := []Run{
+		{Timestamp: t0, AgentID: "a1", Agent: "manager-tdd", DurationSeconds: 60, Tokens: 1000, Success: true, FilesChanged: []string{"a.go", "a_test.go"}},
+		{Timestamp: t0.Add(time.Hour), AgentID: "a2", Agent: "manager-tdd", DurationSeconds: 120, Tokens: 3000, Success: false, Reworks: 1},
+		{Timestamp: t0.Add(2 * time.Hour), AgentID: "a3", Agent: "expert-backend", DurationSeconds: 30, Tokens: 500, Success: true},
+	}
+	for _, r := range runs {
+		if err := Append(root, r); err != nil {
+			t.Fatalf("Append() error: %v", err)
+		}
+	}
+
+	got, err := ReadRuns(root, t0.Add(30*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].AgentID != "a2" {
+		t.Fatalf("ReadRuns(since) = %+v", got)
+	}
+
+	all, _ := ReadRuns(root, time.Time{})
+	cards := Scorecards(all)
+	if len(cards) != 2 || cards[0].Agent != "manager-tdd" {
+		t.Fatalf("Scorecards() = %+v", cards)
+	}
+	tdd := cards[0]
+	if tdd.Runs != 2 || tdd.SuccessRate() != 0.5 || tdd.Reworks != 1 || tdd.FilesChanged != 2 {
+		t.Errorf("manager-tdd scorecard = %+v", tdd)
+	}
+	if tdd.AvgDuration() != 90*time.Second || tdd.AvgTokens() != 2000 || !tdd.LastRun.Equal(t0.Add(time.Hour)) {
+		t.Errorf("manager-tdd averages = %v, %d, %v", tdd.AvgDuration(), tdd.AvgTokens(), tdd.LastRun)
+	}
+}
+
+func TestReadTaskTotals(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	path := TaskMetricsPath(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := `{"timestamp":"2026-10-01T09:00:00Z","tokens_used":100,"tool_uses":3,"duration_seconds":10}
+not json
+{"timestamp":"2026-10-02T09:00:00Z","tokens_used":200,"tool_uses":5,"duration_seconds":20.5}
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadTaskTotals(root, time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != (TaskTotals{Calls: 1, Tokens: 200, ToolUses: 5, DurationSeconds: 20.5}) {
+		t.Errorf("ReadTaskTotals() = %+v", got)
+	}
+
+	if empty, err := ReadTaskTotals(t.TempDir(), time.Time{}); err != nil || empty.Calls != 0 {
+		t.Errorf("missing log = %+v, %v", empty, err)
+	}
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/agentstats"
+	"github.com/modu-ai/moai-adk/internal/core/project"
+)
+
+var agentsCmd = &cobra.Command{
+	Use:   "agents",
+	Short: "Inspect subagent runs in this project",
+	Long: `Inspect the subagent runs recorded in this project.
+
+Every finished subagent is recorded by the SubagentStop hook in
+.moai/logs/agent-runs.jsonl, together with the outcomes it claimed and
+whether re-running the checks confirmed them.`,
+}
+
+var agentsStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show per-agent scorecards",
+	Long: `Show a scorecard per agent type: runs, success rate, mean duration and
+tokens, reworks and files changed. A run fails when a check behind a
+claimed outcome such as "all tests pass" failed; a rework is a run sent
+back because of such a failure.
+
+Examples:
+  moai agents stats
+  moai agents stats --since 7d
+  moai agents stats --agent manager-tdd --format json`,
+	Args: cobra.NoArgs,
+	RunE: runAgentsStats,
+}
+
+func init() {
+	rootCmd.AddCommand(agentsCmd)
+	agentsCmd.AddCommand(agentsStatsCmd)
+
+	agentsStatsCmd.Flags().String("since", "30d", "Start of range: a date (YYYY-MM-DD), Nd for N days ago, 'today' or 'all'")
+	agentsStatsCmd.Flags().String("agent", "", "Only include this agent type")
+	agentsStatsCmd.Flags().String("format", "table", "Output format: table, json")
+}
+
+// agentsReport is the JSON output of `moai agents stats`.
+type agentsReport struct {
+	Since      string                 `json:"since,omitempty"`
+	Scorecards []agentstats.Scorecard `json:"scorecards"`
+	Tasks      agentstats.TaskTotals  `json:"tasks"`
+}
+
+func runAgentsStats(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	since, err := parseUsageSince(sinceFlag, usageNow())
+	if err != nil {
+		return err
+	}
+	agent, _ := cmd.Flags().GetString("agent")
+	format, _ := cmd.Flags().GetString("format")
+	if format != "table" && format != "json" {
+		return fmt.Errorf("invalid --format %q: want table or json", format)
+	}
+
+	root, err := project.FindProjectRoot()
+	if err != nil {
+		return fmt.Errorf("find project root: %w", err)
+	}
+	runs, err := agentstats.ReadRuns(root, since)
+	if err != nil {
+		return err
+	}
+	if agent != "" {
+		var kept []agentstats.Run
+		for _, r := range runs {
+			if r.Agent == agent {
+				kept = append(kept, r)
+			}
+		}
+		runs = kept
+	}
+	tasks, err := agentstats.ReadTaskTotals(root, since)
+	if err != nil {
+		return err
+	}
+
+	report := agentsReport{Scorecards: agentstats.Scorecards(runs), Tasks: tasks}
+	if !since.IsZero() {
+		report.Since = since.Format(time.DateOnly)
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	printAgentsSummary(out, report, len(runs))
+	if len(report.Scorecards) > 0 {
+		_, _ = fmt.Fprintln(out)
+		printAgentsTable(out, report.Scorecards)
+	}
+	return nil
+}
+
+// printAgentsSummary renders the totals card of the recorded runs and the
+// Task tool metrics.
+func printAgentsSummary(out io.Writer, report agentsReport, runs int) {
+	rangeLabel := "all time"
+	if report.Since != "" {
+		rangeLabel = "since " + report.Since
+	}
+	if runs == 0 {
+		_, _ = fmt.Fprintln(out, renderInfoCard("Agent stats",
+			cliMuted.Render("No subagent runs recorded "+rangeLabel+".")))
+		return
+	}
+
+	successes, reworks := 0, 0
+	for _, s := range report.Scorecards {
+		successes += s.Successes
+		reworks += s.Reworks
+	}
+	pairs := []kvPair{
+		{"Range", rangeLabel},
+		{"Runs", fmt.Sprintf("%d across %d agent type(s)", runs, len(report.Scorecards))},
+		{"Success", fmt.Sprintf("%.1f%%", float64(successes)/float64(runs)*100)},
+		{"Reworks", fmt.Sprintf("%d", reworks)},
+	}
+	if t := report.Tasks; t.Calls > 0 {
+		pairs = append(pairs, kvPair{"Task calls", fmt.Sprintf("%d (%s tokens, %d tool uses, %s)",
+			t.Calls, formatTokenCount(t.Tokens), t.ToolUses, formatAgentDuration(time.Duration(t.DurationSeconds*float64(time.Second))))})
+	}
+	_, _ = fmt.Fprintln(out, renderCard("Agent stats", renderKeyValueLines(pairs)))
+}
+
+// printAgentsTable renders one scorecard per row.
+func printAgentsTable(out io.Writer, cards []agentstats.Scorecard) {
+	width := len("AGENT")
+	for _, s := range cards {
+		width = max(width, len(s.Agent))
+	}
+
+	header := fmt.Sprintf("%-*s  %5s  %8s  %9s  %9s  %7s  %6s  %s",
+		width, "AGENT", "RUNS", "SUCCESS", "AVG TIME", "AVG TOK", "REWORKS", "FILES", "LAST RUN")
+	_, _ = fmt.Fprintln(out, cliMuted.Render(header))
+
+	for _, s := range cards {
+		_, _ = fmt.Fprintf(out, "%-*s  %5d  %7.1f%%  %9s  %9s  %7d  %6d  %s\n",
+			width, s.Agent,
+			s.Runs,
+			s.SuccessRate()*100,
+			formatAgentDuration(s.AvgDuration()),
+			formatTokenCount(s.AvgTokens()),
+			s.Reworks,
+			s.FilesChanged,
+			s.LastRun.Local().Format("2006-01-02 15:04"),
+		)
+	}
+}
+
+// formatAgentDuration renders a duration rounded to the second, or "-"
+// when unknown.
+func formatAgentDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return d.Round(time.Second).String()
+}
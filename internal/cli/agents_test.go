@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/agentstats"
+)
+
+func runAgentsStatsCmd(t *testing.T, flags map[string]string) (string, error) {
+	t.Helper()
+	cmd := &cobra.Command{RunE: runAgentsStats}
+	cmd.Flags().AddFlagSet(agentsStatsCmd.Flags())
+	for k, v := range flags {
+		if err := cmd.Flags().Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+		f := cmd.Flags().Lookup(k)
+		t.Cleanup(func() {
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		})
+	}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetContext(context.Background())
+	err := cmd.RunE(cmd, nil)
+	return buf.String(), err
+}
+
+func TestRunAgentsStats(t *testing.T) {
+	root, _ := filepath.EvalSymlinks(t.TempDir())
+	t.Chdir(root)
+
+	now := time.Now()
+	for _, r := range []agentstats.Run{
+		{Timestamp: now.Add(-time.Hour), AgentID: "a1", Agent: "manager-tdd", DurationSeconds: 90, Tokens: 12000, Success: true, FilesChanged: []string{"a.go"}},
+		{Timestamp: now, AgentID: "a2", Agent: "manager-tdd", DurationSeconds: 30, Tokens: 8000, Reworks: 1},
+		{Timestamp: now, AgentID: "a3", Agent: "expert-backend", DurationSeconds: 60, Tokens: 5000, Success: true},
+		{Timestamp: now.AddDate(0, 0, -90), AgentID: "old", Agent: "expert-debug", Success: true},
+	} {
+		if err := agentstats.Append(root, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	metrics := `{"timestamp":"` + now.UTC().Format(time.RFC3339) + `","tokens_used":4000,"tool_uses":7,"duration_seconds":42}` + "\n"
+	if err := os.WriteFile(agentstats.TaskMetricsPath(root), []byte(metrics), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runAgentsStatsCmd(t, nil)
+	if err != nil {
+		t.Fatalf("agents stats: %v", err)
+	}
+	for _, want := range []string{"3 across 2 agent type(s)", "66.7%", "1 (4.0K tokens, 7 tool uses, 42s)", "AGENT", "REWORKS"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	lines := strings.Split(out, "\n")
+	var tddRow string
+	for _, l := range lines {
+		if strings.HasPrefix(l, "manager-tdd") {
+			tddRow = l
+		}
+	}
+	if fields := strings.Fields(tddRow); len(fields) < 7 || fields[1] != "2" || fields[2] != "50.0%" || fields[3] != "1m0s" || fields[4] != "10.0K" || fields[5] != "1" || fields[6] != "1" {
+		t.Errorf("manager-tdd row = %q", tddRow)
+	}
+	if strings.Contains(out, "expert-debug") {
+		t.Errorf("run older than --since listed:\n%s", out)
+	}
+
+	out, err = runAgentsStatsCmd(t, map[string]string{"since": "all", "agent": "expert-debug", "format": "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report agentsReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out)
+	}
+	if len(report.Scorecards) != 1 || report.Scorecards[0].Agent != "expert-debug" || report.Since != "" {
+		t.Errorf("json report = %+v", report)
+	}
+}
+
+func TestRunAgentsStats_NoRuns(t *testing.T) {
+	root, _ := filepath.EvalSymlinks(t.TempDir())
+	if err := os.MkdirAll(filepath.Join(root, ".moai"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(root)
+
+	out, err := runAgentsStatsCmd(t, map[string]string{"since": "all"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "No subagent runs recorded all time.") {
+		t.Errorf("output = %q", out)
+	}
+	if _, err := runAgentsStatsCmd(t, map[string]string{"format": "csv"}); err == nil {
+		t.Error("expected an error for --format csv")
+	}
+}
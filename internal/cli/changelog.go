@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/git/changelog"
+	"github.com/modu-ai/moai-adk/internal/git/ops"
+	"github.com/modu-ai/moai-adk/internal/update"
+)
+
+// changelogTimeout bounds the git commands run by `moai changelog`.
+const changelogTimeout = 30
+
+// changelogNow returns the release date; tests replace it.
+var changelogNow = time.Now
+
+func init() {
+	rootCmd.AddCommand(newChangelogCmd())
+}
+
+func newChangelogCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "changelog",
+		Short: "Generate a changelog section from commit history",
+		Long: `Group the commits since the latest tag by type and scope of the active
+git convention and prepend a Keep a Changelog section to CHANGELOG.md.
+
+Breaking changes ("!" after the type or a BREAKING CHANGE footer) are listed
+first. SPEC IDs and issue numbers are linked through the SPEC registry.
+Without --next the section is written as Unreleased; with --next it is
+titled with the next semantic version computed from the changes.
+
+Example:
+  moai changelog --dry-run
+  moai changelog --since v1.2.0 --next`,
+		Args: cobra.NoArgs,
+		RunE: runChangelog,
+	}
+	cmd.Flags().String("since", "", "Tag or commit to start from (default: latest tag)")
+	cmd.Flags().Bool("next", false, "Title the section with the next semantic version")
+	cmd.Flags().Bool("dry-run", false, "Print the section instead of writing CHANGELOG.md")
+	cmd.Flags().String("output", "CHANGELOG.md", "Changelog file, relative to the project root")
+	return cmd
+}
+
+func runChangelog(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+	since, _ := cmd.Flags().GetString("since")
+	next, _ := cmd.Flags().GetBool("next")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	output, _ := cmd.Flags().GetString("output")
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	mgr := ops.NewGitManager(ops.ManagerConfig{WorkDir: cwd})
+	defer mgr.Shutdown()
+
+	root := mgr.GetRepoRoot()
+	if root == "" {
+		return fmt.Errorf("moai changelog must run inside a git repository")
+	}
+	if since == "" {
+		// Without tags the whole history is described.
+		if r := mgr.ExecuteRaw([]string{"describe", "--tags", "--abbrev=0"}, changelogTimeout); r.Success {
+			since = r.Stdout
+		}
+	}
+	commits, err := changelogCommits(mgr, since)
+	if err != nil {
+		return err
+	}
+
+	cfg := loadWorkflowProjectConfig(root)
+	conv, err := loadProjectConvention(root, cfg)
+	if err != nil {
+		return err
+	}
+	// Without a readable registry, only references in messages are linked.
+	linker, _ := GithubSpecLinkerFactory(root)
+	release := changelog.Build(commits, conv, linker)
+
+	bump := release.Bump()
+	if next {
+		if bump == changelog.BumpNone {
+			return fmt.Errorf("no releasable changes since %s", cmp.Or(since, "the first commit"))
+		}
+		version, err := changelog.NextVersion(changelogBaseVersion(mgr, since), bump)
+		if err != nil {
+			return fmt.Errorf("compute next version: %w", err)
+		}
+		release.Version, release.Date = version, changelogNow()
+	}
+
+	if dryRun {
+		_, _ = fmt.Fprint(out, release.Markdown())
+		return nil
+	}
+
+	path := filepath.Join(root, output)
+	if err := changelog.Prepend(path, release); err != nil {
+		return err
+	}
+	title := "Unreleased"
+	if release.Version != "" {
+		title = release.Version
+	}
+	_, _ = fmt.Fprintln(out, renderSuccessCard(fmt.Sprintf("Changelog updated: %s", title),
+		fmt.Sprintf("Commits:    %d since %s", len(release.Entries), cmp.Or(since, "the first commit")),
+		fmt.Sprintf("Convention: %s", conv.Name),
+		fmt.Sprintf("Bump:       %s", bump),
+		fmt.Sprintf("File:       %s", path),
+	))
+	return nil
+}
+
+// changelogBaseVersion returns the version --next bumps: since itself when
+// it is a semantic version, otherwise the newest semver tag reachable from
+// since. It returns "" (0.0.0) when there is none.
+func changelogBaseVersion(mgr *ops.GitManager, since string) string {
+	if since == "" {
+		return ""
+	}
+	if _, err := update.ParseSemver(since); err == nil {
+		return since
+	}
+	r := mgr.ExecuteRaw([]string{"tag", "--merged", since, "--sort=-v:refname"}, changelogTimeout)
+	if !r.Success {
+		return ""
+	}
+	for tag := range strings.SplitSeq(r.Stdout, "\n") {
+		tag = strings.TrimSpace(tag)
+		if _, err := update.ParseSemver(tag); err == nil {
+			return tag
+		}
+	}
+	return ""
+}
+
+// changelogCommits lists the commits after since (all commits when empty),
+// newest first.
+func changelogCommits(mgr *ops.GitManager, since string) ([]changelog.Commit, error) {
+	rev := "HEAD"
+	if since != "" {
+		rev = since + "..HEAD"
+	}
+	r := mgr.ExecuteRaw([]string{"log", "--format=%H%x1f%s%x1f%b%x1e", rev}, changelogTimeout)
+	if !r.Success {
+		// A repository without commits has nothing to describe.
+		if strings.Contains(r.Stderr, "does not have any commits") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("git log %s: %s", rev, r.Stderr)
+	}
+
+	var commits []changelog.Commit
+	for record := range strings.SplitSeq(r.Stdout, "\x1e") {
+		fields := strings.SplitN(strings.TrimSpace(record), "\x1f", 3)
+		if len(fields) < 2 {
+			continue
+		}
+		c := changelog.Commit{Hash: fields[0], Subject: fields[1]}
+		if len(fields) == 3 {
+			c.Body = strings.TrimSpace(fields[2])
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/github"
+)
+
+func setupChangelogRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	t.Chdir(root)
+	t.Setenv("MOAI_GIT_CONVENTION", "conventional-commits")
+	gitRun(t, "init", "-q")
+
+	origLinker, origNow := GithubSpecLinkerFactory, changelogNow
+	t.Cleanup(func() { GithubSpecLinkerFactory, changelogNow = origLinker, origNow })
+	GithubSpecLinkerFactory = func(string) (github.SpecLinker, error) {
+		return &mockGHSpecLinker{
+			getIssueFunc: func(specID string) (int, error) {
+				if specID == "SPEC-AUTH-001" {
+					return 7, nil
+				}
+				return 0, github.ErrMappingNotFound
+			},
+			getSpecFunc: func(int) (string, error) { return "", github.ErrMappingNotFound },
+		}, nil
+	}
+	changelogNow = func() time.Time { return time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC) }
+
+	gitRun(t, "commit", "-q", "--allow-empty", "-m", "feat: initial release")
+	gitRun(t, "tag", "v1.2.0")
+	gitRun(t, "commit", "-q", "--allow-empty", "-m", "feat(auth): add JWT validation", "-m", "Implements SPEC-AUTH-001.")
+	gitRun(t, "commit", "-q", "--allow-empty", "-m", "fix: handle empty config")
+	gitRun(t, "commit", "-q", "--allow-empty", "-m", "chore: bump deps")
+	return root
+}
+
+func TestChangelog_Next(t *testing.T) {
+	root := setupChangelogRepo(t)
+
+	out, err := runGithubTestCmd(t, newChangelogCmd(), "--next")
+	if err != nil {
+		t.Fatalf("changelog error = %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "v1.3.0") || !strings.Contains(out, "minor") {
+		t.Errorf("output:\n%s", out)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "CHANGELOG.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"# Changelog",
+		"## [1.3.0] - 2026-10-18\n\n### Added\n\n- **auth:** add JWT validation (SPEC-AUTH-001, #7, ",
+		"### Fixed\n\n- handle empty config (",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("CHANGELOG.md missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "bump deps") || strings.Contains(got, "initial release") {
+		t.Errorf("CHANGELOG.md includes omitted commits:\n%s", got)
+	}
+}
+
+func TestChangelog_DryRunBreaking(t *testing.T) {
+	root := setupChangelogRepo(t)
+	gitRun(t, "commit", "-q", "--allow-empty", "-m", "refactor!: drop v1 API")
+
+	out, err := runGithubTestCmd(t, newChangelogCmd(), "--dry-run", "--next")
+	if err != nil {
+		t.Fatalf("changelog error = %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(out, "## [2.0.0] - 2026-10-18\n\n### Breaking Changes\n\n- drop v1 API") {
+		t.Errorf("output:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(root, "CHANGELOG.md")); !os.IsNotExist(err) {
+		t.Error("dry run wrote CHANGELOG.md")
+	}
+}
+
+func TestChangelog_Unreleased(t *testing.T) {
+	setupChangelogRepo(t)
+
+	out, err := runGithubTestCmd(t, newChangelogCmd(), "--since", "HEAD~1", "--dry-run")
+	if err != nil || !strings.HasPrefix(out, "## [Unreleased]\n\nNo notable changes.") {
+		t.Errorf("changelog = %q, %v", out, err)
+	}
+	if _, err := runGithubTestCmd(t, newChangelogCmd(), "--since", "HEAD~1", "--next"); err == nil {
+		t.Error("--next without releasable changes should fail")
+	}
+}
+
+func TestChangelog_NextFromNonVersionSince(t *testing.T) {
+	setupChangelogRepo(t)
+	gitRun(t, "tag", "release-candidate", "HEAD~2")
+
+	// The base version comes from the newest semver tag before the commit.
+	for _, since := range []string{"HEAD~2", "release-candidate"} {
+		out, err := runGithubTestCmd(t, newChangelogCmd(), "--since", since, "--dry-run", "--next")
+		if err != nil {
+			t.Fatalf("--since %s: %v\n%s", since, err, out)
+		}
+		if !strings.HasPrefix(out, "## [1.2.1] - 2026-10-18") {
+			t.Errorf("--since %s output:\n%s", since, out)
+		}
+	}
+}
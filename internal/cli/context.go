@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/contextsearch"
+	"github.com/modu-ai/moai-adk/internal/core/project"
+)
+
+// contextPreviewChars bounds the response shown per search result.
+const contextPreviewChars = 400
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Search previous Claude Code sessions",
+	Long: `Search the exchanges of previous Claude Code sessions in this project.
+
+Transcripts are read incrementally into an index under
+.moai/cache/context-search and ranked with BM25. The same search runs on
+every prompt when context_search.auto_detect is enabled in context.yaml.`,
+}
+
+var contextSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Find previous exchanges related to a query",
+	Long: `Find the prompts and answers of previous sessions that best match a query.
+
+Examples:
+  moai context search "token refresh race"
+  moai context search "release checklist" --limit 10 --all-projects`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runContextSearch,
+}
+
+func init() {
+	rootCmd.AddCommand(contextCmd)
+	contextCmd.AddCommand(contextSearchCmd)
+
+	contextSearchCmd.Flags().Int("limit", 0, "Maximum number of results (default: search.max_results)")
+	contextSearchCmd.Flags().Int("days", 0, "Only search the last N days (default: search.date_range_days)")
+	contextSearchCmd.Flags().Bool("all-projects", false, "Search sessions of every project, not only this one")
+	contextSearchCmd.Flags().Bool("reindex", false, "Read new transcript content even if the index is fresh")
+}
+
+func runContextSearch(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+	query := strings.Join(args, " ")
+
+	root, err := project.FindProjectRoot()
+	if err != nil {
+		return fmt.Errorf("find project root: %w", err)
+	}
+	settings := loadWorkflowProjectConfig(root).ContextSearch
+	if days, _ := cmd.Flags().GetInt("days"); days > 0 {
+		settings.Search.DateRangeDays = days
+	}
+	if all, _ := cmd.Flags().GetBool("all-projects"); all {
+		settings.Search.ProjectScopeOnly = false
+	}
+	limit, _ := cmd.Flags().GetInt("limit")
+	reindex, _ := cmd.Flags().GetBool("reindex")
+
+	searcher := contextsearch.NewSearcher(root, settings)
+	idx, stats, err := searcher.Refresh(cmd.Context(), reindex)
+	if err != nil {
+		return err
+	}
+	if stats.Partial {
+		_, _ = fmt.Fprintln(out, cliMuted.Render("Indexing stopped at the timeout; run again to index the rest."))
+	}
+	results, err := searcher.Search(cmd.Context(), contextsearch.Query{Text: query, Limit: limit, MinMatch: 1})
+	if err != nil {
+		return err
+	}
+
+	title := fmt.Sprintf("Context search: %q", query)
+	if len(results) == 0 {
+		_, _ = fmt.Fprintln(out, renderInfoCard(title,
+			cliMuted.Render(fmt.Sprintf("No matching exchanges among %d indexed.", idx.Len()))))
+		return nil
+	}
+	_, _ = fmt.Fprintln(out, renderCard(title, renderContextResults(results, idx.Len())))
+	return nil
+}
+
+// renderContextResults lists the results with their session, time, score
+// and a preview of the exchange.
+func renderContextResults(results []contextsearch.Result, indexed int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d result(s) from %d indexed exchange(s)", len(results), indexed)
+	for i, r := range results {
+		session := r.SessionID
+		if len(session) > 8 {
+			session = session[:8]
+		}
+		fmt.Fprintf(&b, "\n\n%d. %s  session %s  score %.2f", i+1, r.Time.Local().Format(time.DateTime), session, r.Score)
+		fmt.Fprintf(&b, "\n   Prompt:   %s", contextPreview(r.Prompt))
+		if r.Response != "" {
+			fmt.Fprintf(&b, "\n   Response: %s", contextPreview(r.Response))
+		}
+	}
+	return b.String()
+}
+
+// contextPreview flattens text onto one line and shortens it.
+func contextPreview(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) > contextPreviewChars {
+		text = strings.ToValidUTF8(text[:contextPreviewChars], "") + "…"
+	}
+	return text
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/contextsearch"
+)
+
+// setupContextSearchFixture creates a MoAI project holding the working
+// directory and a settled transcript of one of its sessions.
+func setupContextSearchFixture(t *testing.T) string {
+	t.Helper()
+	root, _ := filepath.EvalSymlinks(t.TempDir())
+	if err := os.MkdirAll(filepath.Join(root, ".moai"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(root)
+
+	path := filepath.Join(t.TempDir(), "sess.jsonl")
+	content := `{"type":"user","sessionId":"3f2a9c1e-77","cwd":"` + root + `","timestamp":"2026-10-01T10:00:00Z","message":{"content":"How do we rotate the signing keys?"}}
+{"type":"assistant","sessionId":"3f2a9c1e-77","cwd":"` + root + `","message":{"content":[{"type":"text","text":"Run scripts/rotate-keys.sh, then restart the auth service."}]}}
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	orig := contextsearch.FindTranscripts
+	contextsearch.FindTranscripts = func() ([]string, error) { return []string{path}, nil }
+	t.Cleanup(func() { contextsearch.FindTranscripts = orig })
+	return root
+}
+
+func runContextSearchCmd(t *testing.T, flags map[string]string, args ...string) (string, error) {
+	t.Helper()
+	cmd := &cobra.Command{RunE: runContextSearch}
+	cmd.Flags().AddFlagSet(contextSearchCmd.Flags())
+	for k, v := range flags {
+		if err := cmd.Flags().Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+		f := cmd.Flags().Lookup(k)
+		t.Cleanup(func() {
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		})
+	}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetContext(context.Background())
+	err := cmd.RunE(cmd, args)
+	return buf.String(), err
+}
+
+func TestRunContextSearch(t *testing.T) {
+	root := setupContextSearchFixture(t)
+
+	out, err := runContextSearchCmd(t, map[string]string{"days": "3650"}, "rotate", "signing", "keys")
+	if err != nil {
+		t.Fatalf("context search: %v", err)
+	}
+	for _, want := range []string{"1 result(s) from 1 indexed exchange(s)", "session 3f2a9c1e", "How do we rotate the signing keys?", "scripts/rotate-keys.sh"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, ".moai", "cache", "context-search", "index.json")); err != nil {
+		t.Errorf("index not written: %v", err)
+	}
+
+	out, err = runContextSearchCmd(t, map[string]string{"days": "3650"}, "kubernetes")
+	if err != nil {
+		t.Fatalf("context search: %v", err)
+	}
+	if !strings.Contains(out, "No matching exchanges among 1 indexed.") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
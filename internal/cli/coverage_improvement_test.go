@@ -1391,19 +1391,12 @@ func TestRestoreMoaiConfig_LegacyBackup(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// Should fallback to legacy restore
 	err := restoreMoaiConfig(tmpDir, backupDir)
 	if err != nil {
 		t.Fatalf("restoreMoaiConfig legacy error: %v", err)
 	}
 }
 
-// =============================================================================
-// restoreMoaiConfigLegacy — update.go:1411 (previously 71.4%)
-// =============================================================================
-
-// TestRestoreMoaiConfigLegacy_SkipsMetadata removed - exists in update_fileops_test.go
-
 // =============================================================================
 // runCC — complete path testing (cc.go:35)
 // =============================================================================
@@ -2924,30 +2917,6 @@ func TestRestoreMoaiConfig_2WayMerge(t *testing.T) {
 	}
 }
 
-// restoreMoaiConfig — test legacy fallback
-func TestRestoreMoaiConfig_LegacyFallback(t *testing.T) {
-	tmpDir := t.TempDir()
-
-	// Create project config dir
-	configDir := filepath.Join(tmpDir, ".moai", "config", "sections")
-	if err := os.MkdirAll(configDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	// Create backup WITHOUT sections dir (legacy format)
-	backupDir := t.TempDir()
-	legacyData := "quality:\n  test_coverage_target: 80\n"
-	if err := os.WriteFile(filepath.Join(backupDir, "quality.yaml"), []byte(legacyData), 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	// Should fall through to legacy path
-	err := restoreMoaiConfig(tmpDir, backupDir)
-	if err != nil {
-		t.Fatalf("restoreMoaiConfig legacy error: %v", err)
-	}
-}
-
 // restoreMoaiConfig — test 3-way merge with template defaults
 func TestRestoreMoaiConfig_3WayMerge(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -4555,90 +4524,6 @@ func TestWorktreePersistentPreRunE_WithDeps(t *testing.T) {
 	}
 }
 
-// --- restoreMoaiConfigLegacy: with merge ---
-
-func TestRestoreMoaiConfigLegacy_WithMerge(t *testing.T) {
-	tmpDir := t.TempDir()
-
-	// Create backup dir with a YAML file
-	backupDir := filepath.Join(tmpDir, "backup")
-	backupSections := filepath.Join(backupDir, "sections")
-	if err := os.MkdirAll(backupSections, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	backupYAML := "user:\n  name: backup-user\n  custom: preserved\n"
-	if err := os.WriteFile(filepath.Join(backupSections, "user.yaml"), []byte(backupYAML), 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	// Create metadata file (should be skipped)
-	if err := os.WriteFile(filepath.Join(backupDir, "backup_metadata.json"), []byte("{}"), 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	// Create target config dir with existing file for merging
-	configDir := filepath.Join(tmpDir, "config")
-	configSections := filepath.Join(configDir, "sections")
-	if err := os.MkdirAll(configSections, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	existingYAML := "user:\n  name: new-user\n  email: new@test.com\n"
-	if err := os.WriteFile(filepath.Join(configSections, "user.yaml"), []byte(existingYAML), 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	err := restoreMoaiConfigLegacy(tmpDir, backupDir, configDir)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-
-	// Verify merged result
-	mergedData, err := os.ReadFile(filepath.Join(configSections, "user.yaml"))
-	if err != nil {
-		t.Fatalf("merged file not found: %v", err)
-	}
-	merged := string(mergedData)
-	// Should contain data from both backup and target
-	if !strings.Contains(merged, "user") {
-		t.Error("expected merged result to contain user key")
-	}
-}
-
-// --- restoreMoaiConfigLegacy: backup file for non-existent target ---
-
-func TestRestoreMoaiConfigLegacy_NewFile(t *testing.T) {
-	tmpDir := t.TempDir()
-
-	backupDir := filepath.Join(tmpDir, "backup")
-	if err := os.MkdirAll(filepath.Join(backupDir, "sections"), 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	backupYAML := "language:\n  conversation_language: ko\n"
-	if err := os.WriteFile(filepath.Join(backupDir, "sections", "language.yaml"), []byte(backupYAML), 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	configDir := filepath.Join(tmpDir, "config")
-	// Don't create config/sections - let the function create it
-
-	err := restoreMoaiConfigLegacy(tmpDir, backupDir, configDir)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-
-	// Verify file was restored
-	data, err := os.ReadFile(filepath.Join(configDir, "sections", "language.yaml"))
-	if err != nil {
-		t.Fatalf("restored file not found: %v", err)
-	}
-	if !strings.Contains(string(data), "ko") {
-		t.Error("expected restored file to contain ko")
-	}
-}
-
 // --- saveTemplateDefaults: exercises embedded template saving ---
 
 func TestSaveTemplateDefaults_CreatesFiles(t *testing.T) {
@@ -6515,52 +6400,6 @@ func TestDetermineStrategy_AllTypes(t *testing.T) {
 	}
 }
 
-// --- restoreMoaiConfigLegacy: skip template-defaults ---
-
-func TestRestoreMoaiConfigLegacy_SkipsTemplateDefaults(t *testing.T) {
-	tmpDir := t.TempDir()
-	configDir := filepath.Join(tmpDir, ".moai", "config")
-	if err := os.MkdirAll(configDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	backupDir := filepath.Join(tmpDir, "backup")
-	if err := os.MkdirAll(backupDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	// Create backup_metadata.json and .template-defaults dir - should be skipped
-	if err := os.WriteFile(filepath.Join(backupDir, "backup_metadata.json"), []byte("{}"), 0o644); err != nil {
-		t.Fatal(err)
-	}
-	if err := os.MkdirAll(filepath.Join(backupDir, ".template-defaults"), 0o755); err != nil {
-		t.Fatal(err)
-	}
-	if err := os.WriteFile(filepath.Join(backupDir, ".template-defaults", "test.yaml"), []byte("test: true"), 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	// Create a real yaml file in backup
-	if err := os.WriteFile(filepath.Join(backupDir, "real.yaml"), []byte("real: config"), 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	err := restoreMoaiConfigLegacy(tmpDir, backupDir, configDir)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-
-	// real.yaml should be restored
-	if _, statErr := os.Stat(filepath.Join(configDir, "real.yaml")); statErr != nil {
-		t.Error("expected real.yaml to be restored")
-	}
-
-	// backup_metadata.json should NOT be restored to config
-	if _, statErr := os.Stat(filepath.Join(configDir, "backup_metadata.json")); statErr == nil {
-		t.Error("expected backup_metadata.json to be skipped")
-	}
-}
-
 // --- isTestEnvironment: more branches ---
 
 func TestIsTestEnvironment_WithFlag(t *testing.T) {
@@ -7168,116 +7007,6 @@ func TestSaveTemplateDefaults(t *testing.T) {
 	}
 }
 
-// --- restoreMoaiConfigLegacy ---
-
-func TestRestoreMoaiConfigLegacy_TargetNotExist(t *testing.T) {
-	tmpDir := t.TempDir()
-
-	// Setup backup with a file
-	backupDir := filepath.Join(tmpDir, "backup")
-	if err := os.MkdirAll(backupDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-	if err := os.WriteFile(filepath.Join(backupDir, "custom.yaml"), []byte("custom:\n  key: value\n"), 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	// Config dir exists but no target file
-	configDir := filepath.Join(tmpDir, ".moai", "config", "sections")
-	if err := os.MkdirAll(configDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	err := restoreMoaiConfigLegacy(tmpDir, backupDir, configDir)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-
-	// Verify file was copied (not merged since target didn't exist)
-	data, err := os.ReadFile(filepath.Join(configDir, "custom.yaml"))
-	if err != nil {
-		t.Fatalf("custom.yaml not restored: %v", err)
-	}
-	if !strings.Contains(string(data), "key: value") {
-		t.Error("expected restored content")
-	}
-}
-
-func TestRestoreMoaiConfigLegacy_SkipsMetadataAndTemplateDefaults(t *testing.T) {
-	tmpDir := t.TempDir()
-
-	backupDir := filepath.Join(tmpDir, "backup")
-	templateDefaultsDir := filepath.Join(backupDir, ".template-defaults")
-	if err := os.MkdirAll(templateDefaultsDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	// Create metadata file and template defaults
-	if err := os.WriteFile(filepath.Join(backupDir, "backup_metadata.json"), []byte(`{}`), 0o644); err != nil {
-		t.Fatal(err)
-	}
-	if err := os.WriteFile(filepath.Join(templateDefaultsDir, "language.yaml"), []byte("template default"), 0o644); err != nil {
-		t.Fatal(err)
-	}
-	// Also add a real config file
-	if err := os.WriteFile(filepath.Join(backupDir, "user.yaml"), []byte("user:\n  name: test\n"), 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	configDir := filepath.Join(tmpDir, "config")
-	if err := os.MkdirAll(configDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	err := restoreMoaiConfigLegacy(tmpDir, backupDir, configDir)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-
-	// Metadata should not be restored to config dir
-	if _, err := os.Stat(filepath.Join(configDir, "backup_metadata.json")); !os.IsNotExist(err) {
-		t.Error("backup_metadata.json should not be restored")
-	}
-
-	// user.yaml should be restored
-	if _, err := os.Stat(filepath.Join(configDir, "user.yaml")); os.IsNotExist(err) {
-		t.Error("user.yaml should be restored")
-	}
-}
-
-func TestRestoreMoaiConfigLegacy_MergeWithExistingTarget(t *testing.T) {
-	tmpDir := t.TempDir()
-
-	backupDir := filepath.Join(tmpDir, "backup")
-	if err := os.MkdirAll(backupDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-	if err := os.WriteFile(filepath.Join(backupDir, "language.yaml"), []byte("language:\n  code_comments: ko\n"), 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	configDir := filepath.Join(tmpDir, "config")
-	if err := os.MkdirAll(configDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-	// Target file already exists with different content
-	if err := os.WriteFile(filepath.Join(configDir, "language.yaml"), []byte("language:\n  conversation_language: en\n"), 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	err := restoreMoaiConfigLegacy(tmpDir, backupDir, configDir)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-
-	data, _ := os.ReadFile(filepath.Join(configDir, "language.yaml"))
-	content := string(data)
-	// After merge, should contain values from both
-	if !strings.Contains(content, "code_comments") {
-		t.Error("expected merged result to contain backup's code_comments")
-	}
-}
-
 // --- runDoctor with verbose, fix, and export ---
 
 func TestRunDoctor_VerboseAndDetail(t *testing.T) {
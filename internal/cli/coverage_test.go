@@ -1132,6 +1132,7 @@ func TestRunStatusline_NilDeps(t *testing.T) {
 	defer func() { deps = origDeps }()
 
 	deps = nil
+	t.Chdir(t.TempDir())
 
 	buf := new(bytes.Buffer)
 	StatuslineCmd.SetOut(buf)
@@ -35,6 +35,8 @@ type Dependencies struct {
 	HookProtocol  hook.Protocol
 	UpdateChecker update.Checker
 	UpdateOrch    update.Orchestrator
+	UpdateLister  update.ReleaseLister
+	UpdatePinned  func(info *update.VersionInfo) update.Orchestrator
 	RankClient    rank.Client
 	RankCredStore rank.CredentialStore
 	RankBrowser   rank.BrowserOpener
@@ -91,17 +93,23 @@ func InitDependencies() {
 
 	deps.HookRegistry.Register(hook.NewStopHandler())
 	deps.HookRegistry.Register(hook.NewPreToolHandlerWithScanner(deps.Config, hook.DefaultSecurityPolicy(), securityScanner))
-	deps.HookRegistry.Register(hook.NewPostToolHandlerWithDiagnostics(diagnosticsCollector))
-	deps.HookRegistry.Register(hook.NewCompactHandler())
+	deps.HookRegistry.Register(hook.NewPostToolHandlerWithContext(deps.Config, diagnosticsCollector))
+	deps.HookRegistry.Register(hook.NewCompactHandlerWithConfig(deps.Config))
 	deps.HookRegistry.Register(hook.NewPostToolUseFailureHandler())
 	deps.HookRegistry.Register(hook.NewNotificationHandler())
-	deps.HookRegistry.Register(hook.NewSubagentStartHandler())
-	deps.HookRegistry.Register(hook.NewUserPromptSubmitHandler())
-	deps.HookRegistry.Register(hook.NewPermissionRequestHandler())
+	deps.HookRegistry.Register(hook.NewSubagentStartHandlerWithConfig(deps.Config))
+	deps.HookRegistry.Register(hook.NewSubagentStopHandlerWithConfig(deps.Config))
+	deps.HookRegistry.Register(hook.NewUserPromptSubmitHandlerWithConfig(deps.Config))
+	deps.HookRegistry.Register(hook.NewPermissionRequestHandlerWithConfig(deps.Config, hook.DefaultSecurityPolicy()))
 	deps.HookRegistry.Register(hook.NewTeammateIdleHandler())
 	deps.HookRegistry.Register(hook.NewTaskCompletedHandler())
-	deps.HookRegistry.Register(hook.NewWorktreeCreateHandler())
-	deps.HookRegistry.Register(hook.NewWorktreeRemoveHandler())
+	deps.HookRegistry.Register(hook.NewWorktreeCreateHandlerWithConfig(deps.Config))
+	deps.HookRegistry.Register(hook.NewWorktreeRemoveHandlerWithConfig(deps.Config))
+
+	// Register the git watcher lifecycle handlers
+	for _, h := range hook.NewGitWatchHandlers(GitWatchStartFactory()) {
+		deps.HookRegistry.Register(h)
+	}
 }
 
 // GetDeps returns the current Dependencies instance.
@@ -133,7 +141,9 @@ func (d *Dependencies) EnsureGit(projectRoot string) error {
 }
 
 // EnsureUpdate lazily initializes Update-related dependencies.
-// It should be called before using UpdateChecker or UpdateOrch.
+// It should be called before using UpdateChecker, UpdateOrch, UpdateLister
+// or UpdatePinned. The checker honors the project's moai.update_channel and
+// moai.required_version so automatic updates never leave the pinned range.
 // Thread-safe: subsequent calls are no-ops if UpdateChecker is already initialized.
 func (d *Dependencies) EnsureUpdate() error {
 	if d.UpdateChecker != nil {
@@ -159,13 +169,10 @@ func (d *Dependencies) EnsureUpdate() error {
 			ReleasesDir:    os.Getenv("MOAI_RELEASES_DIR"),
 			CurrentVersion: currentVersion,
 		}
-		d.UpdateChecker = update.NewLocalChecker(localConfig)
-		d.UpdateOrch = update.NewOrchestrator(
-			currentVersion,
-			d.UpdateChecker,
-			update.NewLocalUpdater(localConfig.ReleasesDir, binaryPath),
-			update.NewRollback(binaryPath),
-		)
+		localChecker := update.NewLocalChecker(localConfig)
+		localUpdater := update.NewLocalUpdater(localConfig.ReleasesDir, binaryPath)
+		rollback := update.NewRollback(binaryPath)
+		d.wireUpdate(currentVersion, localChecker, localUpdater, rollback)
 		return nil
 	}
 
@@ -188,14 +195,34 @@ func (d *Dependencies) EnsureUpdate() error {
 		}
 	}
 
-	d.UpdateChecker = update.NewChecker(apiURL, nil)
+	checker := update.NewChecker(apiURL, nil)
 	updater := update.NewUpdater(binaryPath, nil)
 	rollback := update.NewRollback(binaryPath)
-	d.UpdateOrch = update.NewOrchestrator(currentVersion, d.UpdateChecker, updater, rollback)
+	d.wireUpdate(currentVersion, checker, updater, rollback)
 
 	return nil
 }
 
+// wireUpdate assigns the update dependencies. When the checker can list
+// releases and the project selects a non-stable channel or pins
+// moai.required_version, the checker is wrapped in a channel checker.
+func (d *Dependencies) wireUpdate(currentVersion string, checker update.Checker, updater update.Updater, rollback update.Rollback) {
+	d.UpdateChecker = checker
+	if lister, ok := checker.(update.ReleaseLister); ok {
+		d.UpdateLister = lister
+		if policy, err := readUpdatePolicy("."); err == nil {
+			constraint, cErr := policy.constraint()
+			if cErr == nil && (constraint != nil || policy.Channel != update.ChannelStable) {
+				d.UpdateChecker = update.NewChannelChecker(lister, policy.Channel, constraint)
+			}
+		}
+	}
+	d.UpdateOrch = update.NewOrchestrator(currentVersion, d.UpdateChecker, updater, rollback)
+	d.UpdatePinned = func(info *update.VersionInfo) update.Orchestrator {
+		return update.NewOrchestrator(currentVersion, update.NewPinnedChecker(info), updater, rollback)
+	}
+}
+
 // buildAutoUpdateFunc creates the callback that performs binary self-update.
 // It uses a closure to avoid circular dependencies between hook and update.
 func buildAutoUpdateFunc() hook.AutoUpdateFunc {
@@ -1,9 +1,14 @@
 package cli
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/modu-ai/moai-adk/internal/core/git"
+	"github.com/modu-ai/moai-adk/internal/hook"
 )
 
 func TestInitDependencies(t *testing.T) {
@@ -164,3 +169,38 @@ func (m *mockGitRepository) Log(_ int) ([]git.Commit, error)  { return nil, nil
 func (m *mockGitRepository) Diff(_, _ string) (string, error) { return "", nil }
 func (m *mockGitRepository) IsClean() (bool, error)           { return true, nil }
 func (m *mockGitRepository) Root() string                     { return "/mock/root" }
+
+func TestInitDependencies_GitWatchStartFactory(t *testing.T) {
+	origDeps, origFactory := deps, GitWatchStartFactory
+	defer func() { deps, GitWatchStartFactory = origDeps, origFactory }()
+
+	var started []string
+	GitWatchStartFactory = func() hook.GitWatchStartFunc {
+		return func(_, sessionID string) error {
+			started = append(started, sessionID)
+			return nil
+		}
+	}
+	InitDependencies()
+
+	root := t.TempDir()
+	for _, dir := range []string{".git", ".moai"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("CLAUDE_PROJECT_DIR", root)
+	t.Setenv("MOAI_GIT_WATCH", "")
+
+	for _, h := range deps.HookRegistry.Handlers(hook.EventSessionStart) {
+		if fmt.Sprintf("%T", h) != "*hook.gitWatchHandler" {
+			continue
+		}
+		if _, err := h.Handle(context.Background(), &hook.HookInput{SessionID: "sess-1", CWD: root}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(started) != 1 || started[0] != "sess-1" {
+		t.Errorf("started = %v, want the injected start func called once", started)
+	}
+}
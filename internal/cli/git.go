@@ -0,0 +1,435 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/git/convention"
+	"github.com/modu-ai/moai-adk/internal/git/githook"
+	"github.com/modu-ai/moai-adk/pkg/models"
+)
+
+// GitHookTTY opens the terminal used to offer commit message fixes. Git
+// runs hooks without stdin, so the prompt goes through /dev/tty; opening it
+// fails when there is no controlling terminal (IDEs, CI). Tests replace it.
+var GitHookTTY = func() (io.ReadWriteCloser, error) {
+	return os.OpenFile("/dev/tty", os.O_RDWR, 0)
+}
+
+// zeroOID is the object name git passes for refs that do not exist.
+const zeroOID = "0000000000000000000000000000000000000000"
+
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Native git hooks for commit message conventions",
+	Long: `Install and run native git hooks that check commit messages against
+the configured git convention.
+
+The policy of each hook (enforce, warn or skip) comes from
+git_strategy.<mode>.hooks in .moai/config/sections/git-strategy.yaml.`,
+}
+
+func init() {
+	rootCmd.AddCommand(gitCmd)
+	gitCmd.AddCommand(newGitInstallHooksCmd())
+	gitCmd.AddCommand(newGitCommitMsgCmd())
+	gitCmd.AddCommand(newGitPrePushCmd())
+	gitCmd.AddCommand(newGitPrepareCommitMsgCmd())
+}
+
+func newGitInstallHooksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install-hooks",
+		Short: "Install commit-msg, pre-push and prepare-commit-msg hooks",
+		Long: `Install the commit-msg, pre-push and prepare-commit-msg git hooks into
+the current repository (honouring core.hooksPath).
+
+Existing hooks that were not written by moai are renamed to
+<name>.pre-moai and keep running before the moai checks.
+
+Example:
+  moai git install-hooks`,
+		Args: cobra.NoArgs,
+		RunE: runGitInstallHooks,
+	}
+	cmd.Flags().String("binary", "", "moai binary the hooks run (default: this executable)")
+	return cmd
+}
+
+func runGitInstallHooks(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+
+	bin, _ := cmd.Flags().GetString("binary")
+	if bin == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("locate moai binary: %w", err)
+		}
+		bin = exe
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	result, err := githook.Install(gitHookContext(cmd), cwd, bin)
+	if err != nil {
+		return err
+	}
+
+	hooks := loadWorkflowProjectConfig(cwd).GitStrategy.Hooks
+	lines := []string{
+		fmt.Sprintf("Directory: %s", result.Dir),
+		fmt.Sprintf("commit-msg:         %s", gitHookPolicy(hooks.CommitMsg)),
+		fmt.Sprintf("pre-push:           %s", gitHookPolicy(hooks.PrePush)),
+		fmt.Sprintf("prepare-commit-msg: %s", prepareCommitMsgPolicyLabel(hooks)),
+	}
+	for _, name := range result.Chained {
+		lines = append(lines, cliMuted.Render(fmt.Sprintf("Existing %s kept as %s%s", name, name, githook.ChainSuffix)))
+	}
+	_, _ = fmt.Fprintln(out, renderSuccessCard(fmt.Sprintf("Installed %d git hooks", len(result.Installed)), lines...))
+	return nil
+}
+
+func newGitCommitMsgCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "commit-msg <file>",
+		Short: "Validate a commit message file (git commit-msg hook)",
+		Long: `Validate the commit message in <file> against the configured convention.
+
+With the enforce policy an invalid message aborts the commit; with warn it
+is reported only. When a fix can be suggested it is offered on the terminal,
+or applied directly with --fix.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runGitCommitMsg,
+	}
+	cmd.Flags().Bool("fix", false, "Rewrite the message with the suggested fix without asking")
+	cmd.Flags().Bool("no-interactive", false, "Never prompt for a fix")
+	return cmd
+}
+
+func runGitCommitMsg(cmd *cobra.Command, args []string) error {
+	errOut := cmd.ErrOrStderr()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	cfg := loadWorkflowProjectConfig(cwd)
+	policy := gitHookPolicy(cfg.GitStrategy.Hooks.CommitMsg)
+	if policy == config.HookPolicySkip {
+		return nil
+	}
+
+	raw, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("read commit message: %w", err)
+	}
+	// Git aborts empty messages itself.
+	message := convention.CleanMessage(string(raw))
+	if message == "" || convention.IsGenerated(message) {
+		return nil
+	}
+
+	conv, err := loadProjectConvention(cwd, cfg)
+	if err != nil {
+		return err
+	}
+	result := convention.Validate(message, conv)
+	if result.Valid {
+		// Warning-level rules are reported without blocking the commit.
+		_, _ = fmt.Fprint(errOut, convention.FormatError(result, conv))
+		return nil
+	}
+	_, _ = fmt.Fprint(errOut, convention.FormatError(result, conv))
+
+	if fixed, ok := convention.Fix(message, conv); ok {
+		header := strings.SplitN(fixed, "\n", 2)[0]
+		apply, _ := cmd.Flags().GetBool("fix")
+		noInteractive, _ := cmd.Flags().GetBool("no-interactive")
+		if !apply && !noInteractive && !cfg.System.NonInteractive {
+			apply = confirmCommitFix(header)
+		}
+		if apply {
+			if err := os.WriteFile(args[0], []byte(fixed+"\n"), 0o644); err != nil {
+				return fmt.Errorf("rewrite commit message: %w", err)
+			}
+			_, _ = fmt.Fprintf(errOut, "%s Commit message rewritten: %s\n", symSuccess(), header)
+			return nil
+		}
+	}
+
+	if policy == config.HookPolicyEnforce {
+		return fmt.Errorf("commit message violates %s convention", conv.Name)
+	}
+	_, _ = fmt.Fprintln(errOut, cliWarn.Render("Committing anyway (commit_msg policy: warn)"))
+	return nil
+}
+
+// confirmCommitFix offers the suggested header on the terminal and reports
+// whether it was accepted. Without a terminal nothing is asked.
+func confirmCommitFix(header string) bool {
+	tty, err := GitHookTTY()
+	if err != nil {
+		return false
+	}
+	defer func() { _ = tty.Close() }()
+
+	_, _ = fmt.Fprintf(tty, "\nUse suggested message %q? [Y/n] ", header)
+	answer, err := bufio.NewReader(tty).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "" || answer == "y" || answer == "yes"
+}
+
+func newGitPrePushCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pre-push [remote] [url]",
+		Short: "Validate the commits being pushed (git pre-push hook)",
+		Long: `Validate the messages of all commits being pushed. Reads the pushed refs
+from stdin in git's pre-push format ("<local ref> <local oid> <remote ref>
+<remote oid>" per line). With the enforce policy violations abort the push.`,
+		Args:         cobra.MaximumNArgs(2),
+		SilenceUsage: true,
+		RunE:         runGitPrePush,
+	}
+}
+
+func runGitPrePush(cmd *cobra.Command, _ []string) error {
+	errOut := cmd.ErrOrStderr()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	cfg := loadWorkflowProjectConfig(cwd)
+	policy := gitHookPolicy(cfg.GitStrategy.Hooks.PrePush)
+	if policy == config.HookPolicySkip {
+		return nil
+	}
+
+	messages, err := pushedCommitMessages(gitHookContext(cmd), cwd, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	conv, err := loadProjectConvention(cwd, cfg)
+	if err != nil {
+		return err
+	}
+	results := make([]convention.ValidationResult, len(messages))
+	violations := 0
+	for i, msg := range messages {
+		results[i] = convention.Validate(msg, conv)
+		if !results[i].Valid {
+			violations++
+		}
+	}
+	if violations == 0 {
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(errOut, convention.FormatBatchSummary(results, conv))
+	if policy == config.HookPolicyEnforce {
+		return fmt.Errorf("%d commit(s) violate %s convention; reword them with 'git rebase -i'", violations, conv.Name)
+	}
+	_, _ = fmt.Fprintln(errOut, cliWarn.Render("Pushing anyway (pre_push policy: warn)"))
+	return nil
+}
+
+// pushedCommitMessages returns the messages of the commits a push would
+// send, skipping deleted refs and commits generated by git. Full messages
+// are returned so that body and footer rules apply.
+func pushedCommitMessages(ctx context.Context, dir string, in io.Reader) ([]string, error) {
+	var messages []string
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 || fields[1] == zeroOID {
+			continue
+		}
+		local, remote := fields[1], fields[3]
+
+		// New branches are compared against everything already on a remote.
+		args := []string{"log", "--format=%B%x1e", local, "--not", "--remotes"}
+		if remote != zeroOID {
+			args = []string{"log", "--format=%B%x1e", remote + ".." + local}
+		}
+		out, err := gitHookOutput(ctx, dir, args...)
+		if err != nil {
+			return nil, err
+		}
+		for message := range strings.SplitSeq(out, "\x1e") {
+			message = strings.TrimSpace(message)
+			if message != "" && !convention.IsGenerated(message) {
+				messages = append(messages, message)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read pushed refs: %w", err)
+	}
+	return messages, nil
+}
+
+func newGitPrepareCommitMsgCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "prepare-commit-msg <file> [source] [commit]",
+		Short: "Add convention hints to the commit template (git prepare-commit-msg hook)",
+		Long: `Add the active convention and examples of valid messages as comments to
+the commit message template when git opens an editor for a new message.`,
+		Args:         cobra.RangeArgs(1, 3),
+		SilenceUsage: true,
+		RunE:         runGitPrepareCommitMsg,
+	}
+}
+
+func runGitPrepareCommitMsg(_ *cobra.Command, args []string) error {
+	// Messages from -m, -F, merges, squashes and amends are left alone.
+	if len(args) > 1 && args[1] != "" && args[1] != "template" {
+		return nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	cfg := loadWorkflowProjectConfig(cwd)
+	if prepareCommitMsgPolicy(cfg.GitStrategy.Hooks) == config.HookPolicySkip {
+		return nil
+	}
+	conv, err := loadProjectConvention(cwd, cfg)
+	if err != nil {
+		return err
+	}
+
+	raw, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("read commit message: %w", err)
+	}
+	if bytes.Contains(raw, []byte("# moai: ")) {
+		return nil
+	}
+
+	var hint strings.Builder
+	fmt.Fprintf(&hint, "# moai: commit messages follow the %s convention", conv.Name)
+	if conv.MaxLength > 0 {
+		fmt.Fprintf(&hint, " (header max %d characters)", conv.MaxLength)
+	}
+	hint.WriteString(".\n")
+	for _, ex := range conv.Examples {
+		fmt.Fprintf(&hint, "#   %s\n", ex)
+	}
+	hint.WriteString("#\n")
+
+	// Insert the hint above git's own comments so it survives --cleanup=scissors.
+	content := string(raw)
+	at := len(content)
+	if i := strings.Index("\n"+content, "\n#"); i >= 0 {
+		at = i
+	}
+	content = content[:at] + hint.String() + content[at:]
+	if err := os.WriteFile(args[0], []byte(content), 0o644); err != nil {
+		return fmt.Errorf("write commit message: %w", err)
+	}
+	return nil
+}
+
+// gitHookPolicy normalizes a hooks policy; unknown values warn.
+func gitHookPolicy(policy string) string {
+	switch strings.ToLower(strings.TrimSpace(policy)) {
+	case config.HookPolicyEnforce:
+		return config.HookPolicyEnforce
+	case config.HookPolicySkip:
+		return config.HookPolicySkip
+	default:
+		return config.HookPolicyWarn
+	}
+}
+
+// prepareCommitMsgPolicy returns the policy of the prepare-commit-msg hook,
+// which follows commit_msg unless prepare_commit_msg is set.
+func prepareCommitMsgPolicy(hooks config.GitHooksConfig) string {
+	return gitHookPolicy(cmp.Or(hooks.PrepareCommitMsg, hooks.CommitMsg))
+}
+
+// prepareCommitMsgPolicyLabel describes the prepare-commit-msg policy and
+// where it comes from.
+func prepareCommitMsgPolicyLabel(hooks config.GitHooksConfig) string {
+	if hooks.PrepareCommitMsg == "" {
+		return prepareCommitMsgPolicy(hooks) + " (follows commit_msg)"
+	}
+	return prepareCommitMsgPolicy(hooks)
+}
+
+// loadProjectConvention loads the commit convention of the project.
+// Priority: MOAI_GIT_CONVENTION env var > git_convention.convention > auto.
+func loadProjectConvention(root string, cfg *config.Config) (*convention.Convention, error) {
+	name := cmp.Or(os.Getenv("MOAI_GIT_CONVENTION"), cfg.GitConvention.Convention, "auto")
+	return loadNamedConvention(root, name, cfg.GitConvention.Custom)
+}
+
+// loadNamedConvention loads a built-in, detected or commitlint convention
+// by name; "custom" loads the definition from git-convention.yaml.
+func loadNamedConvention(root, name string, custom models.CustomConventionConfig) (*convention.Convention, error) {
+	mgr := convention.NewManager(root)
+	var err error
+	if name == "custom" {
+		err = mgr.LoadCustom(convention.ConventionConfig{
+			Name:      cmp.Or(custom.Name, "custom"),
+			Pattern:   custom.Pattern,
+			Types:     custom.Types,
+			Scopes:    custom.Scopes,
+			MaxLength: custom.MaxLength,
+			Examples:  custom.Examples,
+			Rules:     custom.Rules,
+		}, custom.Import)
+	} else {
+		err = mgr.LoadConvention(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mgr.Convention(), nil
+}
+
+func gitHookContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// gitHookOutput runs git in dir and returns its stdout.
+func gitHookOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	c := exec.CommandContext(ctx, "git", args...)
+	c.Dir = dir
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
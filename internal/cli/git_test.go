@@ -0,0 +1,284 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modu-ai/moai-adk/internal/git/githook"
+)
+
+// fakeTTY answers a fix prompt and records what was asked.
+type fakeTTY struct {
+	io.Reader
+	prompt bytes.Buffer
+}
+
+func (f *fakeTTY) Write(p []byte) (int, error) { return f.prompt.Write(p) }
+func (f *fakeTTY) Close() error                { return nil }
+
+// setupGitHookRepo creates and enters a repository whose manual mode uses
+// the given commit_msg and pre_push policies.
+func setupGitHookRepo(t *testing.T, commitMsg, prePush string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	t.Chdir(root)
+	t.Setenv("MOAI_GIT_CONVENTION", "conventional-commits")
+	gitRun(t, "init", "-q")
+
+	sections := filepath.Join(root, ".moai", "config", "sections")
+	if err := os.MkdirAll(sections, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	yaml := "git_strategy:\n  mode: manual\n  manual:\n    hooks:\n      commit_msg: " + commitMsg +
+		"\n      pre_push: " + prePush + "\n"
+	if err := os.WriteFile(filepath.Join(sections, "git-strategy.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	origTTY := GitHookTTY
+	t.Cleanup(func() { GitHookTTY = origTTY })
+	GitHookTTY = func() (io.ReadWriteCloser, error) { return nil, os.ErrNotExist }
+	return root
+}
+
+func gitRun(t *testing.T, args ...string) string {
+	t.Helper()
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+	out, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeCommitMsg(t *testing.T, root, msg string) string {
+	t.Helper()
+	path := filepath.Join(root, ".git", "COMMIT_EDITMSG")
+	if err := os.WriteFile(path, []byte(msg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGitCommitMsg(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		message string
+		wantErr bool
+		wantOut string
+	}{
+		{"valid", "enforce", "feat(auth): add login\n# comment\n", false, ""},
+		{"enforce blocks", "enforce", "Added login\n", true, "[PATTERN]"},
+		{"warn reports", "warn", "Added login\n", false, "Committing anyway"},
+		{"skip ignores", "skip", "Added login\n", false, ""},
+		{"merge commits exempt", "enforce", "Merge branch 'main' into dev\n", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := setupGitHookRepo(t, tt.policy, "warn")
+			path := writeCommitMsg(t, root, tt.message)
+
+			out, err := runGithubTestCmd(t, newGitCommitMsgCmd(), path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("commit-msg error = %v, wantErr %v\n%s", err, tt.wantErr, out)
+			}
+			if tt.wantOut != "" && !strings.Contains(out, tt.wantOut) {
+				t.Errorf("output missing %q:\n%s", tt.wantOut, out)
+			}
+			if tt.wantOut == "" && out != "" {
+				t.Errorf("unexpected output:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestGitCommitMsg_Fix(t *testing.T) {
+	root := setupGitHookRepo(t, "enforce", "warn")
+
+	// Accepting the suggestion on the terminal rewrites the message.
+	tty := &fakeTTY{Reader: strings.NewReader("\n")}
+	GitHookTTY = func() (io.ReadWriteCloser, error) { return tty, nil }
+	path := writeCommitMsg(t, root, "Fix crash on empty config\n\nBody.\n")
+	if out, err := runGithubTestCmd(t, newGitCommitMsgCmd(), path); err != nil {
+		t.Fatalf("commit-msg error = %v\n%s", err, out)
+	}
+	if !strings.Contains(tty.prompt.String(), "fix: fix crash on empty config") {
+		t.Errorf("prompt = %q", tty.prompt.String())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "fix: fix crash on empty config\n\nBody.\n" {
+		t.Errorf("rewritten message = %q", data)
+	}
+
+	// Declining keeps the message and enforce blocks the commit.
+	tty = &fakeTTY{Reader: strings.NewReader("n\n")}
+	path = writeCommitMsg(t, root, "Fix crash\n")
+	if _, err := runGithubTestCmd(t, newGitCommitMsgCmd(), path); err == nil {
+		t.Error("declined fix should still block the commit")
+	}
+
+	// --fix applies the suggestion without a terminal.
+	GitHookTTY = func() (io.ReadWriteCloser, error) { return nil, os.ErrNotExist }
+	path = writeCommitMsg(t, root, "Add docs for hooks\n")
+	if out, err := runGithubTestCmd(t, newGitCommitMsgCmd(), path, "--fix"); err != nil {
+		t.Fatalf("commit-msg --fix error = %v\n%s", err, out)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "feat: add docs for hooks\n" {
+		t.Errorf("fixed message = %q", data)
+	}
+}
+
+func TestGitCommitMsg_CustomConvention(t *testing.T) {
+	root := setupGitHookRepo(t, "enforce", "warn")
+	t.Setenv("MOAI_GIT_CONVENTION", "")
+	writeTestFile(t, filepath.Join(root, ".commitlintrc.json"),
+		`{"extends": ["@commitlint/config-conventional"], "rules": {"scope-enum": [1, "always", ["api"]]}}`)
+	writeTestFile(t, filepath.Join(root, ".moai", "config", "sections", "git-convention.yaml"), `git_convention:
+  convention: custom
+  custom:
+    name: team
+    import: .commitlintrc.json
+    rules:
+      trailer-exists: [error, always, "Signed-off-by:"]
+`)
+
+	// Warning-level rules are reported without blocking.
+	path := writeCommitMsg(t, root, "feat(web): add login\n\nSigned-off-by: A <a@example.com>\n")
+	out, err := runGithubTestCmd(t, newGitCommitMsgCmd(), path)
+	if err != nil || !strings.Contains(out, "[WARN] scope-enum") {
+		t.Errorf("commit-msg error = %v\n%s", err, out)
+	}
+
+	path = writeCommitMsg(t, root, "feat(api): add login\n")
+	out, err = runGithubTestCmd(t, newGitCommitMsgCmd(), path)
+	if err == nil || !strings.Contains(out, "violates team convention") || !strings.Contains(out, "[RULE] trailer-exists") {
+		t.Errorf("commit-msg error = %v\n%s", err, out)
+	}
+}
+
+func TestGitPrePush(t *testing.T) {
+	setupGitHookRepo(t, "warn", "enforce")
+	gitRun(t, "commit", "-q", "--allow-empty", "-m", "feat: first")
+	base := gitRun(t, "rev-parse", "HEAD")
+	gitRun(t, "commit", "-q", "--allow-empty", "-m", "wip")
+	gitRun(t, "commit", "-q", "--allow-empty", "-m", "fix: second")
+	head := gitRun(t, "rev-parse", "HEAD")
+
+	push := func(stdin string) (string, error) {
+		cmd := newGitPrePushCmd()
+		cmd.SetIn(strings.NewReader(stdin))
+		return runGithubTestCmd(t, cmd, "origin", "git@example.com:app.git")
+	}
+
+	out, err := push("refs/heads/main " + head + " refs/heads/main " + base + "\n")
+	if err == nil || !strings.Contains(out, `"wip"`) || strings.Contains(out, "fix: second") {
+		t.Errorf("pre-push error = %v\n%s", err, out)
+	}
+
+	// Only commits not yet on the remote are checked.
+	gitRun(t, "commit", "-q", "--allow-empty", "-m", "docs: third")
+	next := gitRun(t, "rev-parse", "HEAD")
+	if out, err := push("refs/heads/main " + next + " refs/heads/main " + head + "\n"); err != nil {
+		t.Errorf("pre-push error = %v\n%s", err, out)
+	}
+
+	// Deleting a remote branch pushes no commits.
+	if out, err := push("(delete) " + zeroOID + " refs/heads/old " + head + "\n"); err != nil {
+		t.Errorf("delete push error = %v\n%s", err, out)
+	}
+}
+
+func TestGitPrepareCommitMsg(t *testing.T) {
+	root := setupGitHookRepo(t, "warn", "warn")
+	template := "\n# Please enter the commit message for your changes.\n"
+	path := writeCommitMsg(t, root, template)
+
+	if _, err := runGithubTestCmd(t, newGitPrepareCommitMsgCmd(), path); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	got := string(data)
+	if !strings.HasPrefix(got, "\n# moai: commit messages follow the conventional-commits convention") ||
+		!strings.HasSuffix(got, template[1:]) {
+		t.Errorf("template = %q", got)
+	}
+
+	// Running again, or for -m messages, leaves the file alone.
+	if _, err := runGithubTestCmd(t, newGitPrepareCommitMsgCmd(), path); err != nil {
+		t.Fatal(err)
+	}
+	path2 := writeCommitMsg(t, root, "feat: x\n")
+	if _, err := runGithubTestCmd(t, newGitPrepareCommitMsgCmd(), path2, "message"); err != nil {
+		t.Fatal(err)
+	}
+	if data2, _ := os.ReadFile(path2); string(data2) != "feat: x\n" {
+		t.Errorf("-m message changed: %q", data2)
+	}
+
+	// prepare_commit_msg: skip turns the hints off without touching commit_msg.
+	yaml := "git_strategy:\n  mode: manual\n  manual:\n    hooks:\n      commit_msg: enforce\n      prepare_commit_msg: skip\n"
+	if err := os.WriteFile(filepath.Join(root, ".moai", "config", "sections", "git-strategy.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path3 := writeCommitMsg(t, root, template)
+	if _, err := runGithubTestCmd(t, newGitPrepareCommitMsgCmd(), path3); err != nil {
+		t.Fatal(err)
+	}
+	if data3, _ := os.ReadFile(path3); string(data3) != template {
+		t.Errorf("template with prepare_commit_msg skip = %q", data3)
+	}
+}
+
+func TestGitInstallHooks(t *testing.T) {
+	root := setupGitHookRepo(t, "enforce", "warn")
+
+	out, err := runGithubTestCmd(t, newGitInstallHooksCmd(), "--binary", "/usr/local/bin/moai")
+	if err != nil {
+		t.Fatalf("install-hooks error = %v\n%s", err, out)
+	}
+	for _, name := range githook.Managed {
+		data, err := os.ReadFile(filepath.Join(root, ".git", "hooks", name))
+		if err != nil || !strings.Contains(string(data), "'/usr/local/bin/moai' git "+name) {
+			t.Errorf("hook %s = %q, %v", name, data, err)
+		}
+	}
+	if !strings.Contains(out, "Installed 3 git hooks") || !strings.Contains(out, "prepare-commit-msg: enforce (follows commit_msg)") {
+		t.Errorf("output:\n%s", out)
+	}
+}
+
+func TestGitHooks_RunUnderViolatedVersionPin(t *testing.T) {
+	root := setupGitHookRepo(t, "enforce", "enforce")
+	t.Setenv("MOAI_UPDATE_CHANNEL", "")
+	writeSystemYAML(t, root, "  required_version: \"<1.0.0\"\n")
+
+	template := "\n# Please enter the commit message for your changes.\n"
+	path := writeCommitMsg(t, root, template)
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+	rootCmd.SetArgs([]string{"git", "prepare-commit-msg", path})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("prepare-commit-msg under a violated pin: %v\n%s", err, buf.String())
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "# moai: ") {
+		t.Errorf("hook did not run: %q", data)
+	}
+}
@@ -11,7 +11,10 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 
+	"github.com/modu-ai/moai-adk/internal/core/quality"
+	"github.com/modu-ai/moai-adk/internal/forge"
 	"github.com/modu-ai/moai-adk/internal/github"
+	"github.com/modu-ai/moai-adk/internal/gitlab"
 	"github.com/modu-ai/moai-adk/internal/workflow"
 )
 
@@ -25,6 +28,52 @@ var GithubSpecLinkerFactory = func(projectRoot string) (github.SpecLinker, error
 	return github.NewSpecLinker(projectRoot)
 }
 
+// GithubExecFunc runs gh commands for the review and merge subcommands.
+// Nil uses the gh binary; tests replace it with a fake.
+var GithubExecFunc github.ExecFunc
+
+// ForgeFactory creates the code hosting provider selected by
+// git_strategy.provider. Tests replace this with a factory that returns a
+// fake provider.
+var ForgeFactory = func(projectRoot string) (forge.Forge, error) {
+	cfg := loadWorkflowProjectConfig(projectRoot).GitStrategy
+	switch strings.ToLower(cfg.Provider) {
+	case "", forge.ProviderGitHub:
+		return github.NewForge(projectRoot, GithubExecFunc), nil
+	case forge.ProviderGitLab:
+		gl, err := gitlab.New(context.Background(), gitlab.Config{
+			InstanceURL: cfg.GitLabInstanceURL,
+			Token:       os.Getenv("GITLAB_TOKEN"),
+			Root:        projectRoot,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return gl, nil
+	default:
+		return nil, fmt.Errorf("provider %q: %w", cfg.Provider, forge.ErrUnknownProvider)
+	}
+}
+
+// GithubQualityGateFactory creates the quality gate used to review pull
+// requests. Tests replace this with a factory that returns a mock.
+var GithubQualityGateFactory = func(projectRoot string) quality.Gate {
+	cfg := loadWorkflowProjectConfig(projectRoot).Quality
+	lsp := noLSPDiagnostics{}
+	return quality.NewTrustGate(quality.QualityConfig{
+		DevelopmentMode: quality.DevelopmentMode(cfg.DevelopmentMode),
+		EnforceQuality:  cfg.EnforceQuality,
+	}, []quality.Validator{
+		quality.NewTestedValidator(lsp, 0, 0), // coverage is reported by CI checks
+		quality.NewReadableValidator(lsp),
+		quality.NewUnderstandableValidator(lsp, 10, true, true),
+		quality.NewSecuredValidator(lsp),
+	})
+}
+
+// githubCheckPollInterval is how often merge --wait-checks polls CI status.
+var githubCheckPollInterval = github.DefaultCheckPollInterval
+
 // GitHub CLI styles.
 var (
 	ghPrimary = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#C45A3C", Dark: "#DA7756"})
@@ -63,15 +112,25 @@ func ghInfoCard(title, content string) string {
 }
 
 var githubCmd = &cobra.Command{
-	Use:   "github",
-	Short: "GitHub integration commands",
-	Long:  "Commands for GitHub issue parsing, SPEC linking, and workflow automation.",
+	Use:     "forge",
+	Aliases: []string{"github"},
+	Short:   "GitHub and GitLab integration commands",
+	Long: `Commands for issue parsing, SPEC linking, and pull/merge request automation.
+
+The provider is selected by git_strategy.provider (github or gitlab) in
+.moai/config/sections/git-strategy.yaml. GitHub uses the gh CLI; GitLab
+uses the REST API v4 at gitlab.instance_url with the GITLAB_TOKEN
+environment variable.`,
 }
 
 func init() {
 	rootCmd.AddCommand(githubCmd)
 	githubCmd.AddCommand(newParseIssueCmd())
 	githubCmd.AddCommand(newLinkSpecCmd())
+	githubCmd.AddCommand(newReviewCmd())
+	githubCmd.AddCommand(newMergeCmd())
+	githubCmd.AddCommand(newFixCmd())
+	githubCmd.AddCommand(newDashboardCmd())
 }
 
 func newParseIssueCmd() *cobra.Command {
@@ -79,10 +138,10 @@ func newParseIssueCmd() *cobra.Command {
 		Use:   "parse-issue <number>",
 		Short: "Parse a GitHub issue",
 		Long: `Parse a GitHub issue and display its contents.
-Uses the gh CLI to fetch issue data from the current repository.
+Fetches issue data from the provider of the current repository.
 
 Example:
-  moai github parse-issue 123`,
+  moai forge parse-issue 123`,
 		Args: cobra.ExactArgs(1),
 		RunE: runParseIssue,
 	}
@@ -102,7 +161,11 @@ func runParseIssue(cmd *cobra.Command, args []string) error {
 		if cwdErr != nil {
 			return fmt.Errorf("get working directory: %w", cwdErr)
 		}
-		parser = github.NewIssueParser(cwd)
+		f, forgeErr := ForgeFactory(cwd)
+		if forgeErr != nil {
+			return forgeErr
+		}
+		parser = f
 	}
 
 	ctx := cmd.Context()
@@ -159,7 +222,7 @@ func newLinkSpecCmd() *cobra.Command {
 The mapping is stored in .moai/github-spec-registry.json.
 
 Example:
-  moai github link-spec 123 SPEC-ISSUE-123`,
+  moai forge link-spec 123 SPEC-ISSUE-123`,
 		Args: cobra.ExactArgs(2),
 		RunE: runLinkSpec,
 	}
@@ -201,3 +264,206 @@ func runLinkSpec(cmd *cobra.Command, args []string) error {
 	))
 	return nil
 }
+
+func newReviewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review <pr-number>",
+		Short: "Review a pull request against quality gates and CI checks",
+		Long: `Run the TRUST 5 quality gates and CI/CD checks for a pull request
+and submit the resulting decision (approve, request changes or comment)
+as a review. Use --dry-run to print the review without posting it.
+
+Example:
+  moai forge review 42
+  moai forge review 42 --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: runGithubReview,
+	}
+	cmd.Flags().String("spec", "", "SPEC ID the pull request implements")
+	cmd.Flags().Bool("dry-run", false, "Print the review without posting it")
+	return cmd
+}
+
+func runGithubReview(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	number, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid PR number %q: %w", args[0], err)
+	}
+	specID, _ := cmd.Flags().GetString("spec")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	gh, err := ForgeFactory(cwd)
+	if err != nil {
+		return err
+	}
+	cr := forge.ChangeRequestName(gh)
+	reviewer, err := github.NewPRReviewer(gh, GithubQualityGateFactory(cwd), nil)
+	if err != nil {
+		return fmt.Errorf("create reviewer: %w", err)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	report, err := reviewer.Review(ctx, number, specID, nil)
+	if err != nil {
+		return fmt.Errorf("review %s #%d: %w", cr, number, err)
+	}
+
+	footer := ghMuted.Render("Dry run: review not posted")
+	if !dryRun {
+		if err := gh.PRReview(ctx, number, report.Decision, report.Summary); err != nil {
+			return fmt.Errorf("post review: %w", err)
+		}
+		footer = fmt.Sprintf("Review posted to %s #%d", cr, number)
+	}
+
+	_, _ = fmt.Fprintln(out, ghInfoCard(
+		fmt.Sprintf("%s #%d Review: %s", cr, number, report.Decision),
+		strings.TrimSpace(report.Summary)+"\n\n"+footer,
+	))
+	return nil
+}
+
+func newMergeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "merge <pr-number>",
+		Short: "Merge a pull request once review and CI prerequisites pass",
+		Long: `Merge a pull request after verifying that it is mergeable, its CI/CD
+checks passed and the automated review approves it. Once merged, the issue
+linked to the SPEC (or named by an issue-N head branch) is closed.
+
+Example:
+  moai forge merge 42
+  moai forge merge 42 --method squash --wait-checks`,
+		Args: cobra.ExactArgs(1),
+		RunE: runGithubMerge,
+	}
+	cmd.Flags().String("method", string(github.MergeMethodMerge), "Merge method: merge, squash or rebase")
+	cmd.Flags().Bool("wait-checks", false, "Wait for pending CI/CD checks before merging")
+	cmd.Flags().Duration("checks-timeout", 30*time.Minute, "Maximum time to wait for CI/CD checks")
+	cmd.Flags().Bool("delete-branch", false, "Delete the head branch after merge")
+	cmd.Flags().Bool("skip-review", false, "Merge without requiring an approving review")
+	cmd.Flags().Bool("no-close-issue", false, "Do not close the linked issue after merge")
+	cmd.Flags().String("spec", "", "SPEC ID the pull request implements")
+	return cmd
+}
+
+func runGithubMerge(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	number, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid PR number %q: %w", args[0], err)
+	}
+	methodFlag, _ := cmd.Flags().GetString("method")
+	method := github.MergeMethod(methodFlag)
+	switch method {
+	case github.MergeMethodMerge, github.MergeMethodSquash, github.MergeMethodRebase:
+	default:
+		return fmt.Errorf("invalid merge method %q: use merge, squash or rebase", methodFlag)
+	}
+	waitChecks, _ := cmd.Flags().GetBool("wait-checks")
+	checksTimeout, _ := cmd.Flags().GetDuration("checks-timeout")
+	deleteBranch, _ := cmd.Flags().GetBool("delete-branch")
+	skipReview, _ := cmd.Flags().GetBool("skip-review")
+	noCloseIssue, _ := cmd.Flags().GetBool("no-close-issue")
+	specID, _ := cmd.Flags().GetString("spec")
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	gh, err := ForgeFactory(cwd)
+	if err != nil {
+		return err
+	}
+	cr := forge.ChangeRequestName(gh)
+	details, err := gh.PRView(ctx, number)
+	if err != nil {
+		return err
+	}
+
+	if waitChecks {
+		_, _ = fmt.Fprintf(out, "Waiting for CI/CD checks on %s #%d...\n", cr, number)
+		waitCtx, cancel := context.WithTimeout(ctx, checksTimeout)
+		status, err := github.WaitForChecks(waitCtx, gh, number, githubCheckPollInterval)
+		cancel()
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "CI/CD checks: %s\n", status.Overall)
+	}
+
+	reviewer, err := github.NewPRReviewer(gh, GithubQualityGateFactory(cwd), nil)
+	if err != nil {
+		return fmt.Errorf("create reviewer: %w", err)
+	}
+	merger, err := github.NewPRMerger(gh, reviewer, nil)
+	if err != nil {
+		return fmt.Errorf("create merger: %w", err)
+	}
+
+	result, err := merger.Merge(ctx, number, github.MergeOptions{
+		AutoMerge:     true,
+		Method:        method,
+		DeleteBranch:  deleteBranch,
+		RequireReview: !skipReview,
+		RequireChecks: true,
+		SpecID:        specID,
+	})
+	if err != nil {
+		return err
+	}
+
+	lines := []string{
+		fmt.Sprintf("Method:  %s", result.Method),
+		fmt.Sprintf("Branch:  %s -> %s", details.HeadBranch, details.BaseBranch),
+	}
+	if result.BranchDeleted {
+		lines = append(lines, "Head branch deleted")
+	}
+	if !noCloseIssue {
+		lines = append(lines, closeMergedPRIssue(ctx, gh, cwd, details, specID))
+	}
+
+	_, _ = fmt.Fprintln(out, ghSuccessCard(fmt.Sprintf("Merged %s #%d", cr, number), lines...))
+	return nil
+}
+
+// closeMergedPRIssue closes the issue resolved by a merged pull request and
+// returns a status line. Failures are reported but do not fail the merge.
+func closeMergedPRIssue(ctx context.Context, f forge.Forge, projectRoot string, pr *github.PRDetails, specID string) string {
+	// Without a readable registry, only issue branches can be resolved.
+	linker, _ := GithubSpecLinkerFactory(projectRoot)
+	issue := github.ResolveLinkedIssue(linker, specID, pr.HeadBranch)
+	if issue == 0 {
+		return ghMuted.Render("No linked issue to close")
+	}
+
+	// GitLab references merge requests as !N and issues as #N.
+	ref := fmt.Sprintf("#%d", pr.Number)
+	if f.Provider() == forge.ProviderGitLab {
+		ref = fmt.Sprintf("!%d", pr.Number)
+	}
+	comment := fmt.Sprintf("Resolved by %s, merged into `%s`.", ref, pr.BaseBranch)
+	if _, err := f.Close(ctx, issue, comment); err != nil {
+		return cliWarn.Render(fmt.Sprintf("Could not close issue #%d: %v", issue, err))
+	}
+	return fmt.Sprintf("Issue:   #%d closed", issue)
+}
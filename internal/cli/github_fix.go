@@ -0,0 +1,351 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/core/git"
+	"github.com/modu-ai/moai-adk/internal/defs"
+	gitbranch "github.com/modu-ai/moai-adk/internal/git"
+	"github.com/modu-ai/moai-adk/internal/github"
+	"github.com/modu-ai/moai-adk/internal/tmux"
+	"github.com/modu-ai/moai-adk/internal/workflow"
+)
+
+// GithubTmuxDetector reports whether tmux is available for `moai forge fix`.
+// Nil uses the system tmux; tests replace it with a mock.
+var GithubTmuxDetector tmux.Detector
+
+// GithubTmuxSessionManager creates the tmux session for `moai forge fix`.
+// Nil uses the system tmux; tests replace it with a mock.
+var GithubTmuxSessionManager tmux.SessionManager
+
+// fixSessionTimeFormat names fix sessions so that they sort chronologically.
+const fixSessionTimeFormat = "2006-01-02-15-04-05"
+
+// fixSession records how issues were fanned out over tmux panes so that
+// `moai forge dashboard` can report on them later.
+type fixSession struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	Panes     []fixPane `json:"panes"`
+}
+
+// fixPane is one tmux pane working through its issues in order.
+type fixPane struct {
+	Issues []fixIssue `json:"issues"`
+}
+
+// fixIssue is an issue prepared for a headless workflow run.
+type fixIssue struct {
+	Number   int    `json:"number"`
+	Title    string `json:"title"`
+	SpecID   string `json:"specId"`
+	Branch   string `json:"branch"`
+	Worktree string `json:"worktree"`
+}
+
+// command returns the shell command that runs the pane's workflows one
+// after another; a failed workflow does not stop the next one.
+func (p fixPane) command() string {
+	runs := make([]string, len(p.Issues))
+	for i, issue := range p.Issues {
+		runs[i] = "moai workflow run " + issue.SpecID
+	}
+	return strings.Join(runs, "; ")
+}
+
+// fixSessionDir returns where fix sessions are recorded.
+func fixSessionDir(projectRoot string) string {
+	return filepath.Join(projectRoot, defs.MoAIDir, defs.LogsSubdir, "workflow", "sessions")
+}
+
+func newFixCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "fix <issue-number>...",
+		Short: "Fix GitHub issues in parallel worktrees and tmux panes",
+		Long: `Parse each issue, create a SPEC-ISSUE-<n> worktree on an issue branch,
+link the issue to the SPEC and open a tmux session that runs
+'moai workflow run' for every issue.
+
+At most workflow.team.max_teammates panes run at once; further issues
+queue behind them in the same panes. Follow progress with
+'moai forge dashboard'.
+
+Example:
+  moai forge fix 12 15 17`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runGithubFix,
+	}
+}
+
+func runGithubFix(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	var numbers []int
+	for _, arg := range args {
+		n, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid issue number %q", arg)
+		}
+		if !slices.Contains(numbers, n) {
+			numbers = append(numbers, n)
+		}
+	}
+
+	detector := GithubTmuxDetector
+	if detector == nil {
+		detector = tmux.NewDetector()
+	}
+	if !detector.IsAvailable() {
+		return fmt.Errorf("moai forge fix runs issues in tmux panes: %w", tmux.ErrTmuxNotFound)
+	}
+
+	mgr, err := workflowWorktrees()
+	if err != nil {
+		return err
+	}
+	root := mgr.Root()
+
+	parser := GithubIssueParser
+	if parser == nil {
+		f, err := ForgeFactory(root)
+		if err != nil {
+			return err
+		}
+		parser = f
+	}
+	linker, err := GithubSpecLinkerFactory(root)
+	if err != nil {
+		return fmt.Errorf("create spec linker: %w", err)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	issues := make([]fixIssue, 0, len(numbers))
+	for _, n := range numbers {
+		issue, err := parser.ParseIssue(ctx, n)
+		if err != nil {
+			return fmt.Errorf("parse issue #%d: %w", n, err)
+		}
+		fi, err := prepareFixWorktree(mgr, issue)
+		if err != nil {
+			return err
+		}
+		if err := linker.LinkIssueToSpec(n, fi.SpecID); err != nil && !errors.Is(err, github.ErrMappingExists) {
+			return fmt.Errorf("link issue #%d: %w", n, err)
+		}
+		issues = append(issues, fi)
+		_, _ = fmt.Fprintf(out, "  %s #%d %s %s\n", symSuccess(), n, fi.SpecID, ghMuted.Render(fi.Branch))
+	}
+
+	maxPanes := loadWorkflowProjectConfig(root).Workflow.MaxTeammates
+	if maxPanes <= 0 {
+		maxPanes = config.DefaultMaxTeammates
+	}
+	now := time.Now()
+	session := &fixSession{
+		Name:      "github-issues-" + now.Format(fixSessionTimeFormat),
+		CreatedAt: now,
+		Panes:     planFixPanes(issues, maxPanes),
+	}
+
+	sessionCfg := &tmux.SessionConfig{Name: session.Name}
+	for _, pane := range session.Panes {
+		sessionCfg.Panes = append(sessionCfg.Panes, tmux.PaneConfig{
+			SpecID:  pane.Issues[0].SpecID,
+			Command: pane.command(),
+		})
+	}
+	sm := GithubTmuxSessionManager
+	if sm == nil {
+		sm = tmux.NewSessionManager()
+	}
+	result, err := sm.Create(ctx, sessionCfg)
+	if err != nil {
+		return fmt.Errorf("create tmux session: %w", err)
+	}
+	session.Name = result.SessionName
+
+	if err := saveFixSession(root, session); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(out, ghSuccessCard(
+		fmt.Sprintf("Fixing %d issue(s) in %d pane(s)", len(issues), result.PaneCount),
+		fmt.Sprintf("Session:   %s", result.SessionName),
+		fmt.Sprintf("Attach:    tmux attach -t %s", result.SessionName),
+		"Dashboard: moai forge dashboard",
+	))
+	return nil
+}
+
+// prepareFixWorktree returns the SPEC-ISSUE worktree for an issue, creating
+// it on an issue branch (e.g. fix/issue-12) when it does not exist yet.
+func prepareFixWorktree(mgr git.WorktreeManager, issue *github.Issue) (fixIssue, error) {
+	fi := fixIssue{
+		Number: issue.Number,
+		Title:  issue.Title,
+		SpecID: fmt.Sprintf("SPEC-ISSUE-%d", issue.Number),
+	}
+	if wt, err := workflow.FindSpecWorktree(mgr, fi.SpecID); err == nil {
+		fi.Branch, fi.Worktree = wt.Branch, wt.Path
+		return fi, nil
+	}
+
+	labels := make([]string, len(issue.Labels))
+	for i, l := range issue.Labels {
+		labels[i] = l.Name
+	}
+	branch, err := gitbranch.FormatIssueBranch(labels, issue.Number)
+	if err != nil {
+		return fi, err
+	}
+	fi.Branch = branch
+	fi.Worktree = filepath.Join(mgr.Root(), defs.MoAIDir, "worktrees", fi.SpecID)
+	if err := mgr.Add(fi.Worktree, branch); err != nil {
+		return fi, fmt.Errorf("create worktree for issue #%d: %w", issue.Number, err)
+	}
+	return fi, nil
+}
+
+// planFixPanes spreads issues round-robin over at most maxPanes panes.
+func planFixPanes(issues []fixIssue, maxPanes int) []fixPane {
+	panes := make([]fixPane, min(len(issues), maxPanes))
+	for i, issue := range issues {
+		p := &panes[i%len(panes)]
+		p.Issues = append(p.Issues, issue)
+	}
+	return panes
+}
+
+func saveFixSession(projectRoot string, session *fixSession) error {
+	dir := fixSessionDir(projectRoot)
+	if err := os.MkdirAll(dir, defs.DirPerm); err != nil {
+		return fmt.Errorf("create session directory: %w", err)
+	}
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal fix session: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, session.Name+".json"), data, defs.FilePerm); err != nil {
+		return fmt.Errorf("write fix session: %w", err)
+	}
+	return nil
+}
+
+// loadFixSession reads the named fix session, or the latest one when name
+// is empty.
+func loadFixSession(projectRoot, name string) (*fixSession, error) {
+	dir := fixSessionDir(projectRoot)
+	if name == "" {
+		entries, err := os.ReadDir(dir)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("read fix sessions: %w", err)
+		}
+		for _, e := range entries {
+			if n, ok := strings.CutSuffix(e.Name(), ".json"); ok && n > name {
+				name = n
+			}
+		}
+		if name == "" {
+			return nil, fmt.Errorf("no fix sessions found; start one with 'moai forge fix <issue>...'")
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("read fix session %s: %w", name, err)
+	}
+	var session fixSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("parse fix session %s: %w", name, err)
+	}
+	return &session, nil
+}
+
+func newDashboardCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "dashboard [session]",
+		Short: "Show per-pane progress of a 'moai forge fix' session",
+		Long: `Summarize the workflow status of every issue in a 'moai forge fix'
+session, grouped by tmux pane. Defaults to the most recent session.
+
+Example:
+  moai forge dashboard`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runGithubDashboard,
+	}
+}
+
+func runGithubDashboard(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	mgr, err := workflowWorktrees()
+	if err != nil {
+		return err
+	}
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	}
+	session, err := loadFixSession(mgr.Root(), name)
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	var done, total int
+	for i, pane := range session.Panes {
+		lines = append(lines, cliPrimary.Render(fmt.Sprintf("Pane %d", i)))
+		for _, issue := range pane.Issues {
+			total++
+			// Issues queued behind another in their pane have no run state yet.
+			state, _ := workflow.LoadRunState(issue.Worktree, issue.SpecID)
+			if state != nil && state.Completed() {
+				done++
+			}
+			lines = append(lines, "  "+renderFixIssueStatus(issue, state))
+		}
+	}
+
+	header := fmt.Sprintf("%d/%d issues completed  %s", done, total,
+		cliMuted.Render("tmux attach -t "+session.Name))
+	_, _ = fmt.Fprintln(out, renderCard(session.Name, header+"\n\n"+strings.Join(lines, "\n")))
+	return nil
+}
+
+// renderFixIssueStatus summarizes one issue's workflow: the first phase that
+// is not completed decides the status, and usage is summed over phases.
+func renderFixIssueStatus(issue fixIssue, state *workflow.RunState) string {
+	label := fmt.Sprintf("#%-5d %-16s", issue.Number, issue.SpecID)
+	if state == nil {
+		return fmt.Sprintf("%s %s queued", label, workflowStatusIcon(workflow.PhaseStatusPending))
+	}
+
+	var tokens int64
+	var cost float64
+	status, detail := workflow.PhaseStatusCompleted, "completed"
+	for _, phase := range workflow.Phases {
+		rec := state.Phase(phase)
+		tokens += rec.Tokens
+		cost += rec.CostUSD
+		if status == workflow.PhaseStatusCompleted && rec.Status != workflow.PhaseStatusCompleted {
+			status, detail = rec.Status, fmt.Sprintf("%s %s", phase, rec.Status)
+		}
+	}
+	return fmt.Sprintf("%s %s %-14s %d tokens  $%.2f", label, workflowStatusIcon(status), detail, tokens, cost)
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modu-ai/moai-adk/internal/core/git"
+	"github.com/modu-ai/moai-adk/internal/github"
+	"github.com/modu-ai/moai-adk/internal/tmux"
+	"github.com/modu-ai/moai-adk/internal/workflow"
+)
+
+type mockTmuxDetector struct{ available bool }
+
+func (d *mockTmuxDetector) IsAvailable() bool        { return d.available }
+func (d *mockTmuxDetector) Version() (string, error) { return "3.4", nil }
+
+type mockTmuxSessionManager struct{ cfg *tmux.SessionConfig }
+
+func (m *mockTmuxSessionManager) Create(_ context.Context, cfg *tmux.SessionConfig) (*tmux.SessionResult, error) {
+	m.cfg = cfg
+	return &tmux.SessionResult{SessionName: cfg.Name, PaneCount: len(cfg.Panes)}, nil
+}
+
+// fixWorktreeStub records worktrees added by `moai forge fix`.
+type fixWorktreeStub struct {
+	workflowWorktreeStub
+	added map[string]string
+}
+
+func (s *fixWorktreeStub) Add(path, branch string) error {
+	s.added[path] = branch
+	s.worktrees = append(s.worktrees, git.Worktree{Path: path, Branch: branch})
+	return nil
+}
+
+func setupGithubFix(t *testing.T, maxTeammates int) (*fixWorktreeStub, *mockTmuxSessionManager, map[int]string) {
+	t.Helper()
+	root := t.TempDir()
+	sections := filepath.Join(root, ".moai", "config", "sections")
+	if err := os.MkdirAll(sections, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	yaml := fmt.Sprintf("workflow:\n  team:\n    max_teammates: %d\n", maxTeammates)
+	if err := os.WriteFile(filepath.Join(sections, "workflow.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Issue 3 already has a worktree from an earlier session.
+	existing := filepath.Join(root, ".moai", "worktrees", "SPEC-ISSUE-3")
+	wt := &fixWorktreeStub{
+		workflowWorktreeStub: workflowWorktreeStub{
+			root:      root,
+			worktrees: []git.Worktree{{Path: existing, Branch: "feat/issue-3"}},
+		},
+		added: map[string]string{},
+	}
+	sm := &mockTmuxSessionManager{}
+	links := map[int]string{}
+
+	origDeps, origParser, origLinker := deps, GithubIssueParser, GithubSpecLinkerFactory
+	origDetector, origSM := GithubTmuxDetector, GithubTmuxSessionManager
+	t.Cleanup(func() {
+		deps, GithubIssueParser, GithubSpecLinkerFactory = origDeps, origParser, origLinker
+		GithubTmuxDetector, GithubTmuxSessionManager = origDetector, origSM
+	})
+	deps = &Dependencies{GitWorktree: wt}
+	GithubIssueParser = &mockGHIssueParser{
+		parseFunc: func(_ context.Context, n int) (*github.Issue, error) {
+			issue := &github.Issue{Number: n, Title: fmt.Sprintf("Issue %d", n)}
+			if n%2 == 0 {
+				issue.Labels = []github.Label{{Name: "bug"}}
+			}
+			return issue, nil
+		},
+	}
+	GithubSpecLinkerFactory = func(string) (github.SpecLinker, error) {
+		return &mockGHSpecLinker{linkFunc: func(n int, specID string) error {
+			if _, ok := links[n]; ok {
+				return github.ErrMappingExists
+			}
+			links[n] = specID
+			return nil
+		}}, nil
+	}
+	GithubTmuxDetector = &mockTmuxDetector{available: true}
+	GithubTmuxSessionManager = sm
+	return wt, sm, links
+}
+
+func TestGithubFix(t *testing.T) {
+	wt, sm, links := setupGithubFix(t, 2)
+
+	out, err := runGithubTestCmd(t, newFixCmd(), "2", "#3", "5", "2")
+	if err != nil {
+		t.Fatalf("fix error = %v\n%s", err, out)
+	}
+
+	if len(wt.added) != 2 ||
+		wt.added[filepath.Join(wt.root, ".moai", "worktrees", "SPEC-ISSUE-2")] != "fix/issue-2" ||
+		wt.added[filepath.Join(wt.root, ".moai", "worktrees", "SPEC-ISSUE-5")] != "feat/issue-5" {
+		t.Errorf("added worktrees = %v", wt.added)
+	}
+	if len(links) != 3 || links[3] != "SPEC-ISSUE-3" {
+		t.Errorf("links = %v", links)
+	}
+
+	// Three issues over two panes: the first pane runs two in sequence.
+	if sm.cfg == nil || len(sm.cfg.Panes) != 2 {
+		t.Fatalf("session = %+v", sm.cfg)
+	}
+	if got := sm.cfg.Panes[0].Command; got != "moai workflow run SPEC-ISSUE-2; moai workflow run SPEC-ISSUE-5" {
+		t.Errorf("pane 0 command = %q", got)
+	}
+	if sm.cfg.Panes[1].SpecID != "SPEC-ISSUE-3" {
+		t.Errorf("pane 1 = %+v", sm.cfg.Panes[1])
+	}
+	if !strings.Contains(out, "tmux attach -t "+sm.cfg.Name) {
+		t.Errorf("output:\n%s", out)
+	}
+
+	// The dashboard reports each issue from its run state.
+	state := workflow.NewRunState("SPEC-ISSUE-3")
+	for _, p := range workflow.Phases {
+		state.Phase(p).Status = workflow.PhaseStatusCompleted
+		state.Phase(p).Tokens = 100
+	}
+	if err := state.Save(filepath.Join(wt.root, ".moai", "worktrees", "SPEC-ISSUE-3")); err != nil {
+		t.Fatal(err)
+	}
+	state = workflow.NewRunState("SPEC-ISSUE-2")
+	state.Phase(workflow.PhasePlan).Status = workflow.PhaseStatusCompleted
+	state.Phase(workflow.PhaseRun).Status = workflow.PhaseStatusFailed
+	if err := state.Save(filepath.Join(wt.root, ".moai", "worktrees", "SPEC-ISSUE-2")); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err = runGithubTestCmd(t, newDashboardCmd())
+	if err != nil {
+		t.Fatalf("dashboard error = %v", err)
+	}
+	for _, want := range []string{"1/3 issues completed", "Pane 1", "run failed", "queued", "300 tokens"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dashboard missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGithubFix_RequiresTmux(t *testing.T) {
+	wt, sm, _ := setupGithubFix(t, 2)
+	GithubTmuxDetector = &mockTmuxDetector{available: false}
+
+	if _, err := runGithubTestCmd(t, newFixCmd(), "2"); err == nil || !strings.Contains(err.Error(), "tmux") {
+		t.Fatalf("fix error = %v, want tmux not found", err)
+	}
+	if len(wt.added) != 0 || sm.cfg != nil {
+		t.Error("fix should not create worktrees or sessions without tmux")
+	}
+}
+
+func TestGithubDashboard_NoSessions(t *testing.T) {
+	setupGithubFix(t, 2)
+	if _, err := runGithubTestCmd(t, newDashboardCmd()); err == nil || !strings.Contains(err.Error(), "moai forge fix") {
+		t.Errorf("dashboard error = %v", err)
+	}
+}
+
+func TestPlanFixPanes(t *testing.T) {
+	issues := make([]fixIssue, 5)
+	for i := range issues {
+		issues[i].SpecID = fmt.Sprintf("SPEC-ISSUE-%d", i+1)
+	}
+	panes := planFixPanes(issues, 2)
+	if len(panes) != 2 || len(panes[0].Issues) != 3 || len(panes[1].Issues) != 2 {
+		t.Errorf("panes = %+v", panes)
+	}
+	if panes := planFixPanes(issues[:1], 4); len(panes) != 1 {
+		t.Errorf("single issue panes = %d, want 1", len(panes))
+	}
+}
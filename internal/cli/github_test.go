@@ -4,10 +4,22 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/forge"
 	"github.com/modu-ai/moai-adk/internal/github"
+	"github.com/modu-ai/moai-adk/internal/gitlab"
 	"github.com/modu-ai/moai-adk/internal/workflow"
 )
 
@@ -306,6 +318,8 @@ func TestGithubCmd_HasSubcommands(t *testing.T) {
 	expected := map[string]bool{
 		"parse-issue": false,
 		"link-spec":   false,
+		"review":      false,
+		"merge":       false,
 	}
 
 	for _, cmd := range githubCmd.Commands() {
@@ -354,3 +368,323 @@ func TestGithubCmd_LinkSpecRequiresTwoArgs(t *testing.T) {
 	}
 	t.Error("link-spec subcommand not found")
 }
+
+// --- Tests for review and merge subcommands ---
+
+// fakeGH answers gh commands for a single open PR and records every call.
+type fakeGH struct {
+	checks      []string // successive "pr checks" outputs; the last one repeats
+	headBranch  string
+	mergeErr    error
+	calls       []string
+	checksCalls int
+}
+
+func (f *fakeGH) exec(_ context.Context, _ string, args ...string) (string, error) {
+	call := strings.Join(args, " ")
+	f.calls = append(f.calls, call)
+	switch {
+	case strings.HasPrefix(call, "pr view"):
+		return `{"number":42,"title":"Fix login","state":"OPEN","mergeable":"MERGEABLE","headRefName":"` +
+			f.headBranch + `","baseRefName":"main"}`, nil
+	case strings.HasPrefix(call, "pr checks"):
+		out := f.checks[min(f.checksCalls, len(f.checks)-1)]
+		f.checksCalls++
+		return out, nil
+	case strings.HasPrefix(call, "pr merge"):
+		return "", f.mergeErr
+	}
+	return "", nil
+}
+
+func (f *fakeGH) called(prefix string) []string {
+	var matched []string
+	for _, c := range f.calls {
+		if strings.HasPrefix(c, prefix) {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+const (
+	ghChecksPass    = `[{"name":"test","status":"completed","conclusion":"success"}]`
+	ghChecksPending = `[{"name":"test","status":"in_progress","conclusion":""}]`
+	ghChecksFail    = `[{"name":"test","status":"completed","conclusion":"failure"}]`
+)
+
+func withFakeGH(t *testing.T, f *fakeGH) {
+	t.Helper()
+	origExec, origLinker := GithubExecFunc, GithubSpecLinkerFactory
+	t.Cleanup(func() { GithubExecFunc, GithubSpecLinkerFactory = origExec, origLinker })
+	GithubExecFunc = f.exec
+	GithubSpecLinkerFactory = func(string) (github.SpecLinker, error) {
+		return &mockGHSpecLinker{getIssueFunc: func(specID string) (int, error) {
+			if specID == "SPEC-AUTH-001" {
+				return 7, nil
+			}
+			return 0, github.ErrMappingNotFound
+		}}, nil
+	}
+	t.Chdir(t.TempDir())
+}
+
+func runGithubTestCmd(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestGithubReview(t *testing.T) {
+	tests := []struct {
+		name     string
+		checks   string
+		args     []string
+		wantPost string
+	}{
+		{"approve", ghChecksPass, []string{"42"}, "pr review 42 --approve --body"},
+		{"request changes", ghChecksFail, []string{"42"}, "pr review 42 --request-changes --body"},
+		{"dry run", ghChecksPass, []string{"42", "--dry-run"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeGH{checks: []string{tt.checks}, headBranch: "feature/SPEC-AUTH-001"}
+			withFakeGH(t, f)
+
+			out, err := runGithubTestCmd(t, newReviewCmd(), tt.args...)
+			if err != nil {
+				t.Fatalf("review error = %v\n%s", err, out)
+			}
+			posted := f.called("pr review")
+			if tt.wantPost == "" {
+				if len(posted) != 0 || !strings.Contains(out, "Dry run") {
+					t.Errorf("dry run posted %v\n%s", posted, out)
+				}
+				return
+			}
+			if len(posted) != 1 || !strings.HasPrefix(posted[0], tt.wantPost) || !strings.Contains(posted[0], "## PR #42 Review") {
+				t.Errorf("posted reviews = %q, want %q", posted, tt.wantPost)
+			}
+		})
+	}
+}
+
+func TestGithubMerge_ClosesLinkedIssue(t *testing.T) {
+	f := &fakeGH{checks: []string{ghChecksPending, ghChecksPass}, headBranch: "feature/SPEC-AUTH-001"}
+	withFakeGH(t, f)
+	origInterval := githubCheckPollInterval
+	defer func() { githubCheckPollInterval = origInterval }()
+	githubCheckPollInterval = time.Millisecond
+
+	out, err := runGithubTestCmd(t, newMergeCmd(), "42", "--method", "squash", "--wait-checks", "--delete-branch")
+	if err != nil {
+		t.Fatalf("merge error = %v\n%s", err, out)
+	}
+	if merges := f.called("pr merge"); len(merges) != 1 || merges[0] != "pr merge 42 --squash --delete-branch" {
+		t.Errorf("merge calls = %q", merges)
+	}
+	if f.checksCalls < 2 {
+		t.Errorf("checks polled %d times, want to wait past pending", f.checksCalls)
+	}
+	if closes := f.called("issue close"); len(closes) != 1 || closes[0] != "issue close 7" {
+		t.Errorf("issue close calls = %q", closes)
+	}
+	if !strings.Contains(out, "Merged PR #42") || !strings.Contains(out, "#7 closed") {
+		t.Errorf("output:\n%s", out)
+	}
+}
+
+func TestGithubMerge_IssueBranch(t *testing.T) {
+	f := &fakeGH{checks: []string{ghChecksPass}, headBranch: "fix/issue-19"}
+	withFakeGH(t, f)
+
+	if out, err := runGithubTestCmd(t, newMergeCmd(), "42"); err != nil {
+		t.Fatalf("merge error = %v\n%s", err, out)
+	}
+	if closes := f.called("issue close"); len(closes) != 1 || closes[0] != "issue close 19" {
+		t.Errorf("issue close calls = %q", closes)
+	}
+
+	f = &fakeGH{checks: []string{ghChecksPass}, headBranch: "fix/issue-19"}
+	withFakeGH(t, f)
+	if out, err := runGithubTestCmd(t, newMergeCmd(), "42", "--no-close-issue"); err != nil {
+		t.Fatalf("merge error = %v\n%s", err, out)
+	}
+	if closes := f.called("issue"); len(closes) != 0 {
+		t.Errorf("--no-close-issue still touched issues: %q", closes)
+	}
+}
+
+func TestGithubMerge_Blocked(t *testing.T) {
+	tests := []struct {
+		name    string
+		checks  string
+		args    []string
+		wantErr error
+	}{
+		{"failing checks", ghChecksFail, []string{"42"}, github.ErrMergeBlocked},
+		{"pending checks", ghChecksPending, []string{"42"}, github.ErrMergeBlocked},
+		{"checks timeout", ghChecksPending, []string{"42", "--wait-checks", "--checks-timeout", "20ms"}, github.ErrChecksPending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeGH{checks: []string{tt.checks}, headBranch: "fix/issue-19"}
+			withFakeGH(t, f)
+			origInterval := githubCheckPollInterval
+			defer func() { githubCheckPollInterval = origInterval }()
+			githubCheckPollInterval = 5 * time.Millisecond
+
+			_, err := runGithubTestCmd(t, newMergeCmd(), tt.args...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("merge error = %v, want %v", err, tt.wantErr)
+			}
+			if len(f.called("pr merge")) != 0 || len(f.called("issue")) != 0 {
+				t.Errorf("blocked merge still ran: %q", f.calls)
+			}
+		})
+	}
+}
+
+func TestGithubMerge_InvalidMethod(t *testing.T) {
+	f := &fakeGH{checks: []string{ghChecksPass}}
+	withFakeGH(t, f)
+
+	if _, err := runGithubTestCmd(t, newMergeCmd(), "42", "--method", "fast-forward"); err == nil {
+		t.Fatal("expected error for invalid merge method")
+	}
+	if len(f.calls) != 0 {
+		t.Errorf("gh called with invalid method: %q", f.calls)
+	}
+}
+
+// --- Tests for the GitLab provider ---
+
+// setupForgeGitLab points git_strategy at a fake GitLab serving merge
+// request !42 from fix/issue-19 and returns the requests it receives.
+func setupForgeGitLab(t *testing.T) func() []string {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []string
+	routes := map[string]string{
+		"GET /merge_requests/42": `{"iid":42,"title":"Fix login","state":"opened","detailed_merge_status":"mergeable",` +
+			`"source_branch":"fix/issue-19","target_branch":"main"}`,
+		"GET /merge_requests/42/pipelines": `[{"id":5,"status":"success"}]`,
+		"GET /pipelines/5/jobs":            `[{"name":"test","status":"success"}]`,
+		"PUT /merge_requests/42/merge":     `{"state":"merged"}`,
+		"POST /issues/19/notes":            `{}`,
+		"PUT /issues/19":                   `{}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "glpat-test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		key := r.Method + " " + strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4/projects/group%2Fapp")
+		mu.Lock()
+		requests = append(requests, key)
+		mu.Unlock()
+		resp, ok := routes[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			resp = `{"message":"404 Not found"}`
+		}
+		_, _ = w.Write([]byte(resp))
+	}))
+	t.Cleanup(srv.Close)
+
+	f := &fakeGH{}
+	withFakeGH(t, f)
+	t.Setenv("GITLAB_TOKEN", "glpat-test")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", "git@gitlab.example.com:group/app.git"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	sections := filepath.Join(".moai", "config", "sections")
+	if err := os.MkdirAll(sections, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	yaml := "git_strategy:\n  provider: gitlab\n  gitlab:\n    instance_url: " + srv.URL + "\n"
+	if err := os.WriteFile(filepath.Join(sections, "git-strategy.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if len(f.calls) > 0 {
+			t.Errorf("gitlab provider ran gh commands: %q", f.calls)
+		}
+	})
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(requests)
+	}
+}
+
+func TestForgeMerge_GitLab(t *testing.T) {
+	requests := setupForgeGitLab(t)
+
+	out, err := runGithubTestCmd(t, newMergeCmd(), "42", "--method", "squash")
+	if err != nil {
+		t.Fatalf("merge error = %v\n%s", err, out)
+	}
+	got := strings.Join(requests(), "\n")
+	for _, want := range []string{"PUT /merge_requests/42/merge", "POST /issues/19/notes", "PUT /issues/19"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing request %q in:\n%s", want, got)
+		}
+	}
+	if !strings.Contains(out, "Merged MR #42") || !strings.Contains(out, "#19 closed") {
+		t.Errorf("output:\n%s", out)
+	}
+}
+
+func TestForgeFactory_GitLabRequiresToken(t *testing.T) {
+	setupForgeGitLab(t)
+	t.Setenv("GITLAB_TOKEN", "")
+
+	if _, err := runGithubTestCmd(t, newMergeCmd(), "42"); !errors.Is(err, gitlab.ErrTokenMissing) {
+		t.Errorf("merge error = %v, want ErrTokenMissing", err)
+	}
+}
+
+func TestForgeFactory_Provider(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "glpat-test")
+	root := t.TempDir()
+	sections := filepath.Join(root, ".moai", "config", "sections")
+	if err := os.MkdirAll(sections, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	setProvider := func(provider string) {
+		t.Helper()
+		yaml := "git_strategy:\n  provider: " + provider + "\n"
+		if err := os.WriteFile(filepath.Join(sections, "git-strategy.yaml"), []byte(yaml), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, provider := range []string{`""`, "github", "GitHub"} {
+		setProvider(provider)
+		f, err := ForgeFactory(root)
+		if err != nil || f.Provider() != forge.ProviderGitHub || forge.ChangeRequestName(f) != "PR" {
+			t.Errorf("ForgeFactory(%s) = %v, %v, want github", provider, f, err)
+		}
+	}
+
+	setProvider("bitbucket")
+	if _, err := ForgeFactory(root); !errors.Is(err, forge.ErrUnknownProvider) {
+		t.Errorf("ForgeFactory(bitbucket) error = %v, want ErrUnknownProvider", err)
+	}
+}
+
+func TestGithubCmd_ForgeAlias(t *testing.T) {
+	if githubCmd.Name() != "forge" || !githubCmd.HasAlias("github") {
+		t.Errorf("command = %q aliases %v, want forge with github alias", githubCmd.Name(), githubCmd.Aliases)
+	}
+}
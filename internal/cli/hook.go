@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/modu-ai/moai-adk/internal/hook"
+	"github.com/modu-ai/moai-adk/pkg/version"
 )
 
 var hookCmd = &cobra.Command{
@@ -86,6 +88,10 @@ func runHookEvent(cmd *cobra.Command, event hook.EventType) error {
 		return fmt.Errorf("read hook input: %w", err)
 	}
 
+	if handled, err := enforceHookRequiredVersion(event, input); handled {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
 	defer cancel()
 
@@ -111,6 +117,47 @@ func runHookEvent(cmd *cobra.Command, event hook.EventType) error {
 	return nil
 }
 
+// enforceHookRequiredVersion keeps a binary violating moai.required_version
+// from running hook handlers: it writes requiredVersionOutput instead, or
+// fails with the violation. It reports whether the event was handled.
+func enforceHookRequiredVersion(event hook.EventType, input *hook.HookInput) (bool, error) {
+	violation := checkRequiredVersion(hookProjectRoot(input), version.GetVersion())
+	if !errors.Is(violation, errVersionConstraint) {
+		return false, nil
+	}
+	output := requiredVersionOutput(event, violation)
+	if output == nil {
+		return true, violation
+	}
+	return true, deps.HookProtocol.WriteOutput(os.Stdout, output)
+}
+
+// hookProjectRoot returns the project a hook event was sent for.
+func hookProjectRoot(input *hook.HookInput) string {
+	if input.CWD == "" {
+		return "."
+	}
+	return input.CWD
+}
+
+// requiredVersionOutput is what a hook reports instead of running its
+// handlers when the binary violates moai.required_version. SessionStart
+// tells the user, and PreToolUse and PermissionRequest ask for every tool
+// call because the security policy cannot be applied. Other events return
+// nil and fail with violation.
+func requiredVersionOutput(event hook.EventType, violation error) *hook.HookOutput {
+	switch event {
+	case hook.EventSessionStart:
+		return hook.NewSessionOutput(true, violation.Error())
+	case hook.EventPreToolUse:
+		return hook.NewAskOutput(violation.Error())
+	case hook.EventPermissionRequest:
+		return hook.NewPermissionRequestOutput(hook.DecisionAsk, violation.Error())
+	default:
+		return nil
+	}
+}
+
 // runHookList displays all registered hook handlers.
 func runHookList(cmd *cobra.Command, _ []string) error {
 	out := cmd.OutOrStdout()
@@ -177,6 +224,10 @@ func runAgentHook(cmd *cobra.Command, args []string) error {
 		event = hook.EventPreToolUse
 	}
 
+	if handled, err := enforceHookRequiredVersion(event, input); handled {
+		return err
+	}
+
 	// Add action to input for handler identification
 	input.Data = fmt.Appendf(nil, `{"action":"%s"}`, action)
 
@@ -136,7 +136,7 @@ func TestHookDepsWiring_AllHandlersRegistered(t *testing.T) {
 		hook.EventPostToolUse,
 		hook.EventStop,
 		hook.EventPreCompact,
-		hook.EventSubagentStop, // registered via RankSessionHandler conditionally
+		hook.EventSubagentStop,
 		// New events:
 		hook.EventPostToolUseFailure,
 		hook.EventNotification,
@@ -148,9 +148,7 @@ func TestHookDepsWiring_AllHandlersRegistered(t *testing.T) {
 	}
 
 	// Events that may not have a handler (conditionally registered).
-	optionalEvents := map[hook.EventType]bool{
-		hook.EventSubagentStop: true, // RankSessionHandler is conditional
-	}
+	optionalEvents := map[hook.EventType]bool{}
 
 	for _, event := range allEvents {
 		handlers := deps.HookRegistry.Handlers(event)
@@ -169,7 +167,6 @@ func TestHookValidEventTypes_Complete(t *testing.T) {
 		hook.EventPostToolUseFailure,
 		hook.EventNotification,
 		hook.EventSubagentStart,
-		hook.EventUserPromptSubmit,
 		hook.EventPermissionRequest,
 		hook.EventTeammateIdle,
 		hook.EventTaskCompleted,
@@ -318,9 +315,13 @@ func TestHookValidEventTypes_AllHaveSubcommands(t *testing.T) {
 
 	// Collect event subcommand names (exclude utility subcommands like "list", "agent", "pre-push").
 	utilitySubcmds := map[string]bool{
-		"list":     true,
-		"agent":    true,
-		"pre-push": true,
+		"list":      true,
+		"agent":     true,
+		"pre-push":  true,
+		"git-watch": true,
+		"log":       true,
+		"record":    true,
+		"replay":    true,
 	}
 
 	for _, cmd := range hookCmd.Commands() {
@@ -348,7 +349,6 @@ func TestHookDepsWiring_HandlerCounts(t *testing.T) {
 		hook.EventPostToolUseFailure,
 		hook.EventNotification,
 		hook.EventSubagentStart,
-		hook.EventUserPromptSubmit,
 		hook.EventPermissionRequest,
 		hook.EventTeammateIdle,
 		hook.EventTaskCompleted,
@@ -363,6 +363,11 @@ func TestHookDepsWiring_HandlerCounts(t *testing.T) {
 		}
 	}
 
+	// UserPromptSubmit has the prompt logger and the git watcher report.
+	if n := len(deps.HookRegistry.Handlers(hook.EventUserPromptSubmit)); n != 2 {
+		t.Errorf("event %q: got %d handlers, want 2", hook.EventUserPromptSubmit, n)
+	}
+
 	// SessionStart may have multiple handlers (session start + auto-update + optional rank).
 	sessionStartHandlers := deps.HookRegistry.Handlers(hook.EventSessionStart)
 	if len(sessionStartHandlers) < 2 {
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/gitwatch"
+	"github.com/modu-ai/moai-adk/internal/hook"
+	"github.com/modu-ai/moai-adk/internal/statusline"
+)
+
+func init() {
+	hookCmd.AddCommand(gitWatchCmd)
+}
+
+var gitWatchCmd = &cobra.Command{
+	Use:   "git-watch",
+	Short: "Run the background git event watcher",
+	Long: `Run the git event watcher in the current directory. The watcher is
+started by the SessionStart hook and records branch switches, new commits,
+rebases and external edits until SessionEnd stops it.`,
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runGitWatch,
+}
+
+func runGitWatch(cmd *cobra.Command, _ []string) error {
+	root, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return gitwatch.NewWatcher(root, gitwatch.DefaultInterval).Run(ctx)
+}
+
+// GitWatchStartFactory creates the callback the SessionStart hook uses to
+// launch the git watcher. Tests replace it so that no watcher is spawned.
+var GitWatchStartFactory = buildGitWatchStartFunc
+
+// buildGitWatchStartFunc creates the callback that launches the watcher as
+// a detached `moai hook git-watch` process.
+func buildGitWatchStartFunc() hook.GitWatchStartFunc {
+	return func(projectRoot, sessionID string) error {
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("locate moai binary: %w", err)
+		}
+		_, err = gitwatch.Start(projectRoot, sessionID, []string{exe, "hook", "git-watch"})
+		return err
+	}
+}
+
+// gitEventsProvider adapts the git watcher store to the statusline.
+type gitEventsProvider struct {
+	projectRoot string
+}
+
+func newGitEventsProvider(projectRoot string) *gitEventsProvider {
+	return &gitEventsProvider{projectRoot: projectRoot}
+}
+
+// CollectGitEvents briefly describes the changes made outside Claude since
+// the last turn. It returns nil when there are none.
+func (p *gitEventsProvider) CollectGitEvents(_ context.Context) (*statusline.GitEventsData, error) {
+	events, err := gitwatch.NewStore(p.projectRoot).Pending()
+	if err != nil {
+		return nil, err
+	}
+	summary := gitwatch.Brief(events)
+	if summary == "" {
+		return nil, nil
+	}
+	return &statusline.GitEventsData{Summary: summary, Available: true}, nil
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/core/project"
+	"github.com/modu-ai/moai-adk/internal/hook/audit"
+)
+
+// hookLogReasonChars bounds the reason shown per record in the table.
+const hookLogReasonChars = 60
+
+func init() {
+	hookCmd.AddCommand(hookLogCmd)
+
+	hookLogCmd.Flags().String("event", "", "Only include this event, e.g. PreToolUse")
+	hookLogCmd.Flags().String("decision", "", "Only include this decision: allow, deny, ask, rewrite, block, error, timeout")
+	hookLogCmd.Flags().String("handler", "", "Only include handlers whose name contains this text")
+	hookLogCmd.Flags().String("session", "", "Only include sessions starting with this ID")
+	hookLogCmd.Flags().String("since", "7d", "Start of range: a date (YYYY-MM-DD), Nd for N days ago, 'today' or 'all'")
+	hookLogCmd.Flags().Int("limit", 50, "Show at most the N most recent records (0 = all)")
+	hookLogCmd.Flags().String("format", "table", "Output format: table, json")
+}
+
+var hookLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Query the hook audit log",
+	Long: `Query the audit log of hook decisions in .moai/logs/hooks.
+
+Every hook handler invocation is recorded with its event, handler,
+decision, reason, rule ID, tool name, a digest of the tool input and its
+latency. The summary counts decisions, latency per event and the rules
+behind deny, ask, allow and rewrite decisions.
+
+Examples:
+  moai hook log
+  moai hook log --decision deny --since 30d
+  moai hook log --event PreToolUse --session 3f2a9c1e --format json`,
+	Args: cobra.NoArgs,
+	RunE: runHookLog,
+}
+
+// hookLogReport is the JSON output of `moai hook log`.
+type hookLogReport struct {
+	Since   string         `json:"since,omitempty"`
+	Summary audit.Summary  `json:"summary"`
+	Records []audit.Record `json:"records"`
+}
+
+func runHookLog(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	since, err := parseUsageSince(sinceFlag, usageNow())
+	if err != nil {
+		return err
+	}
+	format, _ := cmd.Flags().GetString("format")
+	if format != "table" && format != "json" {
+		return fmt.Errorf("invalid --format %q: want table or json", format)
+	}
+	limit, _ := cmd.Flags().GetInt("limit")
+	filter := audit.Filter{Since: since}
+	filter.Event, _ = cmd.Flags().GetString("event")
+	filter.Decision, _ = cmd.Flags().GetString("decision")
+	filter.Handler, _ = cmd.Flags().GetString("handler")
+	filter.Session, _ = cmd.Flags().GetString("session")
+
+	root, err := project.FindProjectRoot()
+	if err != nil {
+		return fmt.Errorf("find project root: %w", err)
+	}
+	settings := loadWorkflowProjectConfig(root).HookAudit
+	records, err := audit.Read(audit.ResolveDir(root, cmp.Or(settings.Dir, config.DefaultHookAuditDir)), filter)
+	if err != nil {
+		return err
+	}
+
+	report := hookLogReport{Summary: audit.Summarize(records), Records: records}
+	if !since.IsZero() {
+		report.Since = since.Format(time.DateOnly)
+	}
+	if limit > 0 && len(report.Records) > limit {
+		report.Records = report.Records[len(report.Records)-limit:]
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	if report.Summary.Total == 0 {
+		msg := "No hook decisions recorded"
+		if !settings.Enabled {
+			msg += "; the audit log is disabled in hooks.yaml"
+		}
+		_, _ = fmt.Fprintln(out, renderInfoCard("Hook audit log", cliMuted.Render(msg+".")))
+		return nil
+	}
+	printHookLogSummary(out, report)
+	_, _ = fmt.Fprintln(out)
+	printHookLogTable(out, report.Records)
+	return nil
+}
+
+// printHookLogSummary renders decision counts, latency per event and the
+// most frequent rules.
+func printHookLogSummary(out io.Writer, report hookLogReport) {
+	s := report.Summary
+	rangeLabel := "all time"
+	if report.Since != "" {
+		rangeLabel = "since " + report.Since
+	}
+
+	var decisions []string
+	for _, d := range []string{audit.DecisionAllow, audit.DecisionDeny, audit.DecisionAsk, audit.DecisionRewrite, audit.DecisionBlock, audit.DecisionError, audit.DecisionTimeout} {
+		if n := s.ByDecision[d]; n > 0 {
+			decisions = append(decisions, fmt.Sprintf("%d %s", n, d))
+		}
+	}
+	pairs := []kvPair{
+		{"Range", rangeLabel},
+		{"Records", fmt.Sprintf("%d from %d session(s)", s.Total, s.Sessions)},
+		{"Decisions", strings.Join(decisions, ", ")},
+	}
+	for _, e := range s.Events {
+		pairs = append(pairs, kvPair{e.Event, fmt.Sprintf("%d calls, p50 %.1fms, p95 %.1fms, max %.1fms", e.Count, e.P50MS, e.P95MS, e.MaxMS)})
+	}
+	if len(s.Rules) > 0 {
+		var rules []string
+		for _, r := range s.Rules[:min(len(s.Rules), 5)] {
+			rules = append(rules, fmt.Sprintf("%s (%d)", r.RuleID, r.Count))
+		}
+		pairs = append(pairs, kvPair{"Top rules", strings.Join(rules, ", ")})
+	}
+	_, _ = fmt.Fprintln(out, renderCard("Hook audit log", renderKeyValueLines(pairs)))
+}
+
+// printHookLogTable renders one record per row, oldest first.
+func printHookLogTable(out io.Writer, records []audit.Record) {
+	header := fmt.Sprintf("%-19s  %-18s  %-28s  %-8s  %-18s  %-8s  %9s  %s",
+		"TIME", "EVENT", "HANDLER", "DECISION", "RULE", "TOOL", "LATENCY", "REASON")
+	_, _ = fmt.Fprintln(out, cliMuted.Render(header))
+
+	for _, r := range records {
+		reason := strings.Join(strings.Fields(r.Reason), " ")
+		if len(reason) > hookLogReasonChars {
+			reason = strings.ToValidUTF8(reason[:hookLogReasonChars], "") + "…"
+		}
+		_, _ = fmt.Fprintf(out, "%-19s  %-18s  %-28s  %-8s  %-18s  %-8s  %7.1fms  %s\n",
+			r.Time.Local().Format(time.DateTime),
+			r.Event,
+			strings.TrimPrefix(r.Handler, "hook."),
+			r.Decision,
+			cmp.Or(r.RuleID, "-"),
+			cmp.Or(r.ToolName, "-"),
+			r.LatencyMS,
+			reason,
+		)
+	}
+}
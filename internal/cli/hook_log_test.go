@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/hook/audit"
+)
+
+func runHookLogCmd(t *testing.T, flags map[string]string) (string, error) {
+	t.Helper()
+	cmd := &cobra.Command{RunE: runHookLog}
+	cmd.Flags().AddFlagSet(hookLogCmd.Flags())
+	for k, v := range flags {
+		if err := cmd.Flags().Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() {
+		for k := range flags {
+			f := cmd.Flags().Lookup(k)
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		}
+	}()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetContext(context.Background())
+	err := cmd.RunE(cmd, nil)
+	return buf.String(), err
+}
+
+func TestRunHookLog(t *testing.T) {
+	root, _ := filepath.EvalSymlinks(t.TempDir())
+	if err := os.MkdirAll(filepath.Join(root, ".moai"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(root)
+
+	now := time.Now()
+	l := &audit.Log{Dir: filepath.Join(root, ".moai", "logs", "hooks")}
+	err := l.Append(
+		audit.Record{Time: now.AddDate(0, 0, -30), SessionID: "sess-old", Event: "PreToolUse", Handler: "hook.preToolHandler", Decision: audit.DecisionDeny, RuleID: "file-protected-1"},
+		audit.Record{Time: now.Add(-time.Minute), SessionID: "sess-a", Event: "PreToolUse", Handler: "hook.preToolHandler", Decision: audit.DecisionDeny, Reason: "Dangerous command blocked: rm -rf", RuleID: "bash-dangerous-0", ToolName: "Bash", LatencyMS: 1.2},
+		audit.Record{Time: now, SessionID: "sess-a", Event: "PreToolUse", Handler: "hook.preToolHandler", Decision: audit.DecisionAllow, ToolName: "Read", LatencyMS: 0.4},
+		audit.Record{Time: now, SessionID: "sess-b", Event: "Stop", Handler: "hook.stopHandler", Decision: audit.DecisionAllow, LatencyMS: 3},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runHookLogCmd(t, nil)
+	if err != nil {
+		t.Fatalf("hook log: %v", err)
+	}
+	for _, want := range []string{"3 from 2 session(s)", "2 allow, 1 deny", "2 calls, p50 0.4ms, p95 1.2ms", "bash-dangerous-0 (1)", "preToolHandler", "Dangerous command blocked"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "file-protected-1") {
+		t.Errorf("record older than --since listed:\n%s", out)
+	}
+
+	out, err = runHookLogCmd(t, map[string]string{"decision": "deny", "since": "all", "format": "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report hookLogReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out)
+	}
+	if report.Summary.Total != 2 || len(report.Records) != 2 || report.Records[0].SessionID != "sess-old" {
+		t.Errorf("json report = %+v", report)
+	}
+
+	out, err = runHookLogCmd(t, map[string]string{"session": "sess-b", "limit": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "stopHandler") || strings.Contains(out, "preToolHandler") {
+		t.Errorf("session filter output:\n%s", out)
+	}
+}
+
+func TestRunHookLog_Empty(t *testing.T) {
+	root, _ := filepath.EvalSymlinks(t.TempDir())
+	if err := os.MkdirAll(filepath.Join(root, ".moai"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(root)
+
+	out, err := runHookLogCmd(t, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "No hook decisions recorded.") {
+		t.Errorf("output = %q", out)
+	}
+}
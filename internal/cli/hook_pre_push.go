@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/modu-ai/moai-adk/internal/git/convention"
+	"github.com/modu-ai/moai-adk/pkg/models"
 )
 
 func init() {
@@ -44,9 +45,8 @@ func runPrePush(cmd *cobra.Command, _ []string) error {
 	}
 
 	// Load convention configuration.
-	convName := resolveConventionName()
-	mgr := convention.NewManager(repoPath)
-	if err := mgr.LoadConvention(convName); err != nil {
+	conv, err := loadNamedConvention(repoPath, resolveConventionName(), resolveCustomConvention())
+	if err != nil {
 		return fmt.Errorf("pre-push: load convention: %w", err)
 	}
 
@@ -62,8 +62,10 @@ func runPrePush(cmd *cobra.Command, _ []string) error {
 	}
 
 	// Validate each message.
-	results := mgr.ValidateMessages(input)
-	conv := mgr.Convention()
+	results := make([]convention.ValidationResult, len(input))
+	for i, msg := range input {
+		results[i] = convention.Validate(msg, conv)
+	}
 
 	violations := 0
 	for _, r := range results {
@@ -111,6 +113,17 @@ func resolveConventionName() string {
 	return "auto"
 }
 
+// resolveCustomConvention returns the custom convention definition from
+// configuration, used when the convention name is "custom".
+func resolveCustomConvention() models.CustomConventionConfig {
+	if deps != nil && deps.Config != nil {
+		if cfg := deps.Config.Get(); cfg != nil {
+			return cfg.GitConvention.Custom
+		}
+	}
+	return models.CustomConventionConfig{}
+}
+
 // isEnforceOnPushEnabled checks whether convention enforcement is enabled.
 // Priority: MOAI_ENFORCE_ON_PUSH env var > config > default false.
 func isEnforceOnPushEnabled() bool {
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/core/project"
+	"github.com/modu-ai/moai-adk/internal/hook"
+	"github.com/modu-ai/moai-adk/internal/hook/replay"
+)
+
+func init() {
+	hookCmd.AddCommand(hookRecordCmd)
+	hookCmd.AddCommand(hookReplayCmd)
+
+	hookRecordCmd.Flags().String("dir", replay.DefaultDir, "Directory fixtures are written to")
+	hookRecordCmd.Flags().StringSlice("event", []string{string(hook.EventPreToolUse), string(hook.EventPermissionRequest)}, "Events to record, or 'all'")
+	hookRecordCmd.Flags().Bool("stop", false, "Stop the active recording session")
+
+	hookReplayCmd.Flags().Bool("update", false, "Rewrite the expected decision of every fixture with the current one")
+	hookReplayCmd.Flags().String("event", "", "Only replay fixtures of this event")
+}
+
+var hookRecordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Record hook inputs as replay fixtures",
+	Long: `Start recording the hook inputs of this project as fixtures for
+'moai hook replay'. While recording, every dispatched event is saved with
+the decision it produced until 'moai hook record --stop'.
+
+Credential-like fields, known token formats and the project root are
+redacted before a fixture is written. Identical inputs share one fixture.
+
+Examples:
+  moai hook record
+  moai hook record --event PreToolUse,UserPromptSubmit --dir testdata/hooks
+  moai hook record --stop`,
+	Args: cobra.NoArgs,
+	RunE: runHookRecord,
+}
+
+var hookReplayCmd = &cobra.Command{
+	Use:   "replay [dir]",
+	Short: "Replay recorded fixtures and diff their decisions",
+	Long: `Run recorded hook fixtures through the current handlers and compare
+each decision and rule with the recorded expectation. The command fails
+when any decision changed, so it can guard policy changes in CI.
+
+Handlers run for real: prefer fixtures of events without side effects,
+such as PreToolUse and PermissionRequest. With --update the current
+decisions become the new expectations.
+
+Examples:
+  moai hook replay
+  moai hook replay testdata/hooks --event PreToolUse
+  moai hook replay --update`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHookReplay,
+}
+
+func runHookRecord(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+	root, err := project.FindProjectRoot()
+	if err != nil {
+		return fmt.Errorf("find project root: %w", err)
+	}
+
+	if stop, _ := cmd.Flags().GetBool("stop"); stop {
+		session, err := replay.Stop(root)
+		if err != nil {
+			return err
+		}
+		if session == nil {
+			_, _ = fmt.Fprintln(out, renderInfoCard("Hook recording", cliMuted.Render("No recording session is active.")))
+			return nil
+		}
+		fixtures, err := replay.Load(session.FixtureDir(root))
+		if err != nil {
+			return err
+		}
+		recorded := 0
+		for _, f := range fixtures {
+			if !f.RecordedAt.Before(session.Started) {
+				recorded++
+			}
+		}
+		_, _ = fmt.Fprintln(out, renderCard("Hook recording stopped", renderKeyValueLines([]kvPair{
+			{"Fixtures", fmt.Sprintf("%d recorded since %s", recorded, session.Started.Local().Format(time.DateTime))},
+			{"Directory", session.Dir},
+		})))
+		return nil
+	}
+
+	dir, _ := cmd.Flags().GetString("dir")
+	events, _ := cmd.Flags().GetStringSlice("event")
+	if slices.Contains(events, "all") {
+		events = nil
+	}
+	for _, e := range events {
+		if !hook.IsValidEventType(hook.EventType(e)) {
+			return fmt.Errorf("invalid --event %q", e)
+		}
+	}
+	session := replay.Session{Started: time.Now(), Dir: dir, Events: events}
+	if err := replay.Start(root, session); err != nil {
+		return err
+	}
+	recorded := "all events"
+	if len(events) > 0 {
+		recorded = strings.Join(events, ", ")
+	}
+	_, _ = fmt.Fprintln(out, renderCard("Hook recording started", renderKeyValueLines([]kvPair{
+		{"Events", recorded},
+		{"Directory", dir},
+		{"Stop", "moai hook record --stop"},
+	})))
+	return nil
+}
+
+// hookReplayResult is the outcome of one fixture.
+type hookReplayResult struct {
+	Fixture string
+	Event   string
+	Expect  replay.Expectation
+	Got     replay.Expectation
+}
+
+func runHookReplay(cmd *cobra.Command, args []string) error {
+	if deps == nil || deps.HookRegistry == nil {
+		return fmt.Errorf("hook system not initialized")
+	}
+	out := cmd.OutOrStdout()
+	update, _ := cmd.Flags().GetBool("update")
+	event, _ := cmd.Flags().GetString("event")
+
+	root, err := project.FindProjectRoot()
+	if err != nil {
+		return fmt.Errorf("find project root: %w", err)
+	}
+	dir := filepath.Join(root, replay.DefaultDir)
+	if len(args) == 1 {
+		dir = args[0]
+	}
+	fixtures, err := replay.Load(dir)
+	if err != nil {
+		return err
+	}
+	if event != "" {
+		fixtures = slices.DeleteFunc(fixtures, func(f *replay.Fixture) bool { return !strings.EqualFold(f.Event, event) })
+	}
+	if len(fixtures) == 0 {
+		_, _ = fmt.Fprintln(out, renderInfoCard("Hook replay", cliMuted.Render("No fixtures found in "+dir+".")))
+		return nil
+	}
+
+	var changed []hookReplayResult
+	for _, f := range fixtures {
+		got, err := hook.ReplayFixture(cmd.Context(), deps.HookRegistry, root, f)
+		if err != nil {
+			return fmt.Errorf("replay %s: %w", filepath.Base(f.Path), err)
+		}
+		if f.Expect.Matches(got) {
+			continue
+		}
+		changed = append(changed, hookReplayResult{Fixture: relPath(dir, f.Path), Event: f.Event, Expect: f.Expect, Got: got})
+		if update {
+			f.Expect = got
+			if _, err := replay.Save(dir, f); err != nil {
+				return err
+			}
+		}
+	}
+
+	printHookReplayResults(out, len(fixtures), changed, update)
+	if len(changed) > 0 && !update {
+		return fmt.Errorf("%d of %d fixtures changed decision", len(changed), len(fixtures))
+	}
+	return nil
+}
+
+// printHookReplayResults renders the fixtures whose decision changed.
+func printHookReplayResults(out io.Writer, total int, changed []hookReplayResult, updated bool) {
+	status := fmt.Sprintf("%d passed, %d changed", total-len(changed), len(changed))
+	if updated && len(changed) > 0 {
+		status = fmt.Sprintf("%d of %d expectations updated", len(changed), total)
+	}
+	_, _ = fmt.Fprintln(out, renderCard("Hook replay", renderKeyValueLines([]kvPair{
+		{"Fixtures", fmt.Sprint(total)},
+		{"Result", status},
+	})))
+	if len(changed) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintln(out)
+	_, _ = fmt.Fprintln(out, cliMuted.Render(fmt.Sprintf("%-48s  %-18s  %-32s  %s", "FIXTURE", "EVENT", "EXPECTED", "GOT")))
+	for _, r := range changed {
+		_, _ = fmt.Fprintf(out, "%-48s  %-18s  %-32s  %s\n", r.Fixture, r.Event, r.Expect, r.Got)
+	}
+}
+
+// relPath returns path relative to dir when possible.
+func relPath(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/hook"
+	"github.com/modu-ai/moai-adk/internal/hook/replay"
+)
+
+func runHookSubcommand(t *testing.T, template *cobra.Command, run func(*cobra.Command, []string) error, args []string, flags map[string]string) (string, error) {
+	t.Helper()
+	cmd := &cobra.Command{RunE: run}
+	cmd.Flags().AddFlagSet(template.Flags())
+	for k, v := range flags {
+		if err := cmd.Flags().Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() {
+		for k := range flags {
+			f := cmd.Flags().Lookup(k)
+			if sv, ok := f.Value.(interface{ Replace([]string) error }); ok {
+				_ = sv.Replace(strings.Split(strings.Trim(f.DefValue, "[]"), ","))
+			} else {
+				_ = f.Value.Set(f.DefValue)
+			}
+			f.Changed = false
+		}
+	}()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetContext(context.Background())
+	err := cmd.RunE(cmd, args)
+	return buf.String(), err
+}
+
+func TestRunHookRecord(t *testing.T) {
+	root, _ := filepath.EvalSymlinks(t.TempDir())
+	if err := os.MkdirAll(filepath.Join(root, ".moai"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(root)
+
+	if _, err := runHookSubcommand(t, hookRecordCmd, runHookRecord, nil, map[string]string{"event": "NoSuchEvent"}); err == nil {
+		t.Error("expected an error for an unknown event")
+	}
+
+	out, err := runHookSubcommand(t, hookRecordCmd, runHookRecord, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "PreToolUse, PermissionRequest") {
+		t.Errorf("start output:\n%s", out)
+	}
+	session, err := replay.Active(root)
+	if err != nil || session == nil || !session.Records("PreToolUse") || session.Records("Stop") {
+		t.Fatalf("session = %+v, %v", session, err)
+	}
+
+	out, err = runHookSubcommand(t, hookRecordCmd, runHookRecord, nil, map[string]string{"stop": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "0 recorded since") {
+		t.Errorf("stop output:\n%s", out)
+	}
+	if session, _ := replay.Active(root); session != nil {
+		t.Errorf("session still active: %+v", session)
+	}
+}
+
+func TestRunHookReplay(t *testing.T) {
+	root, _ := filepath.EvalSymlinks(t.TempDir())
+	if err := os.MkdirAll(filepath.Join(root, ".moai"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(root)
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+
+	origDeps := deps
+	defer func() { deps = origDeps }()
+	reg := hook.NewRegistry(nil)
+	reg.Register(hook.NewPreToolHandler(nil, hook.DefaultSecurityPolicy()))
+	deps = &Dependencies{HookRegistry: reg}
+
+	dir := filepath.Join(root, replay.DefaultDir)
+	save := func(command string, expect replay.Expectation) {
+		t.Helper()
+		input := `{"session_id":"s1","cwd":"` + root + `","hook_event_name":"PreToolUse","tool_name":"Bash","tool_input":{"command":"` + command + `"}}`
+		f, err := replay.NewFixture("PreToolUse", []byte(input), root, expect, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := replay.Save(dir, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("go test ./...", replay.Expectation{Decision: "allow"})
+	save("rm -rf /", replay.Expectation{Decision: "allow"})
+
+	out, err := runHookSubcommand(t, hookReplayCmd, runHookReplay, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 fixtures changed decision") {
+		t.Fatalf("replay error = %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "1 passed, 1 changed") || !strings.Contains(out, "deny (bash-dangerous-") {
+		t.Errorf("replay output:\n%s", out)
+	}
+
+	out, err = runHookSubcommand(t, hookReplayCmd, runHookReplay, []string{dir}, map[string]string{"update": "true"})
+	if err != nil {
+		t.Fatalf("replay --update: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "1 of 2 expectations updated") {
+		t.Errorf("update output:\n%s", out)
+	}
+
+	out, err = runHookSubcommand(t, hookReplayCmd, runHookReplay, nil, nil)
+	if err != nil || !strings.Contains(out, "2 passed, 0 changed") {
+		t.Errorf("replay after update = %v\n%s", err, out)
+	}
+}
@@ -43,6 +43,7 @@ func TestHookCmd_HasSubcommands(t *testing.T) {
 		"list", "agent", "pre-push",
 		"post-tool-failure", "notification", "subagent-start", "user-prompt-submit",
 		"permission-request", "teammate-idle", "task-completed", "subagent-stop",
+		"log", "record", "replay",
 	}
 	for _, name := range expected {
 		found := false
@@ -60,8 +61,8 @@ func TestHookCmd_HasSubcommands(t *testing.T) {
 
 func TestHookCmd_SubcommandCount(t *testing.T) {
 	count := len(hookCmd.Commands())
-	if count != 19 {
-		t.Errorf("hook should have 19 subcommands, got %d", count)
+	if count != 23 {
+		t.Errorf("hook should have 23 subcommands, got %d", count)
 	}
 }
 
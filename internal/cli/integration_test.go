@@ -281,6 +281,7 @@ func TestStatuslineCmd_WithDeps(t *testing.T) {
 	defer func() { deps = origDeps }()
 
 	InitDependencies()
+	t.Chdir(t.TempDir())
 
 	buf := new(bytes.Buffer)
 	StatuslineCmd.SetOut(buf)
@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/modu-ai/moai-adk/internal/core/migration"
+	"github.com/modu-ai/moai-adk/internal/defs"
+	"github.com/modu-ai/moai-adk/internal/manifest"
+	"github.com/modu-ai/moai-adk/pkg/version"
+)
+
+// MigrationRegistry is the registry used by migrate commands and template
+// sync. Tests replace it with a registry of fixture steps.
+var MigrationRegistry = migration.Default()
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Inspect and apply template migrations",
+	Long: `Manage versioned template migrations for breaking configuration and
+layout changes. Pending migrations are applied automatically by 'moai update';
+these commands expose the registry for inspection and manual control.`,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show applied and pending migrations",
+		Args:  cobra.NoArgs,
+		RunE:  runMigrateStatus,
+	}
+
+	upCmd := &cobra.Command{
+		Use:   "up",
+		Short: "Apply pending migrations",
+		Long: `Apply pending migrations in version order, from the project's
+project.template_version up to the running binary version (or --to).
+
+Example:
+  moai migrate up --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: runMigrateUp,
+	}
+	upCmd.Flags().String("to", "", "Target template version (default: running binary version)")
+	upCmd.Flags().Bool("dry-run", false, "Show what would change without modifying files")
+
+	downCmd := &cobra.Command{
+		Use:   "down",
+		Short: "Revert applied migrations newer than a version",
+		Long: `Revert applied migrations whose target version is newer than --to,
+in reverse order. Fails without changes if any of them is irreversible.
+
+Example:
+  moai migrate down --to 2.4.0 --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: runMigrateDown,
+	}
+	downCmd.Flags().String("to", "", "Template version to revert to (required)")
+	downCmd.Flags().Bool("dry-run", false, "Show what would change without modifying files")
+	_ = downCmd.MarkFlagRequired("to")
+
+	migrateCmd.AddCommand(statusCmd, upCmd, downCmd)
+}
+
+// getProjectTemplateVersion returns project.template_version from
+// project.yaml, falling back to moai.template_version in system.yaml.
+// Returns "0.0.0" when neither is set.
+func getProjectTemplateVersion(projectRoot string) (string, error) {
+	configPath := filepath.Join(projectRoot, defs.MoAIDir, defs.SectionsSubdir, defs.ProjectYAML)
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("read project config: %w", err)
+	}
+	if err == nil {
+		var config struct {
+			Project struct {
+				TemplateVersion string `yaml:"template_version"`
+			} `yaml:"project"`
+		}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return "", fmt.Errorf("parse project config: %w", err)
+		}
+		if config.Project.TemplateVersion != "" {
+			return config.Project.TemplateVersion, nil
+		}
+	}
+	return getProjectConfigVersion(projectRoot)
+}
+
+// newProjectMigrator loads the manifest for projectRoot and returns a
+// Migrator bound to MigrationRegistry.
+func newProjectMigrator(projectRoot string) (*migration.Migrator, error) {
+	mgr := manifest.NewManager()
+	if _, err := mgr.Load(projectRoot); err != nil {
+		return nil, fmt.Errorf("load manifest: %w", err)
+	}
+	return migration.NewMigrator(projectRoot, MigrationRegistry, mgr), nil
+}
+
+func runMigrateStatus(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+
+	current, err := getProjectTemplateVersion(".")
+	if err != nil {
+		return err
+	}
+	migrator, err := newProjectMigrator(".")
+	if err != nil {
+		return err
+	}
+
+	statuses := migrator.Status(current, version.GetVersion())
+	lines := []string{
+		fmt.Sprintf("Template version: %s", current),
+		fmt.Sprintf("Binary version:   %s", version.GetVersion()),
+		"",
+	}
+	if len(statuses) == 0 {
+		lines = append(lines, "No migrations registered.")
+	}
+	pending := 0
+	for _, st := range statuses {
+		line := fmt.Sprintf("%-15s %-8s %s", migrationStateLabel(st.State), st.Step.ToVersion, st.Step.ID)
+		if st.AppliedAt != "" {
+			line += cliMuted.Render("  (" + st.AppliedAt + ")")
+		}
+		lines = append(lines, line)
+		if st.State == migration.StatePending {
+			pending++
+		}
+	}
+	if pending > 0 {
+		lines = append(lines, "", fmt.Sprintf("%d pending. Run 'moai migrate up' to apply.", pending))
+	}
+
+	_, _ = fmt.Fprintln(out, renderCard("Template Migrations", strings.Join(lines, "\n")))
+	return nil
+}
+
+func runMigrateUp(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+	dryRun := getBoolFlag(cmd, "dry-run")
+
+	target, _ := cmd.Flags().GetString("to")
+	if target == "" {
+		target = version.GetVersion()
+	}
+	current, err := getProjectTemplateVersion(".")
+	if err != nil {
+		return err
+	}
+	migrator, err := newProjectMigrator(".")
+	if err != nil {
+		return err
+	}
+
+	report, err := migrator.Up(current, target, migration.Options{DryRun: dryRun, BackupDir: migrationBackupDir(".")})
+	printMigrationReport(out, report, "No pending migrations.")
+	return err
+}
+
+func runMigrateDown(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+	dryRun := getBoolFlag(cmd, "dry-run")
+	target, _ := cmd.Flags().GetString("to")
+
+	migrator, err := newProjectMigrator(".")
+	if err != nil {
+		return err
+	}
+
+	report, err := migrator.Down(target, migration.Options{DryRun: dryRun, BackupDir: migrationBackupDir(".")})
+	printMigrationReport(out, report, "No applied migrations newer than "+target+".")
+	return err
+}
+
+// runTemplateMigrations applies pending migrations (upgrade) or reverts
+// newer ones (downgrade) before template files are backed up and replaced,
+// so the backup and 3-way merge operate on the migrated layout. The files
+// each step touches are copied to migrationBackupDir first, and a failed
+// step is rolled back from that copy.
+// mgr is the manifest manager shared with the deploy step so that applied
+// migrations are not lost when the deployer saves the manifest.
+// Irreversible steps on downgrade are reported but do not block the sync.
+func runTemplateMigrations(projectRoot, packageVersion string, mgr manifest.Manager, out io.Writer) error {
+	current, err := getProjectTemplateVersion(projectRoot)
+	if err != nil {
+		return err
+	}
+	migrator := migration.NewMigrator(projectRoot, MigrationRegistry, mgr)
+	backupDir := migrationBackupDir(projectRoot)
+
+	if cmp, ok := compareVersions(packageVersion, current); ok && cmp < 0 {
+		report, downErr := migrator.Down(packageVersion, migration.Options{BackupDir: backupDir})
+		if downErr != nil {
+			_, _ = fmt.Fprintf(out, "  %s Migration revert skipped: %v\n", symWarning(), downErr)
+			return nil
+		}
+		if len(report.Steps) > 0 {
+			_, _ = fmt.Fprintf(out, "  %s Reverted %d migration(s)\n", symSuccess(), len(report.Steps))
+		}
+		return nil
+	}
+
+	report, err := migrator.Up(current, packageVersion, migration.Options{BackupDir: backupDir})
+	if err != nil {
+		return fmt.Errorf("apply migrations (pre-migration files in %s): %w", backupDir, err)
+	}
+	if len(report.Steps) > 0 {
+		_, _ = fmt.Fprintf(out, "  %s Applied %d migration(s) (%d change(s)), pre-migration files in %s\n",
+			symSuccess(), len(report.Steps), report.Changed(), backupDir)
+	} else {
+		_, _ = fmt.Fprintln(out, "  - No pending migrations")
+	}
+	return nil
+}
+
+// migrationBackupDir returns where a migration run snapshots the files its
+// steps touch. It lives apart from the timestamped config backups so the
+// update restore and backup cleanup never pick it up.
+func migrationBackupDir(projectRoot string) string {
+	return filepath.Join(projectRoot, defs.BackupsDir, "migrations", time.Now().Format(defs.BackupTimestampFormat))
+}
+
+// printMigrationReport renders the steps and operations of a migrate run.
+func printMigrationReport(out io.Writer, report *migration.Report, emptyMsg string) {
+	if report == nil {
+		return
+	}
+	if len(report.Steps) == 0 {
+		_, _ = fmt.Fprintln(out, emptyMsg)
+		return
+	}
+
+	verb := "Applied"
+	if report.DryRun {
+		verb = "Would apply"
+	}
+	for _, s := range report.Steps {
+		_, _ = fmt.Fprintf(out, "%s %s %s\n", symSuccess(), verb, s.Step.ID)
+		if s.Step.Description != "" {
+			_, _ = fmt.Fprintf(out, "    %s\n", cliMuted.Render(s.Step.Description))
+		}
+		for _, op := range s.Operations {
+			mark := "-"
+			if op.Changed {
+				mark = "~"
+			}
+			_, _ = fmt.Fprintf(out, "    %s %s\n", mark, op.Description)
+		}
+	}
+	if report.DryRun {
+		_, _ = fmt.Fprintf(out, "\nDry run: %d change(s) pending, nothing written.\n", report.Changed())
+	}
+}
+
+func migrationStateLabel(s migration.State) string {
+	switch s {
+	case migration.StateApplied:
+		return cliSuccess.Render("applied")
+	case migration.StatePending:
+		return cliWarn.Render("pending")
+	case migration.StateIncluded:
+		return cliMuted.Render("included")
+	}
+	return cliMuted.Render("n/a")
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/core/migration"
+	"github.com/modu-ai/moai-adk/internal/manifest"
+)
+
+// useTestMigrations swaps MigrationRegistry for the duration of a test.
+func useTestMigrations(t *testing.T, steps ...migration.Step) {
+	t.Helper()
+	r, err := migration.NewRegistry(steps...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := MigrationRegistry
+	MigrationRegistry = r
+	t.Cleanup(func() { MigrationRegistry = orig })
+}
+
+func testMigrationSteps() []migration.Step {
+	return []migration.Step{
+		{
+			ID:          "2.1.0-move-notes",
+			ToVersion:   "2.1.0",
+			Description: "Move notes",
+			Operations:  []migration.Operation{migration.MoveFile{From: "notes.md", To: ".moai/notes.md"}},
+		},
+	}
+}
+
+func newMigrateTestCmd(run func(*cobra.Command, []string) error) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{RunE: run}
+	cmd.Flags().String("to", "", "")
+	cmd.Flags().Bool("dry-run", false, "")
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	return cmd, buf
+}
+
+func TestMigrateCmd_Registered(t *testing.T) {
+	found := false
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "migrate" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("migrate command not registered on root")
+	}
+	for _, name := range []string{"status", "up", "down"} {
+		if c, _, err := migrateCmd.Find([]string{name}); err != nil || c.Name() != name {
+			t.Errorf("migrate %s not registered", name)
+		}
+	}
+}
+
+func TestGetProjectTemplateVersion(t *testing.T) {
+	t.Run("missing config", func(t *testing.T) {
+		v, err := getProjectTemplateVersion(t.TempDir())
+		if err != nil || v != "0.0.0" {
+			t.Errorf("got (%q, %v), want 0.0.0", v, err)
+		}
+	})
+
+	t.Run("project.yaml wins", func(t *testing.T) {
+		root := t.TempDir()
+		writeSystemYAML(t, root, "  template_version: \"2.0.0\"\n")
+		path := filepath.Join(root, ".moai", "config", "sections", "project.yaml")
+		if err := os.WriteFile(path, []byte("project:\n  template_version: \"2.1.0\"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		v, err := getProjectTemplateVersion(root)
+		if err != nil || v != "2.1.0" {
+			t.Errorf("got (%q, %v), want 2.1.0", v, err)
+		}
+	})
+
+	t.Run("falls back to system.yaml", func(t *testing.T) {
+		root := t.TempDir()
+		writeSystemYAML(t, root, "  template_version: \"2.0.0\"\n")
+		v, err := getProjectTemplateVersion(root)
+		if err != nil || v != "2.0.0" {
+			t.Errorf("got (%q, %v), want 2.0.0", v, err)
+		}
+	})
+}
+
+func TestRunMigrateUpAndDown(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	useTestMigrations(t, testMigrationSteps()...)
+	writeSystemYAML(t, root, "  template_version: \"2.0.0\"\n")
+	if err := os.WriteFile("notes.md", []byte("n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Dry run reports the change without moving the file.
+	cmd, buf := newMigrateTestCmd(runMigrateUp)
+	_ = cmd.Flags().Set("to", "2.1.0")
+	_ = cmd.Flags().Set("dry-run", "true")
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Would apply 2.1.0-move-notes") {
+		t.Errorf("dry-run output = %q", buf.String())
+	}
+	if _, err := os.Stat("notes.md"); err != nil {
+		t.Fatal("dry run moved the file")
+	}
+
+	cmd, buf = newMigrateTestCmd(runMigrateUp)
+	_ = cmd.Flags().Set("to", "2.1.0")
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Applied 2.1.0-move-notes") {
+		t.Errorf("up output = %q", buf.String())
+	}
+	if _, err := os.Stat(filepath.Join(".moai", "notes.md")); err != nil {
+		t.Fatal("notes.md was not moved")
+	}
+
+	mgr := manifest.NewManager()
+	mf, err := mgr.Load(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mf.Migrations) != 1 || mf.Migrations[0].ID != "2.1.0-move-notes" {
+		t.Fatalf("manifest migrations = %+v", mf.Migrations)
+	}
+
+	cmd, _ = newMigrateTestCmd(runMigrateDown)
+	_ = cmd.Flags().Set("to", "2.0.0")
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat("notes.md"); err != nil {
+		t.Error("down did not restore notes.md")
+	}
+}
+
+func TestRunMigrateStatus(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	useTestMigrations(t, testMigrationSteps()...)
+	writeSystemYAML(t, root, "  template_version: \"2.0.0\"\n")
+
+	cmd, buf := newMigrateTestCmd(runMigrateStatus)
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "2.1.0-move-notes") || !strings.Contains(out, "Template version: 2.0.0") {
+		t.Errorf("status output = %q", out)
+	}
+}
+
+func TestRunTemplateMigrations_DowngradeIrreversible(t *testing.T) {
+	root := t.TempDir()
+	useTestMigrations(t, migration.Step{
+		ID:         "2.1.0-remove",
+		ToVersion:  "2.1.0",
+		Operations: []migration.Operation{migration.RemovePath{Path: "legacy"}},
+	})
+	writeSystemYAML(t, root, "  template_version: \"2.1.0\"\n")
+
+	mgr := manifest.NewManager()
+	mf, err := mgr.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mf.Migrations = append(mf.Migrations, manifest.AppliedMigration{ID: "2.1.0-remove", ToVersion: "2.1.0"})
+
+	var buf bytes.Buffer
+	if err := runTemplateMigrations(root, "2.0.0", mgr, &buf); err != nil {
+		t.Fatalf("downgrade should not fail: %v", err)
+	}
+	if !strings.Contains(buf.String(), "revert skipped") {
+		t.Errorf("output = %q, want revert warning", buf.String())
+	}
+}
@@ -0,0 +1,268 @@
+package cli
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/core/project"
+	"github.com/modu-ai/moai-adk/internal/hook"
+	"github.com/modu-ai/moai-adk/internal/hook/audit"
+)
+
+var permissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "Manage the permission policy of this project",
+	Long: `Manage the permission_policy rules in .moai/config/sections/hooks.yaml.
+
+The PermissionRequest hook checks every request against the PreToolUse
+security policy first and then against the rules in order: the first rule
+matching the tool, file path, command prefix and permission mode decides.
+Requests no rule matches are left to the user.`,
+}
+
+var permissionsSuggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Propose allow rules from repeated manual approvals",
+	Long: `Propose allow rules for the permission requests you approved repeatedly.
+
+A request counts as approved when the hook audit log shows the tool ran
+after the PermissionRequest. Commands are grouped by their leading words
+("go test"), files by their top-level directory ("internal/**"). Requests
+an existing rule already decides are not suggested again. Use --accept to
+append the suggestions to hooks.yaml.
+
+Examples:
+  moai permissions suggest
+  moai permissions suggest --since 7d --min 5
+  moai permissions suggest --accept`,
+	Args: cobra.NoArgs,
+	RunE: runPermissionsSuggest,
+}
+
+func init() {
+	rootCmd.AddCommand(permissionsCmd)
+	permissionsCmd.AddCommand(permissionsSuggestCmd)
+
+	permissionsSuggestCmd.Flags().String("since", "30d", "Start of range: a date (YYYY-MM-DD), Nd for N days ago, 'today' or 'all'")
+	permissionsSuggestCmd.Flags().Int("min", 3, "Only suggest rules approved at least N times")
+	permissionsSuggestCmd.Flags().Bool("accept", false, "Append the suggested rules to hooks.yaml")
+	permissionsSuggestCmd.Flags().String("format", "table", "Output format: table, json")
+}
+
+// permissionRuleEntry is a permission rule as written to hooks.yaml and
+// shown by --format json; unlike config.PermissionRule it omits empty
+// fields.
+type permissionRuleEntry struct {
+	ID       string   `yaml:"id" json:"id"`
+	Decision string   `yaml:"decision,omitempty" json:"decision,omitempty"`
+	Tools    []string `yaml:"tools,omitempty,flow" json:"tools,omitempty"`
+	Paths    []string `yaml:"paths,omitempty,flow" json:"paths,omitempty"`
+	Commands []string `yaml:"commands,omitempty,flow" json:"commands,omitempty"`
+	Modes    []string `yaml:"modes,omitempty,flow" json:"modes,omitempty"`
+	Reason   string   `yaml:"reason,omitempty" json:"reason,omitempty"`
+}
+
+func newPermissionRuleEntry(r config.PermissionRule) permissionRuleEntry {
+	return permissionRuleEntry{
+		ID:       r.ID,
+		Decision: r.Decision,
+		Tools:    r.Tools,
+		Paths:    r.Paths,
+		Commands: r.Commands,
+		Modes:    r.Modes,
+		Reason:   r.Reason,
+	}
+}
+
+// permissionSuggestion is one suggestion in the JSON output.
+type permissionSuggestion struct {
+	Rule      permissionRuleEntry `json:"rule"`
+	Approvals int                 `json:"approvals"`
+	Sessions  int                 `json:"sessions"`
+}
+
+// permissionsSuggestReport is the JSON output of `moai permissions suggest`.
+type permissionsSuggestReport struct {
+	Since       string                 `json:"since,omitempty"`
+	Suggestions []permissionSuggestion `json:"suggestions"`
+	Accepted    bool                   `json:"accepted"`
+}
+
+func runPermissionsSuggest(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	since, err := parseUsageSince(sinceFlag, usageNow())
+	if err != nil {
+		return err
+	}
+	format, _ := cmd.Flags().GetString("format")
+	if format != "table" && format != "json" {
+		return fmt.Errorf("invalid --format %q: want table or json", format)
+	}
+	minApprovals, _ := cmd.Flags().GetInt("min")
+	accept, _ := cmd.Flags().GetBool("accept")
+
+	root, err := project.FindProjectRoot()
+	if err != nil {
+		return fmt.Errorf("find project root: %w", err)
+	}
+	cfg := loadWorkflowProjectConfig(root)
+	records, err := audit.Read(audit.ResolveDir(root, cmp.Or(cfg.HookAudit.Dir, config.DefaultHookAuditDir)), audit.Filter{Since: since})
+	if err != nil {
+		return err
+	}
+	suggestions := hook.SuggestPermissionRules(records, minApprovals, cfg.PermissionPolicy.Rules)
+
+	report := permissionsSuggestReport{Suggestions: []permissionSuggestion{}}
+	if !since.IsZero() {
+		report.Since = since.Format(time.DateOnly)
+	}
+	var entries []permissionRuleEntry
+	for _, s := range suggestions {
+		entry := newPermissionRuleEntry(s.Rule)
+		entries = append(entries, entry)
+		report.Suggestions = append(report.Suggestions, permissionSuggestion{Rule: entry, Approvals: s.Approvals, Sessions: s.Sessions})
+	}
+
+	var path string
+	if accept && len(entries) > 0 {
+		if path, err = appendPermissionRules(root, entries); err != nil {
+			return err
+		}
+		report.Accepted = true
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	if len(suggestions) == 0 {
+		msg := fmt.Sprintf("No request was approved %d or more times", max(minApprovals, 1))
+		if !cfg.HookAudit.Enabled {
+			msg = "No approvals recorded; the audit log is disabled in hooks.yaml"
+		}
+		_, _ = fmt.Fprintln(out, renderInfoCard("Permission suggestions", cliMuted.Render(msg+".")))
+		return nil
+	}
+	printPermissionSuggestions(out, suggestions)
+	_, _ = fmt.Fprintln(out)
+	if report.Accepted {
+		_, _ = fmt.Fprintf(out, "Added %d rule(s) to %s.\n", len(entries), relPath(root, path))
+	} else {
+		_, _ = fmt.Fprintln(out, cliMuted.Render("Run with --accept to add these rules to hooks.yaml."))
+	}
+	return nil
+}
+
+// printPermissionSuggestions renders one suggested rule per row, most
+// approved first.
+func printPermissionSuggestions(out io.Writer, suggestions []hook.PermissionSuggestion) {
+	header := fmt.Sprintf("%-28s  %-14s  %-32s  %9s  %8s", "RULE", "TOOLS", "MATCHES", "APPROVALS", "SESSIONS")
+	_, _ = fmt.Fprintln(out, cliMuted.Render(header))
+	for _, s := range suggestions {
+		matches := strings.Join(slices.Concat(s.Rule.Commands, s.Rule.Paths), ", ")
+		_, _ = fmt.Fprintf(out, "%-28s  %-14s  %-32s  %9d  %8d\n",
+			s.Rule.ID, strings.Join(s.Rule.Tools, ","), matches, s.Approvals, s.Sessions)
+	}
+}
+
+// appendPermissionRules appends rules to permission_policy.rules in the
+// hooks.yaml of a project, keeping its comments and creating the file or
+// section when missing. It returns the path written.
+func appendPermissionRules(root string, rules []permissionRuleEntry) (string, error) {
+	path := filepath.Join(root, ".moai", "config", "sections", "hooks.yaml")
+
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return "", fmt.Errorf("read hooks.yaml: %w", err)
+	default:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return "", fmt.Errorf("parse hooks.yaml: %w", err)
+		}
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	top := doc.Content[0]
+	if top.Kind != yaml.MappingNode {
+		return "", errors.New("parse hooks.yaml: top level is not a mapping")
+	}
+
+	policy := yamlMappingValue(top, "permission_policy", yaml.MappingNode)
+	if policy.Kind != yaml.MappingNode {
+		return "", errors.New("parse hooks.yaml: permission_policy is not a mapping")
+	}
+	if enabled := yamlMappingValue(policy, "enabled", yaml.ScalarNode); enabled.Tag == "!!null" {
+		enabled.Tag, enabled.Value = "!!bool", "true"
+	}
+	seq := yamlMappingValue(policy, "rules", yaml.SequenceNode)
+	if seq.Kind != yaml.SequenceNode {
+		return "", errors.New("parse hooks.yaml: permission_policy.rules is not a list")
+	}
+	seq.Style = 0
+	for _, r := range rules {
+		var node yaml.Node
+		if err := node.Encode(r); err != nil {
+			return "", fmt.Errorf("encode permission rule: %w", err)
+		}
+		seq.Content = append(seq.Content, &node)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return "", fmt.Errorf("encode hooks.yaml: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("encode hooks.yaml: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return "", fmt.Errorf("write hooks.yaml: %w", err)
+	}
+	return path, nil
+}
+
+// yamlMappingValue returns the value of key in a mapping node. A missing
+// key is added, and a key without a value given one, as an empty node of
+// kind; an empty scalar is tagged !!null.
+func yamlMappingValue(mapping *yaml.Node, key string, kind yaml.Kind) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != key {
+			continue
+		}
+		value := mapping.Content[i+1]
+		if value.Kind == yaml.ScalarNode && value.Tag == "!!null" && kind != yaml.ScalarNode {
+			value.Kind, value.Tag, value.Value = kind, "", ""
+		}
+		return value
+	}
+	value := &yaml.Node{Kind: kind}
+	if kind == yaml.ScalarNode {
+		value.Tag = "!!null"
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	return value
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/hook/audit"
+)
+
+func TestRunPermissionsSuggest(t *testing.T) {
+	root, _ := filepath.EvalSymlinks(t.TempDir())
+	sections := filepath.Join(root, ".moai", "config", "sections")
+	if err := os.MkdirAll(sections, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	hooksYAML := filepath.Join(sections, "hooks.yaml")
+	original := "# Hook Policies\nhook_audit:\n  enabled: true # Keep this comment\npermission_policy:\n  enabled: true\n  rules: []\n"
+	if err := os.WriteFile(hooksYAML, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(root)
+
+	now := time.Now()
+	var records []audit.Record
+	for i := range 3 {
+		digest := fmt.Sprintf("sha256:%016d", i)
+		session := fmt.Sprintf("sess-%d", i%2)
+		records = append(records,
+			audit.Record{Time: now, SessionID: session, Event: "PermissionRequest", Handler: "hook.permissionRequestHandler", Decision: audit.DecisionAsk, ToolName: "Bash", Subject: "go test", InputDigest: digest},
+			audit.Record{Time: now, SessionID: session, Event: "PostToolUse", Handler: "hook.postToolHandler", Decision: audit.DecisionAllow, ToolName: "Bash", InputDigest: digest},
+		)
+	}
+	if err := (&audit.Log{Dir: filepath.Join(root, ".moai", "logs", "hooks")}).Append(records...); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runHookSubcommand(t, permissionsSuggestCmd, runPermissionsSuggest, nil, map[string]string{"min": "4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "approved 4 or more times") {
+		t.Errorf("output with --min 4:\n%s", out)
+	}
+
+	out, err = runHookSubcommand(t, permissionsSuggestCmd, runPermissionsSuggest, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"allow-go-test", "go test", "Run with --accept"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = runHookSubcommand(t, permissionsSuggestCmd, runPermissionsSuggest, nil, map[string]string{"accept": "true", "format": "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report permissionsSuggestReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out)
+	}
+	if !report.Accepted || len(report.Suggestions) != 1 || report.Suggestions[0].Approvals != 3 || report.Suggestions[0].Sessions != 2 {
+		t.Errorf("report = %+v", report)
+	}
+
+	data, _ := os.ReadFile(hooksYAML)
+	if !strings.Contains(string(data), "# Keep this comment") || !strings.Contains(string(data), "commands: [go test]") {
+		t.Errorf("hooks.yaml after --accept:\n%s", data)
+	}
+	rules := loadWorkflowProjectConfig(root).PermissionPolicy.Rules
+	if len(rules) != 1 || rules[0].ID != "allow-go-test" || !slices.Equal(rules[0].Tools, []string{"Bash"}) {
+		t.Errorf("loaded rules = %+v", rules)
+	}
+
+	// Accepted rules are not suggested again.
+	out, err = runHookSubcommand(t, permissionsSuggestCmd, runPermissionsSuggest, nil, nil)
+	if err != nil || !strings.Contains(out, "No request was approved") {
+		t.Errorf("suggest after --accept = %v:\n%s", err, out)
+	}
+}
+
+func TestAppendPermissionRules_NewFile(t *testing.T) {
+	root := t.TempDir()
+	path, err := appendPermissionRules(root, []permissionRuleEntry{{ID: "allow-files-internal", Decision: "allow", Paths: []string{"internal/**"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	want := "permission_policy:\n  enabled: true\n  rules:\n    - id: allow-files-internal\n      decision: allow\n      paths: [internal/**]\n"
+	if string(data) != want {
+		t.Errorf("hooks.yaml =\n%s\nwant\n%s", data, want)
+	}
+}
@@ -22,6 +22,10 @@ const (
 	rankBatchSize = 100
 )
 
+// rankOutboxDir is the submission outbox location; empty selects the
+// default under ~/.moai/rank. Tests point it at a temporary directory.
+var rankOutboxDir = ""
+
 var rankCmd = &cobra.Command{
 	Use:   "rank",
 	Short: "MoAI Rank leaderboard management",
@@ -129,6 +133,10 @@ func newRankStatusCmd() *cobra.Command {
 
 			userRank, err := deps.RankClient.GetUserRank(ctx)
 			if err != nil {
+				// Queued sessions are still worth showing when the API is down.
+				if pairs := rankOutboxPairs(); len(pairs) > 0 {
+					_, _ = fmt.Fprintln(out, renderCard("MoAI Rank", renderKeyValueLines(pairs)))
+				}
 				return fmt.Errorf("get rank: %w", err)
 			}
 
@@ -141,6 +149,7 @@ func newRankStatusCmd() *cobra.Command {
 					kvPair{"Sessions", fmt.Sprintf("%d", userRank.Stats.TotalSessions)},
 				)
 			}
+			pairs = append(pairs, rankOutboxPairs()...)
 			_, _ = fmt.Fprintln(out, renderCard("MoAI Rank", renderKeyValueLines(pairs)))
 			return nil
 		},
@@ -199,6 +208,9 @@ func newRankSyncCmd() *cobra.Command {
 			// Get device info for multi-device tracking
 			deviceInfo := rank.GetDeviceInfo()
 
+			// Deliver sessions queued by the SessionEnd hook first.
+			drainRankOutbox(cmd.Context(), deps.RankClient, out)
+
 			// Check for --force flag
 			force, _ := cmd.Flags().GetBool("force")
 			if force && syncState != nil {
@@ -226,14 +238,27 @@ func newRankSyncCmd() *cobra.Command {
 			skippedCount := 0
 			alreadySyncedCount := 0
 
+			// Skip already-synced transcripts (unless --force)
+			var pending []string
 			for _, transcriptPath := range transcripts {
-				// Skip already-synced transcripts (unless --force)
 				if syncState != nil && syncState.IsSynced(transcriptPath) {
 					alreadySyncedCount++
 					continue
 				}
+				pending = append(pending, transcriptPath)
+			}
+
+			// Bring the transcript index up to date so only content appended
+			// since the last sync is parsed.
+			index := openTranscriptIndex()
+			if index != nil {
+				if _, err := index.Update(pending); err != nil {
+					_, _ = fmt.Fprintf(out, "Warning: could not update transcript index: %v\n", err)
+				}
+			}
 
-				usage, err := rank.ParseTranscript(transcriptPath)
+			for _, transcriptPath := range pending {
+				usage, err := indexedTranscriptUsage(index, transcriptPath)
 				if err != nil {
 					skippedCount++
 					continue
@@ -381,6 +406,62 @@ func newRankRegisterCmd() *cobra.Command {
 	}
 }
 
+// drainRankOutbox submits sessions queued in the local outbox and reports
+// the outcome. Failures are reported but never abort the caller.
+func drainRankOutbox(ctx context.Context, client rank.Client, out io.Writer) {
+	outbox, err := rank.NewOutbox(rankOutboxDir, rank.OutboxConfig{})
+	if err != nil {
+		return
+	}
+	pending, err := outbox.Pending()
+	if err != nil || len(pending) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(out, "Delivering %d queued session(s)...\n", len(pending))
+	res, err := outbox.Drain(ctx, client)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "Warning: could not update outbox: %v\n", err)
+		return
+	}
+	_, _ = fmt.Fprintf(out, "Outbox: %d sent, %d still queued", res.Sent, res.Remaining)
+	if res.Dropped > 0 {
+		_, _ = fmt.Fprintf(out, ", %d dropped after repeated rejections", res.Dropped)
+	}
+	_, _ = fmt.Fprintln(out)
+}
+
+// rankOutboxPairs describes the local submission outbox for rank status.
+// Returns nil when nothing is queued and no error was recorded.
+func rankOutboxPairs() []kvPair {
+	outbox, err := rank.NewOutbox(rankOutboxDir, rank.OutboxConfig{})
+	if err != nil {
+		return nil
+	}
+	st, err := outbox.Status()
+	if err != nil || (st.Depth == 0 && st.LastError == "") {
+		return nil
+	}
+
+	pairs := []kvPair{{"Outbox", fmt.Sprintf("%d session(s) queued", st.Depth)}}
+	if st.LastError != "" {
+		pairs = append(pairs, kvPair{"Last error", fmt.Sprintf("%s (%s)", st.LastError, st.LastErrorAt.Local().Format("2006-01-02 15:04"))})
+	}
+	return pairs
+}
+
+// indexedTranscriptUsage returns session totals from the transcript index,
+// parsing the transcript in full when it is not indexed. The index must
+// already have been updated for transcriptPath.
+func indexedTranscriptUsage(index *rank.TranscriptIndex, transcriptPath string) (*rank.TranscriptUsage, error) {
+	if index != nil {
+		if usage, ok := index.Usage(transcriptPath); ok {
+			return usage, nil
+		}
+	}
+	return rank.ParseTranscript(transcriptPath)
+}
+
 // syncBatchResult holds the summary counts from a batch sync operation.
 type syncBatchResult struct {
 	Submitted    int
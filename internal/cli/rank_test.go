@@ -236,3 +236,76 @@ func TestSubmitSyncBatches_BatchFailure(t *testing.T) {
 		}
 	})
 }
+
+func TestIndexedTranscriptUsage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sess.jsonl")
+	content := `{"timestamp":"2026-01-01T10:00:00Z","type":"user","message":{"content":"hi"}}
+{"timestamp":"2026-01-01T10:00:30Z","type":"assistant","message":{"model":"claude-opus-4-6","usage":{"input_tokens":100,"output_tokens":20}}}
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := rank.ParseTranscript(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without an index the transcript is parsed directly.
+	got, err := indexedTranscriptUsage(nil, path)
+	if err != nil || *got != *want {
+		t.Fatalf("unindexed usage = %+v, %v; want %+v", got, err, want)
+	}
+
+	index, err := rank.OpenTranscriptIndex(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := index.Update([]string{path}); err != nil {
+		t.Fatal(err)
+	}
+	got, err = indexedTranscriptUsage(index, path)
+	if err != nil || *got != *want {
+		t.Errorf("indexed usage = %+v, %v; want %+v", got, err, want)
+	}
+}
+
+func TestRankOutbox_StatusAndDrain(t *testing.T) {
+	origDir := rankOutboxDir
+	rankOutboxDir = t.TempDir()
+	defer func() { rankOutboxDir = origDir }()
+
+	if pairs := rankOutboxPairs(); pairs != nil {
+		t.Fatalf("empty outbox should report nothing, got %v", pairs)
+	}
+
+	outbox, err := rank.NewOutbox(rankOutboxDir, rank.OutboxConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 2 {
+		s := &rank.SessionSubmission{EndedAt: "2026-01-01T10:00:00Z", InputTokens: int64(i + 1)}
+		if _, err := outbox.Enqueue(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pairs := rankOutboxPairs()
+	if len(pairs) != 1 || pairs[0].value != "2 session(s) queued" {
+		t.Errorf("rankOutboxPairs() = %v", pairs)
+	}
+
+	var buf bytes.Buffer
+	client := &mockRankClient{
+		submitBatchFunc: func(_ context.Context, batch []*rank.SessionSubmission) (*rank.BatchResult, error) {
+			return &rank.BatchResult{Success: true, Processed: len(batch), Succeeded: len(batch)}, nil
+		},
+	}
+	drainRankOutbox(context.Background(), client, &buf)
+	if !strings.Contains(buf.String(), "2 sent, 0 still queued") {
+		t.Errorf("drain output = %q", buf.String())
+	}
+	if pairs := rankOutboxPairs(); pairs != nil {
+		t.Errorf("drained outbox should report nothing, got %v", pairs)
+	}
+}
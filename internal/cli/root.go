@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/modu-ai/moai-adk/internal/cli/worktree"
+	"github.com/modu-ai/moai-adk/internal/forge"
 	"github.com/modu-ai/moai-adk/pkg/version"
 )
 
@@ -19,6 +20,10 @@ that serves as the runtime backbone for the MoAI framework within Claude Code.
 It provides CLI tooling, configuration management, LSP integration,
 Git operations, quality gates, and autonomous development loop capabilities.`,
 	Version: version.GetVersion(),
+	// Enforce moai.required_version from system.yaml before any command runs.
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		return enforceRequiredVersion(cmd)
+	},
 }
 
 // @MX:ANCHOR: [AUTO] Execute is the main entry point for the moai CLI
@@ -34,6 +39,9 @@ func init() {
 
 	// Wire worktree subcommand with lazy Git initialization
 	worktree.WorktreeCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := enforceRequiredVersion(cmd); err != nil {
+			return err
+		}
 		if deps == nil {
 			return fmt.Errorf("dependencies not initialized")
 		}
@@ -47,6 +55,13 @@ func init() {
 		worktree.WorktreeProvider = deps.GitWorktree
 		return nil
 	}
+	worktree.QualityGateFactory = newWorktreeQualityGate
+	worktree.ForgeFactory = func(projectRoot string) (forge.Forge, error) {
+		return ForgeFactory(projectRoot)
+	}
+	worktree.StrategyModeFunc = func(projectRoot string) string {
+		return loadWorkflowProjectConfig(projectRoot).GitStrategy.Mode
+	}
 
 	// Register worktree subcommand tree
 	rootCmd.AddCommand(worktree.WorktreeCmd)
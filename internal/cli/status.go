@@ -1,12 +1,16 @@
 package cli
 
 import (
+	"cmp"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/modu-ai/moai-adk/internal/git/ops"
 	"github.com/modu-ai/moai-adk/pkg/version"
 )
 
@@ -19,6 +23,7 @@ var statusCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().Bool("stats", false, "Show git snapshot cache statistics")
 }
 
 // runStatus displays the current project status.
@@ -31,6 +36,7 @@ func runStatus(cmd *cobra.Command, _ []string) error {
 	}
 
 	projectName := filepath.Base(cwd)
+	showStats, _ := cmd.Flags().GetBool("stats")
 
 	pairs := []kvPair{
 		{"Project", projectName},
@@ -45,6 +51,7 @@ func runStatus(cmd *cobra.Command, _ []string) error {
 			kvPair{"Status", "Not initialized (run 'moai init')"},
 		)
 		_, _ = fmt.Fprintln(out, renderCard("Project Status", renderKeyValueLines(pairs)))
+		printGitStatus(out, cwd, nil, showStats)
 		return nil
 	}
 	pairs = append(pairs, kvPair{"Config", filepath.Join(".moai", "config", "sections")})
@@ -62,10 +69,56 @@ func runStatus(cmd *cobra.Command, _ []string) error {
 	pairs = append(pairs, kvPair{"Status", "Initialized"})
 
 	_, _ = fmt.Fprintln(out, renderCard("Project Status", renderKeyValueLines(pairs)))
+	printGitStatus(out, cwd, ops.NewSnapshotCache(ops.SnapshotCachePath(cwd), 0), showStats)
 
 	return nil
 }
 
+// printGitStatus renders the repository snapshot shared with the statusline
+// and, with showStats, the snapshot cache statistics. Nothing is printed
+// outside a git repository.
+func printGitStatus(out io.Writer, dir string, cache *ops.SnapshotCache, showStats bool) {
+	if _, err := ops.Fingerprint(dir); err != nil {
+		return
+	}
+	mgr := ops.NewGitManager(ops.ManagerConfig{WorkDir: dir})
+	defer mgr.Shutdown()
+
+	snap, _ := mgr.CachedSnapshot(cache)
+	pairs := []kvPair{{"Branch", cmp.Or(snap.Branch, "(detached)")}}
+	if snap.Head != "" {
+		pairs = append(pairs, kvPair{"Last commit", snap.Head[:min(7, len(snap.Head))] + " " + snap.LastCommit})
+	}
+	pairs = append(pairs, kvPair{"Changes", fmt.Sprintf("%d staged, %d modified, %d untracked", snap.Staged, snap.Modified, snap.Untracked)})
+	upstream := "none"
+	if snap.HasUpstream {
+		upstream = fmt.Sprintf("%d ahead, %d behind", snap.Ahead, snap.Behind)
+	}
+	pairs = append(pairs, kvPair{"Upstream", upstream})
+	_, _ = fmt.Fprintln(out, renderCard("Git", renderKeyValueLines(pairs)))
+
+	if !showStats {
+		return
+	}
+	var statsPairs []kvPair
+	if cache != nil {
+		cs := cache.Stats()
+		statsPairs = append(statsPairs,
+			kvPair{"Snapshot hits", fmt.Sprintf("%d of %d (%.1f%%)", cs.Hits, cs.Hits+cs.Misses, cs.HitRate*100)})
+		if !cs.StoredAt.IsZero() {
+			statsPairs = append(statsPairs, kvPair{"Last refresh", cs.StoredAt.Local().Format(time.DateTime)})
+		}
+	} else {
+		statsPairs = append(statsPairs, kvPair{"Snapshot cache", "disabled outside MoAI projects"})
+	}
+	st := mgr.GetStatistics()
+	statsPairs = append(statsPairs,
+		kvPair{"Git commands", fmt.Sprintf("%d run", st.Operations.CacheMisses)},
+		kvPair{"Avg operation", time.Duration(st.Operations.AvgExecutionTime).Round(time.Microsecond).String()},
+	)
+	_, _ = fmt.Fprintln(out, renderCard("Git Cache", renderKeyValueLines(statsPairs)))
+}
+
 // countDirs counts the number of subdirectories in a directory.
 func countDirs(dir string) int {
 	entries, err := os.ReadDir(dir)
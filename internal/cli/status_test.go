@@ -3,6 +3,7 @@ package cli
 import (
 	"bytes"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -164,6 +165,51 @@ func TestRunStatus_WithMoAIDir(t *testing.T) {
 	}
 }
 
+func TestRunStatus_GitStats(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".moai"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "feat: initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	t.Chdir(tmpDir)
+
+	if err := statusCmd.Flags().Set("stats", "true"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = statusCmd.Flags().Set("stats", "false") })
+	buf := new(bytes.Buffer)
+	statusCmd.SetOut(buf)
+
+	// The first run fills the snapshot cache; later runs are served from it.
+	for range 3 {
+		buf.Reset()
+		if err := runStatus(statusCmd, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out := buf.String()
+	for _, want := range []string{"Branch", "main", "feat: initial", "Git Cache", "2 of 3"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".moai", "cache", "git-snapshot.json")); err != nil {
+		t.Errorf("snapshot cache not written: %v", err)
+	}
+}
+
 func TestCountDirs(t *testing.T) {
 	tmpDir := t.TempDir()
 	if err := os.Mkdir(filepath.Join(tmpDir, "dir1"), 0o755); err != nil {
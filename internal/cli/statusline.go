@@ -46,6 +46,14 @@ func runStatusline(cmd *cobra.Command, _ []string) error {
 		SegmentConfig: segmentConfig,
 	}
 
+	// Budget spend requires reading transcripts; skip it when the segment is off
+	if enabled, ok := segmentConfig[statusline.SegmentBudget]; !ok || enabled {
+		opts.BudgetProvider = newUsageBudgetProvider(projectRoot)
+	}
+	if projectRoot != "" {
+		opts.GitEventsProvider = newGitEventsProvider(projectRoot)
+	}
+
 	// Create builder and render
 	builder := statusline.New(opts)
 
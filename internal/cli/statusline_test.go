@@ -44,6 +44,9 @@ func TestStatuslineCmd_Execution_NoDeps(t *testing.T) {
 	defer func() { deps = origDeps }()
 
 	deps = nil
+	// Run outside the repository so the git snapshot cache is not written
+	// into its .moai directory.
+	t.Chdir(t.TempDir())
 
 	buf := new(bytes.Buffer)
 	StatuslineCmd.SetOut(buf)
@@ -21,6 +21,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/modu-ai/moai-adk/internal/cli/wizard"
+	"github.com/modu-ai/moai-adk/internal/core/migration"
 	"github.com/modu-ai/moai-adk/internal/core/project"
 	"github.com/modu-ai/moai-adk/internal/defs"
 	"github.com/modu-ai/moai-adk/internal/manifest"
@@ -70,6 +71,9 @@ func init() {
 	updateCmd.Flags().Bool("yes", false, "Auto-confirm all prompts (CI/CD mode)")
 	updateCmd.Flags().Bool("templates-only", false, "Skip binary update, sync templates only")
 	updateCmd.Flags().Bool("binary", false, "Update binary only, skip template sync")
+	updateCmd.Flags().String("to", "", "Install a specific version (upgrade or downgrade) and migrate templates")
+	updateCmd.Flags().Bool("list", false, "List available versions on the release channel")
+	updateCmd.Flags().String("channel", "", "Release channel to list: stable, beta or nightly (default from system.yaml)")
 }
 
 // @MX:ANCHOR: [AUTO] runUpdate orchestrates binary update and template synchronization
@@ -87,6 +91,9 @@ func init() {
 //	--yes: Auto-confirm all prompts (CI/CD mode)
 //	--templates-only: Skip binary update, sync templates only
 //	--binary: Update binary only, skip template sync
+//	--to: Install a specific version (upgrade or downgrade) and migrate templates
+//	--list: List available versions on the release channel
+//	--channel: Release channel for --list (stable, beta, nightly)
 func runUpdate(cmd *cobra.Command, _ []string) error {
 	checkOnly := getBoolFlag(cmd, "check")
 	shellEnv := getBoolFlag(cmd, "shell-env")
@@ -114,6 +121,19 @@ func runUpdate(cmd *cobra.Command, _ []string) error {
 		return runShellEnvConfig(cmd)
 	}
 
+	// Handle --list mode (available versions on the release channel)
+	if getBoolFlag(cmd, "list") {
+		return runUpdateList(cmd)
+	}
+
+	// Handle --to mode (pinned install, including downgrades)
+	if f := cmd.Flags().Lookup("to"); f != nil && f.Value.String() != "" {
+		if templatesOnly {
+			return fmt.Errorf("--to and --templates-only are mutually exclusive")
+		}
+		return runUpdateTo(cmd, f.Value.String())
+	}
+
 	// Handle --check mode (informational: check if newer binary exists)
 	if checkOnly {
 		// Lazily initialize update dependencies
@@ -255,11 +275,18 @@ func runBinaryUpdateStep(cmd *cobra.Command) (updated bool, err error) {
 // moai binary, preserving the original command-line arguments. It sets
 // MOAI_SKIP_BINARY_UPDATE=1 to prevent the re-execed process from
 // attempting another binary update.
+func reexecNewBinary() error {
+	return reexecWithArgs(os.Args[1:])
+}
+
+// reexecWithArgs replaces the current process with the installed moai
+// binary invoked with args (excluding the program name). It sets
+// MOAI_SKIP_BINARY_UPDATE=1 to prevent a re-exec loop.
 //
 // On Unix this uses syscall.Exec (the process is replaced in-place).
 // On Windows syscall.Exec is not available, so we spawn a child process
 // and exit the parent.
-func reexecNewBinary() error {
+func reexecWithArgs(args []string) error {
 	exe, err := os.Executable()
 	if err != nil {
 		return fmt.Errorf("resolve executable path: %w", err)
@@ -272,7 +299,7 @@ func reexecNewBinary() error {
 
 	if runtime.GOOS == "windows" {
 		// Windows: spawn child and exit parent
-		child := exec.Command(exe, os.Args[1:]...)
+		child := exec.Command(exe, args...)
 		child.Stdin = os.Stdin
 		child.Stdout = os.Stdout
 		child.Stderr = os.Stderr
@@ -283,7 +310,7 @@ func reexecNewBinary() error {
 	}
 
 	// Unix: replace process via execve(2)
-	return syscall.Exec(exe, os.Args, os.Environ())
+	return syscall.Exec(exe, append([]string{os.Args[0]}, args...), os.Environ())
 }
 
 // runTemplateSync synchronizes embedded templates with the project directory.
@@ -420,6 +447,13 @@ func runTemplateSyncWithReporter(cmd *cobra.Command, reporter project.ProgressRe
 		message string
 		execute func() error
 	}{
+		{
+			name:    "Migrate",
+			message: "Applying template migrations",
+			execute: func() error {
+				return runTemplateMigrations(projectRoot, packageVersion, mgr, out)
+			},
+		},
 		{
 			name:    "Backup",
 			message: "Backing up configuration",
@@ -1329,11 +1363,16 @@ func restoreMoaiConfig(projectRoot, backupDir string) error {
 		has3Way = true
 	}
 
-	// Walk through backup files (only sections/*.yaml)
+	if err := restoreUserConfigFiles(backupDir, configDir); err != nil {
+		return err
+	}
+
+	// Walk through backup files (only sections/*.yaml). Section files of
+	// older layouts were moved into sections/ by the 2.0.0 migration before
+	// the backup was taken.
 	sectionsBackupDir := filepath.Join(backupDir, "sections")
 	if info, err := os.Stat(sectionsBackupDir); err != nil || !info.IsDir() {
-		// No sections in backup, try walking from backup root
-		return restoreMoaiConfigLegacy(projectRoot, backupDir, configDir)
+		return nil
 	}
 
 	return filepath.Walk(sectionsBackupDir, func(backupPath string, info os.FileInfo, err error) error {
@@ -1406,57 +1445,41 @@ func restoreMoaiConfig(projectRoot, backupDir string) error {
 	})
 }
 
-// restoreMoaiConfigLegacy handles restore from legacy backup format
-// (pre-3-way merge) where files might be at the backup root level.
-func restoreMoaiConfigLegacy(projectRoot, backupDir, configDir string) error {
+// restoreUserConfigFiles restores backed-up files outside sections/ that
+// the new templates did not deploy, such as user-created files. Files the
+// templates deployed are left as deployed.
+func restoreUserConfigFiles(backupDir, configDir string) error {
 	return filepath.Walk(backupDir, func(backupPath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		if info.IsDir() {
-			return nil
-		}
 
 		relPath, err := filepath.Rel(backupDir, backupPath)
 		if err != nil {
 			return err
 		}
-
-		// Skip metadata and template defaults
-		if filepath.Base(relPath) == "backup_metadata.json" ||
-			strings.HasPrefix(relPath, ".template-defaults") {
+		if info.IsDir() {
+			if relPath == "sections" || relPath == ".template-defaults" {
+				return filepath.SkipDir
+			}
 			return nil
 		}
-
-		targetPath := filepath.Join(configDir, relPath)
-
-		backupData, err := os.ReadFile(backupPath)
-		if err != nil {
-			return err
+		if relPath == "backup_metadata.json" {
+			return nil
 		}
 
-		if _, err := os.Stat(targetPath); err != nil {
-			if os.IsNotExist(err) {
-				if err := os.MkdirAll(filepath.Dir(targetPath), defs.DirPerm); err != nil {
-					return fmt.Errorf("create parent directory for %s: %w", relPath, err)
-				}
-				return os.WriteFile(targetPath, backupData, defs.FilePerm)
-			}
+		targetPath := filepath.Join(configDir, relPath)
+		if _, err := os.Stat(targetPath); !os.IsNotExist(err) {
 			return err
 		}
-
-		targetData, err := os.ReadFile(targetPath)
+		data, err := os.ReadFile(backupPath)
 		if err != nil {
 			return err
 		}
-
-		merged, err := mergeYAMLDeep(targetData, backupData)
-		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Warning: merge failed for %s, restoring backup\n", relPath)
-			return os.WriteFile(targetPath, backupData, defs.FilePerm)
+		if err := os.MkdirAll(filepath.Dir(targetPath), defs.DirPerm); err != nil {
+			return fmt.Errorf("create parent directory for %s: %w", relPath, err)
 		}
-
-		return os.WriteFile(targetPath, merged, defs.FilePerm)
+		return os.WriteFile(targetPath, data, defs.FilePerm)
 	})
 }
 
@@ -1852,6 +1875,7 @@ func applyWizardConfig(projectRoot string, result *wizard.WizardResult) error {
 var allStatuslineSegments = []string{
 	statusline.SegmentModel, statusline.SegmentContext, statusline.SegmentOutputStyle, statusline.SegmentDirectory,
 	statusline.SegmentGitStatus, statusline.SegmentClaudeVersion, statusline.SegmentMoaiVersion, statusline.SegmentGitBranch,
+	statusline.SegmentBudget, statusline.SegmentGitEvents,
 }
 
 // presetToSegments converts a statusline preset name and optional custom segment map
@@ -2060,99 +2084,7 @@ func ensureGlobalSettingsEnv() error {
 // This includes orphaned scripts that were never deployed and deprecated Python-based hooks.
 // Returns true if any cleanup was performed.
 func cleanLegacyHooks(settings map[string]any) bool {
-	// List of legacy hook patterns to remove.
-	// All moai handle-*.sh hooks belong in project-level settings, not global.
-	legacyPatterns := []string{
-		"handle-session-end.sh",
-		"handle-session-start.sh",
-		"handle-stop.sh",
-		"handle-pre-tool.sh",
-		"handle-post-tool.sh",
-		"handle-agent-hook.sh",
-		"handle-compact.sh",
-		"session_end__rank_submit",
-		"post_tool__code_formatter.py",
-		"post_tool__linter.py",
-		"post_tool__ast_grep_scan.py",
-	}
-
-	hooksMap, ok := settings["hooks"].(map[string]any)
-	if !ok {
-		return false
-	}
-
-	modified := false
-	for hookType, hookListInterface := range hooksMap {
-		hookList, ok := hookListInterface.([]any)
-		if !ok {
-			continue
-		}
-
-		var cleanedHooks []any
-		for _, hookGroup := range hookList {
-			groupMap, ok := hookGroup.(map[string]any)
-			if !ok {
-				cleanedHooks = append(cleanedHooks, hookGroup)
-				continue
-			}
-
-			hooksList, ok := groupMap["hooks"].([]any)
-			if !ok {
-				cleanedHooks = append(cleanedHooks, hookGroup)
-				continue
-			}
-
-			var cleanedGroupHooks []any
-			for _, hookEntry := range hooksList {
-				entryMap, ok := hookEntry.(map[string]any)
-				if !ok {
-					cleanedGroupHooks = append(cleanedGroupHooks, hookEntry)
-					continue
-				}
-
-				command, ok := entryMap["command"].(string)
-				if !ok {
-					cleanedGroupHooks = append(cleanedGroupHooks, hookEntry)
-					continue
-				}
-
-				// Check if command contains any legacy pattern
-				shouldRemove := false
-				for _, pattern := range legacyPatterns {
-					if strings.Contains(command, pattern) {
-						shouldRemove = true
-						break
-					}
-				}
-
-				if shouldRemove {
-					modified = true
-				} else {
-					cleanedGroupHooks = append(cleanedGroupHooks, hookEntry)
-				}
-			}
-
-			if len(cleanedGroupHooks) > 0 {
-				groupMap["hooks"] = cleanedGroupHooks
-				cleanedHooks = append(cleanedHooks, groupMap)
-			} else {
-				modified = true
-			}
-		}
-
-		if len(cleanedHooks) > 0 {
-			hooksMap[hookType] = cleanedHooks
-		} else {
-			delete(hooksMap, hookType)
-			modified = true
-		}
-	}
-
-	if modified && len(hooksMap) == 0 {
-		delete(settings, "hooks")
-	}
-
-	return modified
+	return migration.PruneHookCommands(settings, migration.LegacyHookPatterns)
 }
 
 // execCommand executes a command and returns its output.
@@ -0,0 +1,312 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/modu-ai/moai-adk/internal/defs"
+	"github.com/modu-ai/moai-adk/internal/update"
+	"github.com/modu-ai/moai-adk/pkg/version"
+)
+
+// errVersionConstraint indicates the running binary does not satisfy
+// moai.required_version from system.yaml.
+var errVersionConstraint = errors.New("moai version does not satisfy required_version")
+
+// versionCheckExemptCommands lists top-level commands that must keep working
+// when the binary violates moai.required_version, so users can fix the pin.
+// The git hooks run on every commit and push and fail open.
+var versionCheckExemptCommands = []string{
+	"update", "version", "help", "completion", "doctor", "init", "hook", "statusline", "git",
+}
+
+// updatePolicy holds the release channel and version pin read from system.yaml.
+type updatePolicy struct {
+	// RequiredVersion is a constraint such as ">=2.1.0, <3.0.0" (empty = unpinned).
+	RequiredVersion string
+	// Channel is the release channel: stable, beta or nightly.
+	Channel update.Channel
+}
+
+// constraint parses RequiredVersion. Returns nil when no pin is configured.
+func (p *updatePolicy) constraint() (*update.Constraint, error) {
+	if strings.TrimSpace(p.RequiredVersion) == "" {
+		return nil, nil
+	}
+	c, err := update.ParseConstraint(p.RequiredVersion)
+	if err != nil {
+		return nil, fmt.Errorf("moai.required_version: %w", err)
+	}
+	return c, nil
+}
+
+// readUpdatePolicy reads moai.required_version and moai.update_channel from
+// .moai/config/sections/system.yaml. A missing file yields the default policy.
+// MOAI_UPDATE_CHANNEL overrides the configured channel.
+func readUpdatePolicy(projectRoot string) (*updatePolicy, error) {
+	policy := &updatePolicy{Channel: update.DefaultChannel}
+
+	configPath := filepath.Join(projectRoot, defs.MoAIDir, defs.SectionsSubdir, defs.SystemYAML)
+	info, err := os.Stat(configPath)
+	switch {
+	case err == nil && info.Size() > maxConfigSize:
+		return nil, fmt.Errorf("config file too large: %d bytes (max: %d)", info.Size(), maxConfigSize)
+	case err == nil:
+		data, readErr := os.ReadFile(configPath)
+		if readErr != nil {
+			return nil, fmt.Errorf("read config file: %w", readErr)
+		}
+		var config struct {
+			Moai struct {
+				RequiredVersion string `yaml:"required_version"`
+				UpdateChannel   string `yaml:"update_channel"`
+			} `yaml:"moai"`
+		}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("parse config YAML: %w", err)
+		}
+		policy.RequiredVersion = config.Moai.RequiredVersion
+		channel, chErr := update.ParseChannel(config.Moai.UpdateChannel)
+		if chErr != nil {
+			return nil, fmt.Errorf("moai.update_channel: %w", chErr)
+		}
+		policy.Channel = channel
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("stat config file: %w", err)
+	}
+
+	if env := os.Getenv("MOAI_UPDATE_CHANNEL"); env != "" {
+		channel, err := update.ParseChannel(env)
+		if err != nil {
+			return nil, fmt.Errorf("MOAI_UPDATE_CHANNEL: %w", err)
+		}
+		policy.Channel = channel
+	}
+
+	return policy, nil
+}
+
+// isDevBuildVersion reports whether v is a local development build for which
+// version pinning and self-update are meaningless.
+func isDevBuildVersion(v string) bool {
+	return strings.Contains(v, "dirty") || v == "dev" || strings.Contains(v, "none")
+}
+
+// checkRequiredVersion verifies that current satisfies moai.required_version
+// for the project at projectRoot. Dev builds and unpinned projects always pass.
+// Returns an error wrapping errVersionConstraint on violation.
+func checkRequiredVersion(projectRoot, current string) error {
+	if isDevBuildVersion(current) {
+		return nil
+	}
+	policy, err := readUpdatePolicy(projectRoot)
+	if err != nil {
+		return err
+	}
+	constraint, err := policy.constraint()
+	if err != nil || constraint == nil {
+		return err
+	}
+	if !constraint.CheckString(current) {
+		return fmt.Errorf("%w: moai-adk %s, project requires %q (run 'moai update --list' and 'moai update --to <version>')",
+			errVersionConstraint, current, constraint)
+	}
+	return nil
+}
+
+// enforceRequiredVersion is the CLI startup check for moai.required_version.
+// Commands in versionCheckExemptCommands are never blocked.
+func enforceRequiredVersion(cmd *cobra.Command) error {
+	top := cmd
+	for top.HasParent() && top.Parent().HasParent() {
+		top = top.Parent()
+	}
+	for _, name := range versionCheckExemptCommands {
+		if top.Name() == name {
+			return nil
+		}
+	}
+
+	err := checkRequiredVersion(".", version.GetVersion())
+	if errors.Is(err, errVersionConstraint) {
+		return err
+	}
+	// Unreadable or malformed config is reported by the commands that use it.
+	return nil
+}
+
+// updateLister returns the release lister for channel-aware operations,
+// initializing update dependencies on first use.
+func updateLister() (update.ReleaseLister, error) {
+	if deps == nil {
+		return nil, fmt.Errorf("dependencies not initialized")
+	}
+	if err := deps.EnsureUpdate(); err != nil {
+		return nil, fmt.Errorf("initialize update system: %w", err)
+	}
+	if deps.UpdateLister == nil {
+		return nil, fmt.Errorf("update source does not support listing releases")
+	}
+	return deps.UpdateLister, nil
+}
+
+// resolveChannelFlag returns the --channel flag value when set, otherwise
+// the channel from the project policy.
+func resolveChannelFlag(cmd *cobra.Command, policy *updatePolicy) (update.Channel, error) {
+	if f := cmd.Flags().Lookup("channel"); f != nil && f.Changed {
+		return update.ParseChannel(f.Value.String())
+	}
+	return policy.Channel, nil
+}
+
+// runUpdateList prints the releases available on the selected channel,
+// marking the running version and versions excluded by required_version.
+func runUpdateList(cmd *cobra.Command) error {
+	out := cmd.OutOrStdout()
+
+	policy, err := readUpdatePolicy(".")
+	if err != nil {
+		return err
+	}
+	channel, err := resolveChannelFlag(cmd, policy)
+	if err != nil {
+		return err
+	}
+	constraint, err := policy.constraint()
+	if err != nil {
+		return err
+	}
+
+	lister, err := updateLister()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	releases, err := lister.ListReleases(ctx)
+	if err != nil {
+		return fmt.Errorf("list releases: %w", err)
+	}
+	releases = update.FilterReleases(releases, channel, nil)
+
+	_, _ = fmt.Fprintf(out, "Channel: %s\n", channel)
+	if constraint != nil {
+		_, _ = fmt.Fprintf(out, "Required version: %s\n", constraint)
+	}
+	_, _ = fmt.Fprintln(out)
+
+	if len(releases) == 0 {
+		_, _ = fmt.Fprintf(out, "No releases found on the %s channel.\n", channel)
+		return nil
+	}
+
+	current := version.GetVersion()
+	for _, r := range releases {
+		marker := " "
+		if update.FindRelease([]update.VersionInfo{r}, current) != nil {
+			marker = "*"
+		}
+		line := fmt.Sprintf("  %s %-24s", marker, r.Version)
+		if !r.Date.IsZero() {
+			line += "  " + r.Date.Format("2006-01-02")
+		}
+		if constraint != nil && !constraint.CheckString(r.Version) {
+			line += "  " + cliMuted.Render("(excluded by required_version)")
+		}
+		_, _ = fmt.Fprintln(out, line)
+	}
+	_, _ = fmt.Fprintln(out, "\n  * = installed. Install a specific version with: moai update --to <version>")
+	return nil
+}
+
+// runUpdateTo installs exactly the requested version (upgrade or downgrade)
+// and then re-execs the installed binary to synchronize its templates, so
+// project files match the version that is now running.
+func runUpdateTo(cmd *cobra.Command, target string) error {
+	out := cmd.OutOrStdout()
+	force := getBoolFlag(cmd, "force")
+	current := version.GetVersion()
+
+	policy, err := readUpdatePolicy(".")
+	if err != nil {
+		return err
+	}
+	constraint, err := policy.constraint()
+	if err != nil {
+		return err
+	}
+	if constraint != nil && !constraint.CheckString(target) && !force {
+		return fmt.Errorf("version %s does not satisfy moai.required_version %q (use --force to override)", target, constraint)
+	}
+
+	lister, err := updateLister()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 120*time.Second)
+	defer cancel()
+
+	info, err := lister.ResolveRelease(ctx, target)
+	if err != nil {
+		return fmt.Errorf("resolve release %s: %w", target, err)
+	}
+
+	if deps.UpdatePinned == nil {
+		return fmt.Errorf("update source does not support pinned installs")
+	}
+
+	direction := "Installing"
+	if cmp, ok := compareVersions(info.Version, current); ok && cmp < 0 {
+		direction = "Downgrading to"
+	}
+	_, _ = fmt.Fprintf(out, "%s %s (current: %s)...\n", direction, info.Version, current)
+
+	result, err := deps.UpdatePinned(info).Update(ctx)
+	if err != nil {
+		if errors.Is(err, update.ErrUpdateNotAvail) {
+			_, _ = fmt.Fprintf(out, "Already running %s.\n", info.Version)
+			return nil
+		}
+		return fmt.Errorf("install %s: %w", info.Version, err)
+	}
+	_, _ = fmt.Fprintf(out, "Installed: %s -> %s\n", result.PreviousVersion, result.NewVersion)
+
+	if getBoolFlag(cmd, "binary") {
+		_, _ = fmt.Fprintln(out, "Template sync skipped (--binary).")
+		return nil
+	}
+
+	// Re-exec the installed binary so its embedded templates are deployed.
+	// Only long-standing flags are passed because the target may be older.
+	args := []string{"update", "--templates-only", "--force"}
+	if getBoolFlag(cmd, "yes") {
+		args = append(args, "--yes")
+	}
+	if err := reexecWithArgs(args); err != nil {
+		_, _ = fmt.Fprintf(out, "Warning: failed to run template sync with %s: %v\n", info.Version, err)
+		_, _ = fmt.Fprintln(out, "Run 'moai update --templates-only --force' to migrate project templates.")
+	}
+	return nil
+}
+
+// compareVersions compares two version strings with semver precedence.
+// ok is false when either version cannot be parsed.
+func compareVersions(a, b string) (cmp int, ok bool) {
+	av, aErr := update.ParseSemver(a)
+	bv, bErr := update.ParseSemver(b)
+	if aErr != nil || bErr != nil {
+		return 0, false
+	}
+	return av.Compare(bv), true
+}
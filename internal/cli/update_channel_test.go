@@ -0,0 +1,325 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modu-ai/moai-adk/internal/hook"
+	"github.com/modu-ai/moai-adk/internal/update"
+	"github.com/spf13/cobra"
+)
+
+// mockReleaseLister implements update.ReleaseLister for testing.
+type mockReleaseLister struct {
+	releases []update.VersionInfo
+}
+
+func (m *mockReleaseLister) ListReleases(_ context.Context) ([]update.VersionInfo, error) {
+	return m.releases, nil
+}
+
+func (m *mockReleaseLister) ResolveRelease(_ context.Context, v string) (*update.VersionInfo, error) {
+	if r := update.FindRelease(m.releases, v); r != nil {
+		return r, nil
+	}
+	return nil, errors.New("release not found")
+}
+
+// writeSystemYAML writes a system.yaml with the given moai section body.
+func writeSystemYAML(t *testing.T, root, body string) {
+	t.Helper()
+	dir := filepath.Join(root, ".moai", "config", "sections")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "system.yaml"), []byte("moai:\n"+body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadUpdatePolicy(t *testing.T) {
+	t.Setenv("MOAI_UPDATE_CHANNEL", "")
+
+	t.Run("missing file uses defaults", func(t *testing.T) {
+		policy, err := readUpdatePolicy(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if policy.Channel != update.ChannelStable || policy.RequiredVersion != "" {
+			t.Errorf("policy = %+v, want stable/unpinned", policy)
+		}
+	})
+
+	t.Run("reads channel and pin", func(t *testing.T) {
+		root := t.TempDir()
+		writeSystemYAML(t, root, "  update_channel: beta\n  required_version: \">=2.1.0, <3\"\n")
+		policy, err := readUpdatePolicy(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if policy.Channel != update.ChannelBeta {
+			t.Errorf("Channel = %q, want beta", policy.Channel)
+		}
+		if policy.RequiredVersion != ">=2.1.0, <3" {
+			t.Errorf("RequiredVersion = %q", policy.RequiredVersion)
+		}
+	})
+
+	t.Run("env overrides channel", func(t *testing.T) {
+		root := t.TempDir()
+		writeSystemYAML(t, root, "  update_channel: beta\n")
+		t.Setenv("MOAI_UPDATE_CHANNEL", "nightly")
+		policy, err := readUpdatePolicy(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if policy.Channel != update.ChannelNightly {
+			t.Errorf("Channel = %q, want nightly", policy.Channel)
+		}
+	})
+
+	t.Run("invalid channel", func(t *testing.T) {
+		root := t.TempDir()
+		writeSystemYAML(t, root, "  update_channel: edge\n")
+		if _, err := readUpdatePolicy(root); err == nil {
+			t.Error("expected error for unknown channel")
+		}
+	})
+}
+
+func TestCheckRequiredVersion(t *testing.T) {
+	t.Setenv("MOAI_UPDATE_CHANNEL", "")
+	root := t.TempDir()
+	writeSystemYAML(t, root, "  required_version: \"~2.1\"\n")
+
+	if err := checkRequiredVersion(root, "v2.1.4"); err != nil {
+		t.Errorf("2.1.4 should satisfy ~2.1: %v", err)
+	}
+	err := checkRequiredVersion(root, "v2.5.1")
+	if !errors.Is(err, errVersionConstraint) {
+		t.Fatalf("expected errVersionConstraint, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "moai update --to") {
+		t.Errorf("error should suggest moai update --to, got %q", err)
+	}
+	if err := checkRequiredVersion(root, "dev"); err != nil {
+		t.Errorf("dev builds should be exempt: %v", err)
+	}
+	if err := checkRequiredVersion(t.TempDir(), "v0.0.1"); err != nil {
+		t.Errorf("unpinned project should pass: %v", err)
+	}
+}
+
+func TestEnforceRequiredVersion_ExemptCommands(t *testing.T) {
+	t.Setenv("MOAI_UPDATE_CHANNEL", "")
+	root := t.TempDir()
+	writeSystemYAML(t, root, "  required_version: \"<1.0.0\"\n")
+	t.Chdir(root)
+
+	if err := enforceRequiredVersion(updateCmd); err != nil {
+		t.Errorf("update must stay usable under a violated pin: %v", err)
+	}
+	if err := enforceRequiredVersion(versionCmd); err != nil {
+		t.Errorf("version must stay usable under a violated pin: %v", err)
+	}
+	if err := enforceRequiredVersion(statusCmd); !errors.Is(err, errVersionConstraint) {
+		t.Errorf("status should be blocked, got %v", err)
+	}
+}
+
+func TestRunHookEvent_RequiredVersion(t *testing.T) {
+	t.Setenv("MOAI_UPDATE_CHANNEL", "")
+	root := t.TempDir()
+	writeSystemYAML(t, root, "  required_version: \"<1.0.0\"\n")
+
+	origDeps := deps
+	defer func() { deps = origDeps }()
+
+	tests := []struct {
+		event    hook.EventType
+		wantErr  bool
+		wantJSON string
+	}{
+		{hook.EventSessionStart, false, `"systemMessage"`},
+		{hook.EventPreToolUse, false, `"permissionDecision":"ask"`},
+		{hook.EventPermissionRequest, false, `"permissionDecision":"ask"`},
+		{hook.EventPostToolUse, true, ""},
+		{hook.EventWorktreeCreate, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.event), func(t *testing.T) {
+			dispatched := false
+			var written *hook.HookOutput
+			deps = &Dependencies{
+				HookProtocol: &mockHookProtocol{
+					readInputFunc: func(io.Reader) (*hook.HookInput, error) {
+						return &hook.HookInput{SessionID: "s", CWD: root}, nil
+					},
+					writeOutputFunc: func(_ io.Writer, output *hook.HookOutput) error {
+						written = output
+						return nil
+					},
+				},
+				HookRegistry: &mockHookRegistry{
+					dispatchFunc: func(context.Context, hook.EventType, *hook.HookInput) (*hook.HookOutput, error) {
+						dispatched = true
+						return &hook.HookOutput{}, nil
+					},
+				},
+			}
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+
+			err := runHookEvent(cmd, tt.event)
+			if dispatched {
+				t.Error("handlers ran on a binary violating required_version")
+			}
+			if tt.wantErr != errors.Is(err, errVersionConstraint) {
+				t.Errorf("err = %v, want errVersionConstraint %v", err, tt.wantErr)
+			}
+			if tt.wantJSON == "" {
+				return
+			}
+			data, _ := json.Marshal(written)
+			if !strings.Contains(string(data), tt.wantJSON) || !strings.Contains(string(data), "required_version") {
+				t.Errorf("output = %s", data)
+			}
+		})
+	}
+
+	// An unpinned project dispatches normally.
+	dispatched := false
+	deps = &Dependencies{
+		HookProtocol: &mockHookProtocol{},
+		HookRegistry: &mockHookRegistry{
+			dispatchFunc: func(context.Context, hook.EventType, *hook.HookInput) (*hook.HookOutput, error) {
+				dispatched = true
+				return &hook.HookOutput{}, nil
+			},
+		},
+	}
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	t.Chdir(t.TempDir())
+	if err := runHookEvent(cmd, hook.EventPostToolUse); err != nil || !dispatched {
+		t.Errorf("unpinned runHookEvent = %v, dispatched %v", err, dispatched)
+	}
+}
+
+// newUpdateChannelTestCmd builds a command with the update flags used by
+// the channel helpers, isolated from the global updateCmd.
+func newUpdateChannelTestCmd(buf *bytes.Buffer) *cobra.Command {
+	cmd := &cobra.Command{Use: "update"}
+	cmd.Flags().String("channel", "", "")
+	cmd.Flags().Bool("force", false, "")
+	cmd.Flags().Bool("binary", false, "")
+	cmd.Flags().Bool("yes", false, "")
+	cmd.SetOut(buf)
+	cmd.SetContext(context.Background())
+	return cmd
+}
+
+func TestRunUpdateList(t *testing.T) {
+	t.Setenv("MOAI_UPDATE_CHANNEL", "")
+	root := t.TempDir()
+	writeSystemYAML(t, root, "  update_channel: beta\n  required_version: \"<2.5.0\"\n")
+	t.Chdir(root)
+
+	origDeps := deps
+	defer func() { deps = origDeps }()
+	deps = &Dependencies{
+		UpdateChecker: &mockUpdateChecker{},
+		UpdateLister: &mockReleaseLister{releases: []update.VersionInfo{
+			{Version: "v2.4.0"},
+			{Version: "v2.5.1"},
+			{Version: "v2.6.0-beta.1"},
+			{Version: "v2.6.0-nightly.1"},
+		}},
+	}
+
+	buf := new(bytes.Buffer)
+	if err := runUpdateList(newUpdateChannelTestCmd(buf)); err != nil {
+		t.Fatalf("runUpdateList: %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{"Channel: beta", "Required version: <2.5.0", "v2.6.0-beta.1", "* v2.5.1", "excluded by required_version"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "nightly") {
+		t.Errorf("beta channel should hide nightly builds:\n%s", output)
+	}
+	if strings.Index(output, "v2.6.0-beta.1") > strings.Index(output, "v2.4.0") {
+		t.Errorf("releases should be listed newest first:\n%s", output)
+	}
+}
+
+func TestRunUpdateTo(t *testing.T) {
+	t.Setenv("MOAI_UPDATE_CHANNEL", "")
+	root := t.TempDir()
+	writeSystemYAML(t, root, "  required_version: \">=2.0.0\"\n")
+	t.Chdir(root)
+
+	origDeps := deps
+	defer func() { deps = origDeps }()
+
+	var installed string
+	deps = &Dependencies{
+		UpdateChecker: &mockUpdateChecker{},
+		UpdateLister: &mockReleaseLister{releases: []update.VersionInfo{
+			{Version: "v1.9.0"}, {Version: "v2.0.0"},
+		}},
+		UpdatePinned: func(info *update.VersionInfo) update.Orchestrator {
+			return &mockUpdateOrchestrator{
+				updateFunc: func(_ context.Context) (*update.UpdateResult, error) {
+					installed = info.Version
+					return &update.UpdateResult{PreviousVersion: "v2.5.1", NewVersion: info.Version}, nil
+				},
+			}
+		},
+	}
+
+	t.Run("downgrade within pin", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		cmd := newUpdateChannelTestCmd(buf)
+		if err := cmd.Flags().Set("binary", "true"); err != nil {
+			t.Fatal(err)
+		}
+		if err := runUpdateTo(cmd, "2.0.0"); err != nil {
+			t.Fatalf("runUpdateTo: %v", err)
+		}
+		if installed != "v2.0.0" {
+			t.Errorf("installed = %q, want v2.0.0", installed)
+		}
+		if !strings.Contains(buf.String(), "Downgrading to v2.0.0") {
+			t.Errorf("output should announce downgrade:\n%s", buf.String())
+		}
+	})
+
+	t.Run("rejects version outside pin", func(t *testing.T) {
+		installed = ""
+		err := runUpdateTo(newUpdateChannelTestCmd(new(bytes.Buffer)), "1.9.0")
+		if err == nil || !strings.Contains(err.Error(), "required_version") {
+			t.Errorf("expected required_version error, got %v", err)
+		}
+		if installed != "" {
+			t.Errorf("nothing should be installed, got %q", installed)
+		}
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		err := runUpdateTo(newUpdateChannelTestCmd(new(bytes.Buffer)), "2.9.9")
+		if err == nil || !strings.Contains(err.Error(), "resolve release") {
+			t.Errorf("expected resolve error, got %v", err)
+		}
+	})
+}
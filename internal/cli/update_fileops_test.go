@@ -223,158 +223,9 @@ func TestSaveTemplateDefaults_OverwritesExisting(t *testing.T) {
 	}
 }
 
-// --- restoreMoaiConfigLegacy tests ---
-
-func TestRestoreMoaiConfigLegacy_RestoresFiles(t *testing.T) {
-	tmpDir := t.TempDir()
-
-	// Create the config directory (simulating freshly deployed templates)
-	configDir := filepath.Join(tmpDir, defs.MoAIDir, defs.ConfigSubdir)
-	if err := os.MkdirAll(configDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	// Create a backup directory with a legacy file
-	backupDir := filepath.Join(tmpDir, "backup-legacy")
-	if err := os.MkdirAll(backupDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	// Write a file in the backup (not in sections/ subdirectory, legacy format)
-	backupFile := filepath.Join(backupDir, "custom.yaml")
-	backupContent := []byte("custom:\n  key: legacy-value\n")
-	if err := os.WriteFile(backupFile, backupContent, 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	err := restoreMoaiConfigLegacy(tmpDir, backupDir, configDir)
-	if err != nil {
-		t.Fatalf("restoreMoaiConfigLegacy failed: %v", err)
-	}
-
-	// Verify the file was restored to the config directory
-	restoredPath := filepath.Join(configDir, "custom.yaml")
-	data, err := os.ReadFile(restoredPath)
-	if err != nil {
-		t.Fatalf("read restored file: %v", err)
-	}
-	if !strings.Contains(string(data), "legacy-value") {
-		t.Errorf("restored file should contain backup content, got:\n%s", string(data))
-	}
-}
-
-func TestRestoreMoaiConfigLegacy_SkipsMetadata(t *testing.T) {
-	tmpDir := t.TempDir()
-
-	configDir := filepath.Join(tmpDir, defs.MoAIDir, defs.ConfigSubdir)
-	if err := os.MkdirAll(configDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	backupDir := filepath.Join(tmpDir, "backup-legacy")
-	if err := os.MkdirAll(backupDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	// Write backup_metadata.json (should be skipped)
-	metadataFile := filepath.Join(backupDir, "backup_metadata.json")
-	if err := os.WriteFile(metadataFile, []byte(`{"timestamp":"test"}`), 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	// Write .template-defaults/ directory (should be skipped)
-	templateDefaultsDir := filepath.Join(backupDir, ".template-defaults")
-	if err := os.MkdirAll(templateDefaultsDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-	if err := os.WriteFile(filepath.Join(templateDefaultsDir, "test.yaml"), []byte("skip: me"), 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	err := restoreMoaiConfigLegacy(tmpDir, backupDir, configDir)
-	if err != nil {
-		t.Fatalf("restoreMoaiConfigLegacy failed: %v", err)
-	}
-
-	// backup_metadata.json should NOT be restored to config dir
-	restoredMeta := filepath.Join(configDir, "backup_metadata.json")
-	if _, err := os.Stat(restoredMeta); !os.IsNotExist(err) {
-		t.Error("backup_metadata.json should not be restored to config directory")
-	}
-
-	// .template-defaults/ files should NOT be restored
-	restoredDefaults := filepath.Join(configDir, ".template-defaults")
-	if _, err := os.Stat(restoredDefaults); !os.IsNotExist(err) {
-		t.Error(".template-defaults should not be restored to config directory")
-	}
-}
-
-func TestRestoreMoaiConfigLegacy_MergesWithExistingTarget(t *testing.T) {
-	tmpDir := t.TempDir()
-
-	configDir := filepath.Join(tmpDir, defs.MoAIDir, defs.ConfigSubdir)
-	if err := os.MkdirAll(configDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	// Create an existing target file (simulating new template)
-	targetPath := filepath.Join(configDir, "user.yaml")
-	targetContent := []byte("user:\n  name: new-template\n  new_field: added\n")
-	if err := os.WriteFile(targetPath, targetContent, 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	// Create a backup with user's old config
-	backupDir := filepath.Join(tmpDir, "backup-legacy")
-	if err := os.MkdirAll(backupDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-	backupContent := []byte("user:\n  name: my-custom-name\n")
-	if err := os.WriteFile(filepath.Join(backupDir, "user.yaml"), backupContent, 0o644); err != nil {
-		t.Fatal(err)
-	}
-
-	err := restoreMoaiConfigLegacy(tmpDir, backupDir, configDir)
-	if err != nil {
-		t.Fatalf("restoreMoaiConfigLegacy failed: %v", err)
-	}
-
-	// Read the merged result
-	data, err := os.ReadFile(targetPath)
-	if err != nil {
-		t.Fatalf("read merged file: %v", err)
-	}
-
-	// User's name should be merged in
-	if !strings.Contains(string(data), "my-custom-name") {
-		t.Errorf("merged file should contain user's custom name, got:\n%s", string(data))
-	}
-}
-
-func TestRestoreMoaiConfigLegacy_EmptyBackup(t *testing.T) {
-	tmpDir := t.TempDir()
-
-	configDir := filepath.Join(tmpDir, defs.MoAIDir, defs.ConfigSubdir)
-	if err := os.MkdirAll(configDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	// Empty backup directory
-	backupDir := filepath.Join(tmpDir, "backup-empty")
-	if err := os.MkdirAll(backupDir, 0o755); err != nil {
-		t.Fatal(err)
-	}
-
-	// Should complete without error
-	err := restoreMoaiConfigLegacy(tmpDir, backupDir, configDir)
-	if err != nil {
-		t.Fatalf("restoreMoaiConfigLegacy should succeed with empty backup, got: %v", err)
-	}
-}
-
 // --- restoreMoaiConfig (3-way merge path) tests ---
 
-func TestRestoreMoaiConfig_FallsBackToLegacyWhenNoSections(t *testing.T) {
+func TestRestoreMoaiConfig_RestoresUserFilesOutsideSections(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Create config directory
@@ -383,29 +234,42 @@ func TestRestoreMoaiConfig_FallsBackToLegacyWhenNoSections(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// Create backup without sections/ directory (legacy format)
+	// Backup with a user file outside sections/, a file the new templates
+	// also deployed, and backup bookkeeping that must not be restored.
 	backupDir := filepath.Join(tmpDir, "backup")
-	if err := os.MkdirAll(backupDir, 0o755); err != nil {
-		t.Fatal(err)
+	files := map[string]string{
+		"custom.yaml":                      "custom:\n  key: user\n",
+		"statusline.yaml":                  "mode: user\n",
+		"backup_metadata.json":             "{}",
+		".template-defaults/defaults.yaml": "a: 1\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(backupDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
 	}
-	// Put a YAML file at root level of backup
-	if err := os.WriteFile(filepath.Join(backupDir, "user.yaml"), []byte("user:\n  name: legacy\n"), 0o644); err != nil {
+	if err := os.WriteFile(filepath.Join(configDir, "statusline.yaml"), []byte("mode: template\n"), 0o644); err != nil {
 		t.Fatal(err)
 	}
 
-	err := restoreMoaiConfig(tmpDir, backupDir)
-	if err != nil {
-		t.Fatalf("restoreMoaiConfig should fall back to legacy, got: %v", err)
+	if err := restoreMoaiConfig(tmpDir, backupDir); err != nil {
+		t.Fatalf("restoreMoaiConfig failed: %v", err)
 	}
 
-	// The file should be restored via the legacy path
-	restoredPath := filepath.Join(configDir, "user.yaml")
-	data, err := os.ReadFile(restoredPath)
-	if err != nil {
-		t.Fatalf("read restored file: %v", err)
+	if data, err := os.ReadFile(filepath.Join(configDir, "custom.yaml")); err != nil || string(data) != files["custom.yaml"] {
+		t.Errorf("custom.yaml = %q, %v", data, err)
 	}
-	if !strings.Contains(string(data), "legacy") {
-		t.Errorf("expected legacy content to be restored, got:\n%s", string(data))
+	if data, _ := os.ReadFile(filepath.Join(configDir, "statusline.yaml")); string(data) != "mode: template\n" {
+		t.Errorf("deployed statusline.yaml was overwritten: %q", data)
+	}
+	for _, rel := range []string{"backup_metadata.json", ".template-defaults"} {
+		if _, err := os.Stat(filepath.Join(configDir, rel)); !os.IsNotExist(err) {
+			t.Errorf("%s was restored: %v", rel, err)
+		}
 	}
 }
 
@@ -662,4 +526,3 @@ func TestGetProjectConfigVersion_ValidFile(t *testing.T) {
 		t.Errorf("getProjectConfigVersion = %q, want %q", ver, "2.5.0")
 	}
 }
-
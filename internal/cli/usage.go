@@ -0,0 +1,367 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/defs"
+	"github.com/modu-ai/moai-adk/internal/rank"
+	"github.com/modu-ai/moai-adk/internal/statusline"
+	"github.com/modu-ai/moai-adk/internal/usage"
+)
+
+// findUsageTranscripts locates transcripts for usage analytics.
+// Tests replace it to point at fixture files.
+var findUsageTranscripts = rank.FindTranscripts
+
+// openTranscriptIndex opens the shared transcript index under ~/.moai.
+// Returns nil when it is unavailable, in which case callers parse
+// transcripts in full.
+func openTranscriptIndex() *rank.TranscriptIndex {
+	idx, err := rank.OpenTranscriptIndex("")
+	if err != nil {
+		return nil
+	}
+	return idx
+}
+
+// usageNow returns the current time. Tests replace it for stable output.
+var usageNow = time.Now
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show token usage and cost from local transcripts",
+	Long: `Aggregate token usage and cost from local Claude Code transcripts,
+without logging in or uploading anything.
+
+Usage is grouped by day, project, model or session and shows input, output
+and cache tokens, the cache hit ratio and estimated cost. Spending limits
+from .moai/config/sections/pricing.yaml are reported alongside.
+
+Examples:
+  moai usage
+  moai usage --by model --since 7d
+  moai usage --by project --since 2026-01-01 --format csv --output usage.csv`,
+	Args: cobra.NoArgs,
+	RunE: runUsage,
+}
+
+func init() {
+	rootCmd.AddCommand(usageCmd)
+
+	usageCmd.Flags().String("by", string(usage.GroupByDay), "Group by: day, project, model, session")
+	usageCmd.Flags().String("since", "30d", "Start of range: a date (YYYY-MM-DD), Nd for N days ago, or 'today'")
+	usageCmd.Flags().String("until", "", "End of range (exclusive date, YYYY-MM-DD)")
+	usageCmd.Flags().String("project", "", "Only include this project")
+	usageCmd.Flags().String("model", "", "Only include this model")
+	usageCmd.Flags().String("format", "table", "Output format: table, csv, json")
+	usageCmd.Flags().StringP("output", "o", "", "Write csv/json output to a file instead of stdout")
+	usageCmd.Flags().Int("limit", 0, "Show at most N groups (0 = all)")
+}
+
+func runUsage(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+	now := usageNow()
+
+	byFlag, _ := cmd.Flags().GetString("by")
+	by, err := usage.ParseGroupBy(byFlag)
+	if err != nil {
+		return err
+	}
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	since, err := parseUsageSince(sinceFlag, now)
+	if err != nil {
+		return err
+	}
+	untilFlag, _ := cmd.Flags().GetString("until")
+	var until time.Time
+	if untilFlag != "" {
+		if until, err = time.ParseInLocation(time.DateOnly, untilFlag, now.Location()); err != nil {
+			return fmt.Errorf("invalid --until %q: want YYYY-MM-DD", untilFlag)
+		}
+	}
+	format, _ := cmd.Flags().GetString("format")
+	if format != "table" && format != "csv" && format != "json" {
+		return fmt.Errorf("invalid --format %q: want table, csv or json", format)
+	}
+	project, _ := cmd.Flags().GetString("project")
+	model, _ := cmd.Flags().GetString("model")
+	limit, _ := cmd.Flags().GetInt("limit")
+	outputPath, _ := cmd.Flags().GetString("output")
+
+	paths, err := findUsageTranscripts()
+	if err != nil {
+		return fmt.Errorf("find transcripts: %w", err)
+	}
+
+	// Read from the start of the month as well so budget spend is complete.
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	collectSince := monthStart
+	if !since.IsZero() && since.Before(collectSince) {
+		collectSince = since
+	}
+	all := usage.Collect(openTranscriptIndex(), paths, collectSince)
+
+	filter := usage.Filter{Since: since, Until: until, Project: project, Model: model}
+	records := filter.Apply(all)
+
+	report := &usage.Report{
+		GroupBy:  by,
+		Totals:   usage.Sum(records),
+		Groups:   usage.Summarize(records, by, now.Location()),
+		Unpriced: usage.UnpricedModels(records),
+	}
+	if !since.IsZero() {
+		report.Since = since.Format(time.DateOnly)
+	}
+	if !until.IsZero() {
+		report.Until = until.Format(time.DateOnly)
+	}
+	if limit > 0 && len(report.Groups) > limit {
+		report.Groups = report.Groups[:limit]
+	}
+
+	if format != "table" {
+		w := out
+		if outputPath != "" {
+			f, err := os.Create(outputPath)
+			if err != nil {
+				return fmt.Errorf("create output: %w", err)
+			}
+			defer func() { _ = f.Close() }()
+			w = f
+		}
+		if format == "csv" {
+			err = usage.WriteCSV(w, report)
+		} else {
+			err = usage.WriteJSON(w, report)
+		}
+		if err != nil {
+			return err
+		}
+		if outputPath != "" {
+			_, _ = fmt.Fprintf(out, "%s Wrote %d %s group(s) to %s\n", symSuccess(), len(report.Groups), by, outputPath)
+		}
+		return nil
+	}
+
+	chartEnd := now
+	if !until.IsZero() {
+		chartEnd = until.AddDate(0, 0, -1)
+	}
+	chartStart := since
+	if chartStart.IsZero() {
+		chartStart = chartEnd.AddDate(0, 0, -29)
+	}
+
+	projectRoot, _ := findProjectRoot() //nolint:errcheck // budget is optional outside a project
+	budget := loadUsageBudget(projectRoot)
+	spend := usage.ComputeSpend(all, now)
+
+	printUsageSummary(out, report, usage.DailyCosts(records, chartStart, chartEnd, now.Location()), budget, spend)
+	if len(report.Groups) > 0 {
+		trends := usage.Trends(records, by, chartStart, chartEnd, now.Location())
+		_, _ = fmt.Fprintln(out)
+		printUsageTable(out, report, trends)
+	}
+	return nil
+}
+
+// parseUsageSince parses --since as YYYY-MM-DD, Nd (N days ago, from the
+// start of that day), "today", or "all"/"" for no lower bound.
+func parseUsageSince(s string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch s {
+	case "", "all":
+		return time.Time{}, nil
+	case "today":
+		return today, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return time.Time{}, fmt.Errorf("invalid --since %q: want Nd with N >= 0", s)
+		}
+		return today.AddDate(0, 0, -n), nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q: want YYYY-MM-DD, Nd, today or all", s)
+	}
+	return t, nil
+}
+
+// printUsageSummary renders the totals card with the daily cost sparkline
+// and budget status.
+func printUsageSummary(out io.Writer, report *usage.Report, daily []float64, budget usage.Budget, spend usage.Spend) {
+	t := report.Totals
+	rangeLabel := "all time"
+	if report.Since != "" {
+		rangeLabel = "since " + report.Since
+	}
+	if report.Until != "" {
+		rangeLabel += " until " + report.Until
+	}
+
+	pairs := []kvPair{
+		{"Range", rangeLabel},
+		{"Sessions", fmt.Sprintf("%d (%d messages)", t.Sessions, t.Messages)},
+		{"Input", formatTokenCount(t.InputTokens)},
+		{"Output", formatTokenCount(t.OutputTokens)},
+		{"Cache write", formatTokenCount(t.CacheCreationTokens)},
+		{"Cache read", formatTokenCount(t.CacheReadTokens)},
+		{"Cache hit", fmt.Sprintf("%.1f%%", t.CacheHitRatio()*100)},
+		{"Cost", fmt.Sprintf("$%.2f", t.CostUSD)},
+	}
+	if len(daily) > 1 {
+		pairs = append(pairs, kvPair{"Daily cost", usage.Sparkline(daily)})
+	}
+	for _, st := range budget.Evaluate(spend) {
+		label := "Today"
+		if st.Period == "month" {
+			label = "This month"
+		}
+		pairs = append(pairs, kvPair{label, formatBudgetStatus(st)})
+	}
+
+	content := renderKeyValueLines(pairs)
+	if len(report.Unpriced) > 0 {
+		content += "\n\n" + cliMuted.Render("No pricing for: "+strings.Join(report.Unpriced, ", ")+" (cost counted as $0)")
+	}
+	_, _ = fmt.Fprintln(out, renderCard("Usage", content))
+}
+
+// printUsageTable renders one row per group with a daily cost trend.
+func printUsageTable(out io.Writer, report *usage.Report, trends map[string][]float64) {
+	keyWidth := len(report.GroupBy)
+	for _, g := range report.Groups {
+		keyWidth = max(keyWidth, min(len(g.Key), 40))
+	}
+
+	header := fmt.Sprintf("%-*s  %9s  %9s  %9s  %9s  %6s  %9s  %s",
+		keyWidth, strings.ToUpper(string(report.GroupBy)), "INPUT", "OUTPUT", "CACHE W", "CACHE R", "HIT", "COST", "TREND")
+	_, _ = fmt.Fprintln(out, cliMuted.Render(header))
+
+	for _, g := range report.Groups {
+		key := g.Key
+		if len(key) > keyWidth {
+			key = key[:keyWidth-1] + "…"
+		}
+		trend := ""
+		if report.GroupBy != usage.GroupByDay {
+			trend = usage.Sparkline(trends[g.Key])
+		}
+		_, _ = fmt.Fprintf(out, "%-*s  %9s  %9s  %9s  %9s  %5.1f%%  %9s  %s\n",
+			keyWidth, key,
+			formatTokenCount(g.InputTokens),
+			formatTokenCount(g.OutputTokens),
+			formatTokenCount(g.CacheCreationTokens),
+			formatTokenCount(g.CacheReadTokens),
+			g.CacheHitRatio()*100,
+			fmt.Sprintf("$%.2f", g.CostUSD),
+			trend,
+		)
+	}
+}
+
+// formatTokenCount abbreviates token counts (e.g. 1.2M, 45.3K).
+func formatTokenCount(n int64) string {
+	switch {
+	case n >= 1_000_000_000:
+		return fmt.Sprintf("%.1fB", float64(n)/1e9)
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fK", float64(n)/1e3)
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// formatBudgetStatus renders spend against a limit, colored by severity.
+func formatBudgetStatus(st usage.BudgetStatus) string {
+	text := fmt.Sprintf("$%.2f / $%.2f (%.0f%%)", st.SpentUSD, st.LimitUSD, st.Percent())
+	switch st.Level {
+	case usage.BudgetExceeded:
+		return cliError.Render(text + " over budget")
+	case usage.BudgetWarn:
+		return cliWarn.Render(text)
+	}
+	return text
+}
+
+// loadUsageBudget reads spending limits from pricing.yaml. Returns a
+// disabled budget when the file is missing or invalid.
+func loadUsageBudget(projectRoot string) usage.Budget {
+	pricing := config.NewDefaultPricingConfig()
+	if projectRoot != "" {
+		path := filepath.Join(projectRoot, defs.MoAIDir, defs.SectionsSubdir, "pricing.yaml")
+		if data, err := os.ReadFile(path); err == nil {
+			wrapper := struct {
+				Pricing *config.PricingConfig `yaml:"pricing"`
+			}{Pricing: &pricing}
+			if err := yaml.Unmarshal(data, &wrapper); err != nil {
+				return usage.Budget{}
+			}
+		}
+	}
+	return usage.Budget{
+		DailyUSD:    pricing.DailyBudgetUSD,
+		MonthlyUSD:  pricing.MonthlyBudgetUSD,
+		WarnPercent: pricing.BudgetWarnPercent,
+	}
+}
+
+// usageBudgetProvider implements statusline.BudgetProvider from local
+// transcripts, caching the computed spend between statusline renders.
+type usageBudgetProvider struct {
+	budget usage.Budget
+	cache  *usage.SpendCache
+}
+
+// newUsageBudgetProvider returns a provider for the project's budget, or
+// nil when no limit is configured so the statusline skips the work.
+func newUsageBudgetProvider(projectRoot string) statusline.BudgetProvider {
+	budget := loadUsageBudget(projectRoot)
+	if !budget.Enabled() {
+		return nil
+	}
+	return &usageBudgetProvider{budget: budget, cache: usage.NewSpendCache("", 0)}
+}
+
+// CollectBudget implements statusline.BudgetProvider.
+func (p *usageBudgetProvider) CollectBudget(_ context.Context) (*statusline.BudgetData, error) {
+	now := usageNow()
+	spend := p.cache.Get(now)
+	if spend == nil {
+		paths, err := findUsageTranscripts()
+		if err != nil {
+			return nil, err
+		}
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		computed := usage.ComputeSpend(usage.Collect(openTranscriptIndex(), paths, monthStart), now)
+		_ = p.cache.Set(computed)
+		spend = &computed
+	}
+
+	st, ok := usage.MostSevere(p.budget.Evaluate(*spend))
+	if !ok {
+		return nil, nil
+	}
+	return &statusline.BudgetData{
+		Period:    st.Period,
+		SpentUSD:  st.SpentUSD,
+		LimitUSD:  st.LimitUSD,
+		Level:     statusline.BudgetLevel(st.Level),
+		Available: true,
+	}, nil
+}
@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/statusline"
+	"github.com/modu-ai/moai-adk/internal/usage"
+)
+
+// setupUsageFixture writes a transcript and points usage discovery at it,
+// with the clock fixed at 2026-03-03 15:00 UTC. HOME is isolated so the
+// transcript index and spend cache are private to the test.
+func setupUsageFixture(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sess.jsonl")
+	content := `{"timestamp":"2026-03-01T10:00:00Z","type":"assistant","sessionId":"s1","cwd":"/w/api","message":{"model":"claude-opus-4-5","usage":{"input_tokens":1000000,"cache_read_input_tokens":1000000}}}
+{"timestamp":"2026-03-03T10:00:00Z","type":"assistant","sessionId":"s2","cwd":"/w/web","message":{"model":"claude-sonnet-4-5","usage":{"input_tokens":1000000}}}
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	origFind, origNow := findUsageTranscripts, usageNow
+	findUsageTranscripts = func() ([]string, error) { return []string{path}, nil }
+	usageNow = func() time.Time { return time.Date(2026, 3, 3, 15, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() {
+		findUsageTranscripts, usageNow = origFind, origNow
+	})
+}
+
+func newUsageTestCmd(t *testing.T, flags map[string]string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	cmd := &cobra.Command{RunE: runUsage}
+	cmd.Flags().AddFlagSet(usageCmd.Flags())
+	for k, v := range flags {
+		if err := cmd.Flags().Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+		// The flag set is shared with usageCmd; restore defaults afterwards.
+		f := cmd.Flags().Lookup(k)
+		t.Cleanup(func() {
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		})
+	}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	return cmd, buf
+}
+
+func TestParseUsageSince(t *testing.T) {
+	now := time.Date(2026, 3, 3, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"all", "", false},
+		{"today", "2026-03-03", false},
+		{"7d", "2026-02-24", false},
+		{"0d", "2026-03-03", false},
+		{"2026-01-15", "2026-01-15", false},
+		{"-1d", "", true},
+		{"xd", "", true},
+		{"yesterday", "", true},
+	}
+	for _, tt := range tests {
+		got, err := parseUsageSince(tt.in, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseUsageSince(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		gotStr := ""
+		if !got.IsZero() {
+			gotStr = got.Format(time.DateOnly)
+		}
+		if !tt.wantErr && gotStr != tt.want {
+			t.Errorf("parseUsageSince(%q) = %q, want %q", tt.in, gotStr, tt.want)
+		}
+	}
+}
+
+func TestRunUsage_Table(t *testing.T) {
+	setupUsageFixture(t)
+	t.Chdir(t.TempDir())
+
+	cmd, buf := newUsageTestCmd(t, map[string]string{"by": "project", "since": "7d"})
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Usage", "since 2026-02-24", "Cache hit", "PROJECT", "api", "web", "$8.50"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunUsage_JSONExport(t *testing.T) {
+	setupUsageFixture(t)
+	t.Chdir(t.TempDir())
+	outFile := filepath.Join(t.TempDir(), "usage.json")
+
+	cmd, buf := newUsageTestCmd(t, map[string]string{"by": "model", "since": "all", "format": "json", "output": outFile})
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Wrote 2 model group(s)") {
+		t.Errorf("stdout = %q", buf.String())
+	}
+
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report usage.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.GroupBy != usage.GroupByModel || len(report.Groups) != 2 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestRunUsage_InvalidFlags(t *testing.T) {
+	setupUsageFixture(t)
+	for _, flags := range []map[string]string{
+		{"by": "week"},
+		{"format": "xml"},
+		{"since": "soon"},
+		{"until": "03/01"},
+	} {
+		cmd, _ := newUsageTestCmd(t, flags)
+		if err := cmd.RunE(cmd, nil); err == nil {
+			t.Errorf("flags %v: expected error", flags)
+		}
+	}
+}
+
+func TestUsageBudgetProvider(t *testing.T) {
+	setupUsageFixture(t)
+
+	root := t.TempDir()
+	if newUsageBudgetProvider(root) != nil {
+		t.Fatal("provider should be nil without a configured budget")
+	}
+
+	dir := filepath.Join(root, ".moai", "config", "sections")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	pricing := "pricing:\n  daily_budget_usd: 3.5\n  monthly_budget_usd: 100\n"
+	if err := os.WriteFile(filepath.Join(dir, "pricing.yaml"), []byte(pricing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := newUsageBudgetProvider(root)
+	if provider == nil {
+		t.Fatal("provider should be created when a budget is set")
+	}
+	data, err := provider.CollectBudget(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Today's sonnet usage is $3.00 of $3.50: above the default 80% warning.
+	if data.Period != "day" || data.SpentUSD != 3.0 || data.Level != statusline.BudgetWarn {
+		t.Errorf("budget = %+v", data)
+	}
+}
@@ -0,0 +1,293 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/core/git"
+	"github.com/modu-ai/moai-adk/internal/core/quality"
+	"github.com/modu-ai/moai-adk/internal/workflow"
+)
+
+var workflowCmd = &cobra.Command{
+	Use:   "workflow",
+	Short: "Run the Plan-Run-Sync workflow headlessly",
+	Long: `Run the Plan-Run-Sync workflow for a SPEC without an interactive session.
+
+Each phase launches Claude Code in non-interactive mode inside the SPEC
+worktree (.moai/worktrees/<SPEC-ID>). Output is streamed to
+.moai/logs/workflow/<SPEC-ID>/ in the worktree, and each phase is bounded by
+the token_budget and phase_timeout settings in workflow.yaml.`,
+}
+
+func init() {
+	rootCmd.AddCommand(workflowCmd)
+
+	runCmd := &cobra.Command{
+		Use:   "run <SPEC-ID>",
+		Short: "Run plan, run and sync for a SPEC",
+		Long: `Run the plan, run and sync phases for a SPEC in its worktree,
+starting a new run record.
+
+Example:
+  moai workflow run SPEC-ISSUE-123`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeWorkflow(cmd, args[0], false)
+		},
+	}
+
+	resumeCmd := &cobra.Command{
+		Use:   "resume <SPEC-ID>",
+		Short: "Continue a workflow from its first unfinished phase",
+		Long: `Continue a previous 'moai workflow run', skipping phases that
+already completed.
+
+Example:
+  moai workflow resume SPEC-ISSUE-123`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeWorkflow(cmd, args[0], true)
+		},
+	}
+
+	for _, c := range []*cobra.Command{runCmd, resumeCmd} {
+		c.Flags().String("permission-mode", "acceptEdits", "Claude Code permission mode for each phase")
+		c.Flags().String("claude", workflow.DefaultClaudeBinary, "Claude Code executable")
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status <SPEC-ID>",
+		Short: "Show phase status, usage and logs of a workflow",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runWorkflowStatus,
+	}
+
+	workflowCmd.AddCommand(runCmd, statusCmd, resumeCmd)
+}
+
+// workflowWorktrees returns the worktree manager for the current repository.
+func workflowWorktrees() (git.WorktreeManager, error) {
+	if deps == nil {
+		return nil, fmt.Errorf("dependencies not initialized")
+	}
+	if deps.GitWorktree == nil {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("get working directory: %w", err)
+		}
+		if err := deps.EnsureGit(cwd); err != nil {
+			return nil, fmt.Errorf("initialize git: %w", err)
+		}
+	}
+	return deps.GitWorktree, nil
+}
+
+// workflowPhaseLimits maps workflow.yaml budgets and timeouts to executor limits.
+func workflowPhaseLimits(cfg config.WorkflowConfig) map[workflow.Phase]workflow.PhaseLimits {
+	return map[workflow.Phase]workflow.PhaseLimits{
+		workflow.PhasePlan: {Timeout: cfg.PlanTimeout, TokenBudget: int64(cfg.PlanTokens)},
+		workflow.PhaseRun:  {Timeout: cfg.RunTimeout, TokenBudget: int64(cfg.RunTokens)},
+		workflow.PhaseSync: {Timeout: cfg.SyncTimeout, TokenBudget: int64(cfg.SyncTokens)},
+	}
+}
+
+// loadWorkflowProjectConfig loads the project configuration, falling back
+// to defaults when it cannot be read.
+func loadWorkflowProjectConfig(projectRoot string) *config.Config {
+	mgr := config.NewConfigManager()
+	cfg, err := mgr.Load(projectRoot)
+	if err != nil {
+		return config.NewDefaultConfig()
+	}
+	return cfg
+}
+
+// newWorktreeQualityGate creates the TRUST 5 validator run in SPEC
+// worktrees, configured from the project's quality section.
+func newWorktreeQualityGate(projectRoot string) (quality.WorktreeValidator, error) {
+	cfg := loadWorkflowProjectConfig(projectRoot).Quality
+	return quality.NewWorktreeValidator(
+		quality.DefaultGateFactory(noLSPDiagnostics{}),
+		quality.QualityConfig{
+			DevelopmentMode:    quality.DevelopmentMode(cfg.DevelopmentMode),
+			EnforceQuality:     cfg.EnforceQuality,
+			TestCoverageTarget: 0, // coverage is measured by the run phase itself
+		},
+		deps.Logger,
+	)
+}
+
+// noLSPDiagnostics stands in for the quality gate's LSP client, which is
+// not yet integrated; the gate then reports no diagnostics.
+type noLSPDiagnostics struct{}
+
+func (noLSPDiagnostics) CollectDiagnostics(context.Context) ([]quality.Diagnostic, error) {
+	return nil, nil
+}
+
+func executeWorkflow(cmd *cobra.Command, specID string, resume bool) error {
+	out := cmd.OutOrStdout()
+	if err := workflow.ValidateSpecID(specID); err != nil {
+		return err
+	}
+	mgr, err := workflowWorktrees()
+	if err != nil {
+		return err
+	}
+	wt, err := workflow.FindSpecWorktree(mgr, specID)
+	if err != nil {
+		return err
+	}
+
+	if resume {
+		state, err := workflow.LoadRunState(wt.Path, specID)
+		if errors.Is(err, workflow.ErrNoRunState) {
+			return fmt.Errorf("%w; start it with 'moai workflow run %s'", err, specID)
+		}
+		if err != nil {
+			return err
+		}
+		if state.Completed() {
+			_, _ = fmt.Fprintln(out, renderSuccessCard(specID+" workflow already completed"))
+			return nil
+		}
+	}
+
+	cfg := loadWorkflowProjectConfig(mgr.Root())
+	binary, _ := cmd.Flags().GetString("claude")
+	permissionMode, _ := cmd.Flags().GetString("permission-mode")
+	executor := workflow.NewClaudeExecutor(workflow.ClaudeExecutorConfig{
+		Binary:         binary,
+		Limits:         workflowPhaseLimits(cfg.Workflow),
+		PermissionMode: permissionMode,
+		Resume:         resume,
+		OnPhase: func(_ string, phase workflow.Phase, rec workflow.PhaseRecord) {
+			printWorkflowPhase(out, phase, rec)
+		},
+	}, deps.Logger)
+
+	validator, err := newWorktreeQualityGate(mgr.Root())
+	if err != nil {
+		return err
+	}
+	orch, err := workflow.NewWorktreeOrchestrator(mgr, validator, executor, deps.Logger)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// An interrupt leaves the current phase pending so it can be resumed.
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	_, _ = fmt.Fprintf(out, "Running %s in %s\n", specID, wt.Path)
+	_, runErr := orch.ExecuteWorkflow(ctx, specID)
+
+	if state, err := workflow.LoadRunState(wt.Path, specID); err == nil {
+		_, _ = fmt.Fprintln(out, renderCard(specID+" workflow", renderWorkflowState(state)))
+	}
+	if runErr != nil {
+		_, _ = fmt.Fprintln(out, cliMuted.Render(fmt.Sprintf("Continue with: moai workflow resume %s", specID)))
+		return fmt.Errorf("workflow %s: %w", specID, runErr)
+	}
+	return nil
+}
+
+func runWorkflowStatus(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+	specID := args[0]
+	if err := workflow.ValidateSpecID(specID); err != nil {
+		return err
+	}
+	mgr, err := workflowWorktrees()
+	if err != nil {
+		return err
+	}
+	wt, err := workflow.FindSpecWorktree(mgr, specID)
+	if err != nil {
+		return err
+	}
+	state, err := workflow.LoadRunState(wt.Path, specID)
+	if errors.Is(err, workflow.ErrNoRunState) {
+		_, _ = fmt.Fprintln(out, renderInfoCard(specID+" has not been run",
+			cliMuted.Render("Start it with: moai workflow run "+specID)))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	pairs := []kvPair{
+		{"Worktree", wt.Path},
+		{"Started", state.StartedAt.Local().Format(time.DateTime)},
+		{"Updated", state.UpdatedAt.Local().Format(time.DateTime)},
+	}
+	body := renderKeyValueLines(pairs) + "\n\n" + renderWorkflowState(state)
+	_, _ = fmt.Fprintln(out, renderCard(specID+" workflow", body))
+	return nil
+}
+
+// renderWorkflowState renders one line per phase with usage, duration and
+// the log location, followed by the error of any failed phase.
+func renderWorkflowState(state *workflow.RunState) string {
+	var lines, errs []string
+	for _, phase := range workflow.Phases {
+		rec := state.Phase(phase)
+		line := fmt.Sprintf("%s %-5s %-9s", workflowStatusIcon(rec.Status), phase, rec.Status)
+		if !rec.StartedAt.IsZero() {
+			line += fmt.Sprintf("  %d tokens  $%.2f", rec.Tokens, rec.CostUSD)
+			if !rec.CompletedAt.IsZero() {
+				line += "  " + rec.CompletedAt.Sub(rec.StartedAt).Round(time.Second).String()
+			}
+		}
+		lines = append(lines, line)
+		if rec.LogPath != "" {
+			lines = append(lines, "        "+cliMuted.Render(rec.LogPath))
+		}
+		if rec.Error != "" {
+			errs = append(errs, fmt.Sprintf("%s: %s", phase, rec.Error))
+		}
+	}
+	if len(errs) > 0 {
+		lines = append(lines, "", cliError.Render(strings.Join(errs, "\n")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// printWorkflowPhase reports phase transitions while a workflow runs.
+func printWorkflowPhase(out io.Writer, phase workflow.Phase, rec workflow.PhaseRecord) {
+	switch rec.Status {
+	case workflow.PhaseStatusRunning:
+		_, _ = fmt.Fprintf(out, "  %s %s ...\n", workflowStatusIcon(rec.Status), phase)
+	case workflow.PhaseStatusSkipped:
+		_, _ = fmt.Fprintf(out, "  %s %s already completed\n", workflowStatusIcon(rec.Status), phase)
+	default:
+		_, _ = fmt.Fprintf(out, "  %s %s %s (%d tokens)\n", workflowStatusIcon(rec.Status), phase, rec.Status, rec.Tokens)
+	}
+}
+
+func workflowStatusIcon(status workflow.WorkflowPhaseStatus) string {
+	switch status {
+	case workflow.PhaseStatusCompleted:
+		return symSuccess()
+	case workflow.PhaseStatusFailed:
+		return symError()
+	case workflow.PhaseStatusRunning:
+		return cliWarn.Render("▶")
+	default:
+		return cliMuted.Render("·")
+	}
+}
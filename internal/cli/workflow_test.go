@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/modu-ai/moai-adk/internal/core/git"
+	"github.com/modu-ai/moai-adk/internal/workflow"
+)
+
+// workflowWorktreeStub lists a fixed set of worktrees.
+type workflowWorktreeStub struct {
+	root      string
+	worktrees []git.Worktree
+}
+
+func (s *workflowWorktreeStub) Add(_, _ string) error                    { return nil }
+func (s *workflowWorktreeStub) List() ([]git.Worktree, error)            { return s.worktrees, nil }
+func (s *workflowWorktreeStub) Remove(_ string, _ bool) error            { return nil }
+func (s *workflowWorktreeStub) Prune() error                             { return nil }
+func (s *workflowWorktreeStub) Repair() error                            { return nil }
+func (s *workflowWorktreeStub) Root() string                             { return s.root }
+func (s *workflowWorktreeStub) Sync(_, _, _ string) error                { return nil }
+func (s *workflowWorktreeStub) DeleteBranch(_ string) error              { return nil }
+func (s *workflowWorktreeStub) IsBranchMerged(_, _ string) (bool, error) { return false, nil }
+
+func newWorkflowTestCmd() (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("claude", workflow.DefaultClaudeBinary, "")
+	cmd.Flags().String("permission-mode", "", "")
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	return cmd, buf
+}
+
+func TestWorkflowRunStatusResume(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("stub claude script requires a POSIX shell")
+	}
+	const specID = "SPEC-ISSUE-7"
+	root := t.TempDir()
+	wtPath := filepath.Join(root, ".moai", "worktrees", specID)
+	if err := os.MkdirAll(wtPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// The stub fails the run phase until the "fixed" marker exists.
+	binDir := t.TempDir()
+	marker := filepath.Join(binDir, "fixed")
+	script := `#!/bin/sh
+case "$*" in
+*"/moai run"*) [ -f "` + marker + `" ] || { echo "tests failing" >&2; exit 1; } ;;
+esac
+echo '{"type":"result","is_error":false,"total_cost_usd":0.25,"usage":{"input_tokens":1000,"output_tokens":200}}'
+`
+	if err := os.WriteFile(filepath.Join(binDir, "claude"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	origDeps := deps
+	defer func() { deps = origDeps }()
+	deps = &Dependencies{GitWorktree: &workflowWorktreeStub{
+		root:      root,
+		worktrees: []git.Worktree{{Path: wtPath, Branch: "fix/issue-7"}},
+	}}
+
+	cmd, buf := newWorkflowTestCmd()
+	if err := runWorkflowStatus(cmd, []string{specID}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "has not been run") {
+		t.Errorf("status before run:\n%s", buf.String())
+	}
+
+	cmd, buf = newWorkflowTestCmd()
+	if err := executeWorkflow(cmd, specID, true); err == nil || !strings.Contains(err.Error(), "moai workflow run") {
+		t.Errorf("resume without a run: error = %v", err)
+	}
+
+	cmd, buf = newWorkflowTestCmd()
+	err := executeWorkflow(cmd, specID, false)
+	if err == nil {
+		t.Fatal("run should fail while the run phase fails")
+	}
+	if !strings.Contains(buf.String(), "tests failing") || !strings.Contains(buf.String(), "moai workflow resume "+specID) {
+		t.Errorf("run output:\n%s", buf.String())
+	}
+	state, err := workflow.LoadRunState(wtPath, specID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Phase(workflow.PhasePlan).Status != workflow.PhaseStatusCompleted ||
+		state.Phase(workflow.PhaseRun).Status != workflow.PhaseStatusFailed ||
+		state.Phase(workflow.PhaseSync).Status != workflow.PhaseStatusPending {
+		t.Errorf("state after failed run = %+v", state.Phases)
+	}
+
+	if err := os.WriteFile(marker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd, buf = newWorkflowTestCmd()
+	if err := executeWorkflow(cmd, specID, true); err != nil {
+		t.Fatalf("resume: %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "plan already completed") {
+		t.Errorf("resume output:\n%s", buf.String())
+	}
+
+	cmd, buf = newWorkflowTestCmd()
+	if err := runWorkflowStatus(cmd, []string{specID}); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); strings.Count(out, "completed") != 3 || !strings.Contains(out, "1200 tokens") {
+		t.Errorf("status after resume:\n%s", out)
+	}
+}
+
+func TestWorkflowPhaseLimits(t *testing.T) {
+	cfg := loadWorkflowProjectConfig(t.TempDir()).Workflow
+	limits := workflowPhaseLimits(cfg)
+	if limits[workflow.PhaseRun].TokenBudget != int64(cfg.RunTokens) || limits[workflow.PhaseRun].Timeout != cfg.RunTimeout {
+		t.Errorf("run limits = %+v, config = %+v", limits[workflow.PhaseRun], cfg)
+	}
+	if limits[workflow.PhasePlan].Timeout == 0 {
+		t.Error("plan phase should have a default timeout")
+	}
+}
@@ -1,6 +1,7 @@
 package worktree
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -10,18 +11,28 @@ func newDoneCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "done [branch-name]",
 		Short: "Complete worktree and cleanup",
-		Long: `Complete a worktree by removing it and optionally deleting the branch.
+		Long: `Complete a worktree by optionally landing its branch and then cleaning up.
 
 This command performs the completion workflow:
-1. Remove the worktree at the specified branch
-2. Optionally delete the feature branch (with --delete-branch)
-
-Note: Merging to base branch should be done separately via git merge or PR.`,
+1. With --merge, run the TRUST 5 quality gate in the worktree, check for
+   conflicts with the base branch and land the branch:
+     manual/personal mode - fast-forward (or --squash) the base branch locally
+     team mode (or --pr)  - push the branch and open a pull request
+2. Remove the worktree
+3. Close the tmux pane linked to it by 'moai worktree go'
+4. Delete the branch (with --delete-branch, implied by --merge)
+
+Nothing is cleaned up when the gate, the conflict check or the merge fails.`,
 		Args: cobra.ExactArgs(1),
 		RunE: runDone,
 	}
 	cmd.Flags().Bool("force", false, "Force removal even with uncommitted changes")
 	cmd.Flags().Bool("delete-branch", false, "Delete the branch after removing worktree")
+	cmd.Flags().Bool("merge", false, "Land the branch on the base branch before cleanup")
+	cmd.Flags().String("base", "main", "Base branch to merge into")
+	cmd.Flags().Bool("squash", false, "Squash-merge instead of fast-forwarding (local merges)")
+	cmd.Flags().Bool("pr", false, "Push and open a pull request regardless of git_strategy.mode")
+	cmd.Flags().Bool("skip-gate", false, "Skip the TRUST 5 quality gate")
 	return cmd
 }
 
@@ -39,6 +50,13 @@ func runDone(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("get delete-branch flag: %w", err)
 	}
 
+	merge, _ := cmd.Flags().GetBool("merge")
+	opts := finishOptions{}
+	opts.base, _ = cmd.Flags().GetString("base")
+	opts.squash, _ = cmd.Flags().GetBool("squash")
+	opts.pr, _ = cmd.Flags().GetBool("pr")
+	opts.skipGate, _ = cmd.Flags().GetBool("skip-gate")
+
 	if WorktreeProvider == nil {
 		return fmt.Errorf("worktree manager not initialized (git module not available)")
 	}
@@ -61,18 +79,50 @@ func runDone(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no worktree found for branch %q", branchName)
 	}
 
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	details := []string{fmt.Sprintf("Path: %s", targetPath)}
+
+	// Land the branch first so that a failed check leaves everything in place.
+	forceDelete := false
+	if merge {
+		landed, checks, err := landBranch(ctx, WorktreeProvider.Root(), targetPath, branchName, opts)
+		if err != nil {
+			for _, d := range checks {
+				_, _ = fmt.Fprintln(out, d)
+			}
+			return err
+		}
+		details = append(details, checks...)
+		details = append(details, "Merge:  "+landed.summary)
+		deleteBranch = true
+		forceDelete = !landed.merged
+	}
+
 	// Remove the worktree.
 	if err := WorktreeProvider.Remove(targetPath, force); err != nil {
 		return fmt.Errorf("remove worktree: %w", err)
 	}
+	details = append(details, "Worktree removed.")
 
-	details := []string{
-		fmt.Sprintf("Path: %s", targetPath),
-		"Worktree removed.",
+	if n, err := paneLinker().CloseLinkedPanes(ctx, targetPath); err != nil {
+		details = append(details, fmt.Sprintf("Warning: could not close tmux panes: %v", err))
+	} else if n > 0 {
+		details = append(details, fmt.Sprintf("Closed %d tmux pane(s).", n))
 	}
 
 	if deleteBranch {
-		if err := WorktreeProvider.DeleteBranch(branchName); err != nil {
+		var err error
+		if forceDelete {
+			// Squash merges and pull requests leave the branch unmerged locally.
+			_, err = gitOutput(ctx, WorktreeProvider.Root(), "branch", "-D", branchName)
+		} else {
+			err = WorktreeProvider.DeleteBranch(branchName)
+		}
+		if err != nil {
 			details = append(details,
 				fmt.Sprintf("Warning: could not delete branch: %v", err),
 				fmt.Sprintf("To delete manually: git branch -d %s", branchName),
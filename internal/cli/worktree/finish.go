@@ -0,0 +1,294 @@
+package worktree
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/modu-ai/moai-adk/internal/core/git"
+	"github.com/modu-ai/moai-adk/internal/core/quality"
+	"github.com/modu-ai/moai-adk/internal/forge"
+	"github.com/modu-ai/moai-adk/internal/tmux"
+)
+
+// Collaborators of `moai worktree done --merge`. Set these from the parent
+// CLI package during DI wiring.
+var (
+	// QualityGateFactory creates the TRUST 5 validator run in the worktree
+	// before the branch lands. Nil skips the gate.
+	QualityGateFactory func(projectRoot string) (quality.WorktreeValidator, error)
+
+	// ForgeFactory creates the code hosting client that pushes the branch
+	// and opens the pull request in team mode.
+	ForgeFactory func(projectRoot string) (forge.Forge, error)
+
+	// StrategyModeFunc returns git_strategy.mode ("manual", "personal" or
+	// "team") of the project. Nil is treated as "manual".
+	StrategyModeFunc func(projectRoot string) string
+
+	// BranchManagerFactory creates the branch manager used to detect
+	// conflicts from inside a worktree.
+	BranchManagerFactory = func(dir string) git.BranchManager { return git.NewBranchManager(dir) }
+
+	// PaneLinker links tmux panes to worktrees and closes the panes linked
+	// to a removed worktree. Nil uses the system tmux.
+	PaneLinker WorktreePanes
+)
+
+// WorktreePanes links tmux panes to worktrees.
+type WorktreePanes interface {
+	LinkPane(ctx context.Context, pane, dir string) error
+	CloseLinkedPanes(ctx context.Context, dir string) (int, error)
+}
+
+// paneLinker returns PaneLinker, or the system tmux when it is nil.
+func paneLinker() WorktreePanes {
+	if PaneLinker != nil {
+		return PaneLinker
+	}
+	return tmux.NewSessionManager()
+}
+
+// landing describes how a branch reached its base.
+type landing struct {
+	// summary is shown in the done card, e.g. "fast-forwarded into main".
+	summary string
+	// merged reports whether git sees the branch as merged; squash merges
+	// and pull requests leave it unmerged locally.
+	merged bool
+}
+
+// finishOptions selects how `done --merge` lands a branch.
+type finishOptions struct {
+	base     string
+	squash   bool
+	pr       bool
+	skipGate bool
+}
+
+// landBranch runs the TRUST gate in the worktree, checks for conflicts with
+// base and then merges the branch locally or opens a pull request according
+// to git_strategy.mode. Nothing is changed when a check fails.
+func landBranch(ctx context.Context, root, wtPath, branch string, opts finishOptions) (landing, []string, error) {
+	var details []string
+
+	if status, err := gitOutput(ctx, wtPath, "status", "--porcelain"); err != nil {
+		return landing{}, nil, fmt.Errorf("check worktree status: %w", err)
+	} else if status != "" {
+		return landing{}, nil, fmt.Errorf("worktree %s has uncommitted changes; commit or stash them before merging", wtPath)
+	}
+
+	report, err := runTrustGate(ctx, root, wtPath, opts.skipGate)
+	if err != nil {
+		return landing{}, nil, err
+	}
+	details = append(details, "Gate:   "+gateSummary(report))
+
+	conflicts, err := BranchManagerFactory(wtPath).HasConflicts(opts.base)
+	if err != nil {
+		return landing{}, details, fmt.Errorf("check conflicts with %s: %w", opts.base, err)
+	}
+	if conflicts {
+		return landing{}, details, fmt.Errorf("branch %s may conflict with %s; run 'moai worktree sync %s --base %s' and resolve them first",
+			branch, opts.base, branch, opts.base)
+	}
+
+	mode := "manual"
+	if StrategyModeFunc != nil {
+		mode = StrategyModeFunc(root)
+	}
+	if opts.pr || mode == "team" {
+		number, err := openPullRequest(ctx, root, wtPath, branch, opts.base, report)
+		if err != nil {
+			return landing{}, details, err
+		}
+		return landing{summary: fmt.Sprintf("opened pull request #%d into %s", number, opts.base)}, details, nil
+	}
+	l, err := mergeLocal(ctx, root, branch, opts.base, opts.squash)
+	return l, details, err
+}
+
+// runTrustGate validates the worktree and fails when the gate does not
+// pass. It returns a nil report when the gate is skipped or not wired.
+func runTrustGate(ctx context.Context, root, wtPath string, skip bool) (*quality.Report, error) {
+	if skip || QualityGateFactory == nil {
+		return nil, nil
+	}
+	validator, err := QualityGateFactory(root)
+	if err != nil {
+		return nil, fmt.Errorf("create quality gate: %w", err)
+	}
+	report, err := validator.Validate(ctx, wtPath)
+	if err != nil {
+		return nil, fmt.Errorf("run TRUST gate: %w", err)
+	}
+	if !report.Passed {
+		return report, fmt.Errorf("TRUST gate failed in %s (%s); fix the issues or pass --skip-gate", wtPath, gateSummary(report))
+	}
+	return report, nil
+}
+
+// gateSummary describes a gate report in a few words.
+func gateSummary(report *quality.Report) string {
+	if report == nil {
+		return "skipped"
+	}
+	verdict := "passed"
+	if !report.Passed {
+		verdict = "failed"
+	}
+	return fmt.Sprintf("%s, score %.2f, %d issue(s)", verdict, report.Score, len(report.AllIssues()))
+}
+
+// mergeLocal fast-forwards or squash-merges branch into base in the main
+// worktree at root. A squash merge needs base checked out there; a
+// fast-forward also works when base is not checked out anywhere.
+func mergeLocal(ctx context.Context, root, branch, base string, squash bool) (landing, error) {
+	current, err := gitOutput(ctx, root, "branch", "--show-current")
+	if err != nil {
+		return landing{}, fmt.Errorf("read current branch: %w", err)
+	}
+
+	if squash {
+		if current != base {
+			return landing{}, fmt.Errorf("squash merge needs %s checked out in %s (currently %q)", base, root, current)
+		}
+		subjects, err := branchCommits(ctx, root, base, branch)
+		if err != nil {
+			return landing{}, err
+		}
+		if len(subjects) == 0 {
+			return landing{}, fmt.Errorf("branch %s has no commits ahead of %s", branch, base)
+		}
+		if _, err := gitOutput(ctx, root, "merge", "--squash", branch); err != nil {
+			return landing{}, fmt.Errorf("squash merge %s: %w", branch, err)
+		}
+		if _, err := gitOutput(ctx, root, "commit", "-m", squashMessage(branch, subjects)); err != nil {
+			return landing{}, fmt.Errorf("commit squash merge of %s: %w", branch, err)
+		}
+		return landing{summary: fmt.Sprintf("squash-merged %d commit(s) into %s", len(subjects), base)}, nil
+	}
+
+	if _, err := gitOutput(ctx, root, "merge-base", "--is-ancestor", base, branch); err != nil {
+		return landing{}, fmt.Errorf("cannot fast-forward %s to %s; run 'moai worktree sync %s --base %s --strategy rebase' or pass --squash",
+			base, branch, branch, base)
+	}
+	if current == base {
+		_, err = gitOutput(ctx, root, "merge", "--ff-only", branch)
+	} else {
+		// Updates the ref directly; git refuses when base is checked out in
+		// another worktree.
+		_, err = gitOutput(ctx, root, "fetch", ".", branch+":"+base)
+	}
+	if err != nil {
+		return landing{}, fmt.Errorf("fast-forward %s: %w", base, err)
+	}
+	return landing{summary: "fast-forwarded into " + base, merged: true}, nil
+}
+
+// openPullRequest pushes branch from its worktree and opens a pull request
+// against base with a generated title and body.
+func openPullRequest(ctx context.Context, root, wtPath, branch, base string, report *quality.Report) (int, error) {
+	if ForgeFactory == nil {
+		return 0, fmt.Errorf("code hosting provider not initialized")
+	}
+	f, err := ForgeFactory(root)
+	if err != nil {
+		return 0, fmt.Errorf("create forge client: %w", err)
+	}
+	subjects, err := branchCommits(ctx, wtPath, base, branch)
+	if err != nil {
+		return 0, err
+	}
+	if len(subjects) == 0 {
+		return 0, fmt.Errorf("branch %s has no commits ahead of %s", branch, base)
+	}
+	stat, _ := gitOutput(ctx, wtPath, "diff", "--shortstat", base+"..."+branch)
+
+	if err := f.Push(ctx, wtPath); err != nil {
+		return 0, fmt.Errorf("push %s: %w", branch, err)
+	}
+	number, err := f.PRCreate(ctx, forge.ChangeRequestOptions{
+		Title:      prTitle(branch, subjects),
+		Body:       prBody(branch, base, subjects, stat, report),
+		BaseBranch: base,
+		HeadBranch: branch,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("open pull request: %w", err)
+	}
+	return number, nil
+}
+
+// branchCommits returns the subjects of the commits on branch that are not
+// on base, oldest first.
+func branchCommits(ctx context.Context, dir, base, branch string) ([]string, error) {
+	out, err := gitOutput(ctx, dir, "log", "--reverse", "--format=%s", base+".."+branch)
+	if err != nil {
+		return nil, fmt.Errorf("list commits of %s: %w", branch, err)
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// prTitle uses the only commit subject, or names the SPEC or branch when
+// there are several commits.
+func prTitle(branch string, subjects []string) string {
+	if len(subjects) == 1 {
+		return subjects[0]
+	}
+	name := branch
+	if i := strings.Index(branch, "SPEC-"); i >= 0 {
+		name = branch[i:]
+	}
+	return fmt.Sprintf("%s: %s", name, subjects[0])
+}
+
+// prBody lists the commits, the diff size and the TRUST gate result.
+func prBody(branch, base string, subjects []string, stat string, report *quality.Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Summary\n\nMerges `%s` into `%s`.\n\n## Commits\n\n", branch, base)
+	for _, s := range subjects {
+		fmt.Fprintf(&b, "- %s\n", s)
+	}
+	if stat != "" {
+		fmt.Fprintf(&b, "\n## Changes\n\n%s\n", stat)
+	}
+	fmt.Fprintf(&b, "\n## Quality\n\nTRUST 5 gate: %s\n", gateSummary(report))
+	return b.String()
+}
+
+// squashMessage is the commit message of a squash merge.
+func squashMessage(branch string, subjects []string) string {
+	var b strings.Builder
+	b.WriteString(prTitle(branch, subjects))
+	if len(subjects) > 1 {
+		b.WriteString("\n\n")
+		for _, s := range subjects {
+			fmt.Fprintf(&b, "- %s\n", s)
+		}
+	}
+	return b.String()
+}
+
+// gitOutput runs git in dir and returns its trimmed stdout.
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", errors.New(msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
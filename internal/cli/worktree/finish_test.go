@@ -0,0 +1,324 @@
+package worktree
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modu-ai/moai-adk/internal/core/git"
+	"github.com/modu-ai/moai-adk/internal/core/quality"
+	"github.com/modu-ai/moai-adk/internal/forge"
+)
+
+// fakePanes records the panes linked and the worktrees whose panes were closed.
+type fakePanes struct {
+	linked map[string]string
+	dirs   []string
+}
+
+func (f *fakePanes) LinkPane(_ context.Context, pane, dir string) error {
+	if f.linked == nil {
+		f.linked = make(map[string]string)
+	}
+	f.linked[pane] = dir
+	return nil
+}
+
+func (f *fakePanes) CloseLinkedPanes(_ context.Context, dir string) (int, error) {
+	f.dirs = append(f.dirs, dir)
+	return 1, nil
+}
+
+// fakeValidator returns a fixed TRUST report.
+type fakeValidator struct{ report *quality.Report }
+
+func (f fakeValidator) Validate(context.Context, string) (*quality.Report, error) {
+	return f.report, nil
+}
+
+func (f fakeValidator) ValidateWithConfig(context.Context, string, quality.QualityConfig) (*quality.Report, error) {
+	return f.report, nil
+}
+
+// fakeForge records the push and pull request of team mode.
+type fakeForge struct {
+	forge.Forge
+	pushed string
+	pr     forge.ChangeRequestOptions
+}
+
+func (f *fakeForge) Push(_ context.Context, dir string) error {
+	f.pushed = dir
+	return nil
+}
+
+func (f *fakeForge) PRCreate(_ context.Context, opts forge.ChangeRequestOptions) (int, error) {
+	f.pr = opts
+	return 42, nil
+}
+
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func commitFile(t *testing.T, dir, name, content, msg string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", name)
+	gitRun(t, dir, "commit", "-q", "-m", msg)
+}
+
+// setupFinishRepo creates a repository on main with a worktree for
+// feature/SPEC-AUTH-001 holding two commits, and wires the package
+// collaborators to it.
+func setupFinishRepo(t *testing.T) (root, wtPath string, panes *fakePanes) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root, _ = filepath.EvalSymlinks(t.TempDir())
+	gitRun(t, root, "init", "-q", "-b", "main")
+	gitRun(t, root, "config", "user.name", "t")
+	gitRun(t, root, "config", "user.email", "t@t")
+	commitFile(t, root, "README.md", "readme\n", "chore: initial")
+
+	wtPath = filepath.Join(root, ".moai", "worktrees", "SPEC-AUTH-001")
+	gitRun(t, root, "worktree", "add", "-q", "-b", "feature/SPEC-AUTH-001", wtPath)
+	commitFile(t, wtPath, "auth.go", "package auth\n", "feat(auth): add login")
+	commitFile(t, wtPath, "auth_test.go", "package auth\n", "test(auth): cover login")
+
+	panes = &fakePanes{}
+	origProvider, origPanes := WorktreeProvider, PaneLinker
+	origGate, origForge, origMode := QualityGateFactory, ForgeFactory, StrategyModeFunc
+	t.Cleanup(func() {
+		WorktreeProvider, PaneLinker = origProvider, origPanes
+		QualityGateFactory, ForgeFactory, StrategyModeFunc = origGate, origForge, origMode
+	})
+	WorktreeProvider = git.NewWorktreeManager(root)
+	PaneLinker = panes
+	QualityGateFactory = func(string) (quality.WorktreeValidator, error) {
+		return fakeValidator{report: &quality.Report{Passed: true, Score: 0.9}}, nil
+	}
+	ForgeFactory, StrategyModeFunc = nil, nil
+	return root, wtPath, panes
+}
+
+func runDoneCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := newDoneCmd()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func branchExists(t *testing.T, root, branch string) bool {
+	t.Helper()
+	return exec.Command("git", "-C", root, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch).Run() == nil
+}
+
+func TestRunDone_MergeFastForward(t *testing.T) {
+	root, wtPath, panes := setupFinishRepo(t)
+	head := gitRun(t, wtPath, "rev-parse", "HEAD")
+
+	out, err := runDoneCmd(t, "SPEC-AUTH-001", "--merge")
+	if err != nil {
+		t.Fatalf("done --merge: %v\n%s", err, out)
+	}
+	if got := gitRun(t, root, "rev-parse", "main"); got != head {
+		t.Errorf("main = %s, want fast-forward to %s", got, head)
+	}
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Errorf("worktree should be removed, stat err = %v", err)
+	}
+	if branchExists(t, root, "feature/SPEC-AUTH-001") {
+		t.Error("branch should be deleted")
+	}
+	if len(panes.dirs) != 1 || panes.dirs[0] != wtPath {
+		t.Errorf("closed panes in %v, want [%s]", panes.dirs, wtPath)
+	}
+	for _, want := range []string{"passed, score 0.90", "fast-forwarded into main", "Closed 1 tmux pane"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunDone_MergeSquash(t *testing.T) {
+	root, wtPath, _ := setupFinishRepo(t)
+
+	out, err := runDoneCmd(t, "SPEC-AUTH-001", "--merge", "--squash")
+	if err != nil {
+		t.Fatalf("done --merge --squash: %v\n%s", err, out)
+	}
+	if n := gitRun(t, root, "rev-list", "--count", "main"); n != "2" {
+		t.Errorf("main has %s commits, want 2 (initial + squash)", n)
+	}
+	msg := gitRun(t, root, "log", "-1", "--format=%B", "main")
+	for _, want := range []string{"SPEC-AUTH-001: feat(auth): add login", "- test(auth): cover login"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("squash message missing %q:\n%s", want, msg)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "auth_test.go")); err != nil {
+		t.Errorf("squashed file missing: %v", err)
+	}
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Error("worktree should be removed")
+	}
+	if branchExists(t, root, "feature/SPEC-AUTH-001") {
+		t.Error("squash-merged branch should be force-deleted")
+	}
+}
+
+func TestRunDone_MergeTeamModeOpensPR(t *testing.T) {
+	root, wtPath, _ := setupFinishRepo(t)
+	fake := &fakeForge{}
+	ForgeFactory = func(string) (forge.Forge, error) { return fake, nil }
+	StrategyModeFunc = func(string) string { return "team" }
+	mainHead := gitRun(t, root, "rev-parse", "main")
+
+	out, err := runDoneCmd(t, "SPEC-AUTH-001", "--merge")
+	if err != nil {
+		t.Fatalf("done --merge: %v\n%s", err, out)
+	}
+	if fake.pushed != wtPath {
+		t.Errorf("pushed from %q, want %q", fake.pushed, wtPath)
+	}
+	if fake.pr.BaseBranch != "main" || fake.pr.HeadBranch != "feature/SPEC-AUTH-001" {
+		t.Errorf("PR %s <- %s, want main <- feature/SPEC-AUTH-001", fake.pr.BaseBranch, fake.pr.HeadBranch)
+	}
+	if fake.pr.Title != "SPEC-AUTH-001: feat(auth): add login" {
+		t.Errorf("PR title = %q", fake.pr.Title)
+	}
+	for _, want := range []string{"- feat(auth): add login", "- test(auth): cover login", "2 files changed", "TRUST 5 gate: passed"} {
+		if !strings.Contains(fake.pr.Body, want) {
+			t.Errorf("PR body missing %q:\n%s", want, fake.pr.Body)
+		}
+	}
+	if got := gitRun(t, root, "rev-parse", "main"); got != mainHead {
+		t.Error("team mode must not merge locally")
+	}
+	if !strings.Contains(out, "opened pull request #42") {
+		t.Errorf("output missing PR number:\n%s", out)
+	}
+	if branchExists(t, root, "feature/SPEC-AUTH-001") {
+		t.Error("local branch should be deleted after opening the PR")
+	}
+}
+
+func TestRunDone_MergeGateFailureKeepsWorktree(t *testing.T) {
+	root, wtPath, panes := setupFinishRepo(t)
+	QualityGateFactory = func(string) (quality.WorktreeValidator, error) {
+		return fakeValidator{report: &quality.Report{Passed: false, Score: 0.4}}, nil
+	}
+	mainHead := gitRun(t, root, "rev-parse", "main")
+
+	_, err := runDoneCmd(t, "SPEC-AUTH-001", "--merge")
+	if err == nil || !strings.Contains(err.Error(), "TRUST gate failed") {
+		t.Fatalf("err = %v, want TRUST gate failure", err)
+	}
+	if _, err := os.Stat(wtPath); err != nil {
+		t.Errorf("worktree should be kept: %v", err)
+	}
+	if got := gitRun(t, root, "rev-parse", "main"); got != mainHead {
+		t.Error("main should not move")
+	}
+	if len(panes.dirs) != 0 {
+		t.Error("panes should not be closed")
+	}
+
+	// --skip-gate lands the branch anyway.
+	if out, err := runDoneCmd(t, "SPEC-AUTH-001", "--merge", "--skip-gate"); err != nil {
+		t.Fatalf("done --merge --skip-gate: %v\n%s", err, out)
+	}
+}
+
+func TestRunDone_MergeConflictKeepsWorktree(t *testing.T) {
+	root, wtPath, _ := setupFinishRepo(t)
+	commitFile(t, root, "auth.go", "package login\n", "feat: competing auth")
+
+	_, err := runDoneCmd(t, "SPEC-AUTH-001", "--merge")
+	if err == nil || !strings.Contains(err.Error(), "may conflict with main") {
+		t.Fatalf("err = %v, want conflict", err)
+	}
+	if _, err := os.Stat(wtPath); err != nil {
+		t.Errorf("worktree should be kept: %v", err)
+	}
+	if !branchExists(t, root, "feature/SPEC-AUTH-001") {
+		t.Error("branch should be kept")
+	}
+}
+
+func TestRunDone_MergeDivergedNeedsSquash(t *testing.T) {
+	root, wtPath, _ := setupFinishRepo(t)
+	commitFile(t, root, "docs.md", "docs\n", "docs: unrelated")
+
+	_, err := runDoneCmd(t, "SPEC-AUTH-001", "--merge")
+	if err == nil || !strings.Contains(err.Error(), "cannot fast-forward") {
+		t.Fatalf("err = %v, want fast-forward failure", err)
+	}
+	if _, err := os.Stat(wtPath); err != nil {
+		t.Errorf("worktree should be kept: %v", err)
+	}
+}
+
+func TestRunDone_MergeDirtyWorktree(t *testing.T) {
+	_, wtPath, _ := setupFinishRepo(t)
+	if err := os.WriteFile(filepath.Join(wtPath, "wip.go"), []byte("package auth\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := runDoneCmd(t, "SPEC-AUTH-001", "--merge")
+	if err == nil || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Fatalf("err = %v, want uncommitted changes", err)
+	}
+}
+
+func TestRunDone_RemoveFailureKeepsPanes(t *testing.T) {
+	_, wtPath, panes := setupFinishRepo(t)
+	if err := os.WriteFile(filepath.Join(wtPath, "wip.go"), []byte("package auth\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runDoneCmd(t, "SPEC-AUTH-001"); err == nil {
+		t.Fatal("done should fail to remove a dirty worktree without --force")
+	}
+	if len(panes.dirs) != 0 {
+		t.Errorf("panes closed in %v before the worktree was removed", panes.dirs)
+	}
+}
+
+func TestRunGo_LinksTmuxPane(t *testing.T) {
+	_, wtPath, panes := setupFinishRepo(t)
+	t.Setenv("TMUX_PANE", "%7")
+
+	cmd := newGoCmd()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"SPEC-AUTH-001"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != wtPath {
+		t.Errorf("output = %q, want only the path", buf.String())
+	}
+	if panes.linked["%7"] != wtPath {
+		t.Errorf("linked = %v, want %%7 -> %s", panes.linked, wtPath)
+	}
+}
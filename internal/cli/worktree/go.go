@@ -2,6 +2,7 @@ package worktree
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -14,7 +15,10 @@ func newGoCmd() *cobra.Command {
 
 Use with shell command substitution to change directory:
   cd $(moai worktree go my-branch)
-  cd $(moai wt go my-branch)`,
+  cd $(moai wt go my-branch)
+
+Inside tmux the current pane is linked to the worktree, and
+'moai worktree done' closes it once the worktree is removed.`,
 		Args: cobra.ExactArgs(1),
 		RunE: runGo,
 	}
@@ -35,6 +39,10 @@ func runGo(cmd *cobra.Command, args []string) error {
 
 	for _, wt := range worktrees {
 		if wt.Branch == branchName {
+			if pane := os.Getenv("TMUX_PANE"); pane != "" {
+				// Best effort: stdout is reserved for the path.
+				_ = paneLinker().LinkPane(cmd.Context(), pane, wt.Path)
+			}
 			// Output only the path for shell eval: cd $(moai wt go branch)
 			_, _ = fmt.Fprintln(out, wt.Path)
 			return nil
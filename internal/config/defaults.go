@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/modu-ai/moai-adk/pkg/models"
 )
 
@@ -26,7 +28,8 @@ const (
 	DefaultQualModel  = "opus"
 	DefaultSpeedModel = "haiku"
 
-	DefaultTokenBudget = 250000
+	DefaultTokenBudget       = 250000
+	DefaultBudgetWarnPercent = 80
 
 	DefaultMaxIterations = 5
 
@@ -34,8 +37,37 @@ const (
 	DefaultRunTokens  = 180000
 	DefaultSyncTokens = 40000
 
+	DefaultPlanTimeout = 30 * time.Minute
+	DefaultRunTimeout  = 2 * time.Hour
+	DefaultSyncTimeout = 30 * time.Minute
+
+	DefaultMaxTeammates = 10
+
+	DefaultContextInjectionTokens = 400
+	DefaultSnapshotMaxCount       = 20
+	DefaultSnapshotMaxAgeDays     = 14
+
+	DefaultContextSearchResults         = 5
+	DefaultContextSearchResultTokens    = 1000
+	DefaultContextSearchDays            = 30
+	DefaultContextSearchTimeout         = 10
+	DefaultContextSearchCacheTTL        = 300
+	DefaultContextSearchInjectionTokens = 5000
+	DefaultContextSearchSkipUsage       = 150000
+
+	DefaultWorktreeSetupTimeout = 5 * time.Minute
+	DefaultWorktreeArchiveDir   = ".moai/worktrees/archive"
+
+	DefaultAgentContextTokens = 1500
+	DefaultAgentVerifyTimeout = 20 * time.Second
+
+	DefaultHookAuditDir      = ".moai/logs/hooks"
+	DefaultHookAuditMaxSize  = 10 // MB
+	DefaultHookAuditMaxFiles = 5
+
 	DefaultBranchPrefix = "moai/"
 	DefaultCommitStyle  = "conventional"
+	DefaultGitMode      = "manual"
 
 	DefaultGLMEnvVar  = "GLM_API_KEY"
 	DefaultGLMBaseURL = "https://api.z.ai/api/anthropic"
@@ -75,6 +107,15 @@ func NewDefaultConfig() *Config {
 		Pricing:       NewDefaultPricingConfig(),
 		Ralph:         NewDefaultRalphConfig(),
 		Workflow:      NewDefaultWorkflowConfig(),
+
+		ContextInjection:  NewDefaultContextInjectionConfig(),
+		ContextSearch:     NewDefaultContextSearchConfig(),
+		Worktree:          NewDefaultWorktreeConfig(),
+		AgentContext:      NewDefaultAgentContextConfig(),
+		AgentVerification: NewDefaultAgentVerificationConfig(),
+		HookAudit:         NewDefaultHookAuditConfig(),
+		ToolRewrite:       NewDefaultToolRewriteConfig(),
+		PermissionPolicy:  NewDefaultPermissionPolicyConfig(),
 	}
 }
 
@@ -151,6 +192,11 @@ func NewDefaultGitStrategyConfig() GitStrategyConfig {
 		BranchPrefix: DefaultBranchPrefix,
 		CommitStyle:  DefaultCommitStyle,
 		Provider:     "github",
+		Mode:         DefaultGitMode,
+		Hooks: GitHooksConfig{
+			CommitMsg: HookPolicyWarn,
+			PrePush:   HookPolicyWarn,
+		},
 	}
 }
 
@@ -193,7 +239,8 @@ func NewDefaultLLMConfig() LLMConfig {
 // NewDefaultPricingConfig returns a PricingConfig with default values.
 func NewDefaultPricingConfig() PricingConfig {
 	return PricingConfig{
-		TokenBudget: DefaultTokenBudget,
+		TokenBudget:       DefaultTokenBudget,
+		BudgetWarnPercent: DefaultBudgetWarnPercent,
 	}
 }
 
@@ -209,11 +256,117 @@ func NewDefaultRalphConfig() RalphConfig {
 // NewDefaultWorkflowConfig returns a WorkflowConfig with default values.
 func NewDefaultWorkflowConfig() WorkflowConfig {
 	return WorkflowConfig{
-		AutoClear:  true,
-		PlanTokens: DefaultPlanTokens,
-		RunTokens:  DefaultRunTokens,
-		SyncTokens: DefaultSyncTokens,
+		AutoClear:    true,
+		PlanTokens:   DefaultPlanTokens,
+		RunTokens:    DefaultRunTokens,
+		SyncTokens:   DefaultSyncTokens,
+		PlanTimeout:  DefaultPlanTimeout,
+		RunTimeout:   DefaultRunTimeout,
+		SyncTimeout:  DefaultSyncTimeout,
+		MaxTeammates: DefaultMaxTeammates,
+	}
+}
+
+// NewDefaultContextInjectionConfig returns a ContextInjectionConfig with
+// default values.
+func NewDefaultContextInjectionConfig() ContextInjectionConfig {
+	return ContextInjectionConfig{
+		Enabled:     true,
+		MaxTokens:   DefaultContextInjectionTokens,
+		MinSeverity: SeverityThresholdError,
+		Snapshots: SnapshotRetention{
+			MaxCount:   DefaultSnapshotMaxCount,
+			MaxAgeDays: DefaultSnapshotMaxAgeDays,
+		},
+	}
+}
+
+// NewDefaultContextSearchConfig returns a ContextSearchConfig with default
+// values matching the context.yaml template.
+func NewDefaultContextSearchConfig() ContextSearchConfig {
+	c := ContextSearchConfig{
+		Enabled: true,
+		Search: ContextSearchLimits{
+			MaxResults:         DefaultContextSearchResults,
+			MaxTokensPerResult: DefaultContextSearchResultTokens,
+			DateRangeDays:      DefaultContextSearchDays,
+			ProjectScopeOnly:   true,
+		},
+		Performance: ContextSearchPerformance{
+			TimeoutSeconds:  DefaultContextSearchTimeout,
+			CacheTTLSeconds: DefaultContextSearchCacheTTL,
+		},
+		TokenBudget: ContextSearchBudget{
+			MaxInjectionTokens: DefaultContextSearchInjectionTokens,
+			SkipIfUsageAbove:   DefaultContextSearchSkipUsage,
+		},
 	}
+	c.AutoDetect.Enabled = true
+	return c
+}
+
+// NewDefaultWorktreeConfig returns a WorktreeConfig with default values:
+// .env files and the LSP diagnostics baseline are copied into new
+// worktrees and removed worktrees are archived.
+func NewDefaultWorktreeConfig() WorktreeConfig {
+	return WorktreeConfig{
+		Bootstrap: WorktreeBootstrap{
+			Enabled: true,
+			Copy:    []string{".env", ".env.*", ".moai/memory/diagnostics-baseline.json"},
+		},
+		Teardown: WorktreeTeardown{
+			Archive:    true,
+			ArchiveDir: DefaultWorktreeArchiveDir,
+		},
+	}
+}
+
+// NewDefaultAgentContextConfig returns an AgentContextConfig with default
+// rules: manager and team agents get the active SPEC, quality agents the
+// diagnostics baseline and testing agents the coverage summary.
+func NewDefaultAgentContextConfig() AgentContextConfig {
+	return AgentContextConfig{
+		Enabled:   true,
+		MaxTokens: DefaultAgentContextTokens,
+		Rules: []AgentContextRule{
+			{Agents: []string{"manager-*", "team-*"}, Inject: []string{AgentContextSpec}},
+			{Agents: []string{"manager-quality", "team-quality", "expert-debug", "expert-refactoring"}, Inject: []string{AgentContextDiagnostics}},
+			{Agents: []string{"expert-testing", "team-tester", "manager-tdd", "manager-ddd"}, Inject: []string{AgentContextCoverage}},
+		},
+	}
+}
+
+// NewDefaultAgentVerificationConfig returns an AgentVerificationConfig
+// that verifies claims with the detected toolchain and blocks on failure.
+func NewDefaultAgentVerificationConfig() AgentVerificationConfig {
+	return AgentVerificationConfig{
+		Enabled: true,
+		Block:   true,
+		Timeout: DefaultAgentVerifyTimeout,
+	}
+}
+
+// NewDefaultHookAuditConfig returns a HookAuditConfig that logs hook
+// decisions to .moai/logs/hooks with 10 MB files, keeping five rotations.
+func NewDefaultHookAuditConfig() HookAuditConfig {
+	return HookAuditConfig{
+		Enabled:   true,
+		Dir:       DefaultHookAuditDir,
+		MaxSizeMB: DefaultHookAuditMaxSize,
+		MaxFiles:  DefaultHookAuditMaxFiles,
+	}
+}
+
+// NewDefaultToolRewriteConfig returns a ToolRewriteConfig that applies the
+// built-in rewrite rules only.
+func NewDefaultToolRewriteConfig() ToolRewriteConfig {
+	return ToolRewriteConfig{Enabled: true}
+}
+
+// NewDefaultPermissionPolicyConfig returns a PermissionPolicyConfig
+// without rules, which asks the user for every permission request.
+func NewDefaultPermissionPolicyConfig() PermissionPolicyConfig {
+	return PermissionPolicyConfig{Enabled: true}
 }
 
 // NewDefaultGitConventionConfig returns a GitConventionConfig with default values.
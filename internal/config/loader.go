@@ -1,6 +1,7 @@
 package config
 
 import (
+	"cmp"
 	"fmt"
 	"log/slog"
 	"maps"
@@ -47,15 +48,39 @@ func (l *Loader) Load(configDir string) (*Config, error) {
 	// Load language section
 	l.loadLanguageSection(sectionsDir, cfg)
 
+	// Load project section
+	l.loadProjectSection(sectionsDir, cfg)
+
 	// Load quality section
 	l.loadQualitySection(sectionsDir, cfg)
 
 	// Load git convention section
 	l.loadGitConventionSection(sectionsDir, cfg)
 
+	// Load git strategy section
+	l.loadGitStrategySection(sectionsDir, cfg)
+
 	// Load LLM section
 	l.loadLLMSection(sectionsDir, cfg)
 
+	// Load pricing section
+	l.loadPricingSection(sectionsDir, cfg)
+
+	// Load workflow section
+	l.loadWorkflowSection(sectionsDir, cfg)
+
+	// Load context injection settings
+	l.loadContextSection(sectionsDir, cfg)
+
+	// Load agent worktree policy
+	l.loadWorktreeSection(sectionsDir, cfg)
+
+	// Load subagent context rules and claim verification
+	l.loadAgentContextSection(sectionsDir, cfg)
+
+	// Load hook policies
+	l.loadHooksSection(sectionsDir, cfg)
+
 	return cfg, nil
 }
 
@@ -98,6 +123,20 @@ func (l *Loader) loadLanguageSection(dir string, cfg *Config) {
 	}
 }
 
+// loadProjectSection loads the project metadata from project.yaml.
+func (l *Loader) loadProjectSection(dir string, cfg *Config) {
+	wrapper := &projectFileWrapper{Project: cfg.Project}
+	loaded, err := loadYAMLFile(dir, "project.yaml", wrapper)
+	if err != nil {
+		slog.Warn("failed to load project config, using defaults", "error", err)
+		return
+	}
+	if loaded {
+		cfg.Project = wrapper.Project
+		l.loadedSections["project"] = true
+	}
+}
+
 // loadQualitySection loads the quality configuration section from quality.yaml.
 // The quality.yaml file uses "constitution:" as the top-level key for
 // backward compatibility with Python MoAI-ADK.
@@ -128,6 +167,40 @@ func (l *Loader) loadGitConventionSection(dir string, cfg *Config) {
 	}
 }
 
+// loadGitStrategySection loads the forge provider settings and the hooks
+// policy of the active mode from git-strategy.yaml. The mode-specific
+// automation settings are consumed by the agent templates.
+func (l *Loader) loadGitStrategySection(dir string, cfg *Config) {
+	wrapper := &gitStrategyFileWrapper{}
+	loaded, err := loadYAMLFile(dir, "git-strategy.yaml", wrapper)
+	if err != nil {
+		slog.Warn("failed to load git strategy config, using defaults", "error", err)
+		return
+	}
+	if !loaded {
+		return
+	}
+	gs := wrapper.GitStrategy
+	// Templates render provider as "" when it was never chosen.
+	cfg.GitStrategy.Provider = cmp.Or(gs.Provider, cfg.GitStrategy.Provider)
+	cfg.GitStrategy.GitLabInstanceURL = cmp.Or(gs.GitLab.InstanceURL, gs.GitLabInstanceURL, cfg.GitStrategy.GitLabInstanceURL)
+	cfg.GitStrategy.Mode = cmp.Or(gs.Mode, cfg.GitStrategy.Mode)
+
+	var hooks GitHooksConfig
+	switch cfg.GitStrategy.Mode {
+	case "personal":
+		hooks = gs.Personal.Hooks
+	case "team":
+		hooks = gs.Team.Hooks
+	default:
+		hooks = gs.Manual.Hooks
+	}
+	cfg.GitStrategy.Hooks.CommitMsg = cmp.Or(hooks.CommitMsg, cfg.GitStrategy.Hooks.CommitMsg)
+	cfg.GitStrategy.Hooks.PrePush = cmp.Or(hooks.PrePush, cfg.GitStrategy.Hooks.PrePush)
+	cfg.GitStrategy.Hooks.PrepareCommitMsg = cmp.Or(hooks.PrepareCommitMsg, cfg.GitStrategy.Hooks.PrepareCommitMsg)
+	l.loadedSections["git_strategy"] = true
+}
+
 // loadLLMSection loads the LLM configuration section from llm.yaml.
 func (l *Loader) loadLLMSection(dir string, cfg *Config) {
 	wrapper := &llmFileWrapper{LLM: cfg.LLM}
@@ -142,6 +215,125 @@ func (l *Loader) loadLLMSection(dir string, cfg *Config) {
 	}
 }
 
+// loadPricingSection loads the pricing configuration section from pricing.yaml.
+func (l *Loader) loadPricingSection(dir string, cfg *Config) {
+	wrapper := &pricingFileWrapper{Pricing: cfg.Pricing}
+	loaded, err := loadYAMLFile(dir, "pricing.yaml", wrapper)
+	if err != nil {
+		slog.Warn("failed to load pricing config, using defaults", "error", err)
+		return
+	}
+	if loaded {
+		cfg.Pricing = wrapper.Pricing
+		l.loadedSections["pricing"] = true
+	}
+}
+
+// loadWorkflowSection loads the workflow token budgets and phase timeouts
+// from workflow.yaml. Keys missing from the file keep their defaults.
+func (l *Loader) loadWorkflowSection(dir string, cfg *Config) {
+	wrapper := &workflowFileWrapper{}
+	w := &wrapper.Workflow
+	w.AutoClear.Enabled = cfg.Workflow.AutoClear
+	w.PlanTokens, w.RunTokens, w.SyncTokens = cfg.Workflow.PlanTokens, cfg.Workflow.RunTokens, cfg.Workflow.SyncTokens
+	w.PhaseTimeout.Plan, w.PhaseTimeout.Run, w.PhaseTimeout.Sync = cfg.Workflow.PlanTimeout, cfg.Workflow.RunTimeout, cfg.Workflow.SyncTimeout
+	w.Team.MaxTeammates = cfg.Workflow.MaxTeammates
+
+	loaded, err := loadYAMLFile(dir, "workflow.yaml", wrapper)
+	if err != nil {
+		slog.Warn("failed to load workflow config, using defaults", "error", err)
+		return
+	}
+	if !loaded {
+		return
+	}
+
+	// The nested token_budget layout takes precedence over the flat keys.
+	cfg.Workflow = WorkflowConfig{
+		AutoClear:    w.AutoClear.Enabled,
+		PlanTokens:   cmp.Or(w.TokenBudget.Plan, w.PlanTokens),
+		RunTokens:    cmp.Or(w.TokenBudget.Run, w.RunTokens),
+		SyncTokens:   cmp.Or(w.TokenBudget.Sync, w.SyncTokens),
+		PlanTimeout:  w.PhaseTimeout.Plan,
+		RunTimeout:   w.PhaseTimeout.Run,
+		SyncTimeout:  w.PhaseTimeout.Sync,
+		MaxTeammates: w.Team.MaxTeammates,
+	}
+	l.loadedSections["workflow"] = true
+}
+
+// loadContextSection loads the context injection and search settings from
+// context.yaml. Keys missing from the file keep their defaults.
+func (l *Loader) loadContextSection(dir string, cfg *Config) {
+	wrapper := &contextFileWrapper{ContextInjection: cfg.ContextInjection, ContextSearch: cfg.ContextSearch}
+	loaded, err := loadYAMLFile(dir, "context.yaml", wrapper)
+	if err != nil {
+		slog.Warn("failed to load context config, using defaults", "error", err)
+		return
+	}
+	if loaded {
+		cfg.ContextInjection = wrapper.ContextInjection
+		cfg.ContextSearch = wrapper.ContextSearch
+		l.loadedSections["context_injection"] = true
+		l.loadedSections["context_search"] = true
+	}
+}
+
+// loadWorktreeSection loads the agent worktree policy from worktree.yaml.
+// Keys missing from the file keep their defaults.
+func (l *Loader) loadWorktreeSection(dir string, cfg *Config) {
+	wrapper := &worktreeFileWrapper{Worktree: cfg.Worktree}
+	loaded, err := loadYAMLFile(dir, "worktree.yaml", wrapper)
+	if err != nil {
+		slog.Warn("failed to load worktree config, using defaults", "error", err)
+		return
+	}
+	if loaded {
+		cfg.Worktree = wrapper.Worktree
+		l.loadedSections["worktree"] = true
+	}
+}
+
+// loadAgentContextSection loads the subagent context rules and claim
+// verification from agent-context.yaml. Rules and checks given in the file
+// replace the defaults.
+func (l *Loader) loadAgentContextSection(dir string, cfg *Config) {
+	wrapper := &agentContextFileWrapper{
+		AgentContext:      cfg.AgentContext,
+		AgentVerification: cfg.AgentVerification,
+	}
+	loaded, err := loadYAMLFile(dir, "agent-context.yaml", wrapper)
+	if err != nil {
+		slog.Warn("failed to load agent context config, using defaults", "error", err)
+		return
+	}
+	if loaded {
+		cfg.AgentContext = wrapper.AgentContext
+		cfg.AgentVerification = wrapper.AgentVerification
+		l.loadedSections["agent_context"] = true
+		l.loadedSections["agent_verification"] = true
+	}
+}
+
+// loadHooksSection loads the hook policies from hooks.yaml. Keys missing
+// from the file keep their defaults.
+func (l *Loader) loadHooksSection(dir string, cfg *Config) {
+	wrapper := &hooksFileWrapper{HookAudit: cfg.HookAudit, ToolRewrite: cfg.ToolRewrite, PermissionPolicy: cfg.PermissionPolicy}
+	loaded, err := loadYAMLFile(dir, "hooks.yaml", wrapper)
+	if err != nil {
+		slog.Warn("failed to load hooks config, using defaults", "error", err)
+		return
+	}
+	if loaded {
+		cfg.HookAudit = wrapper.HookAudit
+		cfg.ToolRewrite = wrapper.ToolRewrite
+		cfg.PermissionPolicy = wrapper.PermissionPolicy
+		l.loadedSections["hook_audit"] = true
+		l.loadedSections["tool_rewrite"] = true
+		l.loadedSections["permission_policy"] = true
+	}
+}
+
 // loadYAMLFile reads a YAML file from the given directory and unmarshals it
 // into the target struct. Returns (true, nil) if the file was found and parsed,
 // (false, nil) if the file does not exist, or (false, error) on failure.
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 // setupTestdataDir creates a .moai/config/sections structure under tempDir
@@ -431,3 +432,306 @@ func TestLoaderGitConventionDefaults(t *testing.T) {
 		t.Error("expected git_convention section to NOT be loaded")
 	}
 }
+
+func TestLoaderLoadPricingSection(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	root := setupTestdataDir(t, tempDir, []string{"pricing.yaml"})
+
+	loader := NewLoader()
+	cfg, err := loader.Load(filepath.Join(root, ".moai"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	if cfg.Pricing.TokenBudget != 300000 {
+		t.Errorf("Pricing.TokenBudget: got %d, want 300000", cfg.Pricing.TokenBudget)
+	}
+	if cfg.Pricing.DailyBudgetUSD != 12.5 {
+		t.Errorf("Pricing.DailyBudgetUSD: got %v, want 12.5", cfg.Pricing.DailyBudgetUSD)
+	}
+	if cfg.Pricing.MonthlyBudgetUSD != 200 {
+		t.Errorf("Pricing.MonthlyBudgetUSD: got %v, want 200", cfg.Pricing.MonthlyBudgetUSD)
+	}
+	// Unset keys keep their defaults.
+	if cfg.Pricing.BudgetWarnPercent != DefaultBudgetWarnPercent {
+		t.Errorf("Pricing.BudgetWarnPercent: got %d, want default %d",
+			cfg.Pricing.BudgetWarnPercent, DefaultBudgetWarnPercent)
+	}
+
+	if !loader.LoadedSections()["pricing"] {
+		t.Error("expected pricing section to be loaded")
+	}
+}
+
+func TestLoaderLoadWorkflowSection(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	root := setupTestdataDir(t, tempDir, []string{"workflow.yaml"})
+
+	loader := NewLoader()
+	cfg, err := loader.Load(filepath.Join(root, ".moai"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	if cfg.Workflow.AutoClear {
+		t.Error("Workflow.AutoClear: got true, want false")
+	}
+	if cfg.Workflow.PlanTokens != 25000 || cfg.Workflow.RunTokens != 150000 {
+		t.Errorf("Workflow tokens: got plan %d run %d, want 25000/150000",
+			cfg.Workflow.PlanTokens, cfg.Workflow.RunTokens)
+	}
+	if cfg.Workflow.RunTimeout != 45*time.Minute {
+		t.Errorf("Workflow.RunTimeout: got %v, want 45m", cfg.Workflow.RunTimeout)
+	}
+	if cfg.Workflow.MaxTeammates != 4 {
+		t.Errorf("Workflow.MaxTeammates: got %d, want 4", cfg.Workflow.MaxTeammates)
+	}
+	// Unset keys keep their defaults.
+	if cfg.Workflow.SyncTokens != DefaultSyncTokens {
+		t.Errorf("Workflow.SyncTokens: got %d, want default %d", cfg.Workflow.SyncTokens, DefaultSyncTokens)
+	}
+	if cfg.Workflow.PlanTimeout != DefaultPlanTimeout {
+		t.Errorf("Workflow.PlanTimeout: got %v, want default %v", cfg.Workflow.PlanTimeout, DefaultPlanTimeout)
+	}
+
+	if !loader.LoadedSections()["workflow"] {
+		t.Error("expected workflow section to be loaded")
+	}
+}
+
+func TestLoaderLoadWorkflowSection_FlatLayout(t *testing.T) {
+	t.Parallel()
+
+	// moai init writes the flat layout.
+	tempDir := t.TempDir()
+	root := setupTestdataDir(t, tempDir, nil)
+	flat := "workflow:\n  auto_clear: false\n  plan_tokens: 1000\n  run_tokens: 2000\n  sync_tokens: 3000\n"
+	if err := os.WriteFile(filepath.Join(root, ".moai", "config", "sections", "workflow.yaml"), []byte(flat), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewLoader()
+	cfg, err := loader.Load(filepath.Join(root, ".moai"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.Workflow.AutoClear || cfg.Workflow.PlanTokens != 1000 || cfg.Workflow.SyncTokens != 3000 {
+		t.Errorf("Workflow = %+v", cfg.Workflow)
+	}
+	if cfg.Workflow.RunTimeout != DefaultRunTimeout || cfg.Workflow.MaxTeammates != DefaultMaxTeammates {
+		t.Errorf("Workflow defaults not kept: %+v", cfg.Workflow)
+	}
+}
+
+func TestLoaderLoadGitStrategySection(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	root := setupTestdataDir(t, tempDir, []string{"git-strategy.yaml"})
+
+	loader := NewLoader()
+	cfg, err := loader.Load(filepath.Join(root, ".moai"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.GitStrategy.Provider != "gitlab" {
+		t.Errorf("GitStrategy.Provider: got %q, want gitlab", cfg.GitStrategy.Provider)
+	}
+	if cfg.GitStrategy.GitLabInstanceURL != "https://gitlab.example.com" {
+		t.Errorf("GitStrategy.GitLabInstanceURL: got %q", cfg.GitStrategy.GitLabInstanceURL)
+	}
+	// Hooks come from the active mode; unset policies keep their defaults.
+	if cfg.GitStrategy.Hooks.CommitMsg != HookPolicyEnforce || cfg.GitStrategy.Hooks.PrePush != HookPolicyWarn {
+		t.Errorf("GitStrategy.Hooks: got %+v, want team commit_msg enforce", cfg.GitStrategy.Hooks)
+	}
+	// Keys not present in the file keep their defaults.
+	if cfg.GitStrategy.BranchPrefix != DefaultBranchPrefix {
+		t.Errorf("GitStrategy.BranchPrefix: got %q, want default", cfg.GitStrategy.BranchPrefix)
+	}
+	if !loader.LoadedSections()["git_strategy"] {
+		t.Error("expected git_strategy section to be loaded")
+	}
+}
+
+func TestLoaderLoadContextAndProjectSections(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	root := setupTestdataDir(t, tempDir, nil)
+	sections := filepath.Join(root, ".moai", "config", "sections")
+	files := map[string]string{
+		"context.yaml": "context_search:\n  enabled: true\n  search:\n    max_results: 3\ncontext_injection:\n  min_severity: warning\n",
+		"project.yaml": "project:\n  name: demo\n  language: go\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(sections, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loader := NewLoader()
+	cfg, err := loader.Load(filepath.Join(root, ".moai"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	ci := cfg.ContextInjection
+	if ci.MinSeverity != SeverityThresholdWarning {
+		t.Errorf("ContextInjection.MinSeverity: got %q, want %q", ci.MinSeverity, SeverityThresholdWarning)
+	}
+	// Unset keys keep their defaults.
+	if !ci.Enabled || ci.MaxTokens != DefaultContextInjectionTokens || ci.Snapshots.MaxCount != DefaultSnapshotMaxCount {
+		t.Errorf("ContextInjection defaults not kept: %+v", ci)
+	}
+	if cfg.Project.Name != "demo" || cfg.Project.Language != "go" {
+		t.Errorf("Project = %+v", cfg.Project)
+	}
+	if cs := cfg.ContextSearch; cs.Search.MaxResults != 3 || cs.Search.DateRangeDays != DefaultContextSearchDays || !cs.AutoDetect.Enabled {
+		t.Errorf("ContextSearch = %+v", cs)
+	}
+	for _, name := range []string{"context_injection", "context_search", "project"} {
+		if !loader.LoadedSections()[name] {
+			t.Errorf("expected %s section to be loaded", name)
+		}
+	}
+}
+
+func TestLoaderLoadWorktreeSection(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	root := setupTestdataDir(t, tempDir, nil)
+	content := `worktree:
+  bootstrap:
+    symlink: [.tool-versions]
+    setup:
+      - command: npm
+        args: [ci]
+        timeout: 90s
+  teardown:
+    archive: false
+`
+	path := filepath.Join(root, ".moai", "config", "sections", "worktree.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewLoader()
+	cfg, err := loader.Load(filepath.Join(root, ".moai"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	b := cfg.Worktree.Bootstrap
+	if !b.Enabled || len(b.Copy) != 3 || len(b.Symlink) != 1 || b.Symlink[0] != ".tool-versions" {
+		t.Errorf("Bootstrap = %+v", b)
+	}
+	if len(b.Setup) != 1 || b.Setup[0].Command != "npm" || b.Setup[0].Args[0] != "ci" || b.Setup[0].Timeout != 90*time.Second {
+		t.Errorf("Setup = %+v", b.Setup)
+	}
+	if td := cfg.Worktree.Teardown; td.Archive || td.ArchiveDir != DefaultWorktreeArchiveDir {
+		t.Errorf("Teardown = %+v", td)
+	}
+	if !loader.LoadedSections()["worktree"] {
+		t.Error("expected worktree section to be loaded")
+	}
+}
+
+func TestLoaderLoadAgentContextSection(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	root := setupTestdataDir(t, tempDir, nil)
+	content := `agent_context:
+  max_tokens: 800
+  rules:
+    - agents: [expert-backend]
+      inject: [spec, project]
+      files: [docs/api.md]
+agent_verification:
+  block: false
+  checks:
+    - claim: tests
+      command: make
+      args: [test-short]
+`
+	path := filepath.Join(root, ".moai", "config", "sections", "agent-context.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewLoader()
+	cfg, err := loader.Load(filepath.Join(root, ".moai"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	ac := cfg.AgentContext
+	if !ac.Enabled || ac.MaxTokens != 800 {
+		t.Errorf("AgentContext = %+v", ac)
+	}
+	if len(ac.Rules) != 1 || ac.Rules[0].Agents[0] != "expert-backend" || len(ac.Rules[0].Inject) != 2 || ac.Rules[0].Files[0] != "docs/api.md" {
+		t.Errorf("Rules = %+v, want only the rule from the file", ac.Rules)
+	}
+	if !loader.LoadedSections()["agent_context"] {
+		t.Error("expected agent_context section to be loaded")
+	}
+
+	av := cfg.AgentVerification
+	if !av.Enabled || av.Block || av.Timeout != DefaultAgentVerifyTimeout {
+		t.Errorf("AgentVerification = %+v", av)
+	}
+	if len(av.Checks) != 1 || av.Checks[0].Claim != AgentClaimTests || av.Checks[0].Command != "make" || av.Checks[0].Args[0] != "test-short" {
+		t.Errorf("Checks = %+v", av.Checks)
+	}
+}
+
+func TestLoaderLoadHooksSection(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	root := setupTestdataDir(t, tempDir, nil)
+	content := `hook_audit:
+  max_size_mb: 2
+tool_rewrite:
+  disable: [strip-sudo]
+  rules:
+    - id: plan-first
+      tool: Bash
+      field: command
+      match: '^terraform apply'
+      replace: terraform plan
+      once: true
+permission_policy:
+  rules:
+    - id: run-tests
+      tools: [Bash]
+      commands: [go test]
+`
+	path := filepath.Join(root, ".moai", "config", "sections", "hooks.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewLoader()
+	cfg, err := loader.Load(filepath.Join(root, ".moai"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	want := HookAuditConfig{Enabled: true, Dir: DefaultHookAuditDir, MaxSizeMB: 2, MaxFiles: DefaultHookAuditMaxFiles}
+	if cfg.HookAudit != want {
+		t.Errorf("HookAudit = %+v, want %+v", cfg.HookAudit, want)
+	}
+	if !loader.LoadedSections()["hook_audit"] {
+		t.Error("expected hook_audit section to be loaded")
+	}
+	rw := cfg.ToolRewrite
+	if !rw.Enabled || len(rw.Disable) != 1 || len(rw.Rules) != 1 || rw.Rules[0].ID != "plan-first" || !rw.Rules[0].Once {
+		t.Errorf("ToolRewrite = %+v", rw)
+	}
+	pp := cfg.PermissionPolicy
+	if !pp.Enabled || len(pp.Rules) != 1 || pp.Rules[0].Commands[0] != "go test" {
+		t.Errorf("PermissionPolicy = %+v", pp)
+	}
+}
@@ -241,6 +241,22 @@ func (m *ConfigManager) getSectionLocked(name string) (any, error) {
 		return m.config.Ralph, nil
 	case "workflow":
 		return m.config.Workflow, nil
+	case "context_injection":
+		return m.config.ContextInjection, nil
+	case "context_search":
+		return m.config.ContextSearch, nil
+	case "worktree":
+		return m.config.Worktree, nil
+	case "agent_context":
+		return m.config.AgentContext, nil
+	case "agent_verification":
+		return m.config.AgentVerification, nil
+	case "hook_audit":
+		return m.config.HookAudit, nil
+	case "tool_rewrite":
+		return m.config.ToolRewrite, nil
+	case "permission_policy":
+		return m.config.PermissionPolicy, nil
 	default:
 		return nil, ErrSectionNotFound
 	}
@@ -315,6 +331,54 @@ func (m *ConfigManager) setSectionLocked(name string, value any) error {
 			return fmt.Errorf("%w: expected WorkflowConfig for section %q", ErrSectionTypeMismatch, name)
 		}
 		m.config.Workflow = v
+	case "context_injection":
+		v, ok := value.(ContextInjectionConfig)
+		if !ok {
+			return fmt.Errorf("%w: expected ContextInjectionConfig for section %q", ErrSectionTypeMismatch, name)
+		}
+		m.config.ContextInjection = v
+	case "context_search":
+		v, ok := value.(ContextSearchConfig)
+		if !ok {
+			return fmt.Errorf("%w: expected ContextSearchConfig for section %q", ErrSectionTypeMismatch, name)
+		}
+		m.config.ContextSearch = v
+	case "worktree":
+		v, ok := value.(WorktreeConfig)
+		if !ok {
+			return fmt.Errorf("%w: expected WorktreeConfig for section %q", ErrSectionTypeMismatch, name)
+		}
+		m.config.Worktree = v
+	case "agent_context":
+		v, ok := value.(AgentContextConfig)
+		if !ok {
+			return fmt.Errorf("%w: expected AgentContextConfig for section %q", ErrSectionTypeMismatch, name)
+		}
+		m.config.AgentContext = v
+	case "agent_verification":
+		v, ok := value.(AgentVerificationConfig)
+		if !ok {
+			return fmt.Errorf("%w: expected AgentVerificationConfig for section %q", ErrSectionTypeMismatch, name)
+		}
+		m.config.AgentVerification = v
+	case "hook_audit":
+		v, ok := value.(HookAuditConfig)
+		if !ok {
+			return fmt.Errorf("%w: expected HookAuditConfig for section %q", ErrSectionTypeMismatch, name)
+		}
+		m.config.HookAudit = v
+	case "tool_rewrite":
+		v, ok := value.(ToolRewriteConfig)
+		if !ok {
+			return fmt.Errorf("%w: expected ToolRewriteConfig for section %q", ErrSectionTypeMismatch, name)
+		}
+		m.config.ToolRewrite = v
+	case "permission_policy":
+		v, ok := value.(PermissionPolicyConfig)
+		if !ok {
+			return fmt.Errorf("%w: expected PermissionPolicyConfig for section %q", ErrSectionTypeMismatch, name)
+		}
+		m.config.PermissionPolicy = v
 	default:
 		return ErrSectionNotFound
 	}
@@ -2,6 +2,9 @@ package config
 
 import (
 	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
 
 	"github.com/modu-ai/moai-adk/pkg/models"
 )
@@ -21,16 +24,46 @@ type Config struct {
 	Pricing       PricingConfig              `yaml:"pricing"`
 	Ralph         RalphConfig                `yaml:"ralph"`
 	Workflow      WorkflowConfig             `yaml:"workflow"`
+	// ContextInjection and ContextSearch are read from context.yaml.
+	ContextInjection ContextInjectionConfig `yaml:"context_injection"`
+	ContextSearch    ContextSearchConfig    `yaml:"context_search"`
+	Worktree         WorktreeConfig         `yaml:"worktree"`
+	// AgentContext and AgentVerification are read from agent-context.yaml.
+	AgentContext      AgentContextConfig      `yaml:"agent_context"`
+	AgentVerification AgentVerificationConfig `yaml:"agent_verification"`
+	// HookAudit, ToolRewrite and PermissionPolicy are read from hooks.yaml.
+	HookAudit        HookAuditConfig        `yaml:"hook_audit"`
+	ToolRewrite      ToolRewriteConfig      `yaml:"tool_rewrite"`
+	PermissionPolicy PermissionPolicyConfig `yaml:"permission_policy"`
 }
 
 // GitStrategyConfig represents the git strategy configuration section.
 type GitStrategyConfig struct {
-	AutoBranch        bool   `yaml:"auto_branch"`
-	BranchPrefix      string `yaml:"branch_prefix"`
-	CommitStyle       string `yaml:"commit_style"`
-	WorktreeRoot      string `yaml:"worktree_root"`
-	Provider          string `yaml:"provider"`            // "github", "gitlab"
-	GitLabInstanceURL string `yaml:"gitlab_instance_url"` // GitLab instance URL
+	AutoBranch        bool           `yaml:"auto_branch"`
+	BranchPrefix      string         `yaml:"branch_prefix"`
+	CommitStyle       string         `yaml:"commit_style"`
+	WorktreeRoot      string         `yaml:"worktree_root"`
+	Provider          string         `yaml:"provider"`            // "github", "gitlab"
+	GitLabInstanceURL string         `yaml:"gitlab_instance_url"` // GitLab instance URL
+	Mode              string         `yaml:"mode"`                // "manual", "personal", "team"
+	Hooks             GitHooksConfig `yaml:"hooks"`               // hooks policy of the active mode
+}
+
+// Git hook policies for git_strategy.<mode>.hooks.
+const (
+	HookPolicyEnforce = "enforce"
+	HookPolicyWarn    = "warn"
+	HookPolicySkip    = "skip"
+)
+
+// GitHooksConfig holds the git hook policies (enforce, warn, skip) of the
+// active git_strategy mode.
+type GitHooksConfig struct {
+	CommitMsg string `yaml:"commit_msg"`
+	PrePush   string `yaml:"pre_push"`
+	// PrepareCommitMsg only adds hints to the message template, so only
+	// skip has an effect. Empty follows CommitMsg.
+	PrepareCommitMsg string `yaml:"prepare_commit_msg"`
 }
 
 // SystemConfig represents the system configuration section.
@@ -91,6 +124,12 @@ type GLMModels struct {
 type PricingConfig struct {
 	TokenBudget  int  `yaml:"token_budget"`
 	CostTracking bool `yaml:"cost_tracking"`
+	// Spending limits in USD shown by `moai usage` and the statusline.
+	// Zero disables the limit.
+	DailyBudgetUSD   float64 `yaml:"daily_budget_usd"`
+	MonthlyBudgetUSD float64 `yaml:"monthly_budget_usd"`
+	// BudgetWarnPercent is the share of a limit at which a warning is shown.
+	BudgetWarnPercent int `yaml:"budget_warn_percent"`
 }
 
 // RalphConfig represents the Ralph engine configuration section.
@@ -101,11 +140,262 @@ type RalphConfig struct {
 }
 
 // WorkflowConfig represents the workflow configuration section.
+// The timeouts bound each phase of a headless `moai workflow run`.
 type WorkflowConfig struct {
-	AutoClear  bool `yaml:"auto_clear"`
-	PlanTokens int  `yaml:"plan_tokens"`
-	RunTokens  int  `yaml:"run_tokens"`
-	SyncTokens int  `yaml:"sync_tokens"`
+	AutoClear   bool          `yaml:"auto_clear"`
+	PlanTokens  int           `yaml:"plan_tokens"`
+	RunTokens   int           `yaml:"run_tokens"`
+	SyncTokens  int           `yaml:"sync_tokens"`
+	PlanTimeout time.Duration `yaml:"plan_timeout"`
+	RunTimeout  time.Duration `yaml:"run_timeout"`
+	SyncTimeout time.Duration `yaml:"sync_timeout"`
+
+	// MaxTeammates caps how many workflows run in parallel (team.max_teammates).
+	MaxTeammates int `yaml:"max_teammates"`
+}
+
+// Diagnostic severity thresholds for context injection.
+const (
+	SeverityThresholdError   = "error"
+	SeverityThresholdWarning = "warning"
+)
+
+// ContextInjectionConfig controls the additionalContext that hooks hand
+// back to Claude: LSP diagnostics after edits and project facts at session
+// start.
+type ContextInjectionConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxTokens bounds the injected text of a single hook event.
+	MaxTokens int `yaml:"max_tokens"`
+	// MinSeverity is the lowest diagnostic severity reported: "error" or
+	// "warning".
+	MinSeverity string `yaml:"min_severity"`
+	// Snapshots bounds the PreCompact snapshots kept in
+	// .moai/memory/snapshots.
+	Snapshots SnapshotRetention `yaml:"snapshots"`
+}
+
+// SnapshotRetention limits how many session snapshots are kept and for how
+// long. A non-positive value disables that limit.
+type SnapshotRetention struct {
+	MaxCount   int `yaml:"max_count"`
+	MaxAgeDays int `yaml:"max_age_days"`
+}
+
+// ContextSearchConfig controls the search over previous Claude Code
+// sessions: `moai context search` and the related exchanges injected on
+// UserPromptSubmit.
+type ContextSearchConfig struct {
+	Enabled    bool `yaml:"enabled"`
+	AutoDetect struct {
+		// Enabled injects related exchanges into every prompt.
+		Enabled bool `yaml:"enabled"`
+	} `yaml:"auto_detect"`
+	Search      ContextSearchLimits      `yaml:"search"`
+	Performance ContextSearchPerformance `yaml:"performance"`
+	TokenBudget ContextSearchBudget      `yaml:"token_budget"`
+}
+
+// ContextSearchLimits bounds what a search returns.
+type ContextSearchLimits struct {
+	MaxResults         int `yaml:"max_results"`
+	MaxTokensPerResult int `yaml:"max_tokens_per_result"`
+	// DateRangeDays ignores exchanges older than this many days; 0 keeps
+	// all of them.
+	DateRangeDays int `yaml:"date_range_days"`
+	// ProjectScopeOnly limits the search to sessions run in the project.
+	ProjectScopeOnly bool `yaml:"project_scope_only"`
+}
+
+// ContextSearchPerformance bounds the indexing done before a search.
+type ContextSearchPerformance struct {
+	// TimeoutSeconds bounds one index update; the rest is indexed later.
+	TimeoutSeconds int `yaml:"timeout_seconds"`
+	// CacheTTLSeconds is how long an index update is considered fresh.
+	CacheTTLSeconds int `yaml:"cache_ttl_seconds"`
+}
+
+// ContextSearchBudget bounds the injected exchanges.
+type ContextSearchBudget struct {
+	MaxInjectionTokens int `yaml:"max_injection_tokens"`
+	// SkipIfUsageAbove skips the injection once the session context holds
+	// more tokens than this; 0 never skips.
+	SkipIfUsageAbove int `yaml:"skip_if_usage_above"`
+}
+
+// WorktreeConfig is the policy applied to the git worktrees Claude Code
+// creates for agents with isolation: worktree.
+type WorktreeConfig struct {
+	Bootstrap WorktreeBootstrap `yaml:"bootstrap"`
+	Teardown  WorktreeTeardown  `yaml:"teardown"`
+}
+
+// WorktreeBootstrap prepares a new worktree from the main checkout on
+// WorktreeCreate. Paths are relative to the main checkout and files that
+// already exist in the worktree are never replaced.
+type WorktreeBootstrap struct {
+	Enabled bool `yaml:"enabled"`
+	// Copy lists files or globs copied into the worktree, such as .env
+	// files and untracked local config.
+	Copy []string `yaml:"copy"`
+	// Symlink lists files or globs linked to the main checkout instead.
+	Symlink []string `yaml:"symlink"`
+	// SharedDependencies lists dependency directories (node_modules,
+	// .venv) linked to the main checkout. A directory is only linked when
+	// git ignores it in the worktree, so the link is never committed.
+	SharedDependencies []string `yaml:"shared_dependencies"`
+	// Setup lists the commands run in the worktree once the files are in
+	// place.
+	Setup []WorktreeCommand `yaml:"setup"`
+}
+
+// WorktreeCommand is a setup command. It runs without a shell.
+type WorktreeCommand struct {
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+	// Timeout bounds the command; zero uses DefaultWorktreeSetupTimeout.
+	// The WorktreeCreate hook caps it to what is left of its own deadline.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// WorktreeTeardown controls what is salvaged on WorktreeRemove.
+type WorktreeTeardown struct {
+	// Archive saves uncommitted changes as a patch and keeps unpushed
+	// commits on an archive branch before the worktree goes away.
+	Archive bool `yaml:"archive"`
+	// ArchiveDir receives the patches and reports, relative to the main
+	// checkout.
+	ArchiveDir string `yaml:"archive_dir"`
+}
+
+// Sources of the context injected into subagents.
+const (
+	AgentContextSpec        = "spec"        // excerpt of the active SPEC
+	AgentContextDiagnostics = "diagnostics" // LSP diagnostics baseline
+	AgentContextCoverage    = "coverage"    // coverage summary and target
+	AgentContextProject     = "project"     // project facts
+)
+
+// AgentContextConfig controls the context injected into subagents on
+// SubagentStart.
+type AgentContextConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxTokens bounds the injected text of one subagent.
+	MaxTokens int `yaml:"max_tokens"`
+	// Rules select what each agent is given. Every matching rule applies.
+	Rules []AgentContextRule `yaml:"rules"`
+}
+
+// AgentContextRule gives the matching agents the listed sources and files.
+type AgentContextRule struct {
+	// Agents are agent names or path.Match patterns such as "expert-*".
+	Agents []string `yaml:"agents"`
+	// Inject names built-in sources: spec, diagnostics, coverage, project.
+	Inject []string `yaml:"inject"`
+	// Files are project files added verbatim, relative to the project root.
+	Files []string `yaml:"files"`
+}
+
+// Kinds of outcomes a subagent can claim in its final report.
+const (
+	AgentClaimTests = "tests"
+	AgentClaimBuild = "build"
+	AgentClaimLint  = "lint"
+)
+
+// AgentVerificationConfig controls how SubagentStop re-checks the outcomes
+// a subagent claims, such as "all tests pass".
+type AgentVerificationConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Block sends the subagent back to work when a claimed check fails.
+	// When false the failure is only recorded in its scorecard.
+	Block bool `yaml:"block"`
+	// Timeout bounds each check; zero uses DefaultAgentVerifyTimeout.
+	// Checks that time out count as unverified, not failed.
+	Timeout time.Duration `yaml:"timeout"`
+	// Checks give the command verifying each kind of claim. Claims without
+	// a check fall back to a known check command the subagent ran itself,
+	// such as go test or npm run lint, or to the project's toolchain.
+	Checks []AgentCheck `yaml:"checks"`
+}
+
+// AgentCheck is the command verifying one kind of claim. It runs without
+// a shell in the project root.
+type AgentCheck struct {
+	// Claim is tests, build or lint.
+	Claim   string   `yaml:"claim"`
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+}
+
+// HookAuditConfig controls the append-only audit log of hook decisions.
+type HookAuditConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Dir receives audit.jsonl and its rotated files, relative to the
+	// project root.
+	Dir string `yaml:"dir"`
+	// MaxSizeMB is the size at which audit.jsonl is rotated.
+	MaxSizeMB int `yaml:"max_size_mb"`
+	// MaxFiles bounds the rotated files kept next to audit.jsonl.
+	MaxFiles int `yaml:"max_files"`
+}
+
+// ToolRewriteConfig controls the rewriting of PreToolUse tool input.
+type ToolRewriteConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Disable lists the IDs of built-in rules to skip.
+	Disable []string `yaml:"disable"`
+	// Rules are applied after the built-in rules, in order.
+	Rules []RewriteRule `yaml:"rules"`
+}
+
+// RewriteRule rewrites one string field of a tool's input.
+type RewriteRule struct {
+	ID string `yaml:"id"`
+	// Tool is a regular expression matched against the whole tool name.
+	Tool string `yaml:"tool"`
+	// Field is the tool input field rewritten, such as command or file_path.
+	Field string `yaml:"field"`
+	// Match selects the values to rewrite; Replace is its replacement and
+	// may refer to groups as ${1}.
+	Match   string `yaml:"match"`
+	Replace string `yaml:"replace"`
+	// Unless skips values that already match, keeping rules idempotent.
+	Unless string `yaml:"unless"`
+	// Action replaces Match/Replace with a built-in transformation:
+	// "project_path" resolves relative paths against the project.
+	Action string `yaml:"action"`
+	// Once applies the rule only to the first attempt of a command in a
+	// session, such as adding --dry-run before the real run.
+	Once   bool   `yaml:"once"`
+	Reason string `yaml:"reason"`
+}
+
+// PermissionPolicyConfig decides PermissionRequest events without asking
+// the user when a rule matches.
+type PermissionPolicyConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Rules are evaluated in order; the first matching rule decides.
+	Rules []PermissionRule `yaml:"rules"`
+}
+
+// PermissionRule decides the permission requests it matches. Empty scopes
+// match everything; a rule needs at least one scope besides Modes.
+type PermissionRule struct {
+	ID string `yaml:"id"`
+	// Decision is allow, deny or ask; empty means allow.
+	Decision string `yaml:"decision"`
+	// Tools are tool names such as Bash or Edit.
+	Tools []string `yaml:"tools"`
+	// Paths are globs relative to the project root matched against the
+	// file_path of the tool; ** matches any number of directories.
+	Paths []string `yaml:"paths"`
+	// Commands are prefixes of Bash commands, matched on word boundaries.
+	// Commands chaining or redirecting never match.
+	Commands []string `yaml:"commands"`
+	// Modes are Claude Code permission modes such as default or acceptEdits.
+	Modes  []string `yaml:"modes"`
+	Reason string   `yaml:"reason"`
 }
 
 // LSPQualityGates represents LSP quality gate configuration.
@@ -142,7 +432,9 @@ type SyncGate struct {
 var sectionNames = []string{
 	"user", "language", "quality", "project",
 	"git_strategy", "git_convention", "system", "llm",
-	"pricing", "ralph", "workflow",
+	"pricing", "ralph", "workflow", "context_injection",
+	"context_search", "worktree", "agent_context", "agent_verification",
+	"hook_audit", "tool_rewrite", "permission_policy",
 }
 
 // IsValidSectionName checks if the given name is a valid section name.
@@ -168,6 +460,10 @@ type languageFileWrapper struct {
 	Language models.LanguageConfig `yaml:"language"`
 }
 
+type projectFileWrapper struct {
+	Project models.ProjectConfig `yaml:"project"`
+}
+
 // qualityFileWrapper handles the quality.yaml file which uses "constitution:"
 // as the top-level key (Python MoAI-ADK backward compatibility).
 type qualityFileWrapper struct {
@@ -183,3 +479,106 @@ type gitConventionFileWrapper struct {
 type llmFileWrapper struct {
 	LLM LLMConfig `yaml:"llm"`
 }
+
+// pricingFileWrapper handles the pricing.yaml section file.
+type pricingFileWrapper struct {
+	Pricing PricingConfig `yaml:"pricing"`
+}
+
+// gitStrategyFileWrapper handles the git-strategy.yaml section file. The
+// GitLab instance URL is written nested (gitlab.instance_url) by templates and
+// `moai init`; the flat gitlab_instance_url key is accepted as well.
+type gitStrategyFileWrapper struct {
+	GitStrategy struct {
+		Provider          string `yaml:"provider"`
+		GitLabInstanceURL string `yaml:"gitlab_instance_url"`
+		GitLab            struct {
+			InstanceURL string `yaml:"instance_url"`
+		} `yaml:"gitlab"`
+		Mode     string               `yaml:"mode"`
+		Manual   gitStrategyModeHooks `yaml:"manual"`
+		Personal gitStrategyModeHooks `yaml:"personal"`
+		Team     gitStrategyModeHooks `yaml:"team"`
+	} `yaml:"git_strategy"`
+}
+
+// gitStrategyModeHooks reads the hooks policy of one git_strategy mode.
+type gitStrategyModeHooks struct {
+	Hooks GitHooksConfig `yaml:"hooks"`
+}
+
+// contextFileWrapper handles the context_injection and context_search
+// blocks of context.yaml.
+type contextFileWrapper struct {
+	ContextInjection ContextInjectionConfig `yaml:"context_injection"`
+	ContextSearch    ContextSearchConfig    `yaml:"context_search"`
+}
+
+// agentContextFileWrapper handles the agent_context and agent_verification
+// blocks of agent-context.yaml.
+type agentContextFileWrapper struct {
+	AgentContext      AgentContextConfig      `yaml:"agent_context"`
+	AgentVerification AgentVerificationConfig `yaml:"agent_verification"`
+}
+
+// hooksFileWrapper handles the hooks.yaml section file.
+type hooksFileWrapper struct {
+	HookAudit        HookAuditConfig        `yaml:"hook_audit"`
+	ToolRewrite      ToolRewriteConfig      `yaml:"tool_rewrite"`
+	PermissionPolicy PermissionPolicyConfig `yaml:"permission_policy"`
+}
+
+// worktreeFileWrapper handles the worktree.yaml section file.
+type worktreeFileWrapper struct {
+	Worktree WorktreeConfig `yaml:"worktree"`
+}
+
+// workflowFileWrapper handles the workflow.yaml section file. Two layouts
+// exist: the flat keys written by `moai init` (auto_clear: true, plan_tokens)
+// and the nested template layout (auto_clear.enabled, token_budget.plan).
+// Only keys that map onto WorkflowConfig are read; the remaining team and
+// loop settings are consumed by the agent templates.
+type workflowFileWrapper struct {
+	Workflow struct {
+		AutoClear  workflowAutoClear `yaml:"auto_clear"`
+		PlanTokens int               `yaml:"plan_tokens"`
+		RunTokens  int               `yaml:"run_tokens"`
+		SyncTokens int               `yaml:"sync_tokens"`
+
+		TokenBudget struct {
+			Plan int `yaml:"plan"`
+			Run  int `yaml:"run"`
+			Sync int `yaml:"sync"`
+		} `yaml:"token_budget"`
+		PhaseTimeout struct {
+			Plan time.Duration `yaml:"plan"`
+			Run  time.Duration `yaml:"run"`
+			Sync time.Duration `yaml:"sync"`
+		} `yaml:"phase_timeout"`
+		Team struct {
+			MaxTeammates int `yaml:"max_teammates"`
+		} `yaml:"team"`
+	} `yaml:"workflow"`
+}
+
+// workflowAutoClear accepts auto_clear either as a bool or as a mapping
+// with an enabled key.
+type workflowAutoClear struct {
+	Enabled bool
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (a *workflowAutoClear) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&a.Enabled)
+	}
+	var v struct {
+		Enabled bool `yaml:"enabled"`
+	}
+	v.Enabled = a.Enabled
+	if err := node.Decode(&v); err != nil {
+		return err
+	}
+	a.Enabled = v.Enabled
+	return nil
+}
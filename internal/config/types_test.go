@@ -130,15 +130,18 @@ func TestValidSectionNames(t *testing.T) {
 	names := ValidSectionNames()
 
 	// Verify count
-	if len(names) != 11 {
-		t.Fatalf("expected 11 section names, got %d", len(names))
+	if len(names) != 19 {
+		t.Fatalf("expected 19 section names, got %d", len(names))
 	}
 
 	// Verify all expected names are present
 	expected := map[string]bool{
 		"user": true, "language": true, "quality": true, "project": true,
 		"git_strategy": true, "git_convention": true, "system": true, "llm": true,
-		"pricing": true, "ralph": true, "workflow": true,
+		"pricing": true, "ralph": true, "workflow": true, "context_injection": true,
+		"context_search": true, "worktree": true, "agent_context": true,
+		"agent_verification": true, "hook_audit": true,
+		"tool_rewrite": true, "permission_policy": true,
 	}
 	for _, name := range names {
 		if !expected[name] {
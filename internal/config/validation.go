@@ -120,6 +120,7 @@ var validGitConventionNames = map[string]bool{
 	"conventional-commits": true,
 	"angular":              true,
 	"karma":                true,
+	"commitlint":           true,
 	"custom":               true,
 }
 
@@ -130,7 +131,7 @@ func validateGitConventionConfig(gc *models.GitConventionConfig) []ValidationErr
 	if gc.Convention != "" && !validGitConventionNames[gc.Convention] {
 		errs = append(errs, ValidationError{
 			Field:   "git_convention.convention",
-			Message: "must be one of: auto, conventional-commits, angular, karma, custom",
+			Message: "must be one of: auto, conventional-commits, angular, karma, commitlint, custom",
 			Value:   gc.Convention,
 			Wrapped: ErrInvalidConfig,
 		})
@@ -163,11 +164,12 @@ func validateGitConventionConfig(gc *models.GitConventionConfig) []ValidationErr
 		})
 	}
 
-	// When convention is "custom", pattern is required.
-	if gc.Convention == "custom" && gc.Custom.Pattern == "" {
+	// When convention is "custom", the header format must be defined by a
+	// pattern, a type list or an imported commitlint config.
+	if gc.Convention == "custom" && gc.Custom.Pattern == "" && len(gc.Custom.Types) == 0 && gc.Custom.Import == "" {
 		errs = append(errs, ValidationError{
 			Field:   "git_convention.custom.pattern",
-			Message: "pattern is required when convention is 'custom'",
+			Message: "pattern, types or import is required when convention is 'custom'",
 			Wrapped: ErrInvalidConfig,
 		})
 	}
@@ -567,6 +567,22 @@ func TestValidateGitConventionCustomRequiresPattern(t *testing.T) {
 	}
 }
 
+func TestValidateGitConventionCustomTypesOrImport(t *testing.T) {
+	t.Parallel()
+
+	for _, custom := range []models.CustomConventionConfig{
+		{Types: []string{"feat", "fix"}},
+		{Import: "commitlint.config.json"},
+	} {
+		cfg := NewDefaultConfig()
+		cfg.GitConvention.Convention = "custom"
+		cfg.GitConvention.Custom = custom
+		if err := Validate(cfg, map[string]bool{}); err != nil {
+			t.Errorf("Validate(%+v) error = %v", custom, err)
+		}
+	}
+}
+
 func TestValidateGitConventionDynamicTokens(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,372 @@
+// Package contextsearch searches previous Claude Code sessions for context
+// relevant to a prompt. Exchanges (a prompt and the assistant text that
+// answered it) are read incrementally from local transcripts into an
+// on-disk inverted index and ranked with BM25.
+package contextsearch
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/defs"
+)
+
+// indexVersion is bumped when the stored format changes; older data is
+// discarded and transcripts are re-read.
+const indexVersion = 1
+
+// settleTime is how long a transcript must be unmodified before its last
+// exchange is treated as complete.
+const settleTime = time.Minute
+
+// BM25 parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Doc is one indexed exchange.
+type Doc struct {
+	SessionID  string    `json:"sessionId"`
+	Transcript string    `json:"transcript"`
+	CWD        string    `json:"cwd,omitempty"`
+	Time       time.Time `json:"time"`
+	Prompt     string    `json:"prompt"`
+	Response   string    `json:"response,omitempty"`
+	// Len is the number of terms in the exchange.
+	Len int `json:"len"`
+}
+
+// posting records how often a term occurs in a document.
+type posting struct {
+	Doc int `json:"d"`
+	TF  int `json:"f"`
+}
+
+// fileState is the read position in one transcript.
+type fileState struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	Offset  int64     `json:"offset"`
+	// Foreign marks transcripts of other projects, which are never read
+	// again.
+	Foreign bool `json:"foreign,omitempty"`
+}
+
+// indexData is the persisted index.
+type indexData struct {
+	Version   int                   `json:"version"`
+	Scope     string                `json:"scope"`
+	CheckedAt time.Time             `json:"checkedAt"`
+	Files     map[string]*fileState `json:"files"`
+	Docs      []Doc                 `json:"docs"`
+	Postings  map[string][]posting  `json:"postings"`
+	TotalLen  int                   `json:"totalLen"`
+}
+
+// Stats summarizes the work done by Index.Update.
+type Stats struct {
+	Scanned int  // transcripts checked
+	Updated int  // transcripts with new exchanges
+	Foreign int  // transcripts of other projects found
+	Failed  int  // transcripts that could not be read
+	Pruned  int  // transcripts dropped because they were deleted or truncated
+	NewDocs int  // exchanges added
+	Partial bool // the deadline stopped the update early
+}
+
+// Index is an inverted index of the exchanges in local transcripts.
+type Index struct {
+	dir  string
+	data *indexData
+}
+
+// DefaultDir returns the index directory of a project.
+func DefaultDir(projectRoot string) string {
+	return filepath.Join(projectRoot, defs.MoAIDir, "cache", "context-search")
+}
+
+// Open opens the index stored in dir. A missing or corrupted index, or one
+// built for another scope, yields an empty index. scope is the project root
+// when only its sessions are indexed, or "" for all sessions.
+func Open(dir, scope string) *Index {
+	x := &Index{dir: dir, data: newIndexData(scope)}
+	data, err := os.ReadFile(x.path())
+	if err != nil {
+		return x
+	}
+	var stored indexData
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != indexVersion || stored.Scope != scope {
+		return x
+	}
+	if stored.Files == nil {
+		stored.Files = make(map[string]*fileState)
+	}
+	if stored.Postings == nil {
+		stored.Postings = make(map[string][]posting)
+	}
+	x.data = &stored
+	return x
+}
+
+func newIndexData(scope string) *indexData {
+	return &indexData{
+		Version:  indexVersion,
+		Scope:    scope,
+		Files:    make(map[string]*fileState),
+		Postings: make(map[string][]posting),
+	}
+}
+
+func (x *Index) path() string {
+	return filepath.Join(x.dir, "index.json")
+}
+
+// Len returns the number of indexed exchanges.
+func (x *Index) Len() int {
+	return len(x.data.Docs)
+}
+
+// Fresh reports whether the index was updated within ttl.
+func (x *Index) Fresh(ttl time.Duration, now time.Time) bool {
+	return ttl > 0 && !x.data.CheckedAt.IsZero() && now.Sub(x.data.CheckedAt) < ttl
+}
+
+// Update reads the exchanges appended to the given transcripts since the
+// last update and saves the index. Transcripts last modified before since
+// are skipped, and transcripts of other projects are skipped when the index
+// is scoped. When ctx ends the update stops early and keeps what it read;
+// the rest is read next time.
+func (x *Index) Update(ctx context.Context, paths []string, since time.Time) (Stats, error) {
+	var stats Stats
+	now := time.Now()
+
+	removed := make(map[string]bool)
+	for path := range x.data.Files {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			removed[path] = true
+			delete(x.data.Files, path)
+			stats.Pruned++
+		}
+	}
+
+	var added []Doc
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			stats.Partial = true
+			break
+		}
+		stats.Scanned++
+		info, err := os.Stat(path)
+		if err != nil {
+			stats.Failed++
+			continue
+		}
+		state := x.data.Files[path]
+		if state != nil && (state.Foreign || (state.Size == info.Size() && state.ModTime.Equal(info.ModTime()))) {
+			continue
+		}
+		if state == nil && !since.IsZero() && info.ModTime().Before(since) {
+			continue
+		}
+		if state == nil || info.Size() < state.Offset {
+			if state != nil {
+				removed[path] = true
+				stats.Pruned++
+			}
+			state = &fileState{}
+		}
+
+		res, err := scanExchanges(path, state.Offset, now.Sub(info.ModTime()) > settleTime, x.inScope)
+		if err != nil {
+			slog.Debug("context search: skip transcript", "path", path, "error", err)
+			stats.Failed++
+			continue
+		}
+		state.Size, state.ModTime, state.Offset = info.Size(), info.ModTime(), res.Offset
+		if res.CWD != "" && !x.inScope(res.CWD) {
+			state.Foreign = true
+			stats.Foreign++
+		}
+		x.data.Files[path] = state
+
+		for _, e := range res.Exchanges {
+			added = append(added, Doc{
+				SessionID:  e.SessionID,
+				Transcript: path,
+				CWD:        e.CWD,
+				Time:       e.Time,
+				Prompt:     e.Prompt,
+				Response:   e.Response,
+			})
+		}
+		if len(res.Exchanges) > 0 {
+			stats.Updated++
+		}
+	}
+
+	if len(removed) > 0 {
+		x.rebuild(removed)
+	}
+	for _, d := range added {
+		x.add(d)
+	}
+	stats.NewDocs = len(added)
+
+	x.data.CheckedAt = now
+	if err := x.save(); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+// inScope reports whether a session run in cwd belongs to the index.
+func (x *Index) inScope(cwd string) bool {
+	if x.data.Scope == "" {
+		return true
+	}
+	rel, err := filepath.Rel(x.data.Scope, cwd)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// add indexes one exchange.
+func (x *Index) add(d Doc) {
+	terms := tokenize(d.Prompt + "\n" + d.Response)
+	d.Len = len(terms)
+	id := len(x.data.Docs)
+	x.data.Docs = append(x.data.Docs, d)
+	x.data.TotalLen += d.Len
+
+	freq := make(map[string]int)
+	for _, t := range terms {
+		freq[t]++
+	}
+	for t, n := range freq {
+		x.data.Postings[t] = append(x.data.Postings[t], posting{Doc: id, TF: n})
+	}
+}
+
+// rebuild re-indexes the stored exchanges without those of the removed
+// transcripts.
+func (x *Index) rebuild(removed map[string]bool) {
+	docs := x.data.Docs
+	x.data.Docs = nil
+	x.data.Postings = make(map[string][]posting)
+	x.data.TotalLen = 0
+	for _, d := range docs {
+		if !removed[d.Transcript] {
+			x.add(d)
+		}
+	}
+}
+
+// save writes the index via a temp file and rename.
+func (x *Index) save() error {
+	data, err := json.Marshal(x.data)
+	if err != nil {
+		return fmt.Errorf("marshal context index: %w", err)
+	}
+	if err := os.MkdirAll(x.dir, 0o755); err != nil {
+		return fmt.Errorf("create context index directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(x.dir, "index.json.*.tmp")
+	if err != nil {
+		return fmt.Errorf("write context index: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write context index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write context index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), x.path()); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write context index: %w", err)
+	}
+	return nil
+}
+
+// Query selects the exchanges a search may return.
+type Query struct {
+	// Text is the search text.
+	Text string
+	// Limit is the maximum number of results; 0 means no limit.
+	Limit int
+	// Since drops exchanges older than this time.
+	Since time.Time
+	// ExcludeSession drops the exchanges of a session, usually the
+	// current one whose context Claude already has.
+	ExcludeSession string
+	// MinMatch is the number of distinct query terms a result must
+	// contain, capped at the number of query terms. 0 accepts any match.
+	MinMatch int
+}
+
+// Result is a ranked exchange.
+type Result struct {
+	Doc
+	Score float64
+}
+
+// Search ranks the indexed exchanges against the query with BM25, best
+// first.
+func (x *Index) Search(q Query) []Result {
+	terms := slices.Compact(slices.Sorted(slices.Values(tokenize(q.Text))))
+	n := len(x.data.Docs)
+	if len(terms) == 0 || n == 0 {
+		return nil
+	}
+	avgLen := float64(x.data.TotalLen) / float64(n)
+	if avgLen == 0 {
+		avgLen = 1
+	}
+
+	scores := make(map[int]float64)
+	matched := make(map[int]int)
+	for _, t := range terms {
+		postings := x.data.Postings[t]
+		if len(postings) == 0 {
+			continue
+		}
+		df := float64(len(postings))
+		idf := math.Log(1 + (float64(n)-df+0.5)/(df+0.5))
+		for _, p := range postings {
+			d := x.data.Docs[p.Doc]
+			if (!q.Since.IsZero() && d.Time.Before(q.Since)) || (q.ExcludeSession != "" && d.SessionID == q.ExcludeSession) {
+				continue
+			}
+			tf := float64(p.TF)
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(d.Len)/avgLen)
+			scores[p.Doc] += idf * tf * (bm25K1 + 1) / (tf + norm)
+			matched[p.Doc]++
+		}
+	}
+
+	minMatch := min(q.MinMatch, len(terms))
+	results := make([]Result, 0, len(scores))
+	for id, score := range scores {
+		if matched[id] < minMatch {
+			continue
+		}
+		results = append(results, Result{Doc: x.data.Docs[id], Score: score})
+	}
+	slices.SortFunc(results, func(a, b Result) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), b.Time.Compare(a.Time))
+	})
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+	return results
+}
@@ -0,0 +1,195 @@
+package contextsearch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+)
+
+// setupTranscripts writes two settled sessions of the project at root and
+// one of another project, returning their paths.
+func setupTranscripts(t *testing.T, root string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+	wt := filepath.Join(root, ".moai", "worktrees", "SPEC-AUTH-001")
+
+	a := filepath.Join(dir, "a.jsonl")
+	writeTranscript(t, a, old,
+		userLine("sess-a", root, "2026-10-01T10:00:00Z", "Why does the token refresh fail after rotation?"),
+		assistantLine("sess-a", root, "The refresh token was reused after rotation; auth/refresh.go now stores the new token first."),
+		userLine("sess-a", root, "2026-10-01T10:10:00Z", "Update the changelog"),
+		assistantLine("sess-a", root, "Added an entry under Unreleased."),
+	)
+	b := filepath.Join(dir, "b.jsonl")
+	writeTranscript(t, b, old,
+		userLine("sess-b", wt, "2026-10-02T09:00:00Z", "Write tests for the login handler"),
+		assistantLine("sess-b", wt, "Added table tests covering expired tokens and bad passwords."),
+	)
+	c := filepath.Join(dir, "c.jsonl")
+	writeTranscript(t, c, old,
+		userLine("sess-c", "/elsewhere", "2026-10-03T09:00:00Z", "token refresh rotation in another project"),
+		assistantLine("sess-c", "/elsewhere", "Not this project."),
+	)
+	return []string{a, b, c}
+}
+
+func TestIndex_UpdateAndSearch(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	paths := setupTranscripts(t, root)
+	dir := DefaultDir(root)
+
+	x := Open(dir, root)
+	stats, err := x.Update(context.Background(), paths, time.Time{})
+	if err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if stats.NewDocs != 3 || stats.Foreign != 1 || stats.Updated != 2 {
+		t.Errorf("stats = %+v, want 3 new docs from 2 transcripts and 1 foreign", stats)
+	}
+
+	results := Open(dir, root).Search(Query{Text: "token refresh rotation", Limit: 5})
+	if len(results) == 0 || results[0].SessionID != "sess-a" {
+		t.Fatalf("results = %+v, want sess-a first", results)
+	}
+	if results[0].Prompt != "Why does the token refresh fail after rotation?" {
+		t.Errorf("top prompt = %q", results[0].Prompt)
+	}
+	for _, r := range results {
+		if r.SessionID == "sess-c" {
+			t.Error("another project's session leaked into a scoped index")
+		}
+	}
+
+	// Sessions in worktrees of the project belong to it.
+	if got := x.Search(Query{Text: "login handler tests"}); len(got) == 0 || got[0].SessionID != "sess-b" {
+		t.Errorf("worktree session not found: %+v", got)
+	}
+
+	// Filters.
+	if got := x.Search(Query{Text: "token refresh rotation", ExcludeSession: "sess-a"}); len(got) != 1 || got[0].SessionID != "sess-b" {
+		t.Errorf("ExcludeSession results = %+v", got)
+	}
+	if got := x.Search(Query{Text: "changelog rotation unreleased", MinMatch: 3}); len(got) != 0 {
+		t.Errorf("MinMatch should drop partial matches: %+v", got)
+	}
+	since, _ := time.Parse(time.RFC3339, "2026-10-02T00:00:00Z")
+	if got := x.Search(Query{Text: "token", Since: since}); len(got) != 1 || got[0].SessionID != "sess-b" {
+		t.Errorf("Since results = %+v", got)
+	}
+}
+
+func TestIndex_UpdateIsIncremental(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	paths := setupTranscripts(t, root)
+	dir := DefaultDir(root)
+	if _, err := Open(dir, root).Update(context.Background(), paths, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing changed: nothing is read.
+	x := Open(dir, root)
+	stats, err := x.Update(context.Background(), paths, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.NewDocs != 0 || stats.Updated != 0 || x.Len() != 3 {
+		t.Errorf("stats = %+v, len = %d", stats, x.Len())
+	}
+
+	// An appended exchange is added; deleting a transcript drops its docs.
+	f, err := os.OpenFile(paths[1], os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(userLine("sess-b", root, "2026-10-02T09:30:00Z", "Document the session cookie flags") +
+		assistantLine("sess-b", root, "Documented HttpOnly and SameSite."))
+	_ = f.Close()
+	old := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(paths[1], old, old)
+	if err := os.Remove(paths[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err = x.Update(context.Background(), paths, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.NewDocs != 1 || stats.Pruned != 1 || stats.Failed != 1 {
+		t.Errorf("stats = %+v, want 1 new doc, 1 pruned and the deleted file failed", stats)
+	}
+	x = Open(dir, root)
+	if x.Len() != 2 {
+		t.Errorf("len = %d, want 2", x.Len())
+	}
+	if got := x.Search(Query{Text: "cookie flags"}); len(got) != 1 {
+		t.Errorf("appended exchange not found: %+v", got)
+	}
+	if got := x.Search(Query{Text: "changelog"}); len(got) != 0 {
+		t.Errorf("deleted transcript still searchable: %+v", got)
+	}
+}
+
+func TestIndex_OpenDiscardsOtherScope(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	dir := DefaultDir(root)
+	if _, err := Open(dir, root).Update(context.Background(), setupTranscripts(t, root), time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if Open(dir, "").Len() != 0 {
+		t.Error("an unscoped search must not reuse the project-scoped index")
+	}
+}
+
+func TestSearcher_Search(t *testing.T) {
+	root := t.TempDir()
+	paths := setupTranscripts(t, root)
+	orig := FindTranscripts
+	t.Cleanup(func() { FindTranscripts = orig })
+	FindTranscripts = func() ([]string, error) { return paths, nil }
+
+	cfg := config.NewDefaultContextSearchConfig()
+	cfg.Search.DateRangeDays = 0
+	cfg.Search.MaxResults = 1
+
+	results, err := NewSearcher(root, cfg).Search(context.Background(), Query{Text: "token refresh"})
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+	if len(results) != 1 || results[0].SessionID != "sess-a" {
+		t.Errorf("results = %+v, want only the best match", results)
+	}
+
+	// All projects.
+	cfg.Search.ProjectScopeOnly = false
+	cfg.Search.MaxResults = 5
+	results, err = NewSearcher(root, cfg).Search(context.Background(), Query{Text: "another project"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].SessionID != "sess-c" {
+		t.Errorf("results = %+v, want sess-c", results)
+	}
+
+	// The configured date range hides old exchanges.
+	cfg.Search.DateRangeDays = 1
+	searcher := NewSearcher(root, cfg)
+	searcher.now = func() time.Time { return time.Date(2026, 10, 3, 12, 0, 0, 0, time.UTC) }
+	results, err = searcher.Search(context.Background(), Query{Text: "token refresh"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].SessionID != "sess-c" {
+		t.Errorf("results = %+v, want only the exchange within a day", results)
+	}
+}
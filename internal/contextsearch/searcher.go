@@ -0,0 +1,96 @@
+package contextsearch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/rank"
+)
+
+// FindTranscripts locates the local Claude Code transcripts to index.
+// Tests replace it to point at fixture files.
+var FindTranscripts = rank.FindTranscripts
+
+// Searcher searches the previous sessions of a project according to its
+// context_search settings, bringing the index up to date first.
+type Searcher struct {
+	root string
+	cfg  config.ContextSearchConfig
+	now  func() time.Time
+	// idx is the index opened by the last Refresh.
+	idx *Index
+}
+
+// NewSearcher creates a Searcher for the project at projectRoot.
+func NewSearcher(projectRoot string, cfg config.ContextSearchConfig) *Searcher {
+	return &Searcher{root: projectRoot, cfg: cfg, now: time.Now}
+}
+
+// scope is the project root when the search is limited to the project.
+func (s *Searcher) scope() string {
+	if s.cfg.Search.ProjectScopeOnly {
+		return s.root
+	}
+	return ""
+}
+
+// since is the oldest exchange time within the configured date range.
+func (s *Searcher) since() time.Time {
+	if s.cfg.Search.DateRangeDays <= 0 {
+		return time.Time{}
+	}
+	return s.now().AddDate(0, 0, -s.cfg.Search.DateRangeDays)
+}
+
+// Refresh opens the index and reads new transcript content into it, unless
+// it was refreshed within the cache TTL and force is false. The update is
+// bounded by the configured timeout.
+func (s *Searcher) Refresh(ctx context.Context, force bool) (*Index, Stats, error) {
+	x := Open(DefaultDir(s.root), s.scope())
+	s.idx = x
+	ttl := time.Duration(s.cfg.Performance.CacheTTLSeconds) * time.Second
+	if !force && x.Fresh(ttl, s.now()) {
+		return x, Stats{}, nil
+	}
+
+	paths, err := FindTranscripts()
+	if err != nil {
+		return x, Stats{}, fmt.Errorf("find transcripts: %w", err)
+	}
+	if t := s.cfg.Performance.TimeoutSeconds; t > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(t)*time.Second)
+		defer cancel()
+	}
+	stats, err := x.Update(ctx, paths, s.since())
+	return x, stats, err
+}
+
+// Open opens the stored index without reading new transcript content, so
+// a following Search leaves the index file as it is.
+func (s *Searcher) Open() *Index {
+	s.idx = Open(DefaultDir(s.root), s.scope())
+	return s.idx
+}
+
+// Search ranks the exchanges against q, refreshing the index first unless
+// Refresh already ran. A zero q.Limit uses search.max_results and exchanges
+// older than search.date_range_days are left out.
+func (s *Searcher) Search(ctx context.Context, q Query) ([]Result, error) {
+	x := s.idx
+	if x == nil {
+		var err error
+		if x, _, err = s.Refresh(ctx, false); err != nil {
+			return nil, err
+		}
+	}
+	if q.Limit == 0 {
+		q.Limit = s.cfg.Search.MaxResults
+	}
+	if since := s.since(); since.After(q.Since) {
+		q.Since = since
+	}
+	return x.Search(q), nil
+}
@@ -0,0 +1,59 @@
+package contextsearch
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minTermLen is the shortest term kept, in runes.
+const minTermLen = 2
+
+// stopWords are frequent English words that carry no meaning for search.
+var stopWords = map[string]bool{
+	"a": true, "about": true, "after": true, "all": true, "also": true, "an": true,
+	"and": true, "any": true, "are": true, "as": true, "at": true, "be": true,
+	"been": true, "before": true, "but": true, "by": true, "can": true, "could": true,
+	"did": true, "do": true, "does": true, "done": true, "for": true, "from": true,
+	"had": true, "has": true, "have": true, "here": true, "how": true, "if": true,
+	"in": true, "into": true, "is": true, "it": true, "its": true, "just": true,
+	"let": true, "like": true, "me": true, "my": true, "no": true, "not": true,
+	"now": true, "of": true, "on": true, "or": true, "our": true, "please": true,
+	"should": true, "so": true, "some": true, "than": true, "that": true, "the": true,
+	"their": true, "them": true, "then": true, "there": true, "these": true, "they": true,
+	"this": true, "to": true, "up": true, "us": true, "use": true, "was": true,
+	"we": true, "were": true, "what": true, "when": true, "where": true, "which": true,
+	"while": true, "who": true, "why": true, "will": true, "with": true, "would": true,
+	"you": true, "your": true,
+}
+
+// tokenize splits text into lowercase search terms. Letters and digits
+// form terms in any script; stop words and single characters are dropped
+// and English plurals are folded. Identifiers such as "SPEC-AUTH-001" or
+// "user_id" split into their parts.
+func tokenize(text string) []string {
+	var terms []string
+	for field := range strings.FieldsFuncSeq(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(field)) < minTermLen || stopWords[field] {
+			continue
+		}
+		terms = append(terms, singular(field))
+	}
+	return terms
+}
+
+// singular folds a regular English plural onto its singular so "tokens"
+// matches "token". Short words and "-ss", "-us", "-is" endings are kept.
+func singular(term string) string {
+	switch {
+	case len(term) <= 3:
+		return term
+	case strings.HasSuffix(term, "ies"):
+		return strings.TrimSuffix(term, "ies") + "y"
+	case strings.HasSuffix(term, "s") && !strings.HasSuffix(term, "ss") &&
+		!strings.HasSuffix(term, "us") && !strings.HasSuffix(term, "is"):
+		return strings.TrimSuffix(term, "s")
+	}
+	return term
+}
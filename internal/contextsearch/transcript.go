@@ -0,0 +1,259 @@
+package contextsearch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Bounds of the prompt and the response kept per exchange. Only the stored
+// text is indexed.
+const (
+	maxPromptChars   = 2000
+	maxResponseChars = 4000
+)
+
+// exchange is one user prompt with the assistant text that answered it.
+type exchange struct {
+	SessionID string
+	CWD       string
+	Time      time.Time
+	Prompt    string
+	Response  string
+}
+
+// transcriptLine is the part of a transcript line read for exchanges.
+type transcriptLine struct {
+	Type        string    `json:"type"`
+	SessionID   string    `json:"sessionId"`
+	CWD         string    `json:"cwd"`
+	Timestamp   time.Time `json:"timestamp"`
+	IsSidechain bool      `json:"isSidechain"`
+	IsMeta      bool      `json:"isMeta"`
+	Message     struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// contentBlock is one entry of a structured message content array.
+type contentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slash command invocations are recorded as tagged text.
+var (
+	commandNameRe = regexp.MustCompile(`<command-name>([^<]*)</command-name>`)
+	commandArgsRe = regexp.MustCompile(`<command-args>([^<]*)</command-args>`)
+)
+
+// scanResult is what one pass over a transcript produced.
+type scanResult struct {
+	Exchanges []exchange
+	// Offset is where the next pass resumes: after the last complete
+	// exchange, or at the end when the transcript is settled.
+	Offset    int64
+	SessionID string
+	CWD       string
+}
+
+// scanExchanges reads the exchanges of the transcript at path from offset.
+// An exchange is complete once the next prompt starts; the last one is
+// only emitted when settled reports that the transcript stopped changing.
+// keep is called with the working directory of the first line that has
+// one; returning false stops the scan without exchanges.
+func scanExchanges(path string, offset int64, settled bool, keep func(cwd string) bool) (*scanResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open transcript: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("seek transcript: %w", err)
+	}
+
+	res := &scanResult{Offset: offset}
+	var open *exchange
+	openAt := offset
+	pos := offset
+	checked := false
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// Nothing left, or a partial line still being written.
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read transcript: %w", err)
+		}
+		start := pos
+		pos += int64(len(line))
+
+		var tl transcriptLine
+		if json.Unmarshal(bytes.TrimSpace(line), &tl) != nil {
+			continue
+		}
+		if tl.CWD != "" && !checked {
+			checked = true
+			res.CWD, res.SessionID = tl.CWD, tl.SessionID
+			if keep != nil && !keep(tl.CWD) {
+				return res, nil
+			}
+		}
+		if tl.IsSidechain || tl.IsMeta {
+			continue
+		}
+
+		switch tl.Type {
+		case "user":
+			prompt := promptText(tl.Message.Content)
+			if prompt == "" {
+				continue
+			}
+			if open != nil {
+				res.Exchanges = append(res.Exchanges, *open)
+			}
+			open = &exchange{SessionID: tl.SessionID, CWD: tl.CWD, Time: tl.Timestamp, Prompt: truncate(prompt, maxPromptChars)}
+			openAt = start
+		case "assistant":
+			if open == nil {
+				continue
+			}
+			if text := assistantText(tl.Message.Content); text != "" && len(open.Response) < maxResponseChars {
+				if open.Response != "" {
+					open.Response += "\n"
+				}
+				open.Response = truncate(open.Response+text, maxResponseChars)
+			}
+		}
+	}
+
+	switch {
+	case open == nil:
+		res.Offset = pos
+	case settled:
+		res.Exchanges = append(res.Exchanges, *open)
+		res.Offset = pos
+	default:
+		res.Offset = openAt
+	}
+	return res, nil
+}
+
+// promptText returns the text a user typed, or "" for tool results,
+// command output and other lines that are not prompts. A slash command is
+// rendered as "/name args".
+func promptText(content json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(content, &text); err != nil {
+		var blocks []contentBlock
+		if json.Unmarshal(content, &blocks) != nil {
+			return ""
+		}
+		var parts []string
+		for _, b := range blocks {
+			if b.Type == "text" {
+				parts = append(parts, b.Text)
+			}
+		}
+		text = strings.Join(parts, "\n")
+	}
+	text = strings.TrimSpace(text)
+
+	if m := commandNameRe.FindStringSubmatch(text); m != nil {
+		cmd := strings.TrimSpace(m[1])
+		if a := commandArgsRe.FindStringSubmatch(text); a != nil {
+			cmd = strings.TrimSpace(cmd + " " + strings.TrimSpace(a[1]))
+		}
+		return cmd
+	}
+	if strings.HasPrefix(text, "<local-command-") || strings.HasPrefix(text, "Caveat:") ||
+		strings.HasPrefix(text, "[Request interrupted") {
+		return ""
+	}
+	return text
+}
+
+// assistantText joins the text blocks of an assistant message.
+func assistantText(content json.RawMessage) string {
+	var blocks []contentBlock
+	if json.Unmarshal(content, &blocks) != nil {
+		return ""
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" && strings.TrimSpace(b.Text) != "" {
+			parts = append(parts, strings.TrimSpace(b.Text))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// truncate cuts s to at most n bytes on a rune boundary, marking the cut.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := n
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
+
+// SessionContextTokens returns the context size of the session recorded in
+// the transcript at path: the prompt tokens of its last main-chain
+// assistant message. It reads only the tail of the file and returns 0 when
+// no usage is found.
+func SessionContextTokens(path string) (int64, error) {
+	const tail = 256 << 10
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("open transcript: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("stat transcript: %w", err)
+	}
+	start := max(info.Size()-tail, 0)
+	buf := make([]byte, info.Size()-start)
+	if _, err := f.ReadAt(buf, start); err != nil && !errors.Is(err, io.EOF) {
+		return 0, fmt.Errorf("read transcript: %w", err)
+	}
+
+	lines := bytes.Split(buf, []byte("\n"))
+	for i := len(lines) - 1; i >= 0; i-- {
+		if !bytes.Contains(lines[i], []byte(`"usage"`)) {
+			continue
+		}
+		var tl struct {
+			Type        string `json:"type"`
+			IsSidechain bool   `json:"isSidechain"`
+			Message     struct {
+				Usage *struct {
+					InputTokens              int64 `json:"input_tokens"`
+					CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+					CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+				} `json:"usage"`
+			} `json:"message"`
+		}
+		if json.Unmarshal(lines[i], &tl) != nil || tl.Type != "assistant" || tl.IsSidechain || tl.Message.Usage == nil {
+			continue
+		}
+		u := tl.Message.Usage
+		return u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,176 @@
+package contextsearch
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// userLine is a transcript line with a typed prompt.
+func userLine(session, cwd, ts, prompt string) string {
+	return line(map[string]any{
+		"type": "user", "sessionId": session, "cwd": cwd, "timestamp": ts,
+		"message": map[string]any{"role": "user", "content": prompt},
+	})
+}
+
+// assistantLine is a transcript line with an assistant text reply.
+func assistantLine(session, cwd, text string) string {
+	return line(map[string]any{
+		"type": "assistant", "sessionId": session, "cwd": cwd,
+		"message": map[string]any{"role": "assistant", "content": []map[string]any{
+			{"type": "text", "text": text},
+			{"type": "tool_use", "name": "Read", "input": map[string]any{"file_path": "x"}},
+		}},
+	})
+}
+
+// toolResultLine is a user line carrying only a tool result.
+func toolResultLine(session, cwd string) string {
+	return line(map[string]any{
+		"type": "user", "sessionId": session, "cwd": cwd,
+		"message": map[string]any{"role": "user", "content": []map[string]any{{"type": "tool_result", "content": "ok"}}},
+	})
+}
+
+// line encodes one transcript line.
+func line(v map[string]any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(data) + "\n"
+}
+
+// writeTranscript writes lines to path and sets its modification time.
+func writeTranscript(t *testing.T, path string, modTime time.Time, lines ...string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanExchanges_Incremental(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "s1.jsonl")
+	lines := []string{
+		userLine("s1", "/repo", "2026-10-01T10:00:00Z", "How does token refresh work?"),
+		assistantLine("s1", "/repo", "The refresh token is rotated in auth/refresh.go."),
+		toolResultLine("s1", "/repo"),
+		assistantLine("s1", "/repo", "Rotation happens on every use."),
+		userLine("s1", "/repo", "2026-10-01T10:05:00Z", "Add a test for it"),
+		assistantLine("s1", "/repo", "Added TestRefreshRotation."),
+	}
+	writeTranscript(t, path, time.Now(), lines...)
+
+	// The last exchange may still grow, so an active transcript stops before it.
+	res, err := scanExchanges(path, 0, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Exchanges) != 1 {
+		t.Fatalf("got %d exchanges, want 1", len(res.Exchanges))
+	}
+	ex := res.Exchanges[0]
+	if ex.Prompt != "How does token refresh work?" || ex.SessionID != "s1" || ex.CWD != "/repo" {
+		t.Errorf("exchange = %+v", ex)
+	}
+	if ex.Response != "The refresh token is rotated in auth/refresh.go.\nRotation happens on every use." {
+		t.Errorf("response = %q", ex.Response)
+	}
+	if want := int64(len(strings.Join(lines[:4], ""))); res.Offset != want {
+		t.Errorf("offset = %d, want %d (start of the open exchange)", res.Offset, want)
+	}
+
+	// Once settled, the rest is read from the offset.
+	res, err = scanExchanges(path, res.Offset, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Exchanges) != 1 || res.Exchanges[0].Prompt != "Add a test for it" || res.Exchanges[0].Response != "Added TestRefreshRotation." {
+		t.Errorf("exchanges = %+v", res.Exchanges)
+	}
+	if info, _ := os.Stat(path); res.Offset != info.Size() {
+		t.Errorf("offset = %d, want end of file %d", res.Offset, info.Size())
+	}
+}
+
+func TestScanExchanges_KeepStopsForeignTranscripts(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "s1.jsonl")
+	writeTranscript(t, path, time.Now().Add(-time.Hour),
+		userLine("s1", "/other", "2026-10-01T10:00:00Z", "unrelated work"),
+		assistantLine("s1", "/other", "done"),
+	)
+	res, err := scanExchanges(path, 0, true, func(cwd string) bool { return cwd == "/repo" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Exchanges) != 0 || res.CWD != "/other" {
+		t.Errorf("scan = %+v, want no exchanges from /other", res)
+	}
+}
+
+func TestPromptText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{`"fix the login bug"`, "fix the login bug"},
+		{`[{"type":"text","text":"look at this"},{"type":"image"}]`, "look at this"},
+		{`[{"type":"tool_result","content":"ok"}]`, ""},
+		{`"<command-message>moai</command-message>\n<command-name>/moai</command-name>\n<command-args>run SPEC-AUTH-001</command-args>"`, "/moai run SPEC-AUTH-001"},
+		{`"<local-command-stdout>done</local-command-stdout>"`, ""},
+		{`"[Request interrupted by user]"`, ""},
+	}
+	for _, tt := range tests {
+		if got := promptText(json.RawMessage(tt.content)); got != tt.want {
+			t.Errorf("promptText(%s) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestSessionContextTokens(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "s1.jsonl")
+	usage := func(sidechain bool, input int) string {
+		return line(map[string]any{
+			"type": "assistant", "isSidechain": sidechain,
+			"message": map[string]any{"usage": map[string]any{
+				"input_tokens": input, "cache_read_input_tokens": 1000, "cache_creation_input_tokens": 10,
+			}},
+		})
+	}
+	writeTranscript(t, path, time.Now(), usage(false, 5), usage(false, 7), usage(true, 99999))
+
+	got, err := SessionContextTokens(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 1017 {
+		t.Errorf("SessionContextTokens = %d, want 1017 from the last main-chain message", got)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	got := strings.Join(tokenize("Why does the SPEC-AUTH-001 token_refresh fail? Tokens, policies, status: 토큰 갱신"), ",")
+	if want := "spec,auth,001,token,refresh,fail,token,policy,status,토큰,갱신"; got != want {
+		t.Errorf("tokenize = %q, want %q", got, want)
+	}
+}
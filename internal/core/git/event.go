@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 )
@@ -102,10 +103,10 @@ func (e *EventDetector) DetectChanges() ([]GitEvent, error) {
 		})
 	}
 
-	// Detect new commit (HEAD changed but branch did not, or same branch).
+	// Detect new commit, merge or rebase (HEAD changed on the same branch).
 	if e.lastHEAD != "" && e.lastHEAD != nowHEAD && e.lastBranch == nowBranch {
 		events = append(events, GitEvent{
-			Type:           EventNewCommit,
+			Type:           classifyHEADChange(ctx, e.root, e.lastHEAD, nowHEAD),
 			PreviousBranch: e.lastBranch,
 			CurrentBranch:  nowBranch,
 			PreviousHEAD:   e.lastHEAD,
@@ -125,6 +126,19 @@ func (e *EventDetector) DetectChanges() ([]GitEvent, error) {
 	return events, nil
 }
 
+// classifyHEADChange tells a new commit from a merge commit and from
+// rewritten history (rebase, amend or reset), where the previous HEAD is no
+// longer an ancestor of the current one.
+func classifyHEADChange(ctx context.Context, dir, prev, now string) EventType {
+	if _, err := execGit(ctx, dir, "merge-base", "--is-ancestor", prev, now); err != nil {
+		return EventRebase
+	}
+	if parents, err := execGit(ctx, dir, "rev-list", "--parents", "-n", "1", now); err == nil && len(strings.Fields(parents)) > 2 {
+		return EventMerge
+	}
+	return EventNewCommit
+}
+
 // Poll continuously monitors Git state changes at the configured interval
 // and sends detected events to the provided channel. It blocks until the
 // context is cancelled, at which point it returns ctx.Err().
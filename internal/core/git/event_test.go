@@ -57,6 +57,45 @@ func TestEventDetector_BranchSwitch(t *testing.T) {
 	}
 }
 
+func TestEventDetector_RebaseAndMerge(t *testing.T) {
+	dir := initTestRepo(t)
+	detector := NewEventDetector(dir)
+	if err := detector.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Amending rewrites history: the previous HEAD is no longer an ancestor.
+	runGit(t, dir, "commit", "--amend", "-m", "Reworded initial commit")
+	events, err := detector.DetectChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != EventRebase {
+		t.Errorf("after amend events = %+v, want one rebase", events)
+	}
+
+	runGit(t, dir, "checkout", "-q", "-b", "topic")
+	writeTestFile(t, filepath.Join(dir, "topic.txt"), "topic\n")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "Topic commit")
+	runGit(t, dir, "checkout", "-q", "main")
+	writeTestFile(t, filepath.Join(dir, "main.txt"), "main\n")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "Main commit")
+	if err := detector.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+
+	runGit(t, dir, "merge", "--no-edit", "topic")
+	events, err = detector.DetectChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != EventMerge {
+		t.Errorf("after merge events = %+v, want one merge", events)
+	}
+}
+
 func TestEventDetector_NewCommit(t *testing.T) {
 	dir := initTestRepo(t)
 	detector := NewEventDetector(dir)
@@ -72,7 +72,8 @@ const (
 	// EventMerge indicates a merge occurred.
 	EventMerge EventType = "merge"
 
-	// EventRebase indicates a rebase occurred.
+	// EventRebase indicates the branch history was rewritten (rebase, amend
+	// or reset).
 	EventRebase EventType = "rebase"
 )
 
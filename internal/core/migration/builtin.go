@@ -0,0 +1,78 @@
+package migration
+
+import (
+	"path"
+
+	"github.com/modu-ai/moai-adk/internal/defs"
+)
+
+// LegacyHookPatterns matches hook commands from the Python edition
+// (handle-*.sh wrappers and *.py hooks) that the Go binary replaced with
+// `moai hook <event>`.
+var LegacyHookPatterns = []string{
+	"handle-session-end.sh",
+	"handle-session-start.sh",
+	"handle-stop.sh",
+	"handle-pre-tool.sh",
+	"handle-post-tool.sh",
+	"handle-agent-hook.sh",
+	"handle-compact.sh",
+	"session_end__rank_submit",
+	"post_tool__code_formatter.py",
+	"post_tool__linter.py",
+	"post_tool__ast_grep_scan.py",
+}
+
+// LegacyConfigSections lists the section files that projects older than
+// 2.0.0 kept directly in .moai/config/ instead of .moai/config/sections/.
+var LegacyConfigSections = []string{
+	defs.UserYAML,
+	defs.LanguageYAML,
+	defs.QualityYAML,
+	defs.WorkflowYAML,
+	defs.ProjectYAML,
+	defs.GitStrategyYAML,
+	defs.SystemYAML,
+	defs.StatuslineYAML,
+	"git-convention.yaml",
+	"llm.yaml",
+	"pricing.yaml",
+	"ralph.yaml",
+}
+
+// builtinSteps lists the migrations shipped with this binary.
+// Add new steps at the end; the registry orders them by ToVersion.
+func builtinSteps() []Step {
+	return []Step{
+		{
+			ID:          "2.0.0-remove-legacy-local-hooks",
+			ToVersion:   "2.0.0",
+			Description: "Remove Python-edition hook commands from .claude/settings.local.json",
+			Operations: []Operation{
+				RemoveHookCommands{
+					File:     path.Join(defs.ClaudeDir, defs.SettingsLocalJSON),
+					Patterns: LegacyHookPatterns,
+				},
+			},
+		},
+		{
+			ID:          "2.0.0-move-config-into-sections",
+			ToVersion:   "2.0.0",
+			Description: "Move section files from .moai/config into .moai/config/sections",
+			Operations:  legacyConfigOperations(),
+		},
+	}
+}
+
+// legacyConfigOperations merges each legacy section file into its
+// sections/ counterpart, where the loader and the update merge expect it.
+func legacyConfigOperations() []Operation {
+	ops := make([]Operation, 0, len(LegacyConfigSections))
+	for _, name := range LegacyConfigSections {
+		ops = append(ops, MergeYAMLFile{
+			From: path.Join(defs.MoAIDir, defs.ConfigSubdir, name),
+			To:   path.Join(defs.MoAIDir, defs.SectionsSubdir, name),
+		})
+	}
+	return ops
+}
@@ -0,0 +1,192 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/manifest"
+)
+
+// Options controls a migrate up or down run.
+type Options struct {
+	// DryRun reports what would change without touching files or the manifest.
+	DryRun bool
+
+	// BackupDir, when set, receives a copy of the files each step touches
+	// (under BackupDir/<step ID>) before the step runs. A step that fails
+	// is rolled back from that copy.
+	BackupDir string
+}
+
+// Migrator applies registry steps to a project and records them in the
+// project manifest.
+type Migrator struct {
+	projectRoot string
+	registry    *Registry
+	manifest    manifest.Manager
+	now         func() time.Time
+}
+
+// NewMigrator creates a Migrator. mgr must already be loaded for projectRoot.
+func NewMigrator(projectRoot string, registry *Registry, mgr manifest.Manager) *Migrator {
+	return &Migrator{
+		projectRoot: projectRoot,
+		registry:    registry,
+		manifest:    mgr,
+		now:         time.Now,
+	}
+}
+
+// Applied returns the applied-step records from the manifest.
+func (m *Migrator) Applied() []manifest.AppliedMigration {
+	mf := m.manifest.Manifest()
+	if mf == nil {
+		return nil
+	}
+	return slices.Clone(mf.Migrations)
+}
+
+// appliedSet returns the IDs of applied steps.
+func (m *Migrator) appliedSet() map[string]bool {
+	set := make(map[string]bool)
+	for _, a := range m.Applied() {
+		set[a.ID] = true
+	}
+	return set
+}
+
+// Status classifies every registered step for a project whose templates
+// are at current, migrating toward target ("" = no upper bound).
+func (m *Migrator) Status(current, target string) []StepStatus {
+	appliedAt := make(map[string]string)
+	for _, a := range m.Applied() {
+		appliedAt[a.ID] = a.AppliedAt
+	}
+	applied := m.appliedSet()
+
+	var result []StepStatus
+	for _, s := range m.registry.Steps() {
+		result = append(result, StepStatus{
+			Step:      s,
+			State:     Classify(s, current, target, applied),
+			AppliedAt: appliedAt[s.ID],
+		})
+	}
+	return result
+}
+
+// Up applies pending steps between current and target in order and records
+// each applied step in the manifest. The manifest is saved after every
+// step so a failure leaves an accurate record of what ran.
+func (m *Migrator) Up(current, target string, opts Options) (*Report, error) {
+	report := &Report{DryRun: opts.DryRun}
+
+	for _, step := range m.registry.Pending(current, target, m.appliedSet()) {
+		stepReport, err := m.run(step, step.Operations, opts)
+		report.Steps = append(report.Steps, stepReport)
+		if err != nil {
+			return report, fmt.Errorf("migration %s: %w", step.ID, err)
+		}
+		if opts.DryRun {
+			continue
+		}
+		m.record(step, current)
+		if err := m.manifest.Save(); err != nil {
+			return report, fmt.Errorf("migration %s: record: %w", step.ID, err)
+		}
+	}
+	return report, nil
+}
+
+// Down reverts applied steps newer than target in reverse order.
+// Fails with ErrIrreversible before touching any file when one of the
+// steps to revert has an operation without an inverse.
+func (m *Migrator) Down(target string, opts Options) (*Report, error) {
+	steps := m.registry.Revertible(target, m.appliedSet())
+	for _, step := range steps {
+		if !step.Reversible() {
+			return nil, fmt.Errorf("%w: %s", ErrIrreversible, step.ID)
+		}
+	}
+
+	report := &Report{DryRun: opts.DryRun}
+	for _, step := range steps {
+		inverse := make([]Operation, 0, len(step.Operations))
+		for i := len(step.Operations) - 1; i >= 0; i-- {
+			inverse = append(inverse, step.Operations[i].Inverse())
+		}
+
+		stepReport, err := m.run(step, inverse, opts)
+		report.Steps = append(report.Steps, stepReport)
+		if err != nil {
+			return report, fmt.Errorf("revert %s: %w", step.ID, err)
+		}
+		if opts.DryRun {
+			continue
+		}
+		m.unrecord(step.ID)
+		if err := m.manifest.Save(); err != nil {
+			return report, fmt.Errorf("revert %s: record: %w", step.ID, err)
+		}
+	}
+	return report, nil
+}
+
+// run applies ops for step and collects per-operation results.
+func (m *Migrator) run(step Step, ops []Operation, opts Options) (StepReport, error) {
+	sr := StepReport{Step: step}
+
+	var snap *snapshot
+	if opts.BackupDir != "" && !opts.DryRun {
+		var err error
+		snap, err = takeSnapshot(m.projectRoot, filepath.Join(opts.BackupDir, step.ID), ops)
+		if err != nil {
+			return sr, err
+		}
+	}
+
+	for _, op := range ops {
+		changed, err := op.Apply(m.projectRoot, opts.DryRun)
+		sr.Operations = append(sr.Operations, OperationReport{
+			Description: op.Describe(),
+			Changed:     changed,
+		})
+		if err != nil {
+			if snap != nil {
+				if restoreErr := snap.restore(); restoreErr != nil {
+					return sr, errors.Join(err, fmt.Errorf("roll back: %w", restoreErr))
+				}
+			}
+			return sr, err
+		}
+	}
+	return sr, nil
+}
+
+// record appends an applied-step entry to the in-memory manifest.
+func (m *Migrator) record(step Step, from string) {
+	mf := m.manifest.Manifest()
+	if mf == nil {
+		return
+	}
+	mf.Migrations = append(mf.Migrations, manifest.AppliedMigration{
+		ID:          step.ID,
+		FromVersion: from,
+		ToVersion:   step.ToVersion,
+		AppliedAt:   m.now().UTC().Format(time.RFC3339),
+	})
+}
+
+// unrecord removes an applied-step entry from the in-memory manifest.
+func (m *Migrator) unrecord(id string) {
+	mf := m.manifest.Manifest()
+	if mf == nil {
+		return
+	}
+	mf.Migrations = slices.DeleteFunc(mf.Migrations, func(a manifest.AppliedMigration) bool {
+		return a.ID == id
+	})
+}
@@ -0,0 +1,243 @@
+package migration
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/manifest"
+)
+
+func newTestMigrator(t *testing.T, root string, steps ...Step) *Migrator {
+	t.Helper()
+	r, err := NewRegistry(steps...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr := manifest.NewManager()
+	if _, err := mgr.Load(root); err != nil {
+		t.Fatal(err)
+	}
+	m := NewMigrator(root, r, mgr)
+	m.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	return m
+}
+
+func reversibleSteps() []Step {
+	return []Step{
+		{ID: "2.1.0-move", ToVersion: "2.1.0", Operations: []Operation{MoveFile{From: "a.txt", To: "b.txt"}}},
+		{ID: "2.2.0-rename", ToVersion: "2.2.0", Operations: []Operation{RenameYAMLKey{File: "c.yaml", From: "old", To: "new"}}},
+	}
+}
+
+func TestMigrator_UpRecordsAndIsIdempotent(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "a.txt", "x")
+	writeFile(t, root, "c.yaml", "old: 1\n")
+
+	m := newTestMigrator(t, root, reversibleSteps()...)
+	report, err := m.Up("2.0.0", "2.2.0", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Steps) != 2 || report.Changed() != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	// Applied records persist across a fresh load.
+	reloaded := newTestMigrator(t, root, reversibleSteps()...)
+	applied := reloaded.Applied()
+	if len(applied) != 2 {
+		t.Fatalf("applied = %+v", applied)
+	}
+	if applied[0].ID != "2.1.0-move" || applied[0].FromVersion != "2.0.0" || applied[0].AppliedAt != "2026-01-02T03:04:05Z" {
+		t.Errorf("record = %+v", applied[0])
+	}
+
+	report, err = reloaded.Up("2.0.0", "2.2.0", Options{})
+	if err != nil || len(report.Steps) != 0 {
+		t.Errorf("second Up = (%+v, %v), want no steps", report, err)
+	}
+}
+
+func TestMigrator_UpDryRun(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "a.txt", "x")
+
+	m := newTestMigrator(t, root, reversibleSteps()...)
+	report, err := m.Up("2.0.0", "2.1.0", Options{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.DryRun || report.Changed() != 1 {
+		t.Errorf("report = %+v", report)
+	}
+	if !pathExists(filepath.Join(root, "a.txt")) {
+		t.Error("dry run moved a file")
+	}
+	if len(m.Applied()) != 0 {
+		t.Error("dry run recorded a migration")
+	}
+}
+
+func TestMigrator_UpStopsOnError(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "a.txt", "x")
+	writeFile(t, root, "b.txt", "y")
+	writeFile(t, root, "c.yaml", "old: 1\n")
+
+	m := newTestMigrator(t, root, reversibleSteps()...)
+	_, err := m.Up("2.0.0", "2.2.0", Options{})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("err = %v, want ErrConflict", err)
+	}
+	if len(m.Applied()) != 0 {
+		t.Errorf("applied = %+v, want none", m.Applied())
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "a.txt", "x")
+	writeFile(t, root, "c.yaml", "old: 1\n")
+
+	m := newTestMigrator(t, root, reversibleSteps()...)
+	if _, err := m.Up("2.0.0", "2.2.0", Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := m.Down("2.0.0", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Steps) != 2 || report.Steps[0].Step.ID != "2.2.0-rename" {
+		t.Errorf("revert order = %+v", report.Steps)
+	}
+	if !pathExists(filepath.Join(root, "a.txt")) || readFile(t, root, "c.yaml") != "old: 1\n" {
+		t.Error("files not restored")
+	}
+	if len(m.Applied()) != 0 {
+		t.Errorf("applied = %+v, want none", m.Applied())
+	}
+}
+
+func TestMigrator_DownIrreversible(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "a.txt", "x")
+	writeFile(t, root, "gone", "z")
+
+	steps := append(reversibleSteps()[:1], Step{
+		ID: "2.3.0-remove", ToVersion: "2.3.0", Operations: []Operation{RemovePath{Path: "gone"}},
+	})
+	m := newTestMigrator(t, root, steps...)
+	if _, err := m.Up("2.0.0", "2.3.0", Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Down("2.0.0", Options{}); !errors.Is(err, ErrIrreversible) {
+		t.Fatalf("err = %v, want ErrIrreversible", err)
+	}
+	if !pathExists(filepath.Join(root, "b.txt")) {
+		t.Error("reversible step was reverted despite irreversible failure")
+	}
+	if len(m.Applied()) != 2 {
+		t.Errorf("applied = %+v", m.Applied())
+	}
+
+	// Any range that includes the irreversible step is refused.
+	if _, err := m.Down("2.2.0", Options{}); !errors.Is(err, ErrIrreversible) {
+		t.Errorf("down to 2.2.0 err = %v, want ErrIrreversible", err)
+	}
+}
+
+func TestMigrator_Status(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "a.txt", "x")
+
+	m := newTestMigrator(t, root, reversibleSteps()...)
+	if _, err := m.Up("2.0.0", "2.1.0", Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses := m.Status("2.1.0", "2.2.0")
+	if len(statuses) != 2 {
+		t.Fatalf("statuses = %+v", statuses)
+	}
+	if statuses[0].State != StateApplied || statuses[0].AppliedAt == "" {
+		t.Errorf("first = %+v", statuses[0])
+	}
+	if statuses[1].State != StatePending {
+		t.Errorf("second = %+v", statuses[1])
+	}
+}
+
+func TestMigrator_LegacyConfigSections(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, ".moai/config/user.yaml", "user:\n  name: legacy_user\n")
+	writeFile(t, root, ".moai/config/quality.yaml", "constitution:\n  development_mode: tdd\n")
+	writeFile(t, root, ".moai/config/sections/quality.yaml", "constitution:\n  coverage: 85\n")
+
+	step, ok := Default().Lookup("2.0.0-move-config-into-sections")
+	if !ok {
+		t.Fatal("built-in step not registered")
+	}
+	m := newTestMigrator(t, root, step)
+	report, err := m.Up("0.0.0", "2.0.0", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Changed() != 2 {
+		t.Errorf("changed = %d, want 2", report.Changed())
+	}
+	if got := readFile(t, root, ".moai/config/sections/user.yaml"); got != "user:\n  name: legacy_user\n" {
+		t.Errorf("sections/user.yaml = %q", got)
+	}
+	if got := readFile(t, root, ".moai/config/sections/quality.yaml"); got != "constitution:\n  coverage: 85\n  development_mode: tdd\n" {
+		t.Errorf("sections/quality.yaml = %q", got)
+	}
+	if applied := m.Applied(); len(applied) != 1 || applied[0].ID != step.ID {
+		t.Errorf("applied = %+v", applied)
+	}
+
+	if _, err := m.Down("0.0.0", Options{}); !errors.Is(err, ErrIrreversible) {
+		t.Fatalf("Down err = %v, want ErrIrreversible", err)
+	}
+	if !pathExists(filepath.Join(root, ".moai/config/sections/user.yaml")) {
+		t.Error("Down moved a sections file out of place")
+	}
+}
+
+func TestMigrator_UpRollsBackFailedStep(t *testing.T) {
+	root := t.TempDir()
+	backupDir := filepath.Join(t.TempDir(), "backup")
+	writeFile(t, root, "c.yaml", "old: 1\n")
+	writeFile(t, root, "a.txt", "a")
+	writeFile(t, root, "b.txt", "b")
+
+	step := Step{ID: "2.1.0-partial", ToVersion: "2.1.0", Operations: []Operation{
+		RenameYAMLKey{File: "c.yaml", From: "old", To: "new"},
+		MoveFile{From: "b.txt", To: "d.txt"},
+		MoveFile{From: "a.txt", To: "c.yaml"},
+	}}
+	m := newTestMigrator(t, root, step)
+	if _, err := m.Up("2.0.0", "2.1.0", Options{BackupDir: backupDir}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("Up err = %v, want ErrConflict", err)
+	}
+
+	if got := readFile(t, root, "c.yaml"); got != "old: 1\n" {
+		t.Errorf("c.yaml not rolled back: %q", got)
+	}
+	if got := readFile(t, root, "b.txt"); got != "b" {
+		t.Errorf("b.txt not rolled back: %q", got)
+	}
+	if pathExists(filepath.Join(root, "d.txt")) {
+		t.Error("rollback kept a file the step created")
+	}
+	if got := readFile(t, backupDir, step.ID+"/c.yaml"); got != "old: 1\n" {
+		t.Errorf("snapshot c.yaml = %q", got)
+	}
+	if len(m.Applied()) != 0 {
+		t.Errorf("failed step recorded: %+v", m.Applied())
+	}
+}
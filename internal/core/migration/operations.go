@@ -0,0 +1,616 @@
+package migration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/modu-ai/moai-adk/internal/defs"
+)
+
+// MoveFile renames a file or directory. It is a no-op when From no longer
+// exists and fails with ErrConflict when both From and To exist.
+type MoveFile struct {
+	From string
+	To   string
+}
+
+// Describe implements Operation.
+func (o MoveFile) Describe() string {
+	return fmt.Sprintf("move %s -> %s", o.From, o.To)
+}
+
+// Paths implements Operation.
+func (o MoveFile) Paths() []string {
+	return []string{o.From, o.To}
+}
+
+// Apply implements Operation.
+func (o MoveFile) Apply(projectRoot string, dryRun bool) (bool, error) {
+	src := projectPath(projectRoot, o.From)
+	dst := projectPath(projectRoot, o.To)
+
+	if !pathExists(src) {
+		return false, nil
+	}
+	if pathExists(dst) {
+		return false, fmt.Errorf("%w: %s and %s", ErrConflict, o.From, o.To)
+	}
+	if dryRun {
+		return true, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), defs.DirPerm); err != nil {
+		return false, fmt.Errorf("create parent of %s: %w", o.To, err)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return false, fmt.Errorf("move %s: %w", o.From, err)
+	}
+	return true, nil
+}
+
+// Inverse implements Operation.
+func (o MoveFile) Inverse() Operation {
+	return MoveFile{From: o.To, To: o.From}
+}
+
+// MergeYAMLFile moves a YAML file like MoveFile but merges it into an
+// existing destination instead of failing: keys of From missing in To are
+// added, keys To already has keep their value. It is a no-op when From no
+// longer exists. The inverse moves the merged file back to From.
+type MergeYAMLFile struct {
+	From string
+	To   string
+}
+
+// Describe implements Operation.
+func (o MergeYAMLFile) Describe() string {
+	return fmt.Sprintf("merge %s -> %s", o.From, o.To)
+}
+
+// Paths implements Operation.
+func (o MergeYAMLFile) Paths() []string {
+	return []string{o.From, o.To}
+}
+
+// Apply implements Operation.
+func (o MergeYAMLFile) Apply(projectRoot string, dryRun bool) (bool, error) {
+	src := projectPath(projectRoot, o.From)
+	dst := projectPath(projectRoot, o.To)
+
+	if !pathExists(src) {
+		return false, nil
+	}
+	if !pathExists(dst) {
+		return MoveFile(o).Apply(projectRoot, dryRun)
+	}
+
+	from, fromOK, err := readYAMLNode(src)
+	if err != nil {
+		return false, err
+	}
+	to, toOK, err := readYAMLNode(dst)
+	if err != nil {
+		return false, err
+	}
+	if dryRun {
+		return true, nil
+	}
+	if fromOK {
+		if !toOK {
+			to = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		if mergeYAMLMissing(to, from) {
+			if err := writeYAMLNode(dst, to); err != nil {
+				return false, err
+			}
+		}
+	}
+	if err := os.Remove(src); err != nil {
+		return false, fmt.Errorf("remove %s: %w", o.From, err)
+	}
+	return true, nil
+}
+
+// Inverse implements Operation. The merge is irreversible: To may have
+// existed before Apply, and moving it back would take files the step
+// never touched out of place.
+func (o MergeYAMLFile) Inverse() Operation {
+	return nil
+}
+
+// mergeYAMLMissing adds the keys of the mapping src missing in dst,
+// descending into mappings both have. It reports whether dst changed.
+func mergeYAMLMissing(dst, src *yaml.Node) bool {
+	changed := false
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		_, idx := findYAMLKey(dst, []string{key.Value})
+		if idx < 0 {
+			dst.Content = append(dst.Content, key, value)
+			changed = true
+			continue
+		}
+		if existing := dst.Content[idx+1]; existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode {
+			changed = mergeYAMLMissing(existing, value) || changed
+		}
+	}
+	return changed
+}
+
+// RemovePath deletes a file or directory tree. It cannot be reversed.
+type RemovePath struct {
+	Path string
+}
+
+// Describe implements Operation.
+func (o RemovePath) Describe() string {
+	return "remove " + o.Path
+}
+
+// Paths implements Operation.
+func (o RemovePath) Paths() []string {
+	return []string{o.Path}
+}
+
+// Apply implements Operation.
+func (o RemovePath) Apply(projectRoot string, dryRun bool) (bool, error) {
+	target := projectPath(projectRoot, o.Path)
+	if !pathExists(target) {
+		return false, nil
+	}
+	if dryRun {
+		return true, nil
+	}
+	if err := os.RemoveAll(target); err != nil {
+		return false, fmt.Errorf("remove %s: %w", o.Path, err)
+	}
+	return true, nil
+}
+
+// Inverse implements Operation.
+func (o RemovePath) Inverse() Operation {
+	return nil
+}
+
+// RenameYAMLKey moves a dotted key (e.g. "llm.glm.models.opus") to a new
+// dotted path within a YAML file, preserving comments and the value subtree.
+// Missing intermediate mappings on the target path are created.
+type RenameYAMLKey struct {
+	File string
+	From string
+	To   string
+}
+
+// Describe implements Operation.
+func (o RenameYAMLKey) Describe() string {
+	return fmt.Sprintf("rename %s: %s -> %s", o.File, o.From, o.To)
+}
+
+// Paths implements Operation.
+func (o RenameYAMLKey) Paths() []string {
+	return []string{o.File}
+}
+
+// Apply implements Operation.
+func (o RenameYAMLKey) Apply(projectRoot string, dryRun bool) (bool, error) {
+	path := projectPath(projectRoot, o.File)
+	root, ok, err := readYAMLNode(path)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	srcParent, srcIdx := findYAMLKey(root, splitKey(o.From))
+	if srcParent == nil {
+		return false, nil
+	}
+	if dstParent, _ := findYAMLKey(root, splitKey(o.To)); dstParent != nil {
+		return false, fmt.Errorf("%w: %s has both %s and %s", ErrConflict, o.File, o.From, o.To)
+	}
+	segments := splitKey(o.To)
+	if yamlPathBlocked(root, segments[:len(segments)-1]) {
+		return false, fmt.Errorf("%w: %s: parent of %s is not a mapping", ErrConflict, o.File, o.To)
+	}
+	if dryRun {
+		return true, nil
+	}
+
+	key, value := srcParent.Content[srcIdx], srcParent.Content[srcIdx+1]
+	srcParent.Content = append(srcParent.Content[:srcIdx], srcParent.Content[srcIdx+2:]...)
+
+	dstParent := ensureYAMLMapping(root, segments[:len(segments)-1])
+	key.Value = segments[len(segments)-1]
+	dstParent.Content = append(dstParent.Content, key, value)
+
+	return true, writeYAMLNode(path, root)
+}
+
+// Inverse implements Operation.
+func (o RenameYAMLKey) Inverse() Operation {
+	return RenameYAMLKey{File: o.File, From: o.To, To: o.From}
+}
+
+// DeleteYAMLKey removes a dotted key from a YAML file. It cannot be reversed.
+type DeleteYAMLKey struct {
+	File string
+	Key  string
+}
+
+// Describe implements Operation.
+func (o DeleteYAMLKey) Describe() string {
+	return fmt.Sprintf("delete %s: %s", o.File, o.Key)
+}
+
+// Paths implements Operation.
+func (o DeleteYAMLKey) Paths() []string {
+	return []string{o.File}
+}
+
+// Apply implements Operation.
+func (o DeleteYAMLKey) Apply(projectRoot string, dryRun bool) (bool, error) {
+	path := projectPath(projectRoot, o.File)
+	root, ok, err := readYAMLNode(path)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	parent, idx := findYAMLKey(root, splitKey(o.Key))
+	if parent == nil {
+		return false, nil
+	}
+	if dryRun {
+		return true, nil
+	}
+	parent.Content = append(parent.Content[:idx], parent.Content[idx+2:]...)
+	return true, writeYAMLNode(path, root)
+}
+
+// Inverse implements Operation.
+func (o DeleteYAMLKey) Inverse() Operation {
+	return nil
+}
+
+// RenameJSONKey moves a dotted key within a JSON file such as settings.json.
+type RenameJSONKey struct {
+	File string
+	From string
+	To   string
+}
+
+// Describe implements Operation.
+func (o RenameJSONKey) Describe() string {
+	return fmt.Sprintf("rename %s: %s -> %s", o.File, o.From, o.To)
+}
+
+// Paths implements Operation.
+func (o RenameJSONKey) Paths() []string {
+	return []string{o.File}
+}
+
+// Apply implements Operation.
+func (o RenameJSONKey) Apply(projectRoot string, dryRun bool) (bool, error) {
+	path := projectPath(projectRoot, o.File)
+	doc, ok, err := readJSONMap(path)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	srcParent, srcKey := findJSONKey(doc, splitKey(o.From), false)
+	if srcParent == nil {
+		return false, nil
+	}
+	if dstParent, _ := findJSONKey(doc, splitKey(o.To), false); dstParent != nil {
+		return false, fmt.Errorf("%w: %s has both %s and %s", ErrConflict, o.File, o.From, o.To)
+	}
+	if dryRun {
+		return true, nil
+	}
+
+	value := srcParent[srcKey]
+	delete(srcParent, srcKey)
+	dstParent, dstKey := findJSONKey(doc, splitKey(o.To), true)
+	dstParent[dstKey] = value
+
+	return true, writeJSONMap(path, doc)
+}
+
+// Inverse implements Operation.
+func (o RenameJSONKey) Inverse() Operation {
+	return RenameJSONKey{File: o.File, From: o.To, To: o.From}
+}
+
+// RemoveHookCommands drops hook entries from a Claude Code settings file
+// whose command contains any of Patterns. Empty hook groups and events are
+// pruned. It cannot be reversed.
+type RemoveHookCommands struct {
+	File     string
+	Patterns []string
+}
+
+// Describe implements Operation.
+func (o RemoveHookCommands) Describe() string {
+	return fmt.Sprintf("remove hooks from %s matching %s", o.File, strings.Join(o.Patterns, ", "))
+}
+
+// Paths implements Operation.
+func (o RemoveHookCommands) Paths() []string {
+	return []string{o.File}
+}
+
+// Apply implements Operation.
+func (o RemoveHookCommands) Apply(projectRoot string, dryRun bool) (bool, error) {
+	path := projectPath(projectRoot, o.File)
+	doc, ok, err := readJSONMap(path)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if !PruneHookCommands(doc, o.Patterns) {
+		return false, nil
+	}
+	if dryRun {
+		return true, nil
+	}
+	return true, writeJSONMap(path, doc)
+}
+
+// Inverse implements Operation.
+func (o RemoveHookCommands) Inverse() Operation {
+	return nil
+}
+
+// PruneHookCommands removes hook entries whose command contains any of
+// patterns from a decoded settings.json map. Empty hook groups, empty event
+// lists and an empty "hooks" object are removed as well.
+// Returns true if settings was modified.
+func PruneHookCommands(settings map[string]any, patterns []string) bool {
+	hooksMap, ok := settings["hooks"].(map[string]any)
+	if !ok {
+		return false
+	}
+
+	modified := false
+	for hookType, hookListInterface := range hooksMap {
+		hookList, ok := hookListInterface.([]any)
+		if !ok {
+			continue
+		}
+
+		var cleanedHooks []any
+		for _, hookGroup := range hookList {
+			groupMap, ok := hookGroup.(map[string]any)
+			if !ok {
+				cleanedHooks = append(cleanedHooks, hookGroup)
+				continue
+			}
+
+			hooksList, ok := groupMap["hooks"].([]any)
+			if !ok {
+				cleanedHooks = append(cleanedHooks, hookGroup)
+				continue
+			}
+
+			var cleanedGroupHooks []any
+			for _, hookEntry := range hooksList {
+				entryMap, ok := hookEntry.(map[string]any)
+				if !ok {
+					cleanedGroupHooks = append(cleanedGroupHooks, hookEntry)
+					continue
+				}
+
+				command, ok := entryMap["command"].(string)
+				if !ok || !containsAny(command, patterns) {
+					cleanedGroupHooks = append(cleanedGroupHooks, hookEntry)
+					continue
+				}
+				modified = true
+			}
+
+			if len(cleanedGroupHooks) > 0 {
+				groupMap["hooks"] = cleanedGroupHooks
+				cleanedHooks = append(cleanedHooks, groupMap)
+			} else {
+				modified = true
+			}
+		}
+
+		if len(cleanedHooks) > 0 {
+			hooksMap[hookType] = cleanedHooks
+		} else {
+			delete(hooksMap, hookType)
+			modified = true
+		}
+	}
+
+	if modified && len(hooksMap) == 0 {
+		delete(settings, "hooks")
+	}
+
+	return modified
+}
+
+func containsAny(s string, patterns []string) bool {
+	for _, p := range patterns {
+		if strings.Contains(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// projectPath joins a slash-separated relative path onto the project root.
+func projectPath(projectRoot, rel string) string {
+	return filepath.Join(projectRoot, filepath.FromSlash(rel))
+}
+
+func pathExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func splitKey(key string) []string {
+	return strings.Split(key, ".")
+}
+
+// readYAMLNode parses a YAML file into its top-level mapping node.
+// Returns ok=false when the file does not exist or is empty.
+func readYAMLNode(path string) (*yaml.Node, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("read %s: %w", filepath.Base(path), err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, false, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, false, nil
+	}
+	return doc.Content[0], true, nil
+}
+
+// writeYAMLNode serializes a mapping node back to path with 2-space indent.
+func writeYAMLNode(path string, root *yaml.Node) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode %s: %w", filepath.Base(path), err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encode %s: %w", filepath.Base(path), err)
+	}
+	return os.WriteFile(path, buf.Bytes(), defs.FilePerm)
+}
+
+// findYAMLKey walks mapping nodes along segments and returns the mapping
+// that holds the final key together with the key's index in Content.
+// Returns (nil, -1) when any segment is missing.
+func findYAMLKey(node *yaml.Node, segments []string) (*yaml.Node, int) {
+	current := node
+	for i, seg := range segments {
+		if current.Kind != yaml.MappingNode {
+			return nil, -1
+		}
+		idx := -1
+		for j := 0; j+1 < len(current.Content); j += 2 {
+			if current.Content[j].Value == seg {
+				idx = j
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, -1
+		}
+		if i == len(segments)-1 {
+			return current, idx
+		}
+		current = current.Content[idx+1]
+	}
+	return nil, -1
+}
+
+// yamlPathBlocked reports whether any existing key along segments holds a
+// non-mapping value, which would prevent creating nested keys beneath it.
+func yamlPathBlocked(node *yaml.Node, segments []string) bool {
+	current := node
+	for _, seg := range segments {
+		var next *yaml.Node
+		for j := 0; j+1 < len(current.Content); j += 2 {
+			if current.Content[j].Value == seg {
+				next = current.Content[j+1]
+				break
+			}
+		}
+		if next == nil {
+			return false
+		}
+		if next.Kind != yaml.MappingNode {
+			return true
+		}
+		current = next
+	}
+	return false
+}
+
+// ensureYAMLMapping walks or creates mapping nodes along segments and
+// returns the innermost mapping.
+func ensureYAMLMapping(node *yaml.Node, segments []string) *yaml.Node {
+	current := node
+	for _, seg := range segments {
+		var next *yaml.Node
+		for j := 0; j+1 < len(current.Content); j += 2 {
+			if current.Content[j].Value == seg && current.Content[j+1].Kind == yaml.MappingNode {
+				next = current.Content[j+1]
+				break
+			}
+		}
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			current.Content = append(current.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: seg}, next)
+		}
+		current = next
+	}
+	return current
+}
+
+// readJSONMap decodes a JSON object file. Returns ok=false when the file
+// does not exist.
+func readJSONMap(path string) (map[string]any, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("read %s: %w", filepath.Base(path), err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, false, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+	}
+	if doc == nil {
+		return nil, false, nil
+	}
+	return doc, true, nil
+}
+
+// writeJSONMap writes a JSON object with 2-space indentation and a trailing newline.
+func writeJSONMap(path string, doc map[string]any) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", filepath.Base(path), err)
+	}
+	return os.WriteFile(path, append(data, '\n'), defs.FilePerm)
+}
+
+// findJSONKey walks nested objects along segments and returns the object
+// holding the final key. With create set, missing objects are created.
+func findJSONKey(doc map[string]any, segments []string, create bool) (map[string]any, string) {
+	current := doc
+	for _, seg := range segments[:len(segments)-1] {
+		next, ok := current[seg].(map[string]any)
+		if !ok {
+			if !create {
+				return nil, ""
+			}
+			next = map[string]any{}
+			current[seg] = next
+		}
+		current = next
+	}
+	last := segments[len(segments)-1]
+	if _, ok := current[last]; !ok && !create {
+		return nil, ""
+	}
+	return current, last
+}
@@ -0,0 +1,334 @@
+package migration
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, root, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestMoveFile(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "old/a.md", "hello")
+	op := MoveFile{From: "old/a.md", To: "new/dir/a.md"}
+
+	changed, err := op.Apply(root, true)
+	if err != nil || !changed {
+		t.Fatalf("dry run = (%v, %v), want (true, nil)", changed, err)
+	}
+	if !pathExists(filepath.Join(root, "old/a.md")) {
+		t.Fatal("dry run moved the file")
+	}
+
+	if changed, err = op.Apply(root, false); err != nil || !changed {
+		t.Fatalf("Apply = (%v, %v), want (true, nil)", changed, err)
+	}
+	if got := readFile(t, root, "new/dir/a.md"); got != "hello" {
+		t.Errorf("moved content = %q", got)
+	}
+
+	// Second run is a no-op.
+	if changed, err = op.Apply(root, false); err != nil || changed {
+		t.Errorf("rerun = (%v, %v), want (false, nil)", changed, err)
+	}
+
+	if changed, err = op.Inverse().Apply(root, false); err != nil || !changed {
+		t.Fatalf("inverse = (%v, %v), want (true, nil)", changed, err)
+	}
+	if !pathExists(filepath.Join(root, "old/a.md")) {
+		t.Error("inverse did not restore the file")
+	}
+}
+
+func TestMoveFile_Conflict(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "a", "1")
+	writeFile(t, root, "b", "2")
+
+	_, err := MoveFile{From: "a", To: "b"}.Apply(root, false)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("err = %v, want ErrConflict", err)
+	}
+	if readFile(t, root, "b") != "2" {
+		t.Error("conflicting destination was overwritten")
+	}
+}
+
+func TestRemovePath(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "legacy/x/y.txt", "y")
+	op := RemovePath{Path: "legacy"}
+
+	if op.Inverse() != nil {
+		t.Error("RemovePath should be irreversible")
+	}
+	if changed, err := op.Apply(root, false); err != nil || !changed {
+		t.Fatalf("Apply = (%v, %v)", changed, err)
+	}
+	if pathExists(filepath.Join(root, "legacy")) {
+		t.Error("path still exists")
+	}
+	if changed, _ := op.Apply(root, false); changed {
+		t.Error("rerun reported a change")
+	}
+}
+
+func TestRenameYAMLKey(t *testing.T) {
+	const file = "config.yaml"
+	input := `# header comment
+llm:
+  # model mapping
+  glm_models:
+    opus: glm-4.6 # inline
+  mode: claude
+`
+	tests := []struct {
+		name     string
+		op       RenameYAMLKey
+		wantErr  error
+		changed  bool
+		contains []string
+		absent   []string
+	}{
+		{
+			name:     "nested move keeps comments",
+			op:       RenameYAMLKey{File: file, From: "llm.glm_models", To: "llm.glm.models"},
+			changed:  true,
+			contains: []string{"# header comment", "glm:", "models:", "opus: glm-4.6 # inline"},
+			absent:   []string{"glm_models"},
+		},
+		{
+			name:     "rename in place",
+			op:       RenameYAMLKey{File: file, From: "llm.mode", To: "llm.team_mode"},
+			changed:  true,
+			contains: []string{"team_mode: claude"},
+		},
+		{
+			name: "missing source is no-op",
+			op:   RenameYAMLKey{File: file, From: "llm.nope", To: "llm.other"},
+		},
+		{
+			name: "missing file is no-op",
+			op:   RenameYAMLKey{File: "absent.yaml", From: "a", To: "b"},
+		},
+		{
+			name:    "destination exists",
+			op:      RenameYAMLKey{File: file, From: "llm.glm_models", To: "llm.mode"},
+			wantErr: ErrConflict,
+		},
+		{
+			name:    "destination parent is scalar",
+			op:      RenameYAMLKey{File: file, From: "llm.glm_models", To: "llm.mode.models"},
+			wantErr: ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFile(t, root, file, input)
+
+			changed, err := tt.op.Apply(root, false)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if readFile(t, root, file) != input {
+					t.Error("file modified on conflict")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed != tt.changed {
+				t.Errorf("changed = %v, want %v", changed, tt.changed)
+			}
+			got := readFile(t, root, file)
+			for _, s := range tt.contains {
+				if !strings.Contains(got, s) {
+					t.Errorf("output missing %q:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.absent {
+				if strings.Contains(got, s) {
+					t.Errorf("output still contains %q:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRenameYAMLKey_DryRunAndInverse(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "c.yaml", "a:\n  b: 1\n")
+	op := RenameYAMLKey{File: "c.yaml", From: "a.b", To: "a.c"}
+
+	if changed, err := op.Apply(root, true); err != nil || !changed {
+		t.Fatalf("dry run = (%v, %v)", changed, err)
+	}
+	if readFile(t, root, "c.yaml") != "a:\n  b: 1\n" {
+		t.Fatal("dry run modified the file")
+	}
+	if _, err := op.Apply(root, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := op.Inverse().Apply(root, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, root, "c.yaml"); got != "a:\n  b: 1\n" {
+		t.Errorf("round trip = %q", got)
+	}
+}
+
+func TestDeleteYAMLKey(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "c.yaml", "a:\n  b: 1\n  c: 2\n")
+	op := DeleteYAMLKey{File: "c.yaml", Key: "a.b"}
+
+	if op.Inverse() != nil {
+		t.Error("DeleteYAMLKey should be irreversible")
+	}
+	if changed, err := op.Apply(root, false); err != nil || !changed {
+		t.Fatalf("Apply = (%v, %v)", changed, err)
+	}
+	if got := readFile(t, root, "c.yaml"); got != "a:\n  c: 2\n" {
+		t.Errorf("result = %q", got)
+	}
+	if changed, _ := op.Apply(root, false); changed {
+		t.Error("rerun reported a change")
+	}
+}
+
+func TestRenameJSONKey(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "s.json", `{"env": {"OLD": "1"}, "keep": true}`)
+	op := RenameJSONKey{File: "s.json", From: "env.OLD", To: "moai.env.NEW"}
+
+	if changed, err := op.Apply(root, false); err != nil || !changed {
+		t.Fatalf("Apply = (%v, %v)", changed, err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(readFile(t, root, "s.json")), &doc); err != nil {
+		t.Fatal(err)
+	}
+	moai, _ := doc["moai"].(map[string]any)
+	env, _ := moai["env"].(map[string]any)
+	if env["NEW"] != "1" {
+		t.Errorf("moai.env.NEW = %v", env["NEW"])
+	}
+	if old, _ := doc["env"].(map[string]any); old["OLD"] != nil {
+		t.Error("env.OLD still present")
+	}
+
+	writeFile(t, root, "s.json", `{"a": 1, "b": 2}`)
+	if _, err := (RenameJSONKey{File: "s.json", From: "a", To: "b"}).Apply(root, false); !errors.Is(err, ErrConflict) {
+		t.Errorf("err = %v, want ErrConflict", err)
+	}
+}
+
+func TestRemoveHookCommands(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, ".claude/settings.local.json", `{
+  "hooks": {
+    "Stop": [{"hooks": [{"type": "command", "command": "bash handle-stop.sh"}]}],
+    "SessionStart": [{"hooks": [
+      {"type": "command", "command": "moai hook session-start"},
+      {"type": "command", "command": "python post_tool__linter.py"}
+    ]}]
+  },
+  "permissions": {}
+}`)
+	op := RemoveHookCommands{File: ".claude/settings.local.json", Patterns: LegacyHookPatterns}
+
+	if changed, err := op.Apply(root, true); err != nil || !changed {
+		t.Fatalf("dry run = (%v, %v)", changed, err)
+	}
+	if changed, err := op.Apply(root, false); err != nil || !changed {
+		t.Fatalf("Apply = (%v, %v)", changed, err)
+	}
+	got := readFile(t, root, ".claude/settings.local.json")
+	if strings.Contains(got, "handle-stop.sh") || strings.Contains(got, "post_tool__linter.py") || strings.Contains(got, `"Stop"`) {
+		t.Errorf("legacy hooks remain:\n%s", got)
+	}
+	if !strings.Contains(got, "moai hook session-start") {
+		t.Errorf("current hook removed:\n%s", got)
+	}
+	if changed, _ := op.Apply(root, false); changed {
+		t.Error("rerun reported a change")
+	}
+}
+
+func TestMergeYAMLFile(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "config/user.yaml", "user:\n  name: legacy # set by hand\n  email: a@b.c\nextra: true\n")
+	writeFile(t, root, "config/sections/user.yaml", "user:\n  name: current\n")
+	op := MergeYAMLFile{From: "config/user.yaml", To: "config/sections/user.yaml"}
+
+	changed, err := op.Apply(root, true)
+	if err != nil || !changed {
+		t.Fatalf("dry run = (%v, %v), want (true, nil)", changed, err)
+	}
+	if !pathExists(filepath.Join(root, "config/user.yaml")) {
+		t.Fatal("dry run removed the source")
+	}
+
+	if changed, err = op.Apply(root, false); err != nil || !changed {
+		t.Fatalf("Apply = (%v, %v), want (true, nil)", changed, err)
+	}
+	got := readFile(t, root, "config/sections/user.yaml")
+	for _, want := range []string{"name: current", "email: a@b.c", "extra: true"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("merged file missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "legacy") {
+		t.Errorf("merge overwrote an existing key:\n%s", got)
+	}
+	if pathExists(filepath.Join(root, "config/user.yaml")) {
+		t.Error("source still exists after merge")
+	}
+
+	if changed, err = op.Apply(root, false); err != nil || changed {
+		t.Errorf("rerun = (%v, %v), want (false, nil)", changed, err)
+	}
+
+	if op.Inverse() != nil {
+		t.Error("merge should be irreversible")
+	}
+}
+
+func TestMergeYAMLFile_MissingDestination(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "config/quality.yaml", "constitution:\n  development_mode: tdd\n")
+
+	op := MergeYAMLFile{From: "config/quality.yaml", To: "config/sections/quality.yaml"}
+	if changed, err := op.Apply(root, false); err != nil || !changed {
+		t.Fatalf("Apply = (%v, %v), want (true, nil)", changed, err)
+	}
+	if got := readFile(t, root, "config/sections/quality.yaml"); got != "constitution:\n  development_mode: tdd\n" {
+		t.Errorf("moved content = %q", got)
+	}
+}
@@ -0,0 +1,132 @@
+package migration
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modu-ai/moai-adk/internal/update"
+)
+
+// Registry holds migration steps ordered by ToVersion, then ID.
+type Registry struct {
+	steps []Step
+}
+
+// NewRegistry validates and orders the given steps.
+// Returns ErrInvalidStep for a missing ID or unparseable version and
+// ErrDuplicateStep when two steps share an ID.
+func NewRegistry(steps ...Step) (*Registry, error) {
+	seen := make(map[string]bool, len(steps))
+	for _, s := range steps {
+		if strings.TrimSpace(s.ID) == "" {
+			return nil, fmt.Errorf("%w: empty ID", ErrInvalidStep)
+		}
+		if seen[s.ID] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateStep, s.ID)
+		}
+		seen[s.ID] = true
+		if _, err := update.ParseSemver(s.ToVersion); err != nil {
+			return nil, fmt.Errorf("%w: %s: to version: %v", ErrInvalidStep, s.ID, err)
+		}
+		if s.FromVersion != "" {
+			if _, err := update.ParseSemver(s.FromVersion); err != nil {
+				return nil, fmt.Errorf("%w: %s: from version: %v", ErrInvalidStep, s.ID, err)
+			}
+		}
+		if len(s.Operations) == 0 {
+			return nil, fmt.Errorf("%w: %s: no operations", ErrInvalidStep, s.ID)
+		}
+	}
+
+	ordered := slices.Clone(steps)
+	slices.SortStableFunc(ordered, func(a, b Step) int {
+		if c := compareVersions(a.ToVersion, b.ToVersion); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return &Registry{steps: ordered}, nil
+}
+
+// Default returns the registry of built-in migration steps.
+func Default() *Registry {
+	r, err := NewRegistry(builtinSteps()...)
+	if err != nil {
+		panic(fmt.Sprintf("migration: invalid built-in registry: %v", err))
+	}
+	return r
+}
+
+// Steps returns all registered steps in application order.
+func (r *Registry) Steps() []Step {
+	return slices.Clone(r.steps)
+}
+
+// Lookup returns the step with the given ID.
+func (r *Registry) Lookup(id string) (Step, bool) {
+	for _, s := range r.steps {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Step{}, false
+}
+
+// Classify returns the state of step for a project whose templates are at
+// current, migrating toward target, given the set of applied step IDs.
+func Classify(step Step, current, target string, applied map[string]bool) State {
+	switch {
+	case applied[step.ID]:
+		return StateApplied
+	case target != "" && compareVersions(step.ToVersion, target) > 0:
+		return StateNotApplicable
+	case step.FromVersion != "" && compareVersions(current, step.FromVersion) < 0:
+		return StateNotApplicable
+	case compareVersions(current, step.ToVersion) >= 0:
+		return StateIncluded
+	}
+	return StatePending
+}
+
+// Pending returns the steps that should run to bring a project from the
+// current template version up to target, in application order.
+func (r *Registry) Pending(current, target string, applied map[string]bool) []Step {
+	var pending []Step
+	for _, s := range r.steps {
+		if Classify(s, current, target, applied) == StatePending {
+			pending = append(pending, s)
+		}
+	}
+	return pending
+}
+
+// Revertible returns the applied steps whose ToVersion is newer than target,
+// in reverse application order (the order Down must run them).
+func (r *Registry) Revertible(target string, applied map[string]bool) []Step {
+	var steps []Step
+	for i := len(r.steps) - 1; i >= 0; i-- {
+		s := r.steps[i]
+		if applied[s.ID] && compareVersions(s.ToVersion, target) > 0 {
+			steps = append(steps, s)
+		}
+	}
+	return steps
+}
+
+// compareVersions orders template versions with semver precedence.
+// Unparseable versions (e.g. dev builds) sort after every release so that
+// nothing is considered pending for them.
+func compareVersions(a, b string) int {
+	av, aErr := update.ParseSemver(a)
+	bv, bErr := update.ParseSemver(b)
+	switch {
+	case aErr != nil && bErr != nil:
+		return 0
+	case aErr != nil:
+		return 1
+	case bErr != nil:
+		return -1
+	}
+	return av.Compare(bv)
+}
@@ -0,0 +1,116 @@
+package migration
+
+import (
+	"errors"
+	"testing"
+)
+
+func step(id, to string) Step {
+	return Step{ID: id, ToVersion: to, Operations: []Operation{RemovePath{Path: id}}}
+}
+
+func stepIDs(steps []Step) []string {
+	ids := make([]string, len(steps))
+	for i, s := range steps {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+func equalIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNewRegistry_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []Step
+		wantErr error
+	}{
+		{"empty id", []Step{step("", "1.0.0")}, ErrInvalidStep},
+		{"bad to version", []Step{step("a", "latest")}, ErrInvalidStep},
+		{"bad from version", []Step{{ID: "a", FromVersion: "x", ToVersion: "1.0.0", Operations: []Operation{RemovePath{}}}}, ErrInvalidStep},
+		{"no operations", []Step{{ID: "a", ToVersion: "1.0.0"}}, ErrInvalidStep},
+		{"duplicate", []Step{step("a", "1.0.0"), step("a", "2.0.0")}, ErrDuplicateStep},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRegistry(tt.steps...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewRegistry_Order(t *testing.T) {
+	r, err := NewRegistry(step("c", "2.10.0"), step("b", "2.2.0"), step("a", "2.2.0"), step("rc", "2.10.0-rc.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a", "b", "rc", "c"}
+	if got := stepIDs(r.Steps()); !equalIDs(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if _, ok := r.Lookup("rc"); !ok {
+		t.Error("Lookup(rc) not found")
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	if len(Default().Steps()) == 0 {
+		t.Error("default registry is empty")
+	}
+}
+
+func TestClassify(t *testing.T) {
+	s := Step{ID: "s", FromVersion: "2.0.0", ToVersion: "2.3.0"}
+	tests := []struct {
+		name    string
+		current string
+		target  string
+		applied bool
+		want    State
+	}{
+		{"pending", "2.1.0", "2.4.0", false, StatePending},
+		{"pending to exact target", "2.1.0", "2.3.0", false, StatePending},
+		{"no upper bound", "2.1.0", "", false, StatePending},
+		{"applied", "2.1.0", "2.4.0", true, StateApplied},
+		{"beyond target", "2.1.0", "2.2.0", false, StateNotApplicable},
+		{"before from version", "1.9.0", "2.4.0", false, StateNotApplicable},
+		{"already on version", "2.3.0", "2.4.0", false, StateIncluded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applied := map[string]bool{"s": tt.applied}
+			if got := Classify(s, tt.current, tt.target, applied); got != tt.want {
+				t.Errorf("Classify = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPendingAndRevertible(t *testing.T) {
+	r, err := NewRegistry(step("a", "2.1.0"), step("b", "2.2.0"), step("c", "2.3.0"), step("d", "2.4.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pending := stepIDs(r.Pending("2.1.0", "2.3.0", map[string]bool{"b": true}))
+	if want := []string{"c"}; !equalIDs(pending, want) {
+		t.Errorf("Pending = %v, want %v", pending, want)
+	}
+
+	applied := map[string]bool{"a": true, "b": true, "c": true}
+	revert := stepIDs(r.Revertible("2.1.0", applied))
+	if want := []string{"c", "b"}; !equalIDs(revert, want) {
+		t.Errorf("Revertible = %v, want %v", revert, want)
+	}
+}
@@ -0,0 +1,88 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/modu-ai/moai-adk/internal/defs"
+)
+
+// snapshot is a copy of the paths a step touches, taken before it runs.
+type snapshot struct {
+	projectRoot string
+	dir         string
+	// existed maps each project-relative path to whether it existed.
+	existed map[string]bool
+}
+
+// takeSnapshot copies every path ops may change from projectRoot into dir.
+func takeSnapshot(projectRoot, dir string, ops []Operation) (*snapshot, error) {
+	s := &snapshot{projectRoot: projectRoot, dir: dir, existed: make(map[string]bool)}
+	for _, op := range ops {
+		for _, rel := range op.Paths() {
+			if _, seen := s.existed[rel]; seen {
+				continue
+			}
+			src := projectPath(projectRoot, rel)
+			s.existed[rel] = pathExists(src)
+			if !s.existed[rel] {
+				continue
+			}
+			if err := copyTree(src, projectPath(dir, rel)); err != nil {
+				return nil, fmt.Errorf("snapshot %s: %w", rel, err)
+			}
+		}
+	}
+	return s, nil
+}
+
+// restore puts every snapshotted path back as it was and removes paths
+// that did not exist when the snapshot was taken.
+func (s *snapshot) restore() error {
+	var errs []error
+	for rel, existed := range s.existed {
+		dst := projectPath(s.projectRoot, rel)
+		if err := os.RemoveAll(dst); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if existed {
+			if err := copyTree(projectPath(s.dir, rel), dst); err != nil {
+				errs = append(errs, fmt.Errorf("restore %s: %w", rel, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// copyTree copies the file or directory src to dst, keeping file modes.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, defs.DirPerm)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), defs.DirPerm); err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
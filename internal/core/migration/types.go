@@ -0,0 +1,133 @@
+// Package migration provides a versioned registry of template migrations
+// for breaking configuration and layout changes between MoAI-ADK versions.
+//
+// Each Step declares the template version range it applies to and a list of
+// idempotent Operations (file moves, YAML key renames, settings.json edits).
+// The Migrator applies pending steps in version order during `moai update`
+// and records them in .moai/manifest.json so they can be listed or reverted.
+package migration
+
+import "errors"
+
+// Operation is a single idempotent transformation within a migration step.
+// File paths are relative to the project root and use forward slashes.
+type Operation interface {
+	// Describe returns a one-line human readable summary of the operation.
+	Describe() string
+
+	// Apply performs the transformation under projectRoot. With dryRun set it
+	// only reports whether a change would be made. Applying an operation whose
+	// effect is already present is a no-op that returns changed=false.
+	Apply(projectRoot string, dryRun bool) (changed bool, err error)
+
+	// Paths returns the project-relative files or directories Apply may
+	// change, so the Migrator can snapshot them before the step runs.
+	Paths() []string
+
+	// Inverse returns the operation that undoes this one, or nil when the
+	// operation cannot be reversed.
+	Inverse() Operation
+}
+
+// Step is a named migration between two template versions.
+type Step struct {
+	// ID uniquely identifies the step and is recorded in the manifest.
+	ID string
+
+	// FromVersion is the oldest template version this step understands.
+	// Projects older than FromVersion are skipped. Empty means any version.
+	FromVersion string
+
+	// ToVersion is the template version that introduced the change.
+	// The step is pending for projects whose template version is older.
+	ToVersion string
+
+	// Description explains the change for status and dry-run output.
+	Description string
+
+	// Operations are applied in order on Up and in reverse order on Down.
+	Operations []Operation
+}
+
+// Reversible reports whether every operation in the step has an inverse.
+func (s Step) Reversible() bool {
+	for _, op := range s.Operations {
+		if op.Inverse() == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// State classifies a step relative to a project.
+type State string
+
+const (
+	// StateApplied indicates the step is recorded in the manifest.
+	StateApplied State = "applied"
+
+	// StatePending indicates the step will run on the next `migrate up`.
+	StatePending State = "pending"
+
+	// StateIncluded indicates the project templates already postdate the
+	// step, so the change shipped with the templates themselves.
+	StateIncluded State = "included"
+
+	// StateNotApplicable indicates the project predates FromVersion or the
+	// step targets a version newer than the requested target.
+	StateNotApplicable State = "not_applicable"
+)
+
+// StepStatus pairs a step with its state for a given project.
+type StepStatus struct {
+	Step      Step
+	State     State
+	AppliedAt string
+}
+
+// OperationReport describes the outcome of one operation.
+type OperationReport struct {
+	Description string
+	Changed     bool
+}
+
+// StepReport describes the outcome of one step.
+type StepReport struct {
+	Step       Step
+	Operations []OperationReport
+}
+
+// Report summarizes a migrate up or down run.
+type Report struct {
+	DryRun bool
+	Steps  []StepReport
+}
+
+// Changed returns the number of operations that changed (or would change) files.
+func (r *Report) Changed() int {
+	n := 0
+	for _, s := range r.Steps {
+		for _, op := range s.Operations {
+			if op.Changed {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Sentinel errors for the migration package.
+var (
+	// ErrDuplicateStep indicates two registered steps share an ID.
+	ErrDuplicateStep = errors.New("migration: duplicate step ID")
+
+	// ErrInvalidStep indicates a step has a missing ID or invalid versions.
+	ErrInvalidStep = errors.New("migration: invalid step")
+
+	// ErrIrreversible indicates a step cannot be reverted.
+	ErrIrreversible = errors.New("migration: step is not reversible")
+
+	// ErrConflict indicates an operation found both its source and target
+	// present and refuses to overwrite either.
+	ErrConflict = errors.New("migration: conflicting source and target")
+)
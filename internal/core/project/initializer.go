@@ -403,6 +403,8 @@ func (i *projectInitializer) generateConfigsFallback(opts InitOptions, result *I
   version: %q
   template_version: %q
   update_check_frequency: daily
+  update_channel: stable
+  required_version: ""
 `, tmplCtx.Version, tmplCtx.Version)
 	if err := os.WriteFile(filepath.Join(sectionsDir, defs.SystemYAML), []byte(systemContent), defs.FilePerm); err != nil {
 		return fmt.Errorf("write system.yaml: %w", err)
@@ -21,6 +21,7 @@ const (
 	MemorySubdir   = "memory"
 	LogsSubdir     = "logs"
 	RankSubdir     = "rank"
+	IndexSubdir    = "index"
 )
 
 // Claude subdirectory segments (relative to ClaudeDir).
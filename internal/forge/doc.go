@@ -0,0 +1,10 @@
+// Package forge abstracts the code hosting provider behind a single
+// interface for issue parsing, pull/merge request operations, CI status and
+// issue closing.
+//
+// The request and result types are provider neutral: a pull request on
+// GitHub and a merge request on GitLab are both a ChangeRequest, and CI jobs
+// are Checks. Package github implements Forge with the gh CLI and package
+// gitlab with the GitLab REST API v4; git_strategy.provider in
+// git-strategy.yaml selects between them.
+package forge
@@ -0,0 +1,30 @@
+package forge
+
+import "errors"
+
+// Sentinel errors for the forge package.
+var (
+	// ErrUnknownProvider indicates git_strategy.provider names no supported forge.
+	ErrUnknownProvider = errors.New("forge: unknown provider")
+
+	// ErrNotAuthenticated indicates the forge rejected the credentials.
+	ErrNotAuthenticated = errors.New("forge: not authenticated")
+
+	// ErrUnsupportedMergeMethod indicates the forge cannot merge with the
+	// requested method.
+	ErrUnsupportedMergeMethod = errors.New("forge: unsupported merge method")
+
+	// ErrIssueNotFound indicates the specified issue does not exist.
+	ErrIssueNotFound = errors.New("forge: issue not found")
+
+	// ErrChangeRequestNotFound indicates the specified change request does
+	// not exist.
+	ErrChangeRequestNotFound = errors.New("forge: change request not found")
+
+	// ErrChangeRequestExists indicates a change request already exists for
+	// the branch.
+	ErrChangeRequestExists = errors.New("forge: change request already exists for branch")
+
+	// ErrMergeConflict indicates the change request has merge conflicts.
+	ErrMergeConflict = errors.New("forge: merge conflicts detected")
+)
@@ -0,0 +1,54 @@
+package forge
+
+import "context"
+
+// Supported providers for git_strategy.provider.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// Forge is a code hosting provider. It combines the change request, issue
+// parsing and issue closing operations so that PRReviewer and PRMerger work
+// unchanged on any provider; on GitLab a change request is a merge request.
+type Forge interface {
+	// Provider returns the provider name (ProviderGitHub or ProviderGitLab).
+	Provider() string
+
+	// IsAuthenticated checks that the provider accepts the credentials.
+	IsAuthenticated(ctx context.Context) error
+
+	// ParseIssue fetches an issue and its user comments.
+	ParseIssue(ctx context.Context, number int) (*Issue, error)
+
+	// PRCreate opens a change request and returns its number.
+	PRCreate(ctx context.Context, opts ChangeRequestOptions) (int, error)
+
+	// PRView retrieves a change request by number.
+	PRView(ctx context.Context, number int) (*ChangeRequest, error)
+
+	// PRMerge merges a change request using the specified method.
+	// If deleteBranch is true, the head branch is deleted after merge.
+	PRMerge(ctx context.Context, number int, method MergeMethod, deleteBranch bool) error
+
+	// PRChecks returns the CI/CD check status for a change request.
+	PRChecks(ctx context.Context, number int) (*CheckStatus, error)
+
+	// PRReview submits a review with the given decision and body.
+	PRReview(ctx context.Context, number int, decision ReviewDecision, body string) error
+
+	// Push pushes the current branch of the repository in dir to origin.
+	Push(ctx context.Context, dir string) error
+
+	// Close posts a comment, adds the resolved label, and closes the issue.
+	// Returns a partial CloseResult even on failure to indicate which steps succeeded.
+	Close(ctx context.Context, issueNumber int, comment string) (*CloseResult, error)
+}
+
+// ChangeRequestName returns what the provider calls a change request.
+func ChangeRequestName(f Forge) string {
+	if f.Provider() == ProviderGitLab {
+		return "MR"
+	}
+	return "PR"
+}
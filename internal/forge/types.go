@@ -0,0 +1,145 @@
+package forge
+
+import "time"
+
+// Issue is an issue with its discussion.
+type Issue struct {
+	Number   int
+	Title    string
+	Body     string
+	Labels   []Label
+	Author   Author
+	Comments []Comment
+}
+
+// Label is an issue label.
+type Label struct {
+	Name string
+}
+
+// Author is the account that wrote an issue or comment.
+type Author struct {
+	Login string
+}
+
+// Comment is a user comment on an issue.
+type Comment struct {
+	Body      string
+	Author    Author
+	CreatedAt time.Time
+}
+
+// LabelNames returns a string slice of label names for this issue.
+func (i *Issue) LabelNames() []string {
+	names := make([]string, len(i.Labels))
+	for idx, l := range i.Labels {
+		names[idx] = l.Name
+	}
+	return names
+}
+
+// ChangeRequestOptions holds parameters for opening a pull or merge request.
+type ChangeRequestOptions struct {
+	Title string
+	Body  string
+
+	// BaseBranch is the branch to merge into. Empty uses the default branch.
+	BaseBranch string
+
+	// HeadBranch is the branch to merge. Empty uses the current branch.
+	HeadBranch string
+
+	Labels      []string
+	IssueNumber int
+}
+
+// ChangeRequest describes an existing pull or merge request.
+type ChangeRequest struct {
+	Number int
+	Title  string
+
+	// State is OPEN, MERGED or CLOSED.
+	State string
+
+	// Mergeable is MERGEABLE, CONFLICTING or UNKNOWN.
+	Mergeable string
+
+	HeadBranch string
+	BaseBranch string
+	URL        string
+	CreatedAt  time.Time
+}
+
+// MergeMethod represents the Git merge strategy for a change request.
+type MergeMethod string
+
+const (
+	// MergeMethodMerge creates a merge commit.
+	MergeMethodMerge MergeMethod = "merge"
+
+	// MergeMethodSquash squashes all commits into one.
+	MergeMethodSquash MergeMethod = "squash"
+
+	// MergeMethodRebase rebases commits onto the base branch.
+	MergeMethodRebase MergeMethod = "rebase"
+)
+
+// ReviewDecision represents the outcome of a change request review.
+type ReviewDecision string
+
+const (
+	// ReviewApprove indicates the change passes all quality checks.
+	ReviewApprove ReviewDecision = "APPROVE"
+
+	// ReviewRequestChanges indicates the change requires changes.
+	ReviewRequestChanges ReviewDecision = "REQUEST_CHANGES"
+
+	// ReviewComment indicates the review has observations but no blocking issues.
+	ReviewComment ReviewDecision = "COMMENT"
+)
+
+// CheckConclusion represents the overall CI/CD check result.
+type CheckConclusion string
+
+const (
+	// CheckPass indicates all CI/CD checks passed.
+	CheckPass CheckConclusion = "pass"
+
+	// CheckFail indicates one or more CI/CD checks failed.
+	CheckFail CheckConclusion = "fail"
+
+	// CheckPending indicates CI/CD checks are still running.
+	CheckPending CheckConclusion = "pending"
+)
+
+// Check represents a single CI/CD job or status check.
+type Check struct {
+	Name string
+
+	// Status is queued, in_progress or completed.
+	Status string
+
+	// Conclusion is success, failure, cancelled or skipped once completed.
+	Conclusion string
+}
+
+// CheckStatus holds the aggregated CI/CD check results for a change request.
+type CheckStatus struct {
+	Overall CheckConclusion
+	Checks  []Check
+}
+
+// CloseResult holds the outcome of an issue closure operation.
+type CloseResult struct {
+	// IssueNumber is the issue that was processed.
+	IssueNumber int
+
+	// CommentPosted indicates whether the success comment was posted.
+	CommentPosted bool
+
+	// LabelAdded indicates whether the resolved label was added.
+	LabelAdded bool
+
+	// IssueClosed indicates whether the issue was closed.
+	IssueClosed bool
+}
@@ -0,0 +1,275 @@
+// Package changelog builds Keep a Changelog sections from commit history.
+// Commits are grouped by the type and scope of the active convention;
+// messages that do not follow a typed convention are listed as changes.
+package changelog
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/git/convention"
+)
+
+// Keep a Changelog sections, in rendering order.
+const (
+	SectionBreaking   = "Breaking Changes"
+	SectionAdded      = "Added"
+	SectionChanged    = "Changed"
+	SectionDeprecated = "Deprecated"
+	SectionRemoved    = "Removed"
+	SectionFixed      = "Fixed"
+	SectionSecurity   = "Security"
+)
+
+var sectionOrder = []string{
+	SectionBreaking, SectionAdded, SectionChanged, SectionDeprecated,
+	SectionRemoved, SectionFixed, SectionSecurity,
+}
+
+// typeSections maps commit types to sections. Types not listed here and
+// not in omittedTypes are reported as changes.
+var typeSections = map[string]string{
+	"feat":      SectionAdded,
+	"fix":       SectionFixed,
+	"perf":      SectionChanged,
+	"refactor":  SectionChanged,
+	"revert":    SectionChanged,
+	"deprecate": SectionDeprecated,
+	"remove":    SectionRemoved,
+	"security":  SectionSecurity,
+}
+
+// omittedTypes do not affect users and are left out unless breaking.
+var omittedTypes = map[string]bool{
+	"build": true, "chore": true, "ci": true, "docs": true, "style": true, "test": true,
+}
+
+var (
+	specIDPattern   = regexp.MustCompile(`\bSPEC-[A-Z0-9]+(?:-[A-Z0-9]+)*\b`)
+	issuePattern    = regexp.MustCompile(`(?:^|[\s(])#(\d+)\b`)
+	breakingPattern = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:\s*(.+)$`)
+
+	// trailingRefsPattern matches the "(#12)" suffix added by squash merges;
+	// the reference is rendered with the others instead.
+	trailingRefsPattern = regexp.MustCompile(`\s*\(#\d+(?:,\s*#\d+)*\)$`)
+)
+
+// Commit is a commit to describe.
+type Commit struct {
+	Hash    string
+	Subject string
+	Body    string
+}
+
+// Linker resolves links between SPECs and issues; github.SpecLinker
+// implements it.
+type Linker interface {
+	GetLinkedSpec(issueNum int) (string, error)
+	GetLinkedIssue(specID string) (int, error)
+}
+
+// Entry is one changelog line.
+type Entry struct {
+	Commit
+	Header convention.Header
+
+	// Conventional is false when the subject does not follow a typed convention.
+	Conventional bool
+
+	// BreakingNote is the text of a BREAKING CHANGE footer.
+	BreakingNote string
+
+	SpecIDs []string
+	Issues  []int
+}
+
+// Breaking reports whether the entry is a breaking change.
+func (e Entry) Breaking() bool {
+	return e.Header.Breaking || e.BreakingNote != ""
+}
+
+// Section returns the changelog section of the entry, or "" when omitted.
+func (e Entry) Section() string {
+	if !e.Conventional {
+		return SectionChanged
+	}
+	if s, ok := typeSections[e.Header.Type]; ok {
+		return s
+	}
+	if omittedTypes[e.Header.Type] && !e.Breaking() {
+		return ""
+	}
+	return SectionChanged
+}
+
+// Release is a changelog section for one version.
+type Release struct {
+	// Version is the released version; empty renders an Unreleased section.
+	Version string
+	Date    time.Time
+	Entries []Entry
+}
+
+// Build turns commits into a release. Commits generated by git (merges,
+// reverts, fixups) are skipped. linker may be nil.
+func Build(commits []Commit, conv *convention.Convention, linker Linker) *Release {
+	r := &Release{}
+	for _, c := range commits {
+		if convention.IsGenerated(c.Subject) {
+			continue
+		}
+		e := Entry{Commit: c}
+		e.Header, e.Conventional = convention.ParseHeader(c.Subject, conv)
+		e.Header.Description = trailingRefsPattern.ReplaceAllString(e.Header.Description, "")
+		if m := breakingPattern.FindStringSubmatch(c.Body); m != nil {
+			e.BreakingNote = strings.TrimSpace(m[1])
+		}
+		e.SpecIDs, e.Issues = references(c.Subject+"\n"+c.Body, linker)
+		r.Entries = append(r.Entries, e)
+	}
+	return r
+}
+
+// references finds SPEC IDs and issue numbers in text and completes each
+// side with the links known to linker.
+func references(text string, linker Linker) ([]string, []int) {
+	var specs []string
+	var issues []int
+	addSpec := func(id string) {
+		if id != "" && !slices.Contains(specs, id) {
+			specs = append(specs, id)
+		}
+	}
+	addIssue := func(n int) {
+		if n > 0 && !slices.Contains(issues, n) {
+			issues = append(issues, n)
+		}
+	}
+
+	for _, id := range specIDPattern.FindAllString(text, -1) {
+		addSpec(id)
+	}
+	for _, m := range issuePattern.FindAllStringSubmatch(text, -1) {
+		n, _ := strconv.Atoi(m[1])
+		addIssue(n)
+	}
+	if linker != nil {
+		for _, id := range slices.Clone(specs) {
+			if n, err := linker.GetLinkedIssue(id); err == nil {
+				addIssue(n)
+			}
+		}
+		for _, n := range slices.Clone(issues) {
+			if id, err := linker.GetLinkedSpec(n); err == nil {
+				addSpec(id)
+			}
+		}
+	}
+	return specs, issues
+}
+
+// Bump returns the semver bump the release calls for.
+func (r *Release) Bump() Bump {
+	bump := BumpNone
+	for _, e := range r.Entries {
+		switch {
+		case e.Breaking():
+			return BumpMajor
+		case e.Conventional && e.Header.Type == "feat":
+			bump = max(bump, BumpMinor)
+		case e.Section() != "":
+			bump = max(bump, BumpPatch)
+		}
+	}
+	return bump
+}
+
+// Sections groups the entries by changelog section. Within a section,
+// entries are ordered by scope, unscoped first, keeping commit order.
+func (r *Release) Sections() map[string][]Entry {
+	sections := make(map[string][]Entry)
+	for _, e := range r.Entries {
+		if e.Breaking() {
+			sections[SectionBreaking] = append(sections[SectionBreaking], e)
+		}
+		if s := e.Section(); s != "" {
+			sections[s] = append(sections[s], e)
+		}
+	}
+	for _, entries := range sections {
+		slices.SortStableFunc(entries, func(a, b Entry) int {
+			return cmp.Compare(a.Header.Scope, b.Header.Scope)
+		})
+	}
+	return sections
+}
+
+// Markdown renders the release as a Keep a Changelog section.
+func (r *Release) Markdown() string {
+	var b strings.Builder
+	b.WriteString(r.heading())
+	b.WriteString("\n")
+
+	sections := r.Sections()
+	if len(sections) == 0 {
+		b.WriteString("\nNo notable changes.\n")
+		return b.String()
+	}
+	for _, name := range sectionOrder {
+		entries := sections[name]
+		if len(entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", name)
+		for _, e := range entries {
+			b.WriteString(renderEntry(e, name == SectionBreaking))
+		}
+	}
+	return b.String()
+}
+
+func (r *Release) heading() string {
+	if r.Version == "" {
+		return "## [Unreleased]"
+	}
+	return fmt.Sprintf("## [%s] - %s", strings.TrimPrefix(r.Version, "v"), r.Date.Format(time.DateOnly))
+}
+
+func renderEntry(e Entry, breaking bool) string {
+	var b strings.Builder
+	b.WriteString("- ")
+	if e.Header.Scope != "" {
+		fmt.Fprintf(&b, "**%s:** ", e.Header.Scope)
+	}
+	b.WriteString(e.Header.Description)
+
+	var refs []string
+	refs = append(refs, e.SpecIDs...)
+	for _, n := range e.Issues {
+		refs = append(refs, fmt.Sprintf("#%d", n))
+	}
+	if e.Hash != "" {
+		refs = append(refs, shortHash(e.Hash))
+	}
+	if len(refs) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(refs, ", "))
+	}
+	b.WriteString("\n")
+
+	if breaking && e.BreakingNote != "" {
+		fmt.Fprintf(&b, "  %s\n", e.BreakingNote)
+	}
+	return b.String()
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,171 @@
+package changelog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/git/convention"
+)
+
+type stubLinker struct{}
+
+func (stubLinker) GetLinkedSpec(n int) (string, error) {
+	if n == 12 {
+		return "SPEC-AUTH-001", nil
+	}
+	return "", errors.New("not linked")
+}
+
+func (stubLinker) GetLinkedIssue(specID string) (int, error) {
+	if specID == "SPEC-UI-002" {
+		return 40, nil
+	}
+	return 0, errors.New("not linked")
+}
+
+func conventional(t *testing.T) *convention.Convention {
+	t.Helper()
+	conv, err := convention.ParseBuiltin("conventional-commits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conv
+}
+
+var testCommits = []Commit{
+	{Hash: "aaaaaaaaaa", Subject: "feat(auth): add JWT validation (#12)"},
+	{Hash: "bbbbbbbbbb", Subject: "fix: handle empty config"},
+	{Hash: "cccccccccc", Subject: "docs: update README"},
+	{Hash: "dddddddddd", Subject: "feat: add dark mode", Body: "Implements SPEC-UI-002."},
+	{Hash: "eeeeeeeeee", Subject: "Merge branch 'main' into dev"},
+	{Hash: "ffffffffff", Subject: "feat(api): add v2 endpoints"},
+}
+
+func TestBuild_Markdown(t *testing.T) {
+	r := Build(testCommits, conventional(t), stubLinker{})
+	r.Version, r.Date = "v1.3.0", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	want := `## [1.3.0] - 2026-10-18
+
+### Added
+
+- add dark mode (SPEC-UI-002, #40, ddddddd)
+- **api:** add v2 endpoints (fffffff)
+- **auth:** add JWT validation (SPEC-AUTH-001, #12, aaaaaaa)
+
+### Fixed
+
+- handle empty config (bbbbbbb)
+`
+	if got := r.Markdown(); got != want {
+		t.Errorf("Markdown() =\n%s\nwant:\n%s", got, want)
+	}
+	if r.Bump() != BumpMinor {
+		t.Errorf("Bump() = %s, want minor", r.Bump())
+	}
+}
+
+func TestBuild_Breaking(t *testing.T) {
+	r := Build([]Commit{
+		{Hash: "1111111111", Subject: "refactor(api)!: rename endpoints"},
+		{Hash: "2222222222", Subject: "chore: drop node 18", Body: "BREAKING CHANGE: Node 20 is required."},
+		{Hash: "3333333333", Subject: "fix: typo"},
+	}, conventional(t), nil)
+
+	md := r.Markdown()
+	for _, want := range []string{
+		"## [Unreleased]",
+		"### Breaking Changes\n\n- drop node 18 (2222222)\n  Node 20 is required.\n- **api:** rename endpoints (1111111)\n",
+		"### Changed\n\n- drop node 18 (2222222)\n- **api:** rename endpoints (1111111)\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, md)
+		}
+	}
+	if r.Bump() != BumpMajor {
+		t.Errorf("Bump() = %s, want major", r.Bump())
+	}
+}
+
+func TestBuild_NonConventional(t *testing.T) {
+	conv, err := convention.Parse(convention.ConventionConfig{Name: "ticket", Pattern: `^[A-Z]+-\d+ .+`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := Build([]Commit{{Hash: "abcdef12", Subject: "APP-7 Speed up search"}}, conv, nil)
+
+	if md := r.Markdown(); !strings.Contains(md, "### Changed\n\n- APP-7 Speed up search (abcdef1)\n") {
+		t.Errorf("Markdown() =\n%s", md)
+	}
+	if r.Bump() != BumpPatch {
+		t.Errorf("Bump() = %s, want patch", r.Bump())
+	}
+
+	if md := Build(nil, conv, nil).Markdown(); !strings.Contains(md, "No notable changes.") {
+		t.Errorf("empty Markdown() =\n%s", md)
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	tests := []struct {
+		current string
+		bump    Bump
+		want    string
+	}{
+		{"v1.2.3", BumpPatch, "v1.2.4"},
+		{"v1.2.3", BumpMinor, "v1.3.0"},
+		{"1.2.3", BumpMajor, "2.0.0"},
+		{"v0.4.1", BumpMajor, "v0.5.0"},
+		{"v1.3.0-rc.1", BumpPatch, "v1.3.1"},
+		{"", BumpMinor, "0.1.0"},
+	}
+	for _, tt := range tests {
+		got, err := NextVersion(tt.current, tt.bump)
+		if err != nil || got != tt.want {
+			t.Errorf("NextVersion(%q, %s) = %q, %v, want %q", tt.current, tt.bump, got, err, tt.want)
+		}
+	}
+	if _, err := NextVersion("v1.0.0", BumpNone); err == nil {
+		t.Error("NextVersion(BumpNone) should fail")
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	conv := conventional(t)
+
+	unreleased := Build([]Commit{{Hash: "1111111", Subject: "feat: first"}}, conv, nil)
+	if err := Prepend(path, unreleased); err != nil {
+		t.Fatal(err)
+	}
+	release := Build([]Commit{{Hash: "2222222", Subject: "fix: second"}}, conv, nil)
+	release.Version, release.Date = "v0.1.0", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	if err := Prepend(path, release); err != nil {
+		t.Fatal(err)
+	}
+	next := Build([]Commit{{Hash: "3333333", Subject: "feat: third"}}, conv, nil)
+	next.Version, next.Date = "v0.2.0", time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)
+	if err := Prepend(path, next); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	if !strings.HasPrefix(got, fileHeader+"\n## [0.2.0] - 2026-02-03\n") {
+		t.Errorf("changelog =\n%s", got)
+	}
+	if strings.Contains(got, "Unreleased") || strings.Index(got, "[0.2.0]") > strings.Index(got, "[0.1.0]") {
+		t.Errorf("changelog sections out of order:\n%s", got)
+	}
+
+	if err := Prepend(path, next); !errors.Is(err, ErrVersionExists) {
+		t.Errorf("Prepend(duplicate) error = %v, want ErrVersionExists", err)
+	}
+}
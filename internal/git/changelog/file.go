@@ -0,0 +1,71 @@
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrVersionExists is returned when the changelog already has a section for
+// the release version.
+var ErrVersionExists = errors.New("changelog: version already in changelog")
+
+// fileHeader starts a new CHANGELOG.md.
+const fileHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+// Prepend writes the release above the newest section of the changelog at
+// path, creating the file when needed. An existing Unreleased section is
+// replaced, since the release now covers it.
+func Prepend(path string, r *Release) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("changelog: read %s: %w", path, err)
+	}
+	content := string(data)
+	if content == "" {
+		content = fileHeader
+	}
+
+	if r.Version != "" {
+		heading := fmt.Sprintf("## [%s]", strings.TrimPrefix(r.Version, "v"))
+		if strings.Contains(content, "\n"+heading) {
+			return fmt.Errorf("%w: %s", ErrVersionExists, r.Version)
+		}
+	}
+	content = removeSection(content, "## [Unreleased]")
+
+	head, rest := content, ""
+	if i := strings.Index(content, "\n## "); i >= 0 {
+		head, rest = content[:i+1], content[i+1:]
+	}
+	out := strings.TrimRight(head, "\n") + "\n\n" + r.Markdown()
+	if rest != "" {
+		out += "\n" + rest
+	}
+	if err := os.WriteFile(path, []byte(out), 0o644); err != nil {
+		return fmt.Errorf("changelog: write %s: %w", path, err)
+	}
+	return nil
+}
+
+// removeSection drops the section starting with heading up to the next
+// second-level heading.
+func removeSection(content, heading string) string {
+	start := strings.Index(content, "\n"+heading)
+	if start < 0 {
+		return content
+	}
+	start++
+	end := len(content)
+	if i := strings.Index(content[start+len(heading):], "\n## "); i >= 0 {
+		end = start + len(heading) + i + 1
+	}
+	return content[:start] + content[end:]
+}
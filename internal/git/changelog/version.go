@@ -0,0 +1,67 @@
+package changelog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/modu-ai/moai-adk/internal/update"
+)
+
+// Bump is a semantic version increment.
+type Bump int
+
+// Bumps, from smallest to largest.
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+// String returns the bump name.
+func (b Bump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// NextVersion applies bump to current (e.g. "v1.2.3"), keeping its "v"
+// prefix. An empty current starts from 0.0.0. Before 1.0.0 a breaking
+// change bumps the minor version, as the semver spec leaves 0.x unstable.
+func NextVersion(current string, bump Bump) (string, error) {
+	if bump == BumpNone {
+		return "", fmt.Errorf("changelog: no releasable changes")
+	}
+	prefix := ""
+	v := update.Semver{}
+	if current != "" {
+		parsed, err := update.ParseSemver(current)
+		if err != nil {
+			return "", fmt.Errorf("changelog: %w", err)
+		}
+		v = parsed
+		if strings.HasPrefix(current, "v") {
+			prefix = "v"
+		}
+	}
+
+	if bump == BumpMajor && v.Major == 0 {
+		bump = BumpMinor
+	}
+	switch bump {
+	case BumpMajor:
+		v = update.Semver{Major: v.Major + 1}
+	case BumpMinor:
+		v = update.Semver{Major: v.Major, Minor: v.Minor + 1}
+	default:
+		v = update.Semver{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+	return prefix + v.String(), nil
+}
@@ -0,0 +1,204 @@
+package convention
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// CommitlintConventionName is the name of conventions imported from commitlint.
+const CommitlintConventionName = "commitlint"
+
+// CommitlintFiles lists the commitlint JSON configuration files, in lookup
+// order. package.json is consulted last for a "commitlint" key.
+var CommitlintFiles = []string{".commitlintrc.json", ".commitlintrc", "commitlint.config.json"}
+
+// anyTypePattern accepts any "type(scope)!: subject" header; commitlint
+// configs without type-enum accept every type.
+const anyTypePattern = `^[\w-]+(\(.+\))?!?: .+`
+
+// commitlintPresets holds the rules of the shareable configs moai knows,
+// as published by commitlint.
+var commitlintPresets = map[string]map[string]any{
+	"@commitlint/config-conventional": {
+		"body-leading-blank":     []any{1, "always"},
+		"body-max-line-length":   []any{2, "always", 100},
+		"footer-leading-blank":   []any{1, "always"},
+		"footer-max-line-length": []any{2, "always", 100},
+		"header-max-length":      []any{2, "always", 100},
+		"subject-case":           []any{2, "never", []any{"sentence-case", "start-case", "pascal-case", "upper-case"}},
+		"subject-empty":          []any{2, "never"},
+		"subject-full-stop":      []any{2, "never", "."},
+		"type-case":              []any{2, "always", "lower-case"},
+		"type-empty":             []any{2, "never"},
+		"type-enum": []any{2, "always", []any{
+			"build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test",
+		}},
+	},
+	"@commitlint/config-angular": {
+		"body-leading-blank":   []any{1, "always"},
+		"footer-leading-blank": []any{1, "always"},
+		"header-max-length":    []any{2, "always", 100},
+		"scope-case":           []any{2, "always", "lower-case"},
+		"subject-case":         []any{2, "never", []any{"sentence-case", "start-case", "pascal-case", "upper-case"}},
+		"subject-empty":        []any{2, "never"},
+		"subject-full-stop":    []any{2, "never", "."},
+		"type-case":            []any{2, "always", "lower-case"},
+		"type-empty":           []any{2, "never"},
+		"type-enum": []any{2, "always", []any{
+			"build", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test",
+		}},
+	},
+}
+
+// CommitlintImport is the result of importing a commitlint configuration.
+type CommitlintImport struct {
+	Config ConventionConfig
+
+	// Unsupported lists rules and shareable configs that were skipped.
+	Unsupported []string
+}
+
+type commitlintConfig struct {
+	Extends stringOrList   `json:"extends"`
+	Rules   map[string]any `json:"rules"`
+}
+
+// stringOrList decodes a JSON string or list of strings.
+type stringOrList []string
+
+func (s *stringOrList) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*s = []string{one}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("extends must be a string or list of strings")
+	}
+	*s = list
+	return nil
+}
+
+// ImportCommitlint converts a commitlint JSON configuration into a
+// convention. Rules of known shareable configs are applied first and
+// overridden by the rules of the file. Rules moai cannot enforce are
+// skipped and reported.
+func ImportCommitlint(data []byte) (*CommitlintImport, error) {
+	var cfg commitlintConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("import commitlint config: %w", err)
+	}
+
+	result := &CommitlintImport{}
+	rules := make(map[string]any)
+	for _, name := range cfg.Extends {
+		preset, ok := commitlintPresets[commitlintPresetName(name)]
+		if !ok {
+			result.Unsupported = append(result.Unsupported, "extends "+name)
+			continue
+		}
+		maps.Copy(rules, preset)
+	}
+	maps.Copy(rules, cfg.Rules)
+
+	var unsupported []string
+	for name := range rules {
+		if !IsSupportedRule(name) {
+			unsupported = append(unsupported, name)
+			delete(rules, name)
+		}
+	}
+	slices.Sort(unsupported)
+	result.Unsupported = append(result.Unsupported, unsupported...)
+
+	compiled, err := CompileRules(rules)
+	if err != nil {
+		return nil, fmt.Errorf("import commitlint config: %w", err)
+	}
+
+	conv := ConventionConfig{Name: CommitlintConventionName, Rules: rules}
+	for _, r := range compiled {
+		if r.Never {
+			continue
+		}
+		switch r.Name {
+		case "type-enum":
+			conv.Types = r.Values
+		case "scope-enum":
+			conv.Scopes = r.Values
+		case "header-max-length":
+			conv.MaxLength = r.Limit
+		}
+	}
+	if len(conv.Types) == 0 {
+		conv.Pattern = anyTypePattern
+	}
+	result.Config = conv
+	return result, nil
+}
+
+// commitlintPresetName resolves commitlint's shorthand for shareable
+// configs ("conventional" and "@commitlint/conventional").
+func commitlintPresetName(name string) string {
+	if _, ok := commitlintPresets[name]; ok {
+		return name
+	}
+	for preset := range commitlintPresets {
+		short := preset[len("@commitlint/config-"):]
+		if name == short || name == "@commitlint/"+short || name == "commitlint-config-"+short {
+			return preset
+		}
+	}
+	return name
+}
+
+// FindCommitlintConfig returns the path of the commitlint JSON configuration
+// in dir, or "" when there is none.
+func FindCommitlintConfig(dir string) string {
+	for _, name := range CommitlintFiles {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	if commitlintFromPackageJSON(filepath.Join(dir, "package.json")) != nil {
+		return filepath.Join(dir, "package.json")
+	}
+	return ""
+}
+
+// ImportCommitlintFile imports the commitlint configuration at path, which
+// may also be a package.json with a "commitlint" key.
+func ImportCommitlintFile(path string) (*CommitlintImport, error) {
+	if filepath.Base(path) == "package.json" {
+		data := commitlintFromPackageJSON(path)
+		if data == nil {
+			return nil, fmt.Errorf("import commitlint config: %s has no commitlint key", path)
+		}
+		return ImportCommitlint(data)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("import commitlint config: %w", err)
+	}
+	return ImportCommitlint(data)
+}
+
+func commitlintFromPackageJSON(path string) json.RawMessage {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Commitlint json.RawMessage `json:"commitlint"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return nil
+	}
+	return pkg.Commitlint
+}
@@ -0,0 +1,167 @@
+package convention
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const teamCommitlint = `{
+  "extends": ["@commitlint/config-conventional"],
+  "rules": {
+    "type-enum": [2, "always", ["feat", "fix", "docs"]],
+    "scope-enum": [1, "always", ["api", "cli"]],
+    "header-max-length": [2, "always", 72],
+    "subject-case": [0],
+    "signed-off-by": [2, "always", "Signed-off-by:"]
+  }
+}`
+
+func TestImportCommitlint(t *testing.T) {
+	imported, err := ImportCommitlint([]byte(teamCommitlint))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := imported.Config
+	if cfg.Name != CommitlintConventionName || !slices.Equal(cfg.Types, []string{"feat", "fix", "docs"}) ||
+		!slices.Equal(cfg.Scopes, []string{"api", "cli"}) || cfg.MaxLength != 72 {
+		t.Errorf("Config = %+v", cfg)
+	}
+	if !slices.Equal(imported.Unsupported, []string{"signed-off-by"}) {
+		t.Errorf("Unsupported = %v", imported.Unsupported)
+	}
+
+	conv, err := Parse(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Preset rules apply unless overridden; subject-case is disabled.
+	if !conv.hasRule("subject-full-stop") || conv.hasRule("subject-case") {
+		t.Errorf("Rules = %+v", conv.Rules)
+	}
+	for msg, valid := range map[string]bool{
+		"feat(api): Add v2":                true,
+		"feat(web): add v2":                true,
+		"chore: bump deps":                 false,
+		"fix: handle nil.":                 false,
+		"docs: " + strings.Repeat("x", 70): false,
+	} {
+		if r := Validate(msg, conv); r.Valid != valid {
+			t.Errorf("Validate(%q).Valid = %v, want %v (%+v)", msg, r.Valid, valid, r.Violations)
+		}
+	}
+}
+
+func TestImportCommitlint_NoTypeEnum(t *testing.T) {
+	imported, err := ImportCommitlint([]byte(`{"extends": "@acme/commitlint-config", "rules": {"body-max-line-length": [2, "always", 80]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Config.Pattern != anyTypePattern || !slices.Equal(imported.Unsupported, []string{"extends @acme/commitlint-config"}) {
+		t.Errorf("import = %+v", imported)
+	}
+
+	if _, err := ImportCommitlint([]byte(`{"rules": {"type-enum": [2, "always", 3]}}`)); err == nil {
+		t.Error("ImportCommitlint should reject malformed rules")
+	}
+	if _, err := ImportCommitlint([]byte(`not json`)); err == nil {
+		t.Error("ImportCommitlint should reject invalid JSON")
+	}
+}
+
+func TestFindCommitlintConfig(t *testing.T) {
+	dir := t.TempDir()
+	if got := FindCommitlintConfig(dir); got != "" {
+		t.Errorf("FindCommitlintConfig(empty) = %q", got)
+	}
+
+	pkg := filepath.Join(dir, "package.json")
+	writeFile(t, pkg, `{"name": "app", "commitlint": {"extends": ["conventional"]}}`)
+	if got := FindCommitlintConfig(dir); got != pkg {
+		t.Errorf("FindCommitlintConfig() = %q, want package.json", got)
+	}
+	imported, err := ImportCommitlintFile(pkg)
+	if err != nil || len(imported.Config.Types) != 11 {
+		t.Errorf("ImportCommitlintFile(package.json) = %+v, %v", imported, err)
+	}
+
+	rc := filepath.Join(dir, ".commitlintrc.json")
+	writeFile(t, rc, teamCommitlint)
+	if got := FindCommitlintConfig(dir); got != rc {
+		t.Errorf("FindCommitlintConfig() = %q, want %q", got, rc)
+	}
+}
+
+func TestDetect_PrefersCommitlint(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "feat: add login"},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "chore: bump deps"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	writeFile(t, filepath.Join(dir, "commitlint.config.json"), teamCommitlint)
+
+	result, err := Detect(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Convention.Name != CommitlintConventionName || result.MatchCount != 1 || result.SampleSize != 2 {
+		t.Errorf("Detect() = %+v", result)
+	}
+
+	m := NewManager(dir)
+	if err := m.LoadConvention(CommitlintConventionName); err != nil || m.Convention().MaxLength != 72 {
+		t.Errorf("LoadConvention(commitlint) = %v", err)
+	}
+}
+
+func TestManager_LoadCustom(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "commitlint.config.json"), teamCommitlint)
+
+	m := NewManager(dir)
+	err := m.LoadCustom(ConventionConfig{
+		Name:  "team",
+		Types: []string{"feat", "fix", "chore"},
+		Rules: map[string]any{"trailer-exists": []any{"error", "always", "Signed-off-by:"}},
+	}, "commitlint.config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conv := m.Convention()
+	if conv.Name != "team" || conv.MaxLength != 72 || !conv.hasRule("trailer-exists") || !conv.hasRule("subject-full-stop") {
+		t.Errorf("Convention = %+v", conv)
+	}
+	// The custom types replace the imported type-enum.
+	if r := m.ValidateMessage("chore: bump deps\n\nSigned-off-by: A <a@b.c>"); !r.Valid {
+		t.Errorf("ValidateMessage() violations = %+v", r.Violations)
+	}
+	if r := m.ValidateMessage("fix: handle nil"); r.Valid {
+		t.Error("ValidateMessage() should require the custom trailer")
+	}
+
+	if err := m.LoadCustom(ConventionConfig{Name: "x"}, "missing.json"); err == nil {
+		t.Error("LoadCustom should fail for a missing import")
+	}
+	if err := NewManager(dir).LoadCustom(ConventionConfig{Name: "plain", Types: []string{"feat"}}, ""); err != nil {
+		t.Errorf("LoadCustom without import: %v", err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
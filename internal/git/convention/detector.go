@@ -9,12 +9,22 @@ import (
 // Detect analyzes recent commits in the repository and returns the best
 // matching built-in convention. sampleSize controls how many recent commits
 // to analyze. repoPath is the git repository root.
+//
+// A commitlint configuration in the repository states the convention the
+// team enforces, so it is returned whatever the history matches.
 func Detect(repoPath string, sampleSize int) (*DetectionResult, error) {
 	if sampleSize <= 0 {
 		sampleSize = 100
 	}
 
 	messages, err := getRecentCommitMessages(repoPath, sampleSize)
+
+	if path := FindCommitlintConfig(repoPath); path != "" {
+		if result, ok := detectCommitlint(path, messages); ok {
+			return result, nil
+		}
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("detect convention: %w", err)
 	}
@@ -58,6 +68,29 @@ func Detect(repoPath string, sampleSize int) (*DetectionResult, error) {
 	return bestResult, nil
 }
 
+// detectCommitlint imports the commitlint configuration at path and scores
+// messages against it. Unreadable configurations are ignored.
+func detectCommitlint(path string, messages []string) (*DetectionResult, bool) {
+	imported, err := ImportCommitlintFile(path)
+	if err != nil {
+		return nil, false
+	}
+	conv, err := Parse(imported.Config)
+	if err != nil {
+		return nil, false
+	}
+	result := &DetectionResult{Convention: conv, SampleSize: len(messages)}
+	for _, msg := range messages {
+		if Validate(msg, conv).Valid {
+			result.MatchCount++
+		}
+	}
+	if len(messages) > 0 {
+		result.Confidence = float64(result.MatchCount) / float64(len(messages))
+	}
+	return result, true
+}
+
 // Score calculates how well a set of messages matches a convention (0.0-1.0).
 func Score(messages []string, conv *Convention) float64 {
 	if len(messages) == 0 || conv == nil {
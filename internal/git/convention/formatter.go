@@ -6,22 +6,31 @@ import (
 )
 
 // FormatError creates a user-friendly error message from a ValidationResult.
-// Returns an empty string when the result is valid.
+// Returns an empty string when the result has no violations; valid results
+// with warnings are reported as warnings.
 func FormatError(result ValidationResult, conv *Convention) string {
-	if result.Valid {
+	if len(result.Violations) == 0 {
 		return ""
 	}
 
 	var b strings.Builder
-	fmt.Fprintf(&b, "Commit message violates %s convention:\n", conv.Name)
+	if result.Valid {
+		fmt.Fprintf(&b, "Commit message has warnings under %s convention:\n", conv.Name)
+	} else {
+		fmt.Fprintf(&b, "Commit message violates %s convention:\n", conv.Name)
+	}
 	fmt.Fprintf(&b, "  Message: %q\n", result.Message)
 	fmt.Fprintf(&b, "\n")
 
 	for _, v := range result.Violations {
-		fmt.Fprintf(&b, "  %s %s\n", violationIcon(v.Type), FormatViolation(v))
+		icon := violationIcon(v.Type)
+		if v.Warning {
+			icon = "[WARN]"
+		}
+		fmt.Fprintf(&b, "  %s %s\n", icon, FormatViolation(v))
 	}
 
-	if len(conv.Examples) > 0 {
+	if len(conv.Examples) > 0 && !result.Valid {
 		fmt.Fprintf(&b, "\n  Examples of valid messages:\n")
 		for _, ex := range conv.Examples {
 			fmt.Fprintf(&b, "    - %s\n", ex)
@@ -48,6 +57,11 @@ func FormatViolation(v Violation) string {
 		return fmt.Sprintf("Header too long (%s, %s)", v.Actual, v.Expected)
 	case ViolationRequired:
 		return fmt.Sprintf("Missing required field: %s", v.Field)
+	case ViolationRule:
+		if v.Actual == "" {
+			return fmt.Sprintf("%s (expected: %s)", v.Field, v.Expected)
+		}
+		return fmt.Sprintf("%s (expected: %s, got: %q)", v.Field, v.Expected, v.Actual)
 	default:
 		return fmt.Sprintf("%s: expected %s, got %s", v.Type, v.Expected, v.Actual)
 	}
@@ -97,6 +111,8 @@ func violationIcon(vt ViolationType) string {
 		return "[LENGTH]"
 	case ViolationRequired:
 		return "[REQUIRED]"
+	case ViolationRule:
+		return "[RULE]"
 	default:
 		return "[ERROR]"
 	}
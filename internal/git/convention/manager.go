@@ -1,6 +1,10 @@
 package convention
 
-import "fmt"
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+)
 
 // Manager coordinates convention loading, detection, and validation.
 type Manager struct {
@@ -15,8 +19,21 @@ func NewManager(repoPath string) *Manager {
 
 // LoadConvention loads a convention by name (built-in) or from config.
 // If name is "auto", it auto-detects from the repository history and
-// falls back to conventional-commits on failure.
+// falls back to conventional-commits on failure. "commitlint" imports the
+// commitlint configuration of the repository.
 func (m *Manager) LoadConvention(name string) error {
+	if name == CommitlintConventionName {
+		path := FindCommitlintConfig(m.repoPath)
+		if path == "" {
+			return fmt.Errorf("load convention %q: no commitlint config in %s", name, m.repoPath)
+		}
+		imported, err := ImportCommitlintFile(path)
+		if err != nil {
+			return fmt.Errorf("load convention %q: %w", name, err)
+		}
+		return m.LoadFromConfig(imported.Config)
+	}
+
 	if name == "auto" {
 		result, err := Detect(m.repoPath, 100)
 		if err != nil {
@@ -52,6 +69,48 @@ func (m *Manager) LoadFromConfig(cfg ConventionConfig) error {
 	return nil
 }
 
+// LoadCustom loads a user-defined convention. When importPath is set, the
+// commitlint configuration it names (relative to the repository) is the
+// base and the fields set in cfg override it; rules are merged by name.
+func (m *Manager) LoadCustom(cfg ConventionConfig, importPath string) error {
+	if importPath == "" {
+		return m.LoadFromConfig(cfg)
+	}
+	if !filepath.IsAbs(importPath) {
+		importPath = filepath.Join(m.repoPath, importPath)
+	}
+	imported, err := ImportCommitlintFile(importPath)
+	if err != nil {
+		return fmt.Errorf("load custom convention: %w", err)
+	}
+
+	base := imported.Config
+	if cfg.Name != "" {
+		base.Name = cfg.Name
+	}
+	if cfg.Pattern != "" {
+		base.Pattern = cfg.Pattern
+	}
+	// Lists set in cfg replace the imported rules they correspond to.
+	if len(cfg.Types) > 0 {
+		base.Types, base.Pattern = cfg.Types, cfg.Pattern
+		delete(base.Rules, "type-enum")
+	}
+	if len(cfg.Scopes) > 0 {
+		base.Scopes = cfg.Scopes
+		delete(base.Rules, "scope-enum")
+	}
+	if cfg.MaxLength > 0 {
+		base.MaxLength = cfg.MaxLength
+		delete(base.Rules, "header-max-length")
+	}
+	if len(cfg.Examples) > 0 {
+		base.Examples = cfg.Examples
+	}
+	maps.Copy(base.Rules, cfg.Rules)
+	return m.LoadFromConfig(base)
+}
+
 // ValidateMessage validates a single commit message against the loaded convention.
 func (m *Manager) ValidateMessage(message string) ValidationResult {
 	return Validate(message, m.convention)
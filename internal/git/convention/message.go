@@ -0,0 +1,98 @@
+package convention
+
+import "strings"
+
+// scissorsLine marks where git's verbose commit template starts; everything
+// below it is discarded by `git commit --cleanup=scissors`.
+const scissorsLine = "# ------------------------ >8 ------------------------"
+
+// generatedPrefixes are headers written by git itself, which are exempt
+// from convention checks.
+var generatedPrefixes = []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "}
+
+// CleanMessage strips the comment lines and verbose diff that git removes
+// from a commit message file before recording the commit.
+func CleanMessage(raw string) string {
+	if i := strings.Index(raw, scissorsLine); i >= 0 {
+		raw = raw[:i]
+	}
+	var lines []string
+	for line := range strings.SplitSeq(raw, "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// IsGenerated reports whether message was generated by git (merge, revert,
+// fixup and squash commits).
+func IsGenerated(message string) bool {
+	for _, prefix := range generatedPrefixes {
+		if strings.HasPrefix(message, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fix returns message with its header replaced by the suggested fix of a
+// pattern violation, keeping the body. ok is false when there is nothing to
+// suggest or the suggestion still violates conv.
+func Fix(message string, conv *Convention) (fixed string, ok bool) {
+	result := Validate(message, conv)
+	if result.Valid {
+		return message, true
+	}
+	var suggestion string
+	for _, v := range result.Violations {
+		if v.Suggestion != "" {
+			suggestion = v.Suggestion
+			break
+		}
+	}
+	if suggestion == "" {
+		return message, false
+	}
+
+	fixed = suggestion
+	if _, body, found := strings.Cut(message, "\n"); found {
+		fixed += "\n" + body
+	}
+	if !Validate(fixed, conv).Valid {
+		return message, false
+	}
+	return fixed, true
+}
+
+// Header is a commit header split into its convention parts, e.g.
+// "feat(auth)!: drop sessions" has type feat, scope auth and is breaking.
+type Header struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+// ParseHeader splits header into type, scope and description. ok is false
+// when conv defines no commit types or the header does not match it; the
+// whole header is then the description.
+func ParseHeader(header string, conv *Convention) (h Header, ok bool) {
+	header = strings.TrimSpace(header)
+	h.Description = header
+	if conv == nil || len(conv.Types) == 0 || !conv.Pattern.MatchString(header) {
+		return h, false
+	}
+	prefix, desc, found := strings.Cut(header, ":")
+	typ := extractType(header)
+	if !found || typ == "" {
+		return h, false
+	}
+	return Header{
+		Type:        typ,
+		Scope:       extractScope(prefix),
+		Breaking:    strings.HasSuffix(prefix, "!"),
+		Description: strings.TrimSpace(desc),
+	}, true
+}
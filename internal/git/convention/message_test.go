@@ -0,0 +1,78 @@
+package convention
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCleanMessage(t *testing.T) {
+	raw := "feat: add login  \n\nBody line\n# Please enter the commit message\n#\n" +
+		scissorsLine + "\ndiff --git a/x b/x\n"
+	if got, want := CleanMessage(raw), "feat: add login\n\nBody line"; got != want {
+		t.Errorf("CleanMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestIsGenerated(t *testing.T) {
+	tests := []struct {
+		message string
+		want    bool
+	}{
+		{"Merge branch 'main' into feature", true},
+		{"Revert \"feat: add login\"", true},
+		{"fixup! feat: add login", true},
+		{"squash! feat: add login", true},
+		{"feat: merge settings", false},
+		{"Mergeable state cleanup", false},
+	}
+	for _, tt := range tests {
+		if got := IsGenerated(tt.message); got != tt.want {
+			t.Errorf("IsGenerated(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestFix(t *testing.T) {
+	conv, err := ParseBuiltin("conventional-commits")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixed, ok := Fix("Fix crash on empty config\n\nDetails here.", conv)
+	if !ok || fixed != "fix: fix crash on empty config\n\nDetails here." {
+		t.Errorf("Fix() = %q, %v", fixed, ok)
+	}
+
+	if fixed, ok := Fix("feat: already valid", conv); !ok || fixed != "feat: already valid" {
+		t.Errorf("Fix(valid) = %q, %v", fixed, ok)
+	}
+
+	// The suggestion cannot shorten an over-long header.
+	long := "Add " + strings.Repeat("x", conv.MaxLength)
+	if _, ok := Fix(long, conv); ok {
+		t.Error("Fix() should fail when the suggestion is still invalid")
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	conv, err := ParseBuiltin("conventional-commits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		header string
+		want   Header
+		wantOK bool
+	}{
+		{"feat(auth): add JWT", Header{Type: "feat", Scope: "auth", Description: "add JWT"}, true},
+		{"fix!: drop legacy flag", Header{Type: "fix", Breaking: true, Description: "drop legacy flag"}, true},
+		{"refactor(api)!: rename: endpoints", Header{Type: "refactor", Scope: "api", Breaking: true, Description: "rename: endpoints"}, true},
+		{"Update README", Header{Description: "Update README"}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseHeader(tt.header, conv)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseHeader(%q) = %+v, %v, want %+v, %v", tt.header, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
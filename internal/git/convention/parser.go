@@ -3,20 +3,31 @@ package convention
 import (
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 // Parse compiles a ConventionConfig into a usable Convention.
-// Returns an error if the pattern is empty or invalid.
+// When the pattern is empty it is derived from the types; an error is
+// returned if neither is set or the pattern or rules are invalid.
 func Parse(cfg ConventionConfig) (*Convention, error) {
-	if cfg.Pattern == "" {
+	pattern := cfg.Pattern
+	if pattern == "" && len(cfg.Types) > 0 {
+		pattern = typedPattern(cfg.Types)
+	}
+	if pattern == "" {
 		return nil, fmt.Errorf("convention %q: pattern is required", cfg.Name)
 	}
 
-	compiled, err := regexp.Compile(cfg.Pattern)
+	compiled, err := regexp.Compile(pattern)
 	if err != nil {
 		return nil, fmt.Errorf("convention %q: invalid pattern: %w", cfg.Name, err)
 	}
 
+	rules, err := CompileRules(cfg.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("convention %q: %w", cfg.Name, err)
+	}
+
 	maxLen := cfg.MaxLength
 	if maxLen <= 0 {
 		maxLen = 100 // default
@@ -30,9 +41,19 @@ func Parse(cfg ConventionConfig) (*Convention, error) {
 		MaxLength: maxLen,
 		Required:  cfg.Required,
 		Examples:  cfg.Examples,
+		Rules:     rules,
 	}, nil
 }
 
+// typedPattern builds a "type(scope)!: subject" header pattern for types.
+func typedPattern(types []string) string {
+	quoted := make([]string, len(types))
+	for i, t := range types {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	return `^(` + strings.Join(quoted, "|") + `)(\(.+\))?!?: .+`
+}
+
 // ParseBuiltin loads and compiles a built-in convention by name.
 // Returns an error if the name is not recognized.
 func ParseBuiltin(name string) (*Convention, error) {
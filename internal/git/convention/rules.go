@@ -0,0 +1,479 @@
+package convention
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Severity is the level of a rule, using commitlint's numbering.
+type Severity int
+
+const (
+	// SeverityOff disables a rule.
+	SeverityOff Severity = iota
+
+	// SeverityWarning reports violations without failing validation.
+	SeverityWarning
+
+	// SeverityError fails validation.
+	SeverityError
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityOff:
+		return "off"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// Rule is a compiled commitlint-style rule: [severity, "always"|"never", value].
+type Rule struct {
+	Name     string
+	Severity Severity
+
+	// Never inverts the condition of the rule.
+	Never bool
+
+	// Values holds list and string arguments (enums, cases, trailers).
+	Values []string
+
+	// Limit holds numeric arguments (lengths).
+	Limit int
+}
+
+// ruleArg describes the argument a rule takes.
+type ruleArg int
+
+const (
+	argNone ruleArg = iota
+	argList
+	argNumber
+)
+
+// ruleCheck reports whether the condition of a rule holds for a message,
+// together with the offending value when it does not. "never" rules are
+// handled by the caller, so checks describe the "always" condition.
+type ruleCheck func(r Rule, m messageParts) (ok bool, actual string)
+
+type ruleDef struct {
+	arg   ruleArg
+	check ruleCheck
+	// limit rules have no meaningful "never" form.
+	limit bool
+}
+
+// supportedRules lists the commitlint rules moai enforces.
+var supportedRules = map[string]ruleDef{
+	"type-enum":   {arg: argList, check: enumCheck(func(m messageParts) string { return m.typ })},
+	"type-case":   {arg: argList, check: caseCheck(func(m messageParts) string { return m.typ })},
+	"type-empty":  {check: emptyCheck(func(m messageParts) string { return m.typ })},
+	"scope-enum":  {arg: argList, check: scopeEnumCheck},
+	"scope-case":  {arg: argList, check: caseCheck(func(m messageParts) string { return m.scope })},
+	"scope-empty": {check: emptyCheck(func(m messageParts) string { return m.scope })},
+
+	"subject-case":       {arg: argList, check: caseCheck(func(m messageParts) string { return m.subject })},
+	"subject-empty":      {check: emptyCheck(func(m messageParts) string { return m.subject })},
+	"subject-full-stop":  {arg: argList, check: fullStopCheck},
+	"subject-max-length": {arg: argNumber, limit: true, check: maxLengthCheck(func(m messageParts) string { return m.subject })},
+	"header-max-length":  {arg: argNumber, limit: true, check: maxLengthCheck(func(m messageParts) string { return m.header })},
+	"header-min-length":  {arg: argNumber, limit: true, check: minLengthCheck},
+
+	"body-empty":             {check: emptyCheck(func(m messageParts) string { return m.body })},
+	"body-leading-blank":     {check: leadingBlankCheck(func(m messageParts) (string, bool) { return m.body, m.bodyBlank })},
+	"body-max-line-length":   {arg: argNumber, limit: true, check: maxLineLengthCheck(func(m messageParts) string { return m.body })},
+	"footer-empty":           {check: emptyCheck(func(m messageParts) string { return m.footer })},
+	"footer-leading-blank":   {check: leadingBlankCheck(func(m messageParts) (string, bool) { return m.footer, m.footerBlank })},
+	"footer-max-line-length": {arg: argNumber, limit: true, check: maxLineLengthCheck(func(m messageParts) string { return m.footer })},
+	"references-empty":       {check: referencesEmptyCheck},
+	"trailer-exists":         {arg: argList, check: trailerCheck},
+}
+
+// IsSupportedRule reports whether name is a rule moai can enforce.
+func IsSupportedRule(name string) bool {
+	_, ok := supportedRules[name]
+	return ok
+}
+
+// CompileRules converts commitlint-style rule definitions into rules.
+// Each definition is a list [severity, applicable, value] where severity is
+// 0/1/2 or off/warning/error, applicable is "always" (default) or "never",
+// and value depends on the rule. Disabled rules are dropped; the result is
+// sorted by name.
+func CompileRules(defs map[string]any) ([]Rule, error) {
+	var rules []Rule
+	for name, raw := range defs {
+		def, ok := supportedRules[name]
+		if !ok {
+			return nil, fmt.Errorf("rule %q: unsupported rule", name)
+		}
+		r, err := compileRule(name, def, raw)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", name, err)
+		}
+		if r.Severity != SeverityOff {
+			rules = append(rules, r)
+		}
+	}
+	slices.SortFunc(rules, func(a, b Rule) int { return cmp.Compare(a.Name, b.Name) })
+	return rules, nil
+}
+
+func compileRule(name string, def ruleDef, raw any) (Rule, error) {
+	r := Rule{Name: name}
+	args, ok := raw.([]any)
+	if !ok {
+		// A bare severity is shorthand for [severity].
+		args = []any{raw}
+	}
+	if len(args) == 0 {
+		return r, fmt.Errorf("missing severity")
+	}
+
+	sev, err := parseSeverity(args[0])
+	if err != nil {
+		return r, err
+	}
+	r.Severity = sev
+	if sev == SeverityOff {
+		return r, nil
+	}
+
+	if len(args) > 1 {
+		switch args[1] {
+		case "always":
+		case "never":
+			r.Never = true
+		default:
+			return r, fmt.Errorf("applicable must be \"always\" or \"never\", got %v", args[1])
+		}
+	}
+	if r.Never && def.limit {
+		return r, fmt.Errorf("length rules only support \"always\"")
+	}
+
+	var value any
+	if len(args) > 2 {
+		value = args[2]
+	}
+	switch def.arg {
+	case argList:
+		r.Values, err = stringList(value)
+		if err == nil && len(r.Values) == 0 {
+			err = fmt.Errorf("value is required")
+		}
+	case argNumber:
+		r.Limit, err = number(value)
+	}
+	return r, err
+}
+
+func parseSeverity(v any) (Severity, error) {
+	switch s := v.(type) {
+	case string:
+		switch strings.ToLower(s) {
+		case "off", "0":
+			return SeverityOff, nil
+		case "warning", "warn", "1":
+			return SeverityWarning, nil
+		case "error", "2":
+			return SeverityError, nil
+		}
+	default:
+		if n, err := number(v); err == nil && n >= 0 && n <= 2 {
+			return Severity(n), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid severity %v (use 0-2 or off, warning, error)", v)
+}
+
+func stringList(v any) ([]string, error) {
+	switch x := v.(type) {
+	case string:
+		return []string{x}, nil
+	case []string:
+		return x, nil
+	case []any:
+		out := make([]string, 0, len(x))
+		for _, e := range x {
+			s, ok := e.(string)
+			if !ok {
+				return nil, fmt.Errorf("value must be a list of strings, got %v", e)
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	case nil:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("value must be a string or list of strings, got %v", v)
+}
+
+func number(v any) (int, error) {
+	switch x := v.(type) {
+	case int:
+		return x, nil
+	case int64:
+		return int(x), nil
+	case uint64:
+		return int(x), nil
+	case float64:
+		if x == float64(int(x)) {
+			return int(x), nil
+		}
+	case string:
+		if n, err := strconv.Atoi(x); err == nil {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("value must be a whole number, got %v", v)
+}
+
+// hasRule reports whether conv enforces a rule with the given name.
+func (c *Convention) hasRule(name string) bool {
+	return slices.ContainsFunc(c.Rules, func(r Rule) bool { return r.Name == name })
+}
+
+// messageParts is a commit message split the way commitlint reads it.
+type messageParts struct {
+	header  string
+	typ     string
+	scope   string
+	subject string
+	body    string
+	footer  string
+	raw     string
+
+	// bodyBlank and footerBlank report whether a blank line precedes the
+	// body and footer.
+	bodyBlank   bool
+	footerBlank bool
+}
+
+var (
+	// trailerLinePattern matches git trailers and BREAKING CHANGE notes.
+	trailerLinePattern = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[A-Za-z][\w-]*)(: | #)`)
+	referencePattern   = regexp.MustCompile(`(?:^|[\s(])(?:[\w.-]+/[\w.-]+)?#\d+\b`)
+)
+
+// splitMessage splits message into header, body and footer. The footer is
+// the trailing paragraph when every line of it is a trailer.
+func splitMessage(message string) messageParts {
+	lines := strings.Split(strings.TrimRight(message, "\n"), "\n")
+	m := messageParts{header: strings.TrimSpace(lines[0]), raw: message}
+	rest := lines[1:]
+	if len(rest) > 0 {
+		m.bodyBlank = strings.TrimSpace(rest[0]) == ""
+	}
+
+	// The footer is the last paragraph when it starts with a trailer and
+	// every other line is a trailer or an indented continuation.
+	start := 0
+	for i := len(rest) - 1; i >= 0; i-- {
+		if strings.TrimSpace(rest[i]) == "" {
+			start = i + 1
+			break
+		}
+	}
+	footerStart := len(rest)
+	if para := rest[start:]; len(para) > 0 && trailerLinePattern.MatchString(para[0]) &&
+		!slices.ContainsFunc(para, func(l string) bool {
+			return !trailerLinePattern.MatchString(l) && !strings.HasPrefix(l, " ")
+		}) {
+		footerStart = start
+	}
+
+	m.body = strings.TrimSpace(strings.Join(rest[:footerStart], "\n"))
+	m.footer = strings.TrimSpace(strings.Join(rest[footerStart:], "\n"))
+	m.footerBlank = footerStart > 0 && strings.TrimSpace(rest[footerStart-1]) == ""
+
+	m.typ = extractType(m.header)
+	if m.typ != "" {
+		m.scope = extractScope(m.header)
+	}
+	if _, subject, ok := strings.Cut(m.header, ": "); ok {
+		m.subject = strings.TrimSpace(subject)
+	}
+	return m
+}
+
+// validateRules evaluates the rules of conv against message.
+func validateRules(message string, conv *Convention, result *ValidationResult) {
+	if len(conv.Rules) == 0 {
+		return
+	}
+	parts := splitMessage(message)
+	for _, r := range conv.Rules {
+		ok, actual := supportedRules[r.Name].check(r, parts)
+		if ok != r.Never {
+			continue
+		}
+		result.Violations = append(result.Violations, Violation{
+			Type:     ViolationRule,
+			Field:    r.Name,
+			Expected: r.describe(),
+			Actual:   actual,
+			Warning:  r.Severity == SeverityWarning,
+		})
+	}
+}
+
+// describe renders the rule requirement for error messages.
+func (r Rule) describe() string {
+	applicable := "always"
+	if r.Never {
+		applicable = "never"
+	}
+	switch {
+	case len(r.Values) > 0:
+		return fmt.Sprintf("%s %s", applicable, strings.Join(r.Values, ", "))
+	case r.Limit > 0:
+		return fmt.Sprintf("%s %d", applicable, r.Limit)
+	}
+	return applicable
+}
+
+// The checks below return true when the "always" form of the rule holds.
+// Checks on a part that is absent pass, except emptiness checks, so that a
+// missing scope does not violate scope-case.
+
+func enumCheck(part func(messageParts) string) ruleCheck {
+	return func(r Rule, m messageParts) (bool, string) {
+		v := part(m)
+		if v == "" {
+			return !r.Never, ""
+		}
+		return slices.Contains(r.Values, v), v
+	}
+}
+
+// scopeEnumCheck accepts multiple comma-separated scopes.
+func scopeEnumCheck(r Rule, m messageParts) (bool, string) {
+	if m.scope == "" {
+		return !r.Never, ""
+	}
+	for s := range strings.SplitSeq(m.scope, ",") {
+		in := slices.Contains(r.Values, strings.TrimSpace(s))
+		if in == r.Never {
+			return in, m.scope
+		}
+	}
+	return !r.Never, m.scope
+}
+
+func caseCheck(part func(messageParts) string) ruleCheck {
+	return func(r Rule, m messageParts) (bool, string) {
+		v := part(m)
+		if v == "" {
+			return !r.Never, ""
+		}
+		return slices.ContainsFunc(r.Values, func(c string) bool { return matchesCase(v, c) }), v
+	}
+}
+
+func emptyCheck(part func(messageParts) string) ruleCheck {
+	return func(_ Rule, m messageParts) (bool, string) {
+		v := part(m)
+		return v == "", v
+	}
+}
+
+func leadingBlankCheck(part func(messageParts) (string, bool)) ruleCheck {
+	return func(r Rule, m messageParts) (bool, string) {
+		v, blank := part(m)
+		if v == "" {
+			return !r.Never, ""
+		}
+		return blank, firstLine(v)
+	}
+}
+
+func maxLengthCheck(part func(messageParts) string) ruleCheck {
+	return func(r Rule, m messageParts) (bool, string) {
+		n := utf8.RuneCountInString(part(m))
+		return n <= r.Limit, fmt.Sprintf("%d characters", n)
+	}
+}
+
+func minLengthCheck(r Rule, m messageParts) (bool, string) {
+	n := utf8.RuneCountInString(m.header)
+	return n >= r.Limit, fmt.Sprintf("%d characters", n)
+}
+
+func maxLineLengthCheck(part func(messageParts) string) ruleCheck {
+	return func(r Rule, m messageParts) (bool, string) {
+		for line := range strings.SplitSeq(part(m), "\n") {
+			// Long URLs cannot be wrapped.
+			if utf8.RuneCountInString(line) > r.Limit && !strings.Contains(line, "://") {
+				return false, fmt.Sprintf("%d characters: %s", utf8.RuneCountInString(line), line)
+			}
+		}
+		return true, ""
+	}
+}
+
+func fullStopCheck(r Rule, m messageParts) (bool, string) {
+	if m.subject == "" {
+		return !r.Never, ""
+	}
+	return strings.HasSuffix(m.subject, r.Values[0]), m.subject
+}
+
+func referencesEmptyCheck(_ Rule, m messageParts) (bool, string) {
+	return !referencePattern.MatchString(m.raw), ""
+}
+
+func trailerCheck(r Rule, m messageParts) (bool, string) {
+	for line := range strings.SplitSeq(m.footer, "\n") {
+		if strings.HasPrefix(line, r.Values[0]) {
+			return true, line
+		}
+	}
+	return false, ""
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// matchesCase reports whether s is written in the named commitlint case.
+func matchesCase(s, name string) bool {
+	switch name {
+	case "lower-case", "lowercase":
+		return s == strings.ToLower(s)
+	case "upper-case", "uppercase":
+		return s == strings.ToUpper(s)
+	case "sentence-case", "sentencecase":
+		first, size := utf8.DecodeRuneInString(s)
+		return unicode.IsUpper(first) && s[size:] == strings.ToLower(s[size:])
+	case "start-case":
+		for w := range strings.FieldsSeq(s) {
+			if first, _ := utf8.DecodeRuneInString(w); !unicode.IsUpper(first) {
+				return false
+			}
+		}
+		return true
+	case "pascal-case":
+		first, _ := utf8.DecodeRuneInString(s)
+		return unicode.IsUpper(first) && !strings.ContainsAny(s, " -_")
+	case "camel-case":
+		first, _ := utf8.DecodeRuneInString(s)
+		return unicode.IsLower(first) && !strings.ContainsAny(s, " -_")
+	case "kebab-case":
+		return s == strings.ToLower(s) && !strings.ContainsAny(s, " _")
+	case "snake-case":
+		return s == strings.ToLower(s) && !strings.ContainsAny(s, " -")
+	}
+	return false
+}
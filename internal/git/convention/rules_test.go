@@ -0,0 +1,172 @@
+package convention
+
+import (
+	"strings"
+	"testing"
+)
+
+func ruleConvention(t *testing.T, rules map[string]any) *Convention {
+	t.Helper()
+	conv, err := Parse(ConventionConfig{
+		Name:   "team",
+		Types:  []string{"feat", "fix", "docs"},
+		Scopes: []string{"api", "cli"},
+		Rules:  rules,
+	})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return conv
+}
+
+func TestParse_DerivesPatternFromTypes(t *testing.T) {
+	conv := ruleConvention(t, nil)
+	if !conv.Pattern.MatchString("feat(api)!: add v2") || conv.Pattern.MatchString("chore: bump") {
+		t.Errorf("derived pattern = %s", conv.Pattern)
+	}
+}
+
+func TestCompileRules(t *testing.T) {
+	rules, err := CompileRules(map[string]any{
+		"type-enum":         []any{2, "always", []any{"feat", "fix"}},
+		"subject-case":      []any{"warning", "never", "upper-case"},
+		"header-max-length": []any{float64(2), "always", float64(72)},
+		"body-empty":        []any{0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("rules = %+v, want disabled rule dropped", rules)
+	}
+	if r := rules[0]; r.Name != "header-max-length" || r.Limit != 72 || r.Severity != SeverityError {
+		t.Errorf("rules[0] = %+v", r)
+	}
+	if r := rules[1]; r.Name != "subject-case" || !r.Never || r.Severity != SeverityWarning || r.Values[0] != "upper-case" {
+		t.Errorf("rules[1] = %+v", r)
+	}
+
+	for name, def := range map[string]any{
+		"no-such-rule":      []any{2},
+		"type-enum":         []any{2, "always"},
+		"header-max-length": []any{2, "never", 10},
+		"subject-case":      []any{5, "always", "lower-case"},
+		"body-empty":        []any{2, "sometimes"},
+	} {
+		if _, err := CompileRules(map[string]any{name: def}); err == nil {
+			t.Errorf("CompileRules(%s: %v) should fail", name, def)
+		}
+	}
+}
+
+func TestValidate_Rules(t *testing.T) {
+	conv := ruleConvention(t, map[string]any{
+		"scope-enum":           []any{1, "always", []any{"api", "cli"}},
+		"subject-case":         []any{2, "never", []any{"sentence-case", "start-case", "upper-case"}},
+		"subject-full-stop":    []any{2, "never", "."},
+		"body-leading-blank":   []any{2, "always"},
+		"body-max-line-length": []any{2, "always", 20},
+		"trailer-exists":       []any{2, "always", "Signed-off-by:"},
+	})
+
+	tests := []struct {
+		name      string
+		message   string
+		valid     bool
+		violation string
+	}{
+		{"valid", "feat(api): add v2\n\nShort body.\n\nSigned-off-by: A <a@b.c>", true, ""},
+		{"warning scope", "feat(web): add v2\n\nSigned-off-by: A <a@b.c>", true, "scope-enum"},
+		{"subject case", "feat: Add V2\n\nSigned-off-by: A <a@b.c>", false, "subject-case"},
+		{"full stop", "fix: handle nil.\n\nSigned-off-by: A <a@b.c>", false, "subject-full-stop"},
+		{"body leading blank", "fix: handle nil\nno blank line\n\nSigned-off-by: A <a@b.c>", false, "body-leading-blank"},
+		{"body line length", "fix: handle nil\n\nthis body line is far too long\n\nSigned-off-by: A <a@b.c>", false, "body-max-line-length"},
+		{"missing trailer", "fix: handle nil", false, "trailer-exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Validate(tt.message, conv)
+			if r.Valid != tt.valid {
+				t.Errorf("Valid = %v, want %v (%+v)", r.Valid, tt.valid, r.Violations)
+			}
+			if tt.violation == "" {
+				if len(r.Violations) > 0 {
+					t.Errorf("unexpected violations: %+v", r.Violations)
+				}
+				return
+			}
+			if len(r.Violations) != 1 || r.Violations[0].Field != tt.violation {
+				t.Errorf("violations = %+v, want only %s", r.Violations, tt.violation)
+			}
+		})
+	}
+}
+
+func TestValidate_RuleReplacesListCheck(t *testing.T) {
+	conv, err := Parse(ConventionConfig{
+		Name:    "team",
+		Pattern: anyTypePattern,
+		Types:   []string{"feat", "fix"},
+		Rules:   map[string]any{"type-enum": []any{1, "always", []any{"feat", "fix"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The warning-level rule takes the place of the error-level list check.
+	r := Validate("chore: bump deps", conv)
+	if !r.Valid || len(r.Violations) != 1 || r.Violations[0].Type != ViolationRule || !r.Violations[0].Warning {
+		t.Errorf("Validate() = %+v", r)
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	m := splitMessage("feat(api,cli)!: add v2\n\nBody line.\nMore body.\n\nRefs: #12\nSigned-off-by: A\n  continued")
+	if m.typ != "feat" || m.scope != "api,cli" || m.subject != "add v2" {
+		t.Errorf("header parts = %q %q %q", m.typ, m.scope, m.subject)
+	}
+	if m.body != "Body line.\nMore body." || !m.bodyBlank {
+		t.Errorf("body = %q (blank %v)", m.body, m.bodyBlank)
+	}
+	if !strings.HasPrefix(m.footer, "Refs: #12") || !m.footerBlank {
+		t.Errorf("footer = %q (blank %v)", m.footer, m.footerBlank)
+	}
+
+	// A trailing paragraph that is not all trailers is body.
+	m = splitMessage("fix: x\n\nSee: the docs\nand more")
+	if m.footer != "" || m.body != "See: the docs\nand more" {
+		t.Errorf("body = %q, footer = %q", m.body, m.footer)
+	}
+}
+
+func TestMatchesCase(t *testing.T) {
+	tests := []struct {
+		s, name string
+		want    bool
+	}{
+		{"add feature", "lower-case", true},
+		{"ADD", "upper-case", true},
+		{"Add feature", "sentence-case", true},
+		{"Add Feature", "sentence-case", false},
+		{"Add Feature", "start-case", true},
+		{"AddFeature", "pascal-case", true},
+		{"addFeature", "camel-case", true},
+		{"add-feature", "kebab-case", true},
+		{"add_feature", "snake-case", true},
+		{"add feature", "unknown-case", false},
+	}
+	for _, tt := range tests {
+		if got := matchesCase(tt.s, tt.name); got != tt.want {
+			t.Errorf("matchesCase(%q, %q) = %v, want %v", tt.s, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatError_Warnings(t *testing.T) {
+	conv := ruleConvention(t, map[string]any{"scope-enum": []any{1, "always", []any{"api"}}})
+	r := Validate("feat(web): add", conv)
+	got := FormatError(r, conv)
+	if !strings.Contains(got, "has warnings") || !strings.Contains(got, `[WARN] scope-enum (expected: always api, got: "web")`) {
+		t.Errorf("FormatError() =\n%s", got)
+	}
+}
@@ -22,6 +22,10 @@ const (
 
 	// ViolationRequired indicates a required field is missing.
 	ViolationRequired ViolationType = "required"
+
+	// ViolationRule indicates a commitlint-style rule is not satisfied.
+	// Field holds the rule name.
+	ViolationRule ViolationType = "rule"
 )
 
 // Violation represents a single validation error.
@@ -31,10 +35,15 @@ type Violation struct {
 	Expected   string
 	Actual     string
 	Suggestion string
+
+	// Warning marks violations of warning-level rules, which do not make
+	// the message invalid.
+	Warning bool
 }
 
 // ValidationResult contains the outcome of validating a commit message.
 type ValidationResult struct {
+	// Valid is false when any violation is an error.
 	Valid      bool
 	Message    string
 	Violations []Violation
@@ -49,6 +58,10 @@ type ConventionConfig struct {
 	MaxLength int      `yaml:"max_length"`
 	Required  []string `yaml:"required"`
 	Examples  []string `yaml:"examples"`
+
+	// Rules holds commitlint-style rules keyed by rule name, each a list
+	// [severity, "always"|"never", value]. See CompileRules.
+	Rules map[string]any `yaml:"rules"`
 }
 
 // Convention represents a compiled commit message convention ready for use.
@@ -60,6 +73,7 @@ type Convention struct {
 	MaxLength int
 	Required  []string
 	Examples  []string
+	Rules     []Rule
 }
 
 // DetectionResult contains the outcome of auto-detecting a convention
@@ -30,8 +30,8 @@ func Validate(message string, conv *Convention) ValidationResult {
 		return result
 	}
 
-	// Check max length.
-	if conv.MaxLength > 0 && len(header) > conv.MaxLength {
+	// Check max length; a header-max-length rule takes its place.
+	if conv.MaxLength > 0 && len(header) > conv.MaxLength && !conv.hasRule("header-max-length") {
 		result.Violations = append(result.Violations, Violation{
 			Type:     ViolationMaxLength,
 			Field:    "header",
@@ -40,7 +40,8 @@ func Validate(message string, conv *Convention) ValidationResult {
 		})
 	}
 
-	// Check pattern match.
+	// Check pattern match. Rules on the type, scope and subject only apply
+	// to headers the pattern can be read with.
 	if !conv.Pattern.MatchString(header) {
 		result.Violations = append(result.Violations, Violation{
 			Type:       ViolationPattern,
@@ -52,18 +53,21 @@ func Validate(message string, conv *Convention) ValidationResult {
 	} else {
 		// Pattern matches; check semantic rules.
 		validateSemantics(header, conv, &result)
+		validateRules(message, conv, &result)
 	}
 
-	result.Valid = len(result.Violations) == 0
+	result.Valid = !slices.ContainsFunc(result.Violations, func(v Violation) bool { return !v.Warning })
 	return result
 }
 
-// validateSemantics checks type and scope against allowed lists.
+// validateSemantics checks type and scope against allowed lists. A
+// type-enum or scope-enum rule replaces the matching list check so that its
+// severity applies.
 func validateSemantics(header string, conv *Convention, result *ValidationResult) {
 	commitType := extractType(header)
 
 	// Check type validity.
-	if len(conv.Types) > 0 && commitType != "" {
+	if len(conv.Types) > 0 && commitType != "" && !conv.hasRule("type-enum") {
 		found := slices.Contains(conv.Types, commitType)
 		if !found {
 			result.Violations = append(result.Violations, Violation{
@@ -77,7 +81,7 @@ func validateSemantics(header string, conv *Convention, result *ValidationResult
 
 	// Check scope validity (only when scopes are defined).
 	scope := extractScope(header)
-	if len(conv.Scopes) > 0 && scope != "" {
+	if len(conv.Scopes) > 0 && scope != "" && !conv.hasRule("scope-enum") {
 		found := slices.Contains(conv.Scopes, scope)
 		if !found {
 			result.Violations = append(result.Violations, Violation{
@@ -0,0 +1,144 @@
+// Package githook installs the native git hooks that run moai's commit
+// message checks. Existing user hooks are kept and chained: they run first,
+// and a failing user hook aborts the git operation as before.
+package githook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Hooks managed by moai.
+const (
+	CommitMsg        = "commit-msg"
+	PrePush          = "pre-push"
+	PrepareCommitMsg = "prepare-commit-msg"
+)
+
+// Managed lists the hooks written by Install, in installation order.
+var Managed = []string{CommitMsg, PrePush, PrepareCommitMsg}
+
+// ChainSuffix is appended to the name of a pre-existing hook when it is set
+// aside to be chained.
+const ChainSuffix = ".pre-moai"
+
+// marker identifies hook scripts written by Install.
+const marker = "# moai-adk managed hook"
+
+// ErrChainExists is returned when a user hook would overwrite an already
+// chained hook.
+var ErrChainExists = errors.New("githook: chained hook already exists")
+
+// InstallResult reports what Install did.
+type InstallResult struct {
+	// Dir is the hooks directory (honours core.hooksPath).
+	Dir string
+
+	// Installed lists the hooks written.
+	Installed []string
+
+	// Chained lists the pre-existing user hooks moved aside to <name>.pre-moai.
+	Chained []string
+}
+
+// Dir returns the absolute hooks directory of the repository at repoDir.
+func Dir(ctx context.Context, repoDir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--git-path", "hooks")
+	cmd.Dir = repoDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("githook: locate hooks directory: %s: %w", msg, err)
+		}
+		return "", fmt.Errorf("githook: locate hooks directory: %w", err)
+	}
+	dir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoDir, dir)
+	}
+	return dir, nil
+}
+
+// Install writes the managed hooks into the hooks directory of repoDir.
+// Each hook runs moaiBin. Hooks previously written by Install are replaced;
+// other existing hooks are renamed to <name>.pre-moai and chained.
+func Install(ctx context.Context, repoDir, moaiBin string) (*InstallResult, error) {
+	dir, err := Dir(ctx, repoDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("githook: create hooks directory: %w", err)
+	}
+
+	result := &InstallResult{Dir: dir}
+	for _, name := range Managed {
+		path := filepath.Join(dir, name)
+		existing, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return result, fmt.Errorf("githook: read %s: %w", name, err)
+		case !IsManaged(existing):
+			chained := path + ChainSuffix
+			if _, err := os.Stat(chained); err == nil {
+				return result, fmt.Errorf("%w: %s", ErrChainExists, chained)
+			}
+			if err := os.Rename(path, chained); err != nil {
+				return result, fmt.Errorf("githook: chain %s: %w", name, err)
+			}
+			result.Chained = append(result.Chained, name)
+		}
+
+		//nolint:gosec // hooks must be executable
+		if err := os.WriteFile(path, []byte(Script(name, moaiBin)), 0o755); err != nil {
+			return result, fmt.Errorf("githook: write %s: %w", name, err)
+		}
+		result.Installed = append(result.Installed, name)
+	}
+	return result, nil
+}
+
+// IsManaged reports whether a hook script was written by Install.
+func IsManaged(script []byte) bool {
+	return bytes.Contains(script, []byte(marker))
+}
+
+// Script returns the shell script for the named hook. It runs the chained
+// user hook first, then `moai git <name>` with the hook's arguments.
+func Script(name, moaiBin string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#!/bin/sh\n%s (%s). Reinstall with: moai git install-hooks\n", marker, name)
+	fmt.Fprintf(&b, "# A pre-existing hook is kept as %s%s and runs first.\n", name, ChainSuffix)
+	fmt.Fprintf(&b, "chained=\"$(dirname \"$0\")/%s%s\"\n", name, ChainSuffix)
+	run := fmt.Sprintf("%s git %s \"$@\"", shellQuote(moaiBin), name)
+
+	// pre-push reads the pushed refs from stdin, which both hooks need.
+	if name == PrePush {
+		b.WriteString("input=$(cat)\n")
+		b.WriteString("if [ -x \"$chained\" ]; then\n")
+		b.WriteString("\tprintf '%s\\n' \"$input\" | \"$chained\" \"$@\" || exit $?\n")
+		b.WriteString("fi\n")
+		fmt.Fprintf(&b, "printf '%%s\\n' \"$input\" | %s\n", run)
+		return b.String()
+	}
+
+	b.WriteString("if [ -x \"$chained\" ]; then\n")
+	b.WriteString("\t\"$chained\" \"$@\" || exit $?\n")
+	b.WriteString("fi\n")
+	fmt.Fprintf(&b, "exec %s\n", run)
+	return b.String()
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
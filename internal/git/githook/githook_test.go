@@ -0,0 +1,121 @@
+package githook
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initRepo creates a git repository with a user commit-msg hook and a fake
+// moai binary; both append their arguments to the returned log file.
+func initRepo(t *testing.T) (repo, moaiBin, logFile string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo = t.TempDir()
+	logFile = filepath.Join(t.TempDir(), "calls.log")
+	git(t, repo, "init", "-q")
+
+	moaiBin = filepath.Join(t.TempDir(), "moai")
+	writeScript(t, moaiBin, "echo \"moai $*\" >> '"+logFile+"'\n")
+	writeScript(t, filepath.Join(repo, ".git", "hooks", CommitMsg), "echo \"user $(basename \"$1\")\" >> '"+logFile+"'\n")
+	return repo, moaiBin, logFile
+}
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return string(out)
+}
+
+func writeScript(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstall_ChainsUserHooks(t *testing.T) {
+	repo, moaiBin, logFile := initRepo(t)
+	ctx := context.Background()
+
+	result, err := Install(ctx, repo, moaiBin)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if len(result.Installed) != len(Managed) || len(result.Chained) != 1 || result.Chained[0] != CommitMsg {
+		t.Errorf("result = %+v", result)
+	}
+
+	// Reinstalling replaces managed hooks without chaining them.
+	result, err = Install(ctx, repo, moaiBin)
+	if err != nil || len(result.Chained) != 0 {
+		t.Fatalf("reinstall = %+v, %v", result, err)
+	}
+
+	git(t, repo, "commit", "-q", "--allow-empty", "-m", "feat: first")
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := string(data)
+	userAt := strings.Index(log, "user COMMIT_EDITMSG")
+	moaiAt := strings.Index(log, "moai git commit-msg ")
+	if userAt < 0 || moaiAt < userAt {
+		t.Errorf("user hook should run before moai:\n%s", log)
+	}
+	if !strings.Contains(log, "moai git prepare-commit-msg ") {
+		t.Errorf("prepare-commit-msg not run:\n%s", log)
+	}
+}
+
+func TestInstall_UserHookFailureAborts(t *testing.T) {
+	repo, moaiBin, logFile := initRepo(t)
+	writeScript(t, filepath.Join(repo, ".git", "hooks", CommitMsg), "exit 1\n")
+	if _, err := Install(context.Background(), repo, moaiBin); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("git", "-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com",
+		"commit", "-q", "--allow-empty", "-m", "feat: first")
+	if err := cmd.Run(); err == nil {
+		t.Fatal("commit should fail when the chained hook fails")
+	}
+	data, _ := os.ReadFile(logFile)
+	if strings.Contains(string(data), "git commit-msg") {
+		t.Errorf("moai ran after the chained hook failed:\n%s", data)
+	}
+}
+
+func TestInstall_ChainExists(t *testing.T) {
+	repo, moaiBin, _ := initRepo(t)
+	writeScript(t, filepath.Join(repo, ".git", "hooks", CommitMsg+ChainSuffix), "exit 0\n")
+
+	if _, err := Install(context.Background(), repo, moaiBin); !errors.Is(err, ErrChainExists) {
+		t.Errorf("Install() error = %v, want ErrChainExists", err)
+	}
+}
+
+func TestScript_PrePushForwardsStdin(t *testing.T) {
+	script := Script(PrePush, "/opt/it's/moai")
+	for _, want := range []string{"input=$(cat)", `'/opt/it'\''s/moai' git pre-push "$@"`, marker} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+	if !IsManaged([]byte(script)) || IsManaged([]byte("#!/bin/sh\nexit 0\n")) {
+		t.Error("IsManaged() mismatch")
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 )
 
@@ -79,9 +78,10 @@ func (m *GitManager) ExecuteCommand(cmd GitCommand) GitResult {
 	fullCommand := append([]string{"git"}, args...)
 
 	// Check cache if caching is enabled
+	var cacheKey string
 	if cmd.CacheTTLSeconds >= 0 {
 		branch := m.getCurrentBranch(workDir)
-		cacheKey := GenerateCacheKey(cmd.OperationType, args, workDir, branch)
+		cacheKey = GenerateCacheKey(cmd.OperationType, args, workDir, branch)
 
 		if result, hit := m.cache.Get(cacheKey); hit {
 			result.ExecutionTime = time.Since(start)
@@ -115,8 +115,6 @@ func (m *GitManager) ExecuteCommand(cmd GitCommand) GitResult {
 		if ttl <= 0 {
 			ttl = time.Duration(m.config.DefaultTTLSeconds) * time.Second
 		}
-		branch := m.getCurrentBranch(workDir)
-		cacheKey := GenerateCacheKey(cmd.OperationType, args, workDir, branch)
 		result.Cached = true
 		m.cache.Set(cacheKey, result, ttl)
 	}
@@ -127,28 +125,26 @@ func (m *GitManager) ExecuteCommand(cmd GitCommand) GitResult {
 	return result
 }
 
-// ExecuteParallel executes multiple Git commands in parallel.
+// ExecuteParallel executes multiple Git commands in parallel on the
+// manager's worker pool. After Shutdown the commands run sequentially.
 func (m *GitManager) ExecuteParallel(cmds []GitCommand) []GitResult {
 	if len(cmds) == 0 {
 		return nil
 	}
 
-	results := make([]GitResult, len(cmds))
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, m.config.MaxWorkers)
-
-	for i, cmd := range cmds {
-		wg.Add(1)
-		go func(idx int, c GitCommand) {
-			defer wg.Done()
-			sem <- struct{}{}        // Acquire
-			defer func() { <-sem }() // Release
-			results[idx] = m.ExecuteCommand(c)
-		}(i, cmd)
+	if m.pool.shutdown.Load() {
+		results := make([]GitResult, len(cmds))
+		for i, c := range cmds {
+			results[i] = m.ExecuteCommand(c)
+		}
+		return results
 	}
 
-	wg.Wait()
-	return results
+	tasks := make([]func() GitResult, len(cmds))
+	for i, c := range cmds {
+		tasks[i] = func() GitResult { return m.ExecuteCommand(c) }
+	}
+	return ExecuteParallel(m.pool, tasks)
 }
 
 // GetProjectInfo returns comprehensive project information.
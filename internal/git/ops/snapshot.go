@@ -0,0 +1,342 @@
+package ops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSnapshotTTL bounds how long a cached snapshot is trusted. Unstaged
+// edits do not touch HEAD or the index, so the fingerprint alone cannot
+// detect them.
+const DefaultSnapshotTTL = 5 * time.Second
+
+// RepoSnapshot is the read-mostly repository state shown by the statusline
+// and `moai status`.
+type RepoSnapshot struct {
+	Branch      string    `json:"branch"`
+	Head        string    `json:"head"`
+	LastCommit  string    `json:"lastCommit"`
+	Staged      int       `json:"staged"`
+	Modified    int       `json:"modified"`
+	Untracked   int       `json:"untracked"`
+	Ahead       int       `json:"ahead"`
+	Behind      int       `json:"behind"`
+	HasUpstream bool      `json:"hasUpstream"`
+	CollectedAt time.Time `json:"collectedAt"`
+}
+
+// Snapshot collects the branch, ahead/behind counts, working tree status and
+// last commit in parallel. The commands bypass the in-memory cache; use
+// CachedSnapshot to reuse snapshots across processes.
+func (m *GitManager) Snapshot() RepoSnapshot {
+	cmds := []GitCommand{
+		{OperationType: OpBranch, Args: []string{"--show-current"}, CacheTTLSeconds: -1},
+		{OperationType: OpRevList, Args: []string{"--count", "--left-right", "@{upstream}...HEAD"}, CacheTTLSeconds: -1},
+		// --branch puts a "##" header first, so trimming the output never
+		// eats the status column of the first file.
+		{OperationType: OpStatus, Args: []string{"--porcelain", "--branch"}, CacheTTLSeconds: -1},
+		{OperationType: OpLog, Args: []string{"-1", "--format=%H%x1f%s"}, CacheTTLSeconds: -1},
+	}
+	results := m.ExecuteParallel(cmds)
+
+	snap := RepoSnapshot{CollectedAt: time.Now()}
+	if results[0].Success {
+		snap.Branch = results[0].Stdout
+	}
+	if results[1].Success {
+		if behind, ahead, ok := strings.Cut(results[1].Stdout, "\t"); ok {
+			snap.Behind, _ = strconv.Atoi(behind)
+			snap.Ahead, _ = strconv.Atoi(ahead)
+			snap.HasUpstream = true
+		}
+	}
+	if results[2].Success {
+		snap.Staged, snap.Modified, snap.Untracked = countStatus(results[2].Stdout)
+	}
+	if results[3].Success {
+		snap.Head, snap.LastCommit, _ = strings.Cut(results[3].Stdout, "\x1f")
+	}
+	return snap
+}
+
+// CachedSnapshot returns the snapshot stored in cache when the repository
+// fingerprint still matches, collecting and storing a new one otherwise.
+// The boolean reports a cache hit. A nil cache always collects.
+func (m *GitManager) CachedSnapshot(cache *SnapshotCache) (RepoSnapshot, bool) {
+	if cache == nil {
+		return m.Snapshot(), false
+	}
+	start := time.Now()
+	key, err := Fingerprint(m.workDir)
+	if err != nil {
+		return m.Snapshot(), false
+	}
+	if snap, ok := cache.Get(key); ok {
+		m.stats.RecordOperation(time.Since(start), true, false)
+		return snap, true
+	}
+	snap := m.Snapshot()
+	// git status may refresh the index, so key the snapshot by the state it
+	// leaves behind.
+	if after, err := Fingerprint(m.workDir); err == nil {
+		key = after
+	}
+	if err := cache.Put(key, snap); err != nil {
+		m.stats.RecordOperation(0, false, true)
+	}
+	return snap, false
+}
+
+// countStatus counts staged, modified and untracked files in
+// `git status --porcelain --branch` output.
+func countStatus(out string) (staged, modified, untracked int) {
+	for line := range strings.SplitSeq(out, "\n") {
+		if len(line) < 3 || strings.HasPrefix(line, "## ") {
+			continue
+		}
+		x, y := line[0], line[1]
+		if x == '?' && y == '?' {
+			untracked++
+			continue
+		}
+		if x != ' ' {
+			staged++
+		}
+		if y == 'M' || y == 'D' {
+			modified++
+		}
+	}
+	return staged, modified, untracked
+}
+
+// Fingerprint identifies the repository state at dir without running git.
+// It combines the HEAD reference with the modification times of HEAD, the
+// index, the checked-out branch ref and FETCH_HEAD, so commits, checkouts,
+// staging and fetches all change it.
+func Fingerprint(dir string) (string, error) {
+	gitDir, commonDir, err := findGitDir(dir)
+	if err != nil {
+		return "", err
+	}
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", fmt.Errorf("read HEAD: %w", err)
+	}
+	ref := strings.TrimSpace(string(head))
+
+	parts := []string{gitDir, ref, stamp(filepath.Join(gitDir, "HEAD")), stamp(filepath.Join(gitDir, "index"))}
+	if name, ok := strings.CutPrefix(ref, "ref: "); ok {
+		parts = append(parts, stamp(filepath.Join(commonDir, filepath.FromSlash(name))), stamp(filepath.Join(commonDir, "packed-refs")))
+	}
+	parts = append(parts, stamp(filepath.Join(commonDir, "FETCH_HEAD")))
+	return GenerateCacheKey("snapshot", parts, dir, ""), nil
+}
+
+// stamp returns the modification time and size of path, or "-" when it
+// does not exist.
+func stamp(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+}
+
+// findGitDir walks up from dir to the repository and returns its git
+// directory and, for linked worktrees, the common directory holding refs.
+func findGitDir(dir string) (gitDir, commonDir string, err error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", fmt.Errorf("resolve %s: %w", dir, err)
+	}
+	for {
+		dotGit := filepath.Join(abs, ".git")
+		info, statErr := os.Stat(dotGit)
+		switch {
+		case statErr == nil && info.IsDir():
+			return dotGit, dotGit, nil
+		case statErr == nil:
+			// A linked worktree: ".git" is a file naming the git directory.
+			data, err := os.ReadFile(dotGit)
+			if err != nil {
+				return "", "", fmt.Errorf("read %s: %w", dotGit, err)
+			}
+			target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+			if !ok {
+				return "", "", fmt.Errorf("malformed %s", dotGit)
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(abs, target)
+			}
+			commonDir = target
+			if common, err := os.ReadFile(filepath.Join(target, "commondir")); err == nil {
+				commonDir = strings.TrimSpace(string(common))
+				if !filepath.IsAbs(commonDir) {
+					commonDir = filepath.Join(target, commonDir)
+				}
+			}
+			return target, filepath.Clean(commonDir), nil
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return "", "", errors.New("not a git repository")
+		}
+		abs = parent
+	}
+}
+
+// SnapshotCachePath returns where the snapshot cache of a MoAI project is
+// stored.
+func SnapshotCachePath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".moai", "cache", "git-snapshot.json")
+}
+
+// SnapshotCache persists the latest repository snapshot in a small JSON
+// file so that short-lived processes such as the statusline can skip git
+// entirely while the repository is unchanged. It also counts hits and
+// misses across processes. A hit only appends a byte to a side file, so
+// reading a cached snapshot never rewrites the cache file; the next Put
+// folds those hits into the counters.
+type SnapshotCache struct {
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// SnapshotCacheStats holds the hit and miss counts recorded in the cache
+// file.
+type SnapshotCacheStats struct {
+	Hits     int       `json:"hits"`
+	Misses   int       `json:"misses"`
+	HitRate  float64   `json:"hitRate"`
+	StoredAt time.Time `json:"storedAt"`
+}
+
+// snapshotCacheFile is the on-disk layout of a SnapshotCache.
+type snapshotCacheFile struct {
+	Key      string       `json:"key"`
+	Snapshot RepoSnapshot `json:"snapshot"`
+	StoredAt time.Time    `json:"storedAt"`
+	Hits     int          `json:"hits"`
+	Misses   int          `json:"misses"`
+}
+
+// NewSnapshotCache creates a cache stored at path. A ttl of zero uses
+// DefaultSnapshotTTL.
+func NewSnapshotCache(path string, ttl time.Duration) *SnapshotCache {
+	if ttl <= 0 {
+		ttl = DefaultSnapshotTTL
+	}
+	return &SnapshotCache{path: path, ttl: ttl, now: time.Now}
+}
+
+// Get returns the stored snapshot when it was stored under key within the
+// TTL and records the hit. Misses are recorded by Put.
+func (c *SnapshotCache) Get(key string) (RepoSnapshot, bool) {
+	f := c.read()
+	if f.Key == key && c.now().Sub(f.StoredAt) < c.ttl {
+		c.recordHit()
+		return f.Snapshot, true
+	}
+	return RepoSnapshot{}, false
+}
+
+// Put stores snap under key and records a miss.
+func (c *SnapshotCache) Put(key string, snap RepoSnapshot) error {
+	f := c.read()
+	f.Key, f.Snapshot, f.StoredAt = key, snap, c.now()
+	f.Hits += c.pendingHits()
+	f.Misses++
+	if err := c.write(f); err != nil {
+		return err
+	}
+	// Hits recorded since pendingHits are lost; the counts are best effort.
+	_ = os.Remove(c.hitsPath())
+	return nil
+}
+
+// Stats returns the hit and miss counts recorded so far.
+func (c *SnapshotCache) Stats() SnapshotCacheStats {
+	f := c.read()
+	hits := f.Hits + c.pendingHits()
+	stats := SnapshotCacheStats{Hits: hits, Misses: f.Misses, StoredAt: f.StoredAt}
+	if total := hits + f.Misses; total > 0 {
+		stats.HitRate = float64(hits) / float64(total)
+	}
+	return stats
+}
+
+// hitsPath is the side file counting the hits since the last Put, one byte
+// per hit.
+func (c *SnapshotCache) hitsPath() string {
+	return c.path + ".hits"
+}
+
+// recordHit appends a byte to the hits file. Failures are ignored.
+func (c *SnapshotCache) recordHit() {
+	f, err := os.OpenFile(c.hitsPath(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return
+	}
+	_, _ = f.Write([]byte{'.'})
+	_ = f.Close()
+}
+
+// pendingHits returns the hits recorded since the last Put.
+func (c *SnapshotCache) pendingHits() int {
+	info, err := os.Stat(c.hitsPath())
+	if err != nil {
+		return 0
+	}
+	return int(info.Size())
+}
+
+// read loads the cache file; a missing or corrupt file is empty.
+func (c *SnapshotCache) read() snapshotCacheFile {
+	var f snapshotCacheFile
+	if data, err := os.ReadFile(c.path); err == nil {
+		_ = json.Unmarshal(data, &f)
+	}
+	return f
+}
+
+// write replaces the cache file atomically so that concurrent readers never
+// see a partial file.
+func (c *SnapshotCache) write(f snapshotCacheFile) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("marshal snapshot cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("create snapshot cache dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".git-snapshot-*")
+	if err != nil {
+		return fmt.Errorf("write snapshot cache: %w", err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write snapshot cache: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write snapshot cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write snapshot cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write snapshot cache: %w", err)
+	}
+	return nil
+}
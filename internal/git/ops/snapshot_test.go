@@ -0,0 +1,158 @@
+package ops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGitManager_Snapshot(t *testing.T) {
+	dir := initTestRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new.go"), []byte("package x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "staged.go"), []byte("package x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "staged.go")
+
+	mgr := NewGitManager(ManagerConfig{WorkDir: dir})
+	defer mgr.Shutdown()
+
+	snap := mgr.Snapshot()
+	if snap.Branch != "main" || snap.LastCommit != "Initial commit" || len(snap.Head) != 40 {
+		t.Errorf("Snapshot() = %+v", snap)
+	}
+	// The first status line must keep its leading status column.
+	if snap.Modified != 1 || snap.Staged != 1 || snap.Untracked != 1 {
+		t.Errorf("counts = staged %d, modified %d, untracked %d", snap.Staged, snap.Modified, snap.Untracked)
+	}
+	if snap.HasUpstream {
+		t.Error("HasUpstream = true without an upstream")
+	}
+}
+
+func TestGitManager_CachedSnapshot(t *testing.T) {
+	dir := initTestRepo(t)
+	mgr := NewGitManager(ManagerConfig{WorkDir: dir})
+	defer mgr.Shutdown()
+	cache := NewSnapshotCache(filepath.Join(t.TempDir(), "snap.json"), time.Minute)
+
+	first, hit := mgr.CachedSnapshot(cache)
+	if hit || first.Branch != "main" {
+		t.Fatalf("first CachedSnapshot() = %+v, hit %v", first, hit)
+	}
+	if _, hit := mgr.CachedSnapshot(cache); !hit {
+		t.Error("unchanged repository should hit the cache")
+	}
+
+	// A commit changes the fingerprint.
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "Add a")
+	snap, hit := mgr.CachedSnapshot(cache)
+	if hit || snap.LastCommit != "Add a" {
+		t.Errorf("after commit CachedSnapshot() = %+v, hit %v", snap, hit)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.HitRate < 0.33 || stats.HitRate > 0.34 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+	if mgr.GetStatistics().Operations.CacheHits != 1 {
+		t.Errorf("manager stats = %+v", mgr.GetStatistics().Operations)
+	}
+}
+
+func TestSnapshotCache_TTL(t *testing.T) {
+	cache := NewSnapshotCache(filepath.Join(t.TempDir(), "snap.json"), time.Second)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	if err := cache.Put("k", RepoSnapshot{Branch: "main"}); err != nil {
+		t.Fatal(err)
+	}
+	if snap, ok := cache.Get("k"); !ok || snap.Branch != "main" {
+		t.Errorf("Get() = %+v, %v", snap, ok)
+	}
+	if _, ok := cache.Get("other"); ok {
+		t.Error("Get() with another key should miss")
+	}
+	now = now.Add(2 * time.Second)
+	if _, ok := cache.Get("k"); ok {
+		t.Error("Get() after the TTL should miss")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	dir := initTestRepo(t)
+	before, err := Fingerprint(filepath.Join(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub, err := Fingerprint(t.TempDir()); err == nil {
+		t.Errorf("Fingerprint(non-repo) = %q, want error", sub)
+	}
+
+	// Staging rewrites the index.
+	time.Sleep(10 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(dir, "b.go"), []byte("package b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "b.go")
+	after, err := Fingerprint(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before == after {
+		t.Error("Fingerprint() did not change after staging")
+	}
+
+	// Linked worktrees resolve through the .git file.
+	wt := filepath.Join(t.TempDir(), "wt")
+	runGit(t, dir, "worktree", "add", "-q", "-b", "wt", wt)
+	if _, err := Fingerprint(wt); err != nil {
+		t.Errorf("Fingerprint(worktree) error: %v", err)
+	}
+}
+
+func TestSnapshotCache_HitDoesNotRewriteCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snap.json")
+	cache := NewSnapshotCache(path, time.Minute)
+	if err := cache.Put("k", RepoSnapshot{Branch: "main"}); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		if _, ok := cache.Get("k"); !ok {
+			t.Fatal("Get() should hit")
+		}
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Errorf("cache file rewritten on hit:\n%s\nwas\n%s", after, before)
+	}
+	if stats := cache.Stats(); stats.Hits != 3 || stats.Misses != 1 {
+		t.Errorf("Stats() = %+v, want 3 hits and 1 miss", stats)
+	}
+
+	// The next store folds the hits into the cache file.
+	if err := cache.Put("k2", RepoSnapshot{Branch: "dev"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".hits"); !os.IsNotExist(err) {
+		t.Errorf("hits file after Put: %v", err)
+	}
+	if stats := cache.Stats(); stats.Hits != 3 || stats.Misses != 2 {
+		t.Errorf("Stats() after Put = %+v, want 3 hits and 2 misses", stats)
+	}
+}
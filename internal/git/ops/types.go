@@ -17,6 +17,8 @@ const (
 	OpDiff   GitOperationType = "diff"
 	OpRemote GitOperationType = "remote"
 	OpConfig GitOperationType = "config"
+
+	OpRevList GitOperationType = "rev-list"
 )
 
 // GitCommand represents a Git command specification.
@@ -3,4 +3,8 @@
 // It wraps the gh CLI binary (https://cli.github.com/) via os/exec to provide
 // pull request creation, review, merge, and CI/CD status checking. All GitHub
 // interactions are abstracted behind testable interfaces.
+//
+// The pull request, issue and check types are aliases of the provider
+// neutral types of package forge, and NewForge serves GitHub as a
+// forge.Forge.
 package github
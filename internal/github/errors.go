@@ -28,6 +28,9 @@ var (
 	// ErrCIFailed indicates CI/CD checks failed.
 	ErrCIFailed = errors.New("github: CI/CD checks failed")
 
+	// ErrChecksPending indicates CI/CD checks did not finish in time.
+	ErrChecksPending = errors.New("github: CI/CD checks still pending")
+
 	// ErrReviewRequired indicates PR review has not been approved.
 	ErrReviewRequired = errors.New("github: review approval required")
 
@@ -0,0 +1,30 @@
+package github
+
+import "github.com/modu-ai/moai-adk/internal/forge"
+
+// ghForge serves GitHub as a forge.Forge through the gh-backed clients.
+type ghForge struct {
+	GHClient
+	IssueParser
+	IssueCloser
+}
+
+// Compile-time interface compliance check.
+var _ forge.Forge = (*ghForge)(nil)
+
+// NewForge returns a forge.Forge that runs gh commands in root through fn.
+// A nil fn uses the gh binary.
+func NewForge(root string, fn ExecFunc) forge.Forge {
+	var closerOpts []IssueCloserOption
+	if fn != nil {
+		closerOpts = append(closerOpts, WithExecFunc(fn))
+	}
+	return &ghForge{
+		GHClient:    NewGHClientWithExec(root, fn),
+		IssueParser: NewIssueParserWithExec(root, fn),
+		IssueCloser: NewIssueCloser(root, closerOpts...),
+	}
+}
+
+// Provider returns forge.ProviderGitHub.
+func (*ghForge) Provider() string { return forge.ProviderGitHub }
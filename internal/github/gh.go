@@ -11,6 +11,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/modu-ai/moai-adk/internal/forge"
 )
 
 // ghBin caches the resolved gh binary path to avoid repeated exec.LookPath calls.
@@ -28,45 +30,47 @@ var (
 )
 
 // CheckConclusion represents the overall CI/CD check result.
-type CheckConclusion string
+type CheckConclusion = forge.CheckConclusion
 
 const (
 	// CheckPass indicates all CI/CD checks passed.
-	CheckPass CheckConclusion = "pass"
+	CheckPass = forge.CheckPass
 
 	// CheckFail indicates one or more CI/CD checks failed.
-	CheckFail CheckConclusion = "fail"
+	CheckFail = forge.CheckFail
 
 	// CheckPending indicates CI/CD checks are still running.
-	CheckPending CheckConclusion = "pending"
+	CheckPending = forge.CheckPending
 )
 
 // MergeMethod represents the Git merge strategy for a PR.
-type MergeMethod string
+type MergeMethod = forge.MergeMethod
 
 const (
 	// MergeMethodMerge creates a merge commit.
-	MergeMethodMerge MergeMethod = "merge"
+	MergeMethodMerge = forge.MergeMethodMerge
 
 	// MergeMethodSquash squashes all commits into one.
-	MergeMethodSquash MergeMethod = "squash"
+	MergeMethodSquash = forge.MergeMethodSquash
 
 	// MergeMethodRebase rebases commits onto the base branch.
-	MergeMethodRebase MergeMethod = "rebase"
+	MergeMethodRebase = forge.MergeMethodRebase
 )
 
 // PRCreateOptions holds parameters for creating a pull request.
-type PRCreateOptions struct {
-	Title       string
-	Body        string
-	BaseBranch  string
-	HeadBranch  string
-	Labels      []string
-	IssueNumber int
-}
+type PRCreateOptions = forge.ChangeRequestOptions
 
 // PRDetails holds information about an existing pull request.
-type PRDetails struct {
+type PRDetails = forge.ChangeRequest
+
+// Check represents a single CI/CD status check.
+type Check = forge.Check
+
+// CheckStatus holds the aggregated CI/CD check results for a PR.
+type CheckStatus = forge.CheckStatus
+
+// ghPRView is the JSON output of gh pr view.
+type ghPRView struct {
 	Number     int       `json:"number"`
 	Title      string    `json:"title"`
 	State      string    `json:"state"`
@@ -77,19 +81,13 @@ type PRDetails struct {
 	CreatedAt  time.Time `json:"createdAt"`
 }
 
-// Check represents a single CI/CD status check.
-type Check struct {
+// ghCheck is an entry of the JSON output of gh pr checks.
+type ghCheck struct {
 	Name       string `json:"name"`
 	Status     string `json:"status"`
 	Conclusion string `json:"conclusion"`
 }
 
-// CheckStatus holds the aggregated CI/CD check results for a PR.
-type CheckStatus struct {
-	Overall CheckConclusion
-	Checks  []Check
-}
-
 // GHClient abstracts GitHub CLI (gh) operations for testability.
 type GHClient interface {
 	// PRCreate creates a pull request and returns the PR number.
@@ -105,6 +103,9 @@ type GHClient interface {
 	// PRChecks returns the CI/CD check status for a PR.
 	PRChecks(ctx context.Context, number int) (*CheckStatus, error)
 
+	// PRReview submits a review with the given decision and body.
+	PRReview(ctx context.Context, number int, decision ReviewDecision, body string) error
+
 	// Push pushes the current branch to the remote.
 	Push(ctx context.Context, dir string) error
 
@@ -143,6 +144,12 @@ func NewGHClient(root string) *ghClient {
 	}
 }
 
+// NewGHClientWithExec creates a GitHub CLI client that runs gh commands
+// through fn. A nil fn uses the gh binary.
+func NewGHClientWithExec(root string, fn ExecFunc) *ghClient {
+	return newGHClientWithExec(root, execFunc(fn))
+}
+
 // newGHClientWithExec creates a ghClient with a custom exec function for testing.
 func newGHClientWithExec(root string, fn execFunc) *ghClient {
 	return &ghClient{
@@ -228,11 +235,12 @@ func (c *ghClient) PRView(ctx context.Context, number int) (*PRDetails, error) {
 		return nil, fmt.Errorf("view PR #%d: %w", number, err)
 	}
 
-	var details PRDetails
-	if err := json.Unmarshal([]byte(output), &details); err != nil {
+	var view ghPRView
+	if err := json.Unmarshal([]byte(output), &view); err != nil {
 		return nil, fmt.Errorf("parse PR #%d JSON: %w", number, err)
 	}
 
+	details := PRDetails(view)
 	return &details, nil
 }
 
@@ -283,10 +291,14 @@ func (c *ghClient) PRChecks(ctx context.Context, number int) (*CheckStatus, erro
 		return nil, fmt.Errorf("checks PR #%d: %w", number, err)
 	}
 
-	var checks []Check
-	if err := json.Unmarshal([]byte(output), &checks); err != nil {
+	var ghChecks []ghCheck
+	if err := json.Unmarshal([]byte(output), &ghChecks); err != nil {
 		return nil, fmt.Errorf("parse checks JSON for PR #%d: %w", number, err)
 	}
+	checks := make([]Check, len(ghChecks))
+	for i, c := range ghChecks {
+		checks[i] = Check(c)
+	}
 
 	status := &CheckStatus{
 		Overall: deriveOverallConclusion(checks),
@@ -296,6 +308,34 @@ func (c *ghClient) PRChecks(ctx context.Context, number int) (*CheckStatus, erro
 	return status, nil
 }
 
+// PRReview submits a pull request review. GitHub does not allow authors to
+// approve their own pull requests; such an approval is posted as a comment.
+func (c *ghClient) PRReview(ctx context.Context, number int, decision ReviewDecision, body string) error {
+	var flag string
+	switch decision {
+	case ReviewApprove:
+		flag = "--approve"
+	case ReviewRequestChanges:
+		flag = "--request-changes"
+	case ReviewComment:
+		flag = "--comment"
+	default:
+		return fmt.Errorf("review PR #%d: unsupported decision %q", number, decision)
+	}
+
+	c.logger.Debug("submitting pull request review", "number", number, "decision", decision)
+
+	_, err := c.exec(ctx, "pr", "review", strconv.Itoa(number), flag, "--body", body)
+	if err != nil && decision != ReviewComment && strings.Contains(err.Error(), "own pull request") {
+		c.logger.Info("cannot review own pull request, posting as comment", "number", number)
+		_, err = c.exec(ctx, "pr", "review", strconv.Itoa(number), "--comment", "--body", body)
+	}
+	if err != nil {
+		return fmt.Errorf("review PR #%d: %w", number, err)
+	}
+	return nil
+}
+
 // Push pushes the current branch to the remote repository.
 func (c *ghClient) Push(ctx context.Context, dir string) error {
 	workDir := dir
@@ -164,7 +164,7 @@ func TestGHClient_PRCreate_BadURL(t *testing.T) {
 func TestGHClient_PRView_Success(t *testing.T) {
 	t.Parallel()
 
-	prJSON, _ := json.Marshal(PRDetails{
+	prJSON, _ := json.Marshal(ghPRView{
 		Number:     123,
 		Title:      "Test PR",
 		State:      "OPEN",
@@ -192,6 +192,9 @@ func TestGHClient_PRView_Success(t *testing.T) {
 	if details.Mergeable != "MERGEABLE" {
 		t.Errorf("PRView() Mergeable = %q, want %q", details.Mergeable, "MERGEABLE")
 	}
+	if details.HeadBranch != "feature/test" || details.BaseBranch != "main" {
+		t.Errorf("PRView() branches = %q <- %q, want main <- feature/test", details.BaseBranch, details.HeadBranch)
+	}
 }
 
 // TestGHClient_PRView_NotFound tests PR view when PR is not found.
@@ -381,7 +384,7 @@ func TestGHClient_PRMerge_ExecError(t *testing.T) {
 func TestGHClient_PRChecks_Success(t *testing.T) {
 	t.Parallel()
 
-	checks := []Check{
+	checks := []ghCheck{
 		{Name: "build", Status: "completed", Conclusion: "success"},
 		{Name: "test", Status: "completed", Conclusion: "success"},
 	}
@@ -469,7 +472,7 @@ func TestGHClient_PRChecks_ExecError(t *testing.T) {
 func TestGHClient_PRChecks_FailedChecks(t *testing.T) {
 	t.Parallel()
 
-	checks := []Check{
+	checks := []ghCheck{
 		{Name: "test", Status: "completed", Conclusion: "failure"},
 	}
 	checksJSON, _ := json.Marshal(checks)
@@ -636,3 +639,75 @@ func TestNewGHClientWithPush(t *testing.T) {
 		t.Error("pushFn was not called")
 	}
 }
+
+// TestGHClient_PRReview tests review flags and the own-PR comment fallback.
+func TestGHClient_PRReview(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		decision ReviewDecision
+		ownPR    bool
+		wantArgs [][]string
+	}{
+		{
+			name:     "approve",
+			decision: ReviewApprove,
+			wantArgs: [][]string{{"pr", "review", "7", "--approve", "--body", "lgtm"}},
+		},
+		{
+			name:     "request changes",
+			decision: ReviewRequestChanges,
+			wantArgs: [][]string{{"pr", "review", "7", "--request-changes", "--body", "lgtm"}},
+		},
+		{
+			name:     "own pull request",
+			decision: ReviewApprove,
+			ownPR:    true,
+			wantArgs: [][]string{
+				{"pr", "review", "7", "--approve", "--body", "lgtm"},
+				{"pr", "review", "7", "--comment", "--body", "lgtm"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls [][]string
+			client := newTestGHClient(func(_ context.Context, _ string, args ...string) (string, error) {
+				calls = append(calls, args)
+				if tt.ownPR && slices.Contains(args, "--approve") {
+					return "", errors.New("Can not approve your own pull request")
+				}
+				return "", nil
+			})
+
+			if err := client.PRReview(context.Background(), 7, tt.decision, "lgtm"); err != nil {
+				t.Fatalf("PRReview() error = %v", err)
+			}
+			if len(calls) != len(tt.wantArgs) {
+				t.Fatalf("PRReview() calls = %v, want %v", calls, tt.wantArgs)
+			}
+			for i := range calls {
+				if !slices.Equal(calls[i], tt.wantArgs[i]) {
+					t.Errorf("call %d args = %v, want %v", i, calls[i], tt.wantArgs[i])
+				}
+			}
+		})
+	}
+}
+
+// TestGHClient_PRReview_InvalidDecision tests an unsupported review decision.
+func TestGHClient_PRReview_InvalidDecision(t *testing.T) {
+	t.Parallel()
+
+	client := newTestGHClient(func(_ context.Context, _ string, _ ...string) (string, error) {
+		t.Error("gh should not be called")
+		return "", nil
+	})
+
+	if err := client.PRReview(context.Background(), 7, "DISMISS", "body"); err == nil {
+		t.Fatal("PRReview() expected error for unsupported decision, got nil")
+	}
+}
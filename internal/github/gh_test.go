@@ -14,6 +14,7 @@ type mockGHClient struct {
 	prMergeErr     error
 	prChecksResult *CheckStatus
 	prChecksErr    error
+	prReviewErr    error
 	pushErr        error
 	authErr        error
 
@@ -26,6 +27,8 @@ type mockGHClient struct {
 	pushDir             string
 	prViewCallCount     int
 	prChecksCallCount   int
+	prReviewDecision    ReviewDecision
+	prReviewBody        string
 }
 
 func (m *mockGHClient) PRCreate(_ context.Context, _ PRCreateOptions) (int, error) {
@@ -50,6 +53,12 @@ func (m *mockGHClient) PRChecks(_ context.Context, _ int) (*CheckStatus, error)
 	return m.prChecksResult, m.prChecksErr
 }
 
+func (m *mockGHClient) PRReview(_ context.Context, _ int, decision ReviewDecision, body string) error {
+	m.prReviewDecision = decision
+	m.prReviewBody = body
+	return m.prReviewErr
+}
+
 func (m *mockGHClient) Push(_ context.Context, dir string) error {
 	m.pushCalled = true
 	m.pushDir = dir
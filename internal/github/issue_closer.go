@@ -6,6 +6,8 @@ import (
 	"log/slog"
 	"strconv"
 	"time"
+
+	"github.com/modu-ai/moai-adk/internal/forge"
 )
 
 // ExecFunc is the function signature for executing gh CLI commands.
@@ -13,19 +15,7 @@ import (
 type ExecFunc func(ctx context.Context, dir string, args ...string) (string, error)
 
 // CloseResult holds the outcome of an issue closure operation.
-type CloseResult struct {
-	// IssueNumber is the GitHub issue that was processed.
-	IssueNumber int
-
-	// CommentPosted indicates whether the success comment was posted.
-	CommentPosted bool
-
-	// LabelAdded indicates whether the resolved label was added.
-	LabelAdded bool
-
-	// IssueClosed indicates whether the issue was closed.
-	IssueClosed bool
-}
+type CloseResult = forge.CloseResult
 
 // IssueCloser posts a success comment, adds a resolved label,
 // and closes a GitHub issue.
@@ -6,42 +6,62 @@ import (
 	"fmt"
 	"strconv"
 	"time"
+
+	"github.com/modu-ai/moai-adk/internal/forge"
 )
 
 // Issue represents a parsed GitHub issue.
-type Issue struct {
-	Number   int       `json:"number"`
-	Title    string    `json:"title"`
-	Body     string    `json:"body"`
-	Labels   []Label   `json:"labels"`
-	Author   Author    `json:"author"`
-	Comments []Comment `json:"comments"`
-}
+type Issue = forge.Issue
 
 // Label represents a GitHub issue label.
-type Label struct {
-	Name string `json:"name"`
-}
+type Label = forge.Label
 
 // Author represents a GitHub user.
-type Author struct {
-	Login string `json:"login"`
-}
+type Author = forge.Author
 
 // Comment represents a GitHub issue comment.
-type Comment struct {
-	Body      string    `json:"body"`
-	Author    Author    `json:"author"`
-	CreatedAt time.Time `json:"createdAt"`
+type Comment = forge.Comment
+
+// ghIssue is the JSON output of gh issue view.
+type ghIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Author   ghAuthor `json:"author"`
+	Comments []struct {
+		Body      string    `json:"body"`
+		Author    ghAuthor  `json:"author"`
+		CreatedAt time.Time `json:"createdAt"`
+	} `json:"comments"`
+}
+
+// ghAuthor is a user reference in gh JSON output.
+type ghAuthor struct {
+	Login string `json:"login"`
 }
 
-// LabelNames returns a string slice of label names for this issue.
-func (i *Issue) LabelNames() []string {
-	names := make([]string, len(i.Labels))
-	for idx, l := range i.Labels {
-		names[idx] = l.Name
+// issue converts the gh output to an Issue.
+func (gi *ghIssue) issue() *Issue {
+	issue := &Issue{
+		Number: gi.Number,
+		Title:  gi.Title,
+		Body:   gi.Body,
+		Author: Author(gi.Author),
+	}
+	for _, l := range gi.Labels {
+		issue.Labels = append(issue.Labels, Label(l))
 	}
-	return names
+	for _, c := range gi.Comments {
+		issue.Comments = append(issue.Comments, Comment{
+			Body:      c.Body,
+			Author:    Author(c.Author),
+			CreatedAt: c.CreatedAt,
+		})
+	}
+	return issue
 }
 
 // IssueParser parses GitHub issues.
@@ -64,6 +84,12 @@ func NewIssueParser(root string) IssueParser {
 	return &ghIssueParser{root: root}
 }
 
+// NewIssueParserWithExec returns an IssueParser that runs gh commands
+// through fn. A nil fn uses the gh binary.
+func NewIssueParserWithExec(root string, fn ExecFunc) IssueParser {
+	return newIssueParserWithExec(root, execFunc(fn))
+}
+
 // newIssueParserWithExec creates a ghIssueParser with a custom exec function for testing.
 func newIssueParserWithExec(root string, fn execFunc) IssueParser {
 	return &ghIssueParser{root: root, execFn: fn}
@@ -90,27 +116,27 @@ func (p *ghIssueParser) ParseIssue(ctx context.Context, number int) (*Issue, err
 		return nil, fmt.Errorf("parse issue #%d: %w", number, err)
 	}
 
-	var issue Issue
-	if err := json.Unmarshal([]byte(output), &issue); err != nil {
+	var gi ghIssue
+	if err := json.Unmarshal([]byte(output), &gi); err != nil {
 		return nil, fmt.Errorf("parse issue #%d: unmarshal: %w", number, err)
 	}
 
-	if issue.Title == "" {
+	if gi.Title == "" {
 		return nil, fmt.Errorf("parse issue #%d: empty title", number)
 	}
 
-	return &issue, nil
+	return gi.issue(), nil
 }
 
 // ParseIssueFromJSON parses an Issue from raw JSON bytes.
 // Useful for testing and offline processing.
 func ParseIssueFromJSON(data []byte) (*Issue, error) {
-	var issue Issue
-	if err := json.Unmarshal(data, &issue); err != nil {
+	var gi ghIssue
+	if err := json.Unmarshal(data, &gi); err != nil {
 		return nil, fmt.Errorf("parse issue JSON: %w", err)
 	}
-	if issue.Title == "" {
+	if gi.Title == "" {
 		return nil, fmt.Errorf("parse issue JSON: empty title")
 	}
-	return &issue, nil
+	return gi.issue(), nil
 }
@@ -235,3 +235,36 @@ func (m *prMerger) CheckPrerequisites(ctx context.Context, prNumber int, opts Me
 
 	return check, nil
 }
+
+// DefaultCheckPollInterval is how often WaitForChecks polls CI/CD status.
+const DefaultCheckPollInterval = 15 * time.Second
+
+// WaitForChecks polls the CI/CD status of a PR until no check is pending.
+// It stops when ctx is done, returning the last status with ErrChecksPending.
+func WaitForChecks(ctx context.Context, gh GHClient, prNumber int, interval time.Duration) (*CheckStatus, error) {
+	if gh == nil {
+		return nil, ErrNilGHClient
+	}
+	if interval <= 0 {
+		interval = DefaultCheckPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status, err := gh.PRChecks(ctx, prNumber)
+		if err != nil {
+			return nil, fmt.Errorf("wait for checks on PR #%d: %w", prNumber, err)
+		}
+		if status.Overall != CheckPending {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, fmt.Errorf("wait for checks on PR #%d: %w: %w", prNumber, ErrChecksPending, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
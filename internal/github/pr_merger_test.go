@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/modu-ai/moai-adk/internal/core/quality"
 )
@@ -556,3 +557,43 @@ func TestCheckPrerequisites_UnknownMergeability(t *testing.T) {
 		t.Error("Mergeable = false, want true (UNKNOWN treated as potentially mergeable)")
 	}
 }
+
+func TestWaitForChecks(t *testing.T) {
+	t.Parallel()
+
+	polls := 0
+	client := newTestGHClient(func(_ context.Context, _ string, _ ...string) (string, error) {
+		polls++
+		if polls < 3 {
+			return `[{"name":"test","status":"in_progress","conclusion":""}]`, nil
+		}
+		return `[{"name":"test","status":"completed","conclusion":"success"}]`, nil
+	})
+
+	status, err := WaitForChecks(context.Background(), client, 42, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForChecks() error = %v", err)
+	}
+	if status.Overall != CheckPass || polls != 3 {
+		t.Errorf("WaitForChecks() = %s after %d polls, want pass after 3", status.Overall, polls)
+	}
+}
+
+func TestWaitForChecks_Timeout(t *testing.T) {
+	t.Parallel()
+
+	client := newTestGHClient(func(_ context.Context, _ string, _ ...string) (string, error) {
+		return `[{"name":"test","status":"queued","conclusion":""}]`, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	status, err := WaitForChecks(ctx, client, 42, 5*time.Millisecond)
+	if !errors.Is(err, ErrChecksPending) {
+		t.Fatalf("WaitForChecks() error = %v, want ErrChecksPending", err)
+	}
+	if status == nil || status.Overall != CheckPending {
+		t.Errorf("WaitForChecks() status = %+v, want pending", status)
+	}
+}
@@ -7,20 +7,21 @@ import (
 	"strings"
 
 	"github.com/modu-ai/moai-adk/internal/core/quality"
+	"github.com/modu-ai/moai-adk/internal/forge"
 )
 
 // ReviewDecision represents the outcome of a PR review.
-type ReviewDecision string
+type ReviewDecision = forge.ReviewDecision
 
 const (
 	// ReviewApprove indicates the PR passes all quality checks.
-	ReviewApprove ReviewDecision = "APPROVE"
+	ReviewApprove = forge.ReviewApprove
 
 	// ReviewRequestChanges indicates the PR requires changes.
-	ReviewRequestChanges ReviewDecision = "REQUEST_CHANGES"
+	ReviewRequestChanges = forge.ReviewRequestChanges
 
 	// ReviewComment indicates the review has observations but no blocking issues.
-	ReviewComment ReviewDecision = "COMMENT"
+	ReviewComment = forge.ReviewComment
 )
 
 // ReviewReport holds the results of an automated PR review.
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -176,3 +179,29 @@ func (l *fileSpecLinker) ListMappings() []SpecMapping {
 	copy(result, l.registry.Mappings)
 	return result
 }
+
+// issueBranchPattern matches issue branches such as "fix/issue-123".
+var issueBranchPattern = regexp.MustCompile(`(?:^|/)issue-(\d+)$`)
+
+// ResolveLinkedIssue finds the issue a merged PR resolves. The SPEC ID, or
+// one derived from a "feature/SPEC-..." head branch, is looked up in the
+// registry first; an issue branch such as "fix/issue-123" is used as a
+// fallback. Returns 0 when no issue is linked.
+func ResolveLinkedIssue(linker SpecLinker, specID, headBranch string) int {
+	if specID == "" {
+		if name := headBranch[strings.LastIndex(headBranch, "/")+1:]; strings.HasPrefix(name, "SPEC-") {
+			specID = name
+		}
+	}
+	if specID != "" && linker != nil {
+		if issue, err := linker.GetLinkedIssue(specID); err == nil {
+			return issue
+		}
+	}
+	if m := issueBranchPattern.FindStringSubmatch(headBranch); m != nil {
+		if issue, err := strconv.Atoi(m[1]); err == nil {
+			return issue
+		}
+	}
+	return 0
+}
@@ -341,3 +341,30 @@ func TestSpecLinker_ListMappings_EmptyRegistry(t *testing.T) {
 		t.Errorf("ListMappings len = %d, want 0", len(mappings))
 	}
 }
+
+func TestResolveLinkedIssue(t *testing.T) {
+	linker, _ := setupLinker(t)
+	if err := linker.LinkIssueToSpec(42, "SPEC-AUTH-001"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		specID string
+		branch string
+		want   int
+	}{
+		{"explicit SPEC", "SPEC-AUTH-001", "main", 42},
+		{"SPEC branch", "", "feature/SPEC-AUTH-001", 42},
+		{"issue branch", "", "fix/issue-123", 123},
+		{"unlinked SPEC falls back to branch", "SPEC-OTHER-001", "feat/issue-7", 7},
+		{"nothing linked", "", "feature/login", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveLinkedIssue(linker, tt.specID, tt.branch); got != tt.want {
+				t.Errorf("ResolveLinkedIssue(%q, %q) = %d, want %d", tt.specID, tt.branch, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package gitlab serves GitLab as a forge.Forge through the GitLab REST API
+// v4. Merge requests are the change requests of package forge, and the jobs
+// of a merge request's latest pipeline are its checks.
+package gitlab
@@ -0,0 +1,37 @@
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors for the gitlab package.
+var (
+	// ErrTokenMissing indicates no GitLab access token is configured.
+	ErrTokenMissing = errors.New("gitlab: GITLAB_TOKEN is not set")
+
+	// ErrProjectNotDetected indicates the GitLab project could not be derived
+	// from the origin remote.
+	ErrProjectNotDetected = errors.New("gitlab: cannot detect project from git remote")
+)
+
+// APIError is a non-success response from the GitLab REST API.
+type APIError struct {
+	// Method and Path identify the request.
+	Method string
+	Path   string
+
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+
+	// Message is the error message reported by the API, if any.
+	Message string
+}
+
+// Error returns a human-readable description of the API failure.
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("gitlab: %s %s: status %d", e.Method, e.Path, e.StatusCode)
+	}
+	return fmt.Sprintf("gitlab: %s %s: status %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
@@ -0,0 +1,519 @@
+package gitlab
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/forge"
+)
+
+// DefaultURL is used when gitlab.instance_url is not configured.
+const DefaultURL = "https://gitlab.com"
+
+// Config configures a GitLab client.
+type Config struct {
+	// InstanceURL is the GitLab instance (e.g. "https://gitlab.company.com").
+	// Empty uses DefaultURL.
+	InstanceURL string
+
+	// Project is the project path (e.g. "group/app"). Empty derives it from
+	// the origin remote of Root.
+	Project string
+
+	// Token is sent as PRIVATE-TOKEN on every request.
+	Token string
+
+	// Root is the repository root used for git commands.
+	Root string
+
+	// HTTPClient is used for API requests. Nil uses a client with a 30s timeout.
+	HTTPClient *http.Client
+}
+
+// Client implements forge.Forge using the GitLab REST API v4.
+type Client struct {
+	apiURL  string
+	project string // URL-escaped project path
+	token   string
+	root    string
+	client  *http.Client
+	logger  *slog.Logger
+}
+
+// Compile-time interface compliance check.
+var _ forge.Forge = (*Client)(nil)
+
+// New creates a GitLab client for the configured project.
+// Returns ErrTokenMissing if no token is configured.
+func New(ctx context.Context, cfg Config) (*Client, error) {
+	if cfg.Token == "" {
+		return nil, ErrTokenMissing
+	}
+	project := cfg.Project
+	if project == "" {
+		remote, err := gitOutput(ctx, cfg.Root, "remote", "get-url", "origin")
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrProjectNotDetected, err)
+		}
+		if project, err = ProjectFromRemote(remote); err != nil {
+			return nil, err
+		}
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{
+		apiURL:  strings.TrimRight(cmp.Or(cfg.InstanceURL, DefaultURL), "/") + "/api/v4",
+		project: url.PathEscape(project),
+		token:   cfg.Token,
+		root:    cfg.Root,
+		client:  client,
+		logger:  slog.Default().With("module", "gitlab"),
+	}, nil
+}
+
+// Provider returns forge.ProviderGitLab.
+func (*Client) Provider() string { return forge.ProviderGitLab }
+
+// glUser is a GitLab user reference.
+type glUser struct {
+	Username string `json:"username"`
+}
+
+// glIssue is the subset of a GitLab issue used by ParseIssue.
+type glIssue struct {
+	IID         int      `json:"iid"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Labels      []string `json:"labels"`
+	Author      glUser   `json:"author"`
+}
+
+// glNote is a comment on an issue or merge request.
+type glNote struct {
+	Body      string    `json:"body"`
+	System    bool      `json:"system"`
+	Author    glUser    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// glMergeRequest is the subset of a GitLab merge request used here.
+type glMergeRequest struct {
+	IID                 int       `json:"iid"`
+	Title               string    `json:"title"`
+	State               string    `json:"state"`
+	DetailedMergeStatus string    `json:"detailed_merge_status"`
+	HasConflicts        bool      `json:"has_conflicts"`
+	SourceBranch        string    `json:"source_branch"`
+	TargetBranch        string    `json:"target_branch"`
+	WebURL              string    `json:"web_url"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// glPipeline is a CI pipeline of a merge request.
+type glPipeline struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+// glJob is a job of a CI pipeline.
+type glJob struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// IsAuthenticated checks that the token is accepted by the instance.
+func (g *Client) IsAuthenticated(ctx context.Context) error {
+	if err := g.do(ctx, http.MethodGet, "/user", nil, nil); err != nil {
+		if hasStatus(err, http.StatusUnauthorized, http.StatusForbidden) {
+			return fmt.Errorf("check auth: %w", forge.ErrNotAuthenticated)
+		}
+		return fmt.Errorf("check auth: %w", err)
+	}
+	return nil
+}
+
+// ParseIssue fetches an issue and its user comments.
+func (g *Client) ParseIssue(ctx context.Context, number int) (*forge.Issue, error) {
+	if number <= 0 {
+		return nil, fmt.Errorf("parse issue: invalid issue number %d", number)
+	}
+
+	var gi glIssue
+	if err := g.do(ctx, http.MethodGet, g.projectPath("/issues/%d", number), nil, &gi); err != nil {
+		if hasStatus(err, http.StatusNotFound) {
+			return nil, fmt.Errorf("parse issue #%d: %w", number, forge.ErrIssueNotFound)
+		}
+		return nil, fmt.Errorf("parse issue #%d: %w", number, err)
+	}
+	if gi.Title == "" {
+		return nil, fmt.Errorf("parse issue #%d: empty title", number)
+	}
+
+	var notes []glNote
+	if err := g.do(ctx, http.MethodGet,
+		g.projectPath("/issues/%d/notes?sort=asc&order_by=created_at&per_page=100", number), nil, &notes); err != nil {
+		return nil, fmt.Errorf("parse issue #%d: notes: %w", number, err)
+	}
+
+	issue := &forge.Issue{
+		Number: gi.IID,
+		Title:  gi.Title,
+		Body:   gi.Description,
+		Author: forge.Author{Login: gi.Author.Username},
+	}
+	for _, l := range gi.Labels {
+		issue.Labels = append(issue.Labels, forge.Label{Name: l})
+	}
+	for _, n := range notes {
+		// System notes record events such as label changes, not discussion.
+		if n.System {
+			continue
+		}
+		issue.Comments = append(issue.Comments, forge.Comment{
+			Body:      n.Body,
+			Author:    forge.Author{Login: n.Author.Username},
+			CreatedAt: n.CreatedAt,
+		})
+	}
+	return issue, nil
+}
+
+// PRCreate opens a merge request and returns its IID. An empty head branch
+// uses the current branch; an empty base branch uses the project default.
+func (g *Client) PRCreate(ctx context.Context, opts forge.ChangeRequestOptions) (int, error) {
+	source := opts.HeadBranch
+	if source == "" {
+		branch, err := gitOutput(ctx, g.root, "rev-parse", "--abbrev-ref", "HEAD")
+		if err != nil {
+			return 0, fmt.Errorf("create MR: current branch: %w", err)
+		}
+		source = branch
+	}
+	target := opts.BaseBranch
+	if target == "" {
+		var project struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := g.do(ctx, http.MethodGet, g.projectPath(""), nil, &project); err != nil {
+			return 0, fmt.Errorf("create MR: default branch: %w", err)
+		}
+		target = project.DefaultBranch
+	}
+
+	body := map[string]any{
+		"source_branch": source,
+		"target_branch": target,
+		"title":         opts.Title,
+		"description":   opts.Body,
+	}
+	if len(opts.Labels) > 0 {
+		body["labels"] = strings.Join(opts.Labels, ",")
+	}
+
+	g.logger.Debug("creating merge request", "title", opts.Title, "source", source, "target", target)
+
+	var mr glMergeRequest
+	if err := g.do(ctx, http.MethodPost, g.projectPath("/merge_requests"), body, &mr); err != nil {
+		if hasStatus(err, http.StatusConflict) {
+			return 0, fmt.Errorf("create MR: %w", forge.ErrChangeRequestExists)
+		}
+		return 0, fmt.Errorf("create MR: %w", err)
+	}
+
+	g.logger.Info("merge request created", "iid", mr.IID, "title", opts.Title)
+	return mr.IID, nil
+}
+
+// PRView retrieves a merge request, mapping its state and merge status onto
+// the forge vocabulary (OPEN/MERGED/CLOSED, MERGEABLE/CONFLICTING/UNKNOWN).
+func (g *Client) PRView(ctx context.Context, number int) (*forge.ChangeRequest, error) {
+	mr, err := g.mergeRequest(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("view MR !%d: %w", number, err)
+	}
+
+	details := &forge.ChangeRequest{
+		Number:     mr.IID,
+		Title:      mr.Title,
+		HeadBranch: mr.SourceBranch,
+		BaseBranch: mr.TargetBranch,
+		URL:        mr.WebURL,
+		CreatedAt:  mr.CreatedAt,
+	}
+	switch mr.State {
+	case "opened":
+		details.State = "OPEN"
+	case "merged":
+		details.State = "MERGED"
+	default:
+		details.State = "CLOSED"
+	}
+	switch {
+	case mr.HasConflicts || mr.DetailedMergeStatus == "conflict":
+		details.Mergeable = "CONFLICTING"
+	case mr.DetailedMergeStatus == "mergeable":
+		details.Mergeable = "MERGEABLE"
+	default:
+		details.Mergeable = "UNKNOWN"
+	}
+	return details, nil
+}
+
+// PRMerge merges a merge request. GitLab applies the project's merge method,
+// so only merge and squash can be requested.
+func (g *Client) PRMerge(ctx context.Context, number int, method forge.MergeMethod, deleteBranch bool) error {
+	var squash bool
+	switch method {
+	case forge.MergeMethodMerge:
+	case forge.MergeMethodSquash:
+		squash = true
+	default:
+		return fmt.Errorf("merge MR !%d: %q: %w", number, method, forge.ErrUnsupportedMergeMethod)
+	}
+
+	g.logger.Debug("merging merge request", "iid", number, "method", method)
+
+	err := g.do(ctx, http.MethodPut, g.projectPath("/merge_requests/%d/merge", number), map[string]any{
+		"squash":                      squash,
+		"should_remove_source_branch": deleteBranch,
+	}, nil)
+	if err != nil {
+		if hasStatus(err, http.StatusNotAcceptable) {
+			return fmt.Errorf("merge MR !%d: %w", number, forge.ErrMergeConflict)
+		}
+		return fmt.Errorf("merge MR !%d: %w", number, err)
+	}
+
+	g.logger.Info("merge request merged", "iid", number, "method", method)
+	return nil
+}
+
+// PRChecks reports the latest pipeline of a merge request, one Check per job.
+// A merge request without pipelines is pending, as on GitHub.
+func (g *Client) PRChecks(ctx context.Context, number int) (*forge.CheckStatus, error) {
+	var pipelines []glPipeline
+	if err := g.do(ctx, http.MethodGet, g.projectPath("/merge_requests/%d/pipelines", number), nil, &pipelines); err != nil {
+		if hasStatus(err, http.StatusNotFound) {
+			return nil, fmt.Errorf("checks MR !%d: %w", number, forge.ErrChangeRequestNotFound)
+		}
+		return nil, fmt.Errorf("checks MR !%d: %w", number, err)
+	}
+	if len(pipelines) == 0 {
+		return &forge.CheckStatus{Overall: forge.CheckPending}, nil
+	}
+
+	// Pipelines are listed newest first.
+	latest := pipelines[0]
+	var jobs []glJob
+	if err := g.do(ctx, http.MethodGet,
+		g.projectPath("/pipelines/%d/jobs?per_page=100", latest.ID), nil, &jobs); err != nil {
+		return nil, fmt.Errorf("checks MR !%d: jobs: %w", number, err)
+	}
+
+	status := &forge.CheckStatus{Overall: pipelineConclusion(latest.Status)}
+	for _, j := range jobs {
+		status.Checks = append(status.Checks, jobCheck(j))
+	}
+	return status, nil
+}
+
+// PRReview approves a merge request when the decision is APPROVE and posts
+// the review body as a note. GitLab rejects approvals by the author; the note
+// is posted regardless.
+func (g *Client) PRReview(ctx context.Context, number int, decision forge.ReviewDecision, body string) error {
+	switch decision {
+	case forge.ReviewApprove:
+		err := g.do(ctx, http.MethodPost, g.projectPath("/merge_requests/%d/approve", number), nil, nil)
+		if err != nil && !hasStatus(err, http.StatusUnauthorized, http.StatusForbidden) {
+			return fmt.Errorf("review MR !%d: approve: %w", number, err)
+		}
+		if err != nil {
+			g.logger.Info("cannot approve merge request, posting review as note", "iid", number)
+		}
+	case forge.ReviewRequestChanges, forge.ReviewComment:
+	default:
+		return fmt.Errorf("review MR !%d: unsupported decision %q", number, decision)
+	}
+
+	if err := g.do(ctx, http.MethodPost, g.projectPath("/merge_requests/%d/notes", number),
+		map[string]any{"body": body}, nil); err != nil {
+		return fmt.Errorf("review MR !%d: %w", number, err)
+	}
+	return nil
+}
+
+// Push pushes the current branch to origin.
+func (g *Client) Push(ctx context.Context, dir string) error {
+	if _, err := gitOutput(ctx, cmp.Or(dir, g.root), "push", "-u", "origin", "HEAD"); err != nil {
+		return fmt.Errorf("push: %w", err)
+	}
+	return nil
+}
+
+// Close posts the comment, adds the "resolved" label and closes the issue.
+// As with the gh-backed closer, a failed label is logged and the issue is
+// still closed.
+func (g *Client) Close(ctx context.Context, issueNumber int, comment string) (*forge.CloseResult, error) {
+	if issueNumber <= 0 {
+		return nil, fmt.Errorf("close issue: invalid issue number %d", issueNumber)
+	}
+	result := &forge.CloseResult{IssueNumber: issueNumber}
+	path := g.projectPath("/issues/%d", issueNumber)
+
+	if err := g.do(ctx, http.MethodPost, path+"/notes", map[string]any{"body": comment}, nil); err != nil {
+		if hasStatus(err, http.StatusNotFound) {
+			err = forge.ErrIssueNotFound
+		}
+		return result, fmt.Errorf("close issue #%d: %w", issueNumber, err)
+	}
+	result.CommentPosted = true
+
+	if err := g.do(ctx, http.MethodPut, path, map[string]any{"add_labels": "resolved"}, nil); err != nil {
+		g.logger.Warn("failed to add resolved label", "issue", issueNumber, "error", err)
+	} else {
+		result.LabelAdded = true
+	}
+
+	if err := g.do(ctx, http.MethodPut, path, map[string]any{"state_event": "close"}, nil); err != nil {
+		return result, fmt.Errorf("close issue #%d: %w", issueNumber, err)
+	}
+	result.IssueClosed = true
+
+	g.logger.Info("issue closed successfully", "issue", issueNumber, "label_added", result.LabelAdded)
+	return result, nil
+}
+
+// mergeRequest fetches a merge request by IID.
+func (g *Client) mergeRequest(ctx context.Context, number int) (*glMergeRequest, error) {
+	var mr glMergeRequest
+	if err := g.do(ctx, http.MethodGet, g.projectPath("/merge_requests/%d", number), nil, &mr); err != nil {
+		if hasStatus(err, http.StatusNotFound) {
+			return nil, forge.ErrChangeRequestNotFound
+		}
+		return nil, err
+	}
+	return &mr, nil
+}
+
+// projectPath returns an API path below the configured project.
+func (g *Client) projectPath(format string, args ...any) string {
+	return "/projects/" + g.project + fmt.Sprintf(format, args...)
+}
+
+// do sends a JSON request to the API and decodes the response into out.
+// Non-2xx responses are returned as *APIError.
+func (g *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, g.apiURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", g.token)
+	req.Header.Set("User-Agent", "moai-adk")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &APIError{
+			Method:     method,
+			Path:       path,
+			StatusCode: resp.StatusCode,
+			Message:    apiMessage(resp.Body),
+		}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// apiMessage extracts the "message" or "error" field of an error response.
+// GitLab reports validation failures as an object or list under "message".
+func apiMessage(r io.Reader) string {
+	var body struct {
+		Message json.RawMessage `json:"message"`
+		Error   string          `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r, 64<<10)).Decode(&body); err != nil {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(body.Message, &s) == nil {
+		return s
+	}
+	return cmp.Or(string(body.Message), body.Error)
+}
+
+// hasStatus reports whether err is an APIError with one of the given codes.
+func hasStatus(err error, codes ...int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, c := range codes {
+		if apiErr.StatusCode == c {
+			return true
+		}
+	}
+	return false
+}
+
+// pipelineConclusion maps a pipeline status to an overall check result.
+func pipelineConclusion(status string) forge.CheckConclusion {
+	switch status {
+	case "success", "skipped":
+		return forge.CheckPass
+	case "failed", "canceled":
+		return forge.CheckFail
+	default:
+		return forge.CheckPending
+	}
+}
+
+// jobCheck maps a pipeline job onto the forge check vocabulary.
+func jobCheck(j glJob) forge.Check {
+	check := forge.Check{Name: j.Name, Status: "completed"}
+	switch j.Status {
+	case "success":
+		check.Conclusion = "success"
+	case "failed":
+		check.Conclusion = "failure"
+	case "canceled":
+		check.Conclusion = "cancelled"
+	case "skipped", "manual":
+		check.Conclusion = "skipped"
+	default:
+		check.Status = "in_progress"
+	}
+	return check
+}
@@ -0,0 +1,289 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modu-ai/moai-adk/internal/forge"
+)
+
+const fakeProject = "/api/v4/projects/group%2Fapp"
+
+// fakeGitLab is an in-memory GitLab serving the endpoints used by Client.
+type fakeGitLab struct {
+	mu        sync.Mutex
+	routes    map[string]string // "METHOD path" -> JSON response
+	status    map[string]int    // "METHOD path" -> status code (default 200)
+	requests  []string
+	bodies    map[string][]map[string]any
+	authToken string
+}
+
+func newFakeGitLab(t *testing.T) (*fakeGitLab, *Client) {
+	t.Helper()
+	f := &fakeGitLab{
+		routes: map[string]string{},
+		status: map[string]int{},
+		bodies: map[string][]map[string]any{},
+	}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	gl, err := New(context.Background(), Config{
+		InstanceURL: srv.URL + "/",
+		Project:     "group/app",
+		Token:       "glpat-test",
+		HTTPClient:  srv.Client(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, gl
+}
+
+func (f *fakeGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := r.URL.EscapedPath()
+	if r.URL.RawQuery != "" {
+		path += "?" + r.URL.RawQuery
+	}
+	key := r.Method + " " + strings.TrimPrefix(path, fakeProject)
+	f.requests = append(f.requests, key)
+	f.authToken = r.Header.Get("PRIVATE-TOKEN")
+
+	if r.Body != nil {
+		var body map[string]any
+		if json.NewDecoder(r.Body).Decode(&body) == nil {
+			f.bodies[key] = append(f.bodies[key], body)
+		}
+	}
+
+	resp, ok := f.routes[key]
+	code := f.status[key]
+	switch {
+	case code != 0:
+		w.WriteHeader(code)
+		if resp == "" {
+			resp = `{"message":"rejected"}`
+		}
+	case !ok:
+		w.WriteHeader(http.StatusNotFound)
+		resp = `{"message":"404 Not found"}`
+	}
+	_, _ = w.Write([]byte(resp))
+}
+
+func TestClient_ParseIssue(t *testing.T) {
+	f, gl := newFakeGitLab(t)
+	f.routes["GET /issues/12"] = `{"iid":12,"title":"Login fails","description":"Steps...","labels":["bug","p1"],"author":{"username":"alice"}}`
+	f.routes["GET /issues/12/notes?sort=asc&order_by=created_at&per_page=100"] = `[
+		{"body":"added ~bug label","system":true,"author":{"username":"alice"}},
+		{"body":"I can reproduce","system":false,"author":{"username":"bob"},"created_at":"2026-01-02T03:04:05Z"}
+	]`
+
+	issue, err := gl.ParseIssue(context.Background(), 12)
+	if err != nil {
+		t.Fatalf("ParseIssue() error = %v", err)
+	}
+	if issue.Number != 12 || issue.Title != "Login fails" || issue.Author.Login != "alice" {
+		t.Errorf("issue = %+v", issue)
+	}
+	if got := issue.LabelNames(); len(got) != 2 || got[0] != "bug" {
+		t.Errorf("labels = %v", got)
+	}
+	if len(issue.Comments) != 1 || issue.Comments[0].Author.Login != "bob" {
+		t.Errorf("comments = %+v, want only the user note", issue.Comments)
+	}
+	if f.authToken != "glpat-test" {
+		t.Errorf("PRIVATE-TOKEN = %q", f.authToken)
+	}
+
+	if _, err := gl.ParseIssue(context.Background(), 99); !errors.Is(err, forge.ErrIssueNotFound) {
+		t.Errorf("missing issue error = %v, want ErrIssueNotFound", err)
+	}
+}
+
+func TestClient_MergeRequestLifecycle(t *testing.T) {
+	f, gl := newFakeGitLab(t)
+	ctx := context.Background()
+
+	f.routes["POST /merge_requests"] = `{"iid":7}`
+	n, err := gl.PRCreate(ctx, forge.ChangeRequestOptions{
+		Title: "Fix login", Body: "Closes #12", HeadBranch: "fix/issue-12", BaseBranch: "main",
+		Labels: []string{"bug", "moai"},
+	})
+	if err != nil || n != 7 {
+		t.Fatalf("PRCreate() = %d, %v", n, err)
+	}
+	body := f.bodies["POST /merge_requests"][0]
+	if body["source_branch"] != "fix/issue-12" || body["target_branch"] != "main" || body["labels"] != "bug,moai" {
+		t.Errorf("create body = %v", body)
+	}
+
+	f.routes["GET /merge_requests/7"] = `{"iid":7,"title":"Fix login","state":"opened","detailed_merge_status":"mergeable",
+		"source_branch":"fix/issue-12","target_branch":"main","web_url":"https://gitlab.example.com/group/app/-/merge_requests/7"}`
+	details, err := gl.PRView(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.State != "OPEN" || details.Mergeable != "MERGEABLE" || details.HeadBranch != "fix/issue-12" {
+		t.Errorf("details = %+v", details)
+	}
+
+	f.routes["PUT /merge_requests/7/merge"] = `{"iid":7,"state":"merged"}`
+	if err := gl.PRMerge(ctx, 7, forge.MergeMethodSquash, true); err != nil {
+		t.Fatal(err)
+	}
+	merge := f.bodies["PUT /merge_requests/7/merge"][0]
+	if merge["squash"] != true || merge["should_remove_source_branch"] != true {
+		t.Errorf("merge body = %v", merge)
+	}
+	if err := gl.PRMerge(ctx, 7, forge.MergeMethodRebase, false); !errors.Is(err, forge.ErrUnsupportedMergeMethod) {
+		t.Errorf("rebase error = %v, want ErrUnsupportedMergeMethod", err)
+	}
+
+	f.status["PUT /merge_requests/8/merge"] = http.StatusNotAcceptable
+	if err := gl.PRMerge(ctx, 8, forge.MergeMethodMerge, false); !errors.Is(err, forge.ErrMergeConflict) {
+		t.Errorf("conflict error = %v, want ErrMergeConflict", err)
+	}
+
+	f.status["POST /merge_requests"] = http.StatusConflict
+	if _, err := gl.PRCreate(ctx, forge.ChangeRequestOptions{Title: "x", HeadBranch: "a", BaseBranch: "main"}); !errors.Is(err, forge.ErrChangeRequestExists) {
+		t.Errorf("duplicate MR error = %v, want ErrPRAlreadyExists", err)
+	}
+}
+
+func TestClient_PRView_States(t *testing.T) {
+	f, gl := newFakeGitLab(t)
+	f.routes["GET /merge_requests/1"] = `{"iid":1,"state":"merged","detailed_merge_status":"not_open"}`
+	f.routes["GET /merge_requests/2"] = `{"iid":2,"state":"opened","has_conflicts":true}`
+
+	d, err := gl.PRView(context.Background(), 1)
+	if err != nil || d.State != "MERGED" || d.Mergeable != "UNKNOWN" {
+		t.Errorf("merged MR = %+v, %v", d, err)
+	}
+	d, err = gl.PRView(context.Background(), 2)
+	if err != nil || d.Mergeable != "CONFLICTING" {
+		t.Errorf("conflicting MR = %+v, %v", d, err)
+	}
+	if _, err := gl.PRView(context.Background(), 3); !errors.Is(err, forge.ErrChangeRequestNotFound) {
+		t.Errorf("missing MR error = %v, want ErrPRNotFound", err)
+	}
+}
+
+func TestClient_PRChecks(t *testing.T) {
+	tests := []struct {
+		name      string
+		pipelines string
+		jobs      string
+		want      forge.CheckConclusion
+		wantJobs  int
+	}{
+		{"no pipeline", `[]`, "", forge.CheckPending, 0},
+		{"passed", `[{"id":50,"status":"success"},{"id":40,"status":"failed"}]`,
+			`[{"name":"lint","status":"success"},{"name":"deploy","status":"manual"}]`, forge.CheckPass, 2},
+		{"failed", `[{"id":50,"status":"failed"}]`, `[{"name":"test","status":"failed"}]`, forge.CheckFail, 1},
+		{"running", `[{"id":50,"status":"running"}]`, `[{"name":"test","status":"running"}]`, forge.CheckPending, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, gl := newFakeGitLab(t)
+			f.routes["GET /merge_requests/7/pipelines"] = tt.pipelines
+			f.routes["GET /pipelines/50/jobs?per_page=100"] = tt.jobs
+
+			status, err := gl.PRChecks(context.Background(), 7)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status.Overall != tt.want || len(status.Checks) != tt.wantJobs {
+				t.Errorf("status = %+v, want %s with %d checks", status, tt.want, tt.wantJobs)
+			}
+		})
+	}
+}
+
+func TestClient_PRReview(t *testing.T) {
+	f, gl := newFakeGitLab(t)
+	ctx := context.Background()
+	f.routes["POST /merge_requests/7/notes"] = `{}`
+	f.routes["POST /merge_requests/7/approve"] = `{}`
+
+	if err := gl.PRReview(ctx, 7, forge.ReviewApprove, "## Review"); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.bodies["POST /merge_requests/7/notes"]) != 1 || !strings.Contains(strings.Join(f.requests, ","), "POST /merge_requests/7/approve") {
+		t.Errorf("requests = %v", f.requests)
+	}
+
+	// Authors cannot approve their own merge requests; the note still lands.
+	f.status["POST /merge_requests/7/approve"] = http.StatusUnauthorized
+	if err := gl.PRReview(ctx, 7, forge.ReviewApprove, "## Review"); err != nil {
+		t.Fatalf("own MR review error = %v", err)
+	}
+	if len(f.bodies["POST /merge_requests/7/notes"]) != 2 {
+		t.Error("review note not posted after rejected approval")
+	}
+
+	if err := gl.PRReview(ctx, 7, forge.ReviewRequestChanges, "fix it"); err != nil {
+		t.Fatal(err)
+	}
+	if f.bodies["POST /merge_requests/7/notes"][2]["body"] != "fix it" {
+		t.Errorf("notes = %v", f.bodies["POST /merge_requests/7/notes"])
+	}
+}
+
+func TestClient_Close(t *testing.T) {
+	f, gl := newFakeGitLab(t)
+	f.routes["POST /issues/12/notes"] = `{}`
+	f.routes["PUT /issues/12"] = `{}`
+
+	result, err := gl.Close(context.Background(), 12, "Resolved by !7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.CommentPosted || !result.LabelAdded || !result.IssueClosed {
+		t.Errorf("result = %+v", result)
+	}
+	edits := f.bodies["PUT /issues/12"]
+	if len(edits) != 2 || edits[0]["add_labels"] != "resolved" || edits[1]["state_event"] != "close" {
+		t.Errorf("issue edits = %v", edits)
+	}
+
+	if _, err := gl.Close(context.Background(), 13, "x"); !errors.Is(err, forge.ErrIssueNotFound) {
+		t.Errorf("missing issue error = %v, want ErrIssueNotFound", err)
+	}
+}
+
+func TestClient_IsAuthenticated(t *testing.T) {
+	f, gl := newFakeGitLab(t)
+	f.routes["GET /api/v4/user"] = `{"username":"alice"}`
+	if err := gl.IsAuthenticated(context.Background()); err != nil {
+		t.Errorf("IsAuthenticated() error = %v", err)
+	}
+
+	f.status["GET /api/v4/user"] = http.StatusUnauthorized
+	err := gl.IsAuthenticated(context.Background())
+	if !errors.Is(err, forge.ErrNotAuthenticated) {
+		t.Errorf("IsAuthenticated() error = %v, want ErrNotAuthenticated", err)
+	}
+}
+
+func TestClient_APIErrorMessage(t *testing.T) {
+	f, gl := newFakeGitLab(t)
+	f.status["POST /merge_requests"] = http.StatusBadRequest
+	f.routes["POST /merge_requests"] = `{"message":{"title":["can't be blank"]}}`
+
+	_, err := gl.PRCreate(context.Background(), forge.ChangeRequestOptions{HeadBranch: "a", BaseBranch: "main"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || !strings.Contains(apiErr.Message, "can't be blank") {
+		t.Errorf("error = %v", err)
+	}
+}
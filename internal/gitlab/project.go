@@ -0,0 +1,50 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ProjectFromRemote extracts the project path from a git remote URL such as
+// "git@gitlab.com:group/app.git" or "https://gitlab.com/group/sub/app.git".
+func ProjectFromRemote(remote string) (string, error) {
+	path := strings.TrimSpace(remote)
+	if i := strings.Index(path, "://"); i >= 0 {
+		// scheme://[user@]host[:port]/path
+		path = path[i+3:]
+		slash := strings.Index(path, "/")
+		if slash < 0 {
+			return "", fmt.Errorf("%q: %w", remote, ErrProjectNotDetected)
+		}
+		path = path[slash+1:]
+	} else if i := strings.Index(path, ":"); i >= 0 {
+		// [user@]host:path
+		path = path[i+1:]
+	} else {
+		return "", fmt.Errorf("%q: %w", remote, ErrProjectNotDetected)
+	}
+	path = strings.Trim(strings.TrimSuffix(path, ".git"), "/")
+	if !strings.Contains(path, "/") {
+		return "", fmt.Errorf("%q: %w", remote, ErrProjectNotDetected)
+	}
+	return path, nil
+}
+
+// gitOutput runs git in dir and returns its trimmed stdout.
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s: %w", args[0], msg, err)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
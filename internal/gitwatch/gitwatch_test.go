@@ -0,0 +1,222 @@
+package gitwatch
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	coregit "github.com/modu-ai/moai-adk/internal/core/git"
+)
+
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "-b", "main")
+	writeFile(t, filepath.Join(dir, "README.md"), "hello\n")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@t"}, args...)...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore(t *testing.T) {
+	s := NewStore(t.TempDir())
+	if events, err := s.Pending(); err != nil || len(events) != 0 {
+		t.Fatalf("Pending() on empty store = %v, %v", events, err)
+	}
+
+	t0 := time.Now()
+	if err := s.Append(Event{Time: t0, Type: coregit.EventNewCommit}, Event{Time: t0.Add(2 * time.Second), Type: EventExternalEdit}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetCursor(t0.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	events, err := s.Pending()
+	if err != nil || len(events) != 1 || events[0].Type != EventExternalEdit {
+		t.Errorf("Pending() = %+v, %v", events, err)
+	}
+
+	if err := s.WritePID(PIDInfo{PID: 42, SessionID: "s1"}); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := s.ReadPID(); err != nil || info.PID != 42 || info.SessionID != "s1" {
+		t.Errorf("ReadPID() = %+v, %v", info, err)
+	}
+	if err := s.RemovePID(); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := s.ReadPID(); err != nil || info != nil {
+		t.Errorf("ReadPID() after remove = %+v, %v", info, err)
+	}
+
+	if err := s.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if events, _ := s.Since(time.Time{}); len(events) != 0 || !s.Cursor().IsZero() {
+		t.Errorf("Reset() left events %+v, cursor %v", events, s.Cursor())
+	}
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	dir := initRepo(t)
+	ctx := context.Background()
+	w := NewWatcher(dir, 0)
+	if err := w.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Edits outside git are recorded with their file names.
+	writeFile(t, filepath.Join(dir, "README.md"), "changed\n")
+	writeFile(t, filepath.Join(dir, "notes.txt"), "new\n")
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// An unchanged tree records nothing more.
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	events, err := w.store.Since(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != EventExternalEdit || !slices.Equal(events[0].Files, []string{"README.md", "notes.txt"}) {
+		t.Fatalf("events = %+v", events)
+	}
+
+	// Commits and branch switches are recorded as git events.
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "feat: update readme")
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "checkout", "-q", "-b", "feature")
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	events, _ = w.store.Since(time.Time{})
+	var types []coregit.EventType
+	for _, ev := range events[1:] {
+		types = append(types, ev.Type)
+	}
+	if !slices.Contains(types, coregit.EventBranchSwitch) || !slices.Contains(types, coregit.EventNewCommit) ||
+		slices.Contains(types, EventExternalEdit) {
+		t.Fatalf("types = %v", types)
+	}
+	for _, ev := range events[1:] {
+		if ev.Type == coregit.EventNewCommit && !slices.Equal(ev.Commits, []string{"feat: update readme"}) {
+			t.Errorf("Commits = %v", ev.Commits)
+		}
+	}
+}
+
+func TestRunning_RemovesStalePID(t *testing.T) {
+	root := t.TempDir()
+	s := NewStore(root)
+	// PID 0 never names a live watcher.
+	if err := s.WritePID(PIDInfo{PID: 0, SessionID: "old"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Running(root); ok {
+		t.Error("Running() = true for a dead process")
+	}
+	if info, _ := s.ReadPID(); info != nil {
+		t.Error("stale PID file was not removed")
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("sleep not available")
+	}
+	root := t.TempDir()
+	s := NewStore(root)
+	if err := s.Append(Event{Time: time.Now(), Type: EventExternalEdit}); err != nil {
+		t.Fatal(err)
+	}
+
+	pid, err := Start(root, "s1", []string{sleep, "30"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Stop(root) })
+	if info, ok := Running(root); !ok || info.PID != pid || info.SessionID != "s1" {
+		t.Fatalf("Running() = %+v, %v", info, ok)
+	}
+	if events, _ := s.Since(time.Time{}); len(events) != 0 {
+		t.Errorf("Start() kept events of a previous session: %+v", events)
+	}
+
+	// The same session keeps its watcher.
+	if again, err := Start(root, "s1", []string{sleep, "30"}); err != nil || again != pid {
+		t.Errorf("Start() again = %d, %v, want %d", again, err, pid)
+	}
+
+	if err := Stop(root); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Running(root); ok {
+		t.Error("Running() = true after Stop()")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Now()
+	events := []Event{
+		{Time: now, Type: coregit.EventBranchSwitch, PreviousBranch: "main", Branch: "feature"},
+		{Time: now, Type: coregit.EventNewCommit, Commits: []string{"fix: b", "feat: a"}},
+		{Time: now, Type: coregit.EventNewCommit, Commits: []string{"docs: c"}},
+		{Time: now, Type: EventExternalEdit, Files: []string{"a.go", "b.go", "c.go"}},
+		{Time: now, Type: EventExternalEdit, Files: []string{"c.go", "d.go", "e.go", "f.go", "g.go"}},
+	}
+	got := Summarize(events)
+	for _, want := range []string{
+		"made outside Claude",
+		"- Switched branch: main → feature",
+		"- 3 new commits\n  - docs: c\n  - fix: b\n  - feat: a",
+		"- Edited 7 files: a.go, b.go, c.go, d.go, e.go, +2 more",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Summarize() missing %q:\n%s", want, got)
+		}
+	}
+	if b := Brief(events); b != "→feature, +3 commits, 7 files edited" {
+		t.Errorf("Brief() = %q", b)
+	}
+
+	// Switching back and forth is not reported.
+	back := []Event{
+		{Type: coregit.EventBranchSwitch, PreviousBranch: "main", Branch: "feature"},
+		{Type: coregit.EventBranchSwitch, PreviousBranch: "feature", Branch: "main"},
+	}
+	if s := Summarize(back); s != "" {
+		t.Errorf("Summarize(round trip) = %q", s)
+	}
+	if s := Brief(nil); s != "" {
+		t.Errorf("Brief(nil) = %q", s)
+	}
+}
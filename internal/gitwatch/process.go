@@ -0,0 +1,87 @@
+package gitwatch
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// Running returns the watcher recorded in the PID file when its process is
+// alive. A PID file left by a dead process is removed.
+func Running(projectRoot string) (*PIDInfo, bool) {
+	store := NewStore(projectRoot)
+	info, err := store.ReadPID()
+	if err != nil || info == nil {
+		if err != nil {
+			// An unreadable PID file cannot name a live watcher.
+			_ = store.RemovePID()
+		}
+		return nil, false
+	}
+	if !processAlive(info.PID) {
+		slog.Debug("removing stale git watcher pid file", "pid", info.PID)
+		_ = store.RemovePID()
+		return nil, false
+	}
+	return info, true
+}
+
+// Start launches command as the watcher of sessionID for the project and
+// records it in the PID file. A watcher already running for the session is
+// kept; one left by another session is stopped and its event log cleared.
+// It returns the PID of the watcher.
+func Start(projectRoot, sessionID string, command []string) (int, error) {
+	if len(command) == 0 {
+		return 0, fmt.Errorf("start git watcher: empty command")
+	}
+	store := NewStore(projectRoot)
+	if info, ok := Running(projectRoot); ok {
+		if info.SessionID == sessionID {
+			return info.PID, nil
+		}
+		if err := Stop(projectRoot); err != nil {
+			return 0, err
+		}
+	}
+	if err := store.Reset(); err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(store.Dir(), 0o755); err != nil {
+		return 0, fmt.Errorf("create git-watch dir: %w", err)
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = projectRoot
+	// No stdio: the hook process must be able to exit while the watcher runs.
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = nil, nil, nil
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("start git watcher: %w", err)
+	}
+	pid := cmd.Process.Pid
+	if err := store.WritePID(PIDInfo{PID: pid, SessionID: sessionID, Started: time.Now()}); err != nil {
+		_ = cmd.Process.Kill()
+		return 0, err
+	}
+	_ = cmd.Process.Release()
+	return pid, nil
+}
+
+// Stop terminates the running watcher of the project, if any, and removes
+// the PID file. The watcher also exits on its own once the PID file no
+// longer names it.
+func Stop(projectRoot string) error {
+	info, alive := Running(projectRoot)
+	if err := NewStore(projectRoot).RemovePID(); err != nil {
+		return err
+	}
+	if !alive || info.PID == os.Getpid() {
+		return nil
+	}
+	if err := terminate(info.PID); err != nil {
+		return fmt.Errorf("stop git watcher %d: %w", info.PID, err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package gitwatch
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the watcher in its own session so that it outlives the
+// hook process and is not signalled with Claude's process group.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// processAlive reports whether a process with pid exists.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
+
+// terminate asks the process to exit.
+func terminate(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Signal(syscall.SIGTERM)
+}
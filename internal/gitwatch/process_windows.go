@@ -0,0 +1,39 @@
+//go:build windows
+
+package gitwatch
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the watcher in its own process group so that it outlives
+// the hook process and does not receive Claude's console signals.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// processAlive reports whether a process with pid exists. FindProcess
+// opens a handle on Windows, which fails for processes that exited.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
+
+// terminate stops the process; Windows has no graceful signal for
+// detached processes.
+func terminate(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
@@ -0,0 +1,199 @@
+// Package gitwatch runs a background watcher that records repository
+// changes made outside Claude during a session: branch switches, new
+// commits, merges, rebases and edits to working tree files.
+//
+// The watcher is a separate moai process started at SessionStart and
+// stopped at SessionEnd. It appends events to a session log under
+// .moai/cache/git-watch; hooks read the events recorded since the end of
+// the previous turn and report them to Claude and on the statusline.
+package gitwatch
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	coregit "github.com/modu-ai/moai-adk/internal/core/git"
+)
+
+// EventExternalEdit indicates working tree files changed outside Claude.
+const EventExternalEdit coregit.EventType = "external_edit"
+
+const (
+	eventsFile = "events.jsonl"
+	cursorFile = "cursor"
+	pidFile    = "watcher.pid"
+)
+
+// Event is a repository change recorded in the session log.
+type Event struct {
+	Time           time.Time         `json:"time"`
+	Type           coregit.EventType `json:"type"`
+	Branch         string            `json:"branch,omitempty"`
+	PreviousBranch string            `json:"previous_branch,omitempty"`
+	Head           string            `json:"head,omitempty"`
+	PreviousHead   string            `json:"previous_head,omitempty"`
+
+	// Commits holds the subjects of the commits added, newest first.
+	Commits []string `json:"commits,omitempty"`
+
+	// Files holds the paths of edited files, relative to the repository.
+	Files []string `json:"files,omitempty"`
+}
+
+// PIDInfo describes the running watcher.
+type PIDInfo struct {
+	PID       int       `json:"pid"`
+	SessionID string    `json:"session_id"`
+	Started   time.Time `json:"started"`
+}
+
+// Store holds the session event log, the turn cursor and the PID file of a
+// project.
+type Store struct {
+	dir string
+}
+
+// NewStore returns the store of the project at projectRoot.
+func NewStore(projectRoot string) *Store {
+	return &Store{dir: filepath.Join(projectRoot, ".moai", "cache", "git-watch")}
+}
+
+// Dir returns the directory holding the store files.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Append adds events to the session log.
+func (s *Store) Append(events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("create git-watch dir: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(s.dir, eventsFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open event log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	enc := json.NewEncoder(f)
+	for _, ev := range events {
+		if err := enc.Encode(ev); err != nil {
+			return fmt.Errorf("write event: %w", err)
+		}
+	}
+	return nil
+}
+
+// Since returns the events recorded after t, oldest first. A missing log
+// has no events; malformed lines are skipped.
+func (s *Store) Since(t time.Time) ([]Event, error) {
+	f, err := os.Open(filepath.Join(s.dir, eventsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open event log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var ev Event
+		if json.Unmarshal(scanner.Bytes(), &ev) != nil {
+			continue
+		}
+		if ev.Time.After(t) {
+			events = append(events, ev)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read event log: %w", err)
+	}
+	return events, nil
+}
+
+// Cursor returns the end of the last turn; events after it have not been
+// reported yet. It is zero when no turn has ended.
+func (s *Store) Cursor() time.Time {
+	data, err := os.ReadFile(filepath.Join(s.dir, cursorFile))
+	if err != nil {
+		return time.Time{}
+	}
+	t, _ := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(data)))
+	return t
+}
+
+// SetCursor records the end of a turn.
+func (s *Store) SetCursor(t time.Time) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("create git-watch dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, cursorFile), []byte(t.Format(time.RFC3339Nano)+"\n"), 0o644); err != nil {
+		return fmt.Errorf("write cursor: %w", err)
+	}
+	return nil
+}
+
+// Pending returns the events recorded since the last turn ended.
+func (s *Store) Pending() ([]Event, error) {
+	return s.Since(s.Cursor())
+}
+
+// Reset clears the event log and cursor for a new session.
+func (s *Store) Reset() error {
+	for _, name := range []string{eventsFile, cursorFile} {
+		if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("reset git-watch: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadPID returns the PID file contents, or nil when there is none.
+func (s *Store) ReadPID() (*PIDInfo, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, pidFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read pid file: %w", err)
+	}
+	var info PIDInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parse pid file: %w", err)
+	}
+	return &info, nil
+}
+
+// WritePID records the running watcher.
+func (s *Store) WritePID(info PIDInfo) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("create git-watch dir: %w", err)
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("marshal pid file: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, pidFile), data, 0o644); err != nil {
+		return fmt.Errorf("write pid file: %w", err)
+	}
+	return nil
+}
+
+// RemovePID deletes the PID file.
+func (s *Store) RemovePID() error {
+	if err := os.Remove(filepath.Join(s.dir, pidFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove pid file: %w", err)
+	}
+	return nil
+}
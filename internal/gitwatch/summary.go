@@ -0,0 +1,122 @@
+package gitwatch
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	coregit "github.com/modu-ai/moai-adk/internal/core/git"
+)
+
+// maxListed bounds the commits and files named in a summary.
+const maxListed = 5
+
+// changes is the aggregate of a series of events.
+type changes struct {
+	fromBranch, toBranch string
+	commits              []string
+	newCommits           int
+	rebased, merged      bool
+	files                []string
+}
+
+func aggregate(events []Event) changes {
+	var c changes
+	for _, ev := range events {
+		switch ev.Type {
+		case coregit.EventBranchSwitch:
+			if c.fromBranch == "" {
+				c.fromBranch = ev.PreviousBranch
+			}
+			c.toBranch = ev.Branch
+		case coregit.EventNewCommit:
+			c.newCommits += max(len(ev.Commits), 1)
+			c.commits = append(slices.Clone(ev.Commits), c.commits...)
+		case coregit.EventMerge:
+			c.merged = true
+			c.commits = append(slices.Clone(ev.Commits), c.commits...)
+		case coregit.EventRebase:
+			c.rebased = true
+		case EventExternalEdit:
+			for _, f := range ev.Files {
+				if !slices.Contains(c.files, f) {
+					c.files = append(c.files, f)
+				}
+			}
+		}
+	}
+	// Switching back to the starting branch is not a switch.
+	if c.fromBranch == c.toBranch {
+		c.fromBranch, c.toBranch = "", ""
+	}
+	return c
+}
+
+// Summarize describes events for Claude. It returns "" when there is
+// nothing to report.
+func Summarize(events []Event) string {
+	c := aggregate(events)
+	var lines []string
+	if c.toBranch != "" {
+		lines = append(lines, fmt.Sprintf("- Switched branch: %s → %s", c.fromBranch, c.toBranch))
+	}
+	if c.rebased {
+		lines = append(lines, "- The branch was rebased or reset; earlier commit hashes may no longer exist")
+	}
+	if c.merged {
+		lines = append(lines, "- A merge was committed")
+	}
+	if c.newCommits > 0 {
+		lines = append(lines, fmt.Sprintf("- %d new %s", c.newCommits, plural(c.newCommits, "commit", "commits")))
+	}
+	for _, s := range limit(c.commits, maxListed) {
+		lines = append(lines, "  - "+s)
+	}
+	if len(c.files) > 0 {
+		lines = append(lines, fmt.Sprintf("- Edited %d %s: %s", len(c.files), plural(len(c.files), "file", "files"),
+			strings.Join(limit(c.files, maxListed), ", ")))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "Repository changes since your last turn (made outside Claude):\n" + strings.Join(lines, "\n") +
+		"\nRe-read affected files before relying on earlier context."
+}
+
+// Brief describes events in a few words for the statusline. It returns ""
+// when there is nothing to report.
+func Brief(events []Event) string {
+	c := aggregate(events)
+	var parts []string
+	if c.toBranch != "" {
+		parts = append(parts, "→"+c.toBranch)
+	}
+	if c.rebased {
+		parts = append(parts, "rebased")
+	}
+	if c.merged {
+		parts = append(parts, "merged")
+	}
+	if c.newCommits > 0 {
+		parts = append(parts, fmt.Sprintf("+%d %s", c.newCommits, plural(c.newCommits, "commit", "commits")))
+	}
+	if len(c.files) > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s edited", len(c.files), plural(len(c.files), "file", "files")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// limit returns at most n items, replacing the rest with "+N more".
+func limit(items []string, n int) []string {
+	if len(items) <= n {
+		return items
+	}
+	return append(slices.Clone(items[:n]), fmt.Sprintf("+%d more", len(items)-n))
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
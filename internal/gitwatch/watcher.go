@@ -0,0 +1,225 @@
+package gitwatch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	coregit "github.com/modu-ai/moai-adk/internal/core/git"
+)
+
+const (
+	// DefaultInterval is how often the watcher polls the repository.
+	DefaultInterval = 2 * time.Second
+
+	// maxLifetime stops watchers whose session never ended cleanly.
+	maxLifetime = 12 * time.Hour
+
+	// maxCommits bounds the commit subjects recorded per event.
+	maxCommits = 10
+
+	gitTimeout = 5 * time.Second
+)
+
+// Watcher polls a repository and records changes to the store.
+type Watcher struct {
+	root     string
+	store    *Store
+	detector *coregit.EventDetector
+	interval time.Duration
+
+	// dirty maps the files that differ from HEAD to their modification
+	// time; nil until the first poll takes the baseline.
+	dirty map[string]time.Time
+
+	// now returns the current time; tests replace it.
+	now func() time.Time
+}
+
+// NewWatcher creates a watcher for the repository at root. A zero interval
+// uses DefaultInterval.
+func NewWatcher(root string, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Watcher{
+		root:     root,
+		store:    NewStore(root),
+		detector: coregit.NewEventDetector(root),
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Run polls until ctx is done, the PID file no longer names this process,
+// or maxLifetime passes.
+func (w *Watcher) Run(ctx context.Context) error {
+	if err := w.Init(ctx); err != nil {
+		return err
+	}
+	deadline := time.After(maxLifetime)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-deadline:
+			slog.Info("git watcher reached its maximum lifetime", "root", w.root)
+			return nil
+		case <-ticker.C:
+			if info, err := w.store.ReadPID(); err != nil || info == nil || info.PID != os.Getpid() {
+				slog.Debug("git watcher replaced or stopped", "root", w.root)
+				return nil
+			}
+			if err := w.Poll(ctx); err != nil {
+				slog.Debug("git watcher poll failed", "error", err)
+			}
+		}
+	}
+}
+
+// Init takes the baseline that later polls are compared against.
+func (w *Watcher) Init(ctx context.Context) error {
+	if err := w.detector.Snapshot(); err != nil {
+		return fmt.Errorf("git watcher: %w", err)
+	}
+	dirty, err := w.dirtyFiles(ctx)
+	if err != nil {
+		return fmt.Errorf("git watcher: %w", err)
+	}
+	w.dirty = dirty
+	return nil
+}
+
+// Poll records the changes since the previous poll.
+func (w *Watcher) Poll(ctx context.Context) error {
+	if w.dirty == nil {
+		return w.Init(ctx)
+	}
+
+	gitEvents, err := w.detector.DetectChanges()
+	if err != nil {
+		return err
+	}
+	var events []Event
+	for _, ge := range gitEvents {
+		ev := Event{
+			Time:           w.headTime(ctx),
+			Type:           ge.Type,
+			Branch:         ge.CurrentBranch,
+			PreviousBranch: ge.PreviousBranch,
+			Head:           ge.CurrentHEAD,
+			PreviousHead:   ge.PreviousHEAD,
+		}
+		if ge.Type == coregit.EventNewCommit || ge.Type == coregit.EventMerge || ge.Type == coregit.EventRebase {
+			ev.Commits = w.commitSubjects(ctx, ge.PreviousHEAD, ge.CurrentHEAD)
+		}
+		events = append(events, ev)
+	}
+
+	dirty, err := w.dirtyFiles(ctx)
+	if err != nil {
+		return err
+	}
+	var edited []string
+	var latest time.Time
+	for path, mtime := range dirty {
+		if prev, ok := w.dirty[path]; ok && prev.Equal(mtime) {
+			continue
+		}
+		edited = append(edited, path)
+		if mtime.After(latest) {
+			latest = mtime
+		}
+	}
+	w.dirty = dirty
+	// Checkouts and commits change the working tree themselves.
+	if len(edited) > 0 && len(gitEvents) == 0 {
+		slices.Sort(edited)
+		// The modification time places edits made just before a turn ended
+		// inside that turn; deletions have none.
+		if latest.IsZero() {
+			latest = w.now()
+		}
+		events = append(events, Event{Time: latest, Type: EventExternalEdit, Files: edited})
+	}
+
+	return w.store.Append(events...)
+}
+
+// dirtyFiles returns the modification times of the files git reports as
+// changed or untracked. Deleted files have a zero time.
+func (w *Watcher) dirtyFiles(ctx context.Context) (map[string]time.Time, error) {
+	out, err := w.git(ctx, "status", "--porcelain", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]time.Time)
+	for line := range strings.SplitSeq(out, "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		path := line[3:]
+		if _, to, ok := strings.Cut(path, " -> "); ok {
+			path = to
+		}
+		path = strings.Trim(path, `"`)
+		// Moai's own state is not an edit.
+		if strings.HasPrefix(path, ".moai/cache/") || strings.HasPrefix(path, ".moai/logs/") {
+			continue
+		}
+		var mtime time.Time
+		if info, err := os.Stat(filepath.Join(w.root, path)); err == nil {
+			mtime = info.ModTime()
+		}
+		files[path] = mtime
+	}
+	return files, nil
+}
+
+// headTime returns when HEAD last moved according to the reflog, so that
+// a checkout or commit made just before a turn ended falls inside that
+// turn. It falls back to the current time.
+func (w *Watcher) headTime(ctx context.Context) time.Time {
+	out, err := w.git(ctx, "reflog", "-1", "--date=unix", "--format=%gd")
+	if err != nil {
+		return w.now()
+	}
+	_, stamp, _ := strings.Cut(strings.TrimSuffix(out, "}"), "@{")
+	sec, err := strconv.ParseInt(stamp, 10, 64)
+	if err != nil {
+		return w.now()
+	}
+	return time.Unix(sec, 0)
+}
+
+// commitSubjects returns the subjects of the commits reachable from now but
+// not from prev, newest first.
+func (w *Watcher) commitSubjects(ctx context.Context, prev, now string) []string {
+	out, err := w.git(ctx, "log", "--format=%s", fmt.Sprintf("--max-count=%d", maxCommits), prev+".."+now)
+	if err != nil || out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+func (w *Watcher) git(ctx context.Context, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = w.root
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
@@ -0,0 +1,299 @@
+// Package audit keeps the append-only log of hook decisions.
+//
+// Every hook handler invocation is recorded as one JSON line in
+// audit.jsonl. When the file reaches its size cap it is renamed to
+// audit-<time>.jsonl and a new file is started; the oldest rotated files
+// are removed beyond the configured count. Records are never rewritten.
+package audit
+
+import (
+	"bufio"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Decisions recorded for a handler invocation.
+const (
+	DecisionAllow = "allow"
+	DecisionDeny  = "deny"
+	DecisionAsk   = "ask"
+	// DecisionRewrite allows a tool call with a rewritten input.
+	DecisionRewrite = "rewrite"
+	DecisionBlock   = "block"
+	DecisionError   = "error"
+	DecisionTimeout = "timeout"
+)
+
+// FileName is the active audit log in the log directory.
+const FileName = "audit.jsonl"
+
+// Record is one hook handler invocation.
+type Record struct {
+	Time      time.Time `json:"time"`
+	SessionID string    `json:"session_id,omitempty"`
+	Event     string    `json:"event"`
+	Handler   string    `json:"handler"`
+	Decision  string    `json:"decision"`
+	Reason    string    `json:"reason,omitempty"`
+	// RuleID names the policy rule behind a deny or ask decision.
+	RuleID string `json:"rule_id,omitempty"`
+	// Rewrites names the rules that rewrote the tool input.
+	Rewrites []string `json:"rewrites,omitempty"`
+	ToolName string   `json:"tool_name,omitempty"`
+	// Subject is what a permission request was for: the leading words of
+	// a command or the project-relative file. It never holds arguments.
+	Subject string `json:"subject,omitempty"`
+	// InputDigest identifies the tool input without recording it, so
+	// secrets in commands or file content never reach the log.
+	InputDigest string  `json:"input_digest,omitempty"`
+	LatencyMS   float64 `json:"latency_ms"`
+}
+
+// Digest returns a short digest of a tool input, or "" for none.
+func Digest(input []byte) string {
+	if len(input) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(input)
+	return "sha256:" + hex.EncodeToString(sum[:])[:16]
+}
+
+// ResolveDir returns the log directory configured as dir for a project:
+// relative directories are taken from the project root.
+func ResolveDir(projectRoot, dir string) string {
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(projectRoot, dir)
+}
+
+// Log is an audit log directory.
+type Log struct {
+	Dir string
+	// MaxBytes is the size at which the active file is rotated; zero
+	// disables rotation.
+	MaxBytes int64
+	// MaxFiles bounds the rotated files kept; zero keeps all.
+	MaxFiles int
+}
+
+// Append writes records to the active file in a single write, rotating it
+// first when it would exceed MaxBytes.
+func (l *Log) Append(records ...Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	var buf []byte
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("marshal audit record: %w", err)
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+
+	if err := os.MkdirAll(l.Dir, 0o755); err != nil {
+		return fmt.Errorf("create audit directory: %w", err)
+	}
+	path := filepath.Join(l.Dir, FileName)
+	if info, err := os.Stat(path); err == nil && l.MaxBytes > 0 && info.Size() > 0 && info.Size()+int64(len(buf)) > l.MaxBytes {
+		if err := l.rotate(path, records[0].Time); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.Write(buf); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
+
+// rotate renames the active file after the time of the record that
+// overflowed it and prunes the oldest rotated files.
+func (l *Log) rotate(path string, at time.Time) error {
+	rotated := filepath.Join(l.Dir, "audit-"+at.UTC().Format("20060102T150405.000000000")+".jsonl")
+	if err := os.Rename(path, rotated); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("rotate audit log: %w", err)
+	}
+	if l.MaxFiles <= 0 {
+		return nil
+	}
+	files, err := rotatedFiles(l.Dir)
+	if err != nil {
+		return err
+	}
+	for len(files) > l.MaxFiles {
+		_ = os.Remove(files[0])
+		files = files[1:]
+	}
+	return nil
+}
+
+// rotatedFiles lists the rotated files of dir, oldest first.
+func rotatedFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "audit-*.jsonl"))
+	if err != nil {
+		return nil, fmt.Errorf("list audit logs: %w", err)
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// Filter selects records. Empty fields match everything.
+type Filter struct {
+	Event    string
+	Decision string
+	Handler  string
+	// Session matches session IDs by prefix.
+	Session string
+	Since   time.Time
+}
+
+// Match reports whether r passes the filter.
+func (f Filter) Match(r Record) bool {
+	return (f.Event == "" || strings.EqualFold(r.Event, f.Event)) &&
+		(f.Decision == "" || strings.EqualFold(r.Decision, f.Decision)) &&
+		(f.Handler == "" || strings.Contains(r.Handler, f.Handler)) &&
+		(f.Session == "" || strings.HasPrefix(r.SessionID, f.Session)) &&
+		!r.Time.Before(f.Since)
+}
+
+// Read returns the records of dir matching f, oldest first. Rotated files
+// are read before the active one; malformed lines are skipped.
+func Read(dir string, f Filter) ([]Record, error) {
+	files, err := rotatedFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, filepath.Join(dir, FileName))
+
+	var records []Record
+	for _, path := range files {
+		if err := readFile(path, func(r Record) {
+			if f.Match(r) {
+				records = append(records, r)
+			}
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// readFile calls fn for each record of a log file. A missing file holds
+// no records.
+func readFile(path string, fn func(Record)) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	sc := bufio.NewScanner(file)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var r Record
+		if json.Unmarshal(sc.Bytes(), &r) == nil {
+			fn(r)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("read %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// Summary aggregates records.
+type Summary struct {
+	Total      int            `json:"total"`
+	Sessions   int            `json:"sessions"`
+	ByDecision map[string]int `json:"by_decision"`
+	Events     []EventStats   `json:"events"`
+	// Rules counts the decisions and rewrites of each rule, most frequent
+	// first.
+	Rules []RuleCount `json:"rules,omitempty"`
+}
+
+// EventStats summarizes the handler invocations of one event.
+type EventStats struct {
+	Event string  `json:"event"`
+	Count int     `json:"count"`
+	P50MS float64 `json:"p50_ms"`
+	P95MS float64 `json:"p95_ms"`
+	MaxMS float64 `json:"max_ms"`
+}
+
+// RuleCount is the number of decisions a rule produced.
+type RuleCount struct {
+	RuleID string `json:"rule_id"`
+	Count  int    `json:"count"`
+}
+
+// Summarize aggregates records by decision, event and rule.
+func Summarize(records []Record) Summary {
+	s := Summary{Total: len(records), ByDecision: map[string]int{}}
+	sessions := map[string]bool{}
+	latencies := map[string][]float64{}
+	rules := map[string]int{}
+	for _, r := range records {
+		s.ByDecision[r.Decision]++
+		if r.SessionID != "" {
+			sessions[r.SessionID] = true
+		}
+		latencies[r.Event] = append(latencies[r.Event], r.LatencyMS)
+		if r.RuleID != "" {
+			rules[r.RuleID]++
+		}
+		for _, id := range r.Rewrites {
+			rules[id]++
+		}
+	}
+	s.Sessions = len(sessions)
+
+	for event, ms := range latencies {
+		slices.Sort(ms)
+		s.Events = append(s.Events, EventStats{
+			Event: event,
+			Count: len(ms),
+			P50MS: percentile(ms, 0.50),
+			P95MS: percentile(ms, 0.95),
+			MaxMS: ms[len(ms)-1],
+		})
+	}
+	slices.SortFunc(s.Events, func(a, b EventStats) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Event, b.Event))
+	})
+
+	for id, n := range rules {
+		s.Rules = append(s.Rules, RuleCount{RuleID: id, Count: n})
+	}
+	slices.SortFunc(s.Rules, func(a, b RuleCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.RuleID, b.RuleID))
+	})
+	return s
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(float64(len(sorted))*p+0.5) - 1
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLogAppendRotates(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	l := &Log{Dir: dir, MaxBytes: 400, MaxFiles: 2}
+	t0 := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	for i := range 12 {
+		rec := Record{Time: t0.Add(time.Duration(i) * time.Second), Event: "PreToolUse", Handler: "hook.preToolHandler", Decision: DecisionAllow, ToolName: "Bash"}
+		if err := l.Append(rec); err != nil {
+			t.Fatalf("Append() error: %v", err)
+		}
+	}
+
+	rotated, err := rotatedFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotated) != 2 {
+		t.Errorf("rotated files = %v, want 2 kept", rotated)
+	}
+	info, err := os.Stat(filepath.Join(dir, FileName))
+	if err != nil || info.Size() > l.MaxBytes {
+		t.Errorf("active file = %v, %v", info, err)
+	}
+
+	records, err := Read(dir, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) == 0 || len(records) >= 12 {
+		t.Fatalf("read %d records, want the records of the kept files", len(records))
+	}
+	for i := 1; i < len(records); i++ {
+		if !records[i].Time.After(records[i-1].Time) {
+			t.Fatalf("records out of order at %d", i)
+		}
+	}
+	if last := records[len(records)-1]; !last.Time.Equal(t0.Add(11 * time.Second)) {
+		t.Errorf("last record at %v", last.Time)
+	}
+}
+
+func TestReadFilterAndSummarize(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	l := &Log{Dir: dir}
+	t0 := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	err := l.Append(
+		Record{Time: t0, SessionID: "sess-a1", Event: "PreToolUse", Decision: DecisionDeny, RuleID: "bash-dangerous", LatencyMS: 4},
+		Record{Time: t0.Add(time.Minute), SessionID: "sess-a1", Event: "PreToolUse", Decision: DecisionAllow, LatencyMS: 2},
+		Record{Time: t0.Add(2 * time.Minute), SessionID: "sess-b2", Event: "PreToolUse", Decision: DecisionAsk, RuleID: "file-critical-config", LatencyMS: 10},
+		Record{Time: t0.Add(3 * time.Minute), SessionID: "sess-b2", Event: "Stop", Decision: DecisionBlock, LatencyMS: 120},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "audit-00000000.jsonl"), []byte("not json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	denied, err := Read(dir, Filter{Decision: "DENY"})
+	if err != nil || len(denied) != 1 || denied[0].RuleID != "bash-dangerous" {
+		t.Errorf("deny filter = %+v, %v", denied, err)
+	}
+	if got, _ := Read(dir, Filter{Session: "sess-b", Event: "PreToolUse"}); len(got) != 1 {
+		t.Errorf("session filter = %+v", got)
+	}
+	if got, _ := Read(dir, Filter{Since: t0.Add(90 * time.Second)}); len(got) != 2 {
+		t.Errorf("since filter = %+v", got)
+	}
+
+	all, _ := Read(dir, Filter{})
+	s := Summarize(all)
+	if s.Total != 4 || s.Sessions != 2 || s.ByDecision[DecisionDeny] != 1 || s.ByDecision[DecisionBlock] != 1 {
+		t.Errorf("summary = %+v", s)
+	}
+	if len(s.Events) != 2 || s.Events[0] != (EventStats{Event: "PreToolUse", Count: 3, P50MS: 4, P95MS: 10, MaxMS: 10}) {
+		t.Errorf("events = %+v", s.Events)
+	}
+	if len(s.Rules) != 2 || s.Rules[0].RuleID != "bash-dangerous" {
+		t.Errorf("rules = %+v", s.Rules)
+	}
+}
+
+func TestDigest(t *testing.T) {
+	t.Parallel()
+
+	if Digest(nil) != "" {
+		t.Error("empty input should have no digest")
+	}
+	a := Digest([]byte(`{"command":"echo $TOKEN"}`))
+	if len(a) != len("sha256:")+16 || a == Digest([]byte(`{"command":"ls"}`)) {
+		t.Errorf("Digest() = %q", a)
+	}
+}
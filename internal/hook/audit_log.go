@@ -0,0 +1,119 @@
+package hook
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/hook/audit"
+)
+
+// maxAuditReason bounds the reason kept in an audit record.
+const maxAuditReason = 500
+
+// auditSettings returns the audit log settings of cfg, or the defaults
+// when no configuration is available.
+func auditSettings(cfg ConfigProvider) config.HookAuditConfig {
+	if cfg != nil {
+		if c := cfg.Get(); c != nil {
+			return c.HookAudit
+		}
+	}
+	return config.NewDefaultHookAuditConfig()
+}
+
+// auditLog returns the audit log of a project.
+func auditLog(root string, settings config.HookAuditConfig) *audit.Log {
+	return &audit.Log{
+		Dir:      audit.ResolveDir(root, cmp.Or(settings.Dir, config.DefaultHookAuditDir)),
+		MaxBytes: int64(settings.MaxSizeMB) << 20,
+		MaxFiles: settings.MaxFiles,
+	}
+}
+
+// writeAudit appends the records of one dispatch to the audit log of the
+// project the event belongs to. Outside a MoAI project and during fixture
+// replays nothing is written. Failures are logged and never affect the
+// hook result.
+func (r *registry) writeAudit(ctx context.Context, input *HookInput, records []audit.Record) {
+	if len(records) == 0 || isReplay(ctx) {
+		return
+	}
+	settings := auditSettings(r.cfg)
+	if !settings.Enabled {
+		return
+	}
+	root := resolveProjectRoot(input)
+	if root == "" {
+		return
+	}
+	if err := auditLog(root, settings).Append(records...); err != nil {
+		slog.Warn("failed to write hook audit log", "error", err.Error())
+	}
+}
+
+// auditRecord describes one handler invocation.
+func auditRecord(event EventType, h Handler, input *HookInput, output *HookOutput, err, ctxErr error, latency time.Duration) audit.Record {
+	rec := audit.Record{
+		Time:        time.Now(),
+		SessionID:   input.SessionID,
+		Event:       string(event),
+		Handler:     handlerName(h),
+		ToolName:    input.ToolName,
+		InputDigest: audit.Digest(input.ToolInput),
+		LatencyMS:   float64(latency.Microseconds()) / 1000,
+	}
+	if event == EventPermissionRequest {
+		// The subject lets `moai permissions suggest` learn rules from
+		// the requests the user approved.
+		rec.Subject = permissionSubject(input, resolveProjectRoot(input))
+	}
+	switch {
+	case ctxErr != nil:
+		rec.Decision = audit.DecisionTimeout
+		rec.Reason = ctxErr.Error()
+	case err != nil:
+		rec.Decision = audit.DecisionError
+		rec.Reason = err.Error()
+	default:
+		rec.Decision, rec.Reason = outputDecision(output)
+		if output != nil {
+			rec.RuleID = output.RuleID
+			rec.Rewrites = output.Rewrites
+		}
+	}
+	if len(rec.Reason) > maxAuditReason {
+		rec.Reason = strings.ToValidUTF8(rec.Reason[:maxAuditReason], "") + "…"
+	}
+	return rec
+}
+
+// outputDecision reduces a handler output to its audit decision and
+// reason. Outputs that do not stop, question or rewrite anything are
+// "allow".
+func outputDecision(output *HookOutput) (string, string) {
+	switch {
+	case output == nil:
+		return audit.DecisionAllow, ""
+	case output.Decision == DecisionBlock:
+		return audit.DecisionBlock, output.Reason
+	case output.ExitCode == 2:
+		return audit.DecisionBlock, cmp.Or(output.Reason, output.StopReason)
+	case output.HookSpecificOutput != nil && output.HookSpecificOutput.PermissionDecision == DecisionDeny:
+		return audit.DecisionDeny, output.HookSpecificOutput.PermissionDecisionReason
+	case output.HookSpecificOutput != nil && output.HookSpecificOutput.PermissionDecision == DecisionAsk:
+		return audit.DecisionAsk, output.HookSpecificOutput.PermissionDecisionReason
+	case output.HookSpecificOutput != nil && len(output.HookSpecificOutput.UpdatedInput) > 0:
+		return audit.DecisionRewrite, output.HookSpecificOutput.PermissionDecisionReason
+	}
+	return audit.DecisionAllow, ""
+}
+
+// handlerName names a handler by its type, such as "hook.preToolHandler".
+func handlerName(h Handler) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", h), "*")
+}
@@ -0,0 +1,99 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/hook/audit"
+)
+
+func TestRegistryDispatchWritesAuditLog(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	root := newMoAIProject(t)
+
+	reg := NewRegistry(&mockConfigProvider{cfg: newTestConfig()})
+	reg.Register(&mockHandler{event: EventPreToolUse, output: NewAllowOutput()})
+	reg.Register(NewPreToolHandler(&mockConfigProvider{cfg: newTestConfig()}, DefaultSecurityPolicy()))
+	reg.Register(&mockHandler{event: EventStop, err: errors.New("boom")})
+
+	toolInput := json.RawMessage(`{"command":"rm -rf /"}`)
+	out, err := reg.Dispatch(context.Background(), EventPreToolUse, &HookInput{SessionID: "sess-1", CWD: root, ToolName: "Bash", ToolInput: toolInput})
+	if err != nil || out.HookSpecificOutput.PermissionDecision != DecisionDeny {
+		t.Fatalf("Dispatch() = %+v, %v", out, err)
+	}
+	if _, err := reg.Dispatch(context.Background(), EventStop, &HookInput{SessionID: "sess-1", CWD: root}); err == nil {
+		t.Fatal("expected the handler error")
+	}
+
+	records, err := audit.Read(filepath.Join(root, ".moai", "logs", "hooks"), audit.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("records = %+v, want 3", records)
+	}
+	allow, deny, failed := records[0], records[1], records[2]
+	if allow.Decision != audit.DecisionAllow || allow.Handler != "hook.mockHandler" || allow.SessionID != "sess-1" {
+		t.Errorf("allow record = %+v", allow)
+	}
+	if deny.Decision != audit.DecisionDeny || deny.Handler != "hook.preToolHandler" || !strings.HasPrefix(deny.RuleID, "bash-dangerous-") {
+		t.Errorf("deny record = %+v", deny)
+	}
+	if deny.ToolName != "Bash" || deny.InputDigest != audit.Digest(toolInput) || !strings.Contains(deny.Reason, "Dangerous command blocked") {
+		t.Errorf("deny record = %+v", deny)
+	}
+	if failed.Event != "Stop" || failed.Decision != audit.DecisionError || failed.Reason != "boom" {
+		t.Errorf("error record = %+v", failed)
+	}
+}
+
+func TestRegistryDispatchAuditDisabled(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	root := newMoAIProject(t)
+
+	cfg := newTestConfig()
+	cfg.HookAudit.Enabled = false
+	reg := NewRegistry(&mockConfigProvider{cfg: cfg})
+	reg.Register(&mockHandler{event: EventStop, output: &HookOutput{}})
+	if _, err := reg.Dispatch(context.Background(), EventStop, &HookInput{CWD: root}); err != nil {
+		t.Fatal(err)
+	}
+	if records, _ := audit.Read(filepath.Join(root, ".moai", "logs", "hooks"), audit.Filter{}); len(records) != 0 {
+		t.Errorf("disabled audit log written: %+v", records)
+	}
+}
+
+func TestAuditRecordDecisions(t *testing.T) {
+	t.Parallel()
+
+	input := &HookInput{}
+	h := &mockHandler{}
+	tests := []struct {
+		name   string
+		output *HookOutput
+		ctxErr error
+		want   string
+	}{
+		{"nil output", nil, nil, audit.DecisionAllow},
+		{"stop block", NewStopBlockOutput("keep going"), nil, audit.DecisionBlock},
+		{"exit code 2", &HookOutput{ExitCode: 2}, nil, audit.DecisionBlock},
+		{"ask", NewAskOutput("confirm"), nil, audit.DecisionAsk},
+		{"timeout", nil, context.DeadlineExceeded, audit.DecisionTimeout},
+	}
+	for _, tt := range tests {
+		rec := auditRecord(EventPreToolUse, h, input, tt.output, nil, tt.ctxErr, 1500*time.Microsecond)
+		if rec.Decision != tt.want || rec.LatencyMS != 1.5 {
+			t.Errorf("%s: record = %+v, want decision %q", tt.name, rec, tt.want)
+		}
+	}
+
+	long := NewDenyOutput(strings.Repeat("x", 2*maxAuditReason))
+	if rec := auditRecord(EventPreToolUse, h, input, long, nil, nil, 0); len(rec.Reason) > maxAuditReason+len("…") {
+		t.Errorf("reason not truncated: %d bytes", len(rec.Reason))
+	}
+}
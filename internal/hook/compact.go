@@ -4,36 +4,64 @@ import (
 	"context"
 	"encoding/json"
 	"log/slog"
+	"time"
 )
 
 // compactHandler processes PreCompact events.
 // It captures context information and creates session state snapshots
 // for post-compaction recovery (REQ-HOOK-036). Always returns "allow".
-type compactHandler struct{}
+type compactHandler struct {
+	cfg ConfigProvider
+}
 
 // NewCompactHandler creates a new PreCompact event handler.
 func NewCompactHandler() Handler {
 	return &compactHandler{}
 }
 
+// NewCompactHandlerWithConfig creates a PreCompact handler that prunes old
+// snapshots according to the context_injection settings of cfg.
+func NewCompactHandlerWithConfig(cfg ConfigProvider) Handler {
+	return &compactHandler{cfg: cfg}
+}
+
 // EventType returns EventPreCompact.
 func (h *compactHandler) EventType() EventType {
 	return EventPreCompact
 }
 
-// Handle processes a PreCompact event. It captures the current context,
-// creates a session state snapshot, and returns preservation status in
-// the Data field. Errors are non-blocking.
+// Handle processes a PreCompact event. Inside a MoAI project it writes a
+// snapshot of the session to .moai/memory/snapshots/<session>.json, prunes
+// old snapshots, and returns preservation status in the Data field.
+// Errors are non-blocking.
 func (h *compactHandler) Handle(ctx context.Context, input *HookInput) (*HookOutput, error) {
 	slog.Info("pre-compact context preservation",
 		"session_id", input.SessionID,
 		"project_dir", input.ProjectDir,
+		"trigger", input.Trigger,
 	)
 
 	data := map[string]any{
 		"session_id":       input.SessionID,
 		"status":           "preserved",
-		"snapshot_created": true,
+		"snapshot_created": false,
+	}
+
+	// A fixture replay writes no snapshot.
+	if root := resolveProjectRoot(input); root != "" && !isReplay(ctx) {
+		path, err := saveSnapshot(root, captureSnapshot(root, input))
+		if err != nil {
+			slog.Warn("failed to save session snapshot",
+				"session_id", input.SessionID,
+				"error", err.Error(),
+			)
+		} else {
+			data["snapshot_created"] = true
+			data["snapshot_path"] = path
+			if n := pruneSnapshots(root, injectionSettings(h.cfg).Snapshots, path, time.Now()); n > 0 {
+				data["snapshots_pruned"] = n
+			}
+		}
 	}
 
 	jsonData, err := json.Marshal(data)
@@ -0,0 +1,246 @@
+package hook
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	lsphook "github.com/modu-ai/moai-adk/internal/lsp/hook"
+)
+
+// Context injection renders what handlers collect into short
+// additionalContext text. HookOutput.Data is never sent to Claude, so facts
+// Claude should act on have to be rendered here.
+
+// charsPerToken approximates the tokenizer when enforcing the budget.
+const charsPerToken = 4
+
+// injectionSettings returns the context injection settings, falling back to
+// the defaults when no configuration is available.
+func injectionSettings(cfg ConfigProvider) config.ContextInjectionConfig {
+	if cfg != nil {
+		if c := cfg.Get(); c != nil {
+			return c.ContextInjection
+		}
+	}
+	return config.NewDefaultContextInjectionConfig()
+}
+
+// fitTokenBudget trims text at line boundaries to roughly maxTokens and
+// notes how many lines were dropped. A non-positive budget keeps the text.
+func fitTokenBudget(text string, maxTokens int) string {
+	limit := maxTokens * charsPerToken
+	if maxTokens <= 0 || len(text) <= limit {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	size := 0
+	for i, line := range lines {
+		note := fmt.Sprintf("… %d more line(s) omitted", len(lines)-i)
+		if size+len(line)+1+len(note) > limit {
+			return strings.Join(append(lines[:i:i], note), "\n")
+		}
+		size += len(line) + 1
+	}
+	return text
+}
+
+// reportableDiagnostics returns the diagnostics at or above minSeverity
+// ordered by position. Only "warning" widens the default of errors only.
+func reportableDiagnostics(diags []lsphook.Diagnostic, minSeverity string) []lsphook.Diagnostic {
+	var shown []lsphook.Diagnostic
+	for _, d := range diags {
+		if d.Severity == lsphook.SeverityError ||
+			(d.Severity == lsphook.SeverityWarning && minSeverity == config.SeverityThresholdWarning) {
+			shown = append(shown, d)
+		}
+	}
+	slices.SortStableFunc(shown, func(a, b lsphook.Diagnostic) int {
+		return cmp.Or(cmp.Compare(a.Range.Start.Line, b.Range.Start.Line),
+			cmp.Compare(a.Range.Start.Character, b.Range.Start.Character))
+	})
+	return shown
+}
+
+// diagnosticsDigest identifies a set of diagnostics independently of their
+// positions, so edits that only shift lines do not repeat a report. It is
+// "" for no diagnostics.
+func diagnosticsDigest(diags []lsphook.Diagnostic) string {
+	if len(diags) == 0 {
+		return ""
+	}
+	h := sha256.New()
+	for _, d := range diags {
+		_, _ = fmt.Fprintf(h, "%s\x00%s\x00%s\n", d.Severity, d.Code, d.Message)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// renderDiagnostics describes the diagnostics of file for Claude, one line
+// per diagnostic with a one-based position.
+func renderDiagnostics(file string, diags []lsphook.Diagnostic) string {
+	var errs, warns int
+	for _, d := range diags {
+		if d.Severity == lsphook.SeverityError {
+			errs++
+		} else {
+			warns++
+		}
+	}
+	counts := fmt.Sprintf("%d error(s)", errs)
+	if warns > 0 {
+		counts += fmt.Sprintf(", %d warning(s)", warns)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "LSP diagnostics in %s after your edit: %s. Fix them before moving on.", file, counts)
+	for _, d := range diags {
+		b.WriteString("\n- " + diagnosticLine(d))
+	}
+	return b.String()
+}
+
+// diagnosticLine describes one diagnostic with a one-based position, e.g.
+// "L12:5 error: undefined: foo [gopls]".
+func diagnosticLine(d lsphook.Diagnostic) string {
+	line := fmt.Sprintf("L%d:%d %s: %s", d.Range.Start.Line+1, d.Range.Start.Character+1, string(d.Severity), d.Message)
+	if src := strings.TrimSpace(d.Source + " " + d.Code); src != "" {
+		line += " [" + src + "]"
+	}
+	return line
+}
+
+// renderSessionFacts summarizes the project for the start of a session. It
+// returns "" when nothing is known.
+func renderSessionFacts(cfg *config.Config, projectRoot string) string {
+	var lines []string
+
+	name := cfg.Project.Name
+	if name == "" && projectRoot != "" {
+		name = filepath.Base(projectRoot)
+	}
+	if name != "" {
+		var traits []string
+		for _, t := range []string{string(cfg.Project.Type), cfg.Project.Language, cfg.Project.Framework} {
+			if t != "" {
+				traits = append(traits, t)
+			}
+		}
+		line := "- Project: " + name
+		if len(traits) > 0 {
+			line += " (" + strings.Join(traits, ", ") + ")"
+		}
+		lines = append(lines, line)
+	}
+	if mode := cfg.Quality.DevelopmentMode; mode != "" {
+		line := "- Methodology: " + strings.ToUpper(string(mode))
+		if cfg.Quality.TestCoverageTarget > 0 {
+			line += fmt.Sprintf(", coverage target %d%%", cfg.Quality.TestCoverageTarget)
+		}
+		lines = append(lines, line)
+	}
+	if cfg.GitStrategy.Mode != "" {
+		lines = append(lines, "- Git strategy: "+cfg.GitStrategy.Mode)
+	}
+	if lang := cfg.Language.ConversationLanguageName; lang != "" {
+		lines = append(lines, "- Conversation language: "+lang)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "MoAI project context:\n" + strings.Join(lines, "\n")
+}
+
+// diagnosticsMemo remembers, per session, which diagnostics were last
+// reported for each file. Hook processes are short-lived, so it is kept in
+// .moai/cache/diagnostics-reported.json.
+type diagnosticsMemo struct {
+	path      string
+	sessionID string
+}
+
+// diagnosticsMemoFile is the on-disk layout of a diagnosticsMemo. Open
+// keeps the lines of the diagnostics still reported for each file, which
+// the PreCompact snapshot carries over.
+type diagnosticsMemoFile struct {
+	SessionID string              `json:"sessionId"`
+	Files     map[string]string   `json:"files"`
+	Open      map[string][]string `json:"open,omitempty"`
+}
+
+// newDiagnosticsMemo returns the memo of a MoAI project, or nil when
+// projectRoot is empty.
+func newDiagnosticsMemo(projectRoot, sessionID string) *diagnosticsMemo {
+	if projectRoot == "" {
+		return nil
+	}
+	return &diagnosticsMemo{
+		path:      filepath.Join(projectRoot, ".moai", "cache", "diagnostics-reported.json"),
+		sessionID: sessionID,
+	}
+}
+
+// swap records digest and the diagnostic lines as the last report for file
+// and returns the previous digest. A new session starts from an empty memo.
+// A nil memo remembers nothing.
+func (m *diagnosticsMemo) swap(file, digest string, lines []string) string {
+	if m == nil {
+		return ""
+	}
+	f := m.load()
+	if f.SessionID != m.sessionID || f.Files == nil {
+		f = diagnosticsMemoFile{SessionID: m.sessionID, Files: map[string]string{}}
+	}
+	if f.Open == nil {
+		f.Open = map[string][]string{}
+	}
+	prev := f.Files[file]
+	if digest == "" {
+		delete(f.Files, file)
+		delete(f.Open, file)
+	} else {
+		f.Files[file] = digest
+		f.Open[file] = lines
+	}
+
+	data, err := json.Marshal(f)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(m.path), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(m.path, data, 0o644)
+	}
+	if err != nil {
+		slog.Debug("failed to record reported diagnostics", "error", err)
+	}
+	return prev
+}
+
+// open returns the diagnostic lines still reported in this session, keyed
+// by file.
+func (m *diagnosticsMemo) open() map[string][]string {
+	if m == nil {
+		return nil
+	}
+	if f := m.load(); f.SessionID == m.sessionID {
+		return f.Open
+	}
+	return nil
+}
+
+// load reads the memo file; a missing or unreadable file is empty.
+func (m *diagnosticsMemo) load() diagnosticsMemoFile {
+	var f diagnosticsMemoFile
+	if data, err := os.ReadFile(m.path); err == nil {
+		_ = json.Unmarshal(data, &f)
+	}
+	return f
+}
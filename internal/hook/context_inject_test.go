@@ -0,0 +1,200 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	lsphook "github.com/modu-ai/moai-adk/internal/lsp/hook"
+	"github.com/modu-ai/moai-adk/pkg/models"
+)
+
+func diagAt(line int, severity lsphook.DiagnosticSeverity, msg string) lsphook.Diagnostic {
+	return lsphook.Diagnostic{
+		Range:    lsphook.Range{Start: lsphook.Position{Line: line, Character: 4}},
+		Severity: severity,
+		Source:   "gopls",
+		Message:  msg,
+	}
+}
+
+// newMoAIProject creates a directory recognized as a MoAI project root.
+func newMoAIProject(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".moai"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func editInput(root, sessionID, file string) *HookInput {
+	return &HookInput{
+		SessionID:     sessionID,
+		CWD:           root,
+		HookEventName: "PostToolUse",
+		ToolName:      "Edit",
+		ToolInput:     json.RawMessage(`{"file_path": "` + filepath.Join(root, file) + `"}`),
+	}
+}
+
+func postToolContext(t *testing.T, h Handler, input *HookInput) string {
+	t.Helper()
+	out, err := h.Handle(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	if out.HookSpecificOutput == nil {
+		t.Fatal("expected hookSpecificOutput")
+	}
+	return out.HookSpecificOutput.AdditionalContext
+}
+
+func TestPostToolHandler_InjectsDiagnostics(t *testing.T) {
+	root := newMoAIProject(t)
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+
+	diags := []lsphook.Diagnostic{
+		diagAt(30, lsphook.SeverityWarning, "unused parameter"),
+		diagAt(11, lsphook.SeverityError, "undefined: foo"),
+	}
+	collector := &mockDiagnosticsCollector{
+		getDiagnosticsFunc: func(context.Context, string) ([]lsphook.Diagnostic, error) { return diags, nil },
+	}
+	cfg := newTestConfig()
+	h := NewPostToolHandlerWithContext(&mockConfigProvider{cfg: cfg}, collector)
+
+	got := postToolContext(t, h, editInput(root, "sess-1", "internal/auth/login.go"))
+	if !strings.Contains(got, "LSP diagnostics in internal/auth/login.go after your edit: 1 error(s).") {
+		t.Errorf("missing summary:\n%s", got)
+	}
+	if !strings.Contains(got, "- L12:5 error: undefined: foo [gopls]") {
+		t.Errorf("missing error line:\n%s", got)
+	}
+	if strings.Contains(got, "unused parameter") {
+		t.Errorf("warnings must be left out at the error threshold:\n%s", got)
+	}
+
+	// The same errors after another edit are not repeated.
+	if got := postToolContext(t, h, editInput(root, "sess-1", "internal/auth/login.go")); got != "" {
+		t.Errorf("repeated report: %q", got)
+	}
+
+	// Lowering the threshold changes what is reported, so it is reported.
+	cfg.ContextInjection.MinSeverity = config.SeverityThresholdWarning
+	got = postToolContext(t, h, editInput(root, "sess-1", "internal/auth/login.go"))
+	if !strings.Contains(got, "1 error(s), 1 warning(s)") || !strings.Contains(got, "L31:5 warning: unused parameter") {
+		t.Errorf("warnings not reported:\n%s", got)
+	}
+
+	// Fixing everything is reported once.
+	diags = nil
+	if got := postToolContext(t, h, editInput(root, "sess-1", "internal/auth/login.go")); got != "LSP diagnostics in internal/auth/login.go are resolved." {
+		t.Errorf("resolved report = %q", got)
+	}
+	if got := postToolContext(t, h, editInput(root, "sess-1", "internal/auth/login.go")); got != "" {
+		t.Errorf("clean file reported again: %q", got)
+	}
+}
+
+func TestPostToolHandler_DiagnosticsDedupePerSession(t *testing.T) {
+	root := newMoAIProject(t)
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+
+	collector := &mockDiagnosticsCollector{
+		getDiagnosticsFunc: func(context.Context, string) ([]lsphook.Diagnostic, error) {
+			return []lsphook.Diagnostic{diagAt(3, lsphook.SeverityError, "missing return")}, nil
+		},
+	}
+	h := NewPostToolHandlerWithContext(&mockConfigProvider{cfg: newTestConfig()}, collector)
+
+	if postToolContext(t, h, editInput(root, "sess-1", "a.go")) == "" {
+		t.Fatal("first edit should be reported")
+	}
+	if postToolContext(t, h, editInput(root, "sess-1", "b.go")) == "" {
+		t.Error("another file should be reported")
+	}
+	if postToolContext(t, h, editInput(root, "sess-2", "a.go")) == "" {
+		t.Error("a new session should be told again")
+	}
+}
+
+func TestPostToolHandler_InjectionDisabled(t *testing.T) {
+	root := newMoAIProject(t)
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+
+	collector := &mockDiagnosticsCollector{
+		getDiagnosticsFunc: func(context.Context, string) ([]lsphook.Diagnostic, error) {
+			return []lsphook.Diagnostic{diagAt(3, lsphook.SeverityError, "missing return")}, nil
+		},
+	}
+	cfg := newTestConfig()
+	cfg.ContextInjection.Enabled = false
+	h := NewPostToolHandlerWithContext(&mockConfigProvider{cfg: cfg}, collector)
+
+	if got := postToolContext(t, h, editInput(root, "sess-1", "a.go")); got != "" {
+		t.Errorf("disabled injection produced %q", got)
+	}
+}
+
+func TestSessionStartHandler_InjectsProjectFacts(t *testing.T) {
+	root := newMoAIProject(t)
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+
+	cfg := newTestConfig()
+	cfg.Project = models.ProjectConfig{Name: "moai-adk", Type: models.ProjectTypeCLI, Language: "go"}
+	cfg.Quality.DevelopmentMode = models.ModeDDD
+	h := NewSessionStartHandler(&mockConfigProvider{cfg: cfg})
+
+	out, err := h.Handle(context.Background(), &HookInput{SessionID: "s", CWD: root})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	if out.HookSpecificOutput == nil || out.HookSpecificOutput.HookEventName != "SessionStart" {
+		t.Fatalf("hookSpecificOutput = %+v", out.HookSpecificOutput)
+	}
+	got := out.HookSpecificOutput.AdditionalContext
+	for _, want := range []string{"- Project: moai-adk (cli, go)", "- Methodology: DDD, coverage target 85%", "- Git strategy: manual"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("context missing %q:\n%s", want, got)
+		}
+	}
+
+	// Outside a MoAI project the defaults are not presented as facts.
+	out, err = h.Handle(context.Background(), &HookInput{SessionID: "s", CWD: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	if out.HookSpecificOutput != nil {
+		t.Errorf("unexpected context outside a project: %+v", out.HookSpecificOutput)
+	}
+}
+
+func TestFitTokenBudget(t *testing.T) {
+	t.Parallel()
+
+	lines := make([]string, 50)
+	for i := range lines {
+		lines[i] = "- L1:1 error: something is wrong here"
+	}
+	text := strings.Join(lines, "\n")
+
+	if got := fitTokenBudget(text, 0); got != text {
+		t.Error("a zero budget should keep the text")
+	}
+	if got := fitTokenBudget("short", 10); got != "short" {
+		t.Errorf("short text changed: %q", got)
+	}
+
+	got := fitTokenBudget(text, 100)
+	if len(got) > 100*charsPerToken {
+		t.Errorf("trimmed text is %d chars, want <= %d", len(got), 100*charsPerToken)
+	}
+	if !strings.HasSuffix(got, "more line(s) omitted") {
+		t.Errorf("trimmed text should note omitted lines:\n%s", got)
+	}
+}
@@ -108,7 +108,7 @@ func TestSessionStartHandler_Handle_NilCfgProvider(t *testing.T) {
 // --- compact.go: Handle ---
 
 func TestCompactHandler_Handle_AlwaysReturnsData(t *testing.T) {
-	t.Parallel()
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
 
 	h := NewCompactHandler()
 	ctx := context.Background()
@@ -136,8 +136,9 @@ func TestCompactHandler_Handle_AlwaysReturnsData(t *testing.T) {
 	if err := json.Unmarshal(got.Data, &data); err != nil {
 		t.Fatalf("unmarshal Data: %v", err)
 	}
-	if data["snapshot_created"] != true {
-		t.Errorf("snapshot_created = %v, want true", data["snapshot_created"])
+	// Outside a MoAI project there is nowhere to write the snapshot.
+	if data["snapshot_created"] != false {
+		t.Errorf("snapshot_created = %v, want false", data["snapshot_created"])
 	}
 }
 
@@ -310,6 +311,7 @@ func TestRankSessionHandler_Handle_WithAPIKey_SubmitFails(t *testing.T) {
 	// Handler with an API key but invalid server → submit fails → returns empty output (non-blocking).
 	cred := &mockCredStore{apiKey: "test-api-key", hasCredentials: true}
 	h := NewRankSessionHandler(nil, cred)
+	h.(*rankSessionHandler).outboxDir = t.TempDir()
 	ctx := context.Background()
 
 	input := &HookInput{
@@ -1000,6 +1002,7 @@ func TestRankSessionHandler_Handle_PatternStoreChecksProjectDir(t *testing.T) {
 	}
 
 	h := NewRankSessionHandler(patternStore, cred)
+	h.(*rankSessionHandler).outboxDir = t.TempDir()
 	ctx := context.Background()
 
 	// Non-excluded project with API key → will attempt submit (which will fail without server).
@@ -0,0 +1,89 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/hook/audit"
+	"github.com/modu-ai/moai-adk/internal/hook/replay"
+)
+
+// replayKey marks the context of a fixture replay.
+type replayKey struct{}
+
+// isReplay reports whether ctx belongs to a fixture replay, whose
+// dispatches are neither audited nor recorded. Handlers check it to skip
+// side effects such as setup commands, archives, snapshots and cache writes.
+func isReplay(ctx context.Context) bool {
+	return ctx.Value(replayKey{}) != nil
+}
+
+// recordFixture saves the input of a dispatch as a fixture when a
+// recording session is active in the project. Failures are logged and
+// never affect the hook result.
+func (r *registry) recordFixture(event EventType, input *HookInput, output *HookOutput) {
+	root := resolveProjectRoot(input)
+	if root == "" {
+		return
+	}
+	session, err := replay.Active(root)
+	if err != nil {
+		slog.Warn("failed to read hook recording session", "error", err.Error())
+		return
+	}
+	if session == nil || !session.Records(string(event)) {
+		return
+	}
+
+	raw, err := json.Marshal(input)
+	if err != nil {
+		slog.Warn("failed to record hook fixture", "error", err.Error())
+		return
+	}
+	fixture, err := replay.NewFixture(string(event), raw, root, dispatchExpectation(output, nil), time.Now())
+	if err == nil {
+		_, err = replay.Save(session.FixtureDir(root), fixture)
+	}
+	if err != nil {
+		slog.Warn("failed to record hook fixture", "error", err.Error())
+	}
+}
+
+// dispatchExpectation reduces the result of a dispatch to the expectation
+// stored in fixtures.
+func dispatchExpectation(output *HookOutput, err error) replay.Expectation {
+	switch {
+	case errors.Is(err, ErrHookTimeout):
+		return replay.Expectation{Decision: audit.DecisionTimeout, Reason: err.Error()}
+	case err != nil:
+		return replay.Expectation{Decision: audit.DecisionError, Reason: err.Error()}
+	}
+	decision, reason := outputDecision(output)
+	exp := replay.Expectation{Decision: decision, Reason: reason}
+	if output != nil {
+		exp.RuleID = output.RuleID
+		exp.Rewrites = output.Rewrites
+	}
+	return exp
+}
+
+// ReplayFixture dispatches the input of a fixture in the project rooted at
+// projectRoot and returns the outcome.
+// Replays are not written to the audit log, never recorded, and run the
+// handlers without their side effects, so handlers that act rather than
+// decide reproduce only their decision.
+func ReplayFixture(ctx context.Context, reg Registry, projectRoot string, f *replay.Fixture) (replay.Expectation, error) {
+	var input HookInput
+	if err := json.Unmarshal(f.ExpandInput(projectRoot), &input); err != nil {
+		return replay.Expectation{}, fmt.Errorf("parse fixture input: %w", err)
+	}
+	if input.CWD == "" {
+		input.CWD = projectRoot
+	}
+	output, err := reg.Dispatch(context.WithValue(ctx, replayKey{}, true), EventType(f.Event), &input)
+	return dispatchExpectation(output, err), nil
+}
@@ -0,0 +1,146 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/agentstats"
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/contextsearch"
+	"github.com/modu-ai/moai-adk/internal/hook/audit"
+	"github.com/modu-ai/moai-adk/internal/hook/replay"
+)
+
+func TestRegistryRecordsAndReplaysFixtures(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	root := newMoAIProject(t)
+
+	reg := NewRegistry(&mockConfigProvider{cfg: newTestConfig()})
+	reg.Register(NewPreToolHandler(&mockConfigProvider{cfg: newTestConfig()}, DefaultSecurityPolicy()))
+
+	dispatch := func(command string) {
+		t.Helper()
+		input := &HookInput{SessionID: "sess-1", CWD: root, HookEventName: "PreToolUse", ToolName: "Bash", ToolInput: json.RawMessage(`{"command":"` + command + `"}`)}
+		if _, err := reg.Dispatch(context.Background(), EventPreToolUse, input); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Nothing is recorded without a session.
+	dispatch("ls")
+	dir := filepath.Join(root, replay.DefaultDir)
+	if fixtures, _ := replay.Load(dir); len(fixtures) != 0 {
+		t.Fatalf("fixtures recorded without a session: %d", len(fixtures))
+	}
+
+	if err := replay.Start(root, replay.Session{Started: time.Now(), Events: []string{"PreToolUse"}}); err != nil {
+		t.Fatal(err)
+	}
+	dispatch("ls")
+	dispatch("rm -rf /")
+	dispatch("ls")
+	if _, err := reg.Dispatch(context.Background(), EventStop, &HookInput{SessionID: "sess-1", CWD: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures, err := replay.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) != 2 {
+		t.Fatalf("recorded %d fixtures, want 2 distinct PreToolUse inputs", len(fixtures))
+	}
+	var deny *replay.Fixture
+	for _, f := range fixtures {
+		if strings.Contains(string(f.Input), root) {
+			t.Errorf("fixture contains the project root: %s", f.Input)
+		}
+		if f.Expect.Decision == audit.DecisionDeny {
+			deny = f
+		}
+	}
+	if deny == nil || !strings.HasPrefix(deny.Expect.RuleID, "bash-dangerous-") {
+		t.Fatalf("no deny fixture with a rule: %+v", fixtures)
+	}
+
+	// Replays reproduce the decision, in another checkout too, and are
+	// neither audited nor recorded.
+	before, _ := audit.Read(filepath.Join(root, ".moai", "logs", "hooks"), audit.Filter{})
+	other := newMoAIProject(t)
+	for _, projectRoot := range []string{root, other} {
+		got, err := ReplayFixture(context.Background(), reg, projectRoot, deny)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !deny.Expect.Matches(got) {
+			t.Errorf("replay in %s = %s, want %s", projectRoot, got, deny.Expect)
+		}
+	}
+	after, _ := audit.Read(filepath.Join(root, ".moai", "logs", "hooks"), audit.Filter{})
+	if len(after) != len(before) {
+		t.Errorf("replay wrote %d audit records", len(after)-len(before))
+	}
+	if again, _ := replay.Load(dir); len(again) != 2 {
+		t.Errorf("replay recorded fixtures: %d", len(again))
+	}
+}
+
+func TestReplayFixture_SkipsSideEffects(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	main, wt := newWorktreeProject(t)
+	writeProjectFile(t, wt, "README.md", "hello\nuncommitted\n")
+	writePastSession(t, main)
+
+	cfg := newTestConfig()
+	cfg.Worktree.Bootstrap.Setup = []config.WorktreeCommand{{Command: "touch", Args: []string{"setup-ran"}}}
+	cfg.ContextSearch.Search.DateRangeDays = 0
+	provider := &mockConfigProvider{cfg: cfg}
+	reg := NewRegistry(provider)
+	reg.Register(NewWorktreeCreateHandlerWithConfig(provider))
+	reg.Register(NewWorktreeRemoveHandlerWithConfig(provider))
+	reg.Register(NewSubagentStopHandlerWithConfig(provider))
+	reg.Register(NewCompactHandlerWithConfig(provider))
+	reg.Register(NewUserPromptSubmitHandlerWithConfig(provider))
+
+	inputs := map[EventType]*HookInput{
+		EventWorktreeCreate:   {SessionID: "s1", CWD: main, WorktreePath: wt},
+		EventWorktreeRemove:   {SessionID: "s1", CWD: main, WorktreePath: wt, WorktreeBranch: "agent/a"},
+		EventSubagentStop:     {SessionID: "s1", CWD: main, AgentID: "agent-1"},
+		EventPreCompact:       {SessionID: "s1", CWD: main},
+		EventUserPromptSubmit: {SessionID: "s1", CWD: main, Prompt: "token refresh is failing again"},
+	}
+	for event, input := range inputs {
+		raw, err := json.Marshal(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := replay.NewFixture(string(event), raw, main, replay.Expectation{}, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReplayFixture(context.Background(), reg, main, f); err != nil {
+			t.Fatalf("replay %s: %v", event, err)
+		}
+	}
+
+	for _, path := range []string{
+		filepath.Join(wt, ".env"),
+		filepath.Join(wt, "setup-ran"),
+		filepath.Join(main, ".moai", "worktrees", "archive"),
+		agentstats.RunsPath(main),
+		filepath.Join(main, ".moai", "memory", "snapshots"),
+		filepath.Join(contextsearch.DefaultDir(main), "index.json"),
+	} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("replay wrote %s", path)
+		}
+	}
+	if branches := gitIn(t, main, "branch", "--list", "archive/*"); branches != "" {
+		t.Errorf("replay created archive branches: %s", branches)
+	}
+}
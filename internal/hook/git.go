@@ -0,0 +1,45 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// gitCommandTimeout bounds a single git command run by a hook.
+const gitCommandTimeout = 30 * time.Second
+
+// gitOutput runs git in dir and returns its stdout without trailing
+// whitespace, so porcelain status lines keep their leading columns. Extra
+// environment entries are appended to the current environment.
+func gitOutput(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	out, err := gitRawOutput(ctx, dir, env, args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), " \t\r\n"), nil
+}
+
+// gitRawOutput runs git like gitOutput but returns its stdout unchanged,
+// as output such as patches must be kept byte for byte.
+func gitRawOutput(ctx context.Context, dir string, env []string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,112 @@
+package hook
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/gitwatch"
+)
+
+// GitWatchStartFunc launches the background git watcher for a session.
+// It is provided by the CLI layer, which knows how to re-execute moai.
+type GitWatchStartFunc func(projectRoot, sessionID string) error
+
+// gitWatchHandler ties the git watcher to the session lifecycle:
+// SessionStart starts it, SessionEnd stops it, Stop marks the end of a
+// turn and UserPromptSubmit reports the changes made outside Claude since.
+type gitWatchHandler struct {
+	event EventType
+	start GitWatchStartFunc
+}
+
+// NewGitWatchHandlers creates the SessionStart, SessionEnd, Stop and
+// UserPromptSubmit handlers of the git watcher. Setting MOAI_GIT_WATCH to
+// 0, false or off disables them.
+func NewGitWatchHandlers(start GitWatchStartFunc) []Handler {
+	events := []EventType{EventSessionStart, EventSessionEnd, EventStop, EventUserPromptSubmit}
+	handlers := make([]Handler, 0, len(events))
+	for _, ev := range events {
+		handlers = append(handlers, &gitWatchHandler{event: ev, start: start})
+	}
+	return handlers
+}
+
+// EventType returns the event the handler was created for.
+func (h *gitWatchHandler) EventType() EventType {
+	return h.event
+}
+
+// Handle runs the lifecycle step for the event. Errors are logged and never
+// block the session.
+func (h *gitWatchHandler) Handle(ctx context.Context, input *HookInput) (*HookOutput, error) {
+	if gitWatchDisabled() {
+		return &HookOutput{}, nil
+	}
+	root := resolveProjectRoot(input)
+	if root == "" {
+		return &HookOutput{}, nil
+	}
+
+	switch h.event {
+	case EventSessionStart:
+		if h.start == nil {
+			break
+		}
+		if _, err := os.Stat(filepath.Join(root, ".git")); err != nil {
+			break
+		}
+		if err := h.start(root, input.SessionID); err != nil {
+			slog.Warn("failed to start git watcher", "error", err)
+		}
+	case EventSessionEnd:
+		if err := gitwatch.Stop(root); err != nil {
+			slog.Warn("failed to stop git watcher", "error", err)
+		}
+	case EventStop:
+		if _, ok := gitwatch.Running(root); ok {
+			if err := gitwatch.NewStore(root).SetCursor(time.Now()); err != nil {
+				slog.Warn("failed to record end of turn", "error", err)
+			}
+		}
+	case EventUserPromptSubmit:
+		return h.reportChanges(root), nil
+	}
+	return &HookOutput{}, nil
+}
+
+// reportChanges returns the changes recorded since the last turn as
+// additional context and advances the cursor past them.
+func (h *gitWatchHandler) reportChanges(root string) *HookOutput {
+	store := gitwatch.NewStore(root)
+	events, err := store.Pending()
+	if err != nil {
+		slog.Warn("failed to read git watcher events", "error", err)
+		return &HookOutput{}
+	}
+	summary := gitwatch.Summarize(events)
+	if summary == "" {
+		return &HookOutput{}
+	}
+	if err := store.SetCursor(time.Now()); err != nil {
+		slog.Warn("failed to advance git watcher cursor", "error", err)
+	}
+	return &HookOutput{
+		HookSpecificOutput: &HookSpecificOutput{
+			HookEventName:     string(EventUserPromptSubmit),
+			AdditionalContext: summary,
+		},
+	}
+}
+
+// gitWatchDisabled reports whether MOAI_GIT_WATCH turns the watcher off.
+func gitWatchDisabled() bool {
+	switch strings.ToLower(os.Getenv("MOAI_GIT_WATCH")) {
+	case "0", "false", "off":
+		return true
+	}
+	return false
+}
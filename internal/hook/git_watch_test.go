@@ -0,0 +1,100 @@
+package hook
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	coregit "github.com/modu-ai/moai-adk/internal/core/git"
+	"github.com/modu-ai/moai-adk/internal/gitwatch"
+)
+
+func findGitWatchHandler(t *testing.T, handlers []Handler, event EventType) Handler {
+	t.Helper()
+	for _, h := range handlers {
+		if h.EventType() == event {
+			return h
+		}
+	}
+	t.Fatalf("no git watch handler for %s", event)
+	return nil
+}
+
+func TestGitWatchHandlers(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{".moai", ".git"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("CLAUDE_PROJECT_DIR", root)
+	t.Setenv("MOAI_GIT_WATCH", "")
+
+	var started string
+	handlers := NewGitWatchHandlers(func(projectRoot, sessionID string) error {
+		started = projectRoot + "|" + sessionID
+		return nil
+	})
+	if len(handlers) != 4 {
+		t.Fatalf("NewGitWatchHandlers() returned %d handlers", len(handlers))
+	}
+	ctx := context.Background()
+	input := &HookInput{SessionID: "s1"}
+
+	if _, err := findGitWatchHandler(t, handlers, EventSessionStart).Handle(ctx, input); err != nil {
+		t.Fatal(err)
+	}
+	if started != root+"|s1" {
+		t.Errorf("start called with %q", started)
+	}
+
+	// Nothing to report yet.
+	prompt := findGitWatchHandler(t, handlers, EventUserPromptSubmit)
+	out, err := prompt.Handle(ctx, input)
+	if err != nil || out.HookSpecificOutput != nil {
+		t.Fatalf("Handle() = %+v, %v", out, err)
+	}
+
+	store := gitwatch.NewStore(root)
+	if err := store.Append(gitwatch.Event{Time: time.Now(), Type: coregit.EventBranchSwitch, PreviousBranch: "main", Branch: "hotfix"}); err != nil {
+		t.Fatal(err)
+	}
+	out, err = prompt.Handle(ctx, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.HookSpecificOutput == nil || out.HookSpecificOutput.HookEventName != "UserPromptSubmit" ||
+		!strings.Contains(out.HookSpecificOutput.AdditionalContext, "main → hotfix") {
+		t.Fatalf("Handle() = %+v", out.HookSpecificOutput)
+	}
+	// The report advances the cursor, so it is not repeated.
+	if out, _ := prompt.Handle(ctx, input); out.HookSpecificOutput != nil {
+		t.Errorf("changes reported twice: %+v", out.HookSpecificOutput)
+	}
+}
+
+func TestGitWatchHandlers_Disabled(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{".moai", ".git"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("CLAUDE_PROJECT_DIR", root)
+	t.Setenv("MOAI_GIT_WATCH", "off")
+
+	called := false
+	handlers := NewGitWatchHandlers(func(string, string) error {
+		called = true
+		return nil
+	})
+	if _, err := findGitWatchHandler(t, handlers, EventSessionStart).Handle(context.Background(), &HookInput{SessionID: "s1"}); err != nil {
+		t.Fatal(err)
+	}
+	if called {
+		t.Error("watcher started with MOAI_GIT_WATCH=off")
+	}
+}
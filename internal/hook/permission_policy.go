@@ -0,0 +1,262 @@
+package hook
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/hook/audit"
+)
+
+// permissionPolicySettings returns the permission policy of cfg, or the
+// defaults when no configuration is available.
+func permissionPolicySettings(cfg ConfigProvider) config.PermissionPolicyConfig {
+	if cfg != nil {
+		if c := cfg.Get(); c != nil {
+			return c.PermissionPolicy
+		}
+	}
+	return config.NewDefaultPermissionPolicyConfig()
+}
+
+// permissionTarget is what a permission request asks for.
+type permissionTarget struct {
+	tool    string
+	command string
+	// path is the file of the tool relative to the project root, with
+	// forward slashes; it is empty for files outside the project.
+	path string
+	mode string
+}
+
+// newPermissionTarget describes the tool call of a permission request in
+// the project rooted at projectRoot.
+func newPermissionTarget(input *HookInput, projectRoot string) permissionTarget {
+	t := permissionTarget{tool: input.ToolName, mode: input.PermissionMode}
+	var fields struct {
+		Command      string `json:"command"`
+		FilePath     string `json:"file_path"`
+		NotebookPath string `json:"notebook_path"`
+	}
+	if len(input.ToolInput) == 0 || json.Unmarshal(input.ToolInput, &fields) != nil {
+		return t
+	}
+	t.command = strings.TrimSpace(fields.Command)
+
+	file := cmp.Or(fields.FilePath, fields.NotebookPath)
+	if file == "" {
+		return t
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(input.CWD, file)
+	}
+	if projectRoot == "" || !filepath.IsAbs(file) {
+		return t
+	}
+	if rel, err := filepath.Rel(projectRoot, file); err == nil && filepath.IsLocal(rel) {
+		t.path = filepath.ToSlash(rel)
+	}
+	return t
+}
+
+// matchPermissionRule reports whether rule applies to t. A rule without a
+// tool, path or command scope matches nothing.
+func matchPermissionRule(rule config.PermissionRule, t permissionTarget) bool {
+	if len(rule.Tools) == 0 && len(rule.Paths) == 0 && len(rule.Commands) == 0 {
+		return false
+	}
+	if len(rule.Tools) > 0 && !slices.ContainsFunc(rule.Tools, func(tool string) bool { return strings.EqualFold(tool, t.tool) }) {
+		return false
+	}
+	if len(rule.Modes) > 0 && !slices.Contains(rule.Modes, t.mode) {
+		return false
+	}
+	if len(rule.Paths) > 0 && (t.path == "" || !slices.ContainsFunc(rule.Paths, func(p string) bool { return matchPathGlob(p, t.path) })) {
+		return false
+	}
+	if len(rule.Commands) > 0 && (t.command == "" || !slices.ContainsFunc(rule.Commands, func(p string) bool { return commandHasPrefix(t.command, p) })) {
+		return false
+	}
+	return true
+}
+
+// matchPathGlob matches a slash-separated relative path against a glob in
+// which ** stands for any number of directories.
+func matchPathGlob(pattern, rel string) bool {
+	return matchGlobSegments(strings.Split(strings.Trim(pattern, "/"), "/"), strings.Split(rel, "/"))
+}
+
+func matchGlobSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlobSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, err := path.Match(pattern[0], segments[0])
+	return err == nil && ok && matchGlobSegments(pattern[1:], segments[1:])
+}
+
+// shellControl matches commands that chain, substitute or redirect, whose
+// prefix says nothing about what they run.
+var shellControl = regexp.MustCompile("[;&|<>`\\n]|\\$\\(")
+
+// commandHasPrefix reports whether command starts with the words of
+// prefix and runs nothing else.
+func commandHasPrefix(command, prefix string) bool {
+	if shellControl.MatchString(command) {
+		return false
+	}
+	words, want := strings.Fields(command), strings.Fields(prefix)
+	return len(want) > 0 && len(words) >= len(want) && slices.Equal(words[:len(want)], want)
+}
+
+// permissionSubject summarizes what a permission request is for in the
+// audit log: the leading words of a command or the project-relative file.
+// Arguments that may carry data are left out.
+func permissionSubject(input *HookInput, projectRoot string) string {
+	t := newPermissionTarget(input, projectRoot)
+	if t.path != "" {
+		return t.path
+	}
+	if t.command == "" || shellControl.MatchString(t.command) {
+		return ""
+	}
+	var words []string
+	for i, w := range strings.Fields(t.command) {
+		if i > 0 && (len(words) == 2 || strings.ContainsAny(w, "-=/.:'\"$")) {
+			break
+		}
+		if i == 0 && strings.Contains(w, "=") {
+			return ""
+		}
+		words = append(words, w)
+	}
+	return strings.Join(words, " ")
+}
+
+// PermissionSuggestion is an allow rule learned from manual approvals.
+type PermissionSuggestion struct {
+	Rule      config.PermissionRule `json:"rule"`
+	Approvals int                   `json:"approvals"`
+	Sessions  int                   `json:"sessions"`
+}
+
+// SuggestPermissionRules proposes allow rules for the permission requests
+// the user approved at least minApprovals times. A request counts as
+// approved when the tool ran afterwards: a PostToolUse record with the
+// same session, tool and input follows the PermissionRequest record.
+// Commands are grouped by their leading words, files by their top-level
+// directory. Requests existing rules already decide are skipped.
+func SuggestPermissionRules(records []audit.Record, minApprovals int, existing []config.PermissionRule) []PermissionSuggestion {
+	type pendingKey struct{ session, tool, digest string }
+	pending := map[pendingKey]string{}
+
+	type group struct {
+		rule     config.PermissionRule
+		count    int
+		sessions map[string]bool
+	}
+	groups := map[string]*group{}
+	var order []string
+
+	for _, r := range records {
+		key := pendingKey{r.SessionID, r.ToolName, r.InputDigest}
+		switch r.Event {
+		case string(EventPermissionRequest):
+			if r.Decision == audit.DecisionAsk && r.Subject != "" && r.InputDigest != "" {
+				pending[key] = r.Subject
+			}
+			continue
+		case string(EventPostToolUse):
+		default:
+			continue
+		}
+		subject, ok := pending[key]
+		if !ok {
+			continue
+		}
+		delete(pending, key)
+
+		rule := suggestedRule(r.ToolName, subject)
+		if slices.ContainsFunc(existing, func(e config.PermissionRule) bool {
+			return matchPermissionRule(e, permissionTarget{tool: r.ToolName, command: subject, path: subjectPath(r.ToolName, subject)})
+		}) {
+			continue
+		}
+		g, ok := groups[rule.ID]
+		if !ok {
+			g = &group{rule: rule, sessions: map[string]bool{}}
+			groups[rule.ID] = g
+			order = append(order, rule.ID)
+		}
+		if !slices.Contains(g.rule.Tools, r.ToolName) {
+			g.rule.Tools = append(g.rule.Tools, r.ToolName)
+		}
+		g.count++
+		g.sessions[r.SessionID] = true
+	}
+
+	var suggestions []PermissionSuggestion
+	for _, id := range order {
+		g := groups[id]
+		if g.count < max(minApprovals, 1) {
+			continue
+		}
+		suggestions = append(suggestions, PermissionSuggestion{Rule: g.rule, Approvals: g.count, Sessions: len(g.sessions)})
+	}
+	slices.SortStableFunc(suggestions, func(a, b PermissionSuggestion) int { return cmp.Compare(b.Approvals, a.Approvals) })
+	return suggestions
+}
+
+// subjectPath returns the file path of an audit subject, or "" for a
+// command.
+func subjectPath(tool, subject string) string {
+	if tool == "Bash" {
+		return ""
+	}
+	return subject
+}
+
+// suggestedRule generalizes an approved subject to an allow rule: a command
+// prefix for Bash, the top-level directory for files.
+func suggestedRule(tool, subject string) config.PermissionRule {
+	if tool == "Bash" {
+		return config.PermissionRule{
+			ID:       "allow-" + ruleSlug(subject),
+			Decision: DecisionAllow,
+			Commands: []string{subject},
+			Reason:   fmt.Sprintf("Approved %q before.", subject),
+		}
+	}
+	glob := subject
+	if dir, _, ok := strings.Cut(subject, "/"); ok {
+		glob = dir + "/**"
+	}
+	return config.PermissionRule{
+		ID:       "allow-files-" + ruleSlug(strings.TrimSuffix(glob, "/**")),
+		Decision: DecisionAllow,
+		Paths:    []string{glob},
+		Reason:   fmt.Sprintf("Approved changes to %s before.", glob),
+	}
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+func ruleSlug(s string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
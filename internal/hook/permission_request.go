@@ -1,38 +1,106 @@
 package hook
 
 import (
+	"cmp"
 	"context"
+	"fmt"
 	"log/slog"
+	"os"
 )
 
 // permissionRequestHandler processes PermissionRequest events.
-// It logs permission requests and defers to the default decision.
-type permissionRequestHandler struct{}
+// Without a configuration it logs the request and defers to the user. With
+// one, it decides the request by the PreToolUse security policy and the
+// permission_policy rules of the project.
+type permissionRequestHandler struct {
+	cfg ConfigProvider
+	// checks runs the PreToolUse security checks; nil skips them.
+	checks *preToolHandler
+}
 
 // NewPermissionRequestHandler creates a new PermissionRequest event handler.
 func NewPermissionRequestHandler() Handler {
 	return &permissionRequestHandler{}
 }
 
+// NewPermissionRequestHandlerWithConfig creates a PermissionRequest handler
+// deciding requests by policy and the permission rules of cfg.
+func NewPermissionRequestHandlerWithConfig(cfg ConfigProvider, policy *SecurityPolicy) Handler {
+	h := &permissionRequestHandler{cfg: cfg}
+	if policy != nil {
+		projectDir := os.Getenv("CLAUDE_PROJECT_DIR")
+		if projectDir == "" {
+			projectDir, _ = os.Getwd()
+		}
+		h.checks = &preToolHandler{cfg: cfg, policy: policy, projectDir: projectDir}
+	}
+	return h
+}
+
 // EventType returns EventPermissionRequest.
 func (h *permissionRequestHandler) EventType() EventType {
 	return EventPermissionRequest
 }
 
-// Handle processes a PermissionRequest event. It logs the permission request
-// and returns "ask" (defer to user/default settings).
+// Handle processes a PermissionRequest event. The security policy of
+// PreToolUse is checked first, so a rule can never allow what PreToolUse
+// denies or asks about. Otherwise the first matching permission rule
+// decides; without one the handler returns "ask" (defer to the user).
 func (h *permissionRequestHandler) Handle(ctx context.Context, input *HookInput) (*HookOutput, error) {
 	slog.Info("permission requested",
 		"session_id", input.SessionID,
 		"tool_name", input.ToolName,
+		"permission_mode", input.PermissionMode,
 	)
+
+	if h.checks != nil {
+		if output := h.checks.blockedToolOutput(input.ToolName); output != nil {
+			return output, nil
+		}
+		decision, reason, rule := h.checks.checkToolInput(ctx, input.ToolName, input.ToolInput)
+		if output := policyOutput(decision, reason, rule); output != nil {
+			return output, nil
+		}
+	}
+
+	if h.cfg != nil {
+		if output := h.decideByRules(input); output != nil {
+			return output, nil
+		}
+	}
+
 	// Default to "ask" - defer decision to user/settings.
 	// Per Claude Code protocol, hookSpecificOutput.hookEventName must be "PreToolUse"
 	// (not "PermissionRequest") because PermissionRequest shares the PreToolUse output schema.
-	return &HookOutput{
-		HookSpecificOutput: &HookSpecificOutput{
-			HookEventName:      "PreToolUse",
-			PermissionDecision: DecisionAsk,
-		},
-	}, nil
+	return NewPermissionRequestOutput(DecisionAsk, ""), nil
+}
+
+// decideByRules returns the output of the first permission rule matching
+// the request, or nil when none does.
+func (h *permissionRequestHandler) decideByRules(input *HookInput) *HookOutput {
+	settings := permissionPolicySettings(h.cfg)
+	if !settings.Enabled || len(settings.Rules) == 0 {
+		return nil
+	}
+	target := newPermissionTarget(input, resolveProjectRoot(input))
+	for i, r := range settings.Rules {
+		decision := cmp.Or(r.Decision, DecisionAllow)
+		if decision != DecisionAllow && decision != DecisionDeny && decision != DecisionAsk {
+			slog.Warn("skipping permission rule with unknown decision", "rule", r.ID, "decision", r.Decision)
+			continue
+		}
+		if !matchPermissionRule(r, target) {
+			continue
+		}
+		id := cmp.Or(r.ID, fmt.Sprintf("permission-%d", i))
+		slog.Info("permission decided by rule",
+			"tool_name", input.ToolName,
+			"rule", id,
+			"decision", decision,
+		)
+		output := NewPermissionRequestOutput(decision, cmp.Or(r.Reason, fmt.Sprintf("Permission rule %s: %s.", id, decision)))
+		output.RuleID = id
+		return output
+	}
+	return nil
 }
@@ -2,7 +2,13 @@ package hook
 
 import (
 	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/hook/audit"
 )
 
 func TestPermissionRequestHandler_EventType(t *testing.T) {
@@ -72,3 +78,90 @@ func TestPermissionRequestHandler_Handle(t *testing.T) {
 		})
 	}
 }
+
+func TestPermissionRequestHandler_Policy(t *testing.T) {
+	root, _ := filepath.EvalSymlinks(newMoAIProject(t))
+	t.Setenv("CLAUDE_PROJECT_DIR", root)
+
+	cfg := newTestConfig()
+	cfg.PermissionPolicy.Rules = []config.PermissionRule{
+		{ID: "allow-go-test", Tools: []string{"Bash"}, Commands: []string{"go test"}},
+		{ID: "deny-vendor", Decision: DecisionDeny, Paths: []string{"vendor/**"}, Reason: "vendor/ is generated."},
+		{Tools: []string{"Edit"}, Paths: []string{"internal/**"}, Modes: []string{"acceptEdits"}},
+		{ID: "allow-all-bash", Tools: []string{"Bash"}},
+	}
+	h := NewPermissionRequestHandlerWithConfig(&mockConfigProvider{cfg: cfg}, DefaultSecurityPolicy())
+	fileInput := func(path string) json.RawMessage {
+		data, _ := json.Marshal(map[string]string{"file_path": filepath.Join(root, path)})
+		return data
+	}
+
+	tests := []struct {
+		name         string
+		input        *HookInput
+		wantDecision string
+		wantRule     string // prefix of the rule ID
+	}{
+		{"allowed command", &HookInput{ToolName: "Bash", ToolInput: bashInput(t, "go test ./...")}, DecisionAllow, "allow-go-test"},
+		{"security deny comes first", &HookInput{ToolName: "Bash", ToolInput: bashInput(t, "rm -rf /")}, DecisionDeny, "bash-dangerous-"},
+		{"security ask is never allowed", &HookInput{ToolName: "Bash", ToolInput: bashInput(t, "git reset --hard")}, DecisionAsk, "bash-ask-"},
+		{"denied path", &HookInput{ToolName: "Write", ToolInput: fileInput("vendor/x/a.go")}, DecisionDeny, "deny-vendor"},
+		{"mode scoped rule", &HookInput{ToolName: "Edit", PermissionMode: "acceptEdits", ToolInput: fileInput("internal/a.go")}, DecisionAllow, "permission-2"},
+		{"mode not matched", &HookInput{ToolName: "Edit", PermissionMode: "default", ToolInput: fileInput("internal/a.go")}, DecisionAsk, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.CWD = root
+			got, err := h.Handle(context.Background(), tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := got.HookSpecificOutput.PermissionDecision; d != tt.wantDecision {
+				t.Errorf("PermissionDecision = %q, want %q", d, tt.wantDecision)
+			}
+			if tt.wantRule != "" && !strings.HasPrefix(got.RuleID, tt.wantRule) {
+				t.Errorf("RuleID = %q, want %q", got.RuleID, tt.wantRule)
+			}
+			if got.HookSpecificOutput.HookEventName != "PreToolUse" {
+				t.Errorf("HookEventName = %q", got.HookSpecificOutput.HookEventName)
+			}
+		})
+	}
+
+	cfg.PermissionPolicy.Enabled = false
+	got, _ := h.Handle(context.Background(), &HookInput{CWD: root, ToolName: "Bash", ToolInput: bashInput(t, "go test ./...")})
+	if got.HookSpecificOutput.PermissionDecision != DecisionAsk {
+		t.Errorf("disabled policy decided %q", got.HookSpecificOutput.PermissionDecision)
+	}
+}
+
+func TestRegistryDispatchPermissionAllow(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	root := newMoAIProject(t)
+
+	cfg := newTestConfig()
+	cfg.PermissionPolicy.Rules = []config.PermissionRule{{ID: "allow-go-test", Commands: []string{"go test"}, Reason: "Tests are safe."}}
+	reg := NewRegistry(&mockConfigProvider{cfg: cfg})
+	reg.Register(NewPermissionRequestHandlerWithConfig(&mockConfigProvider{cfg: cfg}, DefaultSecurityPolicy()))
+
+	// The allow of a rule overrides the default ask of PermissionRequest.
+	out, err := reg.Dispatch(context.Background(), EventPermissionRequest, &HookInput{SessionID: "s1", CWD: root, ToolName: "Bash", ToolInput: bashInput(t, "go test ./...")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hso := out.HookSpecificOutput; hso.PermissionDecision != DecisionAllow || hso.PermissionDecisionReason != "Tests are safe." || out.RuleID != "allow-go-test" {
+		t.Errorf("dispatch output = %+v, rule %q", hso, out.RuleID)
+	}
+
+	if _, err := reg.Dispatch(context.Background(), EventPermissionRequest, &HookInput{SessionID: "s1", CWD: root, ToolName: "Bash", ToolInput: bashInput(t, "go vet ./...")}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := audit.Read(filepath.Join(root, ".moai", "logs", "hooks"), audit.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Subject != "go test" || records[1].Subject != "go vet" || records[1].Decision != audit.DecisionAsk {
+		t.Errorf("audit records = %+v", records)
+	}
+}
@@ -3,8 +3,10 @@ package hook
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"path/filepath"
+	"strings"
 
 	lsphook "github.com/modu-ai/moai-adk/internal/lsp/hook"
 )
@@ -12,8 +14,10 @@ import (
 // postToolHandler processes PostToolUse events.
 // It collects tool execution metrics and prepares statusline data
 // (REQ-HOOK-033). This handler is observation-only and always returns "allow".
-// Optionally integrates with LSP diagnostics for Write/Edit operations.
+// Optionally integrates with LSP diagnostics for Write/Edit operations and
+// reports new diagnostics to Claude as additionalContext.
 type postToolHandler struct {
+	cfg         ConfigProvider
 	diagnostics lsphook.LSPDiagnosticsCollector
 }
 
@@ -28,6 +32,13 @@ func NewPostToolHandlerWithDiagnostics(diagnostics lsphook.LSPDiagnosticsCollect
 	return &postToolHandler{diagnostics: diagnostics}
 }
 
+// NewPostToolHandlerWithContext creates a PostToolUse handler that collects
+// LSP diagnostics and injects them as additionalContext according to the
+// context_injection settings of cfg.
+func NewPostToolHandlerWithContext(cfg ConfigProvider, diagnostics lsphook.LSPDiagnosticsCollector) Handler {
+	return &postToolHandler{cfg: cfg, diagnostics: diagnostics}
+}
+
 // EventType returns EventPostToolUse.
 func (h *postToolHandler) EventType() EventType {
 	return EventPostToolUse
@@ -65,8 +76,10 @@ func (h *postToolHandler) Handle(ctx context.Context, input *HookInput) (*HookOu
 	}
 
 	// Collect LSP diagnostics for Write/Edit operations (REQ-HOOK-150, REQ-HOOK-153)
+	var additionalContext string
 	if (input.ToolName == "Write" || input.ToolName == "Edit") && h.diagnostics != nil {
-		h.collectDiagnostics(ctx, input, metrics)
+		filePath, diagnostics := h.collectDiagnostics(ctx, input, metrics)
+		additionalContext = h.diagnosticsContext(input, filePath, diagnostics)
 	}
 
 	jsonData, err := json.Marshal(metrics)
@@ -74,30 +87,29 @@ func (h *postToolHandler) Handle(ctx context.Context, input *HookInput) (*HookOu
 		slog.Error("failed to marshal post-tool metrics",
 			"error", err.Error(),
 		)
-		return NewPostToolOutput(""), nil
+		return NewPostToolOutput(additionalContext), nil
 	}
 
-	return &HookOutput{
-		HookSpecificOutput: &HookSpecificOutput{
-			HookEventName: "PostToolUse",
-		},
-		Data: jsonData,
-	}, nil
+	output := NewPostToolOutput(additionalContext)
+	output.Data = jsonData
+	return output, nil
 }
 
-// collectDiagnostics collects LSP diagnostics for the modified file.
+// collectDiagnostics collects LSP diagnostics for the modified file and
+// returns the file path with its diagnostics. The path is empty when
+// nothing was collected.
 // This is observation-only and MUST NOT block per REQ-HOOK-153.
-func (h *postToolHandler) collectDiagnostics(ctx context.Context, input *HookInput, metrics map[string]any) {
+func (h *postToolHandler) collectDiagnostics(ctx context.Context, input *HookInput, metrics map[string]any) (string, []lsphook.Diagnostic) {
 	// Extract file path from tool input
 	var parsed map[string]any
 	if err := json.Unmarshal(input.ToolInput, &parsed); err != nil {
 		slog.Debug("failed to parse tool input for diagnostics", "error", err)
-		return
+		return "", nil
 	}
 
 	filePath, ok := parsed["file_path"].(string)
 	if !ok || filePath == "" {
-		return
+		return "", nil
 	}
 
 	// Get diagnostics (observation only, never block)
@@ -107,7 +119,7 @@ func (h *postToolHandler) collectDiagnostics(ctx context.Context, input *HookInp
 			"file_path", filePath,
 			"error", err,
 		)
-		return
+		return "", nil
 	}
 
 	// Calculate severity counts
@@ -137,4 +149,39 @@ func (h *postToolHandler) collectDiagnostics(ctx context.Context, input *HookInp
 			"file_path", filepath.Base(filePath),
 		)
 	}
+	return filePath, diagnostics
+}
+
+// diagnosticsContext renders the diagnostics of an edited file for Claude.
+// Diagnostics below the configured severity are left out, and a report is
+// only repeated when the diagnostics of the file changed since the last
+// edit. Once a reported file is clean, Claude is told so.
+func (h *postToolHandler) diagnosticsContext(input *HookInput, filePath string, diagnostics []lsphook.Diagnostic) string {
+	settings := injectionSettings(h.cfg)
+	if !settings.Enabled || filePath == "" {
+		return ""
+	}
+
+	root := resolveProjectRoot(input)
+	display := filePath
+	if root != "" {
+		if rel, err := filepath.Rel(root, filePath); err == nil && !strings.HasPrefix(rel, "..") {
+			display = filepath.ToSlash(rel)
+		}
+	}
+
+	shown := reportableDiagnostics(diagnostics, settings.MinSeverity)
+	digest := diagnosticsDigest(shown)
+	lines := make([]string, len(shown))
+	for i, d := range shown {
+		lines[i] = diagnosticLine(d)
+	}
+	prev := newDiagnosticsMemo(root, input.SessionID).swap(display, digest, lines)
+	switch {
+	case digest == prev:
+		return ""
+	case digest == "":
+		return fmt.Sprintf("LSP diagnostics in %s are resolved.", display)
+	}
+	return fitTokenBudget(renderDiagnostics(display, shown), settings.MaxTokens)
 }
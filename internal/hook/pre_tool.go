@@ -193,7 +193,7 @@ func DefaultSecurityPolicy() *SecurityPolicy {
 		`prisma\s+db\s+push\s+--force`,
 		`drizzle-kit\s+push`,
 		// Git force operations (non-main branches)
-		`git\s+push\s+.*--force`,
+		`git\s+push\s+.*--force(\s|$)`,
 		`git\s+reset\s+--hard`,
 		`git\s+clean\s+-fd`,
 		// Package manager cache clear
@@ -282,7 +282,8 @@ func (h *preToolHandler) EventType() EventType {
 // Handle processes a PreToolUse event. It checks the tool name against the
 // blocklist and scans tool input for dangerous patterns. Returns Decision
 // "deny" with a reason if the tool is denied, "ask" if user confirmation is
-// needed, or "allow" otherwise.
+// needed, or "allow" otherwise. When a rewrite rule applies, the output
+// carries the rewritten input as updatedInput with the rules' explanation.
 func (h *preToolHandler) Handle(ctx context.Context, input *HookInput) (*HookOutput, error) {
 	// No policy means allow everything
 	if h.policy == nil {
@@ -294,63 +295,122 @@ func (h *preToolHandler) Handle(ctx context.Context, input *HookInput) (*HookOut
 		"session_id", input.SessionID,
 	)
 
-	// Check if tool is in the blocked list
+	if output := h.blockedToolOutput(input.ToolName); output != nil {
+		return output, nil
+	}
+
+	rewrite := h.rewriteToolInput(input)
+	decision, reason, rule := h.checkToolInput(ctx, input.ToolName, input.ToolInput)
+	if rewrite == nil || decision == DecisionDeny {
+		if output := policyOutput(decision, reason, rule); output != nil {
+			return output, nil
+		}
+		return NewAllowOutput(), nil
+	}
+
+	// The rewritten input is checked again: a rewrite can settle an ask
+	// decision but never lift a deny of the original input.
+	decision, reason, rule = h.checkToolInput(ctx, input.ToolName, rewrite.input)
+	if decision == DecisionDeny {
+		return policyOutput(decision, reason, rule), nil
+	}
+	slog.Info("tool input rewritten",
+		"tool_name", input.ToolName,
+		"rules", strings.Join(rewrite.rules, ","),
+	)
+	output := policyOutput(decision, reason, rule)
+	if output == nil {
+		output = NewAllowOutput()
+		reason = rewrite.explanation()
+	} else {
+		reason += " " + rewrite.explanation()
+	}
+	output.HookSpecificOutput.PermissionDecisionReason = reason
+	output.HookSpecificOutput.UpdatedInput = rewrite.input
+	output.Rewrites = rewrite.rules
+	if !isReplay(ctx) {
+		// A replay must reproduce the rewrite without consuming it.
+		rewrite.remember()
+	}
+	return output, nil
+}
+
+// blockedToolOutput returns the deny output for a tool in the blocked
+// list, or nil when the tool is not blocked.
+func (h *preToolHandler) blockedToolOutput(toolName string) *HookOutput {
 	for _, blocked := range h.policy.BlockedTools {
-		if strings.EqualFold(input.ToolName, blocked) {
-			reason := fmt.Sprintf("tool %q is blocked by security policy", input.ToolName)
+		if strings.EqualFold(toolName, blocked) {
+			reason := fmt.Sprintf("tool %q is blocked by security policy", toolName)
 			slog.Warn("tool blocked",
-				"tool_name", input.ToolName,
+				"tool_name", toolName,
 				"reason", reason,
 			)
-			return NewDenyOutput(reason), nil
+			output := NewDenyOutput(reason)
+			output.RuleID = "tool-blocked"
+			return output
 		}
 	}
+	return nil
+}
+
+// checkToolInput runs the Bash, file access and content checks on a tool
+// input. Returns (decision, reason, rule) where decision is "deny", "ask",
+// or "" for allow.
+func (h *preToolHandler) checkToolInput(ctx context.Context, toolName string, toolInput json.RawMessage) (string, string, string) {
+	if len(toolInput) == 0 {
+		return "", "", ""
+	}
 
 	// Handle Bash commands
-	if input.ToolName == "Bash" && len(input.ToolInput) > 0 {
-		decision, reason := h.checkBashCommand(input.ToolInput)
+	if toolName == "Bash" {
+		decision, reason, rule := h.checkBashCommand(toolInput)
 		if decision != "" {
 			slog.Warn("bash command security check",
-				"tool_name", input.ToolName,
+				"tool_name", toolName,
 				"decision", decision,
 				"reason", reason,
 			)
-			if decision == DecisionDeny {
-				return NewDenyOutput(reason), nil
-			}
-			if decision == DecisionAsk {
-				return NewAskOutput(reason), nil
-			}
 		}
+		return decision, reason, rule
 	}
 
 	// Handle Write and Edit tools
-	if (input.ToolName == "Write" || input.ToolName == "Edit") && len(input.ToolInput) > 0 {
-		decision, reason := h.checkFileAccess(input.ToolInput, input.ToolName)
+	if toolName == "Write" || toolName == "Edit" {
+		decision, reason, rule := h.checkFileAccess(toolInput, toolName)
 		if decision != "" {
 			slog.Warn("file access security check",
-				"tool_name", input.ToolName,
+				"tool_name", toolName,
 				"decision", decision,
 				"reason", reason,
 			)
-			if decision == DecisionDeny {
-				return NewDenyOutput(reason), nil
-			}
-			if decision == DecisionAsk {
-				return NewAskOutput(reason), nil
-			}
+			return decision, reason, rule
 		}
 
 		// AST-based security scanning for Write operations
-		if input.ToolName == "Write" && h.scanner != nil {
-			decision, reason := h.scanWriteContent(ctx, input.ToolInput)
-			if decision == DecisionDeny {
-				return NewDenyOutput(reason), nil
+		if toolName == "Write" && h.scanner != nil {
+			if decision, reason := h.scanWriteContent(ctx, toolInput); decision == DecisionDeny {
+				return decision, reason, "security-scan"
 			}
 		}
 	}
 
-	return NewAllowOutput(), nil
+	return "", "", ""
+}
+
+// policyOutput builds the deny or ask output of a policy decision tagged
+// with its rule. It returns nil for any other decision.
+func policyOutput(decision, reason, rule string) *HookOutput {
+	var output *HookOutput
+	switch decision {
+	case DecisionDeny:
+		output = NewDenyOutput(reason)
+	case DecisionAsk:
+		output = NewAskOutput(reason)
+	default:
+		return nil
+	}
+	output.RuleID = rule
+	return output
 }
 
 // scanWriteContent scans the content to be written using AST-based security scanner.
@@ -432,52 +492,54 @@ func (h *preToolHandler) scanWriteContent(ctx context.Context, toolInput json.Ra
 }
 
 // checkBashCommand checks a Bash command against dangerous and ask patterns.
-// Returns (decision, reason) where decision is "deny", "ask", or "" for allow.
-func (h *preToolHandler) checkBashCommand(toolInput json.RawMessage) (string, string) {
+// Returns (decision, reason, rule) where decision is "deny", "ask", or "" for
+// allow and rule identifies the matching pattern.
+func (h *preToolHandler) checkBashCommand(toolInput json.RawMessage) (string, string, string) {
 	var parsed map[string]any
 	if err := json.Unmarshal(toolInput, &parsed); err != nil {
-		return "", ""
+		return "", "", ""
 	}
 
 	command, ok := parsed["command"].(string)
 	if !ok || command == "" {
-		return "", ""
+		return "", "", ""
 	}
 
 	// Check dangerous patterns (deny)
-	for _, pattern := range h.policy.DangerousBashPatterns {
+	for i, pattern := range h.policy.DangerousBashPatterns {
 		if pattern.MatchString(command) {
-			return DecisionDeny, fmt.Sprintf("Dangerous command blocked: %s", pattern.String())
+			return DecisionDeny, fmt.Sprintf("Dangerous command blocked: %s", pattern.String()), fmt.Sprintf("bash-dangerous-%d", i)
 		}
 	}
 
 	// Check ask patterns (require confirmation)
-	for _, pattern := range h.policy.AskBashPatterns {
+	for i, pattern := range h.policy.AskBashPatterns {
 		if pattern.MatchString(command) {
-			return DecisionAsk, "This command may have significant effects. Please confirm."
+			return DecisionAsk, "This command may have significant effects. Please confirm.", fmt.Sprintf("bash-ask-%d", i)
 		}
 	}
 
-	return "", ""
+	return "", "", ""
 }
 
 // checkFileAccess checks file path and content against security patterns.
-// Returns (decision, reason) where decision is "deny", "ask", or "" for allow.
-func (h *preToolHandler) checkFileAccess(toolInput json.RawMessage, toolName string) (string, string) {
+// Returns (decision, reason, rule) where decision is "deny", "ask", or "" for
+// allow and rule identifies the check that matched.
+func (h *preToolHandler) checkFileAccess(toolInput json.RawMessage, toolName string) (string, string, string) {
 	var parsed map[string]any
 	if err := json.Unmarshal(toolInput, &parsed); err != nil {
-		return "", ""
+		return "", "", ""
 	}
 
 	filePath, ok := parsed["file_path"].(string)
 	if !ok || filePath == "" {
-		return "", ""
+		return "", "", ""
 	}
 
 	// Resolve path to prevent path traversal attacks
 	resolvedPath, err := filepath.Abs(filePath)
 	if err != nil {
-		return DecisionDeny, "Invalid file path: cannot resolve"
+		return DecisionDeny, "Invalid file path: cannot resolve", "path-unresolvable"
 	}
 
 	// Check if path is within project directory
@@ -499,7 +561,7 @@ func (h *preToolHandler) checkFileAccess(toolInput json.RawMessage, toolName str
 			if relErr != nil || strings.HasPrefix(rel, "..") {
 				// Before denying, check if path is under an allowed external directory.
 				if !h.isAllowedExternalPath(nfcResolved) {
-					return DecisionDeny, "Path traversal detected: file is outside project directory"
+					return DecisionDeny, "Path traversal detected: file is outside project directory", "path-traversal"
 				}
 			}
 		}
@@ -510,16 +572,16 @@ func (h *preToolHandler) checkFileAccess(toolInput json.RawMessage, toolName str
 	normalizedResolved := strings.ReplaceAll(resolvedPath, "\\", "/")
 
 	// Check deny patterns
-	for _, pattern := range h.policy.DenyPatterns {
+	for i, pattern := range h.policy.DenyPatterns {
 		if pattern.MatchString(normalizedPath) || pattern.MatchString(normalizedResolved) {
-			return DecisionDeny, "Protected file: access denied for security reasons"
+			return DecisionDeny, "Protected file: access denied for security reasons", fmt.Sprintf("file-protected-%d", i)
 		}
 	}
 
 	// Check ask patterns
-	for _, pattern := range h.policy.AskPatterns {
+	for i, pattern := range h.policy.AskPatterns {
 		if pattern.MatchString(normalizedPath) || pattern.MatchString(normalizedResolved) {
-			return DecisionAsk, fmt.Sprintf("Critical config file: %s", filepath.Base(filePath))
+			return DecisionAsk, fmt.Sprintf("Critical config file: %s", filepath.Base(filePath)), fmt.Sprintf("file-ask-%d", i)
 		}
 	}
 
@@ -527,15 +589,15 @@ func (h *preToolHandler) checkFileAccess(toolInput json.RawMessage, toolName str
 	if toolName == "Write" {
 		content, ok := parsed["content"].(string)
 		if ok && content != "" {
-			for _, pattern := range h.policy.SensitiveContentPatterns {
+			for i, pattern := range h.policy.SensitiveContentPatterns {
 				if pattern.MatchString(content) {
-					return DecisionDeny, "Content contains sensitive data (credentials, API keys, or certificates)"
+					return DecisionDeny, "Content contains sensitive data (credentials, API keys, or certificates)", fmt.Sprintf("content-secret-%d", i)
 				}
 			}
 		}
 	}
 
-	return "", ""
+	return "", "", ""
 }
 
 // isAllowedExternalPath checks whether the given absolute path falls under
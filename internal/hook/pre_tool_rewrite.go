@@ -0,0 +1,277 @@
+package hook
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+)
+
+// rewriteActionProjectPath resolves relative paths against the project.
+const rewriteActionProjectPath = "project_path"
+
+// DefaultRewriteRules returns the built-in tool input rewrites. They turn
+// cases that would otherwise need confirmation into safer equivalents.
+func DefaultRewriteRules() []config.RewriteRule {
+	return []config.RewriteRule{
+		{
+			ID:      "strip-sudo",
+			Tool:    "Bash",
+			Field:   "command",
+			Match:   `(^|[;&|(]\s*)sudo\s+([^-\s])`,
+			Replace: "${1}${2}",
+			Reason:  "Removed sudo: commands run with the privileges of the session.",
+		},
+		{
+			ID:      "git-force-with-lease",
+			Tool:    "Bash",
+			Field:   "command",
+			Match:   `(\bgit\s+push\b[^;&|]*?\s)(?:--force|-f)(\s|$)`,
+			Replace: "${1}--force-with-lease${2}",
+			Reason:  "Replaced git push --force with --force-with-lease, which refuses to overwrite commits you have not seen.",
+		},
+		{
+			ID:      "git-clean-dry-run",
+			Tool:    "Bash",
+			Field:   "command",
+			Match:   `(\bgit\s+clean)\b`,
+			Replace: "${1} --dry-run",
+			Unless:  `\bgit\s+clean\b[^;&|]*\s(?:--dry-run|-[a-zA-Z]*n[a-zA-Z]*)(\s|$)`,
+			Once:    true,
+			Reason:  "Added --dry-run to the first git clean; run the command again to delete the listed files.",
+		},
+		{
+			ID:      "kubectl-dry-run",
+			Tool:    "Bash",
+			Field:   "command",
+			Match:   `(\bkubectl\s+(?:apply|delete|replace)\b)`,
+			Replace: "${1} --dry-run=server",
+			Unless:  `--dry-run`,
+			Once:    true,
+			Reason:  "Added --dry-run=server to the first kubectl change; run the command again to apply it.",
+		},
+		{
+			ID:     "project-absolute-path",
+			Tool:   "Read|Write|Edit|MultiEdit",
+			Field:  "file_path",
+			Action: rewriteActionProjectPath,
+			Reason: "Resolved the relative file path against the project directory.",
+		},
+	}
+}
+
+// rewriteSettings returns the rewrite settings of cfg, or the defaults
+// when no configuration is available.
+func rewriteSettings(cfg ConfigProvider) config.ToolRewriteConfig {
+	if cfg != nil {
+		if c := cfg.Get(); c != nil {
+			return c.ToolRewrite
+		}
+	}
+	return config.NewDefaultToolRewriteConfig()
+}
+
+// rewriteRule is a compiled RewriteRule.
+type rewriteRule struct {
+	config.RewriteRule
+	tool, match, unless *regexp.Regexp
+}
+
+// compileRewriteRules compiles the built-in rules not disabled in cfg,
+// followed by the rules of cfg. Invalid rules are logged and skipped.
+func compileRewriteRules(cfg config.ToolRewriteConfig) []rewriteRule {
+	if !cfg.Enabled {
+		return nil
+	}
+	var rules []rewriteRule
+	for _, r := range slices.Concat(DefaultRewriteRules(), cfg.Rules) {
+		if slices.Contains(cfg.Disable, r.ID) {
+			continue
+		}
+		compiled, err := compileRewriteRule(r)
+		if err != nil {
+			slog.Warn("skipping invalid rewrite rule", "rule", r.ID, "error", err.Error())
+			continue
+		}
+		rules = append(rules, compiled)
+	}
+	return rules
+}
+
+func compileRewriteRule(r config.RewriteRule) (rewriteRule, error) {
+	compiled := rewriteRule{RewriteRule: r}
+	if r.ID == "" || r.Field == "" {
+		return compiled, errors.New("id and field are required")
+	}
+	if r.Action != "" && r.Action != rewriteActionProjectPath {
+		return compiled, fmt.Errorf("unknown action %q", r.Action)
+	}
+	if r.Action == "" && r.Match == "" {
+		return compiled, errors.New("match or action is required")
+	}
+	var err error
+	if compiled.tool, err = regexp.Compile("^(?:" + r.Tool + ")$"); err != nil {
+		return compiled, fmt.Errorf("tool: %w", err)
+	}
+	if r.Match != "" {
+		if compiled.match, err = regexp.Compile(r.Match); err != nil {
+			return compiled, fmt.Errorf("match: %w", err)
+		}
+	}
+	if r.Unless != "" {
+		if compiled.unless, err = regexp.Compile(r.Unless); err != nil {
+			return compiled, fmt.Errorf("unless: %w", err)
+		}
+	}
+	return compiled, nil
+}
+
+// apply returns the rewritten value, or value itself when the rule does
+// not apply.
+func (r rewriteRule) apply(value, baseDir string) string {
+	if value == "" || (r.unless != nil && r.unless.MatchString(value)) {
+		return value
+	}
+	if r.Action == rewriteActionProjectPath {
+		if filepath.IsAbs(value) || baseDir == "" {
+			return value
+		}
+		return filepath.Join(baseDir, value)
+	}
+	if !r.match.MatchString(value) {
+		return value
+	}
+	return r.match.ReplaceAllString(value, r.Replace)
+}
+
+// toolRewrite is a rewritten tool input.
+type toolRewrite struct {
+	input   json.RawMessage
+	rules   []string
+	reasons []string
+	// once holds the history keys of the Once rules applied.
+	once    []string
+	history *rewriteHistory
+}
+
+// explanation joins the reasons of the applied rules.
+func (rw *toolRewrite) explanation() string {
+	return strings.Join(rw.reasons, " ")
+}
+
+// rewriteToolInput applies the rewrite rules to the tool input. It returns
+// nil when no rule changed it.
+func (h *preToolHandler) rewriteToolInput(input *HookInput) *toolRewrite {
+	if len(input.ToolInput) == 0 {
+		return nil
+	}
+	rules := compileRewriteRules(rewriteSettings(h.cfg))
+	if len(rules) == 0 {
+		return nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(input.ToolInput, &fields); err != nil {
+		return nil
+	}
+
+	baseDir := input.CWD
+	if baseDir == "" {
+		baseDir = h.projectDir
+	}
+	rw := &toolRewrite{}
+	for _, r := range rules {
+		if !r.tool.MatchString(input.ToolName) {
+			continue
+		}
+		value, ok := fields[r.Field].(string)
+		if !ok {
+			continue
+		}
+		rewritten := r.apply(value, baseDir)
+		if rewritten == value {
+			continue
+		}
+		if r.Once {
+			// Without a project to remember the first attempt in, a
+			// once-only rule would apply to every attempt.
+			if rw.history == nil {
+				rw.history = loadRewriteHistory(resolveProjectRoot(input), input.SessionID)
+			}
+			key := rewriteHistoryKey(r.ID, value)
+			if rw.history == nil || slices.Contains(rw.history.Applied, key) {
+				continue
+			}
+			rw.once = append(rw.once, key)
+		}
+		fields[r.Field] = rewritten
+		rw.rules = append(rw.rules, r.ID)
+		if r.Reason != "" {
+			rw.reasons = append(rw.reasons, r.Reason)
+		}
+	}
+	if len(rw.rules) == 0 {
+		return nil
+	}
+
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	rw.input = out
+	return rw
+}
+
+// rewriteHistory records the once-only rewrites applied in a session.
+type rewriteHistory struct {
+	path      string
+	SessionID string   `json:"session_id"`
+	Applied   []string `json:"applied"`
+}
+
+// loadRewriteHistory returns the rewrite history of a session, or nil
+// outside a MoAI project. A history of another session is discarded.
+func loadRewriteHistory(projectRoot, sessionID string) *rewriteHistory {
+	if projectRoot == "" {
+		return nil
+	}
+	h := &rewriteHistory{path: filepath.Join(projectRoot, ".moai", "cache", "tool-rewrites.json")}
+	if data, err := os.ReadFile(h.path); err == nil {
+		_ = json.Unmarshal(data, h)
+	}
+	if h.SessionID != sessionID {
+		h.SessionID, h.Applied = sessionID, nil
+	}
+	return h
+}
+
+// remember saves the once-only rewrites so the next attempt runs as given.
+// Failures are logged; the rewrite then repeats on the next attempt.
+func (rw *toolRewrite) remember() {
+	if len(rw.once) == 0 || rw.history == nil {
+		return
+	}
+	rw.history.Applied = append(rw.history.Applied, rw.once...)
+	data, err := json.Marshal(rw.history)
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(rw.history.path), 0o755); err == nil {
+			err = os.WriteFile(rw.history.path, data, 0o644)
+		}
+	}
+	if err != nil {
+		slog.Warn("failed to save rewrite history", "error", err.Error())
+	}
+}
+
+func rewriteHistoryKey(ruleID, value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return ruleID + ":" + hex.EncodeToString(sum[:8])
+}
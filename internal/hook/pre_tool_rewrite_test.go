@@ -0,0 +1,257 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/hook/audit"
+)
+
+// bashInput returns the tool input of a Bash command.
+func bashInput(t *testing.T, command string) json.RawMessage {
+	t.Helper()
+	data, err := json.Marshal(map[string]string{"command": command})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// updatedField returns a field of the rewritten tool input of output.
+func updatedField(t *testing.T, output *HookOutput, field string) string {
+	t.Helper()
+	var fields map[string]any
+	if err := json.Unmarshal(output.HookSpecificOutput.UpdatedInput, &fields); err != nil {
+		t.Fatalf("updatedInput %s: %v", output.HookSpecificOutput.UpdatedInput, err)
+	}
+	value, _ := fields[field].(string)
+	return value
+}
+
+func TestPreToolHandler_RewriteBash(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		command      string
+		wantDecision string
+		wantCommand  string // "" when the input is not rewritten
+		wantRules    []string
+	}{
+		{
+			name:         "sudo is stripped",
+			command:      "sudo make install",
+			wantDecision: DecisionAllow,
+			wantCommand:  "make install",
+			wantRules:    []string{"strip-sudo"},
+		},
+		{
+			name:         "sudo with options is kept",
+			command:      "sudo -u postgres psql",
+			wantDecision: DecisionAllow,
+		},
+		{
+			name:         "force push becomes force-with-lease",
+			command:      "git push --force origin feature/login",
+			wantDecision: DecisionAllow,
+			wantCommand:  "git push --force-with-lease origin feature/login",
+			wantRules:    []string{"git-force-with-lease"},
+		},
+		{
+			name:         "rewrites compose",
+			command:      "sudo git push -f",
+			wantDecision: DecisionAllow,
+			wantCommand:  "git push --force-with-lease",
+			wantRules:    []string{"strip-sudo", "git-force-with-lease"},
+		},
+		{
+			name:         "force push to main stays denied",
+			command:      "git push --force origin main",
+			wantDecision: DecisionDeny,
+		},
+		{
+			name:         "rewrite never lifts a deny",
+			command:      "sudo rm -rf /",
+			wantDecision: DecisionDeny,
+		},
+		{
+			name:         "once-only rule needs a project",
+			command:      "git clean -fd",
+			wantDecision: DecisionAsk,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := &preToolHandler{cfg: &mockConfigProvider{cfg: newTestConfig()}, policy: DefaultSecurityPolicy(), projectDir: t.TempDir()}
+			input := &HookInput{SessionID: "sess-rw", CWD: t.TempDir(), ToolName: "Bash", ToolInput: bashInput(t, tt.command)}
+			got, err := handler.Handle(context.Background(), input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := got.HookSpecificOutput.PermissionDecision; d != tt.wantDecision {
+				t.Errorf("PermissionDecision = %q, want %q", d, tt.wantDecision)
+			}
+			if tt.wantCommand == "" {
+				if len(got.HookSpecificOutput.UpdatedInput) > 0 {
+					t.Errorf("unexpected updatedInput %s", got.HookSpecificOutput.UpdatedInput)
+				}
+				return
+			}
+			if cmd := updatedField(t, got, "command"); cmd != tt.wantCommand {
+				t.Errorf("rewritten command = %q, want %q", cmd, tt.wantCommand)
+			}
+			if !slices.Equal(got.Rewrites, tt.wantRules) || got.HookSpecificOutput.PermissionDecisionReason == "" {
+				t.Errorf("Rewrites = %v, reason %q", got.Rewrites, got.HookSpecificOutput.PermissionDecisionReason)
+			}
+		})
+	}
+}
+
+func TestPreToolHandler_RewriteOnce(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	root := newMoAIProject(t)
+	handler := &preToolHandler{cfg: &mockConfigProvider{cfg: newTestConfig()}, policy: DefaultSecurityPolicy(), projectDir: root}
+
+	run := func(session string) *HookOutput {
+		t.Helper()
+		out, err := handler.Handle(context.Background(), &HookInput{SessionID: session, CWD: root, ToolName: "Bash", ToolInput: bashInput(t, "git clean -fd")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	first := run("sess-1")
+	if first.HookSpecificOutput.PermissionDecision != DecisionAllow || updatedField(t, first, "command") != "git clean --dry-run -fd" {
+		t.Fatalf("first attempt = %+v", first.HookSpecificOutput)
+	}
+	second := run("sess-1")
+	if second.HookSpecificOutput.PermissionDecision != DecisionAsk || len(second.HookSpecificOutput.UpdatedInput) > 0 {
+		t.Errorf("second attempt = %+v, want ask without rewrite", second.HookSpecificOutput)
+	}
+	if other := run("sess-2"); len(other.HookSpecificOutput.UpdatedInput) == 0 {
+		t.Error("a new session should dry-run again")
+	}
+}
+
+func TestPreToolHandler_RewriteOnceReplay(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	root := newMoAIProject(t)
+	handler := &preToolHandler{cfg: &mockConfigProvider{cfg: newTestConfig()}, policy: DefaultSecurityPolicy(), projectDir: root}
+	ctx := context.WithValue(context.Background(), replayKey{}, true)
+
+	for range 2 {
+		out, err := handler.Handle(ctx, &HookInput{SessionID: "sess-1", CWD: root, ToolName: "Bash", ToolInput: bashInput(t, "git clean -fd")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updatedField(t, out, "command") != "git clean --dry-run -fd" {
+			t.Fatalf("replay = %+v, want the dry-run rewrite every time", out.HookSpecificOutput)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, ".moai", "cache", "tool-rewrites.json")); !os.IsNotExist(err) {
+		t.Errorf("replay saved the rewrite history: %v", err)
+	}
+}
+
+func TestPreToolHandler_RewriteProjectPath(t *testing.T) {
+	// Claude Code runs hooks in the session directory, against which the
+	// original relative path is checked.
+	root, _ := filepath.EvalSymlinks(t.TempDir())
+	t.Chdir(root)
+	handler := &preToolHandler{cfg: &mockConfigProvider{cfg: newTestConfig()}, policy: DefaultSecurityPolicy(), projectDir: root}
+	toolInput, _ := json.Marshal(map[string]string{"file_path": "internal/app/main.go", "content": "package app"})
+	got, err := handler.Handle(context.Background(), &HookInput{CWD: root, ToolName: "Write", ToolInput: toolInput})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path := updatedField(t, got, "file_path"); path != filepath.Join(root, "internal", "app", "main.go") {
+		t.Errorf("file_path = %q", path)
+	}
+	if updatedField(t, got, "content") != "package app" {
+		t.Error("other fields must be kept")
+	}
+}
+
+func TestCompileRewriteRules(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.ToolRewriteConfig{
+		Enabled: true,
+		Disable: []string{"strip-sudo"},
+		Rules: []config.RewriteRule{
+			{ID: "plan-first", Tool: "Bash", Field: "command", Match: `^terraform apply\b`, Replace: "terraform plan"},
+			{ID: "broken", Tool: "Bash", Field: "command", Match: `(`},
+			{ID: "no-match", Tool: "Bash", Field: "command"},
+		},
+	}
+	var ids []string
+	for _, r := range compileRewriteRules(cfg) {
+		ids = append(ids, r.ID)
+	}
+	want := []string{"git-force-with-lease", "git-clean-dry-run", "kubectl-dry-run", "project-absolute-path", "plan-first"}
+	if !slices.Equal(ids, want) {
+		t.Errorf("rules = %v, want %v", ids, want)
+	}
+	if rules := compileRewriteRules(config.ToolRewriteConfig{}); rules != nil {
+		t.Errorf("disabled config compiled %d rules", len(rules))
+	}
+}
+
+func TestRegistryDispatchRewrite(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	root := newMoAIProject(t)
+
+	reg := NewRegistry(&mockConfigProvider{cfg: newTestConfig()})
+	reg.Register(NewPreToolHandler(&mockConfigProvider{cfg: newTestConfig()}, DefaultSecurityPolicy()))
+	seen := &inputRecorder{}
+	reg.Register(seen)
+
+	out, err := reg.Dispatch(context.Background(), EventPreToolUse, &HookInput{SessionID: "s1", CWD: root, ToolName: "Bash", ToolInput: bashInput(t, "sudo go test ./...")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updatedField(t, out, "command") != "go test ./..." || out.HookSpecificOutput.PermissionDecision != DecisionAllow {
+		t.Errorf("dispatch output = %+v", out.HookSpecificOutput)
+	}
+	if string(seen.input) != string(out.HookSpecificOutput.UpdatedInput) {
+		t.Errorf("later handler saw %s, want the rewritten input", seen.input)
+	}
+
+	// An ask is no longer lost behind the default allow.
+	out, err = reg.Dispatch(context.Background(), EventPreToolUse, &HookInput{SessionID: "s1", CWD: root, ToolName: "Bash", ToolInput: bashInput(t, "git reset --hard")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.HookSpecificOutput.PermissionDecision != DecisionAsk || out.RuleID == "" {
+		t.Errorf("ask output = %+v, rule %q", out.HookSpecificOutput, out.RuleID)
+	}
+
+	records, err := audit.Read(filepath.Join(root, ".moai", "logs", "hooks"), audit.Filter{Decision: audit.DecisionRewrite})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || !slices.Equal(records[0].Rewrites, []string{"strip-sudo"}) {
+		t.Errorf("rewrite audit records = %+v", records)
+	}
+}
+
+// inputRecorder is a PreToolUse handler remembering the tool input it saw.
+type inputRecorder struct {
+	input json.RawMessage
+}
+
+func (h *inputRecorder) EventType() EventType { return EventPreToolUse }
+
+func (h *inputRecorder) Handle(_ context.Context, input *HookInput) (*HookOutput, error) {
+	h.input = input.ToolInput
+	return NewAllowOutput(), nil
+}
@@ -12,14 +12,30 @@ import (
 	"time"
 
 	"github.com/modu-ai/moai-adk/internal/rank"
+	"github.com/modu-ai/moai-adk/internal/resilience"
 )
 
+// sessionEndRetryPolicy keeps SessionEnd quick: one short retry, leaving
+// anything undelivered in the outbox for the next session end or rank sync.
+var sessionEndRetryPolicy = resilience.RetryPolicy{
+	MaxRetries: 1,
+	BaseDelay:  250 * time.Millisecond,
+	MaxDelay:   time.Second,
+	UseJitter:  true,
+}
+
 // rankSessionHandler processes SessionEnd events and submits metrics to MoAI Rank API.
-// It checks exclusion patterns and submits session data non-blocking.
+// It checks exclusion patterns, queues the session in the durable outbox and
+// then drains the outbox, so sessions ended offline are delivered later.
 // Errors are logged but don't break the hook chain (per REQ-HOOK-034).
 type rankSessionHandler struct {
 	patternStore *rank.PatternStore
 	credStore    rank.CredentialStore
+
+	// outboxDir overrides the outbox location (default ~/.moai/rank/outbox).
+	outboxDir string
+	// newClient overrides how the API client is built (default rank.NewClient).
+	newClient func(apiKey string) rank.Client
 }
 
 // NewRankSessionHandler creates a new rank session handler.
@@ -69,19 +85,20 @@ func (h *rankSessionHandler) Handle(ctx context.Context, input *HookInput) (*Hoo
 		return &HookOutput{}, nil
 	}
 
-	// Create rank client and submit session
-	client := rank.NewClient(apiKey)
-	if err := client.SubmitSession(ctx, submission); err != nil {
-		slog.Warn("rank: failed to submit session", "error", err)
+	// Queue the session before touching the network so an outage or an
+	// offline machine does not lose it.
+	outbox, err := rank.NewOutbox(h.outboxDir, rank.OutboxConfig{Retry: sessionEndRetryPolicy})
+	if err != nil {
+		slog.Warn("rank: failed to open outbox", "error", err)
+		return &HookOutput{}, nil
+	}
+	if _, err := outbox.Enqueue(submission); err != nil {
+		slog.Warn("rank: failed to queue session", "error", err)
 		return &HookOutput{}, nil
 	}
 
-	slog.Info("rank: session submitted successfully",
-		"session_id", input.SessionID,
-		"project", anonymizePath(projectPath),
-	)
-
-	// Mark session in sync state to prevent re-submission during sync
+	// Mark session in sync state to prevent re-submission during sync;
+	// the outbox now owns its delivery.
 	transcriptPath := rank.FindTranscriptForSession(input.SessionID)
 	if transcriptPath != "" {
 		if syncState, syncErr := rank.NewSyncState(""); syncErr == nil {
@@ -90,6 +107,25 @@ func (h *rankSessionHandler) Handle(ctx context.Context, input *HookInput) (*Hoo
 		}
 	}
 
+	newClient := h.newClient
+	if newClient == nil {
+		newClient = func(apiKey string) rank.Client { return rank.NewClient(apiKey) }
+	}
+	drainCtx, cancel := context.WithTimeout(ctx, EnvRankTimeout())
+	defer cancel()
+	result, err := outbox.Drain(drainCtx, newClient(apiKey))
+	if err != nil {
+		slog.Warn("rank: failed to drain outbox", "error", err)
+		return &HookOutput{}, nil
+	}
+
+	slog.Info("rank: session queued",
+		"session_id", input.SessionID,
+		"project", anonymizePath(projectPath),
+		"sent", result.Sent,
+		"pending", result.Remaining,
+	)
+
 	// SessionEnd hooks return empty JSON {} per Claude Code protocol
 	return &HookOutput{}, nil
 }
@@ -110,7 +146,7 @@ func (h *rankSessionHandler) buildSessionSubmission(input *HookInput) (*rank.Ses
 	// Find transcript file for this session
 	transcriptPath := rank.FindTranscriptForSession(input.SessionID)
 	if transcriptPath != "" {
-		if usage, err := rank.ParseTranscript(transcriptPath); err == nil {
+		if usage, err := sessionTranscriptUsage(transcriptPath); err == nil {
 			inputTokens = usage.InputTokens
 			outputTokens = usage.OutputTokens
 			cacheCreation = usage.CacheCreationTokens
@@ -161,6 +197,20 @@ func (h *rankSessionHandler) buildSessionSubmission(input *HookInput) (*rank.Ses
 	return submission, nil
 }
 
+// sessionTranscriptUsage reads session totals through the transcript index,
+// so ending a long session only parses the part not yet indexed. It falls
+// back to a full parse when the index is unavailable.
+func sessionTranscriptUsage(transcriptPath string) (*rank.TranscriptUsage, error) {
+	if index, err := rank.OpenTranscriptIndex(""); err == nil {
+		if stats, err := index.Update([]string{transcriptPath}); err == nil && stats.Failed == 0 {
+			if usage, ok := index.Usage(transcriptPath); ok {
+				return usage, nil
+			}
+		}
+	}
+	return rank.ParseTranscript(transcriptPath)
+}
+
 // anonymizePath creates a one-way hash of the project path for privacy.
 // This ensures the actual project path is never transmitted to the rank service.
 func anonymizePath(path string) string {
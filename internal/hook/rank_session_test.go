@@ -2,8 +2,11 @@ package hook
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -286,6 +289,61 @@ func TestRankSessionHandler_Handle_NilPatternStore(t *testing.T) {
 	}
 }
 
+func TestRankSessionHandler_Handle_QueuesWhenOffline(t *testing.T) {
+	// Cannot use t.Parallel() with t.Setenv()
+	t.Setenv("HOME", t.TempDir())
+	outboxDir := t.TempDir()
+
+	var online atomic.Bool
+	var batches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !online.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		batches.Add(1)
+		_, _ = w.Write([]byte(`{"success":true,"data":{"success":true,"processed":1,"succeeded":1}}`))
+	}))
+	defer srv.Close()
+
+	h := &rankSessionHandler{
+		credStore: &mockCredStore{apiKey: "test-key", hasCredentials: true},
+		outboxDir: outboxDir,
+		newClient: func(apiKey string) rank.Client {
+			return rank.NewClient(apiKey, rank.WithBaseURL(srv.URL))
+		},
+	}
+	input := &HookInput{SessionID: "sess-offline", CWD: "/tmp", HookEventName: "SessionEnd", Model: "claude-opus-4"}
+
+	// Keep the retry backoff short: the hook gives up when ctx ends.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := h.Handle(ctx, input); err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+
+	outbox, err := rank.NewOutbox(outboxDir, rank.OutboxConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := outbox.Status(); st.Depth != 1 || st.LastError == "" {
+		t.Fatalf("outbox status while offline = %+v, want 1 queued with error", st)
+	}
+
+	// The next session end delivers the backlog as well as its own session.
+	online.Store(true)
+	input.SessionID = "sess-online"
+	if _, err := h.Handle(context.Background(), input); err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	if st, _ := outbox.Status(); st.Depth != 0 {
+		t.Errorf("outbox depth after reconnect = %d, want 0", st.Depth)
+	}
+	if batches.Load() == 0 {
+		t.Error("no batch reached the server")
+	}
+}
+
 // --- buildSessionSubmission tests ---
 
 func TestBuildSessionSubmission_BasicFields(t *testing.T) {
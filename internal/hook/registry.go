@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
+
+	"github.com/modu-ai/moai-adk/internal/hook/audit"
 )
 
 // @MX:ANCHOR: [AUTO] Hook Registry는 모든 Claude Code 이벤트 핸들러의 중앙 등록 및 디스패치 시스템입니다. 순차 실행, 타임아웃, block short-circuit을 지원합니다.
@@ -51,11 +54,21 @@ func (r *registry) Register(handler Handler) {
 // Handlers are executed sequentially within a timeout context. If any handler
 // returns Decision "block", remaining handlers are skipped and the block result
 // is returned immediately (REQ-HOOK-003). If all handlers succeed, Decision
-// "allow" is returned (REQ-HOOK-004).
+// "allow" is returned (REQ-HOOK-004), carrying the additionalContext of every
+// handler that supplied one.
 //
 // Note: Stop and SessionEnd events should NOT include hookSpecificOutput per
 // Claude Code protocol. These events return empty JSON {} instead.
 func (r *registry) Dispatch(ctx context.Context, event EventType, input *HookInput) (*HookOutput, error) {
+	output, err := r.dispatch(ctx, event, input)
+	if err == nil && !isReplay(ctx) {
+		r.recordFixture(event, input, output)
+	}
+	return output, err
+}
+
+// dispatch runs the handlers of an event; see Dispatch.
+func (r *registry) dispatch(ctx context.Context, event EventType, input *HookInput) (*HookOutput, error) {
 	handlers := r.handlers[event]
 	if len(handlers) == 0 {
 		slog.Debug("no handlers registered for event", "event", string(event))
@@ -66,6 +79,17 @@ func (r *registry) Dispatch(ctx context.Context, event EventType, input *HookInp
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
+	// Every handler invocation is recorded in the audit log, however the
+	// dispatch ends.
+	var records []audit.Record
+	defer func() { r.writeAudit(ctx, input, records) }()
+
+	var contexts []string
+	// asked is the first output asking for confirmation; allowed the first
+	// one allowing by a rule; rewritten the last one rewriting the tool
+	// input, which later handlers then see.
+	var asked, allowed, rewritten *HookOutput
+	var rewrites []string
 	for i, h := range handlers {
 		slog.Debug("dispatching handler",
 			"event", string(event),
@@ -73,7 +97,9 @@ func (r *registry) Dispatch(ctx context.Context, event EventType, input *HookInp
 			"handler_total", len(handlers),
 		)
 
+		start := time.Now()
 		output, err := h.Handle(ctx, input)
+		records = append(records, auditRecord(event, h, input, output, err, ctx.Err(), time.Since(start)))
 
 		// Check for context deadline exceeded (timeout)
 		if ctx.Err() != nil {
@@ -117,9 +143,67 @@ func (r *registry) Dispatch(ctx context.Context, event EventType, input *HookInp
 			)
 			return output, nil
 		}
+
+		if output == nil || output.HookSpecificOutput == nil {
+			continue
+		}
+		hso := output.HookSpecificOutput
+		if hso.AdditionalContext != "" {
+			contexts = append(contexts, hso.AdditionalContext)
+		}
+		if hso.PermissionDecision == DecisionAsk && asked == nil {
+			asked = output
+		}
+		if hso.PermissionDecision == DecisionAllow && output.RuleID != "" && allowed == nil {
+			allowed = output
+		}
+		if len(hso.UpdatedInput) > 0 {
+			rewritten = output
+			rewrites = append(rewrites, output.Rewrites...)
+			updated := *input
+			updated.ToolInput = hso.UpdatedInput
+			input = &updated
+		}
+	}
+
+	result := r.defaultOutputForEvent(event)
+	if len(contexts) > 0 {
+		if result.HookSpecificOutput == nil {
+			result.HookSpecificOutput = &HookSpecificOutput{HookEventName: string(event)}
+		}
+		result.HookSpecificOutput.AdditionalContext = strings.Join(contexts, "\n\n")
+	}
+	if asked != nil || allowed != nil || rewritten != nil {
+		mergePermission(result, asked, allowed, rewritten, rewrites)
 	}
+	return result, nil
+}
 
-	return r.defaultOutputForEvent(event), nil
+// mergePermission carries an ask decision, an allow decision of a rule and
+// the rewritten tool input of the handlers into the result of a dispatch.
+// An ask wins over an allow, which overrides the default ask of
+// PermissionRequest. An ask keeps its reason; otherwise the reason explains
+// the rewrite.
+func mergePermission(result, asked, allowed, rewritten *HookOutput, rewrites []string) {
+	if result.HookSpecificOutput == nil {
+		result.HookSpecificOutput = &HookSpecificOutput{HookEventName: "PreToolUse"}
+	}
+	hso := result.HookSpecificOutput
+	if allowed != nil {
+		hso.PermissionDecision = DecisionAllow
+		hso.PermissionDecisionReason = allowed.HookSpecificOutput.PermissionDecisionReason
+		result.RuleID = allowed.RuleID
+	}
+	if rewritten != nil {
+		hso.PermissionDecisionReason = rewritten.HookSpecificOutput.PermissionDecisionReason
+		hso.UpdatedInput = rewritten.HookSpecificOutput.UpdatedInput
+		result.Rewrites = rewrites
+	}
+	if asked != nil {
+		hso.PermissionDecision = DecisionAsk
+		hso.PermissionDecisionReason = asked.HookSpecificOutput.PermissionDecisionReason
+		result.RuleID = asked.RuleID
+	}
 }
 
 // isBlockDecision checks if the output represents a blocking decision.
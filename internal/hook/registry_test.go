@@ -317,6 +317,29 @@ func verifyDecision(t *testing.T, got *HookOutput, event EventType, wantDecision
 	}
 }
 
+func TestRegistryDispatchMergesAdditionalContext(t *testing.T) {
+	t.Parallel()
+
+	reg := NewRegistry(&mockConfigProvider{cfg: newTestConfig()})
+	withContext := func(text string) *mockHandler {
+		return &mockHandler{event: EventUserPromptSubmit, output: &HookOutput{
+			HookSpecificOutput: &HookSpecificOutput{HookEventName: "UserPromptSubmit", AdditionalContext: text},
+		}}
+	}
+	reg.Register(withContext("first"))
+	reg.Register(&mockHandler{event: EventUserPromptSubmit, output: &HookOutput{}})
+	reg.Register(withContext("second"))
+
+	out, err := reg.Dispatch(context.Background(), EventUserPromptSubmit, &HookInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.HookSpecificOutput == nil || out.HookSpecificOutput.HookEventName != "UserPromptSubmit" ||
+		out.HookSpecificOutput.AdditionalContext != "first\n\nsecond" {
+		t.Errorf("HookSpecificOutput = %+v", out.HookSpecificOutput)
+	}
+}
+
 func TestRegistryDispatchTimeout(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,338 @@
+// Package replay stores recorded hook inputs as fixtures and the decisions
+// expected for them.
+//
+// While a recording session is active (see Start), the hook registry saves
+// each dispatched input as one JSON fixture together with the decision it
+// produced. `moai hook replay` runs the fixtures through the current
+// handlers and reports every fixture whose decision changed, so policy
+// changes can be tested against real traffic.
+//
+// Fixtures are redacted before they are written: values of credential-like
+// fields are removed, known token formats are masked with placeholders of
+// the same shape, and the project root is replaced by RootPlaceholder so
+// fixtures stay valid on other machines.
+package replay
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultDir is the fixture directory, relative to the project root.
+const DefaultDir = ".moai/hooks/fixtures"
+
+// RootPlaceholder stands for the project root inside fixture inputs.
+const RootPlaceholder = "${CLAUDE_PROJECT_DIR}"
+
+// Redacted replaces the value of a credential-like field.
+const Redacted = "[REDACTED]"
+
+// Expectation is the outcome of dispatching a fixture. Replays compare the
+// decision and rule; the reason is kept for readers of the fixture.
+type Expectation struct {
+	Decision string `json:"decision"`
+	RuleID   string `json:"rule_id,omitempty"`
+	// Rewrites names the rules that rewrote the tool input.
+	Rewrites []string `json:"rewrites,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+// Matches reports whether got has the decision, rule and rewrites of e.
+func (e Expectation) Matches(got Expectation) bool {
+	return e.Decision == got.Decision && e.RuleID == got.RuleID && slices.Equal(e.Rewrites, got.Rewrites)
+}
+
+// String formats the decision and rules, such as "deny (bash-dangerous-0)".
+func (e Expectation) String() string {
+	rules := slices.DeleteFunc(append([]string{e.RuleID}, e.Rewrites...), func(s string) bool { return s == "" })
+	if len(rules) == 0 {
+		return e.Decision
+	}
+	return e.Decision + " (" + strings.Join(rules, ", ") + ")"
+}
+
+// Fixture is one recorded hook input.
+type Fixture struct {
+	Event      string    `json:"event"`
+	RecordedAt time.Time `json:"recorded_at"`
+	// Redacted reports that secrets were removed from Input, which can
+	// change the decision of checks that look at them.
+	Redacted bool            `json:"redacted,omitempty"`
+	Input    json.RawMessage `json:"input"`
+	Expect   Expectation     `json:"expect"`
+
+	// Path is the file the fixture was loaded from.
+	Path string `json:"-"`
+}
+
+// NewFixture redacts a hook input recorded in projectRoot.
+func NewFixture(event string, input []byte, projectRoot string, expect Expectation, at time.Time) (*Fixture, error) {
+	redacted, changed, err := Redact(input, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	return &Fixture{Event: event, RecordedAt: at, Redacted: changed, Input: redacted, Expect: expect}, nil
+}
+
+// FileName names a fixture after its event, tool and input, so recording
+// the same input twice keeps a single file.
+func (f *Fixture) FileName() string {
+	name := strings.ToLower(f.Event)
+	var probe struct {
+		ToolName string `json:"tool_name"`
+	}
+	if json.Unmarshal(f.Input, &probe) == nil && probe.ToolName != "" {
+		name += "-" + strings.ToLower(nonWord.ReplaceAllString(probe.ToolName, "_"))
+	}
+	sum := sha256.Sum256(f.Input)
+	return name + "-" + hex.EncodeToString(sum[:])[:12] + ".json"
+}
+
+var nonWord = regexp.MustCompile(`\W+`)
+
+// ExpandInput returns the fixture input with RootPlaceholder replaced by
+// projectRoot.
+func (f *Fixture) ExpandInput(projectRoot string) []byte {
+	root, _ := json.Marshal(projectRoot)
+	return []byte(strings.ReplaceAll(string(f.Input), RootPlaceholder, string(root[1:len(root)-1])))
+}
+
+// Save writes the fixture to dir and returns its path.
+func Save(dir string, f *Fixture) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create fixture directory: %w", err)
+	}
+	path := f.Path
+	if path == "" {
+		path = filepath.Join(dir, f.FileName())
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return "", fmt.Errorf("marshal fixture: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return "", fmt.Errorf("write fixture: %w", err)
+	}
+	return path, nil
+}
+
+// Load reads the fixtures of dir and its subdirectories in path order. A
+// missing directory holds no fixtures.
+func Load(dir string) ([]*Fixture, error) {
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	var fixtures []*Fixture
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read fixture: %w", err)
+		}
+		var f Fixture
+		if err := json.Unmarshal(data, &f); err != nil {
+			return fmt.Errorf("parse fixture %s: %w", path, err)
+		}
+		if f.Event == "" || len(f.Input) == 0 {
+			return fmt.Errorf("parse fixture %s: missing event or input", path)
+		}
+		f.Path = path
+		fixtures = append(fixtures, &f)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load fixtures: %w", err)
+	}
+	return fixtures, nil
+}
+
+// sensitiveKey matches field names whose string values are dropped.
+var sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|api[_-]?key|access[_-]?key|authorization|credential|private[_-]?key)`)
+
+// tokenPatterns match secrets inside strings. Group 1 is kept and group 2
+// is masked with 'X' of the same length, so content checks still fire.
+var tokenPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(-----BEGIN [A-Z ]*PRIVATE KEY-----)([\s\S]*?)-----END`),
+	regexp.MustCompile(`\b(gh[pousr]_|github_pat_)([A-Za-z0-9_]{20,})`),
+	regexp.MustCompile(`\b(glpat-)([A-Za-z0-9_\-]{20,})`),
+	regexp.MustCompile(`\b(sk-)([A-Za-z0-9_\-]{20,})`),
+	regexp.MustCompile(`\b(xox[baprs]-)([A-Za-z0-9\-]{10,})`),
+	regexp.MustCompile(`\b(AKIA)([0-9A-Z]{16})`),
+	regexp.MustCompile(`\b(ya29\.)([A-Za-z0-9_\-]{20,})`),
+	regexp.MustCompile(`(?i)(bearer\s+)([A-Za-z0-9._~+/\-]{16,}=*)`),
+	regexp.MustCompile(`(?i)((?:password|passwd|secret|token|api[_-]?key)\s*[=:]\s*["']?)([^\s"'&;|]{4,})`),
+}
+
+// Redact removes secrets and the project root from a JSON hook input. It
+// reports whether any secret was removed.
+func Redact(input []byte, projectRoot string) (json.RawMessage, bool, error) {
+	var v any
+	if err := json.Unmarshal(input, &v); err != nil {
+		return nil, false, fmt.Errorf("parse hook input: %w", err)
+	}
+	r := redactor{root: filepath.Clean(projectRoot)}
+	v = r.value("", v)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, false, fmt.Errorf("marshal hook input: %w", err)
+	}
+	return bytes.TrimSpace(buf.Bytes()), r.changed, nil
+}
+
+type redactor struct {
+	root    string
+	changed bool
+}
+
+func (r *redactor) value(key string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			v[k] = r.value(k, child)
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = r.value(key, child)
+		}
+		return v
+	case string:
+		if v != "" && sensitiveKey.MatchString(key) {
+			r.changed = true
+			return Redacted
+		}
+		return r.text(v)
+	}
+	return v
+}
+
+func (r *redactor) text(s string) string {
+	for _, re := range tokenPatterns {
+		s = maskGroup(re, s, &r.changed)
+	}
+	if r.root != "" && r.root != "." && r.root != string(filepath.Separator) {
+		s = strings.ReplaceAll(s, r.root, RootPlaceholder)
+	}
+	return s
+}
+
+// masked marks a value an earlier pattern already masked, whose prefix
+// must survive the generic assignment pattern.
+const masked = "XXXXXXXX"
+
+// maskGroup replaces group 2 of every match of re with 'X'.
+func maskGroup(re *regexp.Regexp, s string, changed *bool) string {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[4], m[5]
+		if start < 0 || strings.Contains(s[start:end], masked) {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(strings.Repeat("X", end-start))
+		last = end
+		*changed = true
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// SessionPath is the marker of an active recording session.
+func SessionPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".moai", "cache", "hook-record.json")
+}
+
+// Session is an active recording session.
+type Session struct {
+	Started time.Time `json:"started"`
+	Dir     string    `json:"dir"`
+	// Events lists the recorded events; empty records every event.
+	Events []string `json:"events,omitempty"`
+}
+
+// Records reports whether the session records event.
+func (s *Session) Records(event string) bool {
+	return len(s.Events) == 0 || slices.Contains(s.Events, event)
+}
+
+// FixtureDir returns the directory fixtures are written to.
+func (s *Session) FixtureDir(projectRoot string) string {
+	dir := s.Dir
+	if dir == "" {
+		dir = DefaultDir
+	}
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(projectRoot, dir)
+}
+
+// Start begins a recording session, replacing any active one.
+func Start(projectRoot string, s Session) error {
+	path := SessionPath(projectRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal recording session: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write recording session: %w", err)
+	}
+	return nil
+}
+
+// Active returns the active recording session, or nil when none is.
+func Active(projectRoot string) (*Session, error) {
+	data, err := os.ReadFile(SessionPath(projectRoot))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read recording session: %w", err)
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse recording session: %w", err)
+	}
+	return &s, nil
+}
+
+// Stop ends the active recording session and returns it, or nil when
+// none was active.
+func Stop(projectRoot string) (*Session, error) {
+	s, err := Active(projectRoot)
+	if err != nil || s == nil {
+		return s, err
+	}
+	if err := os.Remove(SessionPath(projectRoot)); err != nil {
+		return nil, fmt.Errorf("stop recording session: %w", err)
+	}
+	return s, nil
+}
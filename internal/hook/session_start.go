@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"log/slog"
+	"strings"
 
 	"github.com/modu-ai/moai-adk/internal/config"
 )
@@ -27,6 +28,7 @@ func (h *sessionStartHandler) EventType() EventType {
 
 // Handle processes a SessionStart event. It logs the session ID, loads
 // project configuration, and returns project information in the Data field.
+// In a MoAI project the same facts are given to Claude as additionalContext.
 // Errors are non-blocking: the handler logs warnings and returns allow.
 func (h *sessionStartHandler) Handle(ctx context.Context, input *HookInput) (*HookOutput, error) {
 	slog.Info("session started",
@@ -66,7 +68,45 @@ func (h *sessionStartHandler) Handle(ctx context.Context, input *HookInput) (*Ho
 		return &HookOutput{}, nil
 	}
 
-	return &HookOutput{Data: jsonData}, nil
+	output := &HookOutput{Data: jsonData}
+	if facts := h.sessionContext(cfg, input); facts != "" {
+		output.HookSpecificOutput = &HookSpecificOutput{
+			HookEventName:     string(EventSessionStart),
+			AdditionalContext: facts,
+		}
+	}
+	return output, nil
+}
+
+// sessionContext renders the project facts for Claude within the context
+// injection budget. After compaction or resume the session snapshot saved
+// by PreCompact follows the facts. Outside a MoAI project the defaults
+// would be misleading, so nothing is rendered.
+func (h *sessionStartHandler) sessionContext(cfg *config.Config, input *HookInput) string {
+	settings := injectionSettings(h.cfg)
+	root := resolveProjectRoot(input)
+	if cfg == nil || !settings.Enabled || root == "" {
+		return ""
+	}
+
+	var parts []string
+	if facts := renderSessionFacts(cfg, root); facts != "" {
+		parts = append(parts, facts)
+	}
+	if input.Source == "compact" || input.Source == "resume" {
+		snap, err := loadSnapshot(root, input.SessionID)
+		if err != nil {
+			slog.Warn("failed to load session snapshot",
+				"session_id", input.SessionID,
+				"error", err.Error(),
+			)
+		} else if snap != nil {
+			if summary := renderSnapshot(snap); summary != "" {
+				parts = append(parts, summary)
+			}
+		}
+	}
+	return fitTokenBudget(strings.Join(parts, "\n\n"), settings.MaxTokens)
 }
 
 // getConfig safely retrieves the configuration, returning nil if unavailable.
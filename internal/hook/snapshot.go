@@ -0,0 +1,379 @@
+package hook
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/defs"
+	"github.com/modu-ai/moai-adk/internal/loop"
+	"github.com/modu-ai/moai-adk/internal/workflow"
+)
+
+// Session snapshots carry the working state of a session across context
+// compaction. PreCompact writes one to .moai/memory/snapshots/<session>.json
+// and SessionStart with source "compact" or "resume" hands its summary back
+// to Claude.
+
+// maxSnapshotFiles bounds the touched files kept in a snapshot.
+const maxSnapshotFiles = 50
+
+// sessionSnapshot is the on-disk layout of a session snapshot.
+type sessionSnapshot struct {
+	SessionID          string              `json:"sessionId"`
+	CreatedAt          time.Time           `json:"createdAt"`
+	Trigger            string              `json:"trigger,omitempty"`
+	CustomInstructions string              `json:"customInstructions,omitempty"`
+	ActiveSpec         *snapshotSpec       `json:"activeSpec,omitempty"`
+	OpenTasks          []snapshotTask      `json:"openTasks,omitempty"`
+	FilesTouched       []string            `json:"filesTouched,omitempty"`
+	LastFeedback       *loop.Feedback      `json:"lastFeedback,omitempty"`
+	Diagnostics        map[string][]string `json:"unresolvedDiagnostics,omitempty"`
+}
+
+// snapshotSpec is the SPEC being worked on. Phase is a feedback loop phase
+// (analyze, implement, test, review) or a workflow phase (plan, run, sync).
+type snapshotSpec struct {
+	ID        string `json:"id"`
+	Phase     string `json:"phase"`
+	Iteration int    `json:"iteration,omitempty"`
+	MaxIter   int    `json:"maxIterations,omitempty"`
+}
+
+// snapshotTask is an unfinished entry of the session todo list.
+type snapshotTask struct {
+	Content string `json:"content"`
+	Status  string `json:"status"`
+}
+
+// snapshotDir returns the directory holding the snapshots of a project.
+func snapshotDir(projectRoot string) string {
+	return filepath.Join(projectRoot, defs.MoAIDir, defs.MemorySubdir, "snapshots")
+}
+
+// snapshotPath returns the snapshot file of a session. Path separators in
+// the session ID are replaced so the file stays inside the snapshot
+// directory.
+func snapshotPath(projectRoot, sessionID string) string {
+	return filepath.Join(snapshotDir(projectRoot), safeFileName(sessionID)+".json")
+}
+
+// safeFileName turns an identifier into a single path element.
+func safeFileName(id string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(id)
+	if name == "" {
+		name = "unknown"
+	}
+	return name
+}
+
+// captureSnapshot collects the state of the session described by input.
+// Every source is optional: what cannot be read is left out.
+func captureSnapshot(projectRoot string, input *HookInput) *sessionSnapshot {
+	snap := &sessionSnapshot{
+		SessionID:          input.SessionID,
+		CreatedAt:          time.Now(),
+		Trigger:            input.Trigger,
+		CustomInstructions: strings.TrimSpace(input.CustomInstructions),
+		Diagnostics:        newDiagnosticsMemo(projectRoot, input.SessionID).open(),
+	}
+
+	if state := latestLoopState(projectRoot); state != nil {
+		snap.ActiveSpec = &snapshotSpec{
+			ID:        state.SpecID,
+			Phase:     string(state.Phase),
+			Iteration: state.Iteration,
+			MaxIter:   state.MaxIter,
+		}
+		if n := len(state.Feedback); n > 0 {
+			fb := state.Feedback[n-1]
+			snap.LastFeedback = &fb
+		}
+	} else {
+		snap.ActiveSpec = activeWorkflowSpec(projectRoot)
+	}
+
+	if input.TranscriptPath != "" {
+		tasks, files, err := scanTranscript(input.TranscriptPath)
+		if err != nil {
+			slog.Debug("failed to scan transcript for snapshot", "path", input.TranscriptPath, "error", err)
+		}
+		snap.OpenTasks = tasks
+		for _, f := range files {
+			if rel, err := filepath.Rel(projectRoot, f); err == nil && !strings.HasPrefix(rel, "..") {
+				f = filepath.ToSlash(rel)
+			}
+			snap.FilesTouched = append(snap.FilesTouched, f)
+		}
+	}
+	return snap
+}
+
+// latestLoopState returns the most recently updated feedback loop state in
+// .moai/loop, or nil when there is none.
+func latestLoopState(projectRoot string) *loop.LoopState {
+	dir := filepath.Join(projectRoot, defs.MoAIDir, "loop")
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	storage := loop.NewFileStorage(dir)
+
+	var latest *loop.LoopState
+	for _, p := range paths {
+		state, err := storage.LoadState(strings.TrimSuffix(filepath.Base(p), ".json"))
+		if err != nil {
+			continue
+		}
+		if latest == nil || state.UpdatedAt.After(latest.UpdatedAt) {
+			latest = state
+		}
+	}
+	return latest
+}
+
+// activeWorkflowSpec returns the SPEC of the most recent unfinished headless
+// workflow run with its first unfinished phase, or nil.
+func activeWorkflowSpec(projectRoot string) *snapshotSpec {
+	dirs, _ := filepath.Glob(workflow.RunStateDir(projectRoot, "*"))
+
+	var latest *workflow.RunState
+	for _, d := range dirs {
+		state, err := workflow.LoadRunState(projectRoot, filepath.Base(d))
+		if err != nil || state.Completed() {
+			continue
+		}
+		if latest == nil || state.UpdatedAt.After(latest.UpdatedAt) {
+			latest = state
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+	for _, p := range workflow.Phases {
+		if latest.Phase(p).Status != workflow.PhaseStatusCompleted {
+			return &snapshotSpec{ID: latest.SpecID, Phase: string(p)}
+		}
+	}
+	return nil
+}
+
+// transcriptToolLine is the part of a transcript line that carries tool
+// calls.
+type transcriptToolLine struct {
+	IsSidechain bool `json:"isSidechain"`
+	Message     struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// transcriptToolUse is a tool_use block of an assistant message.
+type transcriptToolUse struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Input struct {
+		FilePath     string         `json:"file_path"`
+		NotebookPath string         `json:"notebook_path"`
+		Todos        []snapshotTask `json:"todos"`
+	} `json:"input"`
+}
+
+// scanTranscript reads a session transcript and returns the unfinished
+// entries of the last TodoWrite list and the files written or edited,
+// most recently touched last.
+func scanTranscript(path string) ([]snapshotTask, []string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open transcript: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var todos []snapshotTask
+	var files []string
+	r := bufio.NewReader(f)
+	for {
+		line, readErr := r.ReadBytes('\n')
+		if bytes.Contains(line, []byte(`"tool_use"`)) {
+			var tl transcriptToolLine
+			var blocks []transcriptToolUse
+			if json.Unmarshal(line, &tl) == nil && !tl.IsSidechain && json.Unmarshal(tl.Message.Content, &blocks) == nil {
+				for _, b := range blocks {
+					if b.Type != "tool_use" {
+						continue
+					}
+					switch b.Name {
+					case "TodoWrite":
+						todos = b.Input.Todos
+					case "Write", "Edit", "MultiEdit", "NotebookEdit":
+						file := cmp.Or(b.Input.FilePath, b.Input.NotebookPath)
+						if file == "" {
+							continue
+						}
+						files = slices.DeleteFunc(files, func(f string) bool { return f == file })
+						files = append(files, file)
+					}
+				}
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, nil, fmt.Errorf("read transcript: %w", readErr)
+		}
+	}
+
+	var open []snapshotTask
+	for _, t := range todos {
+		if t.Status != "completed" {
+			open = append(open, t)
+		}
+	}
+	if len(files) > maxSnapshotFiles {
+		files = files[len(files)-maxSnapshotFiles:]
+	}
+	return open, files, nil
+}
+
+// saveSnapshot writes snap into the snapshot directory of projectRoot and
+// returns its path.
+func saveSnapshot(projectRoot string, snap *sessionSnapshot) (string, error) {
+	path := snapshotPath(projectRoot, snap.SessionID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("create snapshot directory: %w", err)
+	}
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal snapshot: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", fmt.Errorf("write snapshot: %w", err)
+	}
+	return path, nil
+}
+
+// loadSnapshot reads the snapshot of a session. It returns nil without an
+// error when the session has none.
+func loadSnapshot(projectRoot, sessionID string) (*sessionSnapshot, error) {
+	data, err := os.ReadFile(snapshotPath(projectRoot, sessionID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+	var snap sessionSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("parse snapshot: %w", err)
+	}
+	return &snap, nil
+}
+
+// pruneSnapshots removes snapshots older than the retention age and then
+// the oldest ones beyond the retention count. keep is never removed. It
+// returns the number of snapshots removed.
+func pruneSnapshots(projectRoot string, retention config.SnapshotRetention, keep string, now time.Time) int {
+	paths, _ := filepath.Glob(filepath.Join(snapshotDir(projectRoot), "*.json"))
+
+	type entry struct {
+		path    string
+		modTime time.Time
+	}
+	var entries []entry
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil {
+			entries = append(entries, entry{p, info.ModTime()})
+		}
+	}
+	slices.SortFunc(entries, func(a, b entry) int { return b.modTime.Compare(a.modTime) })
+
+	removed := 0
+	kept := 0
+	for _, e := range entries {
+		expired := retention.MaxAgeDays > 0 && now.Sub(e.modTime) > time.Duration(retention.MaxAgeDays)*24*time.Hour
+		surplus := retention.MaxCount > 0 && kept >= retention.MaxCount
+		if e.path == keep || (!expired && !surplus) {
+			kept++
+			continue
+		}
+		if err := os.Remove(e.path); err != nil {
+			slog.Debug("failed to prune snapshot", "path", e.path, "error", err)
+			continue
+		}
+		removed++
+	}
+	return removed
+}
+
+// renderSnapshot summarizes a snapshot for the session continuing after
+// compaction or resume. It returns "" when the snapshot holds nothing.
+func renderSnapshot(snap *sessionSnapshot) string {
+	var lines []string
+
+	if s := snap.ActiveSpec; s != nil {
+		line := fmt.Sprintf("- Active SPEC: %s (phase: %s", s.ID, s.Phase)
+		if s.Iteration > 0 {
+			line += fmt.Sprintf(", iteration %d", s.Iteration)
+			if s.MaxIter > 0 {
+				line += fmt.Sprintf("/%d", s.MaxIter)
+			}
+		}
+		lines = append(lines, line+")")
+	}
+	if fb := snap.LastFeedback; fb != nil {
+		build := "ok"
+		if !fb.BuildSuccess {
+			build = "failing"
+		}
+		line := fmt.Sprintf("- Last loop feedback (%s #%d): build %s, tests %d passed / %d failed, %d lint error(s), coverage %.1f%%",
+			fb.Phase, fb.Iteration, build, fb.TestsPassed, fb.TestsFailed, fb.LintErrors, fb.Coverage)
+		if notes := strings.TrimSpace(fb.Notes); notes != "" {
+			line += ". " + notes
+		}
+		lines = append(lines, line)
+	}
+	if len(snap.OpenTasks) > 0 {
+		lines = append(lines, "- Open tasks:")
+		for _, t := range snap.OpenTasks {
+			lines = append(lines, fmt.Sprintf("  - [%s] %s", t.Status, t.Content))
+		}
+	}
+	if len(snap.Diagnostics) > 0 {
+		lines = append(lines, "- Unresolved diagnostics:")
+		for _, file := range slices.Sorted(maps.Keys(snap.Diagnostics)) {
+			for _, d := range snap.Diagnostics[file] {
+				lines = append(lines, fmt.Sprintf("  - %s %s", file, d))
+			}
+		}
+	}
+	if len(snap.FilesTouched) > 0 {
+		// Most recent first, so trimming drops the oldest.
+		files := slices.Clone(snap.FilesTouched)
+		slices.Reverse(files)
+		lines = append(lines, "- Files touched: "+strings.Join(files, ", "))
+	}
+	if snap.CustomInstructions != "" {
+		lines = append(lines, "- Compaction instructions: "+snap.CustomInstructions)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	header := "Session state saved before compaction"
+	if !snap.CreatedAt.IsZero() {
+		header += " at " + snap.CreatedAt.Local().Format("2006-01-02 15:04")
+	}
+	return header + ":\n" + strings.Join(lines, "\n")
+}
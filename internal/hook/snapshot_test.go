@@ -0,0 +1,218 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/loop"
+)
+
+// writeTranscript writes a transcript with the given assistant tool calls,
+// one line per call.
+func writeTranscript(t *testing.T, dir string, calls ...map[string]any) string {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString(`{"type":"user","message":{"content":"start"}}` + "\n")
+	for _, call := range calls {
+		line, err := json.Marshal(map[string]any{
+			"type": "assistant",
+			"message": map[string]any{
+				"content": []map[string]any{{"type": "tool_use", "name": call["name"], "input": call["input"]}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Write(line)
+		b.WriteString("\n")
+	}
+	path := filepath.Join(dir, "transcript.jsonl")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// setupSnapshotProject creates a MoAI project with a feedback loop, an
+// unresolved diagnostic and a transcript for session "sess-1".
+func setupSnapshotProject(t *testing.T) (root, transcript string) {
+	t.Helper()
+	root = newMoAIProject(t)
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+
+	storage := loop.NewFileStorage(filepath.Join(root, ".moai", "loop"))
+	for _, state := range []*loop.LoopState{
+		{SpecID: "SPEC-OLD-001", Phase: loop.PhaseReview, UpdatedAt: time.Now().Add(-time.Hour)},
+		{
+			SpecID: "SPEC-AUTH-001", Phase: loop.PhaseTest, Iteration: 3, MaxIter: 10, UpdatedAt: time.Now(),
+			Feedback: []loop.Feedback{
+				{Phase: loop.PhaseImplement, Iteration: 2, BuildSuccess: false},
+				{Phase: loop.PhaseTest, Iteration: 3, BuildSuccess: true, TestsPassed: 12, TestsFailed: 2, Coverage: 78.5, Notes: "token refresh fails"},
+			},
+		},
+	} {
+		if err := storage.SaveState(state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newDiagnosticsMemo(root, "sess-1").swap("internal/auth/login.go", "d1", []string{"L12:5 error: undefined: foo [gopls]"})
+
+	transcript = writeTranscript(t, t.TempDir(),
+		map[string]any{"name": "Edit", "input": map[string]any{"file_path": filepath.Join(root, "internal/auth/login.go")}},
+		map[string]any{"name": "TodoWrite", "input": map[string]any{"todos": []map[string]any{
+			{"content": "Write login handler", "status": "completed"},
+			{"content": "Fix token refresh", "status": "pending"},
+		}}},
+		map[string]any{"name": "Write", "input": map[string]any{"file_path": filepath.Join(root, "internal/auth/token.go")}},
+		map[string]any{"name": "TodoWrite", "input": map[string]any{"todos": []map[string]any{
+			{"content": "Write login handler", "status": "completed"},
+			{"content": "Fix token refresh", "status": "in_progress"},
+			{"content": "Update README", "status": "pending"},
+		}}},
+		map[string]any{"name": "Edit", "input": map[string]any{"file_path": filepath.Join(root, "internal/auth/login.go")}},
+	)
+	return root, transcript
+}
+
+func TestCompactHandler_WritesSnapshot(t *testing.T) {
+	root, transcript := setupSnapshotProject(t)
+
+	h := NewCompactHandlerWithConfig(&mockConfigProvider{cfg: newTestConfig()})
+	out, err := h.Handle(context.Background(), &HookInput{
+		SessionID:          "sess-1",
+		CWD:                root,
+		TranscriptPath:     transcript,
+		HookEventName:      "PreCompact",
+		Trigger:            "manual",
+		CustomInstructions: "keep the auth decisions",
+	})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	var data map[string]any
+	if err := json.Unmarshal(out.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data["snapshot_created"] != true {
+		t.Fatalf("snapshot_created = %v, want true", data["snapshot_created"])
+	}
+
+	snap, err := loadSnapshot(root, "sess-1")
+	if err != nil || snap == nil {
+		t.Fatalf("loadSnapshot() = %v, %v", snap, err)
+	}
+	if data["snapshot_path"] != filepath.Join(root, ".moai", "memory", "snapshots", "sess-1.json") {
+		t.Errorf("snapshot_path = %v", data["snapshot_path"])
+	}
+	if snap.ActiveSpec == nil || snap.ActiveSpec.ID != "SPEC-AUTH-001" || snap.ActiveSpec.Phase != "test" {
+		t.Errorf("ActiveSpec = %+v, want SPEC-AUTH-001 in test", snap.ActiveSpec)
+	}
+	if snap.LastFeedback == nil || snap.LastFeedback.TestsFailed != 2 {
+		t.Errorf("LastFeedback = %+v", snap.LastFeedback)
+	}
+	if len(snap.OpenTasks) != 2 || snap.OpenTasks[0].Content != "Fix token refresh" || snap.OpenTasks[0].Status != "in_progress" {
+		t.Errorf("OpenTasks = %+v, want the unfinished entries of the last list", snap.OpenTasks)
+	}
+	if got := strings.Join(snap.FilesTouched, ","); got != "internal/auth/token.go,internal/auth/login.go" {
+		t.Errorf("FilesTouched = %q", got)
+	}
+	if got := snap.Diagnostics["internal/auth/login.go"]; len(got) != 1 {
+		t.Errorf("Diagnostics = %v", snap.Diagnostics)
+	}
+	if snap.Trigger != "manual" || snap.CustomInstructions != "keep the auth decisions" {
+		t.Errorf("Trigger/CustomInstructions = %q/%q", snap.Trigger, snap.CustomInstructions)
+	}
+}
+
+func TestSessionStartHandler_InjectsSnapshot(t *testing.T) {
+	root, transcript := setupSnapshotProject(t)
+	cfg := &mockConfigProvider{cfg: newTestConfig()}
+
+	if _, err := NewCompactHandlerWithConfig(cfg).Handle(context.Background(), &HookInput{
+		SessionID: "sess-1", CWD: root, TranscriptPath: transcript, CustomInstructions: "keep the auth decisions",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewSessionStartHandler(cfg)
+	for _, source := range []string{"compact", "resume"} {
+		out, err := h.Handle(context.Background(), &HookInput{SessionID: "sess-1", CWD: root, Source: source})
+		if err != nil {
+			t.Fatalf("Handle() error: %v", err)
+		}
+		got := out.HookSpecificOutput.AdditionalContext
+		for _, want := range []string{
+			"Session state saved before compaction",
+			"- Active SPEC: SPEC-AUTH-001 (phase: test, iteration 3/10)",
+			"- Last loop feedback (test #3): build ok, tests 12 passed / 2 failed, 0 lint error(s), coverage 78.5%. token refresh fails",
+			"  - [in_progress] Fix token refresh",
+			"  - internal/auth/login.go L12:5 error: undefined: foo [gopls]",
+			"- Files touched: internal/auth/login.go, internal/auth/token.go",
+			"- Compaction instructions: keep the auth decisions",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("source %s: context missing %q:\n%s", source, want, got)
+			}
+		}
+	}
+
+	// A fresh session and a session without a snapshot only get the facts.
+	for _, input := range []*HookInput{
+		{SessionID: "sess-1", CWD: root, Source: "startup"},
+		{SessionID: "sess-2", CWD: root, Source: "compact"},
+	} {
+		out, err := h.Handle(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Handle() error: %v", err)
+		}
+		if strings.Contains(out.HookSpecificOutput.AdditionalContext, "Session state") {
+			t.Errorf("%+v: unexpected snapshot:\n%s", input, out.HookSpecificOutput.AdditionalContext)
+		}
+	}
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	now := time.Now()
+	for i, age := range []time.Duration{0, time.Hour, 2 * time.Hour, 3 * time.Hour, 30 * 24 * time.Hour} {
+		path, err := saveSnapshot(root, &sessionSnapshot{SessionID: fmt.Sprintf("s%d", i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The snapshot just written survives beyond the retention count.
+	keep := snapshotPath(root, "s3")
+
+	removed := pruneSnapshots(root, config.SnapshotRetention{MaxCount: 2, MaxAgeDays: 14}, keep, now)
+	if removed != 2 {
+		t.Errorf("removed = %d, want 2", removed)
+	}
+	for name, want := range map[string]bool{"s0": true, "s1": true, "s2": false, "s3": true, "s4": false} {
+		_, err := os.Stat(snapshotPath(root, name))
+		if exists := err == nil; exists != want {
+			t.Errorf("%s exists = %v, want %v", name, exists, want)
+		}
+	}
+}
+
+func TestSnapshotPath_StaysInDirectory(t *testing.T) {
+	t.Parallel()
+
+	path := snapshotPath("/repo", "../../etc/passwd")
+	if filepath.Dir(path) != snapshotDir("/repo") {
+		t.Errorf("snapshotPath escaped the snapshot directory: %s", path)
+	}
+}
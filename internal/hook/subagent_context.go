@@ -0,0 +1,407 @@
+package hook
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/defs"
+	lsphook "github.com/modu-ai/moai-adk/internal/lsp/hook"
+)
+
+// Agent runs record what a subagent was given on SubagentStart and the
+// state of the working tree at that moment, so SubagentStop can tell what
+// the agent changed. They are kept in .moai/memory/agents/<agent_id>.json.
+
+const (
+	// maxDirtyFiles bounds the modified files hashed for an agent run.
+	maxDirtyFiles = 500
+
+	// maxBaselineFiles bounds the files listed in the diagnostics summary.
+	maxBaselineFiles = 10
+)
+
+// agentRun is the on-disk record of a subagent started in the project.
+type agentRun struct {
+	AgentID   string    `json:"agentId"`
+	AgentType string    `json:"agentType"`
+	SessionID string    `json:"sessionId,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	// Head is the commit checked out when the agent started.
+	Head string `json:"head,omitempty"`
+	// Dirty maps the files modified or untracked at start to a hash of
+	// their content.
+	Dirty    map[string]string `json:"dirty,omitempty"`
+	Injected []injectedContext `json:"injected,omitempty"`
+	// Reworks counts the times SubagentStop sent the agent back because a
+	// claimed check failed.
+	Reworks int `json:"reworks,omitempty"`
+}
+
+// injectedContext is one section of the context given to a subagent.
+type injectedContext struct {
+	Source string `json:"source"`
+	// Ref names what the section was built from: a SPEC ID or a file.
+	Ref  string `json:"ref,omitempty"`
+	Text string `json:"text"`
+}
+
+// agentContextSettings returns the subagent context rules of cfg, or the
+// defaults when no configuration is available.
+func agentContextSettings(cfg ConfigProvider) config.AgentContextConfig {
+	if cfg != nil {
+		if c := cfg.Get(); c != nil {
+			return c.AgentContext
+		}
+	}
+	return config.NewDefaultAgentContextConfig()
+}
+
+// agentName returns the agent type of a subagent without a plugin
+// namespace, so "moai:manager-spec" matches rules for "manager-spec".
+func agentName(input *HookInput) string {
+	name := input.AgentType
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// matchAgentRules collects the sources and files of every rule matching
+// the agent, in rule order and without duplicates.
+func matchAgentRules(rules []config.AgentContextRule, agent string) (sources, files []string) {
+	for _, r := range rules {
+		if !slices.ContainsFunc(r.Agents, func(p string) bool {
+			ok, err := path.Match(p, agent)
+			return err == nil && ok
+		}) {
+			continue
+		}
+		for _, s := range r.Inject {
+			if !slices.Contains(sources, s) {
+				sources = append(sources, s)
+			}
+		}
+		for _, f := range r.Files {
+			if !slices.Contains(files, f) {
+				files = append(files, f)
+			}
+		}
+	}
+	return sources, files
+}
+
+// buildAgentContext renders the sections configured for the agent. Each
+// section gets an equal share of the token budget.
+func buildAgentContext(cfg *config.Config, settings config.AgentContextConfig, root, agent string) []injectedContext {
+	sources, files := matchAgentRules(settings.Rules, agent)
+
+	var sections []injectedContext
+	for _, s := range sources {
+		var ref, text string
+		switch s {
+		case config.AgentContextSpec:
+			ref, text = renderSpecExcerpt(root)
+		case config.AgentContextDiagnostics:
+			text = renderBaselineSummary(root)
+		case config.AgentContextCoverage:
+			text = renderCoverageSummary(root, cfg)
+		case config.AgentContextProject:
+			if cfg != nil {
+				text = renderSessionFacts(cfg, root)
+			}
+		}
+		if text != "" {
+			sections = append(sections, injectedContext{Source: s, Ref: ref, Text: text})
+		}
+	}
+	for _, f := range files {
+		if text := renderProjectFile(root, f); text != "" {
+			sections = append(sections, injectedContext{Source: "file", Ref: f, Text: text})
+		}
+	}
+
+	if len(sections) > 0 && settings.MaxTokens > 0 {
+		share := settings.MaxTokens / len(sections)
+		for i := range sections {
+			sections[i].Text = fitTokenBudget(sections[i].Text, share)
+		}
+	}
+	return sections
+}
+
+// activeSpecID returns the SPEC being worked on: the latest feedback loop,
+// then the latest unfinished workflow run, then the most recently changed
+// SPEC document.
+func activeSpecID(root string) string {
+	if state := latestLoopState(root); state != nil && state.SpecID != "" {
+		return state.SpecID
+	}
+	if spec := activeWorkflowSpec(root); spec != nil {
+		return spec.ID
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(root, defs.MoAIDir, defs.SpecsSubdir, "*", "spec.md"))
+	var latest string
+	var latestMod time.Time
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err == nil && info.ModTime().After(latestMod) {
+			latest, latestMod = filepath.Base(filepath.Dir(p)), info.ModTime()
+		}
+	}
+	return latest
+}
+
+// renderSpecExcerpt renders the title, status and opening of the active
+// SPEC document, leaving out its front matter and history table.
+func renderSpecExcerpt(root string) (specID, text string) {
+	specID = activeSpecID(root)
+	if specID == "" {
+		return "", ""
+	}
+	data, err := os.ReadFile(filepath.Join(root, defs.MoAIDir, defs.SpecsSubdir, specID, "spec.md"))
+	if err != nil {
+		return specID, ""
+	}
+
+	meta, body := splitFrontMatter(string(data))
+	header := "Active SPEC: " + specID
+	if meta["title"] != "" {
+		header += " - " + meta["title"]
+	}
+	if meta["status"] != "" {
+		header += " (status " + meta["status"] + ")"
+	}
+	return specID, header + "\n\n" + strings.TrimSpace(dropSection(body, "HISTORY"))
+}
+
+// splitFrontMatter separates a leading YAML front matter block from a
+// markdown document and returns its top-level scalar keys.
+func splitFrontMatter(doc string) (map[string]string, string) {
+	meta := map[string]string{}
+	rest, ok := strings.CutPrefix(doc, "---\n")
+	if !ok {
+		return meta, doc
+	}
+	block, body, ok := strings.Cut(rest, "\n---\n")
+	if !ok {
+		return meta, doc
+	}
+	for line := range strings.Lines(block) {
+		key, value, ok := strings.Cut(strings.TrimRight(line, "\n"), ":")
+		if !ok || strings.HasPrefix(key, " ") || strings.HasPrefix(key, "-") {
+			continue
+		}
+		meta[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return meta, body
+}
+
+// dropSection removes the level-two markdown section with the given
+// heading, up to the next heading of that level.
+func dropSection(body, heading string) string {
+	var b strings.Builder
+	skipping := false
+	for line := range strings.Lines(body) {
+		if strings.HasPrefix(line, "## ") {
+			skipping = strings.EqualFold(strings.TrimSpace(line[3:]), heading)
+		}
+		if !skipping {
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+// renderBaselineSummary summarizes the LSP diagnostics baseline: totals
+// and the files with the most errors and warnings.
+func renderBaselineSummary(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, defs.MoAIDir, defs.MemorySubdir, lsphook.BaselineFileName))
+	if err != nil {
+		return ""
+	}
+	var baseline lsphook.DiagnosticsBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return ""
+	}
+
+	type fileCounts struct {
+		path string
+		lsphook.SeverityCounts
+	}
+	var total lsphook.SeverityCounts
+	var files []fileCounts
+	for p, fb := range baseline.Files {
+		var c lsphook.SeverityCounts
+		for _, d := range fb.Diagnostics {
+			switch d.Severity {
+			case lsphook.SeverityError:
+				c.Errors++
+			case lsphook.SeverityWarning:
+				c.Warnings++
+			}
+		}
+		total.Errors += c.Errors
+		total.Warnings += c.Warnings
+		if c.Errors+c.Warnings > 0 {
+			files = append(files, fileCounts{path: relToRoot(root, p), SeverityCounts: c})
+		}
+	}
+	slices.SortFunc(files, func(a, b fileCounts) int {
+		return cmp.Or(cmp.Compare(b.Errors, a.Errors), cmp.Compare(b.Warnings, a.Warnings), strings.Compare(a.path, b.path))
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Diagnostics baseline (%s): %d error(s), %d warning(s) across %d file(s). Do not add new ones.",
+		baseline.UpdatedAt.Local().Format(time.DateTime), total.Errors, total.Warnings, len(files))
+	for i, f := range files {
+		if i == maxBaselineFiles {
+			fmt.Fprintf(&b, "\n- ... %d more file(s)", len(files)-i)
+			break
+		}
+		fmt.Fprintf(&b, "\n- %s: %d error(s), %d warning(s)", f.path, f.Errors, f.Warnings)
+	}
+	return b.String()
+}
+
+// renderCoverageSummary reports the last recorded test coverage against
+// the quality target.
+func renderCoverageSummary(root string, cfg *config.Config) string {
+	data, err := os.ReadFile(filepath.Join(root, defs.MoAIDir, defs.MemorySubdir, "coverage.json"))
+	if err != nil {
+		return ""
+	}
+	var cov coverageData
+	if err := json.Unmarshal(data, &cov); err != nil {
+		return ""
+	}
+	line := fmt.Sprintf("Test coverage: %.1f%%", cov.CoveragePercent)
+	if cfg != nil && cfg.Quality.TestCoverageTarget > 0 {
+		line += fmt.Sprintf(" (target %d%%)", cfg.Quality.TestCoverageTarget)
+	}
+	if cov.UpdatedAt != "" {
+		line += ", measured " + cov.UpdatedAt
+	}
+	return line
+}
+
+// renderProjectFile returns the contents of a project file under a short
+// heading. Paths leaving the project are ignored.
+func renderProjectFile(root, rel string) string {
+	if !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil || len(data) == 0 {
+		return ""
+	}
+	return rel + ":\n" + strings.TrimSpace(string(data))
+}
+
+// renderAgentContext joins the sections into the additionalContext text.
+func renderAgentContext(agent string, sections []injectedContext) string {
+	parts := []string{"MoAI context for " + agent + ":"}
+	for _, s := range sections {
+		parts = append(parts, s.Text)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// agentRunDir is the directory holding the agent run records.
+func agentRunDir(root string) string {
+	return filepath.Join(root, defs.MoAIDir, defs.MemorySubdir, "agents")
+}
+
+// agentRunPath is the record of one subagent.
+func agentRunPath(root, agentID string) string {
+	return filepath.Join(agentRunDir(root), safeFileName(agentID)+".json")
+}
+
+// saveAgentRun writes the record of a subagent.
+func saveAgentRun(root string, run *agentRun) error {
+	if err := os.MkdirAll(agentRunDir(root), 0o755); err != nil {
+		return fmt.Errorf("create agent run directory: %w", err)
+	}
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal agent run: %w", err)
+	}
+	if err := os.WriteFile(agentRunPath(root, run.AgentID), data, 0o644); err != nil {
+		return fmt.Errorf("write agent run: %w", err)
+	}
+	return nil
+}
+
+// loadAgentRun reads the record of a subagent. It returns nil without an
+// error when none exists.
+func loadAgentRun(root, agentID string) (*agentRun, error) {
+	data, err := os.ReadFile(agentRunPath(root, agentID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read agent run: %w", err)
+	}
+	var run agentRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("parse agent run: %w", err)
+	}
+	return &run, nil
+}
+
+// workingTreeState returns the checked out commit and content hashes of
+// the files that differ from it, untracked files included. Outside a git
+// repository both are empty.
+func workingTreeState(ctx context.Context, root string) (string, map[string]string) {
+	head, err := gitOutput(ctx, root, nil, "rev-parse", "HEAD")
+	if err != nil {
+		return "", nil
+	}
+	status, err := gitOutput(ctx, root, nil, "status", "--porcelain", "-z", "--untracked-files=all")
+	if err != nil || status == "" {
+		return head, nil
+	}
+
+	dirty := map[string]string{}
+	entries := strings.Split(status, "\x00")
+	for i := 0; i < len(entries) && len(dirty) < maxDirtyFiles; i++ {
+		e := entries[i]
+		if len(e) < 4 {
+			continue
+		}
+		// Renames and copies are followed by their source path.
+		if e[0] == 'R' || e[0] == 'C' {
+			i++
+		}
+		rel := e[3:]
+		dirty[rel] = fileDigest(filepath.Join(root, rel))
+	}
+	return head, dirty
+}
+
+// fileDigest hashes a file's content; a missing file hashes to "deleted".
+func fileDigest(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return "deleted"
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, bufio.NewReader(f)); err != nil {
+		return "unreadable"
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
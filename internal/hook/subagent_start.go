@@ -3,28 +3,85 @@ package hook
 import (
 	"context"
 	"log/slog"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/config"
 )
 
 // subagentStartHandler processes SubagentStart events.
-// It logs subagent startup for session tracking.
-type subagentStartHandler struct{}
+// It logs subagent startup for session tracking and gives the subagent the
+// project context configured for its agent type.
+type subagentStartHandler struct {
+	cfg ConfigProvider
+}
 
 // NewSubagentStartHandler creates a new SubagentStart event handler.
 func NewSubagentStartHandler() Handler {
 	return &subagentStartHandler{}
 }
 
+// NewSubagentStartHandlerWithConfig creates a SubagentStart handler that
+// injects context according to the agent_context rules of cfg.
+func NewSubagentStartHandlerWithConfig(cfg ConfigProvider) Handler {
+	return &subagentStartHandler{cfg: cfg}
+}
+
 // EventType returns EventSubagentStart.
 func (h *subagentStartHandler) EventType() EventType {
 	return EventSubagentStart
 }
 
-// Handle processes a SubagentStart event. It logs the subagent startup details.
+// Handle processes a SubagentStart event. It logs the subagent startup
+// details and, inside a MoAI project, renders the sections configured for
+// the agent type as additionalContext. The sections and the working tree
+// state are recorded in .moai/memory/agents/<agent_id>.json for
+// SubagentStop. Errors are non-blocking.
 func (h *subagentStartHandler) Handle(ctx context.Context, input *HookInput) (*HookOutput, error) {
 	slog.Info("subagent started",
 		"session_id", input.SessionID,
 		"agent_id", input.AgentID,
+		"agent_type", input.AgentType,
 		"agent_transcript_path", input.AgentTranscriptPath,
 	)
-	return &HookOutput{}, nil
+
+	root := resolveProjectRoot(input)
+	if root == "" || input.AgentID == "" {
+		return &HookOutput{}, nil
+	}
+
+	agent := agentName(input)
+	run := &agentRun{
+		AgentID:   input.AgentID,
+		AgentType: agent,
+		SessionID: input.SessionID,
+		StartedAt: time.Now(),
+	}
+	run.Head, run.Dirty = workingTreeState(ctx, root)
+
+	output := &HookOutput{}
+	if settings := agentContextSettings(h.cfg); settings.Enabled {
+		run.Injected = buildAgentContext(h.getConfig(), settings, root, agent)
+		if len(run.Injected) > 0 {
+			output.HookSpecificOutput = &HookSpecificOutput{
+				HookEventName:     string(EventSubagentStart),
+				AdditionalContext: renderAgentContext(agent, run.Injected),
+			}
+		}
+	}
+
+	if err := saveAgentRun(root, run); err != nil {
+		slog.Warn("failed to record subagent start",
+			"agent_id", input.AgentID,
+			"error", err.Error(),
+		)
+	}
+	return output, nil
+}
+
+// getConfig safely retrieves the configuration, returning nil if unavailable.
+func (h *subagentStartHandler) getConfig() *config.Config {
+	if h.cfg == nil {
+		return nil
+	}
+	return h.cfg.Get()
 }
@@ -2,7 +2,11 @@ package hook
 
 import (
 	"context"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/modu-ai/moai-adk/internal/config"
 )
 
 func TestSubagentStartHandler_EventType(t *testing.T) {
@@ -16,7 +20,8 @@ func TestSubagentStartHandler_EventType(t *testing.T) {
 }
 
 func TestSubagentStartHandler_Handle(t *testing.T) {
-	t.Parallel()
+	// Outside a MoAI project nothing is injected or recorded.
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
 
 	tests := []struct {
 		name  string
@@ -49,8 +54,6 @@ func TestSubagentStartHandler_Handle(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-
 			h := NewSubagentStartHandler()
 			ctx := context.Background()
 			got, err := h.Handle(ctx, tt.input)
@@ -67,3 +70,118 @@ func TestSubagentStartHandler_Handle(t *testing.T) {
 		})
 	}
 }
+
+func TestSubagentStartHandler_InjectsAgentContext(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	root := newMoAIProject(t)
+
+	spec := `---
+id: SPEC-AUTH-001
+title: Token refresh
+status: Draft
+---
+
+# SPEC-AUTH-001: Token refresh
+
+## HISTORY
+
+| Version | Date | Description |
+| 0.1.0 | 2026-10-01 | Initial |
+
+## Requirements
+
+- WHEN a refresh token is reused THEN the session SHALL be revoked.
+`
+	writeProjectFile(t, root, ".moai/specs/SPEC-AUTH-001/spec.md", spec)
+	writeProjectFile(t, root, ".moai/memory/coverage.json", `{"coverage_percent": 72.5, "updated_at": "2026-10-17"}`)
+	writeProjectFile(t, root, ".moai/memory/diagnostics-baseline.json", `{"version":"1.0.0","files":{
+		"auth/refresh.go":{"diagnostics":[{"severity":"error","message":"x"},{"severity":"warning","message":"y"}]},
+		"auth/store.go":{"diagnostics":[{"severity":"warning","message":"z"}]}}}`)
+	writeProjectFile(t, root, "docs/tdd.md", "Write the failing test first.\n")
+
+	cfg := newTestConfig()
+	cfg.Quality.TestCoverageTarget = 85
+	cfg.AgentContext.Rules = append(cfg.AgentContext.Rules, config.AgentContextRule{
+		Agents: []string{"manager-tdd"}, Files: []string{"docs/tdd.md", "../outside.md"},
+	})
+	h := NewSubagentStartHandlerWithConfig(&mockConfigProvider{cfg: cfg})
+
+	out, err := h.Handle(context.Background(), &HookInput{
+		SessionID: "s1", CWD: root, AgentID: "a-42", AgentType: "moai:manager-tdd",
+	})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	if out.HookSpecificOutput == nil || out.HookSpecificOutput.HookEventName != "SubagentStart" {
+		t.Fatalf("HookSpecificOutput = %+v", out.HookSpecificOutput)
+	}
+	got := out.HookSpecificOutput.AdditionalContext
+	for _, want := range []string{
+		"MoAI context for manager-tdd:",
+		"Active SPEC: SPEC-AUTH-001 - Token refresh (status Draft)",
+		"the session SHALL be revoked",
+		"Test coverage: 72.5% (target 85%), measured 2026-10-17",
+		"docs/tdd.md:\nWrite the failing test first.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("context missing %q:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"HISTORY", "Diagnostics baseline", "outside.md"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("context should not contain %q:\n%s", unwanted, got)
+		}
+	}
+
+	run, err := loadAgentRun(root, "a-42")
+	if err != nil || run == nil {
+		t.Fatalf("loadAgentRun() = %v, %v", run, err)
+	}
+	if run.AgentType != "manager-tdd" || run.SessionID != "s1" || len(run.Injected) != 3 {
+		t.Errorf("run = %+v", run)
+	}
+	if run.Injected[0].Source != config.AgentContextSpec || run.Injected[0].Ref != "SPEC-AUTH-001" {
+		t.Errorf("first section = %+v", run.Injected[0])
+	}
+
+	// Quality agents get the diagnostics baseline instead.
+	out, err = h.Handle(context.Background(), &HookInput{SessionID: "s1", CWD: root, AgentID: "a-43", AgentType: "manager-quality"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = out.HookSpecificOutput.AdditionalContext
+	if !strings.Contains(got, "1 error(s), 2 warning(s) across 2 file(s)") {
+		t.Errorf("diagnostics summary missing:\n%s", got)
+	}
+	if !strings.Contains(got, "- auth/refresh.go: 1 error(s), 1 warning(s)\n- auth/store.go: 0 error(s), 1 warning(s)") {
+		t.Errorf("files not ranked by errors:\n%s", got)
+	}
+}
+
+func TestSubagentStartHandler_RecordsWorkingTree(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	main, _ := newWorktreeProject(t)
+	writeProjectFile(t, main, "README.md", "changed\n")
+
+	cfg := newTestConfig()
+	cfg.AgentContext.Enabled = false
+	h := NewSubagentStartHandlerWithConfig(&mockConfigProvider{cfg: cfg})
+	out, err := h.Handle(context.Background(), &HookInput{CWD: main, AgentID: "a-1", AgentType: "expert-backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.HookSpecificOutput != nil {
+		t.Errorf("disabled injection produced context: %+v", out.HookSpecificOutput)
+	}
+
+	run, err := loadAgentRun(main, "a-1")
+	if err != nil || run == nil {
+		t.Fatalf("loadAgentRun() = %v, %v", run, err)
+	}
+	if run.Head != gitIn(t, main, "rev-parse", "HEAD") {
+		t.Errorf("head = %q", run.Head)
+	}
+	if run.Dirty["README.md"] != fileDigest(filepath.Join(main, "README.md")) || run.Dirty["vendor/lib.go"] == "" {
+		t.Errorf("dirty = %v", run.Dirty)
+	}
+}
@@ -0,0 +1,185 @@
+package hook
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/agentstats"
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/rank"
+)
+
+// subagentStopHandler processes SubagentStop events.
+// It verifies the outcomes a subagent claims in its final report by
+// re-running the matching checks and records the finished run for the
+// per-agent scorecards of `moai agents stats`.
+type subagentStopHandler struct {
+	cfg ConfigProvider
+	now func() time.Time
+}
+
+// NewSubagentStopHandler creates a new SubagentStop event handler with the
+// default verification settings.
+func NewSubagentStopHandler() Handler {
+	return &subagentStopHandler{now: time.Now}
+}
+
+// NewSubagentStopHandlerWithConfig creates a SubagentStop handler that
+// verifies claims according to the agent_verification section of cfg.
+func NewSubagentStopHandlerWithConfig(cfg ConfigProvider) Handler {
+	return &subagentStopHandler{cfg: cfg, now: time.Now}
+}
+
+// EventType returns EventSubagentStop.
+func (h *subagentStopHandler) EventType() EventType {
+	return EventSubagentStop
+}
+
+// Handle processes a SubagentStop event. Inside a MoAI project it reads the
+// subagent transcript, re-runs the checks behind claims such as "all tests
+// pass" and, when one fails, sends the subagent back with the failure
+// output. A subagent already continuing because of a stop hook is not
+// blocked again. Otherwise the run is appended to
+// .moai/logs/agent-runs.jsonl. Errors are non-blocking.
+func (h *subagentStopHandler) Handle(ctx context.Context, input *HookInput) (*HookOutput, error) {
+	slog.Info("subagent stopped",
+		"session_id", input.SessionID,
+		"agent_id", input.AgentID,
+		"agent_type", input.AgentType,
+		"stop_hook_active", input.StopHookActive,
+	)
+
+	// A fixture replay neither re-runs checks nor records the run.
+	root := resolveProjectRoot(input)
+	if root == "" || input.AgentID == "" || isReplay(ctx) {
+		return &HookOutput{}, nil
+	}
+
+	run, err := loadAgentRun(root, input.AgentID)
+	if err != nil {
+		slog.Warn("failed to load subagent start record",
+			"agent_id", input.AgentID,
+			"error", err.Error(),
+		)
+	}
+	if run == nil {
+		run = &agentRun{AgentID: input.AgentID, AgentType: agentName(input), SessionID: input.SessionID}
+	}
+
+	report := &agentReport{}
+	if input.AgentTranscriptPath != "" {
+		if r, err := scanAgentTranscript(input.AgentTranscriptPath); err == nil {
+			report = r
+		} else {
+			slog.Warn("failed to read subagent transcript",
+				"agent_id", input.AgentID,
+				"error", err.Error(),
+			)
+		}
+	}
+
+	settings := agentVerificationSettings(h.cfg)
+	var claims []agentstats.Claim
+	if settings.Enabled {
+		claims = verifyClaims(ctx, settings, root, report)
+	}
+	failed := failedClaims(claims)
+
+	if len(failed) > 0 && settings.Block && !input.StopHookActive {
+		run.Reworks++
+		if err := saveAgentRun(root, run); err != nil {
+			slog.Warn("failed to record subagent rework",
+				"agent_id", input.AgentID,
+				"error", err.Error(),
+			)
+		}
+		return NewStopBlockOutput(renderFailedClaims(failed)), nil
+	}
+
+	record := agentstats.Run{
+		Timestamp:    h.now(),
+		SessionID:    input.SessionID,
+		AgentID:      input.AgentID,
+		Agent:        run.AgentType,
+		ToolUses:     report.ToolUses,
+		FilesChanged: changedFiles(ctx, root, run),
+		Claims:       claims,
+		Success:      len(failed) == 0,
+		Reworks:      run.Reworks,
+	}
+	if !run.StartedAt.IsZero() {
+		record.DurationSeconds = record.Timestamp.Sub(run.StartedAt).Seconds()
+	}
+	if input.AgentTranscriptPath != "" {
+		if usage, err := rank.ParseTranscript(input.AgentTranscriptPath); err == nil {
+			record.Tokens = usage.InputTokens + usage.OutputTokens + usage.CacheCreationTokens + usage.CacheReadTokens
+		}
+	}
+
+	if err := agentstats.Append(root, record); err != nil {
+		slog.Warn("failed to record subagent run",
+			"agent_id", input.AgentID,
+			"error", err.Error(),
+		)
+	}
+	_ = os.Remove(agentRunPath(root, input.AgentID))
+
+	output := &HookOutput{}
+	if len(failed) > 0 {
+		kinds := make([]string, len(failed))
+		for i, c := range failed {
+			kinds[i] = c.Kind
+		}
+		output.SystemMessage = fmt.Sprintf("Subagent %s reported success but its %s check(s) failed", cmp.Or(record.Agent, "(unknown)"), strings.Join(kinds, ", "))
+	}
+	return output, nil
+}
+
+// agentVerificationSettings returns the claim verification settings of
+// cfg, or the defaults when no configuration is available.
+func agentVerificationSettings(cfg ConfigProvider) config.AgentVerificationConfig {
+	if cfg != nil {
+		if c := cfg.Get(); c != nil {
+			return c.AgentVerification
+		}
+	}
+	return config.NewDefaultAgentVerificationConfig()
+}
+
+// changedFiles returns the files the subagent changed: files committed
+// since its start and files whose content differs from the start state.
+// It is empty when the start state is unknown.
+func changedFiles(ctx context.Context, root string, run *agentRun) []string {
+	if run.Head == "" {
+		return nil
+	}
+	changed := map[string]bool{}
+	if out, err := gitOutput(ctx, root, nil, "diff", "--name-only", run.Head, "HEAD"); err == nil {
+		for l := range strings.Lines(out) {
+			if l = strings.TrimSpace(l); l != "" {
+				changed[l] = true
+			}
+		}
+	}
+
+	_, dirty := workingTreeState(ctx, root)
+	for rel, digest := range dirty {
+		if digest != run.Dirty[rel] {
+			changed[rel] = true
+		}
+	}
+	for rel, digest := range run.Dirty {
+		if _, still := dirty[rel]; !still && fileDigest(filepath.Join(root, rel)) != digest {
+			changed[rel] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(changed))
+}
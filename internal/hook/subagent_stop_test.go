@@ -0,0 +1,250 @@
+package hook
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/agentstats"
+	"github.com/modu-ai/moai-adk/internal/config"
+)
+
+// writeAgentTranscript writes a subagent transcript that ran command and
+// ended with report.
+func writeAgentTranscript(t *testing.T, dir, command, report string) string {
+	t.Helper()
+	path := filepath.Join(dir, "agent.jsonl")
+	content := `{"type":"user","isSidechain":true,"message":{"role":"user","content":"Implement the feature"}}
+{"type":"assistant","isSidechain":true,"timestamp":"2026-10-18T09:00:00Z","message":{"id":"m1","role":"assistant","usage":{"input_tokens":100,"output_tokens":50,"cache_read_input_tokens":1000},"content":[{"type":"tool_use","name":"Bash","input":{"command":"` + command + `"}}]}}
+{"type":"user","isSidechain":true,"message":{"role":"user","content":[{"type":"tool_result","content":"ok"}]}}
+{"type":"assistant","isSidechain":true,"timestamp":"2026-10-18T09:01:00Z","message":{"id":"m2","role":"assistant","usage":{"input_tokens":200,"output_tokens":80},"content":[{"type":"text","text":"` + report + `"}]}}
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// verificationConfig returns a config whose tests check runs git args.
+func verificationConfig(args ...string) *mockConfigProvider {
+	cfg := newTestConfig()
+	cfg.AgentVerification.Checks = []config.AgentCheck{
+		{Claim: config.AgentClaimTests, Command: "git", Args: args},
+	}
+	return &mockConfigProvider{cfg: cfg}
+}
+
+func TestSubagentStopHandler_EventType(t *testing.T) {
+	t.Parallel()
+
+	if got := NewSubagentStopHandler().EventType(); got != EventSubagentStop {
+		t.Errorf("EventType() = %q, want %q", got, EventSubagentStop)
+	}
+}
+
+func TestSubagentStopHandler_RecordsVerifiedRun(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	root, _ := newWorktreeProject(t)
+
+	start := NewSubagentStartHandlerWithConfig(&mockConfigProvider{cfg: newTestConfig()})
+	input := &HookInput{SessionID: "s1", CWD: root, AgentID: "agent-1", AgentType: "moai:expert-backend"}
+	if _, err := start.Handle(context.Background(), input); err != nil {
+		t.Fatal(err)
+	}
+
+	// The agent edits one file and commits another; .env was modified
+	// before it started and is left alone.
+	writeProjectFile(t, root, "README.md", "hello\nagain\n")
+	writeProjectFile(t, root, "api.go", "package api\n")
+	gitIn(t, root, "add", "api.go")
+	gitIn(t, root, "commit", "-q", "-m", "add api")
+
+	run, err := loadAgentRun(root, "agent-1")
+	if err != nil || run == nil {
+		t.Fatalf("start record missing: %v", err)
+	}
+	run.StartedAt = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	if err := saveAgentRun(root, run); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &subagentStopHandler{
+		cfg: verificationConfig("status", "--short"),
+		now: func() time.Time { return time.Date(2026, 10, 18, 9, 2, 30, 0, time.UTC) },
+	}
+	input.AgentTranscriptPath = writeAgentTranscript(t, t.TempDir(), "go test ./api/...", "Done. All tests pass.")
+	out, err := h.Handle(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	if out.Decision != "" || out.SystemMessage != "" {
+		t.Errorf("verified run produced output: %+v", out)
+	}
+
+	runs, err := agentstats.ReadRuns(root, time.Time{})
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("ReadRuns() = %+v, %v", runs, err)
+	}
+	got := runs[0]
+	if got.Agent != "expert-backend" || !got.Success || got.Reworks != 0 || got.ToolUses != 1 {
+		t.Errorf("run = %+v", got)
+	}
+	if got.DurationSeconds != 150 || got.Tokens != 1430 {
+		t.Errorf("duration = %v, tokens = %d", got.DurationSeconds, got.Tokens)
+	}
+	if !slices.Equal(got.FilesChanged, []string{"README.md", "api.go"}) {
+		t.Errorf("files changed = %v", got.FilesChanged)
+	}
+	if len(got.Claims) != 1 || got.Claims[0].Status != agentstats.ClaimVerified || got.Claims[0].Check != "git status --short" {
+		t.Errorf("claims = %+v", got.Claims)
+	}
+	if run, _ := loadAgentRun(root, "agent-1"); run != nil {
+		t.Error("start record not removed after the run was recorded")
+	}
+}
+
+func TestSubagentStopHandler_BlocksFailedClaim(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	root := newMoAIProject(t)
+
+	h := NewSubagentStopHandlerWithConfig(verificationConfig("no-such-command"))
+	input := &HookInput{
+		CWD:                 root,
+		AgentID:             "agent-2",
+		AgentType:           "manager-tdd",
+		AgentTranscriptPath: writeAgentTranscript(t, t.TempDir(), "ls", "Implemented. The tests are passing and the build succeeds."),
+	}
+	out, err := h.Handle(context.Background(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Decision != DecisionBlock || !strings.Contains(out.Reason, "- tests: `git no-such-command` failed") {
+		t.Errorf("output = %+v", out)
+	}
+	// No toolchain is detected, so the build claim is left unverified.
+	if strings.Contains(out.Reason, "build") {
+		t.Errorf("unverified claim reported as failed: %q", out.Reason)
+	}
+	if runs, _ := agentstats.ReadRuns(root, time.Time{}); len(runs) != 0 {
+		t.Errorf("blocked run recorded: %+v", runs)
+	}
+
+	// The agent stops again without fixing the tests: the run is recorded
+	// as a failure with one rework instead of looping.
+	input.StopHookActive = true
+	out, err = h.Handle(context.Background(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Decision != "" || out.SystemMessage != "Subagent manager-tdd reported success but its tests check(s) failed" {
+		t.Errorf("output = %+v", out)
+	}
+	runs, _ := agentstats.ReadRuns(root, time.Time{})
+	if len(runs) != 1 || runs[0].Success || runs[0].Reworks != 1 || len(runs[0].Claims) != 2 {
+		t.Fatalf("runs = %+v", runs)
+	}
+	if c := runs[0].Claims[1]; c.Kind != config.AgentClaimBuild || c.Status != agentstats.ClaimUnverified {
+		t.Errorf("build claim = %+v", c)
+	}
+}
+
+func TestSubagentStopHandler_BlockDisabled(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	root := newMoAIProject(t)
+
+	provider := verificationConfig("no-such-command")
+	provider.cfg.AgentVerification.Block = false
+	h := NewSubagentStopHandlerWithConfig(provider)
+	out, err := h.Handle(context.Background(), &HookInput{
+		CWD:                 root,
+		AgentID:             "agent-3",
+		AgentTranscriptPath: writeAgentTranscript(t, t.TempDir(), "ls", "All 12 tests pass."),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Decision != "" || out.SystemMessage == "" {
+		t.Errorf("output = %+v", out)
+	}
+}
+
+func TestDetectClaims(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"All tests pass.", []string{"tests"}},
+		{"The 42 unit tests are now passing; build succeeds and lint is clean.", []string{"tests", "build", "lint"}},
+		{"Project compiles without errors.", []string{"build"}},
+		{"The code builds cleanly.", []string{"build"}},
+		{"Two tests fail and need a fixture.", nil},
+		{"I wrote tests for the parser.", nil},
+	}
+	for _, tt := range tests {
+		if got := detectClaims(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("detectClaims(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestRerunnableCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		command string
+		kind    string
+		want    bool
+	}{
+		{"go test ./...", config.AgentClaimTests, true},
+		{"npm run test -- --watch=false", config.AgentClaimTests, true},
+		{"python3 -m pytest -q", config.AgentClaimTests, true},
+		{"cargo clippy --all-targets", config.AgentClaimLint, true},
+		{"go build ./cmd/moai", config.AgentClaimBuild, true},
+		// Commands mentioning a keyword are not checks.
+		{"npm publish --tag latest", config.AgentClaimTests, false},
+		{"go run ./cmd/migrate --target=latest", config.AgentClaimTests, false},
+		{"make build-and-deploy", config.AgentClaimBuild, false},
+		{"make test", config.AgentClaimTests, false},
+		{"npx vitest run", config.AgentClaimTests, false},
+		{"python deploy_tests.py", config.AgentClaimTests, false},
+		{"gotest ./...", config.AgentClaimTests, false},
+		// A check of another kind does not verify the claim.
+		{"go vet ./...", config.AgentClaimTests, false},
+		{"go test ./... && ./deploy.sh", config.AgentClaimTests, false},
+	}
+	for _, tt := range tests {
+		if got := rerunnableCommand(tt.command, tt.kind) != nil; got != tt.want {
+			t.Errorf("rerunnableCommand(%q, %s) = %v, want %v", tt.command, tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestClaimCheck(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeProjectFile(t, root, "package.json", `{"scripts":{"test":"vitest","lint":"eslint ."}}`)
+	settings := config.NewDefaultAgentVerificationConfig()
+
+	commands := []string{"npm test -- parser", "npm test | tee out.log", "ls"}
+	if got := claimCheck(settings, root, config.AgentClaimTests, commands); !slices.Equal(got, []string{"npm", "test", "--", "parser"}) {
+		t.Errorf("tests check = %v, want the last safe test command", got)
+	}
+	if got := claimCheck(settings, root, config.AgentClaimLint, nil); !slices.Equal(got, []string{"npm", "run", "lint"}) {
+		t.Errorf("lint check = %v", got)
+	}
+	if got := claimCheck(settings, root, config.AgentClaimBuild, nil); got != nil {
+		t.Errorf("build check = %v, want none without a build script", got)
+	}
+
+	settings.Checks = []config.AgentCheck{{Claim: config.AgentClaimTests, Command: "make", Args: []string{"check"}}}
+	if got := claimCheck(settings, root, config.AgentClaimTests, commands); !slices.Equal(got, []string{"make", "check"}) {
+		t.Errorf("configured check = %v", got)
+	}
+}
@@ -0,0 +1,331 @@
+package hook
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/agentstats"
+	"github.com/modu-ai/moai-adk/internal/config"
+)
+
+const (
+	// checkOutputLines bounds the output kept from a failed check.
+	checkOutputLines = 15
+
+	// checkDeadlineMargin is left of the hook deadline when a check runs,
+	// so a slow check is reported as unverified instead of timing out the
+	// whole hook.
+	checkDeadlineMargin = 3 * time.Second
+)
+
+// claimPatterns recognize outcomes a subagent reports as achieved in its
+// final message.
+var claimPatterns = []struct {
+	kind string
+	re   *regexp.Regexp
+}{
+	{config.AgentClaimTests, regexp.MustCompile(`(?i)\b(?:all\s+)?(?:\d+\s+)?(?:unit\s+|integration\s+|the\s+)?tests?(?:\s+suite)?\s+(?:are\s+|is\s+|now\s+|all\s+|still\s+)*(?:pass(?:es|ed|ing)?|succeed(?:s|ed)?|green)\b`)},
+	{config.AgentClaimBuild, regexp.MustCompile(`(?i)\bbuilds?\s+(?:is\s+|now\s+)*(?:pass(?:es|ed|ing)?|succeed(?:s|ed)?|successful(?:ly)?|clean(?:ly)?|green)\b|\b(?:builds|compiles)\s+(?:cleanly|successfully|without\s+errors)\b`)},
+	{config.AgentClaimLint, regexp.MustCompile(`(?i)\b(?:lint(?:er|ers|ing)?|vet)\s+(?:is\s+|are\s+|now\s+|checks?\s+)*(?:pass(?:es|ed|ing)?|clean|green)\b|\bno\s+lint(?:er)?\s+(?:errors|warnings|issues)\b`)},
+}
+
+// rerunnableChecks are the commands from the transcript that are re-run as
+// checks of a kind of claim. A command qualifies only when its leading
+// words equal one of these, so publishing, deploying or migrating commands
+// that merely mention a keyword are never run.
+var rerunnableChecks = map[string][][]string{
+	config.AgentClaimTests: {
+		{"go", "test"}, {"cargo", "test"}, {"deno", "test"}, {"dotnet", "test"}, {"mix", "test"},
+		{"mvn", "test"}, {"gradle", "test"}, {"./gradlew", "test"},
+		{"npm", "test"}, {"npm", "run", "test"}, {"pnpm", "test"}, {"pnpm", "run", "test"},
+		{"yarn", "test"}, {"yarn", "run", "test"}, {"bun", "test"}, {"bun", "run", "test"},
+		{"pytest"}, {"python", "-m", "pytest"}, {"python3", "-m", "pytest"}, {"uv", "run", "pytest"},
+		{"jest"}, {"vitest"}, {"rspec"}, {"bundle", "exec", "rspec"},
+	},
+	config.AgentClaimBuild: {
+		{"go", "build"}, {"cargo", "build"}, {"dotnet", "build"}, {"mix", "compile"},
+		{"mvn", "compile"}, {"gradle", "build"}, {"./gradlew", "build"},
+		{"npm", "run", "build"}, {"pnpm", "run", "build"}, {"pnpm", "build"},
+		{"yarn", "build"}, {"yarn", "run", "build"}, {"bun", "run", "build"}, {"tsc"},
+	},
+	config.AgentClaimLint: {
+		{"go", "vet"}, {"cargo", "clippy"}, {"cargo", "check"}, {"golangci-lint", "run"},
+		{"npm", "run", "lint"}, {"pnpm", "run", "lint"}, {"pnpm", "lint"},
+		{"yarn", "lint"}, {"yarn", "run", "lint"}, {"bun", "run", "lint"},
+		{"ruff", "check"}, {"eslint"},
+	},
+}
+
+// agentReport is what SubagentStop reads from a subagent transcript.
+type agentReport struct {
+	// FinalText is the text of the last assistant message.
+	FinalText string
+	// Commands are the Bash commands the agent ran, in order.
+	Commands []string
+	ToolUses int
+}
+
+// agentTranscriptLine is the part of a subagent transcript line read by
+// scanAgentTranscript.
+type agentTranscriptLine struct {
+	Type    string `json:"type"`
+	Message struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// agentContentBlock is a content block of an assistant message.
+type agentContentBlock struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Name  string `json:"name"`
+	Input struct {
+		Command string `json:"command"`
+	} `json:"input"`
+}
+
+// scanAgentTranscript reads a subagent transcript. Unlike the session
+// transcript, every line of it belongs to the subagent's side chain.
+func scanAgentTranscript(path string) (*agentReport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open agent transcript: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	report := &agentReport{}
+	r := bufio.NewReader(f)
+	for {
+		line, readErr := r.ReadBytes('\n')
+		if bytes.Contains(line, []byte(`"assistant"`)) {
+			var tl agentTranscriptLine
+			var blocks []agentContentBlock
+			if json.Unmarshal(line, &tl) == nil && tl.Type == "assistant" && json.Unmarshal(tl.Message.Content, &blocks) == nil {
+				var texts []string
+				for _, b := range blocks {
+					switch b.Type {
+					case "text":
+						texts = append(texts, b.Text)
+					case "tool_use":
+						report.ToolUses++
+						if b.Name == "Bash" && b.Input.Command != "" {
+							report.Commands = append(report.Commands, b.Input.Command)
+						}
+					}
+				}
+				if len(texts) > 0 {
+					report.FinalText = strings.Join(texts, "\n")
+				}
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("read agent transcript: %w", readErr)
+		}
+	}
+	return report, nil
+}
+
+// detectClaims returns the kinds of outcomes claimed in text, in the order
+// of claimPatterns.
+func detectClaims(text string) []string {
+	var kinds []string
+	for _, p := range claimPatterns {
+		if p.re.MatchString(text) {
+			kinds = append(kinds, p.kind)
+		}
+	}
+	return kinds
+}
+
+// claimCheck returns the command verifying a kind of claim: the configured
+// check, else the last matching command the agent ran itself, else the
+// check of the project's toolchain. It returns nil when none is known.
+func claimCheck(settings config.AgentVerificationConfig, root, kind string, commands []string) []string {
+	for _, c := range settings.Checks {
+		if c.Claim == kind && c.Command != "" {
+			return append([]string{c.Command}, c.Args...)
+		}
+	}
+	for _, command := range slices.Backward(commands) {
+		if argv := rerunnableCommand(command, kind); argv != nil {
+			return argv
+		}
+	}
+	return toolchainCheck(root, kind)
+}
+
+// rerunnableCommand splits a Bash command into arguments when it is a
+// known check of kind (see rerunnableChecks) that can be run without a
+// shell: a single command without pipes, redirections, substitutions or
+// globs.
+func rerunnableCommand(command, kind string) []string {
+	if strings.ContainsAny(command, "|&;<>$`(){}*?[]\\\"'\n") {
+		return nil
+	}
+	argv := strings.Fields(command)
+	for _, prefix := range rerunnableChecks[kind] {
+		if len(argv) >= len(prefix) && slices.Equal(argv[:len(prefix)], prefix) {
+			return argv
+		}
+	}
+	return nil
+}
+
+// toolchainCheck returns the conventional check of kind for the project's
+// toolchain.
+func toolchainCheck(root, kind string) []string {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(root, name))
+		return err == nil
+	}
+	switch {
+	case exists("go.mod"):
+		return map[string][]string{
+			config.AgentClaimTests: {"go", "test", "./..."},
+			config.AgentClaimBuild: {"go", "build", "./..."},
+			config.AgentClaimLint:  {"go", "vet", "./..."},
+		}[kind]
+	case exists("Cargo.toml"):
+		return map[string][]string{
+			config.AgentClaimTests: {"cargo", "test"},
+			config.AgentClaimBuild: {"cargo", "build"},
+			config.AgentClaimLint:  {"cargo", "clippy"},
+		}[kind]
+	case exists("package.json"):
+		script := map[string]string{
+			config.AgentClaimTests: "test",
+			config.AgentClaimBuild: "build",
+			config.AgentClaimLint:  "lint",
+		}[kind]
+		if packageScripts(root)[script] {
+			return []string{"npm", "run", script}
+		}
+	case exists("pyproject.toml"), exists("pytest.ini"), exists("setup.py"):
+		if kind == config.AgentClaimTests {
+			return []string{"python3", "-m", "pytest", "-q"}
+		}
+	}
+	return nil
+}
+
+// packageScripts returns the script names defined in package.json.
+func packageScripts(root string) map[string]bool {
+	data, err := os.ReadFile(filepath.Join(root, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return nil
+	}
+	names := make(map[string]bool, len(pkg.Scripts))
+	for name := range pkg.Scripts {
+		names[name] = true
+	}
+	return names
+}
+
+// verifyClaims re-runs the check behind every claim in the report. Claims
+// sharing a command share its result.
+func verifyClaims(ctx context.Context, settings config.AgentVerificationConfig, root string, report *agentReport) []agentstats.Claim {
+	results := map[string]agentstats.Claim{}
+	var claims []agentstats.Claim
+	for _, kind := range detectClaims(report.FinalText) {
+		argv := claimCheck(settings, root, kind, report.Commands)
+		if argv == nil {
+			claims = append(claims, agentstats.Claim{Kind: kind, Status: agentstats.ClaimUnverified, Detail: "no check known for this claim"})
+			continue
+		}
+		check := strings.Join(argv, " ")
+		res, ok := results[check]
+		if !ok {
+			res = runCheck(ctx, root, argv, settings.Timeout)
+			results[check] = res
+		}
+		res.Kind = kind
+		claims = append(claims, res)
+	}
+	return claims
+}
+
+// runCheck runs a check command in root. A check that cannot be started or
+// does not finish in time leaves the claim unverified.
+func runCheck(ctx context.Context, root string, argv []string, timeout time.Duration) agentstats.Claim {
+	claim := agentstats.Claim{Check: strings.Join(argv, " ")}
+	if timeout <= 0 {
+		timeout = config.DefaultAgentVerifyTimeout
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = min(timeout, time.Until(deadline)-checkDeadlineMargin)
+	}
+	if timeout <= 0 {
+		claim.Status = agentstats.ClaimUnverified
+		claim.Detail = "no time left to run the check"
+		return claim
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = root
+	out, err := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		claim.Status = agentstats.ClaimUnverified
+		claim.Detail = "timed out after " + timeout.Round(time.Second).String()
+	case errors.As(err, &exitErr):
+		claim.Status = agentstats.ClaimFailed
+		claim.Detail = lastLines(string(out), checkOutputLines)
+	case err != nil:
+		claim.Status = agentstats.ClaimUnverified
+		claim.Detail = err.Error()
+	default:
+		claim.Status = agentstats.ClaimVerified
+	}
+	return claim
+}
+
+// failedClaims returns the claims whose check failed.
+func failedClaims(claims []agentstats.Claim) []agentstats.Claim {
+	var failed []agentstats.Claim
+	for _, c := range claims {
+		if c.Status == agentstats.ClaimFailed {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// renderFailedClaims explains the failed claims to the subagent.
+func renderFailedClaims(failed []agentstats.Claim) string {
+	var b strings.Builder
+	b.WriteString("Your report claims outcomes that failed verification:\n")
+	for _, c := range failed {
+		fmt.Fprintf(&b, "- %s: `%s` failed", c.Kind, c.Check)
+		if c.Detail != "" {
+			fmt.Fprintf(&b, ": %s", c.Detail)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("Fix the failures and re-run the check before reporting success, or report them as open issues.")
+	return b.String()
+}
@@ -127,7 +127,7 @@ type HookInput struct {
 	// SessionStart fields
 	Source    string `json:"source,omitempty"`     // startup, resume, clear, compact
 	Model     string `json:"model,omitempty"`      // Model identifier
-	AgentType string `json:"agent_type,omitempty"` // Custom agent name if --agent flag used
+	AgentType string `json:"agent_type,omitempty"` // Custom agent name if --agent flag used; the subagent type on SubagentStart/SubagentStop
 
 	// SessionEnd fields
 	Reason string `json:"reason,omitempty"` // clear, logout, prompt_input_exit, bypass_permissions_disabled, other
@@ -180,6 +180,8 @@ type HookSpecificOutput struct {
 	PermissionDecision       string `json:"permissionDecision,omitempty"`
 	PermissionDecisionReason string `json:"permissionDecisionReason,omitempty"`
 	AdditionalContext        string `json:"additionalContext,omitempty"`
+	// UpdatedInput replaces the tool input of a PreToolUse event.
+	UpdatedInput json.RawMessage `json:"updatedInput,omitempty"`
 }
 
 // HookOutput represents the JSON payload written to stdout for Claude Code.
@@ -202,6 +204,14 @@ type HookOutput struct {
 	// UpdatedInput is used by UserPromptSubmit to modify the user's prompt.
 	UpdatedInput string `json:"updatedInput,omitempty"`
 
+	// RuleID names the policy rule behind a deny or ask decision. Not
+	// serialized to JSON; it is recorded in the hook audit log.
+	RuleID string `json:"-"`
+
+	// Rewrites names the rules that rewrote the tool input. Not serialized
+	// to JSON; it is recorded in the hook audit log.
+	Rewrites []string `json:"-"`
+
 	// ExitCode allows handlers to signal a specific process exit code.
 	// Not serialized to JSON. Used for exit code 2 protocol (TeammateIdle, TaskCompleted).
 	ExitCode int `json:"-"`
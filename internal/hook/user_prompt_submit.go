@@ -2,18 +2,37 @@ package hook
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"strings"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+	"github.com/modu-ai/moai-adk/internal/contextsearch"
 )
 
+// contextSearchMinMatch is the number of prompt terms an exchange must
+// share to be injected, so single common words do not pull in noise.
+const contextSearchMinMatch = 2
+
 // userPromptSubmitHandler processes UserPromptSubmit events.
-// It logs user prompt submissions for auditing.
-type userPromptSubmitHandler struct{}
+// It logs user prompt submissions for auditing and, in a MoAI project,
+// injects related exchanges from previous sessions as additionalContext.
+type userPromptSubmitHandler struct {
+	cfg ConfigProvider
+}
 
 // NewUserPromptSubmitHandler creates a new UserPromptSubmit event handler.
 func NewUserPromptSubmitHandler() Handler {
 	return &userPromptSubmitHandler{}
 }
 
+// NewUserPromptSubmitHandlerWithConfig creates a UserPromptSubmit handler
+// that searches previous sessions according to the context_search settings
+// of cfg.
+func NewUserPromptSubmitHandlerWithConfig(cfg ConfigProvider) Handler {
+	return &userPromptSubmitHandler{cfg: cfg}
+}
+
 // EventType returns EventUserPromptSubmit.
 func (h *userPromptSubmitHandler) EventType() EventType {
 	return EventUserPromptSubmit
@@ -30,5 +49,87 @@ func (h *userPromptSubmitHandler) Handle(ctx context.Context, input *HookInput)
 		"session_id", input.SessionID,
 		"prompt_preview", prompt,
 	)
-	return &HookOutput{}, nil
+
+	related := h.relatedContext(ctx, input)
+	if related == "" {
+		return &HookOutput{}, nil
+	}
+	return &HookOutput{
+		HookSpecificOutput: &HookSpecificOutput{
+			HookEventName:     string(EventUserPromptSubmit),
+			AdditionalContext: related,
+		},
+	}, nil
+}
+
+// relatedContext searches the previous sessions of the project for the
+// prompt and renders the best exchanges within the token budget. Nothing
+// is injected when the search is disabled, outside a MoAI project, or once
+// the session context is above the configured usage.
+func (h *userPromptSubmitHandler) relatedContext(ctx context.Context, input *HookInput) string {
+	if h.cfg == nil || strings.TrimSpace(input.Prompt) == "" {
+		return ""
+	}
+	c := h.cfg.Get()
+	if c == nil || !c.ContextSearch.Enabled || !c.ContextSearch.AutoDetect.Enabled {
+		return ""
+	}
+	root := resolveProjectRoot(input)
+	if root == "" {
+		return ""
+	}
+	settings := c.ContextSearch
+	if limit := settings.TokenBudget.SkipIfUsageAbove; limit > 0 && input.TranscriptPath != "" {
+		if used, err := contextsearch.SessionContextTokens(input.TranscriptPath); err == nil && used > int64(limit) {
+			slog.Debug("context search skipped: session context above limit", "tokens", used, "limit", limit)
+			return ""
+		}
+	}
+
+	searcher := contextsearch.NewSearcher(root, settings)
+	if isReplay(ctx) {
+		// A fixture replay searches the stored index without rewriting it.
+		searcher.Open()
+	}
+	results, err := searcher.Search(ctx, contextsearch.Query{
+		Text:           input.Prompt,
+		ExcludeSession: input.SessionID,
+		MinMatch:       contextSearchMinMatch,
+	})
+	if err != nil {
+		slog.Warn("context search failed", "error", err.Error())
+		return ""
+	}
+	return renderRelatedExchanges(results, settings)
+}
+
+// renderRelatedExchanges presents search results to Claude, each cut to
+// the per-result budget and all of them to the injection budget.
+func renderRelatedExchanges(results []contextsearch.Result, settings config.ContextSearchConfig) string {
+	if len(results) == 0 {
+		return ""
+	}
+	perResult := settings.Search.MaxTokensPerResult * charsPerToken
+
+	var b strings.Builder
+	b.WriteString("Related exchanges from previous sessions in this project (context search). Use them only if relevant:")
+	for _, r := range results {
+		entry := fmt.Sprintf("Prompt: %s", r.Prompt)
+		if r.Response != "" {
+			entry += "\nResponse: " + r.Response
+		}
+		if perResult > 0 && len(entry) > perResult {
+			entry = strings.ToValidUTF8(entry[:perResult], "") + "…"
+		}
+		fmt.Fprintf(&b, "\n\n[%s, session %s]\n%s", r.Time.Local().Format("2006-01-02 15:04"), shortSessionID(r.SessionID), entry)
+	}
+	return fitTokenBudget(b.String(), settings.TokenBudget.MaxInjectionTokens)
+}
+
+// shortSessionID abbreviates a session ID for display.
+func shortSessionID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
 }
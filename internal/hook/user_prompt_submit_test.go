@@ -2,8 +2,14 @@ package hook
 
 import (
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/contextsearch"
 )
 
 func TestUserPromptSubmitHandler_EventType(t *testing.T) {
@@ -69,3 +75,96 @@ func TestUserPromptSubmitHandler_Handle(t *testing.T) {
 		})
 	}
 }
+
+// writePastSession writes a settled transcript of a previous session run in
+// root and points the context search at it.
+func writePastSession(t *testing.T, root string) {
+	t.Helper()
+	lines := []map[string]any{
+		{"type": "user", "sessionId": "past-session-1", "cwd": root, "timestamp": "2026-10-01T10:00:00Z",
+			"message": map[string]any{"content": "Why does the token refresh fail after rotation?"}},
+		{"type": "assistant", "sessionId": "past-session-1", "cwd": root,
+			"message": map[string]any{"content": []map[string]any{{"type": "text", "text": "The old refresh token was reused; refresh.go now stores the rotated token first."}}}},
+	}
+	var b strings.Builder
+	for _, l := range lines {
+		data, _ := json.Marshal(l)
+		b.Write(data)
+		b.WriteString("\n")
+	}
+	path := filepath.Join(t.TempDir(), "past.jsonl")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	orig := contextsearch.FindTranscripts
+	t.Cleanup(func() { contextsearch.FindTranscripts = orig })
+	contextsearch.FindTranscripts = func() ([]string, error) { return []string{path}, nil }
+}
+
+func TestUserPromptSubmitHandler_InjectsRelatedExchanges(t *testing.T) {
+	root := newMoAIProject(t)
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	writePastSession(t, root)
+
+	cfg := newTestConfig()
+	cfg.ContextSearch.Search.DateRangeDays = 0
+	h := NewUserPromptSubmitHandlerWithConfig(&mockConfigProvider{cfg: cfg})
+
+	input := &HookInput{SessionID: "current", CWD: root, Prompt: "token refresh is failing again"}
+	out, err := h.Handle(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	if out.HookSpecificOutput == nil || out.HookSpecificOutput.HookEventName != "UserPromptSubmit" {
+		t.Fatalf("hookSpecificOutput = %+v", out.HookSpecificOutput)
+	}
+	got := out.HookSpecificOutput.AdditionalContext
+	for _, want := range []string{"Related exchanges from previous sessions", "session past-ses", "Prompt: Why does the token refresh fail after rotation?", "Response: The old refresh token was reused"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("context missing %q:\n%s", want, got)
+		}
+	}
+
+	// A prompt sharing a single term is not enough.
+	input.Prompt = "rename the token"
+	if out, _ := h.Handle(context.Background(), input); out.HookSpecificOutput != nil {
+		t.Errorf("weak match injected: %q", out.HookSpecificOutput.AdditionalContext)
+	}
+
+	// Auto-detection off leaves the prompt alone.
+	cfg.ContextSearch.AutoDetect.Enabled = false
+	input.Prompt = "token refresh is failing again"
+	if out, _ := h.Handle(context.Background(), input); out.HookSpecificOutput != nil {
+		t.Error("auto_detect disabled but context was injected")
+	}
+}
+
+func TestUserPromptSubmitHandler_SkipsAboveUsage(t *testing.T) {
+	root := newMoAIProject(t)
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	writePastSession(t, root)
+
+	transcript := filepath.Join(t.TempDir(), "current.jsonl")
+	usage := `{"type":"assistant","message":{"usage":{"input_tokens":10,"cache_read_input_tokens":160000}}}` + "\n"
+	if err := os.WriteFile(transcript, []byte(usage), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := newTestConfig()
+	cfg.ContextSearch.Search.DateRangeDays = 0
+	h := NewUserPromptSubmitHandlerWithConfig(&mockConfigProvider{cfg: cfg})
+	out, err := h.Handle(context.Background(), &HookInput{
+		SessionID: "current", CWD: root, TranscriptPath: transcript, Prompt: "token refresh is failing again",
+	})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+	if out.HookSpecificOutput != nil {
+		t.Errorf("context injected above skip_if_usage_above: %q", out.HookSpecificOutput.AdditionalContext)
+	}
+}
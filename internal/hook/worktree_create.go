@@ -2,26 +2,37 @@ package hook
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
 )
 
 // worktreeCreateHandler processes WorktreeCreate events.
 // Fired when Claude Code creates an isolated git worktree for an agent
 // with isolation: worktree in its frontmatter (v2.1.49+).
-type worktreeCreateHandler struct{}
+type worktreeCreateHandler struct {
+	cfg ConfigProvider
+}
 
 // NewWorktreeCreateHandler creates a new WorktreeCreate event handler.
 func NewWorktreeCreateHandler() Handler {
 	return &worktreeCreateHandler{}
 }
 
+// NewWorktreeCreateHandlerWithConfig creates a WorktreeCreate handler that
+// bootstraps new worktrees according to the worktree policy of cfg.
+func NewWorktreeCreateHandlerWithConfig(cfg ConfigProvider) Handler {
+	return &worktreeCreateHandler{cfg: cfg}
+}
+
 // EventType returns EventWorktreeCreate.
 func (h *worktreeCreateHandler) EventType() EventType {
 	return EventWorktreeCreate
 }
 
-// Handle processes a WorktreeCreate event. It logs the worktree creation details
-// for session tracking and debugging.
+// Handle processes a WorktreeCreate event. It logs the worktree creation
+// details and, when the bootstrap policy is enabled, copies and links files
+// from the main checkout and runs the setup commands in the new worktree.
+// The report is returned in the Data field; failures are non-blocking.
 func (h *worktreeCreateHandler) Handle(ctx context.Context, input *HookInput) (*HookOutput, error) {
 	slog.Info("worktree created for isolated agent",
 		"session_id", input.SessionID,
@@ -30,5 +41,28 @@ func (h *worktreeCreateHandler) Handle(ctx context.Context, input *HookInput) (*
 		"worktree_path", input.WorktreePath,
 		"worktree_branch", input.WorktreeBranch,
 	)
-	return &HookOutput{}, nil
+
+	// A fixture replay leaves the worktree untouched.
+	policy := worktreeSettings(h.cfg).Bootstrap
+	if !policy.Enabled || isReplay(ctx) || !isWorktreeDir(input.WorktreePath) {
+		return &HookOutput{}, nil
+	}
+	main := mainCheckout(ctx, input)
+	if main == "" {
+		return &HookOutput{}, nil
+	}
+
+	report := bootstrapWorktree(ctx, main, input.WorktreePath, policy)
+	for _, s := range report.Skipped {
+		slog.Debug("worktree bootstrap skipped a path", "worktree_path", input.WorktreePath, "detail", s)
+	}
+	if n := report.failedSetups(); n > 0 {
+		slog.Warn("worktree setup commands failed", "worktree_path", input.WorktreePath, "failed", n)
+	}
+
+	out := &HookOutput{SystemMessage: report.summary()}
+	if data, err := json.Marshal(report); err == nil {
+		out.Data = data
+	}
+	return out, nil
 }
@@ -0,0 +1,434 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+)
+
+const (
+	// setupOutputLines is how many trailing output lines of a failed setup
+	// command are reported.
+	setupOutputLines = 5
+
+	// setupDeadlineMargin is left of the hook deadline when a setup command
+	// runs, so a slow command is cut short instead of timing out the whole
+	// WorktreeCreate hook.
+	setupDeadlineMargin = 3 * time.Second
+)
+
+// worktreeSettings returns the worktree policy of cfg, or the defaults
+// when no configuration is available.
+func worktreeSettings(cfg ConfigProvider) config.WorktreeConfig {
+	if cfg != nil {
+		if c := cfg.Get(); c != nil {
+			return c.Worktree
+		}
+	}
+	return config.NewDefaultWorktreeConfig()
+}
+
+// mainCheckout finds the main checkout a worktree belongs to. The project
+// root of the hook input is preferred; otherwise the parent of the
+// repository's common git directory is used.
+func mainCheckout(ctx context.Context, input *HookInput) string {
+	if root := resolveProjectRoot(input); root != "" && root != input.WorktreePath {
+		return root
+	}
+	common, err := gitOutput(ctx, input.WorktreePath, nil, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil || filepath.Base(common) != ".git" {
+		return ""
+	}
+	return filepath.Dir(common)
+}
+
+// setupResult is the outcome of one bootstrap setup command.
+type setupResult struct {
+	Command  string `json:"command"`
+	Duration string `json:"duration,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Skipped  bool   `json:"skipped,omitempty"`
+}
+
+// bootstrapReport lists what was prepared in a new worktree.
+type bootstrapReport struct {
+	Copied  []string      `json:"copied,omitempty"`
+	Linked  []string      `json:"linked,omitempty"`
+	Skipped []string      `json:"skipped,omitempty"`
+	Setup   []setupResult `json:"setup,omitempty"`
+}
+
+// failedSetups counts the setup commands that did not succeed, including
+// those skipped for lack of time.
+func (r *bootstrapReport) failedSetups() int {
+	n := 0
+	for _, s := range r.Setup {
+		if s.Error != "" || s.Skipped {
+			n++
+		}
+	}
+	return n
+}
+
+// summary describes the report in one line for the user.
+func (r *bootstrapReport) summary() string {
+	if len(r.Copied)+len(r.Linked)+len(r.Setup) == 0 {
+		return ""
+	}
+	msg := fmt.Sprintf("Worktree bootstrap: %d copied, %d linked", len(r.Copied), len(r.Linked))
+	if len(r.Setup) > 0 {
+		msg += fmt.Sprintf(", %d/%d setup command(s) succeeded", len(r.Setup)-r.failedSetups(), len(r.Setup))
+	}
+	for _, s := range r.Setup {
+		switch {
+		case s.Error != "":
+			msg += fmt.Sprintf("\n  %s: %s", s.Command, s.Error)
+		case s.Skipped:
+			msg += fmt.Sprintf("\n  %s: skipped, no time left before the hook deadline", s.Command)
+		}
+	}
+	return msg
+}
+
+// bootstrapWorktree copies and links files from the main checkout into the
+// worktree, then runs the setup commands in it. Individual failures are
+// recorded in the report and do not stop the remaining steps.
+func bootstrapWorktree(ctx context.Context, main, worktree string, policy config.WorktreeBootstrap) *bootstrapReport {
+	r := &bootstrapReport{}
+	for _, rel := range expandWorktreeGlobs(main, policy.Copy, r) {
+		if skip := destinationTaken(worktree, rel); skip != "" {
+			r.Skipped = append(r.Skipped, rel+": "+skip)
+			continue
+		}
+		if err := copyPath(filepath.Join(main, rel), filepath.Join(worktree, rel)); err != nil {
+			r.Skipped = append(r.Skipped, rel+": "+err.Error())
+			continue
+		}
+		r.Copied = append(r.Copied, rel)
+	}
+	for _, rel := range expandWorktreeGlobs(main, policy.Symlink, r) {
+		linkPath(main, worktree, rel, r)
+	}
+	for _, rel := range expandWorktreeGlobs(main, policy.SharedDependencies, r) {
+		if info, err := os.Stat(filepath.Join(main, rel)); err != nil || !info.IsDir() {
+			r.Skipped = append(r.Skipped, rel+": not a directory")
+			continue
+		}
+		// check-ignore exits non-zero for paths git would track; the trailing
+		// slash matches directory-only patterns such as "node_modules/".
+		if _, err := gitOutput(ctx, worktree, nil, "check-ignore", "-q", filepath.ToSlash(rel)+"/"); err != nil {
+			r.Skipped = append(r.Skipped, rel+": not ignored by git")
+			continue
+		}
+		linkPath(main, worktree, rel, r)
+	}
+	for _, c := range policy.Setup {
+		r.Setup = append(r.Setup, runSetupCommand(ctx, worktree, c))
+	}
+	return r
+}
+
+// expandWorktreeGlobs resolves patterns against the main checkout and
+// returns the matching paths relative to it. Patterns leaving the checkout
+// are rejected; patterns without matches are ignored.
+func expandWorktreeGlobs(main string, patterns []string, r *bootstrapReport) []string {
+	var rels []string
+	for _, pattern := range patterns {
+		if !filepath.IsLocal(filepath.FromSlash(pattern)) {
+			r.Skipped = append(r.Skipped, pattern+": outside the project")
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(main, filepath.FromSlash(pattern)))
+		if err != nil {
+			r.Skipped = append(r.Skipped, pattern+": "+err.Error())
+			continue
+		}
+		for _, m := range matches {
+			rel, err := filepath.Rel(main, m)
+			if err == nil && !slices.Contains(rels, rel) {
+				rels = append(rels, rel)
+			}
+		}
+	}
+	return rels
+}
+
+// destinationTaken explains why rel cannot be placed in the worktree, or
+// returns "" when the path is free.
+func destinationTaken(worktree, rel string) string {
+	if _, err := os.Lstat(filepath.Join(worktree, rel)); err == nil {
+		return "already present"
+	}
+	return ""
+}
+
+// linkPath symlinks rel in the worktree to the same path in the main
+// checkout.
+func linkPath(main, worktree, rel string, r *bootstrapReport) {
+	if skip := destinationTaken(worktree, rel); skip != "" {
+		r.Skipped = append(r.Skipped, rel+": "+skip)
+		return
+	}
+	dst := filepath.Join(worktree, rel)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		r.Skipped = append(r.Skipped, rel+": "+err.Error())
+		return
+	}
+	if err := os.Symlink(filepath.Join(main, rel), dst); err != nil {
+		r.Skipped = append(r.Skipped, rel+": "+err.Error())
+		return
+	}
+	r.Linked = append(r.Linked, rel)
+}
+
+// copyPath copies a file or directory tree, keeping file modes.
+func copyPath(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return os.CopyFS(dst, os.DirFS(src))
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, info.Mode().Perm())
+}
+
+// runSetupCommand runs one setup command in the worktree within its
+// timeout, capped to what is left of the hook deadline. A command with no
+// time left is reported as skipped.
+func runSetupCommand(ctx context.Context, worktree string, c config.WorktreeCommand) setupResult {
+	res := setupResult{Command: strings.TrimSpace(c.Command + " " + strings.Join(c.Args, " "))}
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultWorktreeSetupTimeout
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = min(timeout, time.Until(deadline)-setupDeadlineMargin)
+	}
+	if timeout <= 0 {
+		res.Skipped = true
+		return res
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, c.Command, c.Args...)
+	cmd.Dir = worktree
+	out, err := cmd.CombinedOutput()
+	res.Duration = time.Since(start).Round(time.Millisecond).String()
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		res.Error = "timed out after " + timeout.Round(time.Millisecond).String()
+	case err != nil:
+		res.Error = err.Error()
+		if tail := lastLines(string(out), setupOutputLines); tail != "" {
+			res.Error += ": " + tail
+		}
+	}
+	return res
+}
+
+// lastLines returns the last n non-empty lines of text joined by " | ".
+func lastLines(text string, n int) string {
+	var lines []string
+	for l := range strings.Lines(text) {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, " | ")
+}
+
+// worktreeArchive records what was salvaged from a removed worktree. It is
+// written next to the patch as <name>-<time>.json.
+type worktreeArchive struct {
+	Worktree  string    `json:"worktree"`
+	Branch    string    `json:"branch,omitempty"`
+	Head      string    `json:"head"`
+	SessionID string    `json:"session_id,omitempty"`
+	AgentName string    `json:"agent_name,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Patch holds the uncommitted changes, including untracked files.
+	Patch        string   `json:"patch,omitempty"`
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	// ArchiveBranch points at the commits not reachable from any other
+	// branch or remote.
+	ArchiveBranch   string `json:"archive_branch,omitempty"`
+	UnpushedCommits int    `json:"unpushed_commits,omitempty"`
+
+	// Report is the path of this record; it is not serialized.
+	Report string `json:"-"`
+}
+
+// summary describes what was salvaged in one line for the user.
+func (a *worktreeArchive) summary(main string) string {
+	var parts []string
+	if a.Patch != "" {
+		parts = append(parts, fmt.Sprintf("%d uncommitted file(s) saved to %s", len(a.ChangedFiles), relToRoot(main, a.Patch)))
+	}
+	if a.ArchiveBranch != "" {
+		parts = append(parts, fmt.Sprintf("%d unpushed commit(s) kept on branch %s", a.UnpushedCommits, a.ArchiveBranch))
+	}
+	return fmt.Sprintf("Worktree %s archived: %s", filepath.Base(a.Worktree), strings.Join(parts, "; "))
+}
+
+// relToRoot shortens path to be relative to root when it lies inside it.
+func relToRoot(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && filepath.IsLocal(rel) {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// archiveWorktree preserves the work of a worktree that is about to be
+// removed: uncommitted changes are written as a binary patch and unpushed
+// commits are kept on an archive branch. It returns nil when there was
+// nothing to salvage.
+func archiveWorktree(ctx context.Context, main string, input *HookInput, dir string, now time.Time) (*worktreeArchive, error) {
+	wt := input.WorktreePath
+	head, err := gitOutput(ctx, wt, nil, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("read worktree HEAD: %w", err)
+	}
+	branch := input.WorktreeBranch
+	if branch == "" {
+		if b, err := gitOutput(ctx, wt, nil, "symbolic-ref", "--short", "-q", "HEAD"); err == nil {
+			branch = b
+		}
+	}
+
+	name := safeFileName(filepath.Base(wt)) + "-" + now.UTC().Format("20060102-150405")
+	a := &worktreeArchive{
+		Worktree:  wt,
+		Branch:    branch,
+		Head:      head,
+		SessionID: input.SessionID,
+		AgentName: input.AgentName,
+		CreatedAt: now.UTC(),
+	}
+
+	patch, files, err := uncommittedPatch(ctx, wt)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) > 0 {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create archive directory: %w", err)
+		}
+		a.Patch = filepath.Join(dir, name+".patch")
+		a.ChangedFiles = files
+		if err := os.WriteFile(a.Patch, patch, 0o644); err != nil {
+			return nil, fmt.Errorf("write patch: %w", err)
+		}
+	}
+
+	if n := unpushedCommits(ctx, wt, branch); n > 0 {
+		archiveBranch := "archive/" + name
+		if _, err := gitOutput(ctx, wt, nil, "branch", archiveBranch, head); err != nil {
+			return nil, fmt.Errorf("create archive branch: %w", err)
+		}
+		a.ArchiveBranch = archiveBranch
+		a.UnpushedCommits = n
+	}
+
+	if a.Patch == "" && a.ArchiveBranch == "" {
+		return nil, nil
+	}
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal archive report: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create archive directory: %w", err)
+	}
+	a.Report = filepath.Join(dir, name+".json")
+	if err := os.WriteFile(a.Report, data, 0o644); err != nil {
+		return nil, fmt.Errorf("write archive report: %w", err)
+	}
+	return a, nil
+}
+
+// uncommittedPatch returns the worktree's changes against HEAD, untracked
+// files included, and the changed paths. A temporary index is used so the
+// worktree's own index is left untouched.
+func uncommittedPatch(ctx context.Context, wt string) ([]byte, []string, error) {
+	status, err := gitOutput(ctx, wt, nil, "status", "--porcelain", "--untracked-files=all")
+	if err != nil {
+		return nil, nil, fmt.Errorf("read worktree status: %w", err)
+	}
+	if status == "" {
+		return nil, nil, nil
+	}
+
+	tmp, err := os.MkdirTemp("", "moai-worktree-index-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("create temporary index: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(tmp, "index")}
+
+	if _, err := gitOutput(ctx, wt, env, "read-tree", "HEAD"); err != nil {
+		return nil, nil, fmt.Errorf("stage worktree changes: %w", err)
+	}
+	if _, err := gitOutput(ctx, wt, env, "add", "-A"); err != nil {
+		return nil, nil, fmt.Errorf("stage worktree changes: %w", err)
+	}
+	names, err := gitOutput(ctx, wt, env, "diff", "--cached", "--name-only", "HEAD")
+	if err != nil {
+		return nil, nil, fmt.Errorf("list worktree changes: %w", err)
+	}
+	if names == "" {
+		return nil, nil, nil
+	}
+	// The patch is kept untrimmed: a hunk may end on a blank context line.
+	patch, err := gitRawOutput(ctx, wt, env, "diff", "--cached", "--binary", "HEAD")
+	if err != nil {
+		return nil, nil, fmt.Errorf("write worktree diff: %w", err)
+	}
+	return patch, strings.Split(names, "\n"), nil
+}
+
+// unpushedCommits counts the commits of HEAD that no other local branch
+// and no remote-tracking branch contains. The worktree's own branch is
+// left out because it usually goes away with the worktree.
+func unpushedCommits(ctx context.Context, wt, branch string) int {
+	args := []string{"rev-list", "--count", "HEAD", "--not"}
+	if branch != "" {
+		// --exclude patterns for --branches omit the refs/heads/ prefix.
+		args = append(args, "--exclude="+branch)
+	}
+	args = append(args, "--branches", "--remotes")
+	out, err := gitOutput(ctx, wt, nil, args...)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(out)
+	return n
+}
+
+// isWorktreeDir reports whether path is an existing directory.
+func isWorktreeDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,262 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/config"
+)
+
+// gitIn runs git in dir and fails the test on error.
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@t"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// writeProjectFile writes content to root/rel, creating parent directories.
+func writeProjectFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newWorktreeProject creates a MoAI project repository with local files
+// that are not committed and an agent worktree next to it.
+func newWorktreeProject(t *testing.T) (main, wt string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	main, _ = filepath.EvalSymlinks(t.TempDir())
+	gitIn(t, main, "init", "-q", "-b", "main")
+	writeProjectFile(t, main, ".moai/config/config.yaml", "moai: {}\n")
+	writeProjectFile(t, main, ".gitignore", ".env\n.env.*\nnode_modules/\n.claude/worktrees/\n.moai/memory/\n")
+	writeProjectFile(t, main, "README.md", "hello\n")
+	gitIn(t, main, "add", ".")
+	gitIn(t, main, "commit", "-q", "-m", "initial")
+
+	writeProjectFile(t, main, ".env", "TOKEN=secret\n")
+	writeProjectFile(t, main, ".env.local", "DEBUG=1\n")
+	writeProjectFile(t, main, ".moai/memory/diagnostics-baseline.json", "{}\n")
+	writeProjectFile(t, main, "node_modules/left-pad/index.js", "module.exports = 1\n")
+	writeProjectFile(t, main, "vendor/lib.go", "package lib\n")
+
+	wt = filepath.Join(main, ".claude", "worktrees", "agent-a")
+	gitIn(t, main, "worktree", "add", "-q", "-b", "agent/a", wt)
+	return main, wt
+}
+
+func TestWorktreeCreateHandler_Bootstrap(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	main, wt := newWorktreeProject(t)
+
+	cfg := newTestConfig()
+	cfg.Worktree.Bootstrap.SharedDependencies = []string{"node_modules", "vendor"}
+	cfg.Worktree.Bootstrap.Setup = []config.WorktreeCommand{
+		{Command: "git", Args: []string{"status", "--short"}},
+		{Command: "git", Args: []string{"no-such-command"}, Timeout: 10 * time.Second},
+	}
+	h := NewWorktreeCreateHandlerWithConfig(&mockConfigProvider{cfg: cfg})
+
+	out, err := h.Handle(context.Background(), &HookInput{SessionID: "s1", CWD: main, WorktreePath: wt, WorktreeBranch: "agent/a"})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+
+	for _, rel := range []string{".env", ".env.local", ".moai/memory/diagnostics-baseline.json"} {
+		info, err := os.Lstat(filepath.Join(wt, rel))
+		if err != nil || !info.Mode().IsRegular() {
+			t.Errorf("%s not copied: %v", rel, err)
+		}
+	}
+	if target, err := os.Readlink(filepath.Join(wt, "node_modules")); err != nil || target != filepath.Join(main, "node_modules") {
+		t.Errorf("node_modules link = %q, %v", target, err)
+	}
+	if _, err := os.Lstat(filepath.Join(wt, "vendor")); err == nil {
+		t.Error("vendor is tracked by git and must not be linked")
+	}
+
+	var report bootstrapReport
+	if err := json.Unmarshal(out.Data, &report); err != nil {
+		t.Fatalf("unmarshal report: %v", err)
+	}
+	if !slices.Contains(report.Skipped, "vendor: not ignored by git") {
+		t.Errorf("skipped = %v", report.Skipped)
+	}
+	if len(report.Setup) != 2 || report.Setup[0].Error != "" || report.Setup[1].Error == "" {
+		t.Errorf("setup = %+v", report.Setup)
+	}
+	if !strings.Contains(out.SystemMessage, "3 copied, 1 linked, 1/2 setup command(s) succeeded") {
+		t.Errorf("SystemMessage = %q", out.SystemMessage)
+	}
+}
+
+func TestWorktreeCreateHandler_BootstrapDisabled(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	main, wt := newWorktreeProject(t)
+
+	cfg := newTestConfig()
+	cfg.Worktree.Bootstrap.Enabled = false
+	h := NewWorktreeCreateHandlerWithConfig(&mockConfigProvider{cfg: cfg})
+	out, err := h.Handle(context.Background(), &HookInput{CWD: main, WorktreePath: wt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.SystemMessage != "" || out.Data != nil {
+		t.Errorf("disabled bootstrap produced output: %+v", out)
+	}
+	if _, err := os.Lstat(filepath.Join(wt, ".env")); err == nil {
+		t.Error(".env copied although bootstrap is disabled")
+	}
+}
+
+func TestRunSetupCommand_CappedToHookDeadline(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), setupDeadlineMargin+300*time.Millisecond)
+	defer cancel()
+
+	install := config.WorktreeCommand{Command: "sleep", Args: []string{"5"}, Timeout: 5 * time.Minute}
+	start := time.Now()
+	res := runSetupCommand(ctx, t.TempDir(), install)
+	if !strings.HasPrefix(res.Error, "timed out after") || time.Since(start) > 2*time.Second {
+		t.Errorf("first command = %+v after %s, want cut short before the hook deadline", res, time.Since(start))
+	}
+	if ctx.Err() != nil {
+		t.Error("setup command ran into the hook deadline")
+	}
+
+	if res := runSetupCommand(ctx, t.TempDir(), install); !res.Skipped || res.Error != "" {
+		t.Errorf("second command = %+v, want skipped", res)
+	}
+	r := &bootstrapReport{Setup: []setupResult{{Command: "sleep 5", Skipped: true}}}
+	if !strings.Contains(r.summary(), "0/1 setup command(s) succeeded") || !strings.Contains(r.summary(), "skipped") {
+		t.Errorf("summary = %q", r.summary())
+	}
+}
+
+func TestWorktreeRemoveHandler_ArchivesUnsavedWork(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	main, wt := newWorktreeProject(t)
+
+	writeProjectFile(t, wt, "feature.go", "package feature\n")
+	gitIn(t, wt, "add", "feature.go")
+	gitIn(t, wt, "commit", "-q", "-m", "add feature")
+	writeProjectFile(t, wt, "README.md", "hello\nworld\n")
+	writeProjectFile(t, wt, "notes/todo.txt", "finish tests\n")
+	gitIn(t, wt, "add", "README.md")
+
+	h := &worktreeRemoveHandler{
+		cfg: &mockConfigProvider{cfg: newTestConfig()},
+		now: func() time.Time { return time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC) },
+	}
+	// Without a project directory in the input the main checkout is found
+	// through git.
+	out, err := h.Handle(context.Background(), &HookInput{SessionID: "s1", WorktreePath: wt, WorktreeBranch: "agent/a"})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+
+	var archive worktreeArchive
+	if err := json.Unmarshal(out.Data, &archive); err != nil {
+		t.Fatalf("unmarshal archive: %v", err)
+	}
+	wantPatch := filepath.Join(main, ".moai", "worktrees", "archive", "agent-a-20261018-093000.patch")
+	if archive.Patch != wantPatch {
+		t.Errorf("patch = %q, want %q", archive.Patch, wantPatch)
+	}
+	if !slices.Equal(archive.ChangedFiles, []string{"README.md", "notes/todo.txt"}) {
+		t.Errorf("changed files = %v", archive.ChangedFiles)
+	}
+	if archive.ArchiveBranch != "archive/agent-a-20261018-093000" || archive.UnpushedCommits != 1 {
+		t.Errorf("archive branch = %q with %d commit(s)", archive.ArchiveBranch, archive.UnpushedCommits)
+	}
+	if got := gitIn(t, main, "log", "-1", "--format=%s", archive.ArchiveBranch); got != "add feature" {
+		t.Errorf("archive branch head = %q", got)
+	}
+	if _, err := os.Stat(strings.TrimSuffix(wantPatch, ".patch") + ".json"); err != nil {
+		t.Errorf("report not written: %v", err)
+	}
+	want := "Worktree agent-a archived: 2 uncommitted file(s) saved to .moai/worktrees/archive/agent-a-20261018-093000.patch; 1 unpushed commit(s) kept on branch archive/agent-a-20261018-093000"
+	if out.SystemMessage != want {
+		t.Errorf("SystemMessage = %q, want %q", out.SystemMessage, want)
+	}
+
+	// The worktree's own index is untouched and the patch restores the work.
+	if got := gitIn(t, wt, "diff", "--cached", "--name-only"); got != "README.md" {
+		t.Errorf("worktree index changed: staged %q", got)
+	}
+	gitIn(t, wt, "reset", "-q", "--hard")
+	gitIn(t, wt, "clean", "-q", "-fd")
+	gitIn(t, wt, "apply", wantPatch)
+	if data, err := os.ReadFile(filepath.Join(wt, "notes", "todo.txt")); err != nil || string(data) != "finish tests\n" {
+		t.Errorf("patch did not restore the untracked file: %q, %v", data, err)
+	}
+}
+
+func TestWorktreeRemoveHandler_NothingToSalvage(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	main, wt := newWorktreeProject(t)
+
+	h := NewWorktreeRemoveHandlerWithConfig(&mockConfigProvider{cfg: newTestConfig()})
+	out, err := h.Handle(context.Background(), &HookInput{CWD: main, WorktreePath: wt, WorktreeBranch: "agent/a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.SystemMessage != "" || out.Data != nil {
+		t.Errorf("clean worktree produced output: %+v", out)
+	}
+	if _, err := os.Stat(filepath.Join(main, ".moai", "worktrees", "archive")); err == nil {
+		t.Error("archive directory created for a clean worktree")
+	}
+}
+
+func TestWorktreeRemoveHandler_PatchEndingOnBlankLine(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", "")
+	main, wt := newWorktreeProject(t)
+
+	// The last hunk of the patch ends on a blank context line, which a
+	// trimmed patch loses.
+	writeProjectFile(t, wt, "zz.txt", "one\ntwo\nthree\n\n")
+	gitIn(t, wt, "add", "zz.txt")
+	gitIn(t, wt, "commit", "-q", "-m", "add zz")
+	gitIn(t, wt, "branch", "-q", "keep", "HEAD")
+	writeProjectFile(t, wt, "zz.txt", "ONE\ntwo\nthree\n\n")
+
+	h := NewWorktreeRemoveHandlerWithConfig(&mockConfigProvider{cfg: newTestConfig()})
+	out, err := h.Handle(context.Background(), &HookInput{CWD: main, WorktreePath: wt, WorktreeBranch: "agent/a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var archive worktreeArchive
+	if err := json.Unmarshal(out.Data, &archive); err != nil {
+		t.Fatalf("unmarshal archive: %v", err)
+	}
+
+	clean := filepath.Join(t.TempDir(), "clean")
+	gitIn(t, main, "worktree", "add", "-q", "--detach", clean, "keep")
+	gitIn(t, clean, "apply", "--check", archive.Patch)
+	gitIn(t, clean, "apply", archive.Patch)
+	if data, err := os.ReadFile(filepath.Join(clean, "zz.txt")); err != nil || string(data) != "ONE\ntwo\nthree\n\n" {
+		t.Errorf("patch restored %q, %v", data, err)
+	}
+}
@@ -2,17 +2,29 @@ package hook
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
+	"path/filepath"
+	"time"
 )
 
 // worktreeRemoveHandler processes WorktreeRemove events.
 // Fired when Claude Code removes an isolated git worktree after an agent
 // with isolation: worktree terminates (v2.1.49+).
-type worktreeRemoveHandler struct{}
+type worktreeRemoveHandler struct {
+	cfg ConfigProvider
+	now func() time.Time
+}
 
 // NewWorktreeRemoveHandler creates a new WorktreeRemove event handler.
 func NewWorktreeRemoveHandler() Handler {
-	return &worktreeRemoveHandler{}
+	return &worktreeRemoveHandler{now: time.Now}
+}
+
+// NewWorktreeRemoveHandlerWithConfig creates a WorktreeRemove handler that
+// archives unsaved work according to the worktree policy of cfg.
+func NewWorktreeRemoveHandlerWithConfig(cfg ConfigProvider) Handler {
+	return &worktreeRemoveHandler{cfg: cfg, now: time.Now}
 }
 
 // EventType returns EventWorktreeRemove.
@@ -20,8 +32,11 @@ func (h *worktreeRemoveHandler) EventType() EventType {
 	return EventWorktreeRemove
 }
 
-// Handle processes a WorktreeRemove event. It logs the worktree removal details
-// for session tracking and cleanup verification.
+// Handle processes a WorktreeRemove event. It logs the worktree removal
+// details and, when archiving is enabled, saves uncommitted changes as a
+// patch and unpushed commits on a branch under the archive directory of
+// the main checkout, reporting what was salvaged. Failures are
+// non-blocking.
 func (h *worktreeRemoveHandler) Handle(ctx context.Context, input *HookInput) (*HookOutput, error) {
 	slog.Info("worktree removed after isolated agent termination",
 		"session_id", input.SessionID,
@@ -30,5 +45,36 @@ func (h *worktreeRemoveHandler) Handle(ctx context.Context, input *HookInput) (*
 		"worktree_path", input.WorktreePath,
 		"worktree_branch", input.WorktreeBranch,
 	)
-	return &HookOutput{}, nil
+
+	// A fixture replay writes no patch or archive branch.
+	policy := worktreeSettings(h.cfg).Teardown
+	if !policy.Archive || isReplay(ctx) || !isWorktreeDir(input.WorktreePath) {
+		return &HookOutput{}, nil
+	}
+	main := mainCheckout(ctx, input)
+	if main == "" {
+		return &HookOutput{}, nil
+	}
+
+	dir := filepath.FromSlash(policy.ArchiveDir)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(main, dir)
+	}
+	archive, err := archiveWorktree(ctx, main, input, dir, h.now())
+	if err != nil {
+		slog.Warn("failed to archive worktree",
+			"worktree_path", input.WorktreePath,
+			"error", err.Error(),
+		)
+		return &HookOutput{SystemMessage: "Worktree archive failed, uncommitted work may be lost: " + err.Error()}, nil
+	}
+	if archive == nil {
+		return &HookOutput{}, nil
+	}
+
+	out := &HookOutput{SystemMessage: archive.summary(main)}
+	if data, err := json.Marshal(archive); err == nil {
+		out.Data = data
+	}
+	return out, nil
 }
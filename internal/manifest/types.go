@@ -43,6 +43,7 @@ type Manifest struct {
 	Version    string               `json:"version"`
 	DeployedAt string               `json:"deployed_at"`
 	Files      map[string]FileEntry `json:"files"`
+	Migrations []AppliedMigration   `json:"migrations,omitempty"`
 }
 
 // FileEntry represents a single tracked file in the manifest.
@@ -53,6 +54,14 @@ type FileEntry struct {
 	CurrentHash  string     `json:"current_hash"`
 }
 
+// AppliedMigration records a template migration step applied to the project.
+type AppliedMigration struct {
+	ID          string `json:"id"`
+	FromVersion string `json:"from_version,omitempty"`
+	ToVersion   string `json:"to_version"`
+	AppliedAt   string `json:"applied_at"`
+}
+
 // ChangedFile represents a file whose content has changed since last tracking.
 type ChangedFile struct {
 	Path       string     `json:"path"`
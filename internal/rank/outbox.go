@@ -0,0 +1,380 @@
+package rank
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/defs"
+	"github.com/modu-ai/moai-adk/internal/resilience"
+)
+
+// Outbox defaults.
+const (
+	// DefaultOutboxMaxAttempts is how many server rejections a queued
+	// session survives before it is dropped.
+	DefaultOutboxMaxAttempts = 10
+
+	// outboxSentRetention is how long delivered session hashes are
+	// remembered so re-enqueueing them is a no-op.
+	outboxSentRetention = 30 * 24 * time.Hour
+)
+
+// ErrInvalidSessionHash is returned when a submission's hash cannot be used
+// as an outbox key.
+var ErrInvalidSessionHash = errors.New("rank: invalid session hash")
+
+// OutboxEntry is a queued session submission.
+type OutboxEntry struct {
+	Session   *SessionSubmission `json:"session"`
+	QueuedAt  time.Time          `json:"queuedAt"`
+	Attempts  int                `json:"attempts"`
+	LastError string             `json:"lastError,omitempty"`
+}
+
+// OutboxStatus summarizes the outbox for display.
+type OutboxStatus struct {
+	Depth       int       `json:"-"`
+	LastError   string    `json:"lastError,omitempty"`
+	LastErrorAt time.Time `json:"lastErrorAt"`
+	LastSentAt  time.Time `json:"lastSentAt"`
+	Dropped     int       `json:"dropped"`
+}
+
+// DrainResult reports the outcome of Outbox.Drain.
+type DrainResult struct {
+	Sent      int // sessions accepted by the server
+	Failed    int // sessions left queued after a failed attempt
+	Dropped   int // sessions discarded after too many rejections
+	Remaining int // sessions still queued
+}
+
+// OutboxConfig configures delivery. Zero values select defaults.
+type OutboxConfig struct {
+	// Retry is the backoff policy for each batch.
+	Retry resilience.RetryPolicy
+	// Breaker stops a drain early while the API keeps failing.
+	Breaker *resilience.CircuitBreaker
+	// MaxAttempts bounds server rejections per session.
+	MaxAttempts int
+	// BatchSize is the number of sessions per request (at most MaxBatchSize).
+	BatchSize int
+}
+
+// DefaultOutboxRetryPolicy returns the batch retry policy used by the outbox.
+func DefaultOutboxRetryPolicy() resilience.RetryPolicy {
+	return resilience.RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   5 * time.Second,
+		UseJitter:  true,
+	}
+}
+
+// Outbox is a durable local queue of session submissions. Each session is
+// stored as its own file keyed by SessionHash, so concurrent SessionEnd
+// hooks never overwrite each other and enqueueing the same session twice
+// is a no-op. Sessions leave the queue only once the API accepts them.
+type Outbox struct {
+	dir string
+	cfg OutboxConfig
+}
+
+// NewOutbox creates an outbox stored in dir.
+// If dir is empty, uses ~/.moai/rank/outbox.
+func NewOutbox(dir string, cfg OutboxConfig) (*Outbox, error) {
+	if dir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("get home directory: %w", err)
+		}
+		dir = filepath.Join(homeDir, defs.MoAIDir, defs.RankSubdir, "outbox")
+	}
+	if cfg.Retry.MaxRetries == 0 && cfg.Retry.BaseDelay == 0 {
+		cfg.Retry = DefaultOutboxRetryPolicy()
+	}
+	if cfg.Breaker == nil {
+		cfg.Breaker = resilience.NewCircuitBreaker(resilience.CircuitBreakerConfig{Threshold: 3})
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultOutboxMaxAttempts
+	}
+	if cfg.BatchSize <= 0 || cfg.BatchSize > MaxBatchSize {
+		cfg.BatchSize = MaxBatchSize
+	}
+	return &Outbox{dir: dir, cfg: cfg}, nil
+}
+
+func (o *Outbox) pendingPath(hash string) string {
+	return filepath.Join(o.dir, "pending", hash+".json")
+}
+
+func (o *Outbox) sentPath(hash string) string {
+	return filepath.Join(o.dir, "sent", hash)
+}
+
+func (o *Outbox) statePath() string {
+	return filepath.Join(o.dir, "state.json")
+}
+
+// Enqueue adds a session to the outbox. A missing SessionHash is computed
+// with ComputeSessionHash. Returns false without error when the session is
+// already queued or was delivered recently.
+func (o *Outbox) Enqueue(s *SessionSubmission) (bool, error) {
+	if s.SessionHash == "" {
+		s.SessionHash = ComputeSessionHash(s.EndedAt, s.InputTokens, s.OutputTokens, s.CacheCreationTokens, s.CacheReadTokens, s.ModelName)
+	}
+	if !isValidSessionID(s.SessionHash) {
+		return false, ErrInvalidSessionHash
+	}
+	for _, p := range []string{o.pendingPath(s.SessionHash), o.sentPath(s.SessionHash)} {
+		if _, err := os.Stat(p); err == nil {
+			return false, nil
+		}
+	}
+
+	entry := &OutboxEntry{Session: s, QueuedAt: time.Now()}
+	if err := o.writeEntry(entry); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (o *Outbox) writeEntry(e *OutboxEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal outbox entry: %w", err)
+	}
+	if err := writeFileAtomic(o.pendingPath(e.Session.SessionHash), data); err != nil {
+		return fmt.Errorf("write outbox entry: %w", err)
+	}
+	return nil
+}
+
+// Pending returns the queued sessions, oldest first. Unreadable entries are
+// skipped.
+func (o *Outbox) Pending() ([]*OutboxEntry, error) {
+	files, err := os.ReadDir(filepath.Join(o.dir, "pending"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read outbox: %w", err)
+	}
+
+	var entries []*OutboxEntry
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(o.dir, "pending", f.Name()))
+		if err != nil {
+			continue
+		}
+		var e OutboxEntry
+		if err := json.Unmarshal(data, &e); err != nil || e.Session == nil || !isValidSessionID(e.Session.SessionHash) {
+			slog.Debug("rank outbox: skip unreadable entry", "file", f.Name())
+			continue
+		}
+		entries = append(entries, &e)
+	}
+	slices.SortFunc(entries, func(a, b *OutboxEntry) int {
+		return a.QueuedAt.Compare(b.QueuedAt)
+	})
+	return entries, nil
+}
+
+// Status returns the queue depth with the last delivery outcome.
+func (o *Outbox) Status() (OutboxStatus, error) {
+	st := o.loadState()
+	entries, err := o.Pending()
+	if err != nil {
+		return st, err
+	}
+	st.Depth = len(entries)
+	return st, nil
+}
+
+func (o *Outbox) loadState() OutboxStatus {
+	var st OutboxStatus
+	data, err := os.ReadFile(o.statePath())
+	if err != nil {
+		return st
+	}
+	_ = json.Unmarshal(data, &st)
+	return st
+}
+
+func (o *Outbox) saveState(st OutboxStatus) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal outbox state: %w", err)
+	}
+	if err := writeFileAtomic(o.statePath(), data); err != nil {
+		return fmt.Errorf("write outbox state: %w", err)
+	}
+	return nil
+}
+
+// Drain submits queued sessions in batches through SubmitSessionsBatch,
+// retrying each batch with backoff behind the circuit breaker. Sessions
+// are removed once accepted; a batch the server partially rejects is
+// resubmitted per session through SubmitSession. Server rejections count against MaxAttempts;
+// network errors do not, so sessions survive long offline periods. The
+// drain stops early when the circuit opens or ctx ends.
+// The returned error reports local storage failures only; delivery
+// failures are reflected in the result and in Status.
+func (o *Outbox) Drain(ctx context.Context, client Client) (DrainResult, error) {
+	var res DrainResult
+	entries, err := o.Pending()
+	if err != nil {
+		return res, err
+	}
+	st := o.loadState()
+	var lastErr error
+
+	for i := 0; i < len(entries); i += o.cfg.BatchSize {
+		if ctx.Err() != nil || o.cfg.Breaker.State() == resilience.StateOpen {
+			break
+		}
+		batch := entries[i:min(i+o.cfg.BatchSize, len(entries))]
+		sessions := make([]*SessionSubmission, len(batch))
+		for j, e := range batch {
+			sessions[j] = e.Session
+		}
+
+		var result *BatchResult
+		err := resilience.Retry(ctx, o.cfg.Retry, func() error {
+			return o.cfg.Breaker.Call(ctx, func() error {
+				r, err := client.SubmitSessionsBatch(ctx, sessions)
+				result = r
+				return err
+			})
+		})
+		if err == nil && result != nil && result.Failed > 0 {
+			// The batch result carries counts only, so resubmit the sessions
+			// one by one: accepted ones leave the queue and only the
+			// rejected ones count an attempt.
+			for _, e := range batch {
+				serr := o.cfg.Breaker.Call(ctx, func() error {
+					return client.SubmitSession(ctx, e.Session)
+				})
+				if serr == nil || isDuplicateSession(serr) {
+					o.markSent(e.Session.SessionHash)
+					res.Sent++
+					st.LastSentAt = time.Now()
+					continue
+				}
+				lastErr = serr
+				if werr := o.fail(e, serr, &res, &st); werr != nil {
+					return res, werr
+				}
+			}
+			continue
+		}
+
+		if err == nil {
+			for _, e := range batch {
+				o.markSent(e.Session.SessionHash)
+			}
+			res.Sent += len(batch)
+			st.LastSentAt = time.Now()
+			continue
+		}
+
+		lastErr = err
+		for _, e := range batch {
+			if werr := o.fail(e, err, &res, &st); werr != nil {
+				return res, werr
+			}
+		}
+	}
+
+	if lastErr != nil {
+		st.LastError = lastErr.Error()
+		st.LastErrorAt = time.Now()
+	} else if res.Sent > 0 {
+		st.LastError = ""
+	}
+	o.pruneSent()
+
+	remaining, err := o.Pending()
+	if err != nil {
+		return res, err
+	}
+	res.Remaining = len(remaining)
+	st.Depth = res.Remaining
+	if lastErr != nil || res.Sent > 0 || res.Dropped > 0 {
+		if err := o.saveState(st); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// fail records a delivery error for e. Server rejections count against
+// MaxAttempts and drop the session once it is exhausted.
+func (o *Outbox) fail(e *OutboxEntry, err error, res *DrainResult, st *OutboxStatus) error {
+	if !countsAsAttempt(err) {
+		res.Failed++
+		return nil
+	}
+	e.Attempts++
+	e.LastError = err.Error()
+	if e.Attempts >= o.cfg.MaxAttempts {
+		slog.Warn("rank outbox: dropping session after repeated rejections",
+			"session_hash", e.Session.SessionHash, "attempts", e.Attempts, "error", err)
+		_ = os.Remove(o.pendingPath(e.Session.SessionHash))
+		res.Dropped++
+		st.Dropped++
+		return nil
+	}
+	res.Failed++
+	return o.writeEntry(e)
+}
+
+// markSent moves a delivered session from the queue to the sent markers.
+func (o *Outbox) markSent(hash string) {
+	if err := os.MkdirAll(filepath.Dir(o.sentPath(hash)), 0o700); err == nil {
+		_ = os.WriteFile(o.sentPath(hash), nil, 0o600)
+	}
+	_ = os.Remove(o.pendingPath(hash))
+}
+
+// pruneSent forgets delivered hashes older than the retention period.
+func (o *Outbox) pruneSent() {
+	dir := filepath.Join(o.dir, "sent")
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-outboxSentRetention)
+	for _, f := range files {
+		if info, err := f.Info(); err == nil && info.ModTime().Before(cutoff) {
+			_ = os.Remove(filepath.Join(dir, f.Name()))
+		}
+	}
+}
+
+// countsAsAttempt reports whether a delivery error was the API refusing the
+// sessions themselves. Network failures, server errors, an open circuit,
+// cancellation and authentication problems leave attempts untouched.
+func countsAsAttempt(err error) bool {
+	var apiErr *ApiError
+	return errors.As(err, &apiErr) && apiErr.IsClientError()
+}
+
+// isDuplicateSession reports whether the API refused a session because it
+// already has it, as happens for sessions a partially rejected batch accepted.
+func isDuplicateSession(err error) bool {
+	var apiErr *ApiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
@@ -0,0 +1,269 @@
+package rank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/resilience"
+)
+
+// fastOutboxConfig keeps retries quick for tests.
+func fastOutboxConfig() OutboxConfig {
+	return OutboxConfig{
+		Retry:   resilience.RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+		Breaker: resilience.NewCircuitBreaker(resilience.CircuitBreakerConfig{Threshold: 10, Timeout: time.Minute}),
+	}
+}
+
+func testSession(n int) *SessionSubmission {
+	return &SessionSubmission{
+		EndedAt:      fmt.Sprintf("2026-01-01T10:%02d:00Z", n),
+		InputTokens:  int64(100 + n),
+		OutputTokens: 10,
+		ModelName:    "claude-opus-4-6",
+	}
+}
+
+// batchServer is an httptest stand-in for the batch endpoint. Each request
+// is answered by respond with the number of sessions received.
+func batchServer(t *testing.T, respond func(call, sessions int) (int, BatchResult)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/sessions/batch" {
+			http.NotFound(w, r)
+			return
+		}
+		var payload struct {
+			Sessions []SessionSubmission `json:"sessions"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		status, result := respond(int(calls.Add(1)), len(payload.Sessions))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status >= 400 {
+			_, _ = fmt.Fprintf(w, `{"success":false,"error":{"code":"E","message":"status %d"}}`, status)
+			return
+		}
+		data, _ := json.Marshal(result)
+		_, _ = fmt.Fprintf(w, `{"success":true,"data":%s}`, data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestOutbox_EnqueueIsIdempotent(t *testing.T) {
+	o, err := NewOutbox(t.TempDir(), fastOutboxConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := testSession(1)
+	added, err := o.Enqueue(s)
+	if err != nil || !added {
+		t.Fatalf("Enqueue() = %v, %v", added, err)
+	}
+	if s.SessionHash != ComputeSessionHash(s.EndedAt, s.InputTokens, s.OutputTokens, 0, 0, s.ModelName) {
+		t.Errorf("SessionHash = %q, want ComputeSessionHash value", s.SessionHash)
+	}
+
+	// The same session, rebuilt independently, is not queued twice.
+	if added, _ := o.Enqueue(testSession(1)); added {
+		t.Error("duplicate session should not be enqueued")
+	}
+	if _, err := o.Enqueue(&SessionSubmission{SessionHash: "../escape"}); err == nil {
+		t.Error("invalid hash should be rejected")
+	}
+
+	st, err := o.Status()
+	if err != nil || st.Depth != 1 {
+		t.Errorf("Status() = %+v, %v", st, err)
+	}
+}
+
+func TestOutbox_DrainRetriesThenDelivers(t *testing.T) {
+	srv, calls := batchServer(t, func(call, n int) (int, BatchResult) {
+		if call == 1 {
+			return http.StatusServiceUnavailable, BatchResult{}
+		}
+		return http.StatusOK, BatchResult{Success: true, Processed: n, Succeeded: n}
+	})
+	client := NewClient("key", WithBaseURL(srv.URL))
+
+	o, _ := NewOutbox(t.TempDir(), fastOutboxConfig())
+	for i := range 3 {
+		if _, err := o.Enqueue(testSession(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := o.Drain(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Sent != 3 || res.Remaining != 0 || calls.Load() != 2 {
+		t.Errorf("Drain() = %+v after %d calls, want 3 sent after a retry", res, calls.Load())
+	}
+
+	// Delivered sessions are remembered and not queued again.
+	if added, _ := o.Enqueue(testSession(0)); added {
+		t.Error("delivered session should not be re-enqueued")
+	}
+	st, _ := o.Status()
+	if st.Depth != 0 || st.LastSentAt.IsZero() || st.LastError != "" {
+		t.Errorf("Status() = %+v", st)
+	}
+}
+
+func TestOutbox_DrainOffline(t *testing.T) {
+	srv, _ := batchServer(t, func(int, int) (int, BatchResult) { return http.StatusOK, BatchResult{} })
+	url := srv.URL
+	srv.Close() // connection refused from here on
+
+	cfg := fastOutboxConfig()
+	cfg.BatchSize = 1
+	cfg.Breaker = resilience.NewCircuitBreaker(resilience.CircuitBreakerConfig{Threshold: 1, Timeout: time.Minute})
+	o, _ := NewOutbox(t.TempDir(), cfg)
+	for i := range 2 {
+		if _, err := o.Enqueue(testSession(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := o.Drain(context.Background(), NewClient("key", WithBaseURL(url)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first batch opens the circuit, so the second is not attempted.
+	if res.Sent != 0 || res.Failed != 1 || res.Remaining != 2 {
+		t.Errorf("Drain() = %+v", res)
+	}
+
+	pending, _ := o.Pending()
+	for _, e := range pending {
+		if e.Attempts != 0 {
+			t.Errorf("network failures must not count as attempts: %+v", e)
+		}
+	}
+	st, _ := o.Status()
+	if st.Depth != 2 || st.LastError == "" || st.LastErrorAt.IsZero() {
+		t.Errorf("Status() = %+v, want depth 2 with last error", st)
+	}
+}
+
+func TestOutbox_DrainDropsRejectedSessions(t *testing.T) {
+	srv, calls := batchServer(t, func(_, n int) (int, BatchResult) {
+		return http.StatusOK, BatchResult{Success: true, Processed: n, Failed: n}
+	})
+	cfg := fastOutboxConfig()
+	cfg.MaxAttempts = 2
+	o, _ := NewOutbox(t.TempDir(), cfg)
+	if _, err := o.Enqueue(testSession(1)); err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient("key", WithBaseURL(srv.URL))
+
+	res, _ := o.Drain(context.Background(), client)
+	if res.Failed != 1 || res.Remaining != 1 {
+		t.Fatalf("first Drain() = %+v", res)
+	}
+	// Rejections are not retried within a drain.
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+
+	res, _ = o.Drain(context.Background(), client)
+	if res.Dropped != 1 || res.Remaining != 0 {
+		t.Errorf("second Drain() = %+v, want the session dropped", res)
+	}
+	if st, _ := o.Status(); st.Dropped != 1 {
+		t.Errorf("Status().Dropped = %d, want 1", st.Dropped)
+	}
+}
+
+func TestApiError_IsClientError(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{400, true},
+		{404, true},
+		{408, false},
+		{429, false},
+		{500, false},
+		{503, false},
+	}
+	for _, tt := range tests {
+		if got := (&ApiError{StatusCode: tt.status}).IsClientError(); got != tt.want {
+			t.Errorf("status %d: IsClientError() = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestOutbox_DrainPartiallyRejectedBatch(t *testing.T) {
+	poison := testSession(2)
+	var batchCalls, singleCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/sessions/batch":
+			batchCalls.Add(1)
+			_, _ = fmt.Fprint(w, `{"success":true,"data":{"success":true,"processed":3,"succeeded":2,"failed":1}}`)
+		case "/api/v1/sessions":
+			singleCalls.Add(1)
+			var s SessionSubmission
+			_ = json.NewDecoder(r.Body).Decode(&s)
+			switch s.EndedAt {
+			case poison.EndedAt:
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = fmt.Fprint(w, `{"success":false,"error":{"code":"E","message":"invalid session"}}`)
+			case testSession(1).EndedAt:
+				// Accepted by the batch already.
+				w.WriteHeader(http.StatusConflict)
+				_, _ = fmt.Fprint(w, `{"success":false,"error":{"code":"E","message":"duplicate"}}`)
+			default:
+				_, _ = fmt.Fprint(w, `{"success":true,"data":{}}`)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := fastOutboxConfig()
+	cfg.MaxAttempts = 2
+	o, _ := NewOutbox(t.TempDir(), cfg)
+	for _, s := range []*SessionSubmission{testSession(1), poison, testSession(3)} {
+		if _, err := o.Enqueue(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := NewClient("key", WithBaseURL(srv.URL))
+
+	res, err := o.Drain(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Sent != 2 || res.Failed != 1 || res.Remaining != 1 {
+		t.Fatalf("Drain() = %+v, want 2 sent and the rejected session queued", res)
+	}
+	if batchCalls.Load() != 1 || singleCalls.Load() != 3 {
+		t.Errorf("calls = %d batch, %d single; want 1 and 3", batchCalls.Load(), singleCalls.Load())
+	}
+	pending, _ := o.Pending()
+	if len(pending) != 1 || pending[0].Session.SessionHash != poison.SessionHash || pending[0].Attempts != 1 {
+		t.Fatalf("pending = %+v, want only the rejected session with one attempt", pending)
+	}
+
+	// The poison session is dropped alone; delivered sessions stay delivered.
+	res, _ = o.Drain(context.Background(), client)
+	if res.Dropped != 1 || res.Sent != 0 || res.Remaining != 0 {
+		t.Errorf("second Drain() = %+v, want the rejected session dropped", res)
+	}
+}
@@ -1,6 +1,8 @@
 // Package rank provides model pricing for MoAI Rank cost calculation.
 package rank
 
+import "strings"
+
 // ModelPricing holds pricing information for a Claude model.
 // Prices are in USD per million tokens.
 type ModelPricing struct {
@@ -103,6 +105,37 @@ func GetModelPricing(modelName string) ModelPricing {
 	return ModelPricing{}
 }
 
+// ResolveModelPricing returns the pricing for a model name, also accepting
+// undated aliases such as "claude-opus-4-6" for "claude-opus-4-6-20260203".
+// The second return value is false when no pricing is known.
+func ResolveModelPricing(modelName string) (ModelPricing, bool) {
+	if pricing, ok := modelPricingDB[modelName]; ok {
+		return pricing, true
+	}
+	if modelName == "" {
+		return ModelPricing{}, false
+	}
+	for name, pricing := range modelPricingDB {
+		if date, ok := strings.CutPrefix(name, modelName+"-"); ok && isDateSuffix(date) {
+			return pricing, true
+		}
+	}
+	return ModelPricing{}, false
+}
+
+// isDateSuffix reports whether s is a YYYYMMDD model release date.
+func isDateSuffix(s string) bool {
+	if len(s) != 8 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // CalculateCost calculates the USD cost for token usage.
 func CalculateCost(inputTokens, outputTokens, cacheCreation, cacheRead int64, pricing ModelPricing) float64 {
 	inputCost := float64(inputTokens) / 1_000_000 * pricing.Input
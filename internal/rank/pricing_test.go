@@ -77,6 +77,29 @@ func TestHasPricing_Unknown(t *testing.T) {
 	}
 }
 
+func TestResolveModelPricing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		model     string
+		wantOK    bool
+		wantInput float64
+	}{
+		{"claude-opus-4-5-20251101", true, 5.00},
+		{"claude-opus-4-5", true, 5.00},
+		{"claude-opus-4", true, 15.00},
+		{"claude-opus", false, 0},
+		{"", false, 0},
+		{"gpt-4", false, 0},
+	}
+	for _, tt := range tests {
+		got, ok := ResolveModelPricing(tt.model)
+		if ok != tt.wantOK || got.Input != tt.wantInput {
+			t.Errorf("ResolveModelPricing(%q) = (%v, %v), want input %v, ok %v", tt.model, got, ok, tt.wantInput, tt.wantOK)
+		}
+	}
+}
+
 func TestCalculateCost_Zero(t *testing.T) {
 	t.Parallel()
 
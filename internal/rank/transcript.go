@@ -2,14 +2,11 @@
 package rank
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
-	"strings"
-	"time"
 )
 
 // TranscriptUsage represents token usage extracted from a Claude Code transcript.
@@ -31,12 +28,29 @@ type transcriptMessage struct {
 	Type      string        `json:"type"`
 	Message   transcriptMsg `json:"message"`
 	Model     string        `json:"model"`
+	SessionID string        `json:"sessionId"`
+	CWD       string        `json:"cwd"`
+	RequestID string        `json:"requestId"`
+
+	IsSidechain bool   `json:"isSidechain"`
+	AgentID     string `json:"agentId"`
 }
 
 // transcriptMsg represents the message content with usage data.
 type transcriptMsg struct {
-	Usage *transcriptUsage `json:"usage"`
-	Model string           `json:"model"`
+	ID      string           `json:"id"`
+	Usage   *transcriptUsage `json:"usage"`
+	Model   string           `json:"model"`
+	Content json.RawMessage  `json:"content"`
+}
+
+// transcriptBlock is one entry of a structured message content array.
+type transcriptBlock struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Input struct {
+		SubagentType string `json:"subagent_type"`
+	} `json:"input"`
 }
 
 // transcriptUsage represents token usage information.
@@ -50,84 +64,19 @@ type transcriptUsage struct {
 // ParseTranscript parses a Claude Code transcript JSONL file and extracts token usage.
 // The transcript file contains one JSON object per line, with token usage in message.usage fields.
 func ParseTranscript(transcriptPath string) (*TranscriptUsage, error) {
-	file, err := os.Open(transcriptPath)
-	if err != nil {
-		return nil, fmt.Errorf("open transcript: %w", err)
-	}
-	defer func() {
-		// Close errors are ignored for read-only files
-		_ = file.Close()
-	}()
-
-	usage := &TranscriptUsage{}
-	var firstTimestamp, lastTimestamp string
-	turnCount := 0
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
-		}
-
-		var msg transcriptMessage
-		if err := json.Unmarshal([]byte(line), &msg); err != nil {
-			// Skip invalid lines
-			continue
-		}
-
-		// Track timestamps for duration calculation
-		if msg.Timestamp != "" {
-			if firstTimestamp == "" {
-				firstTimestamp = msg.Timestamp
-			}
-			lastTimestamp = msg.Timestamp
-		}
-
-		// Count user turns
-		if msg.Type == "user" {
-			turnCount++
-		}
-
-		// Extract model name
-		model := msg.Model
-		if model != "" && usage.ModelName == "" {
-			usage.ModelName = model
-		}
-		if msg.Message.Model != "" && usage.ModelName == "" {
-			usage.ModelName = msg.Message.Model
-		}
-
-		// Extract token usage
-		if msg.Message.Usage != nil {
-			usage.InputTokens += msg.Message.Usage.InputTokens
-			usage.OutputTokens += msg.Message.Usage.OutputTokens
-			usage.CacheCreationTokens += msg.Message.Usage.CacheCreationInputTokens
-			usage.CacheReadTokens += msg.Message.Usage.CacheReadInputTokens
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("scan transcript: %w", err)
-	}
-
-	// Set timing metadata
-	usage.StartedAt = firstTimestamp
-	usage.EndedAt = lastTimestamp
-	usage.TurnCount = turnCount
-
-	// Calculate duration if timestamps are available
-	if firstTimestamp != "" && lastTimestamp != "" {
-		start, err := time.Parse(time.RFC3339Nano, firstTimestamp)
-		if err == nil {
-			end, err := time.Parse(time.RFC3339Nano, lastTimestamp)
-			if err == nil {
-				usage.DurationSeconds = int64(end.Sub(start).Seconds())
-			}
-		}
+	var cursor TranscriptCursor
+	if _, err := parseTranscriptFrom(transcriptPath, &cursor, true); err != nil {
+		return nil, err
 	}
+	usage := cursor.Usage
+	return &usage, nil
+}
 
-	return usage, nil
+// ParseTranscriptTurns parses a whole transcript and returns its turns in
+// file order.
+func ParseTranscriptTurns(transcriptPath string) ([]Turn, error) {
+	var cursor TranscriptCursor
+	return parseTranscriptFrom(transcriptPath, &cursor, true)
 }
 
 // claudeDesktopConfigDir returns the Claude Desktop (Electron app) configuration directory
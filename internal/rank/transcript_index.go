@@ -0,0 +1,345 @@
+package rank
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/defs"
+)
+
+// transcriptIndexVersion is bumped when the stored format changes; older
+// data is discarded and transcripts are re-parsed.
+const transcriptIndexVersion = 1
+
+// indexSettleTime is how long a transcript must be unmodified before a
+// trailing line without a newline is treated as complete.
+const indexSettleTime = time.Minute
+
+// IndexedTranscript is the index entry for one transcript file.
+type IndexedTranscript struct {
+	ID          string           `json:"id"`
+	Size        int64            `json:"size"`
+	ModTime     time.Time        `json:"modTime"`
+	SessionID   string           `json:"sessionId,omitempty"`
+	CWD         string           `json:"cwd,omitempty"`
+	Turns       int              `json:"turns"`
+	FirstTurnAt time.Time        `json:"firstTurnAt"`
+	LastTurnAt  time.Time        `json:"lastTurnAt"`
+	Cursor      TranscriptCursor `json:"cursor"`
+}
+
+// transcriptIndexData is the persisted index manifest.
+type transcriptIndexData struct {
+	Version   int                           `json:"version"`
+	UpdatedAt time.Time                     `json:"updatedAt"`
+	Files     map[string]*IndexedTranscript `json:"files"`
+}
+
+// indexedTurns is the per-transcript turns file. It carries its own cursor
+// so a manifest lost to a concurrent writer never causes turns to be
+// appended twice.
+type indexedTurns struct {
+	Version int              `json:"version"`
+	Path    string           `json:"path"`
+	Size    int64            `json:"size"`
+	ModTime time.Time        `json:"modTime"`
+	Cursor  TranscriptCursor `json:"cursor"`
+	Turns   []Turn           `json:"turns"`
+}
+
+// IndexStats summarizes the work done by TranscriptIndex.Update.
+type IndexStats struct {
+	Scanned   int   // transcripts checked
+	Updated   int   // transcripts with new content
+	Reset     int   // transcripts re-parsed after being truncated
+	Failed    int   // transcripts that could not be read
+	Pruned    int   // entries removed for deleted transcripts
+	NewTurns  int   // turns added or updated
+	BytesRead int64 // transcript bytes parsed
+}
+
+// TranscriptIndex is a local index of per-turn transcript records.
+// Each transcript is parsed once; later updates read only the bytes
+// appended since, so long sessions are never re-parsed from scratch.
+type TranscriptIndex struct {
+	dir  string
+	data *transcriptIndexData
+}
+
+// OpenTranscriptIndex opens the index stored in dir.
+// If dir is empty, uses ~/.moai/index/transcripts.
+// A missing or corrupted manifest yields an empty index.
+func OpenTranscriptIndex(dir string) (*TranscriptIndex, error) {
+	if dir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("get home directory: %w", err)
+		}
+		dir = filepath.Join(homeDir, defs.MoAIDir, defs.IndexSubdir, "transcripts")
+	}
+
+	x := &TranscriptIndex{dir: dir, data: newTranscriptIndexData()}
+	data, err := os.ReadFile(x.manifestPath())
+	if err != nil {
+		return x, nil
+	}
+	var stored transcriptIndexData
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != transcriptIndexVersion {
+		return x, nil
+	}
+	if stored.Files == nil {
+		stored.Files = make(map[string]*IndexedTranscript)
+	}
+	x.data = &stored
+	return x, nil
+}
+
+func newTranscriptIndexData() *transcriptIndexData {
+	return &transcriptIndexData{
+		Version: transcriptIndexVersion,
+		Files:   make(map[string]*IndexedTranscript),
+	}
+}
+
+func (x *TranscriptIndex) manifestPath() string {
+	return filepath.Join(x.dir, "index.json")
+}
+
+func (x *TranscriptIndex) turnsPath(id string) string {
+	return filepath.Join(x.dir, "turns", id+".json")
+}
+
+// transcriptID derives a stable file name for a transcript path.
+func transcriptID(path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Update brings the index up to date for the given transcripts, parsing
+// only content appended since the last update, and drops entries whose
+// transcript no longer exists. The manifest is saved when anything changed.
+// Unreadable transcripts are counted in Failed and otherwise skipped.
+func (x *TranscriptIndex) Update(paths []string) (IndexStats, error) {
+	var stats IndexStats
+	for _, path := range paths {
+		stats.Scanned++
+		if err := x.updateFile(path, &stats); err != nil {
+			slog.Debug("transcript index: skip transcript", "path", path, "error", err)
+			stats.Failed++
+		}
+	}
+
+	for path, entry := range x.data.Files {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			_ = os.Remove(x.turnsPath(entry.ID))
+			delete(x.data.Files, path)
+			stats.Pruned++
+		}
+	}
+
+	if stats.Updated == 0 && stats.Pruned == 0 {
+		return stats, nil
+	}
+	x.data.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(x.data, "", "  ")
+	if err != nil {
+		return stats, fmt.Errorf("marshal transcript index: %w", err)
+	}
+	if err := writeFileAtomic(x.manifestPath(), data); err != nil {
+		return stats, fmt.Errorf("write transcript index: %w", err)
+	}
+	return stats, nil
+}
+
+func (x *TranscriptIndex) updateFile(path string, stats *IndexStats) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	entry := x.data.Files[path]
+	if entry != nil && entry.Size == info.Size() && entry.ModTime.Equal(info.ModTime()) {
+		return nil
+	}
+
+	id := transcriptID(path)
+	state := x.loadTurns(id, path)
+
+	// Another process may already have indexed this content.
+	if state.Size != info.Size() || !state.ModTime.Equal(info.ModTime()) {
+		if info.Size() < state.Cursor.Offset {
+			state = &indexedTurns{Version: transcriptIndexVersion, Path: path}
+			stats.Reset++
+		}
+
+		before := state.Cursor.Offset
+		settled := time.Since(info.ModTime()) > indexSettleTime
+		turns, err := parseTranscriptFrom(path, &state.Cursor, settled)
+		if err != nil {
+			return err
+		}
+		for _, t := range turns {
+			state.Turns = mergeTurn(state.Turns, t)
+		}
+		state.Size = info.Size()
+		state.ModTime = info.ModTime()
+		stats.NewTurns += len(turns)
+		stats.BytesRead += state.Cursor.Offset - before
+
+		data, err := json.Marshal(state)
+		if err != nil {
+			return fmt.Errorf("marshal turns: %w", err)
+		}
+		if err := writeFileAtomic(x.turnsPath(id), data); err != nil {
+			return fmt.Errorf("write turns: %w", err)
+		}
+	}
+
+	x.data.Files[path] = state.entry(id)
+	stats.Updated++
+	return nil
+}
+
+// loadTurns reads the stored turns for a transcript, returning an empty
+// state when none exist or they belong to another format or path.
+func (x *TranscriptIndex) loadTurns(id, path string) *indexedTurns {
+	empty := &indexedTurns{Version: transcriptIndexVersion, Path: path}
+	data, err := os.ReadFile(x.turnsPath(id))
+	if err != nil {
+		return empty
+	}
+	var state indexedTurns
+	if err := json.Unmarshal(data, &state); err != nil || state.Version != transcriptIndexVersion || state.Path != path {
+		return empty
+	}
+	return &state
+}
+
+// entry builds the manifest entry summarizing the stored turns.
+func (s *indexedTurns) entry(id string) *IndexedTranscript {
+	e := &IndexedTranscript{
+		ID:      id,
+		Size:    s.Size,
+		ModTime: s.ModTime,
+		Turns:   len(s.Turns),
+		Cursor:  s.Cursor,
+	}
+	for _, t := range s.Turns {
+		if e.SessionID == "" {
+			e.SessionID = t.SessionID
+		}
+		if e.CWD == "" {
+			e.CWD = t.CWD
+		}
+		if t.Timestamp.IsZero() {
+			continue
+		}
+		if e.FirstTurnAt.IsZero() || t.Timestamp.Before(e.FirstTurnAt) {
+			e.FirstTurnAt = t.Timestamp
+		}
+		if t.Timestamp.After(e.LastTurnAt) {
+			e.LastTurnAt = t.Timestamp
+		}
+	}
+	return e
+}
+
+// Entry returns the index entry for a transcript.
+func (x *TranscriptIndex) Entry(path string) (*IndexedTranscript, bool) {
+	e, ok := x.data.Files[path]
+	return e, ok
+}
+
+// Usage returns the session totals of an indexed transcript, as
+// ParseTranscript would report them.
+func (x *TranscriptIndex) Usage(path string) (*TranscriptUsage, bool) {
+	e, ok := x.data.Files[path]
+	if !ok {
+		return nil, false
+	}
+	usage := e.Cursor.Usage
+	return &usage, true
+}
+
+// Turns returns the indexed turns of a transcript.
+func (x *TranscriptIndex) Turns(path string) ([]Turn, error) {
+	e, ok := x.data.Files[path]
+	if !ok {
+		return nil, fmt.Errorf("transcript not indexed: %s", path)
+	}
+	state := x.loadTurns(e.ID, path)
+	if state.Cursor.Offset == 0 && e.Cursor.Offset != 0 {
+		return nil, fmt.Errorf("turns missing for %s", path)
+	}
+	return state.Turns, nil
+}
+
+// TurnsSince returns, keyed by transcript path, the turns at or after since
+// for each indexed transcript in paths. Transcripts whose last turn is
+// older than since are not loaded. A zero since returns every turn.
+func (x *TranscriptIndex) TurnsSince(paths []string, since time.Time) map[string][]Turn {
+	result := make(map[string][]Turn)
+	for _, path := range paths {
+		e, ok := x.data.Files[path]
+		if !ok || e.Turns == 0 || (!since.IsZero() && e.LastTurnAt.Before(since)) {
+			continue
+		}
+		turns, err := x.Turns(path)
+		if err != nil {
+			slog.Debug("transcript index: load turns", "path", path, "error", err)
+			continue
+		}
+		if !since.IsZero() {
+			kept := turns[:0]
+			for _, t := range turns {
+				if !t.Timestamp.Before(since) {
+					kept = append(kept, t)
+				}
+			}
+			turns = kept
+		}
+		if len(turns) > 0 {
+			result[path] = turns
+		}
+	}
+	return result
+}
+
+// Len returns the number of indexed transcripts.
+func (x *TranscriptIndex) Len() int {
+	return len(x.data.Files)
+}
+
+// writeFileAtomic writes data via a temp file and rename, creating the
+// parent directory as needed. Concurrent writers each use their own temp
+// file, so readers never observe a partial write.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package rank
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func appendLines(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	for _, l := range lines {
+		if _, err := f.WriteString(l + "\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTranscriptIndex_IncrementalUpdate(t *testing.T) {
+	indexDir := filepath.Join(t.TempDir(), "index")
+	path := filepath.Join(t.TempDir(), "sess.jsonl")
+	appendLines(t, path,
+		`{"timestamp":"2026-01-01T10:00:00Z","type":"user","sessionId":"s1","cwd":"/work/app","message":{"content":"hi"}}`,
+		`{"timestamp":"2026-01-01T10:00:01Z","type":"assistant","sessionId":"s1","cwd":"/work/app","requestId":"r1","message":{"id":"m1","model":"claude-opus-4-6","usage":{"input_tokens":10,"output_tokens":1}}}`,
+	)
+
+	idx, err := OpenTranscriptIndex(indexDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := idx.Update([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Updated != 1 || stats.NewTurns != 1 {
+		t.Fatalf("first update stats = %+v", stats)
+	}
+	firstRead := stats.BytesRead
+
+	// Unchanged transcripts are not read again.
+	if stats, _ := idx.Update([]string{path}); stats.Updated != 0 || stats.BytesRead != 0 {
+		t.Errorf("unchanged update stats = %+v", stats)
+	}
+
+	// The streamed continuation of m1 and a new turn are appended.
+	appendLines(t, path,
+		`{"timestamp":"2026-01-01T10:00:02Z","type":"assistant","sessionId":"s1","requestId":"r1","message":{"id":"m1","model":"claude-opus-4-6","usage":{"input_tokens":10,"output_tokens":30},"content":[{"type":"tool_use","name":"Bash"}]}}`,
+		`{"timestamp":"2026-01-01T10:05:00Z","type":"assistant","sessionId":"s1","requestId":"r2","message":{"id":"m2","model":"claude-opus-4-6","usage":{"input_tokens":5,"output_tokens":5}}}`,
+	)
+	future := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopen to exercise the persisted state.
+	idx, err = OpenTranscriptIndex(indexDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err = idx.Update([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(path)
+	if stats.BytesRead != info.Size()-firstRead {
+		t.Errorf("BytesRead = %d, want only the appended %d bytes", stats.BytesRead, info.Size()-firstRead)
+	}
+
+	turns, err := idx.Turns(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(turns) != 2 || turns[0].OutputTokens != 30 || len(turns[0].ToolUses) != 1 {
+		t.Fatalf("turns = %+v", turns)
+	}
+
+	entry, ok := idx.Entry(path)
+	if !ok || entry.SessionID != "s1" || entry.CWD != "/work/app" || entry.Turns != 2 {
+		t.Errorf("entry = %+v", entry)
+	}
+
+	// Session totals match a full ParseTranscript.
+	got, _ := idx.Usage(path)
+	want, err := ParseTranscript(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != *want {
+		t.Errorf("indexed usage = %+v, want %+v", got, want)
+	}
+
+	since := time.Date(2026, 1, 1, 10, 1, 0, 0, time.UTC)
+	recent := idx.TurnsSince([]string{path}, since)
+	if len(recent[path]) != 1 || recent[path][0].MessageID != "m2" {
+		t.Errorf("TurnsSince = %+v", recent)
+	}
+	if len(idx.TurnsSince([]string{path}, future)) != 0 {
+		t.Error("TurnsSince after the last turn should be empty")
+	}
+}
+
+func TestTranscriptIndex_TruncateAndPrune(t *testing.T) {
+	indexDir := t.TempDir()
+	path := filepath.Join(t.TempDir(), "sess.jsonl")
+	appendLines(t, path,
+		`{"timestamp":"2026-01-01T10:00:00Z","type":"assistant","message":{"id":"m1","usage":{"input_tokens":100}}}`,
+		`{"timestamp":"2026-01-01T10:00:01Z","type":"assistant","message":{"id":"m2","usage":{"input_tokens":100}}}`,
+	)
+
+	idx, _ := OpenTranscriptIndex(indexDir)
+	if _, err := idx.Update([]string{path}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A rewritten, shorter transcript is re-parsed from the start.
+	if err := os.WriteFile(path, []byte(`{"timestamp":"2026-01-02T10:00:00Z","type":"assistant","message":{"id":"m9","usage":{"input_tokens":7}}}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := idx.Update([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Reset != 1 {
+		t.Errorf("stats = %+v, want one reset", stats)
+	}
+	if usage, _ := idx.Usage(path); usage.InputTokens != 7 {
+		t.Errorf("usage after reset = %+v", usage)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.jsonl")
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	stats, err = idx.Update([]string{missing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Failed != 1 || stats.Pruned != 1 || idx.Len() != 0 {
+		t.Errorf("stats = %+v, len = %d", stats, idx.Len())
+	}
+}
+
+func TestOpenTranscriptIndex_Corrupted(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.json"), []byte("{broken"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := OpenTranscriptIndex(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx.Len() != 0 {
+		t.Errorf("corrupted index should open empty, got %d entries", idx.Len())
+	}
+}
@@ -611,3 +611,92 @@ func TestParseTranscript_SingleTimestamp(t *testing.T) {
 		t.Errorf("StartedAt = %q, want 2026-01-01T10:00:00Z", usage.StartedAt)
 	}
 }
+
+func TestParseTranscriptTurns(t *testing.T) {
+	dir := t.TempDir()
+	transcriptFile := filepath.Join(dir, "s1.jsonl")
+
+	content := `{"timestamp":"2026-01-01T10:00:00Z","type":"user","sessionId":"s1","message":{"content":"fix the bug"}}
+{"timestamp":"2026-01-01T10:00:05Z","type":"assistant","sessionId":"s1","cwd":"/work/app","requestId":"r1","message":{"id":"m1","model":"claude-opus-4-6","usage":{"input_tokens":10,"output_tokens":1},"content":[{"type":"text","text":"Looking"}]}}
+{"timestamp":"2026-01-01T10:00:06Z","type":"assistant","sessionId":"s1","cwd":"/work/app","requestId":"r1","message":{"id":"m1","model":"claude-opus-4-6","usage":{"input_tokens":10,"output_tokens":40,"cache_read_input_tokens":500},"content":[{"type":"tool_use","name":"Task","input":{"subagent_type":"expert-backend"}}]}}
+{"timestamp":"2026-01-01T10:00:07Z","type":"user","sessionId":"s1","message":{"content":[{"type":"tool_result","content":"done"}]}}
+not json
+{"timestamp":"2026-01-01T10:00:08Z","type":"assistant","sessionId":"s1","isSidechain":true,"agentId":"a1","message":{"id":"m2","model":"claude-sonnet-4-5","usage":{"input_tokens":3,"output_tokens":2},"content":[{"type":"tool_use","name":"Read"},{"type":"tool_use","name":"Grep"}]}}
+{"timestamp":"2026-01-02T09:00:00Z","type":"user","sessionId":"s1","message":{"content":[{"type":"text","text":"thanks"}]}}
+{"timestamp":"2026-01-02T09:00:01Z","type":"assistant","sessionId":"s1","model":"claude-sonnet-4-5","message":{"usage":{"input_tokens":20,"output_tokens":5,"cache_creation_input_tokens":7}}}
+`
+	if err := os.WriteFile(transcriptFile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	turns, err := ParseTranscriptTurns(transcriptFile)
+	if err != nil {
+		t.Fatalf("ParseTranscriptTurns() returned error: %v", err)
+	}
+	if len(turns) != 3 {
+		t.Fatalf("len(turns) = %d, want 3 (streamed duplicates collapsed)", len(turns))
+	}
+
+	first := turns[0]
+	if first.OutputTokens != 40 || first.CacheReadTokens != 500 {
+		t.Errorf("first turn kept stale usage: %+v", first)
+	}
+	if first.SessionID != "s1" || first.CWD != "/work/app" || first.Model != "claude-opus-4-6" || first.Prompt != 1 {
+		t.Errorf("first turn metadata = %+v", first)
+	}
+	if first.Timestamp.Format("15:04:05") != "10:00:06" {
+		t.Errorf("first timestamp = %v", first.Timestamp)
+	}
+	if strings.Join(first.ToolUses, ",") != "Task" || strings.Join(first.Subagents, ",") != "expert-backend" {
+		t.Errorf("first turn tools = %v, subagents = %v", first.ToolUses, first.Subagents)
+	}
+
+	second := turns[1]
+	if second.AgentID != "a1" || second.Prompt != 1 || strings.Join(second.ToolUses, ",") != "Read,Grep" {
+		t.Errorf("sidechain turn = %+v", second)
+	}
+
+	third := turns[2]
+	if third.Model != "claude-sonnet-4-5" || third.CacheCreationTokens != 7 || third.Prompt != 2 {
+		t.Errorf("third turn = %+v", third)
+	}
+}
+
+func TestParseTranscriptFrom_Incremental(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	line1 := `{"timestamp":"2026-01-01T10:00:00Z","type":"assistant","requestId":"r1","message":{"id":"m1","usage":{"input_tokens":10,"output_tokens":1}}}` + "\n"
+	line2 := `{"timestamp":"2026-01-01T10:00:01Z","type":"assistant","requestId":"r1","message":{"id":"m1","usage":{"input_tokens":10,"output_tokens":9}}}`
+	if err := os.WriteFile(path, []byte(line1+line2), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var cursor TranscriptCursor
+	turns, err := parseTranscriptFrom(path, &cursor, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(turns) != 1 || cursor.Offset != int64(len(line1)) {
+		t.Fatalf("first read: turns = %d, offset = %d; unterminated line must be left unread", len(turns), cursor.Offset)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("\n")
+	_ = f.Close()
+
+	more, err := parseTranscriptFrom(path, &cursor, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(more) != 1 || more[0].OutputTokens != 9 {
+		t.Fatalf("second read = %+v", more)
+	}
+	if merged := mergeTurn(turns, more[0]); len(merged) != 1 || merged[0].OutputTokens != 9 {
+		t.Errorf("merge across reads = %+v", merged)
+	}
+	if cursor.Usage.InputTokens != 20 || cursor.Usage.DurationSeconds != 1 {
+		t.Errorf("running usage = %+v", cursor.Usage)
+	}
+}
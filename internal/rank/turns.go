@@ -0,0 +1,254 @@
+package rank
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// turnMergeWindow is how many trailing turns are checked for a repeated
+// message. Streamed responses write one line per content block, so the
+// lines of a message are adjacent apart from interleaved tool results.
+const turnMergeWindow = 8
+
+// taskToolNames are the tool names that launch a subagent.
+var taskToolNames = map[string]bool{"Task": true, "Agent": true}
+
+// Turn is one assistant response in a transcript, with its token usage
+// and the tools and subagents it invoked.
+type Turn struct {
+	Timestamp time.Time `json:"ts"`
+	SessionID string    `json:"sessionId,omitempty"`
+	CWD       string    `json:"cwd,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	MessageID string    `json:"messageId,omitempty"`
+	RequestID string    `json:"requestId,omitempty"`
+
+	// Prompt is the 1-based index of the user prompt this turn answers,
+	// or 0 for output before the first prompt.
+	Prompt int `json:"prompt"`
+
+	InputTokens         int64 `json:"inputTokens"`
+	OutputTokens        int64 `json:"outputTokens"`
+	CacheCreationTokens int64 `json:"cacheCreationTokens,omitempty"`
+	CacheReadTokens     int64 `json:"cacheReadTokens,omitempty"`
+
+	// ToolUses lists the tools called in this turn, in call order.
+	ToolUses []string `json:"toolUses,omitempty"`
+	// Subagents lists the subagent types launched in this turn.
+	Subagents []string `json:"subagents,omitempty"`
+	// AgentID is set when the turn ran inside a subagent sidechain.
+	AgentID string `json:"agentId,omitempty"`
+}
+
+// key identifies the message a turn belongs to, or "" if the transcript
+// line carried no message ID.
+func (t *Turn) key() string {
+	if t.MessageID == "" {
+		return ""
+	}
+	return t.MessageID + ":" + t.RequestID
+}
+
+// TranscriptCursor records how far a transcript has been parsed so a later
+// read can continue where the previous one stopped.
+type TranscriptCursor struct {
+	// Offset is the byte offset just past the last complete line read.
+	Offset int64 `json:"offset"`
+	// Prompts is the number of user prompts seen so far.
+	Prompts int `json:"prompts"`
+	// Usage holds running session totals with ParseTranscript semantics,
+	// so session hashes stay stable whichever path produced them.
+	Usage TranscriptUsage `json:"usage"`
+}
+
+// parseTranscriptFrom reads the transcript from cursor.Offset, advancing the
+// cursor and returning the turns found. A trailing line without a newline is
+// only consumed when partial is true; otherwise it is left for the next read
+// because Claude Code may still be writing it.
+func parseTranscriptFrom(transcriptPath string, cursor *TranscriptCursor, partial bool) ([]Turn, error) {
+	file, err := os.Open(transcriptPath)
+	if err != nil {
+		return nil, fmt.Errorf("open transcript: %w", err)
+	}
+	defer func() {
+		// Close errors are ignored for read-only files
+		_ = file.Close()
+	}()
+
+	if _, err := file.Seek(cursor.Offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("seek transcript: %w", err)
+	}
+
+	var turns []Turn
+	reader := bufio.NewReaderSize(file, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if partial && len(line) > 0 {
+				cursor.Offset += int64(len(line))
+				turns = cursor.apply(line, turns)
+			}
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read transcript: %w", err)
+		}
+		cursor.Offset += int64(len(line))
+		turns = cursor.apply(line, turns)
+	}
+
+	cursor.Usage.DurationSeconds = durationSeconds(cursor.Usage.StartedAt, cursor.Usage.EndedAt)
+	return turns, nil
+}
+
+// apply folds one transcript line into the cursor totals and appends the
+// turn it describes, if any.
+func (c *TranscriptCursor) apply(line []byte, turns []Turn) []Turn {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return turns
+	}
+
+	var msg transcriptMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		// Skip invalid lines
+		return turns
+	}
+
+	usage := &c.Usage
+	if msg.Timestamp != "" {
+		if usage.StartedAt == "" {
+			usage.StartedAt = msg.Timestamp
+		}
+		usage.EndedAt = msg.Timestamp
+	}
+
+	blocks := parseBlocks(msg.Message.Content)
+	if msg.Type == "user" {
+		usage.TurnCount++
+		if !msg.IsSidechain && isPrompt(msg.Message.Content, blocks) {
+			c.Prompts++
+		}
+	}
+
+	model := msg.Message.Model
+	if model == "" {
+		model = msg.Model
+	}
+	if usage.ModelName == "" {
+		usage.ModelName = model
+	}
+
+	u := msg.Message.Usage
+	if u == nil {
+		return turns
+	}
+	usage.InputTokens += u.InputTokens
+	usage.OutputTokens += u.OutputTokens
+	usage.CacheCreationTokens += u.CacheCreationInputTokens
+	usage.CacheReadTokens += u.CacheReadInputTokens
+
+	ts, _ := time.Parse(time.RFC3339Nano, msg.Timestamp)
+	turn := Turn{
+		Timestamp:           ts,
+		SessionID:           msg.SessionID,
+		CWD:                 msg.CWD,
+		Model:               model,
+		MessageID:           msg.Message.ID,
+		RequestID:           msg.RequestID,
+		Prompt:              c.Prompts,
+		InputTokens:         u.InputTokens,
+		OutputTokens:        u.OutputTokens,
+		CacheCreationTokens: u.CacheCreationInputTokens,
+		CacheReadTokens:     u.CacheReadInputTokens,
+	}
+	if msg.IsSidechain {
+		turn.AgentID = msg.AgentID
+	}
+	for _, b := range blocks {
+		if b.Type != "tool_use" || b.Name == "" {
+			continue
+		}
+		turn.ToolUses = append(turn.ToolUses, b.Name)
+		if taskToolNames[b.Name] && b.Input.SubagentType != "" {
+			turn.Subagents = append(turn.Subagents, b.Input.SubagentType)
+		}
+	}
+	return mergeTurn(turns, turn)
+}
+
+// mergeTurn appends t, or folds it into a recent turn for the same message:
+// usage and timestamp are taken from the later line and tool calls are
+// accumulated.
+func mergeTurn(turns []Turn, t Turn) []Turn {
+	if key := t.key(); key != "" {
+		for i := len(turns) - 1; i >= 0 && i >= len(turns)-turnMergeWindow; i-- {
+			prev := &turns[i]
+			if prev.key() != key {
+				continue
+			}
+			prev.Timestamp = t.Timestamp
+			prev.InputTokens = t.InputTokens
+			prev.OutputTokens = t.OutputTokens
+			prev.CacheCreationTokens = t.CacheCreationTokens
+			prev.CacheReadTokens = t.CacheReadTokens
+			prev.ToolUses = append(prev.ToolUses, t.ToolUses...)
+			prev.Subagents = append(prev.Subagents, t.Subagents...)
+			return turns
+		}
+	}
+	return append(turns, t)
+}
+
+// parseBlocks decodes structured message content. Plain string content
+// yields no blocks.
+func parseBlocks(content json.RawMessage) []transcriptBlock {
+	if len(content) == 0 || content[0] != '[' {
+		return nil
+	}
+	var blocks []transcriptBlock
+	if err := json.Unmarshal(content, &blocks); err != nil {
+		return nil
+	}
+	return blocks
+}
+
+// isPrompt reports whether user message content was typed by the user
+// rather than being a tool result fed back to the model.
+func isPrompt(content json.RawMessage, blocks []transcriptBlock) bool {
+	if len(content) > 0 && content[0] == '"' {
+		return true
+	}
+	if len(blocks) == 0 {
+		return false
+	}
+	for _, b := range blocks {
+		if b.Type == "tool_result" {
+			return false
+		}
+	}
+	return true
+}
+
+// durationSeconds returns the whole seconds between two RFC 3339
+// timestamps, or 0 if either is missing or invalid.
+func durationSeconds(startedAt, endedAt string) int64 {
+	if startedAt == "" || endedAt == "" {
+		return 0
+	}
+	start, err := time.Parse(time.RFC3339Nano, startedAt)
+	if err != nil {
+		return 0
+	}
+	end, err := time.Parse(time.RFC3339Nano, endedAt)
+	if err != nil {
+		return 0
+	}
+	return int64(end.Sub(start).Seconds())
+}
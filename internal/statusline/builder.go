@@ -5,10 +5,11 @@ import (
 	"encoding/json"
 	"io"
 	"log/slog"
+	"os"
 	"path/filepath"
 	"sync"
 
-	gitpkg "github.com/modu-ai/moai-adk/internal/core/git"
+	"github.com/modu-ai/moai-adk/internal/git/ops"
 	"github.com/modu-ai/moai-adk/pkg/version"
 )
 
@@ -17,6 +18,8 @@ import (
 type defaultBuilder struct {
 	gitProvider    GitDataProvider
 	updateProvider UpdateProvider
+	budgetProvider BudgetProvider
+	eventsProvider GitEventsProvider
 	renderer       *Renderer
 	mode           StatuslineMode
 	mu             sync.RWMutex
@@ -32,6 +35,12 @@ type Options struct {
 	// If nil, version will be read from config file automatically.
 	UpdateProvider UpdateProvider
 
+	// BudgetProvider reports spend against pricing budgets. May be nil to skip.
+	BudgetProvider BudgetProvider
+
+	// GitEventsProvider reports changes made outside Claude. May be nil to skip.
+	GitEventsProvider GitEventsProvider
+
 	// RootDir is the project root directory for auto-detecting git repo.
 	// If empty, current directory is used.
 	RootDir string
@@ -63,15 +72,20 @@ func New(opts Options) Builder {
 	gitProvider := opts.GitProvider
 	updateProvider := opts.UpdateProvider
 
-	// Auto-create git provider if not provided
+	// Auto-create git provider if not provided. Snapshots are cached on disk
+	// in MoAI projects so that repeated renders skip git while nothing changed.
 	if gitProvider == nil {
 		rootDir := opts.RootDir
 		if rootDir == "" {
 			rootDir = "."
 		}
-		if repo, err := gitpkg.NewRepository(rootDir); err == nil {
-			gitProvider = NewGitCollector(repo)
-			slog.Debug("auto-opened git repository for statusline", "root", repo.Root())
+		if _, err := ops.Fingerprint(rootDir); err == nil {
+			var cache *ops.SnapshotCache
+			if _, err := os.Stat(filepath.Join(rootDir, ".moai")); err == nil {
+				cache = ops.NewSnapshotCache(ops.SnapshotCachePath(rootDir), 0)
+			}
+			gitProvider = NewSnapshotCollector(ops.NewGitManager(ops.ManagerConfig{WorkDir: rootDir}), cache)
+			slog.Debug("created git snapshot collector for statusline", "root", rootDir)
 		}
 		// If git repo not found, continue without git provider
 	}
@@ -85,6 +99,8 @@ func New(opts Options) Builder {
 	return &defaultBuilder{
 		gitProvider:    gitProvider,
 		updateProvider: updateProvider,
+		budgetProvider: opts.BudgetProvider,
+		eventsProvider: opts.GitEventsProvider,
 		renderer:       NewRenderer(opts.ThemeName, opts.NoColor, opts.SegmentConfig),
 		mode:           mode,
 	}
@@ -172,6 +188,8 @@ func (b *defaultBuilder) collectAll(ctx context.Context, input *StdinData) *Stat
 	var wg sync.WaitGroup
 	var gitResult *GitStatusData
 	var versionResult *VersionData
+	var budgetResult *BudgetData
+	var eventsResult *GitEventsData
 
 	if b.gitProvider != nil {
 		wg.Go(func() {
@@ -195,6 +213,28 @@ func (b *defaultBuilder) collectAll(ctx context.Context, input *StdinData) *Stat
 		})
 	}
 
+	if b.budgetProvider != nil {
+		wg.Go(func() {
+			result, err := b.budgetProvider.CollectBudget(ctx)
+			if err != nil {
+				slog.Debug("budget collection failed", "error", err)
+				return
+			}
+			budgetResult = result
+		})
+	}
+
+	if b.eventsProvider != nil {
+		wg.Go(func() {
+			result, err := b.eventsProvider.CollectGitEvents(ctx)
+			if err != nil {
+				slog.Debug("git event collection failed", "error", err)
+				return
+			}
+			eventsResult = result
+		})
+	}
+
 	wg.Wait()
 
 	if gitResult != nil {
@@ -203,6 +243,12 @@ func (b *defaultBuilder) collectAll(ctx context.Context, input *StdinData) *Stat
 	if versionResult != nil {
 		data.Version = *versionResult
 	}
+	if budgetResult != nil {
+		data.Budget = *budgetResult
+	}
+	if eventsResult != nil {
+		data.GitEvents = *eventsResult
+	}
 
 	return data
 }
@@ -383,3 +383,43 @@ func TestBuilder_DefaultMode(t *testing.T) {
 		t.Error("should produce output with empty mode")
 	}
 }
+
+// mockBudgetProvider implements BudgetProvider for testing.
+type mockBudgetProvider struct {
+	data *BudgetData
+	err  error
+}
+
+func (m *mockBudgetProvider) CollectBudget(_ context.Context) (*BudgetData, error) {
+	return m.data, m.err
+}
+
+func TestBuilder_Build_Budget(t *testing.T) {
+	builder := New(Options{
+		GitProvider:    &mockGitProvider{data: &GitStatusData{}},
+		UpdateProvider: &mockUpdateProvider{data: &VersionData{}},
+		BudgetProvider: &mockBudgetProvider{
+			data: &BudgetData{Period: "day", SpentUSD: 3, LimitUSD: 10, Available: true},
+		},
+		NoColor: true,
+	})
+
+	got, err := builder.Build(context.Background(), makeStdinJSON(&StdinData{CWD: "/tmp/proj"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(got, "💰 $3.00/$10.00 today") {
+		t.Errorf("should contain budget segment, got %q", got)
+	}
+
+	failing := New(Options{
+		GitProvider:    &mockGitProvider{data: &GitStatusData{}},
+		UpdateProvider: &mockUpdateProvider{data: &VersionData{}},
+		BudgetProvider: &mockBudgetProvider{err: errors.New("boom")},
+		NoColor:        true,
+	})
+	got, _ = failing.Build(context.Background(), makeStdinJSON(&StdinData{CWD: "/tmp/proj"}))
+	if strings.Contains(got, "💰") {
+		t.Errorf("budget error should hide the segment, got %q", got)
+	}
+}
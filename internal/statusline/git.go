@@ -5,6 +5,7 @@ import (
 	"log/slog"
 
 	gitpkg "github.com/modu-ai/moai-adk/internal/core/git"
+	"github.com/modu-ai/moai-adk/internal/git/ops"
 )
 
 // gitCollector adapts a git.Repository to the GitDataProvider interface.
@@ -52,3 +53,36 @@ func (c *gitCollector) CollectGitStatus(ctx context.Context) (*GitStatusData, er
 
 	return data, nil
 }
+
+// snapshotCollector adapts an ops.GitManager to the GitDataProvider
+// interface, reusing snapshots cached on disk by earlier invocations.
+type snapshotCollector struct {
+	mgr   *ops.GitManager
+	cache *ops.SnapshotCache
+}
+
+// NewSnapshotCollector creates a GitDataProvider that collects status in
+// parallel through mgr. When cache is non-nil, a snapshot stored by a
+// previous statusline run is reused while the repository is unchanged.
+func NewSnapshotCollector(mgr *ops.GitManager, cache *ops.SnapshotCache) GitDataProvider {
+	return &snapshotCollector{mgr: mgr, cache: cache}
+}
+
+// CollectGitStatus returns the branch, working tree counts and ahead/behind
+// counts of the repository. It never returns an error.
+func (c *snapshotCollector) CollectGitStatus(_ context.Context) (*GitStatusData, error) {
+	if c.mgr == nil {
+		return &GitStatusData{Available: false}, nil
+	}
+	snap, hit := c.mgr.CachedSnapshot(c.cache)
+	slog.Debug("git snapshot collected", "cache_hit", hit)
+	return &GitStatusData{
+		Branch:    snap.Branch,
+		Modified:  snap.Modified,
+		Staged:    snap.Staged,
+		Untracked: snap.Untracked,
+		Ahead:     snap.Ahead,
+		Behind:    snap.Behind,
+		Available: true,
+	}, nil
+}
@@ -3,10 +3,14 @@ package statusline
 import (
 	"context"
 	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 
 	gitpkg "github.com/modu-ai/moai-adk/internal/core/git"
+	"github.com/modu-ai/moai-adk/internal/git/ops"
 )
 
 // mockGitRepo implements git.Repository for testing.
@@ -148,3 +152,46 @@ func TestGitCollector_CollectGitStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestSnapshotCollector_CollectGitStatus(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new.go"), []byte("package x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := ops.NewGitManager(ops.ManagerConfig{WorkDir: dir})
+	defer mgr.Shutdown()
+	cache := ops.NewSnapshotCache(filepath.Join(t.TempDir(), "snap.json"), time.Minute)
+	collector := NewSnapshotCollector(mgr, cache)
+
+	for range 2 {
+		got, err := collector.CollectGitStatus(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Available || got.Branch != "main" || got.Untracked != 1 {
+			t.Errorf("CollectGitStatus() = %+v", got)
+		}
+	}
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("cache stats = %+v, want the second render served from cache", stats)
+	}
+
+	got, _ := NewSnapshotCollector(nil, nil).CollectGitStatus(context.Background())
+	if got.Available {
+		t.Error("nil manager should report git unavailable")
+	}
+}
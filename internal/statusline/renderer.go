@@ -85,7 +85,7 @@ func (r *Renderer) isSegmentEnabled(key string) bool {
 }
 
 // renderCompact returns sections for compact mode with full emoji format.
-// Format: 🤖 Model | 🔋/🪫 Context Graph | 💬 Style | 📁 Directory | 📊 Changes | 🔅 Claude Code Ver | 🗿 MoAI Ver | 🔀 Branch
+// Format: 🤖 Model | 🔋/🪫 Context Graph | 💬 Style | 📁 Directory | 📊 Changes | 🔅 Claude Code Ver | 🗿 MoAI Ver | 🔀 Branch | 💰 Budget | 🛰️ Git events
 // Each segment is filtered by isSegmentEnabled() based on the segment config.
 func (r *Renderer) renderCompact(data *StatusData) []string {
 	var sections []string
@@ -138,9 +138,42 @@ func (r *Renderer) renderCompact(data *StatusData) []string {
 		sections = append(sections, fmt.Sprintf("🔀 %s", data.Git.Branch))
 	}
 
+	// 9. Budget spend (only when a pricing budget is configured)
+	if r.isSegmentEnabled(SegmentBudget) {
+		if budget := renderBudget(data.Budget); budget != "" {
+			sections = append(sections, budget)
+		}
+	}
+
+	// 10. Changes made outside Claude since the last turn
+	if r.isSegmentEnabled(SegmentGitEvents) && data.GitEvents.Available && data.GitEvents.Summary != "" {
+		sections = append(sections, fmt.Sprintf("🛰️ %s", data.GitEvents.Summary))
+	}
+
 	return sections
 }
 
+// renderBudget renders spend against the configured budget.
+// Format: 💰 $4.20/$10.00 today, with ⚠️ at the warning threshold and
+// 🚨 once the limit is reached.
+func renderBudget(b BudgetData) string {
+	if !b.Available || b.LimitUSD <= 0 {
+		return ""
+	}
+	icon := "💰"
+	switch b.Level {
+	case BudgetWarn:
+		icon = "⚠️"
+	case BudgetExceeded:
+		icon = "🚨"
+	}
+	period := "today"
+	if b.Period == "month" {
+		period = "this month"
+	}
+	return fmt.Sprintf("%s %s/%s %s", icon, formatCost(b.SpentUSD), formatCost(b.LimitUSD), period)
+}
+
 // renderMinimal returns sections for minimal mode: model + context graph only.
 // Format: 🤖 Model | 🔋/🪫 Context Graph
 func (r *Renderer) renderMinimal(data *StatusData) []string {
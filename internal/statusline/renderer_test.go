@@ -517,3 +517,49 @@ func TestIsSegmentEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestRender_Budget(t *testing.T) {
+	tests := []struct {
+		name   string
+		budget BudgetData
+		want   string
+	}{
+		{"unavailable", BudgetData{}, ""},
+		{"ok daily", BudgetData{Period: "day", SpentUSD: 4.2, LimitUSD: 10, Available: true}, "💰 $4.20/$10.00 today"},
+		{"warn monthly", BudgetData{Period: "month", SpentUSD: 85, LimitUSD: 100, Level: BudgetWarn, Available: true}, "⚠️ $85.00/$100.00 this month"},
+		{"exceeded", BudgetData{Period: "day", SpentUSD: 12, LimitUSD: 10, Level: BudgetExceeded, Available: true}, "🚨 $12.00/$10.00 today"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRenderer()
+			got := r.Render(&StatusData{Directory: "proj", Budget: tt.budget}, ModeDefault)
+			if tt.want == "" {
+				if strings.Contains(got, "$") {
+					t.Errorf("budget should be hidden, got %q", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("got %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+
+	disabled := NewRenderer("default", true, map[string]bool{SegmentBudget: false})
+	got := disabled.Render(&StatusData{Budget: BudgetData{Period: "day", SpentUSD: 1, LimitUSD: 2, Available: true}}, ModeDefault)
+	if strings.Contains(got, "$") {
+		t.Errorf("disabled budget segment rendered: %q", got)
+	}
+}
+
+func TestRender_GitEvents(t *testing.T) {
+	data := &StatusData{Directory: "proj", GitEvents: GitEventsData{Summary: "→main, +2 commits", Available: true}}
+	if got := newTestRenderer().Render(data, ModeDefault); !strings.Contains(got, "🛰️ →main, +2 commits") {
+		t.Errorf("got %q, want git events section", got)
+	}
+
+	disabled := NewRenderer("default", true, map[string]bool{SegmentGitEvents: false})
+	if got := disabled.Render(data, ModeDefault); strings.Contains(got, "🛰️") {
+		t.Errorf("disabled git events segment rendered: %q", got)
+	}
+}
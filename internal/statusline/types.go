@@ -104,6 +104,8 @@ type StatusData struct {
 	ClaudeCodeVersion string      // Claude Code version from JSON input (e.g., "1.0.80")
 	Directory         string      // Project directory name (e.g., "modu-saju")
 	OutputStyle       string      // Output style name (e.g., "Mr.Alfred", "R2-D2")
+	Budget            BudgetData  // Spend against configured pricing budgets
+	GitEvents         GitEventsData
 }
 
 // GitStatusData holds git repository status information.
@@ -131,6 +133,31 @@ type MetricsData struct {
 	Available bool
 }
 
+// BudgetLevel is the severity of budget spend.
+type BudgetLevel int
+
+const (
+	BudgetOK       BudgetLevel = iota // below the warning threshold
+	BudgetWarn                        // at or above the warning threshold
+	BudgetExceeded                    // at or above the limit
+)
+
+// BudgetData holds spend against the most severe configured budget.
+type BudgetData struct {
+	Period    string // "day" or "month"
+	SpentUSD  float64
+	LimitUSD  float64
+	Level     BudgetLevel
+	Available bool
+}
+
+// GitEventsData holds the repository changes made outside Claude since
+// the last turn, as recorded by the git watcher.
+type GitEventsData struct {
+	Summary   string // e.g. "→main, +2 commits"
+	Available bool
+}
+
 // VersionData holds version and update information.
 type VersionData struct {
 	Current         string
@@ -149,6 +176,8 @@ const (
 	SegmentClaudeVersion = "claude_version"
 	SegmentMoaiVersion   = "moai_version"
 	SegmentGitBranch     = "git_branch"
+	SegmentBudget        = "budget"
+	SegmentGitEvents     = "git_events"
 )
 
 // contextLevel represents the severity level for context window usage coloring.
@@ -174,6 +203,20 @@ type UpdateProvider interface {
 	CheckUpdate(ctx context.Context) (*VersionData, error)
 }
 
+// BudgetProvider abstracts budget spend collection for testability.
+type BudgetProvider interface {
+	// CollectBudget returns spend against the configured budget.
+	// Returns nil (not error) when no budget is configured.
+	CollectBudget(ctx context.Context) (*BudgetData, error)
+}
+
+// GitEventsProvider abstracts git watcher event collection for testability.
+type GitEventsProvider interface {
+	// CollectGitEvents returns the changes recorded since the last turn.
+	// Returns nil (not error) when nothing changed.
+	CollectGitEvents(ctx context.Context) (*GitEventsData, error)
+}
+
 // Builder composes the statusline output from collected data.
 type Builder interface {
 	// Build generates the formatted statusline string from the given input.
@@ -0,0 +1,52 @@
+package tmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// worktreePaneOption is the pane user option linking a pane to a worktree.
+const worktreePaneOption = "@moai_worktree"
+
+// LinkPane records dir as the worktree of pane, so CloseLinkedPanes can
+// find the pane once the worktree is done.
+func (m *DefaultSessionManager) LinkPane(ctx context.Context, pane, dir string) error {
+	if _, err := m.run(ctx, "tmux", "set-option", "-p", "-t", pane, worktreePaneOption, filepath.Clean(dir)); err != nil {
+		return fmt.Errorf("link pane %s: %w", pane, err)
+	}
+	return nil
+}
+
+// CloseLinkedPanes kills the tmux panes linked to the worktree dir by
+// LinkPane. The pane running the caller ($TMUX_PANE) is left alone.
+// Without tmux or a running server there is nothing to close. It returns
+// the number of panes closed.
+func (m *DefaultSessionManager) CloseLinkedPanes(ctx context.Context, dir string) (int, error) {
+	out, err := m.run(ctx, "tmux", "list-panes", "-a", "-F", "#{pane_id}\t#{"+worktreePaneOption+"}")
+	if err != nil {
+		if errors.Is(err, ErrTmuxNotFound) || strings.Contains(err.Error(), "no server running") {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("list panes: %w", err)
+	}
+
+	dir = filepath.Clean(dir)
+	self := os.Getenv("TMUX_PANE")
+	closed := 0
+	for line := range strings.SplitSeq(strings.TrimSpace(out), "\n") {
+		id, linked, ok := strings.Cut(line, "\t")
+		if !ok || id == self || linked != dir {
+			continue
+		}
+		if _, err := m.run(ctx, "tmux", "kill-pane", "-t", id); err != nil {
+			m.logger.Warn("failed to close pane", "pane", id, "error", err)
+			continue
+		}
+		closed++
+	}
+	return closed, nil
+}
@@ -0,0 +1,76 @@
+package tmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestSessionManager_LinkPane(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	runner := func(_ context.Context, name string, args ...string) (string, error) {
+		got = append([]string{name}, args...)
+		return "", nil
+	}
+	mgr := NewSessionManager(WithSessionRunFunc(runner))
+	if err := mgr.LinkPane(context.Background(), "%2", "/repo/.moai/worktrees/SPEC-AUTH-001/"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"tmux", "set-option", "-p", "-t", "%2", "@moai_worktree", "/repo/.moai/worktrees/SPEC-AUTH-001"}
+	if !slices.Equal(got, want) {
+		t.Errorf("command = %v, want %v", got, want)
+	}
+}
+
+func TestSessionManager_CloseLinkedPanes(t *testing.T) {
+	t.Setenv("TMUX_PANE", "%4")
+
+	var killed []string
+	runner := func(_ context.Context, name string, args ...string) (string, error) {
+		switch args[0] {
+		case "list-panes":
+			return "%1\t\n" +
+				"%2\t/repo/.moai/worktrees/SPEC-AUTH-001\n" +
+				"%3\t/repo/.moai/worktrees/SPEC-AUTH-001/internal\n" +
+				"%4\t/repo/.moai/worktrees/SPEC-AUTH-001\n" +
+				"%5\t/repo/.moai/worktrees/SPEC-AUTH-0010\n", nil
+		case "kill-pane":
+			killed = append(killed, args[2])
+		}
+		return "", nil
+	}
+
+	mgr := NewSessionManager(WithSessionRunFunc(runner))
+	n, err := mgr.CloseLinkedPanes(context.Background(), "/repo/.moai/worktrees/SPEC-AUTH-001")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("closed = %d, want 1", n)
+	}
+	// %1 is not linked, %3 and %5 are linked elsewhere and %4 runs the caller.
+	if !slices.Equal(killed, []string{"%2"}) {
+		t.Errorf("killed = %v, want [%%2]", killed)
+	}
+}
+
+func TestSessionManager_CloseLinkedPanes_NoServer(t *testing.T) {
+	t.Parallel()
+
+	for _, runErr := range []error{
+		fmt.Errorf("tmux: %w", ErrTmuxNotFound),
+		errors.New("no server running on /tmp/tmux-0/default"),
+	} {
+		runner := func(context.Context, string, ...string) (string, error) {
+			return "", runErr
+		}
+		n, err := NewSessionManager(WithSessionRunFunc(runner)).CloseLinkedPanes(context.Background(), "/repo")
+		if err != nil || n != 0 {
+			t.Errorf("CloseLinkedPanes with %v = (%d, %v), want (0, nil)", runErr, n, err)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Channel selects which releases are eligible for installation.
+type Channel string
+
+const (
+	// ChannelStable only considers final releases (no pre-release tag).
+	ChannelStable Channel = "stable"
+
+	// ChannelBeta additionally considers alpha, beta and rc pre-releases.
+	ChannelBeta Channel = "beta"
+
+	// ChannelNightly considers every published release, including nightly
+	// and dev builds.
+	ChannelNightly Channel = "nightly"
+)
+
+// DefaultChannel is used when no channel is configured.
+const DefaultChannel = ChannelStable
+
+// betaPreReleaseTags lists the pre-release identifiers accepted on the beta channel.
+var betaPreReleaseTags = []string{"alpha", "beta", "rc"}
+
+// ParseChannel converts a configuration value into a Channel.
+// An empty string yields DefaultChannel.
+func ParseChannel(s string) (Channel, error) {
+	switch Channel(strings.ToLower(strings.TrimSpace(s))) {
+	case "":
+		return DefaultChannel, nil
+	case ChannelStable:
+		return ChannelStable, nil
+	case ChannelBeta:
+		return ChannelBeta, nil
+	case ChannelNightly:
+		return ChannelNightly, nil
+	}
+	return "", fmt.Errorf("update: unknown channel %q (want stable, beta or nightly)", s)
+}
+
+// Includes reports whether a release with the given version belongs to the channel.
+func (c Channel) Includes(v Semver) bool {
+	if !v.IsPreRelease() {
+		return true
+	}
+	switch c {
+	case ChannelNightly:
+		return true
+	case ChannelBeta:
+		tag := strings.ToLower(v.PreRelease[0])
+		for _, prefix := range betaPreReleaseTags {
+			if strings.HasPrefix(tag, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReleaseLister enumerates published releases. It is implemented by the
+// GitHub and local checkers and consumed by channel-aware update flows.
+type ReleaseLister interface {
+	// ListReleases returns all published releases, newest first.
+	// Checksums are not resolved for list entries.
+	ListReleases(ctx context.Context) ([]VersionInfo, error)
+
+	// ResolveRelease returns complete metadata, including the platform
+	// download URL and checksum, for a single version.
+	ResolveRelease(ctx context.Context, version string) (*VersionInfo, error)
+}
+
+// FilterReleases returns the releases that belong to channel and satisfy
+// constraint (nil means unconstrained), sorted newest first. Releases whose
+// tag is not valid semver are dropped.
+func FilterReleases(releases []VersionInfo, channel Channel, constraint *Constraint) []VersionInfo {
+	type parsed struct {
+		info VersionInfo
+		ver  Semver
+	}
+	var kept []parsed
+	for _, r := range releases {
+		v, err := ParseSemver(r.Version)
+		if err != nil || !channel.Includes(v) {
+			continue
+		}
+		if constraint != nil && !constraint.Check(v) {
+			continue
+		}
+		kept = append(kept, parsed{info: r, ver: v})
+	}
+
+	slices.SortStableFunc(kept, func(a, b parsed) int {
+		return b.ver.Compare(a.ver)
+	})
+
+	result := make([]VersionInfo, len(kept))
+	for i, p := range kept {
+		result[i] = p.info
+	}
+	return result
+}
+
+// FindRelease returns the release whose version matches v, ignoring any
+// "v"/"go-v" tag prefix. Returns nil when no release matches.
+func FindRelease(releases []VersionInfo, v string) *VersionInfo {
+	want, err := ParseSemver(v)
+	if err != nil {
+		return nil
+	}
+	for i := range releases {
+		got, err := ParseSemver(releases[i].Version)
+		if err == nil && got.Compare(want) == 0 && got.Build == want.Build {
+			return &releases[i]
+		}
+	}
+	return nil
+}
+
+// channelChecker implements Checker by selecting the newest release on a
+// channel that also satisfies an optional version constraint.
+type channelChecker struct {
+	lister     ReleaseLister
+	channel    Channel
+	constraint *Constraint
+}
+
+// NewChannelChecker creates a Checker restricted to the given channel and
+// constraint. A nil constraint allows every version on the channel.
+func NewChannelChecker(lister ReleaseLister, channel Channel, constraint *Constraint) Checker {
+	if channel == "" {
+		channel = DefaultChannel
+	}
+	return &channelChecker{
+		lister:     lister,
+		channel:    channel,
+		constraint: constraint,
+	}
+}
+
+// CheckLatest returns the newest eligible release with its checksum resolved.
+func (c *channelChecker) CheckLatest(ctx context.Context) (*VersionInfo, error) {
+	releases, err := c.lister.ListReleases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("channel checker: %w", err)
+	}
+
+	eligible := FilterReleases(releases, c.channel, c.constraint)
+	if len(eligible) == 0 {
+		if c.constraint != nil {
+			return nil, fmt.Errorf("channel checker: no %s release satisfies %q", c.channel, c.constraint)
+		}
+		return nil, fmt.Errorf("channel checker: no %s release found", c.channel)
+	}
+
+	info, err := c.lister.ResolveRelease(ctx, eligible[0].Version)
+	if err != nil {
+		return nil, fmt.Errorf("channel checker: %w", err)
+	}
+	return info, nil
+}
+
+// IsUpdateAvailable reports whether the newest eligible release is newer than current.
+func (c *channelChecker) IsUpdateAvailable(current string) (bool, *VersionInfo, error) {
+	info, err := c.CheckLatest(context.Background())
+	if err != nil {
+		return false, nil, err
+	}
+	if compareSemver(info.Version, current) <= 0 {
+		return false, nil, nil
+	}
+	return true, info, nil
+}
+
+// pinnedChecker implements Checker for an explicitly chosen release.
+// It reports an update whenever the pinned version differs from the
+// current one, which lets the Orchestrator perform downgrades.
+type pinnedChecker struct {
+	info *VersionInfo
+}
+
+// NewPinnedChecker creates a Checker that always targets info. Combined with
+// NewOrchestrator it installs a specific version, older or newer.
+func NewPinnedChecker(info *VersionInfo) Checker {
+	return &pinnedChecker{info: info}
+}
+
+// CheckLatest returns the pinned release.
+func (c *pinnedChecker) CheckLatest(_ context.Context) (*VersionInfo, error) {
+	return c.info, nil
+}
+
+// IsUpdateAvailable returns true when current differs from the pinned version.
+func (c *pinnedChecker) IsUpdateAvailable(current string) (bool, *VersionInfo, error) {
+	if compareSemver(c.info.Version, current) == 0 {
+		return false, nil, nil
+	}
+	return true, c.info, nil
+}
@@ -0,0 +1,253 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeLister is an in-memory ReleaseLister for channel tests.
+type fakeLister struct {
+	releases []VersionInfo
+	listErr  error
+	resolved []string
+}
+
+func (f *fakeLister) ListReleases(_ context.Context) ([]VersionInfo, error) {
+	return f.releases, f.listErr
+}
+
+func (f *fakeLister) ResolveRelease(_ context.Context, version string) (*VersionInfo, error) {
+	f.resolved = append(f.resolved, version)
+	if r := FindRelease(f.releases, version); r != nil {
+		out := *r
+		out.URL = "https://example.com/" + r.Version
+		return &out, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func testReleases() []VersionInfo {
+	return []VersionInfo{
+		{Version: "v2.1.0"},
+		{Version: "v2.2.0-beta.1"},
+		{Version: "v2.2.0-nightly.20260301"},
+		{Version: "v2.0.5"},
+		{Version: "v3.0.0-rc.1"},
+		{Version: "not-a-version"},
+	}
+}
+
+func TestParseChannel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		want    Channel
+		wantErr bool
+	}{
+		{"", ChannelStable, false},
+		{"stable", ChannelStable, false},
+		{"Beta", ChannelBeta, false},
+		{" nightly ", ChannelNightly, false},
+		{"edge", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseChannel(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseChannel(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseChannel(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFilterReleases_ByChannel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		channel Channel
+		want    []string
+	}{
+		{ChannelStable, []string{"v2.1.0", "v2.0.5"}},
+		{ChannelBeta, []string{"v3.0.0-rc.1", "v2.2.0-beta.1", "v2.1.0", "v2.0.5"}},
+		{ChannelNightly, []string{"v3.0.0-rc.1", "v2.2.0-nightly.20260301", "v2.2.0-beta.1", "v2.1.0", "v2.0.5"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.channel), func(t *testing.T) {
+			t.Parallel()
+			got := FilterReleases(testReleases(), tt.channel, nil)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d releases, want %d: %v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i].Version != tt.want[i] {
+					t.Errorf("release[%d] = %q, want %q", i, got[i].Version, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFilterReleases_WithConstraint(t *testing.T) {
+	t.Parallel()
+
+	c, err := ParseConstraint("~2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := FilterReleases(testReleases(), ChannelNightly, c)
+	if len(got) != 1 || got[0].Version != "v2.0.5" {
+		t.Errorf("FilterReleases with ~2.0 = %v, want [v2.0.5]", got)
+	}
+}
+
+func TestFindRelease(t *testing.T) {
+	t.Parallel()
+
+	if r := FindRelease(testReleases(), "2.2.0-beta.1"); r == nil || r.Version != "v2.2.0-beta.1" {
+		t.Errorf("FindRelease(2.2.0-beta.1) = %v", r)
+	}
+	if r := FindRelease(testReleases(), "9.9.9"); r != nil {
+		t.Errorf("FindRelease(9.9.9) = %v, want nil", r)
+	}
+	if r := FindRelease(testReleases(), "garbage"); r != nil {
+		t.Errorf("FindRelease(garbage) = %v, want nil", r)
+	}
+}
+
+func TestChannelChecker_CheckLatest(t *testing.T) {
+	t.Parallel()
+
+	lister := &fakeLister{releases: testReleases()}
+	c := NewChannelChecker(lister, ChannelBeta, nil)
+
+	info, err := c.CheckLatest(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLatest: %v", err)
+	}
+	if info.Version != "v3.0.0-rc.1" {
+		t.Errorf("Version = %q, want v3.0.0-rc.1", info.Version)
+	}
+	if info.URL == "" {
+		t.Error("expected resolved URL")
+	}
+}
+
+func TestChannelChecker_RespectsConstraint(t *testing.T) {
+	t.Parallel()
+
+	constraint, _ := ParseConstraint("<3.0.0")
+	c := NewChannelChecker(&fakeLister{releases: testReleases()}, ChannelBeta, constraint)
+
+	available, info, err := c.IsUpdateAvailable("v2.1.0")
+	if err != nil {
+		t.Fatalf("IsUpdateAvailable: %v", err)
+	}
+	if !available || info.Version != "v2.2.0-beta.1" {
+		t.Errorf("got (%v, %v), want update to v2.2.0-beta.1", available, info)
+	}
+
+	available, _, err = c.IsUpdateAvailable("v2.2.0")
+	if err != nil {
+		t.Fatalf("IsUpdateAvailable: %v", err)
+	}
+	if available {
+		t.Error("2.2.0 is newer than 2.2.0-beta.1; no update expected")
+	}
+}
+
+func TestChannelChecker_NoEligibleRelease(t *testing.T) {
+	t.Parallel()
+
+	constraint, _ := ParseConstraint(">=9.0.0")
+	c := NewChannelChecker(&fakeLister{releases: testReleases()}, "", constraint)
+	_, err := c.CheckLatest(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "satisfies") {
+		t.Errorf("expected constraint error, got %v", err)
+	}
+}
+
+func TestChannelChecker_ListError(t *testing.T) {
+	t.Parallel()
+
+	c := NewChannelChecker(&fakeLister{listErr: errors.New("offline")}, ChannelStable, nil)
+	if _, _, err := c.IsUpdateAvailable("1.0.0"); err == nil {
+		t.Error("expected error from lister")
+	}
+}
+
+func TestPinnedChecker_AllowsDowngrade(t *testing.T) {
+	t.Parallel()
+
+	c := NewPinnedChecker(&VersionInfo{Version: "v2.0.5"})
+
+	available, info, err := c.IsUpdateAvailable("v2.1.0")
+	if err != nil || !available || info.Version != "v2.0.5" {
+		t.Errorf("downgrade: got (%v, %v, %v)", available, info, err)
+	}
+
+	available, _, err = c.IsUpdateAvailable("2.0.5")
+	if err != nil || available {
+		t.Errorf("same version: got (%v, %v)", available, err)
+	}
+}
+
+func TestChecker_ListReleasesAndResolve(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		releases := []githubRelease{
+			{TagName: "v2.0.0", PublishedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{TagName: "v2.1.0-beta.1"},
+			{TagName: "python-legacy"},
+			{TagName: "v2.1.0", Assets: []githubAsset{{Name: "unrelated.txt", BrowserDownloadURL: "https://example.com/x"}}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(releases)
+	}))
+	defer ts.Close()
+
+	c := NewChecker(ts.URL+"/repos/o/r/releases/latest", http.DefaultClient)
+	lister, ok := c.(ReleaseLister)
+	if !ok {
+		t.Fatal("checker should implement ReleaseLister")
+	}
+
+	releases, err := lister.ListReleases(context.Background())
+	if err != nil {
+		t.Fatalf("ListReleases: %v", err)
+	}
+	want := []string{"v2.1.0", "v2.1.0-beta.1", "v2.0.0"}
+	if len(releases) != len(want) {
+		t.Fatalf("got %d releases, want %d", len(releases), len(want))
+	}
+	for i := range want {
+		if releases[i].Version != want[i] {
+			t.Errorf("releases[%d] = %q, want %q", i, releases[i].Version, want[i])
+		}
+	}
+	if paths[0] != "/repos/o/r/releases" {
+		t.Errorf("list path = %q, want /repos/o/r/releases", paths[0])
+	}
+
+	info, err := lister.ResolveRelease(context.Background(), "2.0.0")
+	if err != nil {
+		t.Fatalf("ResolveRelease: %v", err)
+	}
+	if info.Version != "v2.0.0" {
+		t.Errorf("resolved Version = %q", info.Version)
+	}
+	if _, err := lister.ResolveRelease(context.Background(), "9.9.9"); err == nil {
+		t.Error("expected not-found error")
+	}
+}
@@ -105,6 +105,80 @@ func (c *checker) CheckLatest(ctx context.Context) (*VersionInfo, error) {
 	return c.buildVersionInfo(release), nil
 }
 
+// releasesListURL derives the releases collection endpoint from the
+// configured API URL ("/releases/latest" -> "/releases").
+func (c *checker) releasesListURL() string {
+	base := strings.TrimSuffix(c.apiURL, "/latest")
+	if strings.Contains(base, "?") {
+		return base
+	}
+	return base + "?per_page=100"
+}
+
+// fetchReleases downloads the release collection from the GitHub API.
+func (c *checker) fetchReleases(ctx context.Context) ([]releaseResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.releasesListURL(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("checker: create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", "moai-adk-updater")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("checker: request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("checker: unexpected status %d", resp.StatusCode)
+	}
+
+	var releases []releaseResponse
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("checker: decode releases array: %w", err)
+	}
+	return releases, nil
+}
+
+// ListReleases returns all published releases with semver tags, newest first.
+// Checksums are not downloaded; use ResolveRelease for an installable entry.
+func (c *checker) ListReleases(ctx context.Context) ([]VersionInfo, error) {
+	releases, err := c.fetchReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]VersionInfo, 0, len(releases))
+	for _, r := range releases {
+		if _, err := ParseSemver(r.TagName); err != nil {
+			continue
+		}
+		infos = append(infos, VersionInfo{Version: r.TagName, Date: r.PublishedAt})
+	}
+	return FilterReleases(infos, ChannelNightly, nil), nil
+}
+
+// ResolveRelease returns the platform archive URL and checksum for version.
+func (c *checker) ResolveRelease(ctx context.Context, version string) (*VersionInfo, error) {
+	releases, err := c.fetchReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	want, err := ParseSemver(version)
+	if err != nil {
+		return nil, fmt.Errorf("checker: %w", err)
+	}
+	for _, r := range releases {
+		got, err := ParseSemver(r.TagName)
+		if err == nil && got.Compare(want) == 0 {
+			return c.buildVersionInfo(r), nil
+		}
+	}
+	return nil, fmt.Errorf("checker: release %s not found", version)
+}
+
 // buildVersionInfo constructs a VersionInfo from a releaseResponse.
 func (c *checker) buildVersionInfo(release releaseResponse) *VersionInfo {
 	info := &VersionInfo{
@@ -219,16 +293,18 @@ func (c *checker) IsUpdateAvailable(current string) (bool, *VersionInfo, error)
 
 // compareSemver compares two semantic version strings.
 // Returns -1 if a < b, 0 if a == b, 1 if a > b.
-// Handles optional "go-v" and "v" prefixes.
+// Handles optional "go-v" and "v" prefixes. Pre-release versions sort
+// before their release (1.2.0-beta.1 < 1.2.0). Strings that are not valid
+// semver fall back to a lenient numeric comparison of major.minor.patch.
 func compareSemver(a, b string) int {
-	// Handle go-v prefix for Go edition releases
-	a = strings.TrimPrefix(a, "go-v")
-	a = strings.TrimPrefix(a, "v")
-	b = strings.TrimPrefix(b, "go-v")
-	b = strings.TrimPrefix(b, "v")
-
-	aParts := parseSemverParts(a)
-	bParts := parseSemverParts(b)
+	av, aErr := ParseSemver(a)
+	bv, bErr := ParseSemver(b)
+	if aErr == nil && bErr == nil {
+		return av.Compare(bv)
+	}
+
+	aParts := parseSemverParts(normalizeVersion(a))
+	bParts := parseSemverParts(normalizeVersion(b))
 
 	for i := range 3 {
 		if aParts[i] > bParts[i] {
@@ -235,6 +235,10 @@ func TestCompareSemver(t *testing.T) {
 		{"go-v prefix b newer", "go-v1.0.0", "go-v2.0.0", -1},
 		{"go-v vs v prefix", "go-v1.0.0", "v1.0.0", 0},
 		{"go-v vs no prefix", "go-v1.0.0", "1.0.0", 0},
+		{"pre-release before release", "v1.0.0-beta.1", "v1.0.0", -1},
+		{"release after pre-release", "v1.0.0", "v1.0.0-rc.1", 1},
+		{"pre-release numeric order", "v1.0.0-beta.11", "v1.0.0-beta.2", 1},
+		{"non-semver falls back", "1.abc", "1.0.0", 0},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,230 @@
+package update
+
+import (
+	"fmt"
+	"strings"
+)
+
+// constraintOp is a comparison operator in a version constraint clause.
+type constraintOp string
+
+const (
+	opEQ constraintOp = "="
+	opNE constraintOp = "!="
+	opGT constraintOp = ">"
+	opGE constraintOp = ">="
+	opLT constraintOp = "<"
+	opLE constraintOp = "<="
+)
+
+// constraintClause is a single "<op> <version>" comparison.
+type constraintClause struct {
+	op  constraintOp
+	ver Semver
+}
+
+// Constraint is a parsed version requirement such as ">=2.1.0, <3.0.0".
+//
+// Supported syntax:
+//   - Comparisons: "=2.1.0", "!=2.1.3", ">2.0", ">=2.1.0", "<3", "<=2.4.1"
+//   - A bare version is an exact match: "2.1.0"
+//   - Tilde ranges allow patch updates: "~2.1" or "~2.1.0" means >=2.1.0, <2.2.0
+//   - Caret ranges allow minor updates: "^2.1.0" means >=2.1.0, <3.0.0
+//   - Wildcards: "2.x", "2.1.x", "*"
+//   - Clauses separated by commas or spaces are ANDed; "||" separates
+//     alternatives that are ORed.
+//
+// Upper bounds exclude pre-releases of the bound itself, so "<3.0.0" and
+// "^2.1.0" do not match 3.0.0-rc.1.
+type Constraint struct {
+	raw  string
+	alts [][]constraintClause
+}
+
+// ParseConstraint parses a version constraint expression.
+func ParseConstraint(expr string) (*Constraint, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("update: empty version constraint")
+	}
+
+	c := &Constraint{raw: expr}
+	for alt := range strings.SplitSeq(expr, "||") {
+		fields := strings.FieldsFunc(alt, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("update: invalid version constraint %q: empty alternative", expr)
+		}
+		var clauses []constraintClause
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+			// Allow a space between operator and version (">= 2.1.0").
+			if isBareOperator(field) && i+1 < len(fields) {
+				field += fields[i+1]
+				i++
+			}
+			parsed, err := parseConstraintTerm(field)
+			if err != nil {
+				return nil, fmt.Errorf("update: invalid version constraint %q: %w", expr, err)
+			}
+			clauses = append(clauses, parsed...)
+		}
+		c.alts = append(c.alts, clauses)
+	}
+	return c, nil
+}
+
+// Check reports whether v satisfies the constraint.
+func (c *Constraint) Check(v Semver) bool {
+	for _, clauses := range c.alts {
+		ok := true
+		for _, cl := range clauses {
+			if !cl.matches(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckString parses v and reports whether it satisfies the constraint.
+// Unparseable versions never satisfy a constraint.
+func (c *Constraint) CheckString(v string) bool {
+	parsed, err := ParseSemver(v)
+	if err != nil {
+		return false
+	}
+	return c.Check(parsed)
+}
+
+// String returns the original constraint expression.
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+func (cl constraintClause) matches(v Semver) bool {
+	cmp := v.Compare(cl.ver)
+	switch cl.op {
+	case opEQ:
+		return cmp == 0
+	case opNE:
+		return cmp != 0
+	case opGT:
+		return cmp > 0
+	case opGE:
+		return cmp >= 0
+	case opLT:
+		// "<3.0.0" should not admit 3.0.0-rc.1: pre-releases of an upper
+		// bound belong to the excluded release line.
+		if v.IsPreRelease() && !cl.ver.IsPreRelease() &&
+			v.Major == cl.ver.Major && v.Minor == cl.ver.Minor && v.Patch == cl.ver.Patch {
+			return false
+		}
+		return cmp < 0
+	case opLE:
+		return cmp <= 0
+	}
+	return false
+}
+
+func isBareOperator(s string) bool {
+	switch s {
+	case "=", "!=", ">", ">=", "<", "<=", "~", "^":
+		return true
+	}
+	return false
+}
+
+// parseConstraintTerm expands one term into one or two comparison clauses.
+func parseConstraintTerm(term string) ([]constraintClause, error) {
+	switch {
+	case term == "*" || term == "x" || term == "X":
+		return []constraintClause{{op: opGE, ver: Semver{}}}, nil
+	case strings.HasPrefix(term, "~"):
+		lo, parts, err := parsePartial(term[1:])
+		if err != nil {
+			return nil, err
+		}
+		hi := Semver{Major: lo.Major + 1}
+		if parts >= 2 {
+			hi = Semver{Major: lo.Major, Minor: lo.Minor + 1}
+		}
+		return rangeClauses(lo, hi), nil
+	case strings.HasPrefix(term, "^"):
+		lo, _, err := parsePartial(term[1:])
+		if err != nil {
+			return nil, err
+		}
+		hi := Semver{Major: lo.Major + 1}
+		if lo.Major == 0 {
+			hi = Semver{Minor: lo.Minor + 1}
+		}
+		return rangeClauses(lo, hi), nil
+	}
+
+	for _, op := range []constraintOp{opGE, opLE, opNE, opGT, opLT, opEQ} {
+		if rest, ok := strings.CutPrefix(term, string(op)); ok {
+			v, err := ParseSemver(rest)
+			if err != nil {
+				return nil, err
+			}
+			return []constraintClause{{op: op, ver: v}}, nil
+		}
+	}
+
+	// Bare version, possibly with wildcards: "2.1.0", "2.1", "2.x".
+	lo, parts, err := parsePartial(term)
+	if err != nil {
+		return nil, err
+	}
+	switch parts {
+	case 1:
+		return rangeClauses(lo, Semver{Major: lo.Major + 1}), nil
+	case 2:
+		return rangeClauses(lo, Semver{Major: lo.Major, Minor: lo.Minor + 1}), nil
+	}
+	return []constraintClause{{op: opEQ, ver: lo}}, nil
+}
+
+// parsePartial parses a possibly incomplete version ("2", "2.1", "2.1.x")
+// and returns it together with the number of concrete numeric components.
+func parsePartial(s string) (Semver, int, error) {
+	s = normalizeVersion(s)
+	core, suffix := s, ""
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		core, suffix = s[:i], s[i:]
+	}
+
+	segments := strings.Split(core, ".")
+	concrete := 0
+	for _, seg := range segments {
+		if seg == "x" || seg == "X" || seg == "*" {
+			break
+		}
+		concrete++
+	}
+	if concrete == 0 {
+		return Semver{}, 0, fmt.Errorf("version %q has no numeric component", s)
+	}
+	if concrete < len(segments) && suffix != "" {
+		return Semver{}, 0, fmt.Errorf("version %q mixes wildcards and pre-release tags", s)
+	}
+
+	v, err := ParseSemver(strings.Join(segments[:concrete], ".") + suffix)
+	if err != nil {
+		return Semver{}, 0, err
+	}
+	return v, concrete, nil
+}
+
+func rangeClauses(lo, hi Semver) []constraintClause {
+	return []constraintClause{
+		{op: opGE, ver: lo},
+		{op: opLT, ver: hi},
+	}
+}
@@ -0,0 +1,84 @@
+package update
+
+import "testing"
+
+func TestParseConstraint_Check(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr    string
+		version string
+		want    bool
+	}{
+		{">=2.1.0", "2.1.0", true},
+		{">=2.1.0", "2.0.9", false},
+		{">=2.1.0, <3.0.0", "2.9.9", true},
+		{">=2.1.0, <3.0.0", "3.0.0", false},
+		{">= 2.1.0 < 3", "2.5.0", true},
+		{"2.1.4", "2.1.4", true},
+		{"2.1.4", "v2.1.4", true},
+		{"2.1.4", "2.1.5", false},
+		{"=2.1.4", "go-v2.1.4", true},
+		{"!=2.1.3", "2.1.3", false},
+		{"!=2.1.3", "2.1.4", true},
+		{">2.0", "2.0.1", true},
+		{"<=2.4.1", "2.4.1", true},
+		{"~2.1", "2.1.9", true},
+		{"~2.1", "2.2.0", false},
+		{"~2.1.3", "2.1.2", false},
+		{"~2", "2.9.0", true},
+		{"^2.1.0", "2.9.0", true},
+		{"^2.1.0", "3.0.0", false},
+		{"^0.3.1", "0.3.9", true},
+		{"^0.3.1", "0.4.0", false},
+		{"2.x", "2.7.1", true},
+		{"2.x", "3.0.0", false},
+		{"2.1.x", "2.1.7", true},
+		{"2.1.x", "2.2.0", false},
+		{"*", "0.0.1", true},
+		{"~1.9 || ^2.1", "1.9.3", true},
+		{"~1.9 || ^2.1", "2.4.0", true},
+		{"~1.9 || ^2.1", "2.0.0", false},
+		{">=2.1.0-beta.1", "2.1.0-beta.2", true},
+		{">=2.1.0", "2.1.0-rc.1", false},
+		{">=2.1.0", "dev", false},
+		{"<3.0.0", "3.0.0-rc.1", false},
+		{"^2.1.0", "3.0.0-beta.1", false},
+		{"<3.0.0-rc.2", "3.0.0-rc.1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr+"/"+tt.version, func(t *testing.T) {
+			t.Parallel()
+			c, err := ParseConstraint(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseConstraint(%q): %v", tt.expr, err)
+			}
+			if got := c.CheckString(tt.version); got != tt.want {
+				t.Errorf("%q.Check(%q) = %v, want %v", tt.expr, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConstraint_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{"", "   ", ">=abc", "~", "2.x-beta", "1.0 ||", ">=1.2.3.4"} {
+		if _, err := ParseConstraint(expr); err == nil {
+			t.Errorf("ParseConstraint(%q) expected error", expr)
+		}
+	}
+}
+
+func TestConstraint_String(t *testing.T) {
+	t.Parallel()
+
+	c, err := ParseConstraint(" >=2.1.0, <3.0.0 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.String() != ">=2.1.0, <3.0.0" {
+		t.Errorf("String() = %q", c.String())
+	}
+}
@@ -142,6 +142,27 @@ func (c *localChecker) IsUpdateAvailable(current string) (bool, *VersionInfo, er
 	return true, info, nil
 }
 
+// ListReleases returns the single release available in the local directory.
+func (c *localChecker) ListReleases(ctx context.Context) ([]VersionInfo, error) {
+	info, err := c.CheckLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []VersionInfo{*info}, nil
+}
+
+// ResolveRelease returns the local release if it matches version.
+func (c *localChecker) ResolveRelease(ctx context.Context, version string) (*VersionInfo, error) {
+	info, err := c.CheckLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if compareSemver(info.Version, version) != 0 {
+		return nil, fmt.Errorf("local checker: release %s not found (available: %s)", version, info.Version)
+	}
+	return info, nil
+}
+
 // isDevVersion checks if the version string indicates a dev build.
 func (c *localChecker) isDevVersion(v string) bool {
 	return strings.Contains(v, "dirty") ||
@@ -0,0 +1,132 @@
+package update
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Semver is a parsed semantic version (https://semver.org).
+// Build metadata is kept for display but ignored for ordering.
+type Semver struct {
+	Major      int
+	Minor      int
+	Patch      int
+	PreRelease []string
+	Build      string
+}
+
+// ParseSemver parses a version string such as "v2.1.0", "go-v2.1.0-beta.2"
+// or "2.1.0+build.5". Missing minor and patch components default to zero.
+// Returns an error when the core version is not numeric.
+func ParseSemver(v string) (Semver, error) {
+	raw := normalizeVersion(v)
+	if raw == "" {
+		return Semver{}, fmt.Errorf("update: empty version")
+	}
+
+	var s Semver
+	if before, after, ok := strings.Cut(raw, "+"); ok {
+		raw = before
+		s.Build = after
+	}
+	if before, after, ok := strings.Cut(raw, "-"); ok {
+		raw = before
+		if after == "" {
+			return Semver{}, fmt.Errorf("update: invalid version %q: empty pre-release", v)
+		}
+		s.PreRelease = strings.Split(after, ".")
+	}
+
+	segments := strings.Split(raw, ".")
+	if len(segments) > 3 {
+		return Semver{}, fmt.Errorf("update: invalid version %q: too many components", v)
+	}
+	nums := [3]int{}
+	for i, seg := range segments {
+		n, err := strconv.Atoi(seg)
+		if err != nil || n < 0 {
+			return Semver{}, fmt.Errorf("update: invalid version %q: component %q is not numeric", v, seg)
+		}
+		nums[i] = n
+	}
+	s.Major, s.Minor, s.Patch = nums[0], nums[1], nums[2]
+	return s, nil
+}
+
+// String formats the version without any "v" prefix.
+func (s Semver) String() string {
+	out := fmt.Sprintf("%d.%d.%d", s.Major, s.Minor, s.Patch)
+	if len(s.PreRelease) > 0 {
+		out += "-" + strings.Join(s.PreRelease, ".")
+	}
+	if s.Build != "" {
+		out += "+" + s.Build
+	}
+	return out
+}
+
+// IsPreRelease reports whether the version carries a pre-release tag.
+func (s Semver) IsPreRelease() bool {
+	return len(s.PreRelease) > 0
+}
+
+// Compare returns -1, 0 or 1 following semver precedence rules:
+// a pre-release sorts before its release, numeric identifiers compare
+// numerically, and alphanumeric identifiers compare lexically.
+func (s Semver) Compare(o Semver) int {
+	for _, pair := range [][2]int{{s.Major, o.Major}, {s.Minor, o.Minor}, {s.Patch, o.Patch}} {
+		if c := compareInt(pair[0], pair[1]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(s.PreRelease) == 0 && len(o.PreRelease) == 0:
+		return 0
+	case len(s.PreRelease) == 0:
+		return 1
+	case len(o.PreRelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(s.PreRelease) && i < len(o.PreRelease); i++ {
+		if c := comparePreReleaseIdent(s.PreRelease[i], o.PreRelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(s.PreRelease), len(o.PreRelease))
+}
+
+// comparePreReleaseIdent compares a single dot-separated pre-release identifier.
+// Numeric identifiers always have lower precedence than alphanumeric ones.
+func comparePreReleaseIdent(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInt(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a > b:
+		return 1
+	case a < b:
+		return -1
+	}
+	return 0
+}
+
+// normalizeVersion strips surrounding whitespace and the "go-v"/"v" tag prefixes.
+func normalizeVersion(v string) string {
+	v = strings.TrimSpace(v)
+	v = strings.TrimPrefix(v, "go-v")
+	return strings.TrimPrefix(v, "v")
+}
@@ -0,0 +1,86 @@
+package update
+
+import "testing"
+
+func TestParseSemver(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"plain", "2.1.0", "2.1.0", false},
+		{"v prefix", "v2.1.0", "2.1.0", false},
+		{"go-v prefix", "go-v2.1.0", "2.1.0", false},
+		{"pre-release", "v2.1.0-beta.2", "2.1.0-beta.2", false},
+		{"build metadata", "2.1.0+20260101", "2.1.0+20260101", false},
+		{"pre-release and build", "2.1.0-rc.1+abc", "2.1.0-rc.1+abc", false},
+		{"major only", "3", "3.0.0", false},
+		{"major.minor", "3.7", "3.7.0", false},
+		{"empty", "", "", true},
+		{"non-numeric", "abc", "", true},
+		{"too many parts", "1.2.3.4", "", true},
+		{"empty pre-release", "1.2.3-", "", true},
+		{"dev build", "dev", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseSemver(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSemver(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParseSemver(%q) = %q, want %q", tt.in, got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSemver_Compare_PreReleaseOrdering(t *testing.T) {
+	t.Parallel()
+
+	// Ordered lowest to highest, following the semver.org precedence example.
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1-nightly.20260101",
+		"1.0.1",
+	}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		a, err := ParseSemver(ordered[i])
+		if err != nil {
+			t.Fatalf("parse %q: %v", ordered[i], err)
+		}
+		b, err := ParseSemver(ordered[i+1])
+		if err != nil {
+			t.Fatalf("parse %q: %v", ordered[i+1], err)
+		}
+		if got := a.Compare(b); got != -1 {
+			t.Errorf("Compare(%q, %q) = %d, want -1", ordered[i], ordered[i+1], got)
+		}
+		if got := b.Compare(a); got != 1 {
+			t.Errorf("Compare(%q, %q) = %d, want 1", ordered[i+1], ordered[i], got)
+		}
+	}
+}
+
+func TestSemver_Compare_IgnoresBuildMetadata(t *testing.T) {
+	t.Parallel()
+
+	a, _ := ParseSemver("1.2.3+build.1")
+	b, _ := ParseSemver("1.2.3+build.2")
+	if got := a.Compare(b); got != 0 {
+		t.Errorf("Compare with differing build metadata = %d, want 0", got)
+	}
+}
@@ -0,0 +1,161 @@
+package usage
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// dayFormat is the key format for day groups and daily series.
+const dayFormat = "2006-01-02"
+
+// Apply returns the records matching f.
+func (f Filter) Apply(records []Record) []Record {
+	var out []Record
+	for _, r := range records {
+		if !f.Since.IsZero() && r.Timestamp.Before(f.Since) {
+			continue
+		}
+		if !f.Until.IsZero() && !r.Timestamp.Before(f.Until) {
+			continue
+		}
+		if f.Project != "" && r.Project != f.Project {
+			continue
+		}
+		if f.Model != "" && r.Model != f.Model {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// Sum totals the given records.
+func Sum(records []Record) Totals {
+	var t Totals
+	sessions := make(map[string]struct{})
+	for _, r := range records {
+		t.add(r)
+		sessions[r.SessionID] = struct{}{}
+	}
+	t.Sessions = len(sessions)
+	return t
+}
+
+func (t *Totals) add(r Record) {
+	t.InputTokens += r.InputTokens
+	t.OutputTokens += r.OutputTokens
+	t.CacheCreationTokens += r.CacheCreationTokens
+	t.CacheReadTokens += r.CacheReadTokens
+	t.CostUSD += r.CostUSD
+	t.Messages++
+}
+
+// Summarize groups records by the given dimension. Day groups are ordered
+// chronologically (in loc); all others by descending cost, then key.
+func Summarize(records []Record, by GroupBy, loc *time.Location) []Group {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	buckets := make(map[string][]Record)
+	for _, r := range records {
+		key := groupKey(r, by, loc)
+		buckets[key] = append(buckets[key], r)
+	}
+
+	groups := make([]Group, 0, len(buckets))
+	for key, rs := range buckets {
+		groups = append(groups, Group{Key: key, Totals: Sum(rs)})
+	}
+
+	slices.SortFunc(groups, func(a, b Group) int {
+		if by == GroupByDay {
+			return cmp.Compare(a.Key, b.Key)
+		}
+		if c := cmp.Compare(b.CostUSD, a.CostUSD); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return groups
+}
+
+func groupKey(r Record, by GroupBy, loc *time.Location) string {
+	switch by {
+	case GroupByProject:
+		return r.Project
+	case GroupByModel:
+		return r.Model
+	case GroupBySession:
+		return r.SessionID
+	}
+	return r.Timestamp.In(loc).Format(dayFormat)
+}
+
+// DailyCosts returns one cost value per calendar day (in loc) from the day
+// containing since up to and including the day containing until. Days
+// without usage are zero so the series can be charted directly.
+func DailyCosts(records []Record, since, until time.Time, loc *time.Location) []float64 {
+	if loc == nil {
+		loc = time.Local
+	}
+	start := startOfDay(since.In(loc))
+	end := startOfDay(until.In(loc))
+	if end.Before(start) {
+		return nil
+	}
+
+	index := make(map[string]int)
+	var series []float64
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		index[d.Format(dayFormat)] = len(series)
+		series = append(series, 0)
+	}
+	for _, r := range records {
+		if i, ok := index[r.Timestamp.In(loc).Format(dayFormat)]; ok {
+			series[i] += r.CostUSD
+		}
+	}
+	return series
+}
+
+// UnpricedModels returns the sorted, distinct models without pricing.
+func UnpricedModels(records []Record) []string {
+	seen := make(map[string]struct{})
+	var models []string
+	for _, r := range records {
+		if r.Priced {
+			continue
+		}
+		if _, ok := seen[r.Model]; !ok {
+			seen[r.Model] = struct{}{}
+			models = append(models, r.Model)
+		}
+	}
+	slices.Sort(models)
+	return models
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Trends returns the DailyCosts series for each key of the grouping
+// dimension, for charting alongside Summarize output.
+func Trends(records []Record, by GroupBy, since, until time.Time, loc *time.Location) map[string][]float64 {
+	if loc == nil {
+		loc = time.Local
+	}
+	buckets := make(map[string][]Record)
+	for _, r := range records {
+		key := groupKey(r, by, loc)
+		buckets[key] = append(buckets[key], r)
+	}
+	trends := make(map[string][]float64, len(buckets))
+	for key, rs := range buckets {
+		trends[key] = DailyCosts(rs, since, until, loc)
+	}
+	return trends
+}
@@ -0,0 +1,176 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultWarnPercent is the budget share at which a warning is shown.
+const DefaultWarnPercent = 80
+
+// DefaultSpendCacheTTL bounds how stale the statusline budget figure can be.
+const DefaultSpendCacheTTL = 2 * time.Minute
+
+// Budget holds the spending limits from the pricing config section.
+// A zero limit disables that period.
+type Budget struct {
+	DailyUSD    float64
+	MonthlyUSD  float64
+	WarnPercent int
+}
+
+// Enabled reports whether any limit is configured.
+func (b Budget) Enabled() bool {
+	return b.DailyUSD > 0 || b.MonthlyUSD > 0
+}
+
+// BudgetLevel is the severity of a budget status.
+type BudgetLevel int
+
+const (
+	BudgetOK BudgetLevel = iota
+	BudgetWarn
+	BudgetExceeded
+)
+
+// BudgetStatus is the spend against one limit.
+type BudgetStatus struct {
+	Period   string // "day" or "month"
+	SpentUSD float64
+	LimitUSD float64
+	Level    BudgetLevel
+}
+
+// Percent returns spend as a percentage of the limit.
+func (s BudgetStatus) Percent() float64 {
+	if s.LimitUSD <= 0 {
+		return 0
+	}
+	return s.SpentUSD / s.LimitUSD * 100
+}
+
+// Spend is the cost for the current day and month.
+type Spend struct {
+	DayUSD     float64   `json:"day_usd"`
+	MonthUSD   float64   `json:"month_usd"`
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+// ComputeSpend sums record costs for the day and month containing now.
+func ComputeSpend(records []Record, now time.Time) Spend {
+	day := startOfDay(now)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	s := Spend{ComputedAt: now}
+	for _, r := range records {
+		if r.Timestamp.Before(month) || r.Timestamp.After(now) {
+			continue
+		}
+		s.MonthUSD += r.CostUSD
+		if !r.Timestamp.Before(day) {
+			s.DayUSD += r.CostUSD
+		}
+	}
+	return s
+}
+
+// Evaluate compares spend against each configured limit, daily first.
+func (b Budget) Evaluate(s Spend) []BudgetStatus {
+	var result []BudgetStatus
+	if b.DailyUSD > 0 {
+		result = append(result, b.status("day", s.DayUSD, b.DailyUSD))
+	}
+	if b.MonthlyUSD > 0 {
+		result = append(result, b.status("month", s.MonthUSD, b.MonthlyUSD))
+	}
+	return result
+}
+
+func (b Budget) status(period string, spent, limit float64) BudgetStatus {
+	warn := b.WarnPercent
+	if warn <= 0 {
+		warn = DefaultWarnPercent
+	}
+	st := BudgetStatus{Period: period, SpentUSD: spent, LimitUSD: limit}
+	switch pct := st.Percent(); {
+	case pct >= 100:
+		st.Level = BudgetExceeded
+	case pct >= float64(warn):
+		st.Level = BudgetWarn
+	}
+	return st
+}
+
+// MostSevere returns the status with the highest level, preferring the
+// higher percentage on ties. Returns false for an empty slice.
+func MostSevere(statuses []BudgetStatus) (BudgetStatus, bool) {
+	if len(statuses) == 0 {
+		return BudgetStatus{}, false
+	}
+	best := statuses[0]
+	for _, s := range statuses[1:] {
+		if s.Level > best.Level || (s.Level == best.Level && s.Percent() > best.Percent()) {
+			best = s
+		}
+	}
+	return best, true
+}
+
+// SpendCache stores the last computed Spend so frequent callers such as
+// the statusline avoid re-parsing transcripts on every render.
+type SpendCache struct {
+	path string
+	ttl  time.Duration
+}
+
+// NewSpendCache creates a cache at path.
+// If path is empty, defaults to ~/.moai/cache/usage_spend.json.
+// If ttl is zero, defaults to DefaultSpendCacheTTL.
+func NewSpendCache(path string, ttl time.Duration) *SpendCache {
+	if path == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = "."
+		}
+		path = filepath.Join(homeDir, ".moai", "cache", "usage_spend.json")
+	}
+	if ttl == 0 {
+		ttl = DefaultSpendCacheTTL
+	}
+	return &SpendCache{path: path, ttl: ttl}
+}
+
+// Get returns the cached spend if it is fresh and from the same day as now.
+// Returns nil on miss, expiry or corruption.
+func (c *SpendCache) Get(now time.Time) *Spend {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil
+	}
+	var s Spend
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil
+	}
+	if now.Sub(s.ComputedAt) > c.ttl || !startOfDay(s.ComputedAt.In(now.Location())).Equal(startOfDay(now)) {
+		return nil
+	}
+	return &s
+}
+
+// Set writes spend to disk, creating directories as needed.
+func (c *SpendCache) Set(s Spend) error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("marshal spend: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0o644); err != nil {
+		return fmt.Errorf("write spend cache: %w", err)
+	}
+	return nil
+}
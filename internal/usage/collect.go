@@ -0,0 +1,92 @@
+package usage
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/rank"
+)
+
+// Collect returns records for the given transcripts at or after since.
+// The transcript index is updated first, so only content appended since
+// the last call is parsed; with a nil index every transcript is parsed in
+// full. Transcripts last modified before since are skipped without being
+// opened (a zero since reads everything). Unreadable transcripts are skipped.
+func Collect(idx *rank.TranscriptIndex, paths []string, since time.Time) []Record {
+	var recent []string
+	for _, path := range paths {
+		if !since.IsZero() {
+			info, err := os.Stat(path)
+			if err != nil || info.ModTime().Before(since) {
+				continue
+			}
+		}
+		recent = append(recent, path)
+	}
+
+	var records []Record
+	if idx == nil {
+		for _, path := range recent {
+			turns, err := rank.ParseTranscriptTurns(path)
+			if err != nil {
+				slog.Debug("skip transcript", "path", path, "error", err)
+				continue
+			}
+			records = append(records, RecordsFromTurns(path, turns)...)
+		}
+		return records
+	}
+
+	if _, err := idx.Update(recent); err != nil {
+		slog.Debug("update transcript index", "error", err)
+	}
+	byPath := idx.TurnsSince(recent, since)
+	for _, path := range recent {
+		records = append(records, RecordsFromTurns(path, byPath[path])...)
+	}
+	return records
+}
+
+// RecordsFromTurns converts transcript turns into priced records.
+// The session falls back to the transcript file name and the project to
+// the transcript's parent directory when the turns do not carry them.
+func RecordsFromTurns(path string, turns []rank.Turn) []Record {
+	records := make([]Record, 0, len(turns))
+	for _, turn := range turns {
+		pricing, priced := rank.ResolveModelPricing(turn.Model)
+		r := Record{
+			Timestamp:           turn.Timestamp,
+			SessionID:           turn.SessionID,
+			Project:             projectName(path, turn.CWD),
+			Model:               turn.Model,
+			InputTokens:         turn.InputTokens,
+			OutputTokens:        turn.OutputTokens,
+			CacheCreationTokens: turn.CacheCreationTokens,
+			CacheReadTokens:     turn.CacheReadTokens,
+			Priced:              priced,
+		}
+		if r.SessionID == "" {
+			r.SessionID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		if r.Model == "" {
+			r.Model = "unknown"
+		}
+		if priced {
+			r.CostUSD = rank.CalculateCost(r.InputTokens, r.OutputTokens, r.CacheCreationTokens, r.CacheReadTokens, pricing)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+// projectName returns the base name of the working directory, or the
+// transcript's directory name (Claude Code's encoded project path).
+func projectName(transcriptPath, cwd string) string {
+	if cwd != "" {
+		return filepath.Base(cwd)
+	}
+	return filepath.Base(filepath.Dir(transcriptPath))
+}
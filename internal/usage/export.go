@@ -0,0 +1,58 @@
+package usage
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// csvColumns are the value columns written by WriteCSV after the key.
+var csvColumns = []string{
+	"input_tokens", "output_tokens", "cache_creation_tokens",
+	"cache_read_tokens", "cache_hit_ratio", "cost_usd", "messages", "sessions",
+}
+
+// WriteCSV writes one row per group, with the grouping dimension as the
+// first column header.
+func WriteCSV(w io.Writer, report *Report) error {
+	cw := csv.NewWriter(w)
+
+	header := append([]string{string(report.GroupBy)}, csvColumns...)
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("usage: write csv: %w", err)
+	}
+	for _, g := range report.Groups {
+		row := []string{
+			g.Key,
+			strconv.FormatInt(g.InputTokens, 10),
+			strconv.FormatInt(g.OutputTokens, 10),
+			strconv.FormatInt(g.CacheCreationTokens, 10),
+			strconv.FormatInt(g.CacheReadTokens, 10),
+			strconv.FormatFloat(g.CacheHitRatio(), 'f', 4, 64),
+			strconv.FormatFloat(g.CostUSD, 'f', 4, 64),
+			strconv.Itoa(g.Messages),
+			strconv.Itoa(g.Sessions),
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("usage: write csv: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("usage: write csv: %w", err)
+	}
+	return nil
+}
+
+// WriteJSON writes the report as indented JSON.
+func WriteJSON(w io.Writer, report *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("usage: write json: %w", err)
+	}
+	return nil
+}
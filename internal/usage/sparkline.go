@@ -0,0 +1,30 @@
+package usage
+
+import "strings"
+
+// sparkBlocks are the eight block heights used by Sparkline.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a one-line block chart scaled to the maximum.
+// Zero values render as the lowest block; an all-zero series is flat.
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	maxVal := 0.0
+	for _, v := range values {
+		maxVal = max(maxVal, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if maxVal > 0 && v > 0 {
+			idx = int(v / maxVal * float64(len(sparkBlocks)-1))
+			idx = min(max(idx, 1), len(sparkBlocks)-1)
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return b.String()
+}
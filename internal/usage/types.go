@@ -0,0 +1,98 @@
+// Package usage aggregates token usage and cost from local Claude Code
+// transcripts for offline analytics and budget tracking.
+package usage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidGroupBy is returned for an unknown grouping dimension.
+var ErrInvalidGroupBy = errors.New("usage: invalid group-by")
+
+// Record is the usage of a single assistant message.
+type Record struct {
+	Timestamp           time.Time `json:"timestamp"`
+	SessionID           string    `json:"session_id"`
+	Project             string    `json:"project"`
+	Model               string    `json:"model"`
+	InputTokens         int64     `json:"input_tokens"`
+	OutputTokens        int64     `json:"output_tokens"`
+	CacheCreationTokens int64     `json:"cache_creation_tokens"`
+	CacheReadTokens     int64     `json:"cache_read_tokens"`
+	CostUSD             float64   `json:"cost_usd"`
+	// Priced is false when the model has no known pricing (CostUSD is 0).
+	Priced bool `json:"priced"`
+}
+
+// Totals sums token usage and cost over a set of records.
+type Totals struct {
+	InputTokens         int64   `json:"input_tokens"`
+	OutputTokens        int64   `json:"output_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	CostUSD             float64 `json:"cost_usd"`
+	Messages            int     `json:"messages"`
+	Sessions            int     `json:"sessions"`
+}
+
+// TotalTokens returns the sum of all token kinds.
+func (t Totals) TotalTokens() int64 {
+	return t.InputTokens + t.OutputTokens + t.CacheCreationTokens + t.CacheReadTokens
+}
+
+// CacheHitRatio returns the share of prompt tokens served from the cache,
+// in [0, 1]. Returns 0 when no prompt tokens were recorded.
+func (t Totals) CacheHitRatio() float64 {
+	prompt := t.InputTokens + t.CacheCreationTokens + t.CacheReadTokens
+	if prompt == 0 {
+		return 0
+	}
+	return float64(t.CacheReadTokens) / float64(prompt)
+}
+
+// GroupBy selects the aggregation dimension.
+type GroupBy string
+
+const (
+	GroupByDay     GroupBy = "day"
+	GroupByProject GroupBy = "project"
+	GroupByModel   GroupBy = "model"
+	GroupBySession GroupBy = "session"
+)
+
+// ParseGroupBy validates a grouping dimension name.
+func ParseGroupBy(s string) (GroupBy, error) {
+	switch g := GroupBy(s); g {
+	case GroupByDay, GroupByProject, GroupByModel, GroupBySession:
+		return g, nil
+	}
+	return "", fmt.Errorf("%w: %q (want day, project, model or session)", ErrInvalidGroupBy, s)
+}
+
+// Group is the totals for one key of a grouping dimension.
+type Group struct {
+	Key string `json:"key"`
+	Totals
+}
+
+// Filter restricts records by time range, project and model.
+// Zero values match everything. Until is exclusive.
+type Filter struct {
+	Since   time.Time
+	Until   time.Time
+	Project string
+	Model   string
+}
+
+// Report is the exported form of an aggregation.
+type Report struct {
+	GroupBy GroupBy `json:"group_by"`
+	Since   string  `json:"since,omitempty"`
+	Until   string  `json:"until,omitempty"`
+	Totals  Totals  `json:"totals"`
+	Groups  []Group `json:"groups"`
+	// Unpriced lists models whose cost could not be computed.
+	Unpriced []string `json:"unpriced_models,omitempty"`
+}
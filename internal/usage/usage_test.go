@@ -0,0 +1,300 @@
+package usage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/rank"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func sampleRecords() []Record {
+	return []Record{
+		{Timestamp: at("2026-03-01T10:00:00Z"), SessionID: "s1", Project: "api", Model: "opus", InputTokens: 100, OutputTokens: 10, CacheReadTokens: 300, CostUSD: 1.0, Priced: true},
+		{Timestamp: at("2026-03-01T11:00:00Z"), SessionID: "s1", Project: "api", Model: "sonnet", InputTokens: 50, OutputTokens: 5, CostUSD: 0.5, Priced: true},
+		{Timestamp: at("2026-03-03T09:00:00Z"), SessionID: "s2", Project: "web", Model: "opus", InputTokens: 200, CacheCreationTokens: 100, CostUSD: 2.0, Priced: true},
+		{Timestamp: at("2026-03-03T12:00:00Z"), SessionID: "s3", Project: "web", Model: "mystery", InputTokens: 10},
+	}
+}
+
+func TestParseGroupBy(t *testing.T) {
+	for _, s := range []string{"day", "project", "model", "session"} {
+		if _, err := ParseGroupBy(s); err != nil {
+			t.Errorf("ParseGroupBy(%q) error: %v", s, err)
+		}
+	}
+	if _, err := ParseGroupBy("week"); !errors.Is(err, ErrInvalidGroupBy) {
+		t.Errorf("ParseGroupBy(week) err = %v, want ErrInvalidGroupBy", err)
+	}
+}
+
+func TestSumAndCacheHitRatio(t *testing.T) {
+	tot := Sum(sampleRecords())
+	if tot.InputTokens != 360 || tot.OutputTokens != 15 || tot.CacheReadTokens != 300 || tot.CacheCreationTokens != 100 {
+		t.Errorf("token totals = %+v", tot)
+	}
+	if tot.CostUSD != 3.5 || tot.Messages != 4 || tot.Sessions != 3 {
+		t.Errorf("cost/messages/sessions = %v/%d/%d", tot.CostUSD, tot.Messages, tot.Sessions)
+	}
+	// 300 read / (360 input + 100 write + 300 read)
+	if got, want := tot.CacheHitRatio(), 300.0/760.0; got != want {
+		t.Errorf("CacheHitRatio = %v, want %v", got, want)
+	}
+	if (Totals{}).CacheHitRatio() != 0 {
+		t.Error("empty CacheHitRatio should be 0")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	records := sampleRecords()
+
+	days := Summarize(records, GroupByDay, time.UTC)
+	if len(days) != 2 || days[0].Key != "2026-03-01" || days[1].Key != "2026-03-03" {
+		t.Fatalf("day groups = %+v", days)
+	}
+	if days[0].CostUSD != 1.5 || days[0].Sessions != 1 {
+		t.Errorf("first day = %+v", days[0])
+	}
+
+	models := Summarize(records, GroupByModel, time.UTC)
+	var keys []string
+	for _, g := range models {
+		keys = append(keys, g.Key)
+	}
+	if strings.Join(keys, ",") != "opus,sonnet,mystery" {
+		t.Errorf("model order = %v, want by descending cost", keys)
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	records := sampleRecords()
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"zero filter", Filter{}, 4},
+		{"since", Filter{Since: at("2026-03-02T00:00:00Z")}, 2},
+		{"until exclusive", Filter{Until: at("2026-03-01T11:00:00Z")}, 1},
+		{"project", Filter{Project: "web"}, 2},
+		{"model", Filter{Model: "opus"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(tt.filter.Apply(records)); got != tt.want {
+				t.Errorf("len = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDailyCostsAndTrends(t *testing.T) {
+	records := sampleRecords()
+	series := DailyCosts(records, at("2026-02-28T00:00:00Z"), at("2026-03-03T23:00:00Z"), time.UTC)
+	want := []float64{0, 1.5, 0, 2.0}
+	if len(series) != len(want) {
+		t.Fatalf("series = %v, want %v", series, want)
+	}
+	for i := range want {
+		if series[i] != want[i] {
+			t.Errorf("series[%d] = %v, want %v", i, series[i], want[i])
+		}
+	}
+
+	if DailyCosts(records, at("2026-03-05T00:00:00Z"), at("2026-03-01T00:00:00Z"), time.UTC) != nil {
+		t.Error("inverted range should return nil")
+	}
+
+	trends := Trends(records, GroupByProject, at("2026-03-01T00:00:00Z"), at("2026-03-03T00:00:00Z"), time.UTC)
+	if got := trends["web"]; len(got) != 3 || got[2] != 2.0 {
+		t.Errorf("web trend = %v", got)
+	}
+}
+
+func TestUnpricedModels(t *testing.T) {
+	got := UnpricedModels(sampleRecords())
+	if len(got) != 1 || got[0] != "mystery" {
+		t.Errorf("UnpricedModels = %v", got)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		in   []float64
+		want string
+	}{
+		{nil, ""},
+		{[]float64{0, 0}, "▁▁"},
+		{[]float64{0, 1, 2, 4}, "▁▂▄█"},
+		{[]float64{0.001, 100}, "▂█"},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.in); got != tt.want {
+			t.Errorf("Sparkline(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	report := &Report{GroupBy: GroupByModel, Groups: Summarize(sampleRecords(), GroupByModel, time.UTC)}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("csv lines = %d, want 4:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "model,input_tokens,") {
+		t.Errorf("header = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "opus,300,10,100,300,") {
+		t.Errorf("first row = %q", lines[1])
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	records := sampleRecords()
+	report := &Report{GroupBy: GroupByDay, Totals: Sum(records), Groups: Summarize(records, GroupByDay, time.UTC)}
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if decoded.Totals.CostUSD != 3.5 || len(decoded.Groups) != 2 || decoded.Groups[0].Key != "2026-03-01" {
+		t.Errorf("decoded = %+v", decoded)
+	}
+}
+
+func TestBudget(t *testing.T) {
+	now := at("2026-03-03T15:00:00Z")
+	spend := ComputeSpend(sampleRecords(), now)
+	if spend.DayUSD != 2.0 || spend.MonthUSD != 3.5 {
+		t.Fatalf("spend = %+v", spend)
+	}
+
+	if (Budget{}).Enabled() {
+		t.Error("zero budget should be disabled")
+	}
+
+	b := Budget{DailyUSD: 2.4, MonthlyUSD: 10}
+	statuses := b.Evaluate(spend)
+	if len(statuses) != 2 {
+		t.Fatalf("statuses = %+v", statuses)
+	}
+	if statuses[0].Period != "day" || statuses[0].Level != BudgetWarn {
+		t.Errorf("day status = %+v, want warn at 83%%", statuses[0])
+	}
+	if statuses[1].Level != BudgetOK {
+		t.Errorf("month status = %+v", statuses[1])
+	}
+
+	worst, ok := MostSevere(statuses)
+	if !ok || worst.Period != "day" {
+		t.Errorf("MostSevere = %+v", worst)
+	}
+
+	exceeded := Budget{DailyUSD: 1, WarnPercent: 50}.Evaluate(spend)
+	if exceeded[0].Level != BudgetExceeded {
+		t.Errorf("exceeded = %+v", exceeded[0])
+	}
+	if _, ok := MostSevere(nil); ok {
+		t.Error("MostSevere(nil) should report false")
+	}
+}
+
+func TestSpendCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "spend.json")
+	cache := NewSpendCache(path, time.Minute)
+	now := at("2026-03-03T15:00:00Z")
+
+	if cache.Get(now) != nil {
+		t.Fatal("empty cache should miss")
+	}
+	if err := cache.Set(Spend{DayUSD: 1, MonthUSD: 2, ComputedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if got := cache.Get(now.Add(30 * time.Second)); got == nil || got.MonthUSD != 2 {
+		t.Errorf("fresh Get = %+v", got)
+	}
+	if cache.Get(now.Add(2*time.Minute)) != nil {
+		t.Error("expired entry should miss")
+	}
+
+	// Entries from the previous day are stale even within the TTL.
+	hourly := NewSpendCache(path, time.Hour)
+	if err := hourly.Set(Spend{DayUSD: 1, ComputedAt: at("2026-03-03T23:50:00Z")}); err != nil {
+		t.Fatal(err)
+	}
+	if hourly.Get(at("2026-03-04T00:10:00Z")) != nil {
+		t.Error("previous-day entry should miss")
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if cache.Get(now) != nil {
+		t.Error("corrupt cache should miss")
+	}
+}
+
+func TestCollect(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "-work-app")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "sess-1.jsonl")
+	content := `{"timestamp":"2026-03-01T10:00:00Z","type":"assistant","message":{"model":"claude-opus-4-5","usage":{"input_tokens":1000000,"output_tokens":0}}}
+{"timestamp":"2026-03-01T10:05:00Z","type":"assistant","cwd":"/home/me/app","sessionId":"abc","message":{"model":"local-model","usage":{"input_tokens":5}}}
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := rank.OpenTranscriptIndex(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, index := range map[string]*rank.TranscriptIndex{"full parse": nil, "indexed": idx} {
+		records := Collect(index, []string{path, filepath.Join(dir, "missing.jsonl")}, time.Time{})
+		if len(records) != 2 {
+			t.Fatalf("%s: records = %+v", name, records)
+		}
+		first := records[0]
+		if first.SessionID != "sess-1" || first.Project != "-work-app" || !first.Priced || first.CostUSD != 5.0 {
+			t.Errorf("%s: first = %+v", name, first)
+		}
+		second := records[1]
+		if second.SessionID != "abc" || second.Project != "app" || second.Priced || second.CostUSD != 0 {
+			t.Errorf("%s: second = %+v", name, second)
+		}
+	}
+	if idx.Len() != 1 {
+		t.Errorf("index holds %d transcripts, want 1", idx.Len())
+	}
+
+	// Files older than since are skipped without parsing.
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if got := Collect(idx, []string{path}, time.Now().Add(-time.Hour)); len(got) != 0 {
+		t.Errorf("stale transcript collected: %+v", got)
+	}
+}
@@ -0,0 +1,372 @@
+package workflow
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultClaudeBinary is the Claude Code CLI looked up on PATH.
+const DefaultClaudeBinary = "claude"
+
+// claudeWaitDelay bounds how long a cancelled claude process may keep its
+// output pipes open before it is killed.
+const claudeWaitDelay = 10 * time.Second
+
+// stderrTailSize is how much of claude's stderr is kept for error messages.
+const stderrTailSize = 2048
+
+// phasePrompts are the slash commands sent to Claude Code for each phase.
+var phasePrompts = map[Phase]string{
+	PhasePlan: "/moai plan %s",
+	PhaseRun:  "/moai run %s",
+	PhaseSync: "/moai sync %s",
+}
+
+// PhaseLimits bounds a single headless phase. Zero values disable a limit.
+type PhaseLimits struct {
+	// Timeout is the wall-clock limit for the phase.
+	Timeout time.Duration
+
+	// TokenBudget caps input, output and cache-creation tokens. Cache reads
+	// are not counted. The phase is stopped once the budget is exceeded.
+	TokenBudget int64
+}
+
+// ClaudeExecutorConfig configures ClaudeExecutor.
+type ClaudeExecutorConfig struct {
+	// Binary is the claude executable (default DefaultClaudeBinary on PATH).
+	Binary string
+
+	// Limits holds the timeout and token budget per phase.
+	Limits map[Phase]PhaseLimits
+
+	// PermissionMode is passed as --permission-mode when set.
+	PermissionMode string
+
+	// ExtraArgs are appended to every claude invocation.
+	ExtraArgs []string
+
+	// Resume skips phases the run state already records as completed.
+	// Without it, the plan phase starts a fresh run record.
+	Resume bool
+
+	// OnPhase is called when a phase starts and finishes. Phases skipped on
+	// resume are reported once with PhaseStatusSkipped.
+	OnPhase func(specID string, phase Phase, rec PhaseRecord)
+}
+
+// ClaudeExecutor implements PhaseExecutor by running Claude Code in
+// non-interactive mode (claude -p) inside the SPEC worktree. Each phase's
+// stream-json output is written to .moai/logs/workflow/<SPEC-ID>/, and the
+// outcome is recorded in the run state next to it.
+type ClaudeExecutor struct {
+	cfg    ClaudeExecutorConfig
+	logger *slog.Logger
+}
+
+// Compile-time interface compliance check.
+var _ PhaseExecutor = (*ClaudeExecutor)(nil)
+
+// NewClaudeExecutor creates a headless Claude Code phase executor.
+func NewClaudeExecutor(cfg ClaudeExecutorConfig, logger *slog.Logger) *ClaudeExecutor {
+	if cfg.Binary == "" {
+		cfg.Binary = DefaultClaudeBinary
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ClaudeExecutor{
+		cfg:    cfg,
+		logger: logger.With("module", "claude-executor"),
+	}
+}
+
+// ExecutePlan runs /moai plan for the SPEC.
+func (e *ClaudeExecutor) ExecutePlan(ctx context.Context, specID, workDir string) error {
+	return e.execute(ctx, PhasePlan, specID, workDir)
+}
+
+// ExecuteRun runs /moai run for the SPEC.
+func (e *ClaudeExecutor) ExecuteRun(ctx context.Context, specID, workDir string) error {
+	return e.execute(ctx, PhaseRun, specID, workDir)
+}
+
+// ExecuteSync runs /moai sync for the SPEC.
+func (e *ClaudeExecutor) ExecuteSync(ctx context.Context, specID, workDir string) error {
+	return e.execute(ctx, PhaseSync, specID, workDir)
+}
+
+// execute runs one phase and records its outcome.
+func (e *ClaudeExecutor) execute(ctx context.Context, phase Phase, specID, workDir string) error {
+	state, err := LoadRunState(workDir, specID)
+	switch {
+	case errors.Is(err, ErrNoRunState), err == nil && phase == PhasePlan && !e.cfg.Resume:
+		state = NewRunState(specID)
+	case err != nil:
+		return err
+	}
+
+	rec := state.Phase(phase)
+	if e.cfg.Resume && rec.Status == PhaseStatusCompleted {
+		e.logger.Info("phase already completed, skipping", "spec_id", specID, "phase", phase)
+		skipped := *rec
+		skipped.Status = PhaseStatusSkipped
+		e.notify(specID, phase, skipped)
+		return nil
+	}
+
+	*rec = PhaseRecord{Status: PhaseStatusRunning, StartedAt: time.Now()}
+	logFile, err := e.openLog(workDir, specID, phase, rec)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = logFile.Close() }()
+	if err := state.Save(workDir); err != nil {
+		return err
+	}
+	e.notify(specID, phase, *rec)
+
+	runErr := e.runClaude(ctx, phase, specID, workDir, logFile, rec)
+	rec.CompletedAt = time.Now()
+	switch {
+	case runErr == nil:
+		rec.Status = PhaseStatusCompleted
+	case ctx.Err() != nil:
+		// Interrupted by the caller: leave the phase to be resumed.
+		rec.Status = PhaseStatusPending
+	default:
+		rec.Status = PhaseStatusFailed
+	}
+	if runErr != nil {
+		rec.Error = runErr.Error()
+	}
+
+	if err := state.Save(workDir); err != nil {
+		e.logger.Warn("failed to save run state", "spec_id", specID, "error", err)
+	}
+	e.notify(specID, phase, *rec)
+	e.logger.Info("phase finished",
+		"spec_id", specID,
+		"phase", phase,
+		"status", rec.Status,
+		"exit_code", rec.ExitCode,
+		"tokens", rec.Tokens,
+	)
+	return runErr
+}
+
+// openLog creates the stream-json log file for a phase.
+func (e *ClaudeExecutor) openLog(workDir, specID string, phase Phase, rec *PhaseRecord) (*os.File, error) {
+	dir := RunStateDir(workDir, specID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create log directory: %w", err)
+	}
+	name := fmt.Sprintf("%s-%s.jsonl", phase, rec.StartedAt.Format("20060102T150405"))
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("create phase log: %w", err)
+	}
+	rec.LogPath = f.Name()
+	return f, nil
+}
+
+// runClaude launches claude for the phase, streams its output into logFile
+// and fills rec with exit code, token usage, cost and session ID.
+func (e *ClaudeExecutor) runClaude(ctx context.Context, phase Phase, specID, workDir string, logFile io.Writer, rec *PhaseRecord) error {
+	bin, err := exec.LookPath(e.cfg.Binary)
+	if err != nil {
+		return fmt.Errorf("%s: %w", e.cfg.Binary, ErrClaudeNotFound)
+	}
+
+	limits := e.cfg.Limits[phase]
+	runCtx := ctx
+	if limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, limits.Timeout)
+		defer cancel()
+	}
+	runCtx, stop := context.WithCancelCause(runCtx)
+	defer stop(nil)
+
+	cmd := exec.CommandContext(runCtx, bin, e.args(phase, specID)...)
+	cmd.Dir = workDir
+	cmd.WaitDelay = claudeWaitDelay
+	stderr := &tailBuffer{max: stderrTailSize}
+	cmd.Stderr = stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("claude stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start claude: %w", err)
+	}
+
+	stream := &streamTracker{}
+	reader := bufio.NewReader(stdout)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			_, _ = logFile.Write(line)
+			stream.apply(line)
+			rec.Tokens = stream.tokens()
+			if limits.TokenBudget > 0 && rec.Tokens > limits.TokenBudget {
+				stop(ErrTokenBudgetExceeded)
+			}
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	waitErr := cmd.Wait()
+
+	rec.Tokens = stream.tokens()
+	rec.CostUSD = stream.costUSD
+	rec.SessionID = stream.sessionID
+	if cmd.ProcessState != nil {
+		rec.ExitCode = cmd.ProcessState.ExitCode()
+	}
+
+	switch {
+	case errors.Is(context.Cause(runCtx), ErrTokenBudgetExceeded):
+		return fmt.Errorf("%s phase used %d tokens (budget %d): %w", phase, rec.Tokens, limits.TokenBudget, ErrTokenBudgetExceeded)
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case runCtx.Err() != nil:
+		return fmt.Errorf("%s phase exceeded %s: %w", phase, limits.Timeout, ErrPhaseTimeout)
+	case waitErr != nil:
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("claude exited with status %d: %s", rec.ExitCode, msg)
+		}
+		return fmt.Errorf("claude exited with status %d: %w", rec.ExitCode, waitErr)
+	case stream.isError:
+		return fmt.Errorf("claude reported an error: %s", stream.result)
+	}
+	return nil
+}
+
+// args builds the claude command line for a phase.
+func (e *ClaudeExecutor) args(phase Phase, specID string) []string {
+	args := []string{
+		"-p", fmt.Sprintf(phasePrompts[phase], specID),
+		"--output-format", "stream-json",
+		"--verbose",
+	}
+	if e.cfg.PermissionMode != "" {
+		args = append(args, "--permission-mode", e.cfg.PermissionMode)
+	}
+	return append(args, e.cfg.ExtraArgs...)
+}
+
+func (e *ClaudeExecutor) notify(specID string, phase Phase, rec PhaseRecord) {
+	if e.cfg.OnPhase != nil {
+		e.cfg.OnPhase(specID, phase, rec)
+	}
+}
+
+// streamUsage is the usage object in Claude Code stream-json events.
+type streamUsage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+}
+
+func (u *streamUsage) budgetTokens() int64 {
+	if u == nil {
+		return 0
+	}
+	return u.InputTokens + u.OutputTokens + u.CacheCreationInputTokens
+}
+
+// streamEvent is the subset of a stream-json line the executor reads.
+type streamEvent struct {
+	Type         string       `json:"type"`
+	SessionID    string       `json:"session_id"`
+	IsError      bool         `json:"is_error"`
+	Result       string       `json:"result"`
+	TotalCostUSD float64      `json:"total_cost_usd"`
+	Usage        *streamUsage `json:"usage"`
+	Message      *struct {
+		ID    string       `json:"id"`
+		Usage *streamUsage `json:"usage"`
+	} `json:"message"`
+}
+
+// streamTracker accumulates token usage and the final result from
+// stream-json output. Assistant events repeat per content block with the
+// same message ID, so usage is kept per message and the latest value wins.
+type streamTracker struct {
+	perMessage map[string]int64
+	anonymous  int64
+	final      int64
+	costUSD    float64
+	sessionID  string
+	isError    bool
+	result     string
+}
+
+func (t *streamTracker) apply(line []byte) {
+	var ev streamEvent
+	if err := json.Unmarshal(bytes.TrimSpace(line), &ev); err != nil {
+		return
+	}
+	if ev.SessionID != "" {
+		t.sessionID = ev.SessionID
+	}
+	switch ev.Type {
+	case "assistant":
+		if ev.Message == nil || ev.Message.Usage == nil {
+			return
+		}
+		n := ev.Message.Usage.budgetTokens()
+		if ev.Message.ID == "" {
+			t.anonymous += n
+			return
+		}
+		if t.perMessage == nil {
+			t.perMessage = make(map[string]int64)
+		}
+		t.perMessage[ev.Message.ID] = n
+	case "result":
+		t.final = ev.Usage.budgetTokens()
+		t.costUSD = ev.TotalCostUSD
+		t.isError = ev.IsError
+		t.result = ev.Result
+	}
+}
+
+// tokens returns the budgeted token count seen so far. The result event's
+// session total is used when it is larger than the streamed sum.
+func (t *streamTracker) tokens() int64 {
+	total := t.anonymous
+	for _, n := range t.perMessage {
+		total += n
+	}
+	return max(total, t.final)
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	max int
+	buf []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.max {
+		b.buf = b.buf[len(b.buf)-b.max:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string { return string(b.buf) }
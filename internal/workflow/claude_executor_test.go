@@ -0,0 +1,230 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+const stubStream = `echo '{"type":"system","subtype":"init","session_id":"sess-1"}'
+echo '{"type":"assistant","session_id":"sess-1","message":{"id":"m1","usage":{"input_tokens":100,"output_tokens":5}}}'
+echo '{"type":"assistant","session_id":"sess-1","message":{"id":"m1","usage":{"input_tokens":100,"output_tokens":20,"cache_read_input_tokens":9000}}}'
+echo '{"type":"assistant","session_id":"sess-1","message":{"id":"m2","usage":{"input_tokens":10,"output_tokens":30,"cache_creation_input_tokens":40}}}'
+`
+
+// stubClaude puts a fake claude script on PATH. The script appends its
+// arguments to calls.log in the returned directory before running body.
+func stubClaude(t *testing.T, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("stub claude script requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho \"$@\" >> \"" + filepath.Join(dir, "calls.log") + "\"\n" + body
+	if err := os.WriteFile(filepath.Join(dir, "claude"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+func stubCalls(t *testing.T, dir string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "calls.log"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestClaudeExecutor_Success(t *testing.T) {
+	stub := stubClaude(t, stubStream+`echo '{"type":"result","subtype":"success","is_error":false,"session_id":"sess-1","total_cost_usd":0.42,"result":"done"}'
+`)
+	workDir := t.TempDir()
+
+	var seen []WorkflowPhaseStatus
+	e := NewClaudeExecutor(ClaudeExecutorConfig{
+		PermissionMode: "acceptEdits",
+		OnPhase:        func(_ string, _ Phase, rec PhaseRecord) { seen = append(seen, rec.Status) },
+	}, nil)
+	if err := e.ExecutePlan(context.Background(), "SPEC-ISSUE-1", workDir); err != nil {
+		t.Fatalf("ExecutePlan() error = %v", err)
+	}
+
+	calls := stubCalls(t, stub)
+	if len(calls) != 1 || !strings.Contains(calls[0], "-p /moai plan SPEC-ISSUE-1 --output-format stream-json") ||
+		!strings.Contains(calls[0], "--permission-mode acceptEdits") {
+		t.Errorf("claude args = %q", calls)
+	}
+
+	state, err := LoadRunState(workDir, "SPEC-ISSUE-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := state.Phase(PhasePlan)
+	// m1 counts once with its latest usage; cache reads are excluded.
+	if rec.Status != PhaseStatusCompleted || rec.Tokens != 200 || rec.CostUSD != 0.42 || rec.SessionID != "sess-1" {
+		t.Errorf("plan record = %+v", rec)
+	}
+	if state.Phase(PhaseRun).Status != PhaseStatusPending {
+		t.Errorf("run phase = %+v, want pending", state.Phase(PhaseRun))
+	}
+	if len(seen) != 2 || seen[0] != PhaseStatusRunning || seen[1] != PhaseStatusCompleted {
+		t.Errorf("OnPhase statuses = %v", seen)
+	}
+
+	log, err := os.ReadFile(rec.LogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(rec.LogPath, RunStateDir(workDir, "SPEC-ISSUE-1")) || strings.Count(string(log), "\n") != 5 {
+		t.Errorf("log %s:\n%s", rec.LogPath, log)
+	}
+}
+
+func TestClaudeExecutor_Failures(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		limits   PhaseLimits
+		wantErr  error
+		wantText string
+		wantExit int
+	}{
+		{
+			name:     "non-zero exit",
+			script:   "echo 'model overloaded' >&2\nexit 3\n",
+			wantText: "model overloaded",
+			wantExit: 3,
+		},
+		{
+			name:     "error result",
+			script:   `echo '{"type":"result","is_error":true,"result":"max turns reached"}'` + "\n",
+			wantText: "max turns reached",
+		},
+		{
+			name:    "timeout",
+			script:  "exec sleep 5\n",
+			limits:  PhaseLimits{Timeout: 100 * time.Millisecond},
+			wantErr: ErrPhaseTimeout,
+		},
+		{
+			name:    "token budget",
+			script:  stubStream + "exec sleep 5\n",
+			limits:  PhaseLimits{TokenBudget: 150},
+			wantErr: ErrTokenBudgetExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubClaude(t, tt.script)
+			workDir := t.TempDir()
+			e := NewClaudeExecutor(ClaudeExecutorConfig{Limits: map[Phase]PhaseLimits{PhaseRun: tt.limits}}, nil)
+
+			start := time.Now()
+			err := e.ExecuteRun(context.Background(), "SPEC-ISSUE-2", workDir)
+			if err == nil {
+				t.Fatal("ExecuteRun() error = nil")
+			}
+			if time.Since(start) > 4*time.Second {
+				t.Errorf("phase was not stopped promptly (%s)", time.Since(start))
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantText != "" && !strings.Contains(err.Error(), tt.wantText) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.wantText)
+			}
+
+			state, err := LoadRunState(workDir, "SPEC-ISSUE-2")
+			if err != nil {
+				t.Fatal(err)
+			}
+			rec := state.Phase(PhaseRun)
+			if rec.Status != PhaseStatusFailed || rec.Error == "" {
+				t.Errorf("run record = %+v, want failed", rec)
+			}
+			if tt.wantExit != 0 && rec.ExitCode != tt.wantExit {
+				t.Errorf("ExitCode = %d, want %d", rec.ExitCode, tt.wantExit)
+			}
+		})
+	}
+}
+
+func TestClaudeExecutor_Resume(t *testing.T) {
+	stub := stubClaude(t, `echo '{"type":"result","is_error":false}'`+"\n")
+	workDir := t.TempDir()
+
+	state := NewRunState("SPEC-ISSUE-3")
+	state.Phase(PhasePlan).Status = PhaseStatusCompleted
+	state.Phase(PhaseRun).Status = PhaseStatusFailed
+	if err := state.Save(workDir); err != nil {
+		t.Fatal(err)
+	}
+
+	var skipped []Phase
+	e := NewClaudeExecutor(ClaudeExecutorConfig{
+		Resume: true,
+		OnPhase: func(_ string, p Phase, rec PhaseRecord) {
+			if rec.Status == PhaseStatusSkipped {
+				skipped = append(skipped, p)
+			}
+		},
+	}, nil)
+	ctx := context.Background()
+	if err := e.ExecutePlan(ctx, "SPEC-ISSUE-3", workDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.ExecuteRun(ctx, "SPEC-ISSUE-3", workDir); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := stubCalls(t, stub)
+	if len(calls) != 1 || !strings.Contains(calls[0], "/moai run SPEC-ISSUE-3") {
+		t.Errorf("claude calls = %q, want only the run phase", calls)
+	}
+	if len(skipped) != 1 || skipped[0] != PhasePlan {
+		t.Errorf("skipped = %v, want [plan]", skipped)
+	}
+	got, _ := LoadRunState(workDir, "SPEC-ISSUE-3")
+	if got.Phase(PhasePlan).Status != PhaseStatusCompleted || got.Phase(PhaseRun).Status != PhaseStatusCompleted {
+		t.Errorf("state = %+v", got.Phases)
+	}
+
+	// A fresh run starts a new record at the plan phase.
+	e = NewClaudeExecutor(ClaudeExecutorConfig{}, nil)
+	if err := e.ExecutePlan(ctx, "SPEC-ISSUE-3", workDir); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = LoadRunState(workDir, "SPEC-ISSUE-3")
+	if got.Phase(PhaseRun).Status != PhaseStatusPending {
+		t.Errorf("run phase after fresh plan = %+v, want pending", got.Phase(PhaseRun))
+	}
+}
+
+func TestClaudeExecutor_MissingBinary(t *testing.T) {
+	workDir := t.TempDir()
+	e := NewClaudeExecutor(ClaudeExecutorConfig{Binary: "moai-test-no-such-claude"}, nil)
+	err := e.ExecuteSync(context.Background(), "SPEC-ISSUE-4", workDir)
+	if !errors.Is(err, ErrClaudeNotFound) {
+		t.Fatalf("error = %v, want ErrClaudeNotFound", err)
+	}
+	state, _ := LoadRunState(workDir, "SPEC-ISSUE-4")
+	if state.Phase(PhaseSync).Status != PhaseStatusFailed {
+		t.Errorf("sync record = %+v", state.Phase(PhaseSync))
+	}
+}
+
+func TestLoadRunState_Missing(t *testing.T) {
+	if _, err := LoadRunState(t.TempDir(), "SPEC-ISSUE-5"); !errors.Is(err, ErrNoRunState) {
+		t.Errorf("error = %v, want ErrNoRunState", err)
+	}
+}
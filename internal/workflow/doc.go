@@ -2,5 +2,7 @@
 //
 // It provides the WorktreeOrchestrator interface for coordinating Plan-Run-Sync
 // phases within isolated worktree environments, integrating with the TRUST 5
-// quality framework for automated validation.
+// quality framework for automated validation. ClaudeExecutor runs each phase
+// headlessly through the Claude Code CLI and records the outcome in a
+// RunState that can be inspected and resumed.
 package workflow
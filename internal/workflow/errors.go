@@ -36,4 +36,17 @@ var (
 
 	// ErrNilExecutor indicates a nil PhaseExecutor was provided.
 	ErrNilExecutor = errors.New("workflow: PhaseExecutor must not be nil")
+
+	// ErrClaudeNotFound indicates the claude binary is not on PATH.
+	ErrClaudeNotFound = errors.New("workflow: claude CLI not found")
+
+	// ErrPhaseTimeout indicates a headless phase exceeded its timeout.
+	ErrPhaseTimeout = errors.New("workflow: phase timed out")
+
+	// ErrTokenBudgetExceeded indicates a headless phase used more tokens
+	// than its budget allows.
+	ErrTokenBudgetExceeded = errors.New("workflow: phase token budget exceeded")
+
+	// ErrNoRunState indicates the SPEC has no recorded headless run.
+	ErrNoRunState = errors.New("workflow: no recorded run")
 )
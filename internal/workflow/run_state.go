@@ -0,0 +1,150 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/modu-ai/moai-adk/internal/core/git"
+	"github.com/modu-ai/moai-adk/internal/defs"
+)
+
+// Phase identifies one step of the Plan-Run-Sync workflow.
+type Phase string
+
+const (
+	// PhasePlan is the SPEC planning phase.
+	PhasePlan Phase = "plan"
+
+	// PhaseRun is the implementation phase.
+	PhaseRun Phase = "run"
+
+	// PhaseSync is the documentation/sync phase.
+	PhaseSync Phase = "sync"
+)
+
+// Phases lists the workflow phases in execution order.
+var Phases = []Phase{PhasePlan, PhaseRun, PhaseSync}
+
+// runStateFile is the run record stored next to the phase logs.
+const runStateFile = "state.json"
+
+// PhaseRecord is the persisted outcome of one headless phase.
+type PhaseRecord struct {
+	Status      WorkflowPhaseStatus `json:"status"`
+	StartedAt   time.Time           `json:"startedAt,omitzero"`
+	CompletedAt time.Time           `json:"completedAt,omitzero"`
+	ExitCode    int                 `json:"exitCode"`
+	Tokens      int64               `json:"tokens"`
+	CostUSD     float64             `json:"costUsd"`
+	SessionID   string              `json:"sessionId,omitempty"`
+	LogPath     string              `json:"logPath,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+// RunState records a headless workflow run for `moai workflow status` and
+// `moai workflow resume`. It lives in the SPEC worktree under
+// .moai/logs/workflow/<SPEC-ID>/state.json.
+type RunState struct {
+	SpecID    string                 `json:"specId"`
+	StartedAt time.Time              `json:"startedAt"`
+	UpdatedAt time.Time              `json:"updatedAt"`
+	Phases    map[Phase]*PhaseRecord `json:"phases"`
+}
+
+// NewRunState returns an empty run record with every phase pending.
+func NewRunState(specID string) *RunState {
+	s := &RunState{
+		SpecID:    specID,
+		StartedAt: time.Now(),
+		Phases:    make(map[Phase]*PhaseRecord, len(Phases)),
+	}
+	for _, p := range Phases {
+		s.Phases[p] = &PhaseRecord{Status: PhaseStatusPending}
+	}
+	return s
+}
+
+// Phase returns the record for p, creating a pending one if missing.
+func (s *RunState) Phase(p Phase) *PhaseRecord {
+	if s.Phases == nil {
+		s.Phases = make(map[Phase]*PhaseRecord, len(Phases))
+	}
+	rec, ok := s.Phases[p]
+	if !ok {
+		rec = &PhaseRecord{Status: PhaseStatusPending}
+		s.Phases[p] = rec
+	}
+	return rec
+}
+
+// Completed reports whether every phase completed.
+func (s *RunState) Completed() bool {
+	for _, p := range Phases {
+		if s.Phase(p).Status != PhaseStatusCompleted {
+			return false
+		}
+	}
+	return true
+}
+
+// RunStateDir returns the directory holding the run record and phase logs
+// for specID inside workDir.
+func RunStateDir(workDir, specID string) string {
+	return filepath.Join(workDir, defs.MoAIDir, defs.LogsSubdir, "workflow", specID)
+}
+
+// LoadRunState reads the run record for specID from workDir.
+// Returns ErrNoRunState if the SPEC has never been run headlessly.
+func LoadRunState(workDir, specID string) (*RunState, error) {
+	data, err := os.ReadFile(filepath.Join(RunStateDir(workDir, specID), runStateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s: %w", specID, ErrNoRunState)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read run state: %w", err)
+	}
+	var s RunState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse run state: %w", err)
+	}
+	return &s, nil
+}
+
+// Save writes the run record for the SPEC into workDir.
+func (s *RunState) Save(workDir string) error {
+	dir := RunStateDir(workDir, s.SpecID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create run state directory: %w", err)
+	}
+	s.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal run state: %w", err)
+	}
+	tmp := filepath.Join(dir, runStateFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write run state: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, runStateFile)); err != nil {
+		return fmt.Errorf("write run state: %w", err)
+	}
+	return nil
+}
+
+// FindSpecWorktree returns the worktree whose directory is named specID.
+func FindSpecWorktree(mgr git.WorktreeManager, specID string) (*git.Worktree, error) {
+	worktrees, err := mgr.List()
+	if err != nil {
+		return nil, fmt.Errorf("list worktrees: %w", err)
+	}
+	for i := range worktrees {
+		if filepath.Base(worktrees[i].Path) == specID {
+			return &worktrees[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no worktree found for %s: %w", specID, ErrNotInWorktree)
+}
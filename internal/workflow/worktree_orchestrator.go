@@ -334,24 +334,17 @@ func (o *worktreeOrchestrator) PrepareForReview(ctx context.Context, specID stri
 
 // findWorktreeForSpec looks up the worktree directory for a given SPEC ID.
 func (o *worktreeOrchestrator) findWorktreeForSpec(ctx context.Context, specID string) (*WorktreeContext, error) {
-	worktrees, err := o.worktreeMgr.List()
+	wt, err := FindSpecWorktree(o.worktreeMgr, specID)
 	if err != nil {
-		return nil, fmt.Errorf("list worktrees: %w", err)
-	}
-
-	for _, wt := range worktrees {
-		if filepath.Base(wt.Path) == specID {
-			return &WorktreeContext{
-				SpecID:      specID,
-				WorktreeDir: wt.Path,
-				Branch:      wt.Branch,
-				BaseBranch:  o.detectBranch(ctx, o.worktreeMgr.Root()),
-				IssueNumber: extractIssueNumber(specID),
-			}, nil
-		}
+		return nil, err
 	}
-
-	return nil, fmt.Errorf("no worktree found for %s: %w", specID, ErrNotInWorktree)
+	return &WorktreeContext{
+		SpecID:      specID,
+		WorktreeDir: wt.Path,
+		Branch:      wt.Branch,
+		BaseBranch:  o.detectBranch(ctx, o.worktreeMgr.Root()),
+		IssueNumber: extractIssueNumber(specID),
+	}, nil
 }
 
 // detectDefaultBranch determines the repository's default branch by reading
@@ -240,4 +240,12 @@ type CustomConventionConfig struct {
 	Scopes    []string `yaml:"scopes"`
 	MaxLength int      `yaml:"max_length"`
 	Examples  []string `yaml:"examples"`
+
+	// Rules holds commitlint-style rules keyed by rule name, each a list
+	// [severity, "always"|"never", value], e.g. subject-case: [2, never, [upper-case]].
+	Rules map[string]any `yaml:"rules"`
+
+	// Import names a commitlint JSON config, relative to the project root,
+	// whose rules are the base of this convention.
+	Import string `yaml:"import"`
 }